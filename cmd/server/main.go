@@ -2,20 +2,33 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
 	"github.com/jorzel/booking-service/internal/infrastructure"
 	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog"
 )
 
 func main() {
-	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	// Every logger derives from this one, so PII is masked the same way in services and handlers
+	privacy := infrastructure.DefaultPrivacyConfig()
+	if fields, ok := os.LookupEnv("LOG_EMAIL_FIELDS"); ok {
+		privacy.EmailFields = infrastructure.ParseLogFields(fields)
+	}
+	privacy.HashFields = infrastructure.ParseLogFields(os.Getenv("LOG_HASH_FIELDS"))
+	privacy.HashKey = os.Getenv("LOG_HASH_KEY")
+	logger := zerolog.New(infrastructure.NewMaskingWriter(os.Stdout, privacy)).With().Timestamp().Logger()
 
 	config := infrastructure.Config{
 		Host:     getEnv("DB_HOST", "localhost"),
@@ -24,35 +37,362 @@ func main() {
 		Password: getEnv("DB_PASSWORD", "postgres"),
 		Database: getEnv("DB_NAME", "booking_service"),
 		SSLMode:  getEnv("DB_SSLMODE", "disable"),
+		Schema:   os.Getenv("DB_SCHEMA"),
+	}
+
+	statementTimeout, err := time.ParseDuration(getEnv("DB_STATEMENT_TIMEOUT", "5s"))
+	if err != nil || statementTimeout < 0 {
+		logger.Fatal().Err(err).Msg("invalid DB_STATEMENT_TIMEOUT")
+	}
+	config.StatementTimeout = statementTimeout
+	adminStatementTimeout, err := time.ParseDuration(getEnv("ADMIN_STATEMENT_TIMEOUT", "5m"))
+	if err != nil || adminStatementTimeout < 0 {
+		logger.Fatal().Err(err).Msg("invalid ADMIN_STATEMENT_TIMEOUT")
+	}
+
+	breakerThreshold, err := strconv.Atoi(getEnv("DB_BREAKER_THRESHOLD", "5"))
+	if err != nil || breakerThreshold <= 0 {
+		logger.Fatal().Err(err).Msg("invalid DB_BREAKER_THRESHOLD")
+	}
+	breakerCooldown, err := time.ParseDuration(getEnv("DB_BREAKER_COOLDOWN", "30s"))
+	if err != nil || breakerCooldown <= 0 {
+		logger.Fatal().Err(err).Msg("invalid DB_BREAKER_COOLDOWN")
+	}
+	breaker := infrastructure.NewCircuitBreaker(breakerThreshold, breakerCooldown)
+	maxTransactions, err := strconv.Atoi(getEnv("DB_MAX_TRANSACTIONS", "20"))
+	if err != nil || maxTransactions < 0 {
+		logger.Fatal().Err(err).Msg("invalid DB_MAX_TRANSACTIONS")
 	}
 
-	db, err := infrastructure.NewPostgresDB(config)
+	db, err := infrastructure.NewPostgresDB(config, infrastructure.WithCircuitBreaker(breaker))
 	if err != nil {
 		logger.Fatal().Err(err).Msg("failed to connect to database")
 	}
-	defer db.Close()
 
-	// Wrap with instrumented client for metrics
-	instrumentedDB := infrastructure.NewInstrumentedPostgresClient(db)
+	// Components are stopped in reverse registration order: the HTTP server, then workers, then the database
+	lifecycle := infrastructure.NewLifecycle(logger)
+	lifecycle.Register(infrastructure.Component{
+		Name: "database",
+		Stop: func(context.Context) error { return db.Close() },
+	})
 
-	eventRepo := infrastructure.NewPostgresEventRepository(instrumentedDB)
-	bookingRepo := infrastructure.NewPostgresBookingRepository(instrumentedDB)
+	// Wrap with instrumented client for metrics; queries of transactional routes go through their request transaction
+	// The transaction limit sits below the request scope, so savepoints nested in a request transaction share its slot
+	slowTxThreshold, err := time.ParseDuration(getEnv("SLOW_TX_THRESHOLD", infrastructure.DefaultSlowTransactionThreshold.String()))
+	if err != nil || slowTxThreshold < 0 {
+		logger.Fatal().Err(err).Msg("invalid SLOW_TX_THRESHOLD")
+	}
+	var pgClient infrastructure.DBClient = infrastructure.NewInstrumentedPostgresClient(db,
+		infrastructure.WithSlowTransactionThreshold(slowTxThreshold))
+	if maxTransactions > 0 {
+		pgClient = infrastructure.NewTransactionLimitedDBClient(pgClient, maxTransactions)
+	}
+	instrumentedDB := infrastructure.NewRequestScopedDBClient(pgClient)
+
+	// Fail fast on misconfiguration instead of serving errors; every failing check is reported together
+	selfCheckCtx, cancelSelfCheck := context.WithTimeout(context.Background(), 10*time.Second)
+	err = infrastructure.RunSelfChecks(selfCheckCtx,
+		infrastructure.DatabaseCheck(instrumentedDB),
+		infrastructure.SchemaCheck(instrumentedDB),
+		infrastructure.RequiredEnvCheck(strings.Split(getEnv("SELF_CHECK_REQUIRED_ENV", ""), ",")),
+		infrastructure.MetricsCheck(prometheus.DefaultRegisterer, prometheus.DefaultGatherer),
+	)
+	cancelSelfCheck()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("refusing to start")
+	}
+
+	repoLogger := infrastructure.WithRepositoryLogger(logger.With().Str("component", "repository").Logger())
+	// Listing all events fails on a malformed row by default; opting in skips and logs such rows instead
+	skipMalformedRows := infrastructure.WithSkipMalformedRows(getEnv("EVENT_SKIP_MALFORMED_ROWS", "false") == "true")
+	eventRepo := infrastructure.NewPostgresEventRepository(instrumentedDB, repoLogger, skipMalformedRows)
+	batchSize, err := strconv.Atoi(getEnv("DB_INSERT_BATCH_SIZE", strconv.Itoa(infrastructure.DefaultBatchSize)))
+	if err != nil || batchSize <= 0 {
+		logger.Fatal().Err(err).Msg("invalid DB_INSERT_BATCH_SIZE")
+	}
+	bookingRepo := infrastructure.NewPostgresBookingRepository(instrumentedDB, repoLogger, infrastructure.WithBatchSize(batchSize))
 	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(instrumentedDB)
+	seatRepo := infrastructure.NewPostgresSeatRepository(instrumentedDB)
+	holdRepo := infrastructure.NewPostgresHoldRepository(instrumentedDB)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(instrumentedDB)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(instrumentedDB)
+	webhookRepo := infrastructure.NewPostgresWebhookRepository(instrumentedDB)
+	allocationRepo := infrastructure.NewPostgresAllocationRepository(instrumentedDB)
+	lotteryRepo := infrastructure.NewPostgresLotteryRepository(instrumentedDB)
+
+	checkDuplicateAvailability(ticketAvailabilityRepo, logger)
+
+	idGenerator, err := domain.NewIDGenerator(getEnv("ID_FORMAT", domain.IDFormatUUIDv4))
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid ID_FORMAT")
+	}
+
+	clockSkew, err := time.ParseDuration(getEnv("CLOCK_SKEW_TOLERANCE", domain.DefaultClockSkew.String()))
+	if err != nil || clockSkew < 0 {
+		logger.Fatal().Err(err).Msg("invalid CLOCK_SKEW_TOLERANCE")
+	}
+	clock := domain.NewClock(time.Now, clockSkew)
+
+	// Events created without end_date or duration end this long after they start; 0s leaves the end unknown
+	defaultEventDuration, err := time.ParseDuration(getEnv("EVENT_DEFAULT_DURATION", "0s"))
+	if err != nil || defaultEventDuration < 0 {
+		logger.Fatal().Err(err).Msg("invalid EVENT_DEFAULT_DURATION")
+	}
+
+	eventServiceOpts := []app.EventServiceOption{
+		app.WithEventIDGenerator(idGenerator),
+		app.WithEventClock(clock),
+		app.WithDuplicateNameCheck(getEnv("EVENT_DUPLICATE_CHECK", "true") == "true"),
+		app.WithDefaultEventDuration(defaultEventDuration),
+	}
+	var replicas []transport.Replica
+	if replicaHost := os.Getenv("DB_REPLICA_HOST"); replicaHost != "" {
+		replicaConfig := config
+		replicaConfig.Host = replicaHost
+
+		replicaDB, err := infrastructure.NewPostgresDB(replicaConfig)
+		if err != nil {
+			logger.Warn().Err(err).Str("host", replicaHost).Msg("read replica unavailable, stale-read fallback disabled")
+		} else {
+			lifecycle.Register(infrastructure.Component{
+				Name: "read_replica",
+				Stop: func(context.Context) error { return replicaDB.Close() },
+			})
+			replicaClient := infrastructure.NewInstrumentedPostgresClient(replicaDB)
+			replicaEventRepo := infrastructure.NewPostgresEventRepository(replicaClient, repoLogger, skipMalformedRows)
+			eventServiceOpts = append(eventServiceOpts, app.WithReadReplica(replicaEventRepo))
+			replicas = append(replicas, transport.Replica{Name: replicaHost, Lag: infrastructure.NewPostgresReplicationLag(replicaClient)})
+		}
+	}
+
+	idempotencyKeyTTL, err := time.ParseDuration(getEnv("IDEMPOTENCY_KEY_TTL", app.DefaultIdempotencyKeyTTL.String()))
+	if err != nil || idempotencyKeyTTL <= 0 {
+		logger.Fatal().Err(err).Msg("invalid IDEMPOTENCY_KEY_TTL")
+	}
+	// Request dedup is opt-in: double submits are only collapsed when BOOKING_DEDUP_WINDOW is set
+	dedupWindow, err := time.ParseDuration(getEnv("BOOKING_DEDUP_WINDOW", "0s"))
+	if err != nil || dedupWindow < 0 {
+		logger.Fatal().Err(err).Msg("invalid BOOKING_DEDUP_WINDOW")
+	}
+	webhookWorkers, err := strconv.Atoi(getEnv("WEBHOOK_WORKERS", strconv.Itoa(app.DefaultWebhookWorkers)))
+	if err != nil || webhookWorkers <= 0 {
+		logger.Fatal().Err(err).Msg("invalid WEBHOOK_WORKERS")
+	}
+	webhookQueueSize, err := strconv.Atoi(getEnv("WEBHOOK_QUEUE_SIZE", strconv.Itoa(app.DefaultWebhookQueueSize)))
+	if err != nil || webhookQueueSize < 0 {
+		logger.Fatal().Err(err).Msg("invalid WEBHOOK_QUEUE_SIZE")
+	}
+	webhookServiceOpts := []app.WebhookServiceOption{
+		app.WithWebhookClock(clock),
+		app.WithWebhookIDGenerator(idGenerator),
+		app.WithWebhookWorkers(webhookWorkers, webhookQueueSize),
+	}
+	var webhookSenderOpts []infrastructure.WebhookSenderOption
+	// Webhooks may only target public hosts; local development can opt in to localhost receivers
+	if getEnv("WEBHOOK_ALLOW_INTERNAL_HOSTS", "false") == "true" {
+		webhookServiceOpts = append(webhookServiceOpts, app.WithInternalWebhookHosts())
+		webhookSenderOpts = append(webhookSenderOpts, infrastructure.WithWebhookInternalHosts())
+	}
+	webhookSender := infrastructure.NewHTTPWebhookSender(webhookSenderOpts...)
+	webhookService := app.NewWebhookService(webhookRepo, eventRepo, webhookSender, logger, webhookServiceOpts...)
+	// Caps the bookings of one event in flight, so a flash sale cannot queue the whole pool on one row lock
+	eventConcurrency, err := strconv.Atoi(getEnv("BOOKING_EVENT_CONCURRENCY", strconv.Itoa(app.DefaultEventConcurrency)))
+	if err != nil || eventConcurrency < 0 {
+		logger.Fatal().Err(err).Msg("invalid BOOKING_EVENT_CONCURRENCY")
+	}
+	bookingServiceOpts := []app.BookingServiceOption{
+		app.WithBookingIDGenerator(idGenerator), app.WithIdempotencyKeyTTL(idempotencyKeyTTL), app.WithRequestDedup(dedupWindow),
+		app.WithBookingClock(clock), app.WithBookingWebhooks(webhookService), app.WithEventConcurrency(eventConcurrency),
+		app.WithAllocations(allocationRepo),
+	}
+	if raw := os.Getenv("CANCELLATION_POLICY"); raw != "" {
+		policy, err := domain.ParseCancellationPolicy(raw)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("invalid CANCELLATION_POLICY")
+		}
+		bookingServiceOpts = append(bookingServiceOpts, app.WithCancellationPolicy(policy))
+	}
+	// Configured rates are as of startup; FX_RATE_MAX_AGE only makes sense once rates come from a feed
+	if raw := os.Getenv("FX_RATES"); raw != "" {
+		rates, err := infrastructure.ParseFXRates(raw, time.Now())
+		if err != nil {
+			logger.Fatal().Err(err).Msg("invalid FX_RATES")
+		}
+		fxRateMaxAge, err := time.ParseDuration(getEnv("FX_RATE_MAX_AGE", "0s"))
+		if err != nil || fxRateMaxAge < 0 {
+			logger.Fatal().Err(err).Msg("invalid FX_RATE_MAX_AGE")
+		}
+		bookingServiceOpts = append(bookingServiceOpts, app.WithFXConversion(infrastructure.NewStaticFXRates(rates...), fxRateMaxAge))
+	}
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, instrumentedDB, logger,
+		bookingServiceOpts...)
+	// Cancelling an event cancels and refunds its bookings in the same transaction
+	eventServiceOpts = append(eventServiceOpts, app.WithCancelledEventBookings(bookingService))
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, instrumentedDB, logger, eventServiceOpts...)
+
+	holdTTL, err := time.ParseDuration(getEnv("HOLD_TTL", app.DefaultHoldTTL.String()))
+	if err != nil || holdTTL <= 0 {
+		logger.Fatal().Err(err).Msg("invalid HOLD_TTL")
+	}
+	holdExpiryNoticeLead, err := time.ParseDuration(getEnv("HOLD_EXPIRY_NOTICE_LEAD", "2m"))
+	if err != nil || holdExpiryNoticeLead < 0 {
+		logger.Fatal().Err(err).Msg("invalid HOLD_EXPIRY_NOTICE_LEAD")
+	}
+	holdServiceOpts := []app.HoldServiceOption{
+		app.WithHoldExpiryNotifier(infrastructure.NewLogNotifier(logger), holdExpiryNoticeLead), app.WithHoldClock(clock),
+		app.WithHoldIDGenerator(idGenerator),
+	}
+	var paymentGateway *infrastructure.HTTPPaymentGateway
+	if gatewayURL := os.Getenv("PAYMENT_GATEWAY_URL"); gatewayURL != "" {
+		depositPercent, err := strconv.Atoi(getEnv("HOLD_DEPOSIT_PERCENT", "20"))
+		if err != nil || depositPercent <= 0 || depositPercent > 100 {
+			logger.Fatal().Err(err).Msg("invalid HOLD_DEPOSIT_PERCENT")
+		}
+		paymentGateway = infrastructure.NewHTTPPaymentGateway(gatewayURL, os.Getenv("PAYMENT_GATEWAY_API_KEY"))
+		holdServiceOpts = append(holdServiceOpts, app.WithHoldDeposits(paymentGateway, depositPercent))
+	}
+	holdService := app.NewHoldService(holdRepo, eventRepo, ticketAvailabilityRepo, bookingRepo, instrumentedDB, logger, holdTTL,
+		holdServiceOpts...)
+
+	// Integrations with external services register a probe here for /readyz
+	dependencies := infrastructure.NewDependencyChecker(infrastructure.DefaultDependencyProbeTimeout)
+	// Bookings are served without webhooks or payments, so neither fails readiness, they only degrade it
+	if probeURL := os.Getenv("WEBHOOK_PROBE_URL"); probeURL != "" {
+		dependencies.Register("webhooks", false, webhookSender.Probe(probeURL))
+	}
+	if paymentGateway != nil {
+		dependencies.Register("payment_gateway", false, paymentGateway.Probe())
+	}
+
+	workers := infrastructure.NewWorkerRegistry()
+	// A few missed sweeps are tolerated before the sweeper is reported degraded
+	workers.Register(holdSweeperWorker, 3*holdSweepInterval)
+	workers.Register(idempotencySweeperWorker, 3*idempotencySweepInterval)
+	workers.Register(reservationSweeperWorker, 3*reservationSweepInterval)
+
+	lifecycle.Register(workerComponent(holdSweeperWorker, func(ctx context.Context) {
+		runHoldSweeper(ctx, holdService, holdSweepInterval, workers, logger)
+	}))
+	lifecycle.Register(workerComponent(idempotencySweeperWorker, func(ctx context.Context) {
+		runIdempotencySweeper(ctx, bookingService, idempotencySweepInterval, workers, logger)
+	}))
+	lifecycle.Register(workerComponent(reservationSweeperWorker, func(ctx context.Context) {
+		runReservationSweeper(ctx, bookingService, reservationSweepInterval, workers, logger)
+	}))
+
+	// Re-verifies the schema while serving, so manual DDL after startup is reported instead of failing bookings
+	schemaDriftInterval, err := time.ParseDuration(getEnv("SCHEMA_DRIFT_INTERVAL", defaultSchemaDriftInterval.String()))
+	if err != nil || schemaDriftInterval < 0 {
+		logger.Fatal().Err(err).Msg("invalid SCHEMA_DRIFT_INTERVAL")
+	}
+	if schemaDriftInterval > 0 {
+		workers.Register(schemaDriftWorker, 3*schemaDriftInterval)
+		schemaDrift := infrastructure.NewSchemaDriftDetector(instrumentedDB, logger)
+		lifecycle.Register(workerComponent(schemaDriftWorker, func(ctx context.Context) {
+			runSchemaDriftDetector(ctx, schemaDrift, schemaDriftInterval, workers, logger)
+		}))
+	}
+
+	// Registered before the HTTP server, so deliveries of the last bookings finish before the database closes
+	lifecycle.Register(infrastructure.Component{
+		Name: "webhook_deliveries",
+		Stop: webhookService.Wait,
+	})
+
+	adminToken := getEnv("ADMIN_TOKEN", "")
+	adminTokens, err := transport.ParseAdminTokens(getEnv("ADMIN_TOKENS", ""))
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid ADMIN_TOKENS")
+	}
+	if adminToken == "" && len(adminTokens) == 0 {
+		logger.Warn().Msg("ADMIN_TOKEN and ADMIN_TOKENS not set, /admin routes are unauthenticated")
+	}
 
-	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, instrumentedDB, logger)
-	bookingService := app.NewBookingService(bookingRepo, ticketAvailabilityRepo, instrumentedDB, logger)
+	trustedProxies, err := transport.ParseTrustedProxies(getEnv("TRUSTED_PROXIES", ""))
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid TRUSTED_PROXIES")
+	}
+
+	requestIDHeaders, err := transport.ParseRequestIDHeaders(getEnv("REQUEST_ID_HEADERS", ""))
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid REQUEST_ID_HEADERS")
+	}
+
+	// Each environment lists its own origins, inline or in a file deployed alongside it
+	originPatterns := transport.ParseAllowedOrigins(getEnv("CORS_ALLOWED_ORIGINS", ""))
+	if path := getEnv("CORS_ALLOWED_ORIGINS_FILE", ""); path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("failed to read CORS_ALLOWED_ORIGINS_FILE")
+		}
+		originPatterns = append(originPatterns, transport.ParseAllowedOrigins(string(raw))...)
+	}
+	allowedOrigins, err := transport.NewOriginAllowlist(originPatterns)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid CORS allowed origins")
+	}
+
+	maintenanceRetryAfter, err := time.ParseDuration(getEnv("MAINTENANCE_RETRY_AFTER", transport.DefaultMaintenanceRetryAfter.String()))
+	if err != nil || maintenanceRetryAfter <= 0 {
+		logger.Fatal().Err(err).Msg("invalid MAINTENANCE_RETRY_AFTER")
+	}
+	maintenance := transport.NewMaintenance(getEnv("MAINTENANCE_MODE", "false") == "true", maintenanceRetryAfter)
+	if maintenance.Enabled() {
+		logger.Warn().Msg("starting in maintenance mode, writes are paused until POST /admin/maintenance turns it off")
+	}
+
+	replicaMaxLag, err := time.ParseDuration(getEnv("DB_REPLICA_MAX_LAG", infrastructure.DefaultReplicaMaxLag.String()))
+	if err != nil || replicaMaxLag <= 0 {
+		logger.Fatal().Err(err).Msg("invalid DB_REPLICA_MAX_LAG")
+	}
 
-	router := transport.NewRouter(eventService, bookingService, instrumentedDB, logger)
+	// Requests slower than this are logged as warnings; 0s only reports them through Server-Timing
+	slowResponseThreshold, err := time.ParseDuration(getEnv("SLOW_RESPONSE_THRESHOLD", transport.DefaultSlowResponseThreshold.String()))
+	if err != nil || slowResponseThreshold < 0 {
+		logger.Fatal().Err(err).Msg("invalid SLOW_RESPONSE_THRESHOLD")
+	}
+
+	// Routes listed here, e.g. "POST /admin/events/import", apply all their writes or none
+	transactionalRoutes, err := transport.ParseTransactionalRoutes(getEnv("TRANSACTIONAL_ROUTES", ""))
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid TRANSACTIONAL_ROUTES")
+	}
+
+	router := transport.NewRouter(eventService, bookingService, holdService, instrumentedDB, workers, logger,
+		transport.WithAdminToken(adminToken), transport.WithAdminTokens(adminTokens), transport.WithCircuitBreaker(breaker), transport.WithTrustedProxies(trustedProxies),
+		transport.WithAllowedOrigins(allowedOrigins), transport.WithMaintenance(maintenance),
+		transport.WithRequestIDHeaders(requestIDHeaders),
+		transport.WithUnprocessableValidation(getEnv("VALIDATION_ERROR_422", "false") == "true"),
+		transport.WithAdminStatementTimeout(adminStatementTimeout), transport.WithWebhooks(webhookService),
+		transport.WithReplicaLagCheck(replicaMaxLag, replicas...), transport.WithDependencyChecker(dependencies),
+		transport.WithTicketCountsAsStrings(getEnv("TICKET_COUNTS_AS_STRINGS", "false") == "true"),
+		transport.WithSlowResponseThreshold(slowResponseThreshold),
+		transport.WithTransactionalRoutes(transactionalRoutes),
+		transport.WithAllocations(app.NewAllocationService(allocationRepo, eventRepo, ticketAvailabilityRepo, instrumentedDB, logger)),
+		transport.WithLottery(app.NewLotteryService(lotteryRepo, eventRepo, ticketAvailabilityRepo, bookingRepo, instrumentedDB, logger,
+			app.WithLotteryIDGenerator(idGenerator))),
+		transport.WithEventMerge(app.NewEventMergeService(eventRepo, ticketAvailabilityRepo, bookingRepo, instrumentedDB, logger)))
 
 	port := getEnv("PORT", "8080")
 	addr := fmt.Sprintf(":%s", port)
 
-	go func() {
-		logger.Info().Str("address", addr).Msg("starting server")
-		if err := router.Start(addr); err != nil {
-			logger.Fatal().Err(err).Msg("server failed to start")
-		}
-	}()
+	lifecycle.Register(infrastructure.Component{
+		Name: "http_server",
+		Start: func(context.Context) error {
+			go func() {
+				logger.Info().Str("address", addr).Msg("starting server")
+				if err := router.Start(addr); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					logger.Fatal().Err(err).Msg("server failed to start")
+				}
+			}()
+			return nil
+		},
+		Stop: router.Shutdown,
+	})
+
+	if err := lifecycle.Start(context.Background()); err != nil {
+		logger.Fatal().Err(err).Msg("failed to start components")
+	}
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
@@ -63,16 +403,184 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	if err := router.Shutdown(ctx); err != nil {
+	if err := lifecycle.Stop(ctx); err != nil {
 		logger.Fatal().Err(err).Msg("server forced to shutdown")
 	}
 
 	logger.Info().Msg("server exited")
 }
 
+// workerComponent runs a background worker until it is stopped; Stop waits for the in-flight run to return
+// so the worker never writes to a database that is already closed
+func workerComponent(name string, run func(ctx context.Context)) infrastructure.Component {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	return infrastructure.Component{
+		Name: name,
+		Start: func(context.Context) error {
+			go func() {
+				defer close(done)
+				run(ctx)
+			}()
+			return nil
+		},
+		Stop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	}
+}
+
+const (
+	holdSweeperWorker  = "hold_sweeper"
+	holdSweepInterval  = 30 * time.Second
+	holdSweepBatchSize = 100
+
+	idempotencySweeperWorker  = "idempotency_sweeper"
+	idempotencySweepInterval  = 5 * time.Minute
+	idempotencySweepBatchSize = 1000
+
+	reservationSweeperWorker  = "reservation_sweeper"
+	reservationSweepInterval  = 30 * time.Second
+	reservationSweepBatchSize = 100
+
+	schemaDriftWorker          = "schema_drift"
+	defaultSchemaDriftInterval = 5 * time.Minute
+)
+
+// runHoldSweeper periodically returns the tickets of holds that expired without confirmation
+// and sends expiry notices for holds about to expire
+// It heartbeats into workers after every sweep that completes without error
+func runHoldSweeper(ctx context.Context, service *app.HoldService, interval time.Duration, workers *infrastructure.WorkerRegistry, logger zerolog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Keep draining while full batches come back so a backlog clears within one tick
+			for {
+				released, err := service.ReleaseExpiredHolds(ctx, holdSweepBatchSize)
+				if err != nil {
+					logger.Error().Err(err).Msg("hold sweep failed")
+					break
+				}
+				if released < holdSweepBatchSize {
+					workers.Heartbeat(holdSweeperWorker)
+					break
+				}
+			}
+
+			// Notices are best effort, so a failure here does not mark the sweeper degraded
+			if _, err := service.NotifyExpiringHolds(ctx, holdSweepBatchSize); err != nil {
+				logger.Error().Err(err).Msg("hold expiry notice sweep failed")
+			}
+		}
+	}
+}
+
+// runIdempotencySweeper periodically deletes idempotency keys whose replay window has passed
+// It heartbeats into workers after every sweep that completes without error
+func runIdempotencySweeper(ctx context.Context, service *app.BookingService, interval time.Duration, workers *infrastructure.WorkerRegistry, logger zerolog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				deleted, err := service.DeleteExpiredIdempotencyKeys(ctx, idempotencySweepBatchSize)
+				if err != nil {
+					logger.Error().Err(err).Msg("idempotency key sweep failed")
+					break
+				}
+				if deleted < idempotencySweepBatchSize {
+					workers.Heartbeat(idempotencySweeperWorker)
+					break
+				}
+			}
+		}
+	}
+}
+
+// runReservationSweeper periodically returns the tickets of bookings reserved with an expiry that were
+// not confirmed in time
+// It heartbeats into workers after every sweep that completes without error
+func runReservationSweeper(ctx context.Context, service *app.BookingService, interval time.Duration, workers *infrastructure.WorkerRegistry, logger zerolog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Keep draining while full batches come back so a backlog clears within one tick
+			for {
+				released, err := service.ReleaseExpiredBookings(ctx, reservationSweepBatchSize)
+				if err != nil {
+					logger.Error().Err(err).Msg("reservation sweep failed")
+					break
+				}
+				if released < reservationSweepBatchSize {
+					workers.Heartbeat(reservationSweeperWorker)
+					break
+				}
+			}
+		}
+	}
+}
+
+// runSchemaDriftDetector periodically checks the schema still matches the migrations; the detector reports drift itself
+// It heartbeats into workers after every check that reaches the database, whether or not it finds drift
+func runSchemaDriftDetector(ctx context.Context, detector *infrastructure.SchemaDriftDetector, interval time.Duration, workers *infrastructure.WorkerRegistry, logger zerolog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := detector.Check(ctx); err != nil {
+				logger.Error().Err(err).Msg("schema drift check failed")
+				continue
+			}
+			workers.Heartbeat(schemaDriftWorker)
+		}
+	}
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
+
+// checkDuplicateAvailability reports events violating the one-availability-row-per-event invariant
+// It only logs, so a data issue does not keep the whole service down
+func checkDuplicateAvailability(repo *infrastructure.PostgresTicketAvailabilityRepository, logger zerolog.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	eventIDs, err := repo.FindDuplicateEventIDs(ctx)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to check for duplicate ticket availability rows")
+		return
+	}
+
+	for _, eventID := range eventIDs {
+		logger.Error().Str("event_id", eventID.String()).Msg("duplicate ticket availability rows detected")
+	}
+}
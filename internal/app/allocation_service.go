@@ -0,0 +1,105 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/rs/zerolog"
+)
+
+// AllocationService manages the blocks of an event's tickets set aside from public sale
+type AllocationService struct {
+	repo                   domain.AllocationRepository
+	eventRepo              domain.EventRepository
+	ticketAvailabilityRepo domain.TicketAvailabilityRepository
+	db                     infrastructure.DBClient
+	logger                 zerolog.Logger
+}
+
+func NewAllocationService(
+	repo domain.AllocationRepository,
+	eventRepo domain.EventRepository,
+	ticketAvailabilityRepo domain.TicketAvailabilityRepository,
+	db infrastructure.DBClient,
+	logger zerolog.Logger,
+) *AllocationService {
+	return &AllocationService{
+		repo:                   repo,
+		eventRepo:              eventRepo,
+		ticketAvailabilityRepo: ticketAvailabilityRepo,
+		db:                     db,
+		logger:                 logger.With().Str("service", "allocation").Logger(),
+	}
+}
+
+type CreateAllocationRequest struct {
+	EventID uuid.UUID
+	Name    string
+	Tickets int
+}
+
+// CreateAllocation moves tickets out of the event's public availability into a new named block
+// It fails with ErrAllocationExceedsAvailable when public sale has fewer tickets left than requested
+func (s *AllocationService) CreateAllocation(ctx context.Context, req CreateAllocationRequest) (*domain.Allocation, error) {
+	allocation, err := domain.NewAllocation(req.EventID, req.Name, req.Tickets)
+	if err != nil {
+		return nil, err
+	}
+
+	err = WithTransaction(ctx, s.db, s.logger, nil, "create_allocation", func(tx domain.Transaction) error {
+		event, err := s.eventRepo.FindByIDForShare(ctx, tx, req.EventID)
+		if err != nil {
+			return fmt.Errorf("failed to find event: %w", err)
+		}
+		if event.Status == domain.EventStatusCancelled {
+			return domain.ErrEventCancelled
+		}
+
+		availability, err := s.ticketAvailabilityRepo.FindByEventIDWithLock(ctx, tx, req.EventID)
+		if err != nil {
+			return fmt.Errorf("failed to find ticket availability: %w", err)
+		}
+		if err := availability.Allocate(allocation.Tickets); err != nil {
+			return err
+		}
+
+		if err := s.repo.CreateWithExecutor(ctx, tx, allocation); err != nil {
+			return err
+		}
+		if err := s.ticketAvailabilityRepo.UpdateWithExecutor(ctx, tx, availability, domain.AvailabilityAllocated); err != nil {
+			return fmt.Errorf("failed to update ticket availability: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Warn().Err(err).Str("event_id", req.EventID.String()).Str("name", allocation.Name).Msg("failed to create allocation")
+		return nil, err
+	}
+
+	s.logger.Info().
+		Str("allocation_id", allocation.ID.String()).
+		Str("event_id", allocation.EventID.String()).
+		Str("name", allocation.Name).
+		Int("tickets", allocation.Tickets).
+		Msg("allocation created")
+
+	return allocation, nil
+}
+
+// ListAllocations returns the event's allocations with their available tickets, ordered by name
+func (s *AllocationService) ListAllocations(ctx context.Context, eventID uuid.UUID) ([]*domain.Allocation, error) {
+	if _, err := s.eventRepo.FindByID(ctx, eventID); err != nil {
+		return nil, fmt.Errorf("failed to find event: %w", err)
+	}
+
+	allocations, err := s.repo.FindByEvent(ctx, eventID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("event_id", eventID.String()).Msg("failed to list allocations")
+		return nil, fmt.Errorf("failed to list allocations: %w", err)
+	}
+
+	return allocations, nil
+}
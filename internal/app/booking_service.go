@@ -3,7 +3,10 @@ package app
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jorzel/booking-service/internal/domain"
@@ -11,41 +14,541 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// DefaultIdempotencyKeyTTL is how long a booking request's idempotency key is replayed before it may be reused
+const DefaultIdempotencyKeyTTL = 24 * time.Hour
+
 type BookingService struct {
 	bookingRepo            domain.BookingRepository
+	eventRepo              domain.EventRepository
 	ticketAvailabilityRepo domain.TicketAvailabilityRepository
+	seatRepo               domain.SeatRepository
+	idempotencyRepo        domain.IdempotencyKeyRepository
+	discountCodeRepo       domain.DiscountCodeRepository
 	db                     infrastructure.DBClient
 	logger                 zerolog.Logger
+	idGenerator            domain.IDGenerator
+	idempotencyKeyTTL      time.Duration
+	dedupWindow            time.Duration
+	clock                  domain.Clock
+	fxRates                domain.FXRateProvider
+	fxRateMaxAge           time.Duration
+	webhooks               *WebhookService
+	limiter                *EventLimiter
+	allocationRepo         domain.AllocationRepository
+	cancellationPolicy     domain.CancellationPolicy
+}
+
+type BookingServiceOption func(*BookingService)
+
+// WithBookingIDGenerator sets how IDs of newly created bookings are generated
+func WithBookingIDGenerator(gen domain.IDGenerator) BookingServiceOption {
+	return func(s *BookingService) {
+		s.idGenerator = gen
+	}
+}
+
+// WithIdempotencyKeyTTL sets how long idempotency keys are honoured before the cleanup job deletes them
+func WithIdempotencyKeyTTL(ttl time.Duration) BookingServiceOption {
+	return func(s *BookingService) {
+		s.idempotencyKeyTTL = ttl
+	}
+}
+
+// WithRequestDedup makes identical booking requests without an Idempotency-Key, arriving within window
+// of each other, return the first request's booking; a zero window disables it
+func WithRequestDedup(window time.Duration) BookingServiceOption {
+	return func(s *BookingService) {
+		s.dedupWindow = window
+	}
+}
+
+// WithBookingClock replaces the clock used for booking cutoffs, viability deadlines and idempotency expiry
+func WithBookingClock(clock domain.Clock) BookingServiceOption {
+	return func(s *BookingService) {
+		s.clock = clock
+	}
+}
+
+// WithFXConversion lets users pay in a currency other than the event's, converting the price at rates
+// from provider; rates published more than maxAge ago are refused. Without it only the event's currency is accepted
+func WithFXConversion(provider domain.FXRateProvider, maxAge time.Duration) BookingServiceOption {
+	return func(s *BookingService) {
+		s.fxRates = provider
+		s.fxRateMaxAge = maxAge
+	}
+}
+
+// WithBookingWebhooks notifies the webhooks registered for an event of each new booking of it
+func WithBookingWebhooks(webhooks *WebhookService) BookingServiceOption {
+	return func(s *BookingService) {
+		s.webhooks = webhooks
+	}
+}
+
+// WithAllocations lets bookings draw from an event's allocation blocks instead of public sale
+// Without it bookings naming an allocation fail with domain.ErrAllocationNotFound
+func WithAllocations(repo domain.AllocationRepository) BookingServiceOption {
+	return func(s *BookingService) {
+		s.allocationRepo = repo
+	}
+}
+
+// WithCancellationPolicy sets the refund tiers quoted on listed bookings (default: domain.DefaultCancellationPolicy)
+func WithCancellationPolicy(policy domain.CancellationPolicy) BookingServiceOption {
+	return func(s *BookingService) {
+		s.cancellationPolicy = policy
+	}
+}
+
+// WithEventConcurrency lets at most limit bookings of one event run at once; the rest fail fast with
+// domain.ErrEventBusy instead of queueing on the event's lock. Zero or less removes the cap
+func WithEventConcurrency(limit int) BookingServiceOption {
+	return func(s *BookingService) {
+		s.limiter = nil
+		if limit > 0 {
+			s.limiter = NewEventLimiter(limit)
+		}
+	}
 }
 
 func NewBookingService(
 	bookingRepo domain.BookingRepository,
+	eventRepo domain.EventRepository,
 	ticketAvailabilityRepo domain.TicketAvailabilityRepository,
+	seatRepo domain.SeatRepository,
+	idempotencyRepo domain.IdempotencyKeyRepository,
+	discountCodeRepo domain.DiscountCodeRepository,
 	db infrastructure.DBClient,
 	logger zerolog.Logger,
+	opts ...BookingServiceOption,
 ) *BookingService {
-	return &BookingService{
+	s := &BookingService{
 		bookingRepo:            bookingRepo,
+		eventRepo:              eventRepo,
 		ticketAvailabilityRepo: ticketAvailabilityRepo,
+		seatRepo:               seatRepo,
+		idempotencyRepo:        idempotencyRepo,
+		discountCodeRepo:       discountCodeRepo,
 		db:                     db,
 		logger:                 logger.With().Str("service", "booking").Logger(),
+		idGenerator:            domain.RandomIDGenerator{},
+		idempotencyKeyTTL:      DefaultIdempotencyKeyTTL,
+		clock:                  domain.SystemClock(),
+		limiter:                NewEventLimiter(DefaultEventConcurrency),
+		cancellationPolicy:     domain.DefaultCancellationPolicy(),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 type CreateBookingRequest struct {
 	EventID       uuid.UUID
 	UserID        uuid.UUID
 	TicketsBooked int
+	Conditional   bool     // Book subject to the event reaching its minimum group size
+	Seats         []string // Seat labels, required for reserved-seating events and one per ticket
+	// IdempotencyKey makes retries with the same key return the original booking instead of booking again
+	IdempotencyKey string
+	DiscountCode   string // Optional code redeemed against the booking's price
+	PayCurrency    string // Optional currency to pay in, converted from the event's at the current rate
+	// Allocation names the block of the event's tickets to book from; empty or "public" books from public sale
+	Allocation string
+	// ExpiresIn reserves the tickets as a pending booking that is released unless confirmed within it;
+	// zero confirms the booking immediately
+	ExpiresIn time.Duration
+
+	// createdBy is set only by CreateBookingOnBehalf, so self-service requests cannot claim an admin
+	createdBy string
 }
 
 func (s *BookingService) CreateBooking(ctx context.Context, req CreateBookingRequest) (*domain.Booking, error) {
-	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	now := s.clock.Now()
+	if req.DiscountCode != "" {
+		code, err := domain.NormalizeDiscountCode(req.DiscountCode)
+		if err != nil {
+			return nil, err
+		}
+		req.DiscountCode = code
+	}
+	if req.PayCurrency != "" {
+		currency, err := domain.NormalizePayCurrency(req.PayCurrency)
+		if err != nil {
+			return nil, err
+		}
+		req.PayCurrency = currency
+	}
+	if req.Allocation != "" {
+		allocation, err := domain.NormalizeAllocationName(req.Allocation)
+		if err != nil {
+			return nil, err
+		}
+		if allocation == domain.PublicAllocation {
+			allocation = ""
+		}
+		req.Allocation = allocation
+	}
+	if req.ExpiresIn != 0 {
+		if err := domain.ValidateReservationTTL(req.ExpiresIn); err != nil {
+			return nil, err
+		}
+		if req.Conditional {
+			return nil, domain.ErrConditionalReservation
+		}
+	}
+
+	idempotencyKey, err := s.requestKey(req, now)
+	if err != nil {
+		return nil, err
+	}
+	if idempotencyKey != nil {
+		// Retries usually arrive after the original committed, so they replay without re-checking the event
+		existing, err := s.idempotencyRepo.FindByKeyWithExecutor(ctx, s.db, idempotencyKey.Key)
+		if err == nil && !existing.IsExpired(now) {
+			return s.replayBooking(ctx, existing, req)
+		}
+		if err != nil && !errors.Is(err, domain.ErrIdempotencyKeyNotFound) {
+			return nil, fmt.Errorf("failed to find idempotency key: %w", err)
+		}
+	}
+
+	// Event metadata is not part of the TicketAvailability aggregate, so it is read before locking
+	event, err := s.eventRepo.FindByID(ctx, req.EventID)
+	if err != nil {
+		s.logger.Error().
+			Err(err).
+			Str("event_id", req.EventID.String()).
+			Msg("failed to find event")
+		return nil, fmt.Errorf("failed to find event: %w", err)
+	}
+
+	if err := event.CheckBookable(s.clock.RuleTime()); err != nil {
+		s.logger.Warn().
+			Err(err).
+			Str("event_id", req.EventID.String()).
+			Str("status", string(event.Status)).
+			Dur("min_advance", event.MinAdvance).
+			Msg("event does not accept bookings")
+		return nil, err
+	}
+	if err := event.CheckDirectSale(); err != nil {
+		return nil, err
+	}
+
+	if err := event.CheckSeatSelection(req.Seats); err != nil {
+		return nil, err
+	}
+	if err := event.CheckQuantity(req.TicketsBooked); err != nil {
+		return nil, err
+	}
+	if event.Seated {
+		if err := domain.ValidateSeatSelection(req.Seats, req.TicketsBooked); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Conditional {
+		if err := event.CheckConditionalBooking(s.clock.RuleTime()); err != nil {
+			s.logger.Warn().
+				Err(err).
+				Str("event_id", req.EventID.String()).
+				Msg("event does not accept conditional bookings")
+			return nil, err
+		}
+	}
+
+	quote, err := event.Quote(req.TicketsBooked)
+	if err != nil {
+		return nil, err
+	}
+
+	// The rate is fetched before the transaction, so a slow provider does not hold the availability lock
+	payRate, err := s.payRate(ctx, quote, req.PayCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := s.acquireEvents(req.EventID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var booking *domain.Booking
+	var soldOut bool
+	var existing *domain.IdempotencyKey
+	txOpts := &sql.TxOptions{Isolation: sql.LevelSerializable}
+	err = WithTransaction(ctx, s.db, s.logger, txOpts, "create_booking", func(tx domain.Transaction) error {
+		// The key is claimed before any tickets are touched, so of two concurrent requests only one books
+		if idempotencyKey != nil {
+			claimed, err := s.idempotencyRepo.ClaimWithExecutor(ctx, tx, idempotencyKey)
+			if err != nil {
+				return err
+			}
+			if !claimed {
+				existing, err = s.idempotencyRepo.FindByKeyWithExecutor(ctx, tx, idempotencyKey.Key)
+				return err
+			}
+		}
+
+		// Re-checked under a shared lock, so a pause or cancellation committed since the read above
+		// rejects the booking, and one in progress waits for it to finish
+		current, err := s.eventRepo.FindByIDForShare(ctx, tx, req.EventID)
+		if err != nil {
+			return fmt.Errorf("failed to find event: %w", err)
+		}
+		if err := current.CheckBookable(s.clock.RuleTime()); err != nil {
+			s.logger.Warn().Err(err).Str("event_id", req.EventID.String()).Msg("event stopped accepting bookings")
+			return err
+		}
+
+		booking, soldOut, err = s.reserveTickets(ctx, tx, req, current.Tickets, quote, payRate, now)
+		if err != nil {
+			return err
+		}
+
+		if idempotencyKey != nil {
+			return s.idempotencyRepo.SetBookingWithExecutor(ctx, tx, idempotencyKey.Key, booking.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return s.replayBooking(ctx, existing, req)
+	}
+	if soldOut {
+		recordSellout(s.logger, event, s.clock.Now())
+	}
+
+	s.logger.Info().
+		Str("booking_id", booking.ID.String()).
+		Str("event_id", booking.EventID.String()).
+		Str("user_id", booking.UserID.String()).
+		Int("tickets", booking.TicketsBooked).
+		Msg("booking created")
+	s.notifyWebhooks(ctx, booking)
+
+	return booking, nil
+}
+
+// acquireEvents takes a booking slot of every event when bookings per event are capped
+func (s *BookingService) acquireEvents(eventIDs ...uuid.UUID) (release func(), err error) {
+	if s.limiter == nil {
+		return func() {}, nil
+	}
+	release, err = s.limiter.AcquireAll(eventIDs)
+	if err != nil {
+		// Counted rather than logged: during a flash sale this happens thousands of times a second
+		infrastructure.BookingsThrottled.Inc()
+		return nil, err
+	}
+	return release, nil
+}
+
+// notifyWebhooks tells the webhooks of the booking's event about it, when webhooks are configured
+func (s *BookingService) notifyWebhooks(ctx context.Context, booking *domain.Booking) {
+	if s.webhooks != nil {
+		s.webhooks.NotifyBookingCreated(ctx, booking)
+	}
+}
+
+// requestKey returns the key deduplicating req: the client's Idempotency-Key, or with request dedup
+// enabled a fingerprint of the request's content; nil when neither applies
+func (s *BookingService) requestKey(req CreateBookingRequest, now time.Time) (*domain.IdempotencyKey, error) {
+	if req.IdempotencyKey != "" {
+		return domain.NewIdempotencyKey(req.IdempotencyKey, s.idempotencyKeyTTL, now)
+	}
+	if s.dedupWindow > 0 {
+		return domain.NewRequestFingerprintKey(req.EventID, req.UserID, req.TicketsBooked, req.Conditional, req.Seats, req.DiscountCode, req.PayCurrency, req.Allocation, req.ExpiresIn, s.dedupWindow, now)
+	}
+	return nil, nil
+}
+
+// CreateBookingOnBehalf books for req.UserID as the admin actor, e.g. support staff taking a phone order
+// The booking is made exactly as if the user had booked, and records the actor for auditing
+func (s *BookingService) CreateBookingOnBehalf(ctx context.Context, actor string, req CreateBookingRequest) (*domain.Booking, error) {
+	actor = strings.TrimSpace(actor)
+	if actor == "" || len(actor) > domain.MaxBookingActorLen {
+		return nil, domain.ErrInvalidBookingActor
+	}
+	req.createdBy = actor
+
+	booking, err := s.CreateBooking(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info().
+		Str("booking_id", booking.ID.String()).
+		Str("user_id", req.UserID.String()).
+		Str("created_by", actor).
+		Msg("booking created on behalf of user")
+
+	return booking, nil
+}
+
+// replayBooking returns the booking a previous request with the same idempotency key created
+func (s *BookingService) replayBooking(ctx context.Context, key *domain.IdempotencyKey, req CreateBookingRequest) (*domain.Booking, error) {
+	booking, err := s.GetBooking(ctx, key.BookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !key.Matches(booking, req.EventID, req.UserID, req.TicketsBooked) {
+		s.logger.Warn().
+			Str("booking_id", booking.ID.String()).
+			Str("event_id", req.EventID.String()).
+			Msg("idempotency key reused with different parameters")
+		return nil, domain.ErrIdempotencyKeyReused
+	}
+
+	s.logger.Info().
+		Str("booking_id", booking.ID.String()).
+		Msg("booking replayed for idempotency key")
+
+	return booking, nil
+}
+
+// DeleteExpiredIdempotencyKeys removes up to limit idempotency keys whose replay window has passed
+func (s *BookingService) DeleteExpiredIdempotencyKeys(ctx context.Context, limit int) (int, error) {
+	deleted, err := s.idempotencyRepo.DeleteExpired(ctx, s.clock.Now(), limit)
 	if err != nil {
-		s.logger.Error().Err(err).Msg("failed to begin transaction")
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		s.logger.Error().Err(err).Msg("failed to delete expired idempotency keys")
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	if deleted > 0 {
+		s.logger.Info().Int("deleted", deleted).Msg("expired idempotency keys deleted")
 	}
-	defer tx.Rollback()
 
+	return deleted, nil
+}
+
+// payRate returns the rate converting quote's currency to payCurrency, or nil when no conversion is needed:
+// no currency was requested, it is the event's own, or the event is free
+func (s *BookingService) payRate(ctx context.Context, quote domain.Quote, payCurrency string) (*domain.FXRate, error) {
+	if payCurrency == "" || payCurrency == quote.Currency || quote.TotalCents == 0 {
+		return nil, nil
+	}
+	if s.fxRates == nil {
+		return nil, domain.ErrUnsupportedCurrency
+	}
+
+	rate, err := s.fxRates.Rate(ctx, quote.Currency, payCurrency)
+	if err != nil {
+		s.logger.Warn().
+			Err(err).
+			Str("currency", quote.Currency).
+			Str("pay_currency", payCurrency).
+			Msg("failed to find exchange rate")
+		return nil, err
+	}
+	if err := rate.CheckFresh(s.clock.Now(), s.fxRateMaxAge); err != nil {
+		s.logger.Warn().
+			Str("currency", quote.Currency).
+			Str("pay_currency", payCurrency).
+			Time("as_of", rate.AsOf).
+			Msg("exchange rate is stale")
+		return nil, err
+	}
+
+	return &rate, nil
+}
+
+// reserveTickets reserves the tickets from public sale or the requested allocation, redeems the discount
+// code and records the booking at the quoted price within tx; total is the event's ticket count
+// soldOut reports that the booking took the event's last tickets on public sale, and the booking carries
+// the public availability it left
+func (s *BookingService) reserveTickets(ctx context.Context, tx domain.Transaction, req CreateBookingRequest, total int, quote domain.Quote, payRate *domain.FXRate, now time.Time) (booking *domain.Booking, soldOut bool, err error) {
+	var allocationID uuid.UUID
+	var eventAvailable *int
+	if req.Allocation != "" {
+		allocationID, err = s.reserveAllocated(ctx, tx, req)
+	} else {
+		var available int
+		available, err = s.reservePublic(ctx, tx, req, total)
+		eventAvailable, soldOut = &available, available == 0
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	price := quote.TotalCents
+	if req.DiscountCode != "" {
+		price, err = s.redeemDiscountCode(ctx, tx, req.DiscountCode, quote, now)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	opts := []domain.BookingOption{
+		domain.WithBookingIDGenerator(s.idGenerator),
+		domain.WithBookingPrice(price, quote.Currency),
+		domain.WithDiscountCode(req.DiscountCode),
+		domain.WithCreatedBy(req.createdBy),
+		domain.WithAllocation(allocationID),
+	}
+	if req.Conditional {
+		opts = append(opts, domain.AsConditional())
+	}
+	if req.ExpiresIn > 0 {
+		opts = append(opts, domain.WithExpiry(now.Add(req.ExpiresIn)))
+	}
+	// The discounted price is converted, so the user pays the same discount whatever their currency
+	if payRate != nil {
+		payPrice, err := payRate.Convert(price)
+		if err != nil {
+			return nil, false, err
+		}
+		opts = append(opts, domain.WithPayPrice(payPrice, *payRate))
+	}
+
+	booking, err = domain.NewBooking(req.EventID, req.UserID, req.TicketsBooked, opts...)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to create booking domain object")
+		return nil, false, fmt.Errorf("invalid booking data: %w", err)
+	}
+
+	var seats []*domain.Seat
+	if len(req.Seats) > 0 {
+		seats, err = s.seatRepo.FindByLabelsWithLock(ctx, tx, req.EventID, req.Seats)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to find seats: %w", err)
+		}
+		if err := domain.ReserveSeats(seats, req.Seats, booking.ID); err != nil {
+			s.logger.Warn().
+				Err(err).
+				Str("event_id", req.EventID.String()).
+				Strs("seats", req.Seats).
+				Msg("seats unavailable")
+			return nil, false, err
+		}
+		booking.SeatLabels = req.Seats
+	}
+	booking.EventAvailableTickets = eventAvailable
+
+	if err := s.bookingRepo.CreateWithExecutor(ctx, tx, booking); err != nil {
+		s.logger.Error().
+			Err(err).
+			Str("booking_id", booking.ID.String()).
+			Msg("failed to save booking")
+		return nil, false, fmt.Errorf("failed to create booking: %w", err)
+	}
+
+	// Seats reference the booking, so they are assigned once it exists
+	if err := s.seatRepo.UpdateWithExecutor(ctx, tx, seats); err != nil {
+		return nil, false, fmt.Errorf("failed to reserve seats: %w", err)
+	}
+
+	return booking, soldOut, nil
+}
+
+// reservePublic locks the event's availability and reserves the tickets from public sale within tx
+// available is what public sale has left afterwards
+func (s *BookingService) reservePublic(ctx context.Context, tx domain.Transaction, req CreateBookingRequest, total int) (available int, err error) {
 	// Lock the TicketAvailability aggregate (not the Event entity)
 	ticketAvailability, err := s.ticketAvailabilityRepo.FindByEventIDWithLock(ctx, tx, req.EventID)
 	if err != nil {
@@ -53,54 +556,417 @@ func (s *BookingService) CreateBooking(ctx context.Context, req CreateBookingReq
 			Err(err).
 			Str("event_id", req.EventID.String()).
 			Msg("failed to find ticket availability")
-		return nil, fmt.Errorf("failed to find ticket availability: %w", err)
+		return 0, fmt.Errorf("failed to find ticket availability: %w", err)
 	}
 
 	// Use the aggregate to enforce booking business rules
-	if err := ticketAvailability.ReserveTickets(req.TicketsBooked); err != nil {
+	if err := ticketAvailability.ReserveTickets(req.TicketsBooked, total); err != nil {
+		popularity := recordSoldOutRejection(err, total)
 		s.logger.Warn().
 			Err(err).
 			Str("event_id", req.EventID.String()).
 			Int("requested", req.TicketsBooked).
 			Int("available", ticketAvailability.AvailableTickets).
+			Str("popularity", popularity).
 			Msg("insufficient tickets")
-		return nil, err
+		return 0, err
 	}
 
 	// Update the aggregate
-	if err := s.ticketAvailabilityRepo.UpdateWithExecutor(ctx, tx, ticketAvailability); err != nil {
+	if err := s.ticketAvailabilityRepo.UpdateWithExecutor(ctx, tx, ticketAvailability, domain.AvailabilityBooked); err != nil {
 		s.logger.Error().
 			Err(err).
 			Str("event_id", req.EventID.String()).
 			Msg("failed to update ticket availability")
-		return nil, fmt.Errorf("failed to update ticket availability: %w", err)
+		return 0, fmt.Errorf("failed to update ticket availability: %w", err)
+	}
+
+	return ticketAvailability.AvailableTickets, nil
+}
+
+// reserveAllocated locks the requested allocation and reserves the tickets from it within tx
+// Public availability is neither read nor locked, so allocation bookings do not contend with public sale
+func (s *BookingService) reserveAllocated(ctx context.Context, tx domain.Transaction, req CreateBookingRequest) (uuid.UUID, error) {
+	if s.allocationRepo == nil {
+		return uuid.Nil, domain.ErrAllocationNotFound
 	}
 
-	booking, err := domain.NewBooking(req.EventID, req.UserID, req.TicketsBooked)
+	allocation, err := s.allocationRepo.FindByNameWithLock(ctx, tx, req.EventID, req.Allocation)
 	if err != nil {
-		s.logger.Error().Err(err).Msg("failed to create booking domain object")
-		return nil, fmt.Errorf("invalid booking data: %w", err)
+		return uuid.Nil, err
 	}
 
-	if err := s.bookingRepo.CreateWithExecutor(ctx, tx, booking); err != nil {
-		s.logger.Error().
+	if err := allocation.Reserve(req.TicketsBooked); err != nil {
+		s.logger.Warn().
 			Err(err).
-			Str("booking_id", booking.ID.String()).
-			Msg("failed to save booking")
-		return nil, fmt.Errorf("failed to create booking: %w", err)
+			Str("event_id", req.EventID.String()).
+			Str("allocation", allocation.Name).
+			Int("requested", req.TicketsBooked).
+			Int("available", allocation.AvailableTickets).
+			Msg("insufficient allocated tickets")
+		return uuid.Nil, err
+	}
+
+	if err := s.allocationRepo.UpdateWithExecutor(ctx, tx, allocation); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to update allocation: %w", err)
+	}
+
+	return allocation.ID, nil
+}
+
+// releaseTickets returns a cancelled booking's tickets to the allocation they came from, or else to availability
+func (s *BookingService) releaseTickets(ctx context.Context, tx domain.Transaction, availability *domain.TicketAvailability, booking *domain.Booking) error {
+	if booking.AllocationID == uuid.Nil {
+		return availability.ReleaseTickets(booking.TicketsBooked)
+	}
+	if s.allocationRepo == nil {
+		return domain.ErrAllocationNotFound
+	}
+
+	allocation, err := s.allocationRepo.FindByIDWithLock(ctx, tx, booking.AllocationID)
+	if err != nil {
+		return err
+	}
+	if err := allocation.Release(booking.TicketsBooked); err != nil {
+		return err
+	}
+	return s.allocationRepo.UpdateWithExecutor(ctx, tx, allocation)
+}
+
+// recordSoldOutRejection counts a reservation that failed for lack of tickets under its event's popularity
+// bucket, which it returns for logging; other reservation errors are not counted
+// Bookings, holds and lottery winners' bookings all reserve through it
+func recordSoldOutRejection(err error, total int) string {
+	popularity := infrastructure.EventPopularityBucket(total)
+	if errors.Is(err, domain.ErrInsufficientTickets) {
+		infrastructure.SoldOutRejections.WithLabelValues(popularity).Inc()
+	}
+	return popularity
+}
+
+// recordSellout observes how long the event took to sell out, once its last tickets were reserved
+// It is called after the reserving transaction commits, so a rolled back reservation is never observed
+func recordSellout(logger zerolog.Logger, event *domain.Event, now time.Time) {
+	if event.CreatedAt.IsZero() {
+		return
 	}
 
-	if err := tx.Commit(); err != nil {
-		s.logger.Error().Err(err).Msg("failed to commit transaction")
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	elapsed := now.Sub(event.CreatedAt)
+	infrastructure.TimeToSellout.Observe(elapsed.Seconds())
+	logger.Info().
+		Str("event_id", event.ID.String()).
+		Dur("time_to_sellout", elapsed).
+		Msg("event sold out")
+}
+
+// redeemDiscountCode uses up one redemption of code and returns the quoted total after its discount
+// The code's row stays locked until tx ends, so concurrent bookings cannot redeem it past its max uses
+func (s *BookingService) redeemDiscountCode(ctx context.Context, tx domain.Transaction, code string, quote domain.Quote, now time.Time) (int64, error) {
+	discountCode, err := s.discountCodeRepo.FindByCodeWithLock(ctx, tx, code)
+	if err != nil {
+		return 0, err
+	}
+
+	price, err := discountCode.Apply(quote.TotalCents, quote.Currency)
+	if err != nil {
+		return 0, err
+	}
+	if err := discountCode.Redeem(now); err != nil {
+		s.logger.Warn().
+			Err(err).
+			Str("discount_code", code).
+			Int("uses", discountCode.Uses).
+			Int("max_uses", discountCode.MaxUses).
+			Msg("discount code rejected")
+		return 0, err
+	}
+
+	if err := s.discountCodeRepo.UpdateUsesWithExecutor(ctx, tx, discountCode); err != nil {
+		return 0, fmt.Errorf("failed to redeem discount code: %w", err)
+	}
+
+	return price, nil
+}
+
+type CreateDiscountCodeRequest struct {
+	Code           string
+	PercentOff     int
+	AmountOffCents int64
+	Currency       string
+	MaxUses        int
+	ExpiresAt      time.Time
+}
+
+func (s *BookingService) CreateDiscountCode(ctx context.Context, req CreateDiscountCodeRequest) (*domain.DiscountCode, error) {
+	code, err := domain.NewDiscountCode(req.Code, req.PercentOff, req.AmountOffCents, req.Currency, req.MaxUses, req.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.discountCodeRepo.Create(ctx, code); err != nil {
+		s.logger.Error().Err(err).Str("discount_code", code.Code).Msg("failed to create discount code")
+		return nil, fmt.Errorf("failed to create discount code: %w", err)
+	}
+
+	s.logger.Info().
+		Str("discount_code", code.Code).
+		Int("max_uses", code.MaxUses).
+		Msg("discount code created")
+
+	return code, nil
+}
+
+// CartItem is one event's share of a multi-event booking
+type CartItem struct {
+	EventID       uuid.UUID
+	TicketsBooked int
+}
+
+type CreateBookingsRequest struct {
+	UserID uuid.UUID
+	Items  []CartItem
+}
+
+// CreateBookings books every cart item in one transaction, or none of them
+// Availability rows are locked in one query in event ID order, written back with one statement and
+// the bookings are inserted with one multi-row statement, so the round trips do not grow with the
+// number of events. Reserved-seating and conditional bookings go through CreateBooking
+func (s *BookingService) CreateBookings(ctx context.Context, req CreateBookingsRequest) ([]*domain.Booking, error) {
+	if len(req.Items) == 0 {
+		return nil, domain.ErrEmptyCart
+	}
+
+	eventIDs := make([]uuid.UUID, 0, len(req.Items))
+	seen := make(map[uuid.UUID]struct{}, len(req.Items))
+	for _, item := range req.Items {
+		if _, ok := seen[item.EventID]; ok {
+			return nil, domain.ErrDuplicateCartEvent
+		}
+		seen[item.EventID] = struct{}{}
+		eventIDs = append(eventIDs, item.EventID)
+	}
+
+	events, err := s.eventRepo.FindByIDs(ctx, eventIDs)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to find cart events")
+		return nil, fmt.Errorf("failed to find events: %w", err)
+	}
+	if len(events) != len(eventIDs) {
+		return nil, domain.ErrEventNotFound
+	}
+
+	ruleTime := s.clock.RuleTime()
+	eventsByID := make(map[uuid.UUID]*domain.Event, len(events))
+	for _, event := range events {
+		eventsByID[event.ID] = event
+		if err := event.CheckBookable(ruleTime); err != nil {
+			s.logger.Warn().Err(err).Str("event_id", event.ID.String()).Msg("cart event does not accept bookings")
+			return nil, err
+		}
+		if err := event.CheckDirectSale(); err != nil {
+			return nil, err
+		}
+		if err := event.CheckSeatSelection(nil); err != nil {
+			return nil, err
+		}
+	}
+	for _, item := range req.Items {
+		if err := eventsByID[item.EventID].CheckQuantity(item.TicketsBooked); err != nil {
+			return nil, err
+		}
+	}
+
+	release, err := s.acquireEvents(eventIDs...)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var bookings []*domain.Booking
+	var soldOut []uuid.UUID
+	txOpts := &sql.TxOptions{Isolation: sql.LevelSerializable}
+	err = WithTransaction(ctx, s.db, s.logger, txOpts, "create_bookings", func(tx domain.Transaction) error {
+		var err error
+		bookings, soldOut, err = s.reserveCart(ctx, tx, req, eventIDs, eventsByID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, eventID := range soldOut {
+		recordSellout(s.logger, eventsByID[eventID], s.clock.Now())
+	}
+
+	s.logger.Info().
+		Str("user_id", req.UserID.String()).
+		Int("events", len(bookings)).
+		Msg("cart bookings created")
+	for _, booking := range bookings {
+		s.notifyWebhooks(ctx, booking)
+	}
+
+	return bookings, nil
+}
+
+// reserveCart reserves tickets for every cart item and records the bookings, priced per event, within tx
+// soldOut lists the events whose last tickets the cart took
+func (s *BookingService) reserveCart(ctx context.Context, tx domain.Transaction, req CreateBookingsRequest, eventIDs []uuid.UUID, events map[uuid.UUID]*domain.Event) (bookings []*domain.Booking, soldOut []uuid.UUID, err error) {
+	// Re-checked under shared locks, taken in one query, so a pause or cancellation committed since the
+	// events were read rejects the cart, and one in progress waits for it to finish
+	current, err := s.eventRepo.FindByIDsForShare(ctx, tx, eventIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find events: %w", err)
+	}
+	if len(current) != len(eventIDs) {
+		return nil, nil, domain.ErrEventNotFound
+	}
+	ruleTime := s.clock.RuleTime()
+	for _, event := range current {
+		if err := event.CheckBookable(ruleTime); err != nil {
+			s.logger.Warn().Err(err).Str("event_id", event.ID.String()).Msg("cart event stopped accepting bookings")
+			return nil, nil, err
+		}
+		events[event.ID] = event
+	}
+
+	availabilities, err := s.ticketAvailabilityRepo.FindByEventIDsWithLock(ctx, tx, eventIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find ticket availability: %w", err)
+	}
+	if len(availabilities) != len(eventIDs) {
+		return nil, nil, domain.ErrEventNotFound
+	}
+
+	byEvent := make(map[uuid.UUID]*domain.TicketAvailability, len(availabilities))
+	for _, availability := range availabilities {
+		byEvent[availability.EventID] = availability
+	}
+
+	bookings = make([]*domain.Booking, 0, len(req.Items))
+	for _, item := range req.Items {
+		availability := byEvent[item.EventID]
+		if err := availability.ReserveTickets(item.TicketsBooked, events[item.EventID].Tickets); err != nil {
+			popularity := recordSoldOutRejection(err, events[item.EventID].Tickets)
+			s.logger.Warn().
+				Err(err).
+				Str("event_id", item.EventID.String()).
+				Int("requested", item.TicketsBooked).
+				Int("available", availability.AvailableTickets).
+				Str("popularity", popularity).
+				Msg("insufficient tickets")
+			return nil, nil, err
+		}
+
+		quote, err := events[item.EventID].Quote(item.TicketsBooked)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		booking, err := domain.NewBooking(item.EventID, req.UserID, item.TicketsBooked,
+			domain.WithBookingIDGenerator(s.idGenerator),
+			domain.WithBookingPrice(quote.TotalCents, quote.Currency))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid booking data: %w", err)
+		}
+		bookings = append(bookings, booking)
+		if availability.AvailableTickets == 0 {
+			soldOut = append(soldOut, item.EventID)
+		}
+	}
+
+	if err := s.ticketAvailabilityRepo.UpdateBatchWithExecutor(ctx, tx, availabilities, domain.AvailabilityBooked); err != nil {
+		return nil, nil, fmt.Errorf("failed to update ticket availability: %w", err)
+	}
+	if err := s.bookingRepo.CreateBatchWithExecutor(ctx, tx, bookings); err != nil {
+		return nil, nil, fmt.Errorf("failed to create bookings: %w", err)
+	}
+
+	return bookings, soldOut, nil
+}
+
+// Buyout books every ticket still available for the event to one user in a single booking
+// The count is read under the availability lock, so a buyout racing other bookings takes exactly what they left
+// Events sold in multiples of a quantity step are bought out to the largest multiple; the rest stays available
+// Reserved-seating events cannot be bought out, as every seat would have to be selected
+func (s *BookingService) Buyout(ctx context.Context, eventID, userID uuid.UUID) (*domain.Booking, error) {
+	event, err := s.eventRepo.FindByID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find event: %w", err)
+	}
+	ruleTime := s.clock.RuleTime()
+	if err := event.CheckBookable(ruleTime); err != nil {
+		s.logger.Warn().Err(err).Str("event_id", eventID.String()).Msg("event does not accept bookings")
+		return nil, err
+	}
+	if err := event.CheckDirectSale(); err != nil {
+		return nil, err
+	}
+	if event.Seated {
+		return nil, domain.ErrBuyoutSeated
+	}
+
+	release, err := s.acquireEvents(eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var booking *domain.Booking
+	var soldOut bool
+	txOpts := &sql.TxOptions{Isolation: sql.LevelSerializable}
+	err = WithTransaction(ctx, s.db, s.logger, txOpts, "buyout", func(tx domain.Transaction) error {
+		current, err := s.eventRepo.FindByIDForShare(ctx, tx, eventID)
+		if err != nil {
+			return fmt.Errorf("failed to find event: %w", err)
+		}
+		if err := current.CheckBookable(ruleTime); err != nil {
+			return err
+		}
+
+		availability, err := s.ticketAvailabilityRepo.FindByEventIDWithLock(ctx, tx, eventID)
+		if err != nil {
+			return fmt.Errorf("failed to find ticket availability: %w", err)
+		}
+		tickets, err := availability.ReserveAll(current.QuantityStep)
+		if err != nil {
+			s.logger.Warn().Err(err).Str("event_id", eventID.String()).Msg("nothing left to buy out")
+			return err
+		}
+		soldOut = availability.AvailableTickets == 0
+
+		quote, err := current.Quote(tickets)
+		if err != nil {
+			return err
+		}
+		booking, err = domain.NewBooking(eventID, userID, tickets,
+			domain.WithBookingIDGenerator(s.idGenerator),
+			domain.WithBookingPrice(quote.TotalCents, quote.Currency))
+		if err != nil {
+			return fmt.Errorf("invalid booking data: %w", err)
+		}
+
+		if err := s.ticketAvailabilityRepo.UpdateWithExecutor(ctx, tx, availability, domain.AvailabilityBooked); err != nil {
+			return fmt.Errorf("failed to update ticket availability: %w", err)
+		}
+		if err := s.bookingRepo.CreateWithExecutor(ctx, tx, booking); err != nil {
+			return fmt.Errorf("failed to create booking: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if soldOut {
+		recordSellout(s.logger, event, s.clock.Now())
 	}
 
 	s.logger.Info().
 		Str("booking_id", booking.ID.String()).
-		Str("event_id", booking.EventID.String()).
-		Str("user_id", booking.UserID.String()).
+		Str("event_id", eventID.String()).
+		Str("user_id", userID.String()).
 		Int("tickets", booking.TicketsBooked).
-		Msg("booking created")
+		Msg("event bought out")
+	s.notifyWebhooks(ctx, booking)
 
 	return booking, nil
 }
@@ -112,5 +978,599 @@ func (s *BookingService) GetBooking(ctx context.Context, id uuid.UUID) (*domain.
 		return nil, fmt.Errorf("failed to get booking: %w", err)
 	}
 
+	seats, err := s.seatRepo.FindByBooking(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Str("booking_id", id.String()).Msg("failed to find booking seats")
+		return nil, fmt.Errorf("failed to get booking seats: %w", err)
+	}
+	for _, seat := range seats {
+		booking.SeatLabels = append(booking.SeatLabels, seat.Label)
+	}
+
 	return booking, nil
 }
+
+// UserBooking is one of a user's bookings with the refund cancelling it now would give
+type UserBooking struct {
+	Booking *domain.Booking
+	Refund  domain.Refund
+}
+
+// ListUserBookings returns the user's bookings, newest first, each with its refund under the cancellation
+// policy at the current time, and counts all of them
+func (s *BookingService) ListUserBookings(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*UserBooking, int, error) {
+	bookings, err := s.bookingRepo.FindByUser(ctx, userID, limit, offset)
+	if err != nil {
+		s.logger.Error().Err(err).Str("user_id", userID.String()).Msg("failed to list user bookings")
+		return nil, 0, fmt.Errorf("failed to list bookings: %w", err)
+	}
+
+	total, err := s.bookingRepo.CountByUser(ctx, userID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("user_id", userID.String()).Msg("failed to count user bookings")
+		return nil, 0, fmt.Errorf("failed to count bookings: %w", err)
+	}
+
+	// The refund depends on how far each booking's event is, so the events are loaded in one query
+	eventIDs := make([]uuid.UUID, 0, len(bookings))
+	seen := make(map[uuid.UUID]bool, len(bookings))
+	for _, booking := range bookings {
+		if !seen[booking.EventID] {
+			seen[booking.EventID] = true
+			eventIDs = append(eventIDs, booking.EventID)
+		}
+	}
+	events, err := s.eventRepo.FindByIDs(ctx, eventIDs)
+	if err != nil {
+		s.logger.Error().Err(err).Str("user_id", userID.String()).Msg("failed to find events of user bookings")
+		return nil, 0, fmt.Errorf("failed to find events: %w", err)
+	}
+	eventsByID := make(map[uuid.UUID]*domain.Event, len(events))
+	for _, event := range events {
+		eventsByID[event.ID] = event
+	}
+
+	now := s.clock.Now()
+	result := make([]*UserBooking, len(bookings))
+	for i, booking := range bookings {
+		result[i] = &UserBooking{Booking: booking}
+		if event, ok := eventsByID[booking.EventID]; ok {
+			result[i].Refund = event.RefundPolicy(s.cancellationPolicy).RefundFor(booking, event, now)
+		}
+	}
+
+	return result, total, nil
+}
+
+// GetBookingHistory returns the booking's changes, oldest first
+func (s *BookingService) GetBookingHistory(ctx context.Context, id uuid.UUID) ([]*domain.BookingChange, error) {
+	if _, err := s.bookingRepo.FindByID(ctx, id); err != nil {
+		s.logger.Error().Err(err).Str("booking_id", id.String()).Msg("failed to find booking")
+		return nil, fmt.Errorf("failed to get booking: %w", err)
+	}
+
+	changes, err := s.bookingRepo.FindChanges(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Str("booking_id", id.String()).Msg("failed to find booking changes")
+		return nil, fmt.Errorf("failed to get booking history: %w", err)
+	}
+
+	return changes, nil
+}
+
+// GetEventLedger pages through the event's ledger, the booking changes that moved its public availability,
+// oldest first, with the availability after each one run from the event's total, and counts all of them
+// Holds, allocations and manual adjustments move availability outside the ledger, so where the last entry
+// differs from the current availability, they or drift account for the difference
+func (s *BookingService) GetEventLedger(ctx context.Context, eventID uuid.UUID, limit, offset int) ([]*domain.LedgerEntry, int, error) {
+	event, err := s.eventRepo.FindByID(ctx, eventID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find event: %w", err)
+	}
+
+	changes, err := s.bookingRepo.FindLedger(ctx, eventID, limit, offset)
+	if err != nil {
+		s.logger.Error().Err(err).Str("event_id", eventID.String()).Msg("failed to list event ledger")
+		return nil, 0, fmt.Errorf("failed to list event ledger: %w", err)
+	}
+
+	total, err := s.bookingRepo.CountLedger(ctx, eventID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("event_id", eventID.String()).Msg("failed to count event ledger")
+		return nil, 0, fmt.Errorf("failed to count event ledger: %w", err)
+	}
+
+	// A later page opens with the availability the earlier pages' changes left
+	opening := event.Tickets
+	if offset > 0 && len(changes) > 0 {
+		earlier, err := s.bookingRepo.SumLedgerByAction(ctx, eventID, offset)
+		if err != nil {
+			s.logger.Error().Err(err).Str("event_id", eventID.String()).Msg("failed to sum event ledger")
+			return nil, 0, fmt.Errorf("failed to sum event ledger: %w", err)
+		}
+		for _, entry := range domain.BuildLedger(opening, earlier) {
+			opening = entry.AvailableAfter
+		}
+	}
+
+	return domain.BuildLedger(opening, changes), total, nil
+}
+
+// SearchByConfirmationCode finds bookings whose confirmation code starts with prefix, ignoring case,
+// and counts all matches
+func (s *BookingService) SearchByConfirmationCode(ctx context.Context, prefix string, limit, offset int) ([]*domain.Booking, int, error) {
+	prefix, err := domain.NormalizeConfirmationCodePrefix(prefix)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	bookings, err := s.bookingRepo.FindByConfirmationCodePrefix(ctx, prefix, limit, offset)
+	if err != nil {
+		s.logger.Error().Err(err).Str("code_prefix", prefix).Msg("failed to search bookings by confirmation code")
+		return nil, 0, fmt.Errorf("failed to search bookings: %w", err)
+	}
+
+	total, err := s.bookingRepo.CountByConfirmationCodePrefix(ctx, prefix)
+	if err != nil {
+		s.logger.Error().Err(err).Str("code_prefix", prefix).Msg("failed to count bookings by confirmation code")
+		return nil, 0, fmt.Errorf("failed to count bookings: %w", err)
+	}
+
+	return bookings, total, nil
+}
+
+// ExportBookings streams bookings matching filter to fn in booked_at order
+func (s *BookingService) ExportBookings(ctx context.Context, filter domain.BookingFilter, fn func(*domain.Booking) error) error {
+	if err := s.bookingRepo.Stream(ctx, filter, fn); err != nil {
+		s.logger.Error().Err(err).Msg("failed to export bookings")
+		return fmt.Errorf("failed to export bookings: %w", err)
+	}
+
+	return nil
+}
+
+// BookingRefund is what cancelling a booking refunded, in the currency of its price
+type BookingRefund struct {
+	BookingID   uuid.UUID
+	RefundCents int64
+	Currency    string
+}
+
+// UserCancellation summarizes CancelAllForUser
+// Refunds lists every cancelled booking, as the user's events may be priced in different currencies
+type UserCancellation struct {
+	Cancelled       int
+	TicketsReleased int
+	Events          int
+	Refunds         []BookingRefund
+}
+
+// CancelAllForUser cancels every booking of the user that is not cancelled yet and returns the tickets,
+// e.g. when the user deletes their account
+// Bookings are locked before availability, as when resolving conditional bookings, and availability rows
+// are locked in event ID order like cart bookings, so it cannot deadlock with either
+func (s *BookingService) CancelAllForUser(ctx context.Context, userID uuid.UUID) (*UserCancellation, error) {
+	summary := &UserCancellation{}
+
+	err := WithTransaction(ctx, s.db, s.logger, nil, "cancel_user_bookings", func(tx domain.Transaction) error {
+		*summary = UserCancellation{}
+
+		bookings, err := s.bookingRepo.FindActiveByUserWithLock(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		if len(bookings) == 0 {
+			return nil
+		}
+
+		// Bookings are ordered by event ID, so the IDs come out sorted and unique
+		var eventIDs []uuid.UUID
+		for _, booking := range bookings {
+			if len(eventIDs) == 0 || eventIDs[len(eventIDs)-1] != booking.EventID {
+				eventIDs = append(eventIDs, booking.EventID)
+			}
+		}
+
+		availabilities, err := s.ticketAvailabilityRepo.FindByEventIDsWithLock(ctx, tx, eventIDs)
+		if err != nil {
+			return fmt.Errorf("failed to find ticket availability: %w", err)
+		}
+		byEvent := make(map[uuid.UUID]*domain.TicketAvailability, len(availabilities))
+		for _, availability := range availabilities {
+			byEvent[availability.EventID] = availability
+		}
+
+		// Each event's cancellation policy decides the refund of its bookings
+		events, err := s.eventRepo.FindByIDs(ctx, eventIDs)
+		if err != nil {
+			return fmt.Errorf("failed to find events: %w", err)
+		}
+		eventsByID := make(map[uuid.UUID]*domain.Event, len(events))
+		for _, event := range events {
+			eventsByID[event.ID] = event
+		}
+
+		now := s.clock.Now()
+		for _, booking := range bookings {
+			availability, ok := byEvent[booking.EventID]
+			event, found := eventsByID[booking.EventID]
+			if !ok || !found {
+				return domain.ErrEventNotFound
+			}
+			refund := event.RefundPolicy(s.cancellationPolicy).ComputeRefund(event, booking, now)
+			if err := booking.Cancel(refund); err != nil {
+				return err
+			}
+			if err := s.releaseTickets(ctx, tx, availability, booking); err != nil {
+				return err
+			}
+			if err := s.bookingRepo.UpdateStatusWithExecutor(ctx, tx, booking); err != nil {
+				return err
+			}
+			if err := s.seatRepo.ReleaseByBookingWithExecutor(ctx, tx, booking.ID); err != nil {
+				return err
+			}
+			summary.Cancelled++
+			summary.TicketsReleased += booking.TicketsBooked
+			summary.Refunds = append(summary.Refunds, BookingRefund{
+				BookingID:   booking.ID,
+				RefundCents: booking.RefundCents,
+				Currency:    booking.Currency,
+			})
+		}
+		summary.Events = len(availabilities)
+
+		if err := s.ticketAvailabilityRepo.UpdateBatchWithExecutor(ctx, tx, availabilities, domain.AvailabilityBookingCancelled); err != nil {
+			return fmt.Errorf("failed to update ticket availability: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Str("user_id", userID.String()).Msg("failed to cancel user bookings")
+		return nil, err
+	}
+
+	s.logger.Info().
+		Str("user_id", userID.String()).
+		Int("cancelled", summary.Cancelled).
+		Int("tickets_released", summary.TicketsReleased).
+		Int("events", summary.Events).
+		Msg("user bookings cancelled")
+
+	return summary, nil
+}
+
+// ConditionalResolution reports how an event's pending conditional bookings were resolved
+// RefundCents totals what the cancelled bookings refunded, in the event's currency
+type ConditionalResolution struct {
+	Viable      bool
+	Confirmed   int
+	Cancelled   int
+	RefundCents int64
+}
+
+// ResolveConditionalBookings confirms the event's pending conditional bookings once its minimum is met,
+// or cancels them and returns their tickets once the viability deadline passes without it
+// Before either happens it returns ErrViabilityUndecided and changes nothing
+func (s *BookingService) ResolveConditionalBookings(ctx context.Context, eventID uuid.UUID) (*ConditionalResolution, error) {
+	resolution := &ConditionalResolution{}
+
+	err := WithTransaction(ctx, s.db, s.logger, nil, "resolve_conditional_bookings", func(tx domain.Transaction) error {
+		*resolution = ConditionalResolution{}
+
+		// Locking the event serializes concurrent resolutions of the same event
+		event, err := s.eventRepo.FindByIDWithLock(ctx, tx, eventID)
+		if err != nil {
+			return fmt.Errorf("failed to find event: %w", err)
+		}
+
+		sold, err := s.bookingRepo.SumTicketsByEventWithExecutor(ctx, tx, eventID)
+		if err != nil {
+			return err
+		}
+
+		viable, err := event.ResolveViability(sold, s.clock.RuleTime())
+		if err != nil {
+			return err
+		}
+		resolution.Viable = viable
+
+		pending, err := s.bookingRepo.FindPendingByEventWithLock(ctx, tx, eventID)
+		if err != nil {
+			return err
+		}
+
+		if viable {
+			for _, booking := range pending {
+				if err := booking.ConfirmConditional(); err != nil {
+					return err
+				}
+				if err := s.bookingRepo.UpdateStatusWithExecutor(ctx, tx, booking); err != nil {
+					return err
+				}
+				resolution.Confirmed++
+			}
+			return nil
+		}
+
+		return s.cancelConditionalBookings(ctx, tx, eventID, pending, resolution)
+	})
+	if err != nil {
+		s.logger.Warn().Err(err).Str("event_id", eventID.String()).Msg("failed to resolve conditional bookings")
+		return nil, err
+	}
+
+	s.logger.Info().
+		Str("event_id", eventID.String()).
+		Bool("viable", resolution.Viable).
+		Int("confirmed", resolution.Confirmed).
+		Int("cancelled", resolution.Cancelled).
+		Msg("conditional bookings resolved")
+
+	return resolution, nil
+}
+
+// cancelEventBookings cancels the bookings of the cancelled event within tx and returns their tickets
+// The event is already cancelled, so its cancellation policy refunds every booking in full, except
+// reservations that were never confirmed and so never paid
+func (s *BookingService) cancelEventBookings(ctx context.Context, tx domain.Transaction, event *domain.Event) ([]BookingRefund, error) {
+	bookings, err := s.bookingRepo.FindActiveByEventWithLock(ctx, tx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(bookings) == 0 {
+		return nil, nil
+	}
+
+	availability, err := s.ticketAvailabilityRepo.FindByEventIDWithLock(ctx, tx, event.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find ticket availability: %w", err)
+	}
+
+	now := s.clock.Now()
+	refunds := make([]BookingRefund, 0, len(bookings))
+	for _, booking := range bookings {
+		refund := event.RefundPolicy(s.cancellationPolicy).ComputeRefund(event, booking, now)
+		if err := booking.Cancel(refund); err != nil {
+			return nil, err
+		}
+		if err := s.releaseTickets(ctx, tx, availability, booking); err != nil {
+			return nil, err
+		}
+		if err := s.bookingRepo.UpdateStatusWithExecutor(ctx, tx, booking); err != nil {
+			return nil, err
+		}
+		if err := s.seatRepo.ReleaseByBookingWithExecutor(ctx, tx, booking.ID); err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, BookingRefund{
+			BookingID:   booking.ID,
+			RefundCents: booking.RefundCents,
+			Currency:    booking.Currency,
+		})
+	}
+
+	if err := s.ticketAvailabilityRepo.UpdateWithExecutor(ctx, tx, availability, domain.AvailabilityBookingCancelled); err != nil {
+		return nil, fmt.Errorf("failed to update ticket availability: %w", err)
+	}
+
+	return refunds, nil
+}
+
+// cancelConditionalBookings cancels pending bookings and returns their tickets to availability within tx
+// The event did not go ahead, so each booking is refunded in full
+func (s *BookingService) cancelConditionalBookings(
+	ctx context.Context,
+	tx domain.Transaction,
+	eventID uuid.UUID,
+	pending []*domain.Booking,
+	resolution *ConditionalResolution,
+) error {
+	if len(pending) == 0 {
+		return nil
+	}
+
+	availability, err := s.ticketAvailabilityRepo.FindByEventIDWithLock(ctx, tx, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to find ticket availability: %w", err)
+	}
+
+	for _, booking := range pending {
+		if err := booking.CancelConditional(); err != nil {
+			return err
+		}
+		if err := s.releaseTickets(ctx, tx, availability, booking); err != nil {
+			return err
+		}
+		if err := s.bookingRepo.UpdateStatusWithExecutor(ctx, tx, booking); err != nil {
+			return err
+		}
+		if err := s.seatRepo.ReleaseByBookingWithExecutor(ctx, tx, booking.ID); err != nil {
+			return err
+		}
+		resolution.Cancelled++
+		resolution.RefundCents += booking.RefundCents
+	}
+
+	if err := s.ticketAvailabilityRepo.UpdateWithExecutor(ctx, tx, availability, domain.AvailabilityBookingCancelled); err != nil {
+		return fmt.Errorf("failed to update ticket availability: %w", err)
+	}
+
+	return nil
+}
+
+// ConfirmBooking confirms a pending reservation made with an expiry
+// Confirming an expired reservation releases its tickets, if the sweeper has not yet, and returns ErrBookingExpired
+func (s *BookingService) ConfirmBooking(ctx context.Context, id uuid.UUID) (*domain.Booking, error) {
+	var booking *domain.Booking
+	expired := false
+
+	err := WithTransaction(ctx, s.db, s.logger, nil, "confirm_booking", func(tx domain.Transaction) error {
+		now := s.clock.Now()
+
+		var err error
+		booking, err = s.bookingRepo.FindByIDWithLock(ctx, tx, id)
+		if err != nil {
+			return fmt.Errorf("failed to find booking: %w", err)
+		}
+
+		if booking.IsExpired(now) {
+			expired = true
+			return s.expireBookings(ctx, tx, []*domain.Booking{booking}, now)
+		}
+
+		if err := booking.ConfirmReservation(now); err != nil {
+			return err
+		}
+		return s.bookingRepo.UpdateStatusWithExecutor(ctx, tx, booking)
+	})
+	if err != nil {
+		s.logger.Warn().Err(err).Str("booking_id", id.String()).Msg("failed to confirm booking")
+		return nil, err
+	}
+
+	// The release above must commit, so expiry is reported only after the transaction
+	if expired {
+		s.logger.Info().Str("booking_id", id.String()).Msg("confirmation rejected, booking expired")
+		return nil, domain.ErrBookingExpired
+	}
+
+	s.logger.Info().
+		Str("booking_id", booking.ID.String()).
+		Str("event_id", booking.EventID.String()).
+		Msg("booking confirmed")
+
+	return booking, nil
+}
+
+// SplitBooking moves splitCount of the booking's tickets to a new booking for newUserID, e.g. a customer
+// handing some tickets to a friend, and returns the reduced original and the new booking
+// Both stay on the same event and allocation, so availability is untouched. Bookings with reserved seats
+// cannot be split, since the seats would have to be divided too. No per-user ticket limit exists yet, so
+// the new owner is held only to the bounds every booking is
+func (s *BookingService) SplitBooking(ctx context.Context, bookingID uuid.UUID, splitCount int, newUserID uuid.UUID) (original, split *domain.Booking, err error) {
+	err = WithTransaction(ctx, s.db, s.logger, nil, "split_booking", func(tx domain.Transaction) error {
+		var err error
+		original, err = s.bookingRepo.FindByIDWithLock(ctx, tx, bookingID)
+		if err != nil {
+			return fmt.Errorf("failed to find booking: %w", err)
+		}
+
+		event, err := s.eventRepo.FindByIDForShare(ctx, tx, original.EventID)
+		if err != nil {
+			return fmt.Errorf("failed to find event: %w", err)
+		}
+		if event.Seated {
+			return domain.ErrSplitSeated
+		}
+
+		split, err = original.Split(splitCount, newUserID, domain.WithBookingIDGenerator(s.idGenerator))
+		if err != nil {
+			return err
+		}
+		// Both halves must still come in the event's quantity step, e.g. whole tables of four
+		for _, half := range []*domain.Booking{split, original} {
+			if err := event.CheckQuantity(half.TicketsBooked); err != nil {
+				return err
+			}
+		}
+		return s.bookingRepo.SplitWithExecutor(ctx, tx, original, split)
+	})
+	if err != nil {
+		s.logger.Warn().Err(err).Str("booking_id", bookingID.String()).Int("split_count", splitCount).Msg("failed to split booking")
+		return nil, nil, err
+	}
+
+	s.logger.Info().
+		Str("booking_id", original.ID.String()).
+		Str("split_booking_id", split.ID.String()).
+		Str("event_id", original.EventID.String()).
+		Str("user_id", newUserID.String()).
+		Int("tickets", split.TicketsBooked).
+		Msg("booking split")
+	s.notifyWebhooks(ctx, split)
+
+	return original, split, nil
+}
+
+// ReleaseExpiredBookings cancels up to limit reservations that were not confirmed in time and returns their tickets
+func (s *BookingService) ReleaseExpiredBookings(ctx context.Context, limit int) (int, error) {
+	released := 0
+
+	err := WithTransaction(ctx, s.db, s.logger, nil, "release_expired_bookings", func(tx domain.Transaction) error {
+		now := s.clock.Now()
+
+		bookings, err := s.bookingRepo.FindExpiredWithLock(ctx, tx, now, limit)
+		if err != nil {
+			return fmt.Errorf("failed to find expired bookings: %w", err)
+		}
+		if err := s.expireBookings(ctx, tx, bookings, now); err != nil {
+			return err
+		}
+
+		released = len(bookings)
+		return nil
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to release expired bookings")
+		return 0, err
+	}
+
+	if released > 0 {
+		s.logger.Info().Int("released", released).Msg("expired bookings released")
+	}
+
+	return released, nil
+}
+
+// expireBookings cancels locked, expired reservations and returns their tickets within tx
+// Availability rows are locked in event ID order like cart bookings, so it cannot deadlock with them
+func (s *BookingService) expireBookings(ctx context.Context, tx domain.Transaction, bookings []*domain.Booking, now time.Time) error {
+	if len(bookings) == 0 {
+		return nil
+	}
+
+	eventIDs := make([]uuid.UUID, 0, len(bookings))
+	seen := make(map[uuid.UUID]bool, len(bookings))
+	for _, booking := range bookings {
+		if !seen[booking.EventID] {
+			seen[booking.EventID] = true
+			eventIDs = append(eventIDs, booking.EventID)
+		}
+	}
+
+	availabilities, err := s.ticketAvailabilityRepo.FindByEventIDsWithLock(ctx, tx, eventIDs)
+	if err != nil {
+		return fmt.Errorf("failed to find ticket availability: %w", err)
+	}
+	byEvent := make(map[uuid.UUID]*domain.TicketAvailability, len(availabilities))
+	for _, availability := range availabilities {
+		byEvent[availability.EventID] = availability
+	}
+
+	for _, booking := range bookings {
+		availability, ok := byEvent[booking.EventID]
+		if !ok {
+			return domain.ErrEventNotFound
+		}
+		if err := booking.Expire(now); err != nil {
+			return err
+		}
+		if err := s.releaseTickets(ctx, tx, availability, booking); err != nil {
+			return err
+		}
+		if err := s.bookingRepo.ExpireWithExecutor(ctx, tx, booking); err != nil {
+			return err
+		}
+		if err := s.seatRepo.ReleaseByBookingWithExecutor(ctx, tx, booking.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.ticketAvailabilityRepo.UpdateBatchWithExecutor(ctx, tx, availabilities, domain.AvailabilityBookingCancelled); err != nil {
+		return fmt.Errorf("failed to update ticket availability: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,235 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// selloutObservations returns the number of observations and their sum in TimeToSellout
+func selloutObservations(t *testing.T) (uint64, float64) {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == "booking_service_time_to_sellout_seconds" {
+			histogram := family.GetMetric()[0].GetHistogram()
+			return histogram.GetSampleCount(), histogram.GetSampleSum()
+		}
+	}
+	return 0, 0
+}
+
+func TestRecordSellout(t *testing.T) {
+	createdAt := time.Date(2030, 3, 1, 9, 0, 0, 0, time.UTC)
+	event := &domain.Event{Name: "Sold Out Show", CreatedAt: createdAt}
+
+	count, sum := selloutObservations(t)
+	recordSellout(zerolog.Nop(), event, createdAt.Add(90*time.Minute))
+
+	gotCount, gotSum := selloutObservations(t)
+	assert.Equal(t, count+1, gotCount)
+	assert.InDelta(t, 90*60, gotSum-sum, 0.001)
+
+	recordSellout(zerolog.Nop(), &domain.Event{Name: "Unsaved"}, createdAt)
+	gotCount, _ = selloutObservations(t)
+	assert.Equal(t, count+1, gotCount, "events without a creation time are not observed")
+}
+
+func TestBookingService_PayRate(t *testing.T) {
+	now := time.Date(2030, 3, 1, 9, 0, 0, 0, time.UTC)
+	clock := domain.NewClock(func() time.Time { return now }, 0)
+	rates := infrastructure.NewStaticFXRates(
+		domain.FXRate{From: "EUR", To: "USD", Rate: 1.08, AsOf: now.Add(-time.Minute)},
+		domain.FXRate{From: "EUR", To: "GBP", Rate: 0.86, AsOf: now.Add(-2 * time.Hour)},
+	)
+	service := NewBookingService(nil, nil, nil, nil, nil, nil, nil, zerolog.Nop(),
+		WithBookingClock(clock), WithFXConversion(rates, time.Hour))
+	quote := domain.Quote{Tickets: 2, UnitPriceCents: 2500, SubtotalCents: 5000, TotalCents: 5000, Currency: "EUR"}
+	ctx := context.Background()
+
+	rate, err := service.payRate(ctx, quote, "USD")
+	require.NoError(t, err)
+	require.NotNil(t, rate)
+	assert.Equal(t, 1.08, rate.Rate)
+
+	rate, err = service.payRate(ctx, quote, "EUR")
+	require.NoError(t, err)
+	assert.Nil(t, rate, "paying in the event's currency needs no conversion")
+
+	rate, err = service.payRate(ctx, domain.Quote{Tickets: 2, Currency: ""}, "USD")
+	require.NoError(t, err)
+	assert.Nil(t, rate, "free events need no conversion")
+
+	_, err = service.payRate(ctx, quote, "CHF")
+	assert.ErrorIs(t, err, domain.ErrUnsupportedCurrency)
+
+	_, err = service.payRate(ctx, quote, "GBP")
+	assert.ErrorIs(t, err, domain.ErrFXRateStale)
+
+	withoutProvider := NewBookingService(nil, nil, nil, nil, nil, nil, nil, zerolog.Nop())
+	_, err = withoutProvider.payRate(ctx, quote, "USD")
+	assert.ErrorIs(t, err, domain.ErrUnsupportedCurrency)
+}
+
+// lockedAvailabilityRepository serves one event's availability to reservations
+type lockedAvailabilityRepository struct {
+	domain.TicketAvailabilityRepository
+	available int
+}
+
+func (r lockedAvailabilityRepository) FindByEventIDWithLock(ctx context.Context, exec domain.Executor, eventID uuid.UUID) (*domain.TicketAvailability, error) {
+	return &domain.TicketAvailability{EventID: eventID, AvailableTickets: r.available, Version: 1}, nil
+}
+
+func TestBookingService_SoldOutRejections(t *testing.T) {
+	service := NewBookingService(nil, nil, lockedAvailabilityRepository{available: 1}, nil, nil, nil, nil, zerolog.Nop())
+	small := infrastructure.SoldOutRejections.WithLabelValues("small")
+	stadium := infrastructure.SoldOutRejections.WithLabelValues("stadium")
+	before, beforeStadium := testutil.ToFloat64(small), testutil.ToFloat64(stadium)
+	ctx := context.Background()
+
+	_, err := service.reservePublic(ctx, nil, CreateBookingRequest{EventID: uuid.New(), UserID: uuid.New(), TicketsBooked: 2}, 50)
+	assert.ErrorIs(t, err, domain.ErrInsufficientTickets)
+	assert.Equal(t, before+1, testutil.ToFloat64(small))
+
+	_, err = service.reservePublic(ctx, nil, CreateBookingRequest{EventID: uuid.New(), UserID: uuid.New(), TicketsBooked: 2}, 50_000)
+	assert.ErrorIs(t, err, domain.ErrInsufficientTickets)
+	assert.Equal(t, beforeStadium+1, testutil.ToFloat64(stadium))
+
+	_, err = service.reservePublic(ctx, nil, CreateBookingRequest{EventID: uuid.New(), UserID: uuid.New(), TicketsBooked: 60}, 50)
+	assert.ErrorIs(t, err, domain.ErrExceedsCapacity)
+	assert.Equal(t, before+1, testutil.ToFloat64(small), "requests beyond the event's total are not sold-out rejections")
+}
+
+// singleEventRepository serves one event; it holds no state, so concurrent bookings may share it
+type singleEventRepository struct {
+	domain.EventRepository
+	event *domain.Event
+}
+
+func (r singleEventRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Event, error) {
+	event := *r.event
+	event.ID = id
+	return &event, nil
+}
+
+// blockingDB parks every transaction in BeginTx until unblock is closed, then fails it
+type blockingDB struct {
+	infrastructure.DBClient
+	entered chan uuid.UUID
+	unblock chan struct{}
+}
+
+func (db *blockingDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (domain.Transaction, error) {
+	db.entered <- uuid.Nil
+	<-db.unblock
+	return nil, errors.New("database is gone")
+}
+
+func TestBookingService_EventConcurrency(t *testing.T) {
+	event, err := domain.NewEvent("Flash Sale", "Arena", time.Now().Add(30*24*time.Hour), 1000)
+	require.NoError(t, err)
+	db := &blockingDB{entered: make(chan uuid.UUID, 10), unblock: make(chan struct{})}
+	service := NewBookingService(nil, singleEventRepository{event: event}, nil, nil, nil, nil, db, zerolog.Nop(),
+		WithEventConcurrency(2))
+	ctx := context.Background()
+	hotEvent, otherEvent := uuid.New(), uuid.New()
+
+	book := func(eventID uuid.UUID) error {
+		_, err := service.CreateBooking(ctx, CreateBookingRequest{EventID: eventID, UserID: uuid.New(), TicketsBooked: 1})
+		return err
+	}
+	waitEntered := func(t *testing.T) {
+		t.Helper()
+		select {
+		case <-db.entered:
+		case <-time.After(time.Second):
+			t.Fatal("booking did not reach the database")
+		}
+	}
+
+	results := make(chan error, 3)
+	for range 2 {
+		go func() { results <- book(hotEvent) }()
+		waitEntered(t)
+	}
+
+	start := time.Now()
+	err = book(hotEvent)
+	assert.ErrorIs(t, err, domain.ErrEventBusy)
+	assert.Less(t, time.Since(start), 100*time.Millisecond, "excess bookings are rejected without waiting")
+
+	go func() { results <- book(otherEvent) }()
+	waitEntered(t)
+	assert.Equal(t, 2, service.limiter.tracked(), "the cap is per event")
+
+	close(db.unblock)
+	for range 3 {
+		assert.Error(t, <-results)
+	}
+	assert.Zero(t, service.limiter.tracked(), "events without bookings in flight are forgotten")
+
+	assert.NotErrorIs(t, book(hotEvent), domain.ErrEventBusy, "slots are returned when bookings finish")
+	waitEntered(t)
+}
+
+// staleEventRepository serves stale to plain reads and current to reads inside a transaction, as when the
+// event changes between the read that validates a request and the transaction that books it
+type staleEventRepository struct {
+	domain.EventRepository
+	stale, current *domain.Event
+}
+
+func (r staleEventRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Event, error) {
+	return r.stale, nil
+}
+
+func (r staleEventRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.Event, error) {
+	return []*domain.Event{r.stale}, nil
+}
+
+func (r staleEventRepository) FindByIDForShare(ctx context.Context, exec domain.Executor, id uuid.UUID) (*domain.Event, error) {
+	return r.current, nil
+}
+
+func (r staleEventRepository) FindByIDsForShare(ctx context.Context, exec domain.Executor, ids []uuid.UUID) ([]*domain.Event, error) {
+	return []*domain.Event{r.current}, nil
+}
+
+func TestBookingService_RechecksEventInTransaction(t *testing.T) {
+	ctx := context.Background()
+	stale, err := domain.NewEvent("Harbour Festival", "Pier 4", time.Now().Add(30*24*time.Hour), 20)
+	require.NoError(t, err)
+	current := *stale
+	require.NoError(t, current.PauseBookings())
+	events := staleEventRepository{stale: stale, current: &current}
+
+	t.Run("cart bookings", func(t *testing.T) {
+		service := NewBookingService(nil, events, nil, nil, nil, nil, &fakeDB{}, zerolog.Nop())
+
+		_, err := service.CreateBookings(ctx, CreateBookingsRequest{
+			UserID: uuid.New(),
+			Items:  []CartItem{{EventID: stale.ID, TicketsBooked: 2}},
+		})
+		assert.ErrorIs(t, err, domain.ErrBookingPaused)
+	})
+
+	t.Run("holds", func(t *testing.T) {
+		service := NewHoldService(nil, events, nil, nil, &fakeDB{}, zerolog.Nop(), DefaultHoldTTL)
+
+		_, err := service.CreateHold(ctx, CreateHoldRequest{EventID: stale.ID, UserID: uuid.New(), Tickets: 2})
+		assert.ErrorIs(t, err, domain.ErrBookingPaused)
+	})
+}
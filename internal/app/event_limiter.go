@@ -0,0 +1,79 @@
+package app
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+)
+
+// DefaultEventConcurrency is how many bookings of one event may run at once; with the pool at 25
+// connections, one hot event cannot take every connection from the others
+const DefaultEventConcurrency = 10
+
+// EventLimiter caps the bookings of each event running at once, so a flash sale does not queue
+// thousands of requests on the event's availability row lock. Requests over the cap fail fast instead
+// An event's entry is dropped as soon as its last booking finishes, so idle events take no memory
+type EventLimiter struct {
+	mu       sync.Mutex
+	limit    int
+	inFlight map[uuid.UUID]int
+}
+
+func NewEventLimiter(limit int) *EventLimiter {
+	return &EventLimiter{limit: limit, inFlight: make(map[uuid.UUID]int)}
+}
+
+// Acquire takes one of the event's slots, failing with domain.ErrEventBusy when all are taken
+// The returned release gives the slot back and must be called exactly once
+func (l *EventLimiter) Acquire(eventID uuid.UUID) (release func(), err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inFlight[eventID] >= l.limit {
+		return nil, domain.ErrEventBusy
+	}
+	l.inFlight[eventID]++
+
+	var once sync.Once
+	return func() { once.Do(func() { l.release(eventID) }) }, nil
+}
+
+// AcquireAll takes a slot of every event, or none of them
+func (l *EventLimiter) AcquireAll(eventIDs []uuid.UUID) (release func(), err error) {
+	releases := make([]func(), 0, len(eventIDs))
+	releaseAll := func() {
+		for _, release := range releases {
+			release()
+		}
+	}
+
+	for _, eventID := range eventIDs {
+		release, err := l.Acquire(eventID)
+		if err != nil {
+			releaseAll()
+			return nil, err
+		}
+		releases = append(releases, release)
+	}
+	return releaseAll, nil
+}
+
+func (l *EventLimiter) release(eventID uuid.UUID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inFlight[eventID] <= 1 {
+		delete(l.inFlight, eventID)
+		return
+	}
+	l.inFlight[eventID]--
+}
+
+// tracked returns the number of events with bookings in flight
+func (l *EventLimiter) tracked() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.inFlight)
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/rs/zerolog"
+)
+
+// EventMergeService folds duplicate events into one, moving the duplicate's bookings along
+type EventMergeService struct {
+	eventRepo              domain.EventRepository
+	ticketAvailabilityRepo domain.TicketAvailabilityRepository
+	bookingRepo            domain.BookingRepository
+	db                     infrastructure.DBClient
+	logger                 zerolog.Logger
+}
+
+func NewEventMergeService(
+	eventRepo domain.EventRepository,
+	ticketAvailabilityRepo domain.TicketAvailabilityRepository,
+	bookingRepo domain.BookingRepository,
+	db infrastructure.DBClient,
+	logger zerolog.Logger,
+) *EventMergeService {
+	return &EventMergeService{
+		eventRepo:              eventRepo,
+		ticketAvailabilityRepo: ticketAvailabilityRepo,
+		bookingRepo:            bookingRepo,
+		db:                     db,
+		logger:                 logger.With().Str("service", "event_merge").Logger(),
+	}
+}
+
+// EventMerge summarizes a merge: the soft-deleted source, and the bookings moved to the target with their tickets
+type EventMerge struct {
+	Target        *domain.Event
+	Availability  *domain.TicketAvailability
+	BookingsMoved int
+	TicketsMoved  int
+	SourceID      uuid.UUID
+}
+
+// MergeEvents moves every booking of sourceID to targetID and soft-deletes the source, in one transaction
+// The target keeps its capacity: the moved bookings take its available tickets, and the merge fails with
+// ErrMergeOverbooks when too few are left. Both events are locked in ID order, so two merges of the same
+// pair in opposite directions cannot deadlock, and bookings in flight for either finish first
+func (s *EventMergeService) MergeEvents(ctx context.Context, sourceID, targetID uuid.UUID) (*EventMerge, error) {
+	if sourceID == targetID {
+		return nil, domain.ErrMergeSameEvent
+	}
+
+	merge := &EventMerge{SourceID: sourceID}
+	err := WithTransaction(ctx, s.db, s.logger, nil, "merge_events", func(tx domain.Transaction) error {
+		events := make(map[uuid.UUID]*domain.Event, 2)
+		for _, id := range lockOrder(sourceID, targetID) {
+			event, err := s.eventRepo.FindByIDWithLock(ctx, tx, id)
+			if err != nil {
+				return fmt.Errorf("failed to find event: %w", err)
+			}
+			events[id] = event
+		}
+		source, target := events[sourceID], events[targetID]
+		if err := source.CheckMergeInto(target); err != nil {
+			return err
+		}
+
+		availabilities, err := s.ticketAvailabilityRepo.FindByEventIDsWithLock(ctx, tx, []uuid.UUID{sourceID, targetID})
+		if err != nil {
+			return fmt.Errorf("failed to lock ticket availability: %w", err)
+		}
+		var targetAvailability *domain.TicketAvailability
+		for _, availability := range availabilities {
+			if availability.EventID == targetID {
+				targetAvailability = availability
+			}
+		}
+		if targetAvailability == nil {
+			return domain.ErrEventNotFound
+		}
+
+		tickets, err := s.bookingRepo.SumTicketsByEventWithExecutor(ctx, tx, sourceID)
+		if err != nil {
+			return fmt.Errorf("failed to sum source bookings: %w", err)
+		}
+		if err := targetAvailability.AbsorbMerged(tickets); err != nil {
+			return err
+		}
+
+		moved, err := s.bookingRepo.ReassignEventWithExecutor(ctx, tx, sourceID, targetID)
+		if err != nil {
+			return fmt.Errorf("failed to move bookings: %w", err)
+		}
+		if tickets > 0 {
+			if err := s.ticketAvailabilityRepo.UpdateWithExecutor(ctx, tx, targetAvailability, domain.AvailabilityMerged); err != nil {
+				return fmt.Errorf("failed to update ticket availability: %w", err)
+			}
+		}
+		if err := s.eventRepo.MarkMergedWithExecutor(ctx, tx, sourceID, targetID); err != nil {
+			return fmt.Errorf("failed to delete source event: %w", err)
+		}
+
+		merge.Target = target
+		merge.Availability = targetAvailability
+		merge.BookingsMoved = moved
+		merge.TicketsMoved = tickets
+		return nil
+	})
+	if err != nil {
+		s.logger.Warn().Err(err).Str("source_id", sourceID.String()).Str("target_id", targetID.String()).Msg("failed to merge events")
+		return nil, err
+	}
+
+	s.logger.Info().
+		Str("source_id", sourceID.String()).
+		Str("target_id", targetID.String()).
+		Int("bookings_moved", merge.BookingsMoved).
+		Int("tickets_moved", merge.TicketsMoved).
+		Int("available", merge.Availability.AvailableTickets).
+		Msg("events merged")
+
+	return merge, nil
+}
+
+// lockOrder returns the two event IDs in the order Postgres sorts UUIDs, as FindByEventIDsWithLock locks them
+func lockOrder(a, b uuid.UUID) []uuid.UUID {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		return []uuid.UUID{b, a}
+	}
+	return []uuid.UUID{a, b}
+}
@@ -2,7 +2,9 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,38 +15,169 @@ import (
 
 type EventService struct {
 	repo                   domain.EventRepository
+	replicaRepo            domain.EventRepository
 	ticketAvailabilityRepo domain.TicketAvailabilityRepository
+	seatRepo               domain.SeatRepository
 	db                     infrastructure.DBClient
 	logger                 zerolog.Logger
+	idGenerator            domain.IDGenerator
+	checkDuplicates        bool
+	clock                  domain.Clock
+	defaultDuration        time.Duration
+	bookings               *BookingService
+}
+
+type EventServiceOption func(*EventService)
+
+// WithReadReplica enables stale-read fallback to a replica-backed repository
+func WithReadReplica(replicaRepo domain.EventRepository) EventServiceOption {
+	return func(s *EventService) {
+		s.replicaRepo = replicaRepo
+	}
+}
+
+// WithEventIDGenerator sets how IDs of newly created events are generated
+func WithEventIDGenerator(gen domain.IDGenerator) EventServiceOption {
+	return func(s *EventService) {
+		s.idGenerator = gen
+	}
+}
+
+// WithDuplicateNameCheck enables the soft same-name, same-day duplicate check on event creation
+func WithDuplicateNameCheck(enabled bool) EventServiceOption {
+	return func(s *EventService) {
+		s.checkDuplicates = enabled
+	}
+}
+
+// WithEventClock replaces the clock used for time-dependent rules, such as rejecting past event dates
+func WithEventClock(clock domain.Clock) EventServiceOption {
+	return func(s *EventService) {
+		s.clock = clock
+	}
+}
+
+// WithDefaultEventDuration sets how long events created without an end last; 0 leaves their end unknown
+func WithDefaultEventDuration(d time.Duration) EventServiceOption {
+	return func(s *EventService) {
+		s.defaultDuration = d
+	}
+}
+
+// WithCancelledEventBookings cancels an event's bookings together with the event, refunding each in full
+// Without it CancelEvent leaves the bookings for the caller to cancel
+func WithCancelledEventBookings(bookings *BookingService) EventServiceOption {
+	return func(s *EventService) {
+		s.bookings = bookings
+	}
 }
 
 func NewEventService(
 	repo domain.EventRepository,
 	ticketAvailabilityRepo domain.TicketAvailabilityRepository,
+	seatRepo domain.SeatRepository,
 	db infrastructure.DBClient,
 	logger zerolog.Logger,
+	opts ...EventServiceOption,
 ) *EventService {
-	return &EventService{
+	s := &EventService{
 		repo:                   repo,
 		ticketAvailabilityRepo: ticketAvailabilityRepo,
+		seatRepo:               seatRepo,
 		db:                     db,
 		logger:                 logger.With().Str("service", "event").Logger(),
+		idGenerator:            domain.RandomIDGenerator{},
+		clock:                  domain.SystemClock(),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 type CreateEventRequest struct {
-	Name     string
-	Date     time.Time
-	Location string
-	Tickets  int
+	Name        string
+	Date        time.Time
+	Location    string
+	Tickets     int
+	OrganizerID uuid.UUID
+	MinAdvance  time.Duration
+	// EndDate or Duration sets when the event ends; with neither, the service's default duration applies
+	EndDate  time.Time
+	Duration time.Duration
+	// MinViable tickets must sell by ViabilityDeadline for the event to run; 0 disables the requirement
+	MinViable         int
+	ViabilityDeadline time.Time
+	// Seats makes the event reserved seating, one label per ticket; empty means general admission
+	Seats []string
+	// PriceCents per ticket in Currency; 0 makes the event free
+	PriceCents int64
+	Currency   string
+	Tags       []string
+	// ImageURL and ThumbnailURL point at the event's externally hosted poster; both are optional
+	ImageURL     string
+	ThumbnailURL string
+	// QuantityStep sells the tickets only in multiples of it, e.g. 4 for tables of four; 0 allows any quantity
+	QuantityStep int
+	// RefundTiers replace the service's cancellation policy for this event; empty keeps the service's
+	RefundTiers []domain.RefundTier
+	// Lottery sells the tickets only through the event's lottery draw instead of bookings and holds
+	Lottery bool
+	// AllowPastDate skips the future-date check for admin flows that backfill historical events
+	AllowPastDate bool
 }
 
 func (s *EventService) CreateEvent(ctx context.Context, req CreateEventRequest) (*domain.Event, error) {
-	event, err := domain.NewEvent(req.Name, req.Location, req.Date, req.Tickets)
+	endDate, err := s.endDate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []domain.EventOption{
+		domain.WithEndDate(endDate),
+		domain.WithOrganizer(req.OrganizerID),
+		domain.WithMinAdvance(req.MinAdvance),
+		domain.WithMinViable(req.MinViable, req.ViabilityDeadline),
+		domain.WithPrice(req.PriceCents, req.Currency),
+		domain.WithTags(req.Tags),
+		domain.WithImages(req.ImageURL, req.ThumbnailURL),
+		domain.WithIDGenerator(s.idGenerator),
+	}
+	if len(req.Seats) > 0 {
+		opts = append(opts, domain.WithSeating())
+	}
+	if req.QuantityStep != 0 {
+		opts = append(opts, domain.WithQuantityStep(req.QuantityStep))
+	}
+	if req.Lottery {
+		opts = append(opts, domain.WithLottery())
+	}
+	if len(req.RefundTiers) > 0 {
+		policy, err := domain.NewCancellationPolicy(req.RefundTiers)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, domain.WithCancellationPolicy(policy))
+	}
+
+	event, err := domain.NewEvent(req.Name, req.Location, req.Date, req.Tickets, opts...)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("failed to create event domain object")
 		return nil, fmt.Errorf("invalid event data: %w", err)
 	}
+	if !req.AllowPastDate {
+		if err := event.CheckFutureDate(s.clock.RuleTime()); err != nil {
+			return nil, err
+		}
+	}
+
+	var seats []*domain.Seat
+	if event.Seated {
+		seats, err = domain.NewSeatMap(event.ID, req.Seats, req.Tickets)
+		if err != nil {
+			return nil, fmt.Errorf("invalid seat map: %w", err)
+		}
+	}
 
 	// Create TicketAvailability aggregate for the event
 	ticketAvailability, err := domain.NewTicketAvailability(event.ID, req.Tickets)
@@ -54,26 +187,26 @@ func (s *EventService) CreateEvent(ctx context.Context, req CreateEventRequest)
 	}
 
 	// Use transaction to ensure atomic creation of both Event and TicketAvailability
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		s.logger.Error().Err(err).Msg("failed to begin transaction")
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback()
+	err = WithTransaction(ctx, s.db, s.logger, nil, "create_event", func(tx domain.Transaction) error {
+		if err := s.repo.CreateWithExecutor(ctx, tx, event); err != nil {
+			s.logger.Error().Err(err).Str("event_id", event.ID.String()).Msg("failed to save event")
+			return fmt.Errorf("failed to create event: %w", err)
+		}
 
-	if err := s.repo.CreateWithExecutor(ctx, tx, event); err != nil {
-		s.logger.Error().Err(err).Str("event_id", event.ID.String()).Msg("failed to save event")
-		return nil, fmt.Errorf("failed to create event: %w", err)
-	}
+		if err := s.ticketAvailabilityRepo.CreateWithExecutor(ctx, tx, ticketAvailability); err != nil {
+			s.logger.Error().Err(err).Str("event_id", event.ID.String()).Msg("failed to save ticket availability")
+			return fmt.Errorf("failed to create ticket availability: %w", err)
+		}
 
-	if err := s.ticketAvailabilityRepo.CreateWithExecutor(ctx, tx, ticketAvailability); err != nil {
-		s.logger.Error().Err(err).Str("event_id", event.ID.String()).Msg("failed to save ticket availability")
-		return nil, fmt.Errorf("failed to create ticket availability: %w", err)
-	}
+		if err := s.seatRepo.CreateBatchWithExecutor(ctx, tx, seats); err != nil {
+			s.logger.Error().Err(err).Str("event_id", event.ID.String()).Msg("failed to save seats")
+			return fmt.Errorf("failed to create seats: %w", err)
+		}
 
-	if err := tx.Commit(); err != nil {
-		s.logger.Error().Err(err).Msg("failed to commit transaction")
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	s.logger.Info().
@@ -85,6 +218,62 @@ func (s *EventService) CreateEvent(ctx context.Context, req CreateEventRequest)
 	return event, nil
 }
 
+// endDate resolves when a new event ends from its explicit end, its duration or the default duration
+func (s *EventService) endDate(req CreateEventRequest) (time.Time, error) {
+	switch {
+	case !req.EndDate.IsZero() && req.Duration != 0:
+		return time.Time{}, domain.ErrEndDateAndDuration
+	case !req.EndDate.IsZero():
+		return req.EndDate, nil
+	case req.Duration < 0:
+		return time.Time{}, domain.ErrInvalidEndDate
+	case req.Duration > 0:
+		return req.Date.Add(req.Duration), nil
+	case s.defaultDuration > 0:
+		return req.Date.Add(s.defaultDuration), nil
+	}
+	return time.Time{}, nil
+}
+
+// GetSeatMap returns the seats of a reserved-seating event ordered by label
+func (s *EventService) GetSeatMap(ctx context.Context, eventID uuid.UUID) ([]*domain.Seat, error) {
+	event, err := s.repo.FindByID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	if !event.Seated {
+		return nil, domain.ErrSeatingNotSupported
+	}
+
+	seats, err := s.seatRepo.FindByEvent(ctx, eventID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("event_id", eventID.String()).Msg("failed to find seats")
+		return nil, fmt.Errorf("failed to get seat map: %w", err)
+	}
+
+	return seats, nil
+}
+
+// FindSameDayDuplicate returns an existing event with the same name on the same calendar day as date
+// It returns nil when there is none or the check is disabled. Lookup failures are logged and
+// ignored, since the check only warns and must never block event creation
+func (s *EventService) FindSameDayDuplicate(ctx context.Context, name string, date time.Time) *domain.Event {
+	if !s.checkDuplicates {
+		return nil
+	}
+
+	existing, err := s.repo.FindByNameAndDate(ctx, name, date)
+	if errors.Is(err, domain.ErrEventNotFound) {
+		return nil
+	}
+	if err != nil {
+		s.logger.Warn().Err(err).Str("name", name).Msg("duplicate event check failed")
+		return nil
+	}
+
+	return existing
+}
+
 func (s *EventService) GetEvent(ctx context.Context, id uuid.UUID) (*domain.Event, error) {
 	event, err := s.repo.FindByID(ctx, id)
 	if err != nil {
@@ -95,13 +284,693 @@ func (s *EventService) GetEvent(ctx context.Context, id uuid.UUID) (*domain.Even
 	return event, nil
 }
 
+// GetEventAllowStale reads from the primary and, when the primary is unreachable and a replica
+// is configured, falls back to the replica. stale reports whether the replica answered
+func (s *EventService) GetEventAllowStale(ctx context.Context, id uuid.UUID) (event *domain.Event, stale bool, err error) {
+	event, err = s.repo.FindByID(ctx, id)
+	if err == nil {
+		return event, false, nil
+	}
+	if s.replicaRepo == nil || !infrastructure.IsConnectionError(err) {
+		s.logger.Error().Err(err).Str("event_id", id.String()).Msg("failed to find event")
+		return nil, false, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	s.logger.Warn().Err(err).Str("event_id", id.String()).Msg("primary unreachable, reading event from replica")
+
+	event, err = s.replicaRepo.FindByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Str("event_id", id.String()).Msg("failed to find event on replica")
+		return nil, false, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	return event, true, nil
+}
+
+// EventCancellation is a cancelled event with the refunds of the bookings cancelled along with it
+type EventCancellation struct {
+	Event   *domain.Event
+	Refunds []BookingRefund
+}
+
+// CancelEvent cancels the event and is idempotent: cancelling an already cancelled event returns it unchanged
+// The event row is locked so concurrent cancellations serialize and only the first one applies side effects
+// With WithCancelledEventBookings its bookings are cancelled and refunded in the same transaction
+func (s *EventService) CancelEvent(ctx context.Context, id uuid.UUID) (*EventCancellation, error) {
+	cancellation := &EventCancellation{}
+	alreadyCancelled := false
+
+	err := WithTransaction(ctx, s.db, s.logger, nil, "cancel_event", func(tx domain.Transaction) error {
+		*cancellation = EventCancellation{}
+
+		event, err := s.repo.FindByIDWithLock(ctx, tx, id)
+		if err != nil {
+			s.logger.Error().Err(err).Str("event_id", id.String()).Msg("failed to find event")
+			return fmt.Errorf("failed to find event: %w", err)
+		}
+		cancellation.Event = event
+
+		if err := event.Cancel(s.clock.Now()); err != nil {
+			if errors.Is(err, domain.ErrEventAlreadyCancelled) {
+				alreadyCancelled = true
+				return nil
+			}
+			return err
+		}
+
+		if err := s.repo.UpdateWithExecutor(ctx, tx, event); err != nil {
+			s.logger.Error().Err(err).Str("event_id", id.String()).Msg("failed to save cancelled event")
+			return fmt.Errorf("failed to cancel event: %w", err)
+		}
+
+		if s.bookings != nil {
+			cancellation.Refunds, err = s.bookings.cancelEventBookings(ctx, tx, event)
+			if err != nil {
+				s.logger.Error().Err(err).Str("event_id", id.String()).Msg("failed to cancel event bookings")
+				return fmt.Errorf("failed to cancel event bookings: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if alreadyCancelled {
+		s.logger.Info().Str("event_id", id.String()).Msg("event already cancelled")
+		return cancellation, nil
+	}
+
+	s.logger.Info().
+		Str("event_id", id.String()).
+		Int("bookings_cancelled", len(cancellation.Refunds)).
+		Msg("event cancelled")
+	return cancellation, nil
+}
+
+// PauseBookings stops new bookings for the event until ResumeBookings, without cancelling it
+func (s *EventService) PauseBookings(ctx context.Context, id uuid.UUID) (*domain.Event, error) {
+	return s.setBookingsPaused(ctx, id, true)
+}
+
+// ResumeBookings lets a paused event accept bookings again
+func (s *EventService) ResumeBookings(ctx context.Context, id uuid.UUID) (*domain.Event, error) {
+	return s.setBookingsPaused(ctx, id, false)
+}
+
+// setBookingsPaused pauses or resumes bookings under the event's row lock, which waits for bookings
+// in flight (they hold it shared), so none commits after the pause returns
+// Both operations are idempotent: an event already in the requested state is returned unchanged
+func (s *EventService) setBookingsPaused(ctx context.Context, id uuid.UUID, paused bool) (*domain.Event, error) {
+	var event *domain.Event
+	err := WithTransaction(ctx, s.db, s.logger, nil, "set_bookings_paused", func(tx domain.Transaction) error {
+		var err error
+		event, err = s.repo.FindByIDWithLock(ctx, tx, id)
+		if err != nil {
+			s.logger.Error().Err(err).Str("event_id", id.String()).Msg("failed to find event")
+			return fmt.Errorf("failed to find event: %w", err)
+		}
+
+		if event.BookingsPaused == paused {
+			return nil
+		}
+		if paused {
+			err = event.PauseBookings()
+		} else {
+			err = event.ResumeBookings()
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := s.repo.UpdateWithExecutor(ctx, tx, event); err != nil {
+			s.logger.Error().Err(err).Str("event_id", id.String()).Msg("failed to save event")
+			return fmt.Errorf("failed to update event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info().Str("event_id", id.String()).Bool("bookings_paused", paused).Msg("event bookings paused state set")
+	return event, nil
+}
+
+// GetAvailability returns the event's current ticket availability with its version
+func (s *EventService) GetAvailability(ctx context.Context, eventID uuid.UUID) (*domain.TicketAvailability, error) {
+	availability, err := s.ticketAvailabilityRepo.FindByEventID(ctx, eventID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("event_id", eventID.String()).Msg("failed to find ticket availability")
+		return nil, fmt.Errorf("failed to get ticket availability: %w", err)
+	}
+
+	return availability, nil
+}
+
+// AdjustAvailability shifts the event's available tickets by delta without changing its total
+// A non-zero expectedVersion makes the adjustment fail with ErrAvailabilityVersionMismatch once
+// the availability has changed since the caller read that version
+func (s *EventService) AdjustAvailability(ctx context.Context, eventID uuid.UUID, delta int, expectedVersion int64) (*domain.TicketAvailability, error) {
+	if delta == 0 {
+		return nil, domain.ErrInvalidAvailabilityDelta
+	}
+	// Checked before the query so an out-of-range delta cannot overflow the INT column arithmetic
+	if delta > domain.MaxTickets || delta < -domain.MaxTickets {
+		return nil, domain.ErrAvailabilityDeltaTooLarge
+	}
+
+	availability, err := s.ticketAvailabilityRepo.AdjustAvailableTickets(ctx, eventID, delta, expectedVersion)
+	if err != nil {
+		s.logger.Warn().
+			Err(err).
+			Str("event_id", eventID.String()).
+			Int("delta", delta).
+			Int64("expected_version", expectedVersion).
+			Msg("failed to adjust ticket availability")
+		return nil, fmt.Errorf("failed to adjust ticket availability: %w", err)
+	}
+
+	s.logger.Info().
+		Str("event_id", eventID.String()).
+		Int("delta", delta).
+		Int("available", availability.AvailableTickets).
+		Msg("ticket availability adjusted")
+
+	return availability, nil
+}
+
+// DefaultReconcileBatchSize is how many events one reconciliation transaction locks at a time
+const DefaultReconcileBatchSize = 100
+
+// AvailabilityReconciliation summarizes a reconciliation run over every event
+type AvailabilityReconciliation struct {
+	Checked   int
+	Corrected int
+	Batches   int
+}
+
+// availabilityCorrection is one event whose availability reconciliation reset
+type availabilityCorrection struct {
+	eventID  uuid.UUID
+	previous int
+	expected int
+}
+
+// ReconcileAllAvailability resets every event of the context's tenant to the availability its bookings, holds
+// and allocations imply; other tenants' events are left alone, so each tenant is reconciled with its own call
+// Events are reconciled batchSize at a time, each batch in its own transaction, so only one batch of
+// availability rows is locked at once and bookings of the other events carry on meanwhile
+// Manual availability adjustments are not derived from bookings, so they are undone as drift
+func (s *EventService) ReconcileAllAvailability(ctx context.Context, batchSize int) (*AvailabilityReconciliation, error) {
+	summary := &AvailabilityReconciliation{}
+	var cursor *domain.EventCursor
+	for {
+		events, err := s.repo.FindPage(ctx, domain.EventFilter{}, cursor, batchSize)
+		if err != nil {
+			s.logger.Error().Err(err).Int("checked", summary.Checked).Msg("failed to list events to reconcile")
+			return nil, fmt.Errorf("failed to list events: %w", err)
+		}
+		if len(events) == 0 {
+			break
+		}
+
+		checked, corrections, err := s.reconcileBatch(ctx, events)
+		if err != nil {
+			s.logger.Error().Err(err).Int("checked", summary.Checked).Int("corrected", summary.Corrected).Msg("failed to reconcile availability")
+			return nil, err
+		}
+		for _, correction := range corrections {
+			s.logger.Warn().
+				Str("event_id", correction.eventID.String()).
+				Int("available", correction.previous).
+				Int("expected", correction.expected).
+				Msg("availability drift corrected")
+		}
+		infrastructure.AvailabilityReconciled.WithLabelValues("corrected").Add(float64(len(corrections)))
+		infrastructure.AvailabilityReconciled.WithLabelValues("consistent").Add(float64(checked - len(corrections)))
+
+		summary.Checked += checked
+		summary.Corrected += len(corrections)
+		summary.Batches++
+		s.logger.Info().
+			Int("batch", summary.Batches).
+			Int("checked", summary.Checked).
+			Int("corrected", summary.Corrected).
+			Msg("availability reconciliation progress")
+
+		if len(events) < batchSize {
+			break
+		}
+		last := events[len(events)-1]
+		cursor = &domain.EventCursor{Date: last.Date, ID: last.ID}
+	}
+
+	s.logger.Info().
+		Int("checked", summary.Checked).
+		Int("corrected", summary.Corrected).
+		Int("batches", summary.Batches).
+		Msg("availability reconciled")
+	return summary, nil
+}
+
+// reconcileBatch locks the events' availability and corrects the rows that drifted, in one transaction
+func (s *EventService) reconcileBatch(ctx context.Context, events []*domain.Event) (int, []availabilityCorrection, error) {
+	eventIDs := make([]uuid.UUID, len(events))
+	for i, event := range events {
+		eventIDs[i] = event.ID
+	}
+
+	var checked int
+	var corrections []availabilityCorrection
+	err := WithTransaction(ctx, s.db, s.logger, nil, "reconcile_availability", func(tx domain.Transaction) error {
+		checked, corrections = 0, nil
+
+		availabilities, err := s.ticketAvailabilityRepo.FindByEventIDsWithLock(ctx, tx, eventIDs)
+		if err != nil {
+			return fmt.Errorf("failed to lock ticket availability: %w", err)
+		}
+		// Derived under the availability locks, which every booking, hold and allocation of public tickets takes
+		expected, err := s.ticketAvailabilityRepo.ExpectedAvailableWithExecutor(ctx, tx, eventIDs)
+		if err != nil {
+			return fmt.Errorf("failed to compute expected availability: %w", err)
+		}
+
+		var drifted []*domain.TicketAvailability
+		for _, availability := range availabilities {
+			want, ok := expected[availability.EventID]
+			if !ok {
+				continue
+			}
+			checked++
+			if want < 0 {
+				s.logger.Error().Str("event_id", availability.EventID.String()).Int("expected", want).Msg("event is overbooked")
+			}
+
+			previous := availability.AvailableTickets
+			if availability.Reconcile(want) {
+				drifted = append(drifted, availability)
+				corrections = append(corrections, availabilityCorrection{
+					eventID:  availability.EventID,
+					previous: previous,
+					expected: availability.AvailableTickets,
+				})
+			}
+		}
+
+		if len(drifted) == 0 {
+			return nil
+		}
+		if err := s.ticketAvailabilityRepo.UpdateBatchWithExecutor(ctx, tx, drifted, domain.AvailabilityReconciled); err != nil {
+			return fmt.Errorf("failed to update ticket availability: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return checked, corrections, nil
+}
+
+// AvailabilityCheck asks whether an event still has Tickets available
+type AvailabilityCheck struct {
+	EventID uuid.UUID
+	Tickets int
+}
+
+// AvailabilityCheckResult answers one AvailabilityCheck; unknown events are reported as unavailable
+type AvailabilityCheckResult struct {
+	EventID   uuid.UUID
+	Available bool
+	Remaining int
+}
+
+// CheckAvailability answers several availability checks, in request order, with a single query
+// The answers are advisory: nothing is locked, so tickets may sell out before checkout
+func (s *EventService) CheckAvailability(ctx context.Context, checks []AvailabilityCheck) ([]AvailabilityCheckResult, error) {
+	if len(checks) == 0 {
+		return nil, domain.ErrEmptyAvailabilityCheck
+	}
+	if len(checks) > domain.MaxAvailabilityChecks {
+		return nil, domain.ErrTooManyAvailabilityChecks
+	}
+
+	eventIDs := make([]uuid.UUID, 0, len(checks))
+	for _, check := range checks {
+		if check.Tickets <= 0 {
+			return nil, domain.ErrInvalidTickets
+		}
+		if check.Tickets > domain.MaxTickets {
+			return nil, domain.ErrTicketsTooLarge
+		}
+		eventIDs = append(eventIDs, check.EventID)
+	}
+
+	availabilities, err := s.ticketAvailabilityRepo.FindByEventIDs(ctx, eventIDs)
+	if err != nil {
+		s.logger.Error().Err(err).Int("events", len(eventIDs)).Msg("failed to find ticket availability")
+		return nil, fmt.Errorf("failed to check ticket availability: %w", err)
+	}
+
+	remaining := make(map[uuid.UUID]int, len(availabilities))
+	for _, availability := range availabilities {
+		remaining[availability.EventID] = availability.AvailableTickets
+	}
+
+	results := make([]AvailabilityCheckResult, len(checks))
+	for i, check := range checks {
+		results[i] = AvailabilityCheckResult{
+			EventID:   check.EventID,
+			Available: remaining[check.EventID] >= check.Tickets,
+			Remaining: remaining[check.EventID],
+		}
+	}
+
+	return results, nil
+}
+
+// QuoteBooking prices tickets at the event without reserving them
+// The quote is advisory: price and availability may change before the booking is made
+func (s *EventService) QuoteBooking(ctx context.Context, eventID uuid.UUID, tickets int) (domain.Quote, error) {
+	event, err := s.repo.FindByID(ctx, eventID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("event_id", eventID.String()).Msg("failed to find event")
+		return domain.Quote{}, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	if err := event.CheckBookable(s.clock.RuleTime()); err != nil {
+		return domain.Quote{}, err
+	}
+
+	return event.Quote(tickets)
+}
+
+// GetUtilization reports how much of the event's capacity is taken, read from ticket availability
+func (s *EventService) GetUtilization(ctx context.Context, eventID uuid.UUID) (domain.Utilization, error) {
+	event, err := s.repo.FindByID(ctx, eventID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("event_id", eventID.String()).Msg("failed to find event")
+		return domain.Utilization{}, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	availability, err := s.ticketAvailabilityRepo.FindByEventID(ctx, eventID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("event_id", eventID.String()).Msg("failed to find ticket availability")
+		return domain.Utilization{}, fmt.Errorf("failed to get ticket availability: %w", err)
+	}
+
+	return availability.Utilization(event.Tickets), nil
+}
+
+// GetAvailabilityAt reconstructs the event's available tickets at at from the availability change log
+// The returned change is the last one at or before at; its Resulting value is the availability then
+func (s *EventService) GetAvailabilityAt(ctx context.Context, eventID uuid.UUID, at time.Time) (*domain.AvailabilityChange, error) {
+	if _, err := s.repo.FindByID(ctx, eventID); err != nil {
+		s.logger.Error().Err(err).Str("event_id", eventID.String()).Msg("failed to find event")
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	change, err := s.ticketAvailabilityRepo.FindChangeAt(ctx, eventID, at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get availability history: %w", err)
+	}
+
+	return change, nil
+}
+
+// EventWithStats is an event with its booking stats
+// Stats is nil when they could not be read; Warnings then says why the response is partial
+type EventWithStats struct {
+	Event    *domain.Event
+	Stats    *EventStats
+	Warnings []string
+}
+
+type EventStats struct {
+	Available   int
+	Utilization domain.Utilization
+}
+
+// GetEventWithStats reads the event and its stats concurrently
+// The event is required and its failure fails the call; the stats are not, so when only they fail the event
+// is returned without them rather than failing the whole request
+func (s *EventService) GetEventWithStats(ctx context.Context, id uuid.UUID) (*EventWithStats, error) {
+	statsCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var availability *domain.TicketAvailability
+	var statsErr error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		availability, statsErr = s.ticketAvailabilityRepo.FindByEventID(statsCtx, id)
+	}()
+
+	event, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		cancel()
+		wg.Wait()
+		s.logger.Error().Err(err).Str("event_id", id.String()).Msg("failed to find event")
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	wg.Wait()
+
+	result := &EventWithStats{Event: event}
+	if statsErr != nil {
+		s.logger.Warn().Err(statsErr).Str("event_id", id.String()).Msg("event stats unavailable, returning event without them")
+		result.Warnings = append(result.Warnings, "stats unavailable")
+		return result, nil
+	}
+
+	result.Stats = &EventStats{
+		Available:   availability.AvailableTickets,
+		Utilization: availability.Utilization(event.Tickets),
+	}
+	return result, nil
+}
+
+// ExportEvents streams every event to fn ordered by date
+func (s *EventService) ExportEvents(ctx context.Context, fn func(*domain.Event) error) error {
+	if err := s.repo.Stream(ctx, fn); err != nil {
+		s.logger.Error().Err(err).Msg("failed to export events")
+		return fmt.Errorf("failed to export events: %w", err)
+	}
+
+	return nil
+}
+
+// ImportEvents creates events, with full ticket availability, in a single transaction
+// Events whose ID already exists are skipped so a partially applied import can be rerun
+func (s *EventService) ImportEvents(ctx context.Context, events []*domain.Event) (created, skipped int, err error) {
+	err = WithTransaction(ctx, s.db, s.logger, nil, "import_events", func(tx domain.Transaction) error {
+		created, skipped = 0, 0
+
+		for _, event := range events {
+			_, err := s.repo.FindByIDWithLock(ctx, tx, event.ID)
+			if err == nil {
+				skipped++
+				continue
+			}
+			if !errors.Is(err, domain.ErrEventNotFound) {
+				return fmt.Errorf("failed to check event %s: %w", event.ID, err)
+			}
+
+			ticketAvailability, err := domain.NewTicketAvailability(event.ID, event.Tickets)
+			if err != nil {
+				return fmt.Errorf("invalid ticket availability data for event %s: %w", event.ID, err)
+			}
+			if err := s.repo.CreateWithExecutor(ctx, tx, event); err != nil {
+				return fmt.Errorf("failed to create event %s: %w", event.ID, err)
+			}
+			if err := s.ticketAvailabilityRepo.CreateWithExecutor(ctx, tx, ticketAvailability); err != nil {
+				return fmt.Errorf("failed to create ticket availability for event %s: %w", event.ID, err)
+			}
+			created++
+		}
+
+		return nil
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Int("events", len(events)).Msg("failed to import events")
+		return 0, 0, err
+	}
+
+	s.logger.Info().Int("created", created).Int("skipped", skipped).Msg("events imported")
+	return created, skipped, nil
+}
+
 func (s *EventService) ListEvents(ctx context.Context) ([]*domain.Event, error) {
-	events, err := s.repo.FindAll(ctx)
+	events, skipped, err := s.repo.FindAll(ctx)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("failed to list events")
 		return nil, fmt.Errorf("failed to list events: %w", err)
 	}
+	if skipped > 0 {
+		s.logger.Warn().Int("skipped", skipped).Int("count", len(events)).Msg("events listed without malformed rows")
+	}
 
 	s.logger.Debug().Int("count", len(events)).Msg("events listed")
 	return events, nil
 }
+
+// EventsLastModified returns when the events catalog last changed, or zero when it is empty
+func (s *EventService) EventsLastModified(ctx context.Context) (time.Time, error) {
+	lastModified, err := s.repo.LastModified(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to read events last modified")
+		return time.Time{}, fmt.Errorf("failed to read events last modified: %w", err)
+	}
+
+	return lastModified, nil
+}
+
+// EventsAvailability is the available tickets of several events, and when the newest of them changed
+type EventsAvailability struct {
+	Available   map[uuid.UUID]int
+	LastChanged time.Time
+}
+
+// GetEventsAvailability reads the available tickets of the events, e.g. of one listing page
+// Events without availability are left out of Available
+func (s *EventService) GetEventsAvailability(ctx context.Context, eventIDs []uuid.UUID) (*EventsAvailability, error) {
+	result := &EventsAvailability{Available: make(map[uuid.UUID]int, len(eventIDs))}
+	if len(eventIDs) == 0 {
+		return result, nil
+	}
+
+	availabilities, err := s.ticketAvailabilityRepo.FindByEventIDs(ctx, eventIDs)
+	if err != nil {
+		s.logger.Error().Err(err).Int("events", len(eventIDs)).Msg("failed to find ticket availability")
+		return nil, fmt.Errorf("failed to find ticket availability: %w", err)
+	}
+	for _, availability := range availabilities {
+		result.Available[availability.EventID] = availability.AvailableTickets
+	}
+
+	result.LastChanged, err = s.ticketAvailabilityRepo.LastChangedAt(ctx, eventIDs)
+	if err != nil {
+		s.logger.Error().Err(err).Int("events", len(eventIDs)).Msg("failed to read availability last changed")
+		return nil, fmt.Errorf("failed to read availability last changed: %w", err)
+	}
+
+	return result, nil
+}
+
+// EventPage is one page of the keyset-paginated events listing
+type EventPage struct {
+	Events     []*domain.Event
+	TotalCount int
+	Next       *domain.EventCursor // nil on the last page
+}
+
+// ListEventsPage returns up to limit events matching filter ordered by date, starting after the cursor when it is set
+func (s *EventService) ListEventsPage(ctx context.Context, filter domain.EventFilter, after *domain.EventCursor, limit int) (*EventPage, error) {
+	// One extra row tells whether another page follows without a second query
+	events, err := s.repo.FindPage(ctx, filter, after, limit+1)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to list events page")
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	total, err := s.repo.Count(ctx, filter)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to count events")
+		return nil, fmt.Errorf("failed to count events: %w", err)
+	}
+
+	page := &EventPage{Events: events, TotalCount: total}
+	if len(events) > limit {
+		page.Events = events[:limit]
+		next := page.Events[limit-1].Cursor()
+		page.Next = &next
+	}
+
+	return page, nil
+}
+
+// CountEvents counts the events matching filter, as totalled by the events listing
+func (s *EventService) CountEvents(ctx context.Context, filter domain.EventFilter) (int, error) {
+	total, err := s.repo.Count(ctx, filter)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to count events")
+		return 0, fmt.Errorf("failed to count events: %w", err)
+	}
+
+	return total, nil
+}
+
+// ListUpcomingEvents returns active events that have not started yet, soonest first, with their total count
+func (s *EventService) ListUpcomingEvents(ctx context.Context, onlyAvailable bool, limit, offset int) ([]*domain.Event, int, error) {
+	now := s.clock.Now()
+
+	events, err := s.repo.FindUpcoming(ctx, now, onlyAvailable, limit, offset)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to list upcoming events")
+		return nil, 0, fmt.Errorf("failed to list upcoming events: %w", err)
+	}
+
+	total, err := s.repo.CountUpcoming(ctx, now, onlyAvailable)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to count upcoming events")
+		return nil, 0, fmt.Errorf("failed to count upcoming events: %w", err)
+	}
+
+	return events, total, nil
+}
+
+// ListEventsForUser returns a page of the events the user has tickets for, each once and ordered by date,
+// and how many there are in total; events the user only has cancelled bookings for are left out
+func (s *EventService) ListEventsForUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.Event, int, error) {
+	events, err := s.repo.FindBookedByUser(ctx, userID, limit, offset)
+	if err != nil {
+		s.logger.Error().Err(err).Str("user_id", userID.String()).Msg("failed to list user events")
+		return nil, 0, fmt.Errorf("failed to list user events: %w", err)
+	}
+
+	total, err := s.repo.CountBookedByUser(ctx, userID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("user_id", userID.String()).Msg("failed to count user events")
+		return nil, 0, fmt.Errorf("failed to count user events: %w", err)
+	}
+
+	return events, total, nil
+}
+
+// GetUserTicketSummary totals the tickets the user holds across all events, excluding cancelled bookings
+func (s *EventService) GetUserTicketSummary(ctx context.Context, userID uuid.UUID) (*domain.UserTicketSummary, error) {
+	perEvent, err := s.repo.SumTicketsByUser(ctx, userID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("user_id", userID.String()).Msg("failed to sum user tickets")
+		return nil, fmt.Errorf("failed to get user ticket summary: %w", err)
+	}
+
+	summary := &domain.UserTicketSummary{PerEvent: perEvent}
+	for _, tickets := range perEvent {
+		summary.Total += tickets.Tickets
+	}
+	return summary, nil
+}
+
+// GetOrganizerDashboard returns a page of the organizer's event summaries and the organizer's event count
+func (s *EventService) GetOrganizerDashboard(ctx context.Context, organizerID uuid.UUID, limit, offset int) ([]*domain.EventBookingSummary, int, error) {
+	summaries, err := s.repo.FindSummariesByOrganizer(ctx, organizerID, limit, offset)
+	if err != nil {
+		s.logger.Error().Err(err).Str("organizer_id", organizerID.String()).Msg("failed to load organizer dashboard")
+		return nil, 0, fmt.Errorf("failed to get organizer dashboard: %w", err)
+	}
+
+	total, err := s.repo.CountByOrganizer(ctx, organizerID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("organizer_id", organizerID.String()).Msg("failed to count organizer events")
+		return nil, 0, fmt.Errorf("failed to count organizer events: %w", err)
+	}
+
+	s.logger.Debug().
+		Str("organizer_id", organizerID.String()).
+		Int("count", len(summaries)).
+		Msg("organizer dashboard loaded")
+	return summaries, total, nil
+}
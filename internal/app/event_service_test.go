@@ -0,0 +1,387 @@
+package app
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEventRepository struct {
+	domain.EventRepository
+	events map[uuid.UUID]*domain.Event
+	err    error
+	calls  int
+}
+
+func (r *fakeEventRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Event, error) {
+	r.calls++
+	if r.err != nil {
+		return nil, r.err
+	}
+	event, ok := r.events[id]
+	if !ok {
+		return nil, domain.ErrEventNotFound
+	}
+	return event, nil
+}
+
+func (r *fakeEventRepository) FindByIDForShare(ctx context.Context, exec domain.Executor, id uuid.UUID) (*domain.Event, error) {
+	return r.FindByID(ctx, id)
+}
+
+func (r *fakeEventRepository) FindByNameAndDate(ctx context.Context, name string, date time.Time) (*domain.Event, error) {
+	r.calls++
+	if r.err != nil {
+		return nil, r.err
+	}
+	y, m, d := date.UTC().Date()
+	for _, event := range r.events {
+		ey, em, ed := event.Date.UTC().Date()
+		if strings.EqualFold(event.Name, name) && ey == y && em == m && ed == d {
+			return event, nil
+		}
+	}
+	return nil, domain.ErrEventNotFound
+}
+
+type fakeTicketAvailabilityRepository struct {
+	domain.TicketAvailabilityRepository
+	availability map[uuid.UUID]*domain.TicketAvailability
+	err          error
+	calls        int
+}
+
+func (r *fakeTicketAvailabilityRepository) FindByEventID(ctx context.Context, eventID uuid.UUID) (*domain.TicketAvailability, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	availability, ok := r.availability[eventID]
+	if !ok {
+		return nil, domain.ErrEventNotFound
+	}
+	return availability, nil
+}
+
+func (r *fakeTicketAvailabilityRepository) FindByEventIDs(ctx context.Context, eventIDs []uuid.UUID) ([]*domain.TicketAvailability, error) {
+	r.calls++
+	if r.err != nil {
+		return nil, r.err
+	}
+	var found []*domain.TicketAvailability
+	for _, id := range eventIDs {
+		if availability, ok := r.availability[id]; ok {
+			found = append(found, availability)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeTicketAvailabilityRepository) FindByEventIDWithLock(ctx context.Context, exec domain.Executor, eventID uuid.UUID) (*domain.TicketAvailability, error) {
+	return r.FindByEventID(ctx, eventID)
+}
+
+// UpdateWithExecutor has nothing to write: availability is shared by pointer with the caller
+func (r *fakeTicketAvailabilityRepository) UpdateWithExecutor(ctx context.Context, exec domain.Executor, availability *domain.TicketAvailability, reason domain.AvailabilityChangeReason) error {
+	return r.err
+}
+
+func TestEventService_FindSameDayDuplicate(t *testing.T) {
+	day := time.Now().Add(30 * 24 * time.Hour).UTC().Truncate(24 * time.Hour).Add(19 * time.Hour)
+	existing, err := domain.NewEvent("Jazz Night", "Blue Room", day, 80)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		enabled bool
+		newName string
+		newDate time.Time
+		repoErr error
+		want    *domain.Event
+	}{
+		{
+			name:    "warns about same name on the same day",
+			enabled: true,
+			newName: "jazz night",
+			newDate: day.Add(2 * time.Hour),
+			want:    existing,
+		},
+		{
+			name:    "no warning for same name on another day",
+			enabled: true,
+			newName: "Jazz Night",
+			newDate: day.Add(24 * time.Hour),
+		},
+		{
+			name:    "no warning for a different name on the same day",
+			enabled: true,
+			newName: "Blues Night",
+			newDate: day,
+		},
+		{
+			name:    "no warning when the check is disabled",
+			enabled: false,
+			newName: "Jazz Night",
+			newDate: day,
+		},
+		{
+			name:    "lookup failure does not produce a warning",
+			enabled: true,
+			newName: "Jazz Night",
+			newDate: day,
+			repoErr: errors.New("connection reset"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeEventRepository{events: map[uuid.UUID]*domain.Event{existing.ID: existing}, err: tt.repoErr}
+			service := NewEventService(repo, nil, nil, nil, zerolog.Nop(), WithDuplicateNameCheck(tt.enabled))
+
+			got := service.FindSameDayDuplicate(context.Background(), tt.newName, tt.newDate)
+
+			assert.Equal(t, tt.want, got)
+			if !tt.enabled {
+				assert.Equal(t, 0, repo.calls)
+			}
+		})
+	}
+}
+
+func TestEventService_GetEventAllowStale(t *testing.T) {
+	event, err := domain.NewEvent("Harvest Festival", "Old Town", time.Now().Add(10*24*time.Hour), 400)
+	require.NoError(t, err)
+
+	connectionRefused := fmt.Errorf("failed to find event: %w", driver.ErrBadConn)
+
+	tests := []struct {
+		name          string
+		primaryErr    error
+		withReplica   bool
+		wantErr       error
+		wantStale     bool
+		wantReplicaOK bool
+	}{
+		{
+			name:        "reads from primary when it is healthy",
+			withReplica: true,
+			wantStale:   false,
+		},
+		{
+			name:          "falls back to replica when primary is unreachable",
+			primaryErr:    connectionRefused,
+			withReplica:   true,
+			wantStale:     true,
+			wantReplicaOK: true,
+		},
+		{
+			name:        "fails when primary is unreachable and no replica is configured",
+			primaryErr:  connectionRefused,
+			withReplica: false,
+			wantErr:     driver.ErrBadConn,
+		},
+		{
+			name:        "does not fall back on non-connection errors",
+			primaryErr:  domain.ErrEventNotFound,
+			withReplica: true,
+			wantErr:     domain.ErrEventNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &fakeEventRepository{events: map[uuid.UUID]*domain.Event{event.ID: event}, err: tt.primaryErr}
+			replica := &fakeEventRepository{events: map[uuid.UUID]*domain.Event{event.ID: event}}
+
+			var opts []EventServiceOption
+			if tt.withReplica {
+				opts = append(opts, WithReadReplica(replica))
+			}
+			service := NewEventService(primary, nil, nil, nil, zerolog.Nop(), opts...)
+
+			got, stale, err := service.GetEventAllowStale(context.Background(), event.ID)
+
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr))
+				assert.Equal(t, 0, replica.calls)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, event.ID, got.ID)
+			assert.Equal(t, tt.wantStale, stale)
+			if tt.wantReplicaOK {
+				assert.Equal(t, 1, replica.calls)
+			} else {
+				assert.Equal(t, 0, replica.calls)
+			}
+		})
+	}
+}
+
+func TestEventService_GetEventWithStats(t *testing.T) {
+	event, err := domain.NewEvent("Harvest Festival", "Old Town", time.Now().Add(10*24*time.Hour), 400)
+	require.NoError(t, err)
+	events := map[uuid.UUID]*domain.Event{event.ID: event}
+	availability := map[uuid.UUID]*domain.TicketAvailability{event.ID: {EventID: event.ID, AvailableTickets: 300}}
+
+	t.Run("returns the event with its stats", func(t *testing.T) {
+		service := NewEventService(&fakeEventRepository{events: events}, &fakeTicketAvailabilityRepository{availability: availability}, nil, nil, zerolog.Nop())
+
+		got, err := service.GetEventWithStats(context.Background(), event.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, event.ID, got.Event.ID)
+		require.NotNil(t, got.Stats)
+		assert.Equal(t, 300, got.Stats.Available)
+		assert.Equal(t, domain.Utilization{Sold: 100, Total: 400, Percent: 25}, got.Stats.Utilization)
+		assert.Empty(t, got.Warnings)
+	})
+
+	t.Run("returns the event without stats when the stats query fails", func(t *testing.T) {
+		statsErr := fmt.Errorf("failed to find ticket availability: %w", driver.ErrBadConn)
+		service := NewEventService(&fakeEventRepository{events: events}, &fakeTicketAvailabilityRepository{err: statsErr}, nil, nil, zerolog.Nop())
+
+		got, err := service.GetEventWithStats(context.Background(), event.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, event.ID, got.Event.ID)
+		assert.Nil(t, got.Stats)
+		assert.Equal(t, []string{"stats unavailable"}, got.Warnings)
+	})
+
+	t.Run("fails when the event cannot be read", func(t *testing.T) {
+		service := NewEventService(&fakeEventRepository{err: driver.ErrBadConn}, &fakeTicketAvailabilityRepository{availability: availability}, nil, nil, zerolog.Nop())
+
+		got, err := service.GetEventWithStats(context.Background(), event.ID)
+
+		assert.ErrorIs(t, err, driver.ErrBadConn)
+		assert.Nil(t, got)
+	})
+}
+
+func TestEventService_CreateEvent_RejectsPastDate(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	service := NewEventService(&fakeEventRepository{}, nil, nil, nil, zerolog.Nop(), WithEventClock(domain.NewClock(func() time.Time { return now }, domain.DefaultClockSkew)))
+
+	_, err := service.CreateEvent(context.Background(), CreateEventRequest{
+		Name:     "Last Year's Gala",
+		Date:     now.Add(-24 * time.Hour),
+		Location: "Opera House",
+		Tickets:  50,
+	})
+
+	assert.ErrorIs(t, err, domain.ErrEventDateInPast)
+}
+
+func TestEventService_EndDate(t *testing.T) {
+	date := time.Date(2030, 6, 1, 19, 0, 0, 0, time.UTC)
+	end := date.Add(3 * time.Hour)
+
+	tests := []struct {
+		name            string
+		defaultDuration time.Duration
+		req             CreateEventRequest
+		want            time.Time
+		wantErr         error
+	}{
+		{name: "explicit end", req: CreateEventRequest{Date: date, EndDate: end}, want: end},
+		{name: "duration", req: CreateEventRequest{Date: date, Duration: 2 * time.Hour}, want: date.Add(2 * time.Hour)},
+		{name: "default duration", defaultDuration: time.Hour, req: CreateEventRequest{Date: date}, want: date.Add(time.Hour)},
+		{name: "explicit end overrides default", defaultDuration: time.Hour, req: CreateEventRequest{Date: date, EndDate: end}, want: end},
+		{name: "unknown end", req: CreateEventRequest{Date: date}},
+		{name: "both end and duration", req: CreateEventRequest{Date: date, EndDate: end, Duration: time.Hour}, wantErr: domain.ErrEndDateAndDuration},
+		{name: "negative duration", req: CreateEventRequest{Date: date, Duration: -time.Hour}, wantErr: domain.ErrInvalidEndDate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewEventService(&fakeEventRepository{}, nil, nil, nil, zerolog.Nop(), WithDefaultEventDuration(tt.defaultDuration))
+
+			got, err := service.endDate(tt.req)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEventService_CreateEvent_RejectsEndBeforeStart(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	service := NewEventService(&fakeEventRepository{}, nil, nil, nil, zerolog.Nop(), WithEventClock(domain.NewClock(func() time.Time { return now }, domain.DefaultClockSkew)))
+
+	_, err := service.CreateEvent(context.Background(), CreateEventRequest{
+		Name:     "Backwards Gala",
+		Date:     now.Add(48 * time.Hour),
+		EndDate:  now.Add(24 * time.Hour),
+		Location: "Opera House",
+		Tickets:  50,
+	})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidEndDate)
+}
+
+func TestEventService_CheckAvailability(t *testing.T) {
+	plenty, few, unknown := uuid.New(), uuid.New(), uuid.New()
+	repo := &fakeTicketAvailabilityRepository{availability: map[uuid.UUID]*domain.TicketAvailability{
+		plenty: {EventID: plenty, AvailableTickets: 100},
+		few:    {EventID: few, AvailableTickets: 2},
+	}}
+	service := NewEventService(&fakeEventRepository{}, repo, nil, nil, zerolog.Nop())
+
+	results, err := service.CheckAvailability(context.Background(), []AvailabilityCheck{
+		{EventID: few, Tickets: 3},
+		{EventID: plenty, Tickets: 100},
+		{EventID: unknown, Tickets: 1},
+		{EventID: few, Tickets: 2},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []AvailabilityCheckResult{
+		{EventID: few, Available: false, Remaining: 2},
+		{EventID: plenty, Available: true, Remaining: 100},
+		{EventID: unknown, Available: false, Remaining: 0},
+		{EventID: few, Available: true, Remaining: 2},
+	}, results)
+	assert.Equal(t, 1, repo.calls, "all items are read with one query")
+}
+
+func TestEventService_CheckAvailability_Validation(t *testing.T) {
+	tooMany := make([]AvailabilityCheck, domain.MaxAvailabilityChecks+1)
+	for i := range tooMany {
+		tooMany[i] = AvailabilityCheck{EventID: uuid.New(), Tickets: 1}
+	}
+
+	tests := []struct {
+		name   string
+		checks []AvailabilityCheck
+		want   error
+	}{
+		{name: "no items", checks: nil, want: domain.ErrEmptyAvailabilityCheck},
+		{name: "too many items", checks: tooMany, want: domain.ErrTooManyAvailabilityChecks},
+		{name: "zero tickets", checks: []AvailabilityCheck{{EventID: uuid.New(), Tickets: 0}}, want: domain.ErrInvalidTickets},
+		{name: "too many tickets", checks: []AvailabilityCheck{{EventID: uuid.New(), Tickets: domain.MaxTickets + 1}}, want: domain.ErrTicketsTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeTicketAvailabilityRepository{}
+			service := NewEventService(&fakeEventRepository{}, repo, nil, nil, zerolog.Nop())
+
+			_, err := service.CheckAvailability(context.Background(), tt.checks)
+
+			assert.ErrorIs(t, err, tt.want)
+			assert.Zero(t, repo.calls)
+		})
+	}
+}
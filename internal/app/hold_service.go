@@ -0,0 +1,495 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/rs/zerolog"
+)
+
+// DefaultHoldTTL is how long a hold keeps its tickets before it must be confirmed
+const DefaultHoldTTL = 10 * time.Minute
+
+type HoldServiceOption func(*HoldService)
+
+// WithHoldExpiryNotifier tells users, through notifier, when their hold expires within lead
+// Without it NotifyExpiringHolds does nothing
+func WithHoldExpiryNotifier(notifier domain.Notifier, lead time.Duration) HoldServiceOption {
+	return func(s *HoldService) {
+		s.notifier = notifier
+		s.expiryNoticeLead = lead
+	}
+}
+
+// WithHoldDeposits makes holds on paid events authorize depositPercent of their total through payments
+// Confirming the hold captures the deposit and the authorized balance; a hold that expires has its deposit
+// voided. Without it, and for holds created before it was set, holds take no payment
+func WithHoldDeposits(payments domain.PaymentGateway, depositPercent int) HoldServiceOption {
+	return func(s *HoldService) {
+		s.payments = payments
+		s.depositPercent = depositPercent
+	}
+}
+
+// WithHoldClock overrides the time source used for hold expiry and the event's booking rules
+func WithHoldClock(clock domain.Clock) HoldServiceOption {
+	return func(s *HoldService) {
+		s.clock = clock
+	}
+}
+
+// WithHoldIDGenerator overrides how hold and booking IDs are generated
+func WithHoldIDGenerator(gen domain.IDGenerator) HoldServiceOption {
+	return func(s *HoldService) {
+		s.idGenerator = gen
+	}
+}
+
+type HoldService struct {
+	holdRepo               domain.HoldRepository
+	eventRepo              domain.EventRepository
+	ticketAvailabilityRepo domain.TicketAvailabilityRepository
+	bookingRepo            domain.BookingRepository
+	db                     infrastructure.DBClient
+	logger                 zerolog.Logger
+	ttl                    time.Duration
+	notifier               domain.Notifier
+	expiryNoticeLead       time.Duration
+	payments               domain.PaymentGateway
+	depositPercent         int
+	clock                  domain.Clock
+	idGenerator            domain.IDGenerator
+}
+
+func NewHoldService(
+	holdRepo domain.HoldRepository,
+	eventRepo domain.EventRepository,
+	ticketAvailabilityRepo domain.TicketAvailabilityRepository,
+	bookingRepo domain.BookingRepository,
+	db infrastructure.DBClient,
+	logger zerolog.Logger,
+	ttl time.Duration,
+	opts ...HoldServiceOption,
+) *HoldService {
+	s := &HoldService{
+		holdRepo:               holdRepo,
+		eventRepo:              eventRepo,
+		ticketAvailabilityRepo: ticketAvailabilityRepo,
+		bookingRepo:            bookingRepo,
+		db:                     db,
+		logger:                 logger.With().Str("service", "hold").Logger(),
+		ttl:                    ttl,
+		clock:                  domain.SystemClock(),
+		idGenerator:            domain.RandomIDGenerator{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+type CreateHoldRequest struct {
+	EventID uuid.UUID
+	UserID  uuid.UUID
+	Tickets int
+}
+
+// CreateHold takes the tickets from availability and keeps them for the hold's TTL
+func (s *HoldService) CreateHold(ctx context.Context, req CreateHoldRequest) (*domain.Hold, error) {
+	now := s.clock.Now()
+
+	event, err := s.eventRepo.FindByID(ctx, req.EventID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("event_id", req.EventID.String()).Msg("failed to find event")
+		return nil, fmt.Errorf("failed to find event: %w", err)
+	}
+
+	if err := event.CheckBookable(s.clock.RuleTime()); err != nil {
+		s.logger.Warn().Err(err).Str("event_id", req.EventID.String()).Msg("event does not accept holds")
+		return nil, err
+	}
+	if err := event.CheckDirectSale(); err != nil {
+		return nil, err
+	}
+
+	// Holds reserve a ticket count, not specific seats
+	if err := event.CheckSeatSelection(nil); err != nil {
+		return nil, err
+	}
+	if err := event.CheckQuantity(req.Tickets); err != nil {
+		return nil, err
+	}
+
+	hold, err := domain.NewHold(req.EventID, req.UserID, req.Tickets, s.ttl, now, domain.WithHoldIDGenerator(s.idGenerator))
+	if err != nil {
+		return nil, fmt.Errorf("invalid hold data: %w", err)
+	}
+
+	// Authorized before the availability row is locked, so the provider's latency does not hold up bookings
+	if err := s.authorizeDeposit(ctx, event, hold); err != nil {
+		return nil, err
+	}
+
+	soldOut := false
+	txOpts := &sql.TxOptions{Isolation: sql.LevelSerializable}
+	err = WithTransaction(ctx, s.db, s.logger, txOpts, "create_hold", func(tx domain.Transaction) error {
+		// Re-checked under a shared lock, so a pause or cancellation committed since the read above
+		// rejects the hold, and one in progress waits for it to finish
+		current, err := s.eventRepo.FindByIDForShare(ctx, tx, req.EventID)
+		if err != nil {
+			return fmt.Errorf("failed to find event: %w", err)
+		}
+		if err := current.CheckBookable(s.clock.RuleTime()); err != nil {
+			s.logger.Warn().Err(err).Str("event_id", req.EventID.String()).Msg("event stopped accepting holds")
+			return err
+		}
+
+		availability, err := s.ticketAvailabilityRepo.FindByEventIDWithLock(ctx, tx, req.EventID)
+		if err != nil {
+			return fmt.Errorf("failed to find ticket availability: %w", err)
+		}
+
+		if err := availability.ReserveTickets(req.Tickets, current.Tickets); err != nil {
+			recordSoldOutRejection(err, current.Tickets)
+			return err
+		}
+		soldOut = availability.AvailableTickets == 0
+
+		if err := s.ticketAvailabilityRepo.UpdateWithExecutor(ctx, tx, availability, domain.AvailabilityHeld); err != nil {
+			return fmt.Errorf("failed to update ticket availability: %w", err)
+		}
+
+		if err := s.holdRepo.CreateWithExecutor(ctx, tx, hold); err != nil {
+			return fmt.Errorf("failed to create hold: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		s.logger.Warn().Err(err).Str("event_id", req.EventID.String()).Int("tickets", req.Tickets).Msg("failed to create hold")
+		s.voidPayment(ctx, hold.PaymentID, hold.ID)
+		return nil, err
+	}
+	// A hold takes the tickets off sale, so taking the last ones is a sellout even if it later expires
+	if soldOut {
+		recordSellout(s.logger, event, now)
+	}
+
+	s.logger.Info().
+		Str("hold_id", hold.ID.String()).
+		Str("event_id", hold.EventID.String()).
+		Int("tickets", hold.Tickets).
+		Time("expires_at", hold.ExpiresAt).
+		Int64("deposit_cents", hold.DepositCents).
+		Msg("hold created")
+
+	return hold, nil
+}
+
+// authorizeDeposit authorizes the hold's deposit on a paid event and records it on the hold
+func (s *HoldService) authorizeDeposit(ctx context.Context, event *domain.Event, hold *domain.Hold) error {
+	if s.payments == nil {
+		return nil
+	}
+
+	quote, err := event.Quote(hold.Tickets)
+	if err != nil {
+		return err
+	}
+	deposit := domain.HoldDepositCents(quote.TotalCents, s.depositPercent)
+	if deposit == 0 {
+		return nil
+	}
+
+	paymentID, err := s.payments.Authorize(ctx, hold.UserID, deposit, quote.Currency)
+	if err != nil {
+		s.logger.Warn().Err(err).Str("event_id", event.ID.String()).Int64("deposit_cents", deposit).Msg("hold deposit not authorized")
+		return fmt.Errorf("failed to authorize hold deposit: %w", err)
+	}
+
+	hold.DepositCents = deposit
+	hold.PaymentID = paymentID
+	return nil
+}
+
+// voidPayment releases an authorization that will not be captured
+// Failures are logged with the transaction ID rather than returned: the authorization lapses at the
+// provider on its own, and the operation that made it unnecessary has already been decided
+func (s *HoldService) voidPayment(ctx context.Context, paymentID string, holdID uuid.UUID) {
+	if s.payments == nil || paymentID == "" {
+		return
+	}
+	if err := s.payments.Void(ctx, paymentID); err != nil {
+		s.logger.Error().Err(err).Str("hold_id", holdID.String()).Str("payment_id", paymentID).Msg("failed to void payment authorization")
+	}
+}
+
+func (s *HoldService) GetHold(ctx context.Context, id uuid.UUID) (*domain.Hold, error) {
+	hold, err := s.holdRepo.FindByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Str("hold_id", id.String()).Msg("failed to find hold")
+		return nil, fmt.Errorf("failed to get hold: %w", err)
+	}
+
+	return hold, nil
+}
+
+// ConfirmHold turns an active hold into a booking
+// Confirming an expired hold releases its tickets, if the sweeper has not yet, and returns ErrHoldExpired
+func (s *HoldService) ConfirmHold(ctx context.Context, id uuid.UUID) (*domain.Booking, error) {
+	return s.confirmHold(ctx, id, uuid.Nil)
+}
+
+// ConfirmHoldForUser confirms the hold like ConfirmHold, but only for the user who placed it
+// Another user's hold is reported as ErrHoldNotFound, so its existence is not revealed
+func (s *HoldService) ConfirmHoldForUser(ctx context.Context, id, userID uuid.UUID) (*domain.Booking, error) {
+	return s.confirmHold(ctx, id, userID)
+}
+
+// confirmHold confirms the hold, checking it belongs to userID unless userID is uuid.Nil
+func (s *HoldService) confirmHold(ctx context.Context, id, userID uuid.UUID) (*domain.Booking, error) {
+	var booking *domain.Booking
+	var hold *domain.Hold
+	var balanceID string
+	expired := false
+	released := false
+
+	err := WithTransaction(ctx, s.db, s.logger, nil, "confirm_hold", func(tx domain.Transaction) error {
+		now := s.clock.Now()
+
+		var err error
+		hold, err = s.holdRepo.FindByIDWithLock(ctx, tx, id)
+		if err != nil {
+			return fmt.Errorf("failed to find hold: %w", err)
+		}
+		// Checked first, so another user can neither confirm the hold nor release it by trying after expiry
+		if userID != uuid.Nil && hold.UserID != userID {
+			return domain.ErrHoldNotFound
+		}
+
+		if hold.IsExpired(now) {
+			expired = true
+			if hold.Status == domain.HoldStatusActive {
+				released = true
+				return s.releaseHold(ctx, tx, hold)
+			}
+			return nil
+		}
+
+		event, err := s.eventRepo.FindByID(ctx, hold.EventID)
+		if err != nil {
+			return fmt.Errorf("failed to find event: %w", err)
+		}
+		quote, err := event.Quote(hold.Tickets)
+		if err != nil {
+			return err
+		}
+
+		booking, err = domain.NewBooking(hold.EventID, hold.UserID, hold.Tickets,
+			domain.WithBookingPrice(quote.TotalCents, quote.Currency), domain.WithBookingIDGenerator(s.idGenerator))
+		if err != nil {
+			return fmt.Errorf("invalid booking data: %w", err)
+		}
+
+		if err := hold.Confirm(booking.ID, now); err != nil {
+			return err
+		}
+
+		// The balance is authorized under the hold's lock, so a concurrent confirmation cannot authorize it twice
+		balanceID, err = s.authorizeBalance(ctx, hold, quote)
+		if err != nil {
+			return err
+		}
+
+		if err := s.bookingRepo.CreateWithExecutor(ctx, tx, booking); err != nil {
+			return fmt.Errorf("failed to create booking: %w", err)
+		}
+
+		if err := s.holdRepo.UpdateWithExecutor(ctx, tx, hold); err != nil {
+			return fmt.Errorf("failed to update hold: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		s.logger.Warn().Err(err).Str("hold_id", id.String()).Msg("failed to confirm hold")
+		s.voidPayment(ctx, balanceID, id)
+		return nil, err
+	}
+
+	// The release above must commit, so expiry is reported only after the transaction
+	if expired {
+		if released {
+			s.voidPayment(ctx, hold.PaymentID, hold.ID)
+		}
+		s.logger.Info().Str("hold_id", id.String()).Msg("confirmation rejected, hold expired")
+		return nil, domain.ErrHoldExpired
+	}
+
+	// Captured only once the booking is committed, so money is never taken for a booking that does not exist
+	s.capturePayment(ctx, hold.PaymentID, booking.ID)
+	s.capturePayment(ctx, balanceID, booking.ID)
+
+	s.logger.Info().
+		Str("hold_id", id.String()).
+		Str("booking_id", booking.ID.String()).
+		Msg("hold confirmed")
+
+	return booking, nil
+}
+
+// authorizeBalance authorizes what is left of the quote after the hold's deposit
+// Holds that took no deposit are confirmed without payment, as are holds whose deposit covers the total
+func (s *HoldService) authorizeBalance(ctx context.Context, hold *domain.Hold, quote domain.Quote) (string, error) {
+	balance := quote.TotalCents - hold.DepositCents
+	if s.payments == nil || hold.PaymentID == "" || balance <= 0 {
+		return "", nil
+	}
+
+	balanceID, err := s.payments.Authorize(ctx, hold.UserID, balance, quote.Currency)
+	if err != nil {
+		s.logger.Warn().Err(err).Str("hold_id", hold.ID.String()).Int64("balance_cents", balance).Msg("hold balance not authorized")
+		return "", fmt.Errorf("failed to authorize hold balance: %w", err)
+	}
+	return balanceID, nil
+}
+
+// capturePayment collects an authorization for a committed booking
+// A failed capture does not undo the booking; it is logged with the transaction ID so it can be retried
+func (s *HoldService) capturePayment(ctx context.Context, paymentID string, bookingID uuid.UUID) {
+	if s.payments == nil || paymentID == "" {
+		return
+	}
+	if err := s.payments.Capture(ctx, paymentID); err != nil {
+		s.logger.Error().Err(err).Str("booking_id", bookingID.String()).Str("payment_id", paymentID).Msg("failed to capture payment")
+	}
+}
+
+// ReleaseExpiredHolds returns the tickets of up to limit expired holds to availability
+func (s *HoldService) ReleaseExpiredHolds(ctx context.Context, limit int) (int, error) {
+	var released []*domain.Hold
+
+	err := WithTransaction(ctx, s.db, s.logger, nil, "release_expired_holds", func(tx domain.Transaction) error {
+		released = nil
+
+		holds, err := s.holdRepo.FindExpiredWithLock(ctx, tx, s.clock.Now(), limit)
+		if err != nil {
+			return fmt.Errorf("failed to find expired holds: %w", err)
+		}
+
+		for _, hold := range holds {
+			if err := s.releaseHold(ctx, tx, hold); err != nil {
+				return err
+			}
+			released = append(released, hold)
+		}
+
+		return nil
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to release expired holds")
+		return 0, err
+	}
+
+	// Voided after the release commits, so a rolled back release keeps its deposit
+	for _, hold := range released {
+		s.voidPayment(ctx, hold.PaymentID, hold.ID)
+	}
+
+	if len(released) > 0 {
+		s.logger.Info().Int("released", len(released)).Msg("expired holds released")
+	}
+
+	return len(released), nil
+}
+
+// NotifyExpiringHolds tells the users of up to limit holds expiring within the notice lead time
+// Holds are marked before the notices go out, so a notice is sent at most once even if delivery fails
+func (s *HoldService) NotifyExpiringHolds(ctx context.Context, limit int) (int, error) {
+	if s.notifier == nil || s.expiryNoticeLead <= 0 {
+		return 0, nil
+	}
+
+	var holds []*domain.Hold
+	err := WithTransaction(ctx, s.db, s.logger, nil, "mark_expiring_holds", func(tx domain.Transaction) error {
+		now := s.clock.Now()
+
+		var err error
+		holds, err = s.holdRepo.FindExpiringWithLock(ctx, tx, now, s.expiryNoticeLead, limit)
+		if err != nil {
+			return fmt.Errorf("failed to find expiring holds: %w", err)
+		}
+
+		for _, hold := range holds {
+			if err := hold.MarkExpiryNotified(now); err != nil {
+				return err
+			}
+			if err := s.holdRepo.UpdateWithExecutor(ctx, tx, hold); err != nil {
+				return fmt.Errorf("failed to update hold: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to mark expiring holds")
+		return 0, err
+	}
+
+	notified := 0
+	for _, hold := range holds {
+		err := s.notifier.Notify(ctx, domain.Notification{
+			Kind:    domain.NotificationHoldExpiring,
+			UserID:  hold.UserID,
+			Message: "hold expiring soon",
+			Attributes: map[string]string{
+				"hold_id":    hold.ID.String(),
+				"event_id":   hold.EventID.String(),
+				"expires_at": hold.ExpiresAt.UTC().Format(time.RFC3339),
+			},
+		})
+		if err != nil {
+			s.logger.Warn().Err(err).Str("hold_id", hold.ID.String()).Msg("failed to send hold expiry notice")
+			continue
+		}
+		notified++
+	}
+
+	if notified > 0 {
+		s.logger.Info().Int("notified", notified).Msg("hold expiry notices sent")
+	}
+
+	return notified, nil
+}
+
+// releaseHold marks the hold released and returns its tickets within tx
+func (s *HoldService) releaseHold(ctx context.Context, tx domain.Transaction, hold *domain.Hold) error {
+	if err := hold.Release(); err != nil {
+		return err
+	}
+
+	availability, err := s.ticketAvailabilityRepo.FindByEventIDWithLock(ctx, tx, hold.EventID)
+	if err != nil {
+		return fmt.Errorf("failed to find ticket availability: %w", err)
+	}
+
+	if err := availability.ReleaseTickets(hold.Tickets); err != nil {
+		return err
+	}
+
+	if err := s.ticketAvailabilityRepo.UpdateWithExecutor(ctx, tx, availability, domain.AvailabilityHoldReleased); err != nil {
+		return fmt.Errorf("failed to update ticket availability: %w", err)
+	}
+
+	if err := s.holdRepo.UpdateWithExecutor(ctx, tx, hold); err != nil {
+		return fmt.Errorf("failed to update hold: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,393 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeHoldRepository keeps holds in memory and filters them the way the Postgres queries do
+type fakeHoldRepository struct {
+	domain.HoldRepository
+	holds map[uuid.UUID]*domain.Hold
+}
+
+func (r *fakeHoldRepository) FindExpiringWithLock(ctx context.Context, exec domain.Executor, now time.Time, lead time.Duration, limit int) ([]*domain.Hold, error) {
+	var holds []*domain.Hold
+	for _, hold := range r.holds {
+		if len(holds) < limit && hold.NeedsExpiryNotice(now, lead) {
+			copied := *hold
+			holds = append(holds, &copied)
+		}
+	}
+	return holds, nil
+}
+
+func (r *fakeHoldRepository) UpdateWithExecutor(ctx context.Context, exec domain.Executor, hold *domain.Hold) error {
+	copied := *hold
+	r.holds[hold.ID] = &copied
+	return nil
+}
+
+func (r *fakeHoldRepository) CreateWithExecutor(ctx context.Context, exec domain.Executor, hold *domain.Hold) error {
+	copied := *hold
+	r.holds[hold.ID] = &copied
+	return nil
+}
+
+func (r *fakeHoldRepository) FindByIDWithLock(ctx context.Context, exec domain.Executor, id uuid.UUID) (*domain.Hold, error) {
+	hold, ok := r.holds[id]
+	if !ok {
+		return nil, domain.ErrHoldNotFound
+	}
+	copied := *hold
+	return &copied, nil
+}
+
+func (r *fakeHoldRepository) FindExpiredWithLock(ctx context.Context, exec domain.Executor, now time.Time, limit int) ([]*domain.Hold, error) {
+	var holds []*domain.Hold
+	for _, hold := range r.holds {
+		if len(holds) < limit && hold.Status == domain.HoldStatusActive && hold.IsExpired(now) {
+			copied := *hold
+			holds = append(holds, &copied)
+		}
+	}
+	return holds, nil
+}
+
+type fakeBookingRepository struct {
+	domain.BookingRepository
+	bookings []*domain.Booking
+}
+
+func (r *fakeBookingRepository) CreateWithExecutor(ctx context.Context, exec domain.Executor, booking *domain.Booking) error {
+	r.bookings = append(r.bookings, booking)
+	return nil
+}
+
+// fakePaymentGateway approves every authorization unless authorizeErr is set and records what it was asked to do
+type fakePaymentGateway struct {
+	authorized   map[string]int64
+	captured     []string
+	voided       []string
+	authorizeErr error
+}
+
+func (g *fakePaymentGateway) Authorize(ctx context.Context, userID uuid.UUID, amountCents int64, currency string) (string, error) {
+	if g.authorizeErr != nil {
+		return "", g.authorizeErr
+	}
+	if g.authorized == nil {
+		g.authorized = map[string]int64{}
+	}
+	txnID := fmt.Sprintf("txn-%d", len(g.authorized)+1)
+	g.authorized[txnID] = amountCents
+	return txnID, nil
+}
+
+func (g *fakePaymentGateway) Capture(ctx context.Context, txnID string) error {
+	g.captured = append(g.captured, txnID)
+	return nil
+}
+
+func (g *fakePaymentGateway) Void(ctx context.Context, txnID string) error {
+	g.voided = append(g.voided, txnID)
+	return nil
+}
+
+type fakeNotifier struct {
+	notifications []domain.Notification
+	err           error
+}
+
+func (n *fakeNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.notifications = append(n.notifications, notification)
+	return nil
+}
+
+func TestHoldService_NotifyExpiringHolds(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	const ttl = 10 * time.Minute
+	const lead = 2 * time.Minute
+
+	newService := func(t *testing.T, notifier domain.Notifier, clock *time.Time) (*HoldService, *domain.Hold) {
+		hold, err := domain.NewHold(uuid.New(), uuid.New(), 2, ttl, start)
+		require.NoError(t, err)
+
+		repo := &fakeHoldRepository{holds: map[uuid.UUID]*domain.Hold{hold.ID: hold}}
+		service := NewHoldService(repo, nil, nil, nil, &fakeDB{}, zerolog.Nop(), ttl,
+			WithHoldExpiryNotifier(notifier, lead),
+			WithHoldClock(domain.NewClock(func() time.Time { return *clock }, 0)))
+		return service, hold
+	}
+
+	t.Run("notifies once when the clock reaches the notice window", func(t *testing.T) {
+		clock := start
+		notifier := &fakeNotifier{}
+		service, hold := newService(t, notifier, &clock)
+
+		notified, err := service.NotifyExpiringHolds(context.Background(), 10)
+		require.NoError(t, err)
+		assert.Zero(t, notified, "nothing is due before the window")
+
+		clock = start.Add(ttl - lead + time.Second)
+		for range 3 {
+			_, err := service.NotifyExpiringHolds(context.Background(), 10)
+			require.NoError(t, err)
+			clock = clock.Add(30 * time.Second)
+		}
+
+		require.Len(t, notifier.notifications, 1)
+		notification := notifier.notifications[0]
+		assert.Equal(t, domain.NotificationHoldExpiring, notification.Kind)
+		assert.Equal(t, hold.UserID, notification.UserID)
+		assert.Equal(t, hold.ID.String(), notification.Attributes["hold_id"])
+	})
+
+	t.Run("failed delivery is not retried", func(t *testing.T) {
+		clock := start.Add(ttl - lead)
+		notifier := &fakeNotifier{err: errors.New("smtp down")}
+		service, _ := newService(t, notifier, &clock)
+
+		notified, err := service.NotifyExpiringHolds(context.Background(), 10)
+		require.NoError(t, err)
+		assert.Zero(t, notified)
+
+		notifier.err = nil
+		notified, err = service.NotifyExpiringHolds(context.Background(), 10)
+		require.NoError(t, err)
+		assert.Zero(t, notified)
+		assert.Empty(t, notifier.notifications)
+	})
+
+	t.Run("disabled without a notifier", func(t *testing.T) {
+		clock := start.Add(ttl - lead)
+		hold, err := domain.NewHold(uuid.New(), uuid.New(), 2, ttl, start)
+		require.NoError(t, err)
+		repo := &fakeHoldRepository{holds: map[uuid.UUID]*domain.Hold{hold.ID: hold}}
+		service := NewHoldService(repo, nil, nil, nil, &fakeDB{}, zerolog.Nop(), ttl,
+			WithHoldClock(domain.NewClock(func() time.Time { return clock }, 0)))
+
+		notified, err := service.NotifyExpiringHolds(context.Background(), 10)
+		require.NoError(t, err)
+		assert.Zero(t, notified)
+		assert.True(t, repo.holds[hold.ID].ExpiryNotifiedAt.IsZero())
+	})
+}
+
+func TestHoldService_Deposits(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	const ttl = 10 * time.Minute
+
+	type fixture struct {
+		service      *HoldService
+		holds        *fakeHoldRepository
+		bookings     *fakeBookingRepository
+		availability *domain.TicketAvailability
+		payments     *fakePaymentGateway
+		event        *domain.Event
+	}
+	newFixture := func(t *testing.T, clock *time.Time) fixture {
+		event, err := domain.NewEvent("Paid Gala", "Opera", start.Add(30*24*time.Hour), 100, domain.WithPrice(2500, "EUR"))
+		require.NoError(t, err)
+		availability := &domain.TicketAvailability{EventID: event.ID, AvailableTickets: 100}
+
+		f := fixture{
+			holds:        &fakeHoldRepository{holds: map[uuid.UUID]*domain.Hold{}},
+			bookings:     &fakeBookingRepository{},
+			availability: availability,
+			payments:     &fakePaymentGateway{},
+			event:        event,
+		}
+		f.service = NewHoldService(f.holds,
+			&fakeEventRepository{events: map[uuid.UUID]*domain.Event{event.ID: event}},
+			&fakeTicketAvailabilityRepository{availability: map[uuid.UUID]*domain.TicketAvailability{event.ID: availability}},
+			f.bookings, &fakeDB{}, zerolog.Nop(), ttl,
+			WithHoldDeposits(f.payments, 20),
+			WithHoldClock(domain.NewClock(func() time.Time { return *clock }, 0)))
+		return f
+	}
+
+	t.Run("confirming captures the deposit and the balance", func(t *testing.T) {
+		clock := start
+		f := newFixture(t, &clock)
+
+		hold, err := f.service.CreateHold(context.Background(), CreateHoldRequest{EventID: f.event.ID, UserID: uuid.New(), Tickets: 3})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1500), hold.DepositCents, "20% of 3 x 25.00")
+		assert.Equal(t, map[string]int64{hold.PaymentID: 1500}, f.payments.authorized)
+		assert.Empty(t, f.payments.captured, "the deposit is only authorized while held")
+
+		clock = start.Add(ttl / 2)
+		booking, err := f.service.ConfirmHold(context.Background(), hold.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7500), booking.PriceCents)
+
+		require.Len(t, f.payments.authorized, 2)
+		var balanceID string
+		for txnID, amount := range f.payments.authorized {
+			if txnID != hold.PaymentID {
+				balanceID = txnID
+				assert.Equal(t, int64(6000), amount, "the balance is the total less the deposit")
+			}
+		}
+		assert.Equal(t, []string{hold.PaymentID, balanceID}, f.payments.captured)
+		assert.Empty(t, f.payments.voided)
+	})
+
+	t.Run("expiry voids the deposit", func(t *testing.T) {
+		clock := start
+		f := newFixture(t, &clock)
+
+		hold, err := f.service.CreateHold(context.Background(), CreateHoldRequest{EventID: f.event.ID, UserID: uuid.New(), Tickets: 2})
+		require.NoError(t, err)
+		require.NotEmpty(t, hold.PaymentID)
+
+		clock = start.Add(ttl)
+		released, err := f.service.ReleaseExpiredHolds(context.Background(), 10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, released)
+
+		assert.Equal(t, []string{hold.PaymentID}, f.payments.voided)
+		assert.Empty(t, f.payments.captured)
+		assert.Equal(t, 100, f.availability.AvailableTickets, "the tickets are back on sale")
+	})
+
+	t.Run("confirming an expired hold voids its deposit", func(t *testing.T) {
+		clock := start
+		f := newFixture(t, &clock)
+
+		hold, err := f.service.CreateHold(context.Background(), CreateHoldRequest{EventID: f.event.ID, UserID: uuid.New(), Tickets: 2})
+		require.NoError(t, err)
+
+		clock = start.Add(ttl + time.Second)
+		_, err = f.service.ConfirmHold(context.Background(), hold.ID)
+		assert.ErrorIs(t, err, domain.ErrHoldExpired)
+
+		assert.Equal(t, []string{hold.PaymentID}, f.payments.voided)
+		assert.Empty(t, f.payments.captured)
+		assert.Empty(t, f.bookings.bookings)
+	})
+
+	t.Run("a declined deposit creates no hold", func(t *testing.T) {
+		clock := start
+		f := newFixture(t, &clock)
+		f.payments.authorizeErr = errors.New("card declined")
+
+		_, err := f.service.CreateHold(context.Background(), CreateHoldRequest{EventID: f.event.ID, UserID: uuid.New(), Tickets: 2})
+		assert.ErrorIs(t, err, f.payments.authorizeErr)
+
+		assert.Empty(t, f.holds.holds)
+		assert.Equal(t, 100, f.availability.AvailableTickets)
+	})
+}
+
+func TestHoldService_ConfirmHoldForUser(t *testing.T) {
+	const ttl = 10 * time.Minute
+	start := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	owner := uuid.New()
+
+	event, err := domain.NewEvent("Jazz Night", "Blue Room", start.Add(30*24*time.Hour), 20)
+	require.NoError(t, err)
+
+	newService := func(clock *time.Time) (*HoldService, *fakeHoldRepository, *fakeBookingRepository, *domain.TicketAvailability) {
+		holds := &fakeHoldRepository{holds: map[uuid.UUID]*domain.Hold{}}
+		bookings := &fakeBookingRepository{}
+		availability := &domain.TicketAvailability{EventID: event.ID, AvailableTickets: 20}
+		service := NewHoldService(holds,
+			&fakeEventRepository{events: map[uuid.UUID]*domain.Event{event.ID: event}},
+			&fakeTicketAvailabilityRepository{availability: map[uuid.UUID]*domain.TicketAvailability{event.ID: availability}},
+			bookings, &fakeDB{}, zerolog.Nop(), ttl,
+			WithHoldClock(domain.NewClock(func() time.Time { return *clock }, 0)))
+		return service, holds, bookings, availability
+	}
+
+	t.Run("confirms the owner's hold", func(t *testing.T) {
+		clock := start
+		service, holds, bookings, _ := newService(&clock)
+		hold, err := service.CreateHold(context.Background(), CreateHoldRequest{EventID: event.ID, UserID: owner, Tickets: 3})
+		require.NoError(t, err)
+
+		booking, err := service.ConfirmHoldForUser(context.Background(), hold.ID, owner)
+		require.NoError(t, err)
+		assert.Equal(t, owner, booking.UserID)
+		assert.Equal(t, 3, booking.TicketsBooked)
+		assert.Len(t, bookings.bookings, 1)
+		assert.Equal(t, domain.HoldStatusConfirmed, holds.holds[hold.ID].Status)
+	})
+
+	t.Run("hides another user's hold", func(t *testing.T) {
+		clock := start
+		service, holds, bookings, _ := newService(&clock)
+		hold, err := service.CreateHold(context.Background(), CreateHoldRequest{EventID: event.ID, UserID: owner, Tickets: 3})
+		require.NoError(t, err)
+
+		_, err = service.ConfirmHoldForUser(context.Background(), hold.ID, uuid.New())
+		assert.ErrorIs(t, err, domain.ErrHoldNotFound)
+		assert.Empty(t, bookings.bookings)
+		assert.Equal(t, domain.HoldStatusActive, holds.holds[hold.ID].Status)
+	})
+
+	t.Run("another user cannot release an expired hold", func(t *testing.T) {
+		clock := start
+		service, holds, _, availability := newService(&clock)
+		hold, err := service.CreateHold(context.Background(), CreateHoldRequest{EventID: event.ID, UserID: owner, Tickets: 3})
+		require.NoError(t, err)
+
+		clock = start.Add(ttl + time.Second)
+		_, err = service.ConfirmHoldForUser(context.Background(), hold.ID, uuid.New())
+		assert.ErrorIs(t, err, domain.ErrHoldNotFound)
+		assert.Equal(t, 17, availability.AvailableTickets)
+		assert.Equal(t, domain.HoldStatusActive, holds.holds[hold.ID].Status)
+
+		_, err = service.ConfirmHoldForUser(context.Background(), hold.ID, owner)
+		assert.ErrorIs(t, err, domain.ErrHoldExpired)
+		assert.Equal(t, 20, availability.AvailableTickets)
+	})
+}
+
+func TestHoldService_UsesIDGenerator(t *testing.T) {
+	event, err := domain.NewEvent("Jazz Night", "Blue Room", time.Now().Add(30*24*time.Hour), 20)
+	require.NoError(t, err)
+	service := NewHoldService(&fakeHoldRepository{holds: map[uuid.UUID]*domain.Hold{}},
+		&fakeEventRepository{events: map[uuid.UUID]*domain.Event{event.ID: event}},
+		&fakeTicketAvailabilityRepository{availability: map[uuid.UUID]*domain.TicketAvailability{event.ID: {EventID: event.ID, AvailableTickets: 20}}},
+		&fakeBookingRepository{}, &fakeDB{}, zerolog.Nop(), DefaultHoldTTL,
+		WithHoldIDGenerator(domain.TimeOrderedIDGenerator{}))
+
+	hold, err := service.CreateHold(context.Background(), CreateHoldRequest{EventID: event.ID, UserID: uuid.New(), Tickets: 2})
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(7), hold.ID.Version())
+
+	booking, err := service.ConfirmHold(context.Background(), hold.ID)
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(7), booking.ID.Version())
+}
+
+func TestHoldService_SoldOutRejections(t *testing.T) {
+	event, err := domain.NewEvent("Jazz Night", "Blue Room", time.Now().Add(30*24*time.Hour), 20)
+	require.NoError(t, err)
+	service := NewHoldService(&fakeHoldRepository{holds: map[uuid.UUID]*domain.Hold{}},
+		&fakeEventRepository{events: map[uuid.UUID]*domain.Event{event.ID: event}},
+		&fakeTicketAvailabilityRepository{availability: map[uuid.UUID]*domain.TicketAvailability{event.ID: {EventID: event.ID, AvailableTickets: 1}}},
+		&fakeBookingRepository{}, &fakeDB{}, zerolog.Nop(), DefaultHoldTTL)
+	small := infrastructure.SoldOutRejections.WithLabelValues("small")
+	before := testutil.ToFloat64(small)
+
+	_, err = service.CreateHold(context.Background(), CreateHoldRequest{EventID: event.ID, UserID: uuid.New(), Tickets: 2})
+	assert.ErrorIs(t, err, domain.ErrInsufficientTickets)
+	assert.Equal(t, before+1, testutil.ToFloat64(small))
+}
@@ -0,0 +1,239 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/rs/zerolog"
+)
+
+type LotteryServiceOption func(*LotteryService)
+
+// WithLotteryClock overrides the time source used for registration times and the event's booking rules
+func WithLotteryClock(clock domain.Clock) LotteryServiceOption {
+	return func(s *LotteryService) {
+		s.clock = clock
+	}
+}
+
+// WithLotteryIDGenerator overrides how entry and booking IDs are generated
+func WithLotteryIDGenerator(gen domain.IDGenerator) LotteryServiceOption {
+	return func(s *LotteryService) {
+		s.idGenerator = gen
+	}
+}
+
+// LotteryService runs lotteries for oversubscribed events: users register, then a draw books the winners
+type LotteryService struct {
+	repo                   domain.LotteryRepository
+	eventRepo              domain.EventRepository
+	ticketAvailabilityRepo domain.TicketAvailabilityRepository
+	bookingRepo            domain.BookingRepository
+	db                     infrastructure.DBClient
+	logger                 zerolog.Logger
+	clock                  domain.Clock
+	idGenerator            domain.IDGenerator
+}
+
+func NewLotteryService(
+	repo domain.LotteryRepository,
+	eventRepo domain.EventRepository,
+	ticketAvailabilityRepo domain.TicketAvailabilityRepository,
+	bookingRepo domain.BookingRepository,
+	db infrastructure.DBClient,
+	logger zerolog.Logger,
+	opts ...LotteryServiceOption,
+) *LotteryService {
+	s := &LotteryService{
+		repo:                   repo,
+		eventRepo:              eventRepo,
+		ticketAvailabilityRepo: ticketAvailabilityRepo,
+		bookingRepo:            bookingRepo,
+		db:                     db,
+		logger:                 logger.With().Str("service", "lottery").Logger(),
+		clock:                  domain.SystemClock(),
+		idGenerator:            domain.RandomIDGenerator{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+type RegisterLotteryRequest struct {
+	EventID uuid.UUID
+	UserID  uuid.UUID
+	Tickets int
+}
+
+// Register enters the user into the event's lottery for req.Tickets; no tickets are taken until the draw
+// Registration closes once the lottery is drawn
+func (s *LotteryService) Register(ctx context.Context, req RegisterLotteryRequest) (*domain.LotteryEntry, error) {
+	entry, err := domain.NewLotteryEntry(req.EventID, req.UserID, req.Tickets, s.clock.Now(),
+		domain.WithLotteryEntryIDGenerator(s.idGenerator))
+	if err != nil {
+		return nil, err
+	}
+
+	ruleTime := s.clock.RuleTime()
+	err = WithTransaction(ctx, s.db, s.logger, nil, "register_lottery", func(tx domain.Transaction) error {
+		// The shared lock keeps a draw, which locks the event exclusively, from running until the entry is in
+		event, err := s.eventRepo.FindByIDForShare(ctx, tx, req.EventID)
+		if err != nil {
+			return fmt.Errorf("failed to find event: %w", err)
+		}
+		if err := event.CheckBookable(ruleTime); err != nil {
+			return err
+		}
+		if err := event.CheckLottery(); err != nil {
+			return err
+		}
+		if event.Seated {
+			return domain.ErrLotterySeated
+		}
+		if err := event.CheckQuantity(entry.Tickets); err != nil {
+			return err
+		}
+		if entry.Tickets > event.Tickets {
+			return domain.ErrExceedsCapacity
+		}
+
+		drawn, err := s.repo.IsDrawnWithExecutor(ctx, tx, req.EventID)
+		if err != nil {
+			return err
+		}
+		if drawn {
+			return domain.ErrLotteryAlreadyDrawn
+		}
+
+		return s.repo.CreateWithExecutor(ctx, tx, entry)
+	})
+	if err != nil {
+		s.logger.Warn().Err(err).Str("event_id", req.EventID.String()).Str("user_id", req.UserID.String()).Msg("failed to register for lottery")
+		return nil, err
+	}
+
+	s.logger.Info().
+		Str("entry_id", entry.ID.String()).
+		Str("event_id", entry.EventID.String()).
+		Str("user_id", entry.UserID.String()).
+		Int("tickets", entry.Tickets).
+		Msg("lottery entry registered")
+
+	return entry, nil
+}
+
+type DrawLotteryRequest struct {
+	EventID uuid.UUID
+	// Weights make some users more likely to win, keyed by user ID; users without one have weight 1
+	Weights map[uuid.UUID]int
+	// Seed reproduces a draw; nil draws with a fresh seed, which is returned with the result
+	Seed *int64
+}
+
+// Draw picks the winners of the event's lottery up to its available tickets and books them, in one transaction
+// Every entry is decided: winners get a booking, the rest lose. An event can only be drawn once
+func (s *LotteryService) Draw(ctx context.Context, req DrawLotteryRequest) (*domain.LotteryDraw, error) {
+	seed := time.Now().UnixNano()
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+
+	ruleTime := s.clock.RuleTime()
+	var draw *domain.LotteryDraw
+	var bookings []*domain.Booking
+	err := WithTransaction(ctx, s.db, s.logger, nil, "draw_lottery", func(tx domain.Transaction) error {
+		bookings = nil
+
+		event, err := s.eventRepo.FindByIDWithLock(ctx, tx, req.EventID)
+		if err != nil {
+			return fmt.Errorf("failed to find event: %w", err)
+		}
+		if err := event.CheckBookable(ruleTime); err != nil {
+			return err
+		}
+		if err := event.CheckLottery(); err != nil {
+			return err
+		}
+
+		availability, err := s.ticketAvailabilityRepo.FindByEventIDWithLock(ctx, tx, req.EventID)
+		if err != nil {
+			return fmt.Errorf("failed to find ticket availability: %w", err)
+		}
+		entries, err := s.repo.FindByEventWithLock(ctx, tx, req.EventID)
+		if err != nil {
+			return err
+		}
+
+		// Entries are checked at registration, but one registered before that check may not be a quantity
+		// the event sells; it loses without taking part, so it cannot become a booking or take capacity
+		eligible := make([]*domain.LotteryEntry, 0, len(entries))
+		var unsellable []*domain.LotteryEntry
+		for _, entry := range entries {
+			if event.CheckQuantity(entry.Tickets) != nil {
+				unsellable = append(unsellable, entry)
+				continue
+			}
+			eligible = append(eligible, entry)
+		}
+
+		draw, err = domain.DrawLottery(eligible, availability.AvailableTickets, req.Weights, seed)
+		if err != nil {
+			return err
+		}
+		for _, entry := range unsellable {
+			entry.Status = domain.LotteryEntryLost
+			draw.Losers = append(draw.Losers, entry)
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+
+		for _, winner := range draw.Winners {
+			if err := availability.ReserveTickets(winner.Tickets, event.Tickets); err != nil {
+				recordSoldOutRejection(err, event.Tickets)
+				return err
+			}
+			quote, err := event.Quote(winner.Tickets)
+			if err != nil {
+				return err
+			}
+			booking, err := domain.NewBooking(event.ID, winner.UserID, winner.Tickets,
+				domain.WithBookingPrice(quote.TotalCents, quote.Currency), domain.WithBookingIDGenerator(s.idGenerator))
+			if err != nil {
+				return fmt.Errorf("invalid booking data: %w", err)
+			}
+			winner.BookingID = booking.ID
+			bookings = append(bookings, booking)
+		}
+
+		if len(bookings) > 0 {
+			if err := s.bookingRepo.CreateBatchWithExecutor(ctx, tx, bookings); err != nil {
+				return fmt.Errorf("failed to create bookings: %w", err)
+			}
+			if err := s.ticketAvailabilityRepo.UpdateWithExecutor(ctx, tx, availability, domain.AvailabilityBooked); err != nil {
+				return fmt.Errorf("failed to update ticket availability: %w", err)
+			}
+		}
+		return s.repo.UpdateBatchWithExecutor(ctx, tx, entries)
+	})
+	if err != nil {
+		s.logger.Warn().Err(err).Str("event_id", req.EventID.String()).Int64("seed", seed).Msg("failed to draw lottery")
+		return nil, err
+	}
+
+	s.logger.Info().
+		Str("event_id", req.EventID.String()).
+		Int64("seed", seed).
+		Int("winners", len(draw.Winners)).
+		Int("losers", len(draw.Losers)).
+		Int("tickets", draw.Tickets()).
+		Msg("lottery drawn")
+
+	return draw, nil
+}
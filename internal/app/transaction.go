@@ -0,0 +1,82 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/rs/zerolog"
+)
+
+const (
+	txOutcomeCommitted  = "committed"
+	txOutcomeRolledBack = "rolledback"
+)
+
+// WithTransaction runs fn inside a transaction, committing when fn returns nil and rolling back otherwise
+// Every outcome is counted in TransactionsTotal under the given operation name;
+// a panic inside fn is counted as a rollback and then re-raised
+// Transactions slower than db's infrastructure.SlowTransactionThreshold, including time spent waiting on locks,
+// are logged
+// A statement timeout set on ctx with infrastructure.WithStatementTimeout applies to the whole transaction
+func WithTransaction(
+	ctx context.Context,
+	db infrastructure.DBClient,
+	logger zerolog.Logger,
+	opts *sql.TxOptions,
+	operation string,
+	fn func(tx domain.Transaction) error,
+) (err error) {
+	start := time.Now()
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		logger.Error().Err(err).Str("operation", operation).Msg("failed to begin transaction")
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		outcome := txOutcomeCommitted
+		if !committed {
+			tx.Rollback()
+			outcome = txOutcomeRolledBack
+			infrastructure.TransactionsTotal.WithLabelValues(operation, txOutcomeRolledBack).Inc()
+		}
+		logSlowTransaction(logger, operation, outcome, time.Since(start), infrastructure.SlowTransactionThreshold(db))
+	}()
+
+	if timeout, ok := infrastructure.StatementTimeoutFromContext(ctx); ok {
+		if err := infrastructure.SetLocalStatementTimeout(ctx, tx, timeout); err != nil {
+			return err
+		}
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error().Err(err).Str("operation", operation).Msg("failed to commit transaction")
+		return fmt.Errorf("failed to commit transaction: %w", infrastructure.ClassifyDBError(err))
+	}
+
+	committed = true
+	infrastructure.TransactionsTotal.WithLabelValues(operation, txOutcomeCommitted).Inc()
+	return nil
+}
+
+func logSlowTransaction(logger zerolog.Logger, operation, outcome string, elapsed, threshold time.Duration) {
+	if threshold <= 0 || elapsed < threshold {
+		return
+	}
+
+	logger.Warn().
+		Str("operation", operation).
+		Str("outcome", outcome).
+		Dur("duration", elapsed).
+		Dur("threshold", threshold).
+		Msg("slow transaction")
+}
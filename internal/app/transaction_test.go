@@ -0,0 +1,193 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTx struct {
+	domain.Executor
+	committed   bool
+	rolledBack  bool
+	commitDelay time.Duration
+}
+
+func (tx *fakeTx) Commit() error {
+	time.Sleep(tx.commitDelay)
+	tx.committed = true
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	if !tx.committed {
+		tx.rolledBack = true
+	}
+	return nil
+}
+
+type fakeDB struct {
+	infrastructure.DBClient
+	tx              *fakeTx
+	txExecutor      domain.Executor
+	commitDelay     time.Duration
+	slowTxThreshold time.Duration
+}
+
+func (db *fakeDB) SlowTransactionThreshold() time.Duration {
+	if db.slowTxThreshold == 0 {
+		return infrastructure.DefaultSlowTransactionThreshold
+	}
+	return db.slowTxThreshold
+}
+
+func (db *fakeDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (domain.Transaction, error) {
+	db.tx = &fakeTx{Executor: db.txExecutor, commitDelay: db.commitDelay}
+	return db.tx, nil
+}
+
+// recordingExecutor records the statements executed through it
+type recordingExecutor struct {
+	domain.Executor
+	statements []string
+}
+
+func (e *recordingExecutor) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	e.statements = append(e.statements, query)
+	return nil, nil
+}
+
+func TestWithTransaction_CountsOutcomes(t *testing.T) {
+	tests := []struct {
+		name            string
+		operation       string
+		fn              func(tx domain.Transaction) error
+		wantPanic       bool
+		wantErr         error
+		expectedOutcome string
+	}{
+		{
+			name:            "counts commit when fn succeeds",
+			operation:       "test_commit",
+			fn:              func(tx domain.Transaction) error { return nil },
+			expectedOutcome: txOutcomeCommitted,
+		},
+		{
+			name:            "counts rollback when fn fails",
+			operation:       "test_rollback",
+			fn:              func(tx domain.Transaction) error { return domain.ErrInsufficientTickets },
+			wantErr:         domain.ErrInsufficientTickets,
+			expectedOutcome: txOutcomeRolledBack,
+		},
+		{
+			name:            "counts rollback when fn panics",
+			operation:       "test_panic",
+			fn:              func(tx domain.Transaction) error { panic("unexpected nil aggregate") },
+			wantPanic:       true,
+			expectedOutcome: txOutcomeRolledBack,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDB{}
+			committed := infrastructure.TransactionsTotal.WithLabelValues(tt.operation, txOutcomeCommitted)
+			rolledBack := infrastructure.TransactionsTotal.WithLabelValues(tt.operation, txOutcomeRolledBack)
+
+			run := func() error {
+				return WithTransaction(context.Background(), db, zerolog.Nop(), nil, tt.operation, tt.fn)
+			}
+
+			if tt.wantPanic {
+				assert.Panics(t, func() { _ = run() })
+			} else {
+				err := run()
+				if tt.wantErr != nil {
+					assert.True(t, errors.Is(err, tt.wantErr))
+				} else {
+					require.NoError(t, err)
+				}
+			}
+
+			if tt.expectedOutcome == txOutcomeCommitted {
+				assert.True(t, db.tx.committed)
+				assert.Equal(t, float64(1), testutil.ToFloat64(committed))
+				assert.Equal(t, float64(0), testutil.ToFloat64(rolledBack))
+			} else {
+				assert.True(t, db.tx.rolledBack)
+				assert.Equal(t, float64(0), testutil.ToFloat64(committed))
+				assert.Equal(t, float64(1), testutil.ToFloat64(rolledBack))
+			}
+		})
+	}
+}
+
+func TestWithTransaction_LogsSlowTransactions(t *testing.T) {
+	tests := []struct {
+		name        string
+		commitDelay time.Duration
+		wantLogged  bool
+	}{
+		{name: "logs commit slower than threshold", commitDelay: 40 * time.Millisecond, wantLogged: true},
+		{name: "stays quiet under threshold", commitDelay: 0, wantLogged: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs strings.Builder
+			logger := zerolog.New(&logs)
+			db := &fakeDB{commitDelay: tt.commitDelay, slowTxThreshold: 20 * time.Millisecond}
+
+			err := WithTransaction(context.Background(), db, logger, nil, "test_slow", func(tx domain.Transaction) error { return nil })
+			require.NoError(t, err)
+
+			if !tt.wantLogged {
+				assert.Empty(t, logs.String())
+				return
+			}
+			assert.Contains(t, logs.String(), `"level":"warn"`)
+			assert.Contains(t, logs.String(), `"message":"slow transaction"`)
+			assert.Contains(t, logs.String(), `"operation":"test_slow"`)
+			assert.Contains(t, logs.String(), `"outcome":"committed"`)
+		})
+	}
+}
+
+func TestWithTransaction_StatementTimeout(t *testing.T) {
+	t.Run("keeps the connection's timeout without an override", func(t *testing.T) {
+		exec := &recordingExecutor{}
+		db := &fakeDB{txExecutor: exec}
+
+		err := WithTransaction(context.Background(), db, zerolog.Nop(), nil, "test_default_timeout", func(tx domain.Transaction) error {
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Empty(t, exec.statements)
+	})
+
+	t.Run("sets the override before fn runs", func(t *testing.T) {
+		exec := &recordingExecutor{}
+		db := &fakeDB{txExecutor: exec}
+		ctx := infrastructure.WithStatementTimeout(context.Background(), 2*time.Minute)
+
+		err := WithTransaction(ctx, db, zerolog.Nop(), nil, "test_timeout_override", func(tx domain.Transaction) error {
+			_, err := tx.ExecContext(ctx, "UPDATE events SET name = name")
+			return err
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"SET LOCAL statement_timeout = 120000", "UPDATE events SET name = name"}, exec.statements)
+		assert.True(t, db.tx.committed)
+	})
+}
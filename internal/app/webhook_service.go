@@ -0,0 +1,334 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultWebhookWorkers deliver notifications concurrently; each sends to one event's webhooks in turn
+	DefaultWebhookWorkers = 4
+	// DefaultWebhookQueueSize notifications wait for a worker before new ones are dead-lettered
+	DefaultWebhookQueueSize = 1000
+)
+
+type WebhookService struct {
+	repo          domain.WebhookRepository
+	eventRepo     domain.EventRepository
+	sender        domain.WebhookSender
+	logger        zerolog.Logger
+	clock         domain.Clock
+	idGenerator   domain.IDGenerator
+	allowInternal bool
+	workers       int
+	queue         chan webhookJob
+	startWorkers  func()
+	deliveries    sync.WaitGroup
+}
+
+// webhookJob is a notification waiting for a delivery worker
+type webhookJob struct {
+	ctx          context.Context
+	notification domain.WebhookNotification
+	// webhookID limits a retried dead letter to the webhook that missed it; uuid.Nil sends to every hook
+	webhookID uuid.UUID
+	// attempts counts the earlier failed deliveries of a retried dead letter
+	attempts int
+}
+
+type WebhookServiceOption func(*WebhookService)
+
+// WithWebhookClock replaces the clock used for registration and notification times
+func WithWebhookClock(clock domain.Clock) WebhookServiceOption {
+	return func(s *WebhookService) {
+		s.clock = clock
+	}
+}
+
+// WithWebhookIDGenerator draws the IDs of webhooks and dead letters from gen instead of random UUIDv4s
+func WithWebhookIDGenerator(gen domain.IDGenerator) WebhookServiceOption {
+	return func(s *WebhookService) {
+		s.idGenerator = gen
+	}
+}
+
+// WithWebhookWorkers delivers notifications with this many workers, queueing up to queueSize of them
+// Without it the service uses DefaultWebhookWorkers and DefaultWebhookQueueSize
+func WithWebhookWorkers(workers, queueSize int) WebhookServiceOption {
+	return func(s *WebhookService) {
+		s.workers = max(workers, 1)
+		s.queue = make(chan webhookJob, max(queueSize, 0))
+	}
+}
+
+// WithInternalWebhookHosts accepts webhooks on localhost and internal addresses, for development and tests
+func WithInternalWebhookHosts() WebhookServiceOption {
+	return func(s *WebhookService) {
+		s.allowInternal = true
+	}
+}
+
+func NewWebhookService(
+	repo domain.WebhookRepository,
+	eventRepo domain.EventRepository,
+	sender domain.WebhookSender,
+	logger zerolog.Logger,
+	opts ...WebhookServiceOption,
+) *WebhookService {
+	s := &WebhookService{
+		repo:        repo,
+		eventRepo:   eventRepo,
+		sender:      sender,
+		logger:      logger.With().Str("service", "webhook").Logger(),
+		clock:       domain.SystemClock(),
+		idGenerator: domain.RandomIDGenerator{},
+		workers:     DefaultWebhookWorkers,
+		queue:       make(chan webhookJob, DefaultWebhookQueueSize),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	// Workers start with the first notification, so services that never notify run none
+	s.startWorkers = sync.OnceFunc(func() {
+		for range s.workers {
+			go s.work()
+		}
+	})
+	return s
+}
+
+type RegisterWebhookRequest struct {
+	EventID uuid.UUID
+	URL     string
+	Secret  string
+}
+
+// RegisterWebhook adds a webhook notified of every later booking of the event
+// URLs naming localhost or an internal address are rejected unless WithInternalWebhookHosts is set
+func (s *WebhookService) RegisterWebhook(ctx context.Context, req RegisterWebhookRequest) (*domain.EventWebhook, error) {
+	if _, err := s.eventRepo.FindByID(ctx, req.EventID); err != nil {
+		return nil, fmt.Errorf("failed to find event: %w", err)
+	}
+
+	hook, err := domain.NewEventWebhook(req.EventID, req.URL, req.Secret, s.clock.Now(), domain.WithWebhookIDGenerator(s.idGenerator))
+	if err != nil {
+		return nil, err
+	}
+	if !s.allowInternal {
+		if err := hook.CheckPublicHost(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Create(ctx, hook); err != nil {
+		s.logger.Error().Err(err).Str("event_id", req.EventID.String()).Msg("failed to create webhook")
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	s.logger.Info().
+		Str("webhook_id", hook.ID.String()).
+		Str("event_id", hook.EventID.String()).
+		Msg("webhook registered")
+
+	return hook, nil
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, eventID, id uuid.UUID) error {
+	if err := s.repo.Delete(ctx, eventID, id); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	s.logger.Info().
+		Str("webhook_id", id.String()).
+		Str("event_id", eventID.String()).
+		Msg("webhook deleted")
+
+	return nil
+}
+
+// NotifyBookingCreated queues the booking for delivery to every webhook of its event, so a slow or
+// retried delivery never holds up the booking response
+// ctx supplies request-scoped values such as the tenant; its cancellation does not stop the deliveries,
+// and inside a request transaction the booking is only queued once that commits
+// When the queue is full the notification is stored as a failed delivery for each webhook instead
+func (s *WebhookService) NotifyBookingCreated(ctx context.Context, booking *domain.Booking) {
+	notification := domain.WebhookNotification{
+		Type:       domain.WebhookBookingCreated,
+		Booking:    booking,
+		OccurredAt: s.clock.Now(),
+	}
+	infrastructure.AfterRequestCommit(ctx, func() {
+		s.enqueue(webhookJob{ctx: infrastructure.WithoutRequestTransaction(context.WithoutCancel(ctx)), notification: notification})
+	})
+}
+
+// ListDeliveryFailures pages through the dead letters, most recent first, with their total count
+func (s *WebhookService) ListDeliveryFailures(ctx context.Context, limit, offset int) ([]*domain.WebhookDeliveryFailure, int, error) {
+	failures, err := s.repo.FindDeliveryFailures(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find webhook delivery failures: %w", err)
+	}
+
+	total, err := s.repo.CountDeliveryFailures(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook delivery failures: %w", err)
+	}
+
+	return failures, total, nil
+}
+
+// RetryDeliveryFailure removes the dead letter and queues its notification again for the webhook that
+// missed it; a retry that fails again is stored as a new dead letter with one more attempt
+// Dead letters stored without their payload are rejected with ErrDeliveryFailureNotReplayable, and those
+// of a deleted webhook with ErrWebhookNotFound
+func (s *WebhookService) RetryDeliveryFailure(ctx context.Context, id uuid.UUID) error {
+	failure, err := s.repo.FindDeliveryFailure(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find webhook delivery failure: %w", err)
+	}
+
+	notification, ok := failure.Notification()
+	if !ok {
+		return domain.ErrDeliveryFailureNotReplayable
+	}
+
+	hooks, err := s.repo.FindByEvent(ctx, failure.EventID)
+	if err != nil {
+		return fmt.Errorf("failed to find webhooks: %w", err)
+	}
+	if !slices.ContainsFunc(hooks, func(hook *domain.EventWebhook) bool { return hook.ID == failure.WebhookID }) {
+		return domain.ErrWebhookNotFound
+	}
+
+	// Deleting first means concurrent retries of one dead letter queue it once: the others find it gone
+	if err := s.repo.DeleteDeliveryFailure(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete webhook delivery failure: %w", err)
+	}
+
+	s.logger.Info().
+		Str("dead_letter_id", id.String()).
+		Str("webhook_id", failure.WebhookID.String()).
+		Str("booking_id", failure.BookingID.String()).
+		Msg("retrying webhook delivery")
+
+	infrastructure.AfterRequestCommit(ctx, func() {
+		s.enqueue(webhookJob{
+			ctx:          infrastructure.WithoutRequestTransaction(context.WithoutCancel(ctx)),
+			notification: notification,
+			webhookID:    failure.WebhookID,
+			attempts:     failure.Attempts,
+		})
+	})
+	return nil
+}
+
+// enqueue hands job to the delivery workers, storing it as failed when the queue is full
+func (s *WebhookService) enqueue(job webhookJob) {
+	s.startWorkers()
+	s.deliveries.Add(1)
+	select {
+	case s.queue <- job:
+	default:
+		defer s.deliveries.Done()
+		s.logger.Warn().Str("booking_id", job.notification.Booking.ID.String()).Msg("webhook delivery queue full")
+		hooks, err := s.findHooks(job)
+		if err != nil {
+			return
+		}
+		for _, hook := range hooks {
+			s.recordFailure(job, hook, errWebhookQueueFull)
+		}
+	}
+}
+
+// errWebhookQueueFull is recorded for notifications dropped because every worker was busy
+var errWebhookQueueFull = errors.New("webhook delivery queue full")
+
+// work delivers queued notifications until the process exits
+func (s *WebhookService) work() {
+	for job := range s.queue {
+		s.deliver(job)
+		s.deliveries.Done()
+	}
+}
+
+// deliver sends the job's notification to its webhooks in turn, storing each failed delivery
+func (s *WebhookService) deliver(job webhookJob) {
+	hooks, err := s.findHooks(job)
+	if err != nil {
+		return
+	}
+
+	for _, hook := range hooks {
+		if err := s.sender.Send(job.ctx, hook, job.notification); err != nil {
+			s.logger.Warn().
+				Err(err).
+				Str("webhook_id", hook.ID.String()).
+				Str("booking_id", job.notification.Booking.ID.String()).
+				Msg("failed to deliver webhook")
+			s.recordFailure(job, hook, err)
+		}
+	}
+}
+
+// findHooks returns the webhooks of the booking's event the job is for, logging a failed lookup
+func (s *WebhookService) findHooks(job webhookJob) ([]*domain.EventWebhook, error) {
+	eventID := job.notification.Booking.EventID
+	hooks, err := s.repo.FindByEvent(job.ctx, eventID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("event_id", eventID.String()).Msg("failed to find webhooks")
+		return nil, err
+	}
+	if job.webhookID == uuid.Nil {
+		return hooks, nil
+	}
+	return slices.DeleteFunc(hooks, func(hook *domain.EventWebhook) bool { return hook.ID != job.webhookID }), nil
+}
+
+// recordFailure stores the job's notification hook did not get as a dead letter, with what it takes to retry it
+func (s *WebhookService) recordFailure(job webhookJob, hook *domain.EventWebhook, cause error) {
+	notification := job.notification
+	failure := &domain.WebhookDeliveryFailure{
+		ID:         s.idGenerator.NewID(),
+		WebhookID:  hook.ID,
+		EventID:    hook.EventID,
+		BookingID:  notification.Booking.ID,
+		Type:       notification.Type,
+		Error:      cause.Error(),
+		FailedAt:   s.clock.Now(),
+		Booking:    notification.Booking,
+		OccurredAt: notification.OccurredAt,
+		Attempts:   job.attempts + 1,
+	}
+	if err := s.repo.CreateDeliveryFailure(job.ctx, failure); err != nil {
+		s.logger.Error().
+			Err(err).
+			Str("webhook_id", hook.ID.String()).
+			Str("booking_id", notification.Booking.ID.String()).
+			Msg("failed to store webhook delivery failure")
+	}
+}
+
+// Wait blocks until the deliveries in flight finish or ctx is done, so shutdown does not drop them
+func (s *WebhookService) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.deliveries.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
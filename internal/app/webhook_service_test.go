@@ -0,0 +1,216 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryWebhookRepo keeps webhooks and dead letters in memory
+type memoryWebhookRepo struct {
+	domain.WebhookRepository
+	mu       sync.Mutex
+	hooks    []*domain.EventWebhook
+	failures []*domain.WebhookDeliveryFailure
+}
+
+func (r *memoryWebhookRepo) Create(ctx context.Context, hook *domain.EventWebhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, hook)
+	return nil
+}
+
+func (r *memoryWebhookRepo) FindByEvent(ctx context.Context, eventID uuid.UUID) ([]*domain.EventWebhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var hooks []*domain.EventWebhook
+	for _, hook := range r.hooks {
+		if hook.EventID == eventID {
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks, nil
+}
+
+func (r *memoryWebhookRepo) CreateDeliveryFailure(ctx context.Context, failure *domain.WebhookDeliveryFailure) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failures = append(r.failures, failure)
+	return nil
+}
+
+func (r *memoryWebhookRepo) FindDeliveryFailure(ctx context.Context, id uuid.UUID) (*domain.WebhookDeliveryFailure, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, failure := range r.failures {
+		if failure.ID == id {
+			return failure, nil
+		}
+	}
+	return nil, domain.ErrDeliveryFailureNotFound
+}
+
+func (r *memoryWebhookRepo) DeleteDeliveryFailure(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, failure := range r.failures {
+		if failure.ID == id {
+			r.failures = append(r.failures[:i], r.failures[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrDeliveryFailureNotFound
+}
+
+// blockingWebhookSender fails every delivery, after waiting for release when it is set
+type blockingWebhookSender struct {
+	release chan struct{}
+}
+
+func (s *blockingWebhookSender) Send(ctx context.Context, hook *domain.EventWebhook, notification domain.WebhookNotification) error {
+	if s.release != nil {
+		<-s.release
+	}
+	return errors.New("webhook responded with status 500")
+}
+
+type webhookEventRepo struct {
+	domain.EventRepository
+}
+
+func (webhookEventRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.Event, error) {
+	return &domain.Event{ID: id}, nil
+}
+
+type fixedIDGenerator struct {
+	id uuid.UUID
+}
+
+func (g fixedIDGenerator) NewID() uuid.UUID {
+	return g.id
+}
+
+func TestWebhookService_RegisterWebhook(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+	service := NewWebhookService(&memoryWebhookRepo{}, webhookEventRepo{}, &blockingWebhookSender{}, zerolog.Nop(),
+		WithWebhookIDGenerator(fixedIDGenerator{id: id}))
+	register := func(url string) (*domain.EventWebhook, error) {
+		return service.RegisterWebhook(ctx, RegisterWebhookRequest{EventID: uuid.New(), URL: url, Secret: "0123456789abcdef"})
+	}
+
+	hook, err := register("https://hooks.example.com/bookings")
+	require.NoError(t, err)
+	assert.Equal(t, id, hook.ID)
+
+	for _, url := range []string{
+		"http://localhost:8080/admin",
+		"http://127.0.0.1/",
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.7/",
+		"http://[::1]/",
+	} {
+		_, err := register(url)
+		assert.ErrorIs(t, err, domain.ErrWebhookHostNotAllowed, url)
+	}
+
+	internal := NewWebhookService(&memoryWebhookRepo{}, webhookEventRepo{}, &blockingWebhookSender{}, zerolog.Nop(),
+		WithInternalWebhookHosts())
+	_, err = internal.RegisterWebhook(ctx, RegisterWebhookRequest{EventID: uuid.New(), URL: "http://127.0.0.1/", Secret: "0123456789abcdef"})
+	assert.NoError(t, err)
+}
+
+func TestWebhookService_DeadLettersFailedDeliveries(t *testing.T) {
+	ctx := context.Background()
+	eventID := uuid.New()
+	hook := &domain.EventWebhook{ID: uuid.New(), EventID: eventID, URL: "https://hooks.example.com/bookings"}
+	booking := func() *domain.Booking {
+		return &domain.Booking{ID: uuid.New(), EventID: eventID}
+	}
+	wait := func(t *testing.T, service *WebhookService) {
+		t.Helper()
+		waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		require.NoError(t, service.Wait(waitCtx))
+	}
+
+	t.Run("stores a delivery that failed every retry", func(t *testing.T) {
+		repo := &memoryWebhookRepo{hooks: []*domain.EventWebhook{hook}}
+		service := NewWebhookService(repo, webhookEventRepo{}, &blockingWebhookSender{}, zerolog.Nop())
+
+		failed := booking()
+		service.NotifyBookingCreated(ctx, failed)
+		wait(t, service)
+
+		require.Len(t, repo.failures, 1)
+		assert.Equal(t, hook.ID, repo.failures[0].WebhookID)
+		assert.Equal(t, failed.ID, repo.failures[0].BookingID)
+		assert.Equal(t, domain.WebhookBookingCreated, repo.failures[0].Type)
+		assert.Contains(t, repo.failures[0].Error, "status 500")
+	})
+
+	t.Run("dead-letters notifications beyond the queue instead of blocking", func(t *testing.T) {
+		repo := &memoryWebhookRepo{hooks: []*domain.EventWebhook{hook}}
+		sender := &blockingWebhookSender{release: make(chan struct{})}
+		service := NewWebhookService(repo, webhookEventRepo{}, sender, zerolog.Nop(), WithWebhookWorkers(1, 1))
+
+		// The first notification occupies the worker and the second the queue, so the third has nowhere to go
+		service.NotifyBookingCreated(ctx, booking())
+		require.Eventually(t, func() bool { return len(service.queue) == 0 }, 5*time.Second, time.Millisecond)
+		service.NotifyBookingCreated(ctx, booking())
+		service.NotifyBookingCreated(ctx, booking())
+
+		repo.mu.Lock()
+		require.Len(t, repo.failures, 1)
+		assert.Equal(t, errWebhookQueueFull.Error(), repo.failures[0].Error)
+		repo.mu.Unlock()
+
+		close(sender.release)
+		wait(t, service)
+		assert.Len(t, repo.failures, 3)
+	})
+
+	t.Run("retries a dead letter for the webhook that missed it, counting the attempt", func(t *testing.T) {
+		other := &domain.EventWebhook{ID: uuid.New(), EventID: eventID, URL: "https://other.example.com/bookings"}
+		repo := &memoryWebhookRepo{hooks: []*domain.EventWebhook{hook, other}}
+		service := NewWebhookService(repo, webhookEventRepo{}, &blockingWebhookSender{}, zerolog.Nop())
+
+		failed := booking()
+		service.NotifyBookingCreated(ctx, failed)
+		wait(t, service)
+		require.Len(t, repo.failures, 2)
+		deadLetter := repo.failures[0]
+		assert.Equal(t, 1, deadLetter.Attempts)
+		assert.Same(t, failed, deadLetter.Booking)
+
+		require.NoError(t, service.RetryDeliveryFailure(ctx, deadLetter.ID))
+		wait(t, service)
+
+		// The retried dead letter is replaced by its failed retry; the other webhook is not sent it again
+		require.Len(t, repo.failures, 2)
+		retried := repo.failures[1]
+		assert.NotEqual(t, deadLetter.ID, retried.ID)
+		assert.Equal(t, deadLetter.WebhookID, retried.WebhookID)
+		assert.Equal(t, failed.ID, retried.BookingID)
+		assert.Equal(t, 2, retried.Attempts)
+		assert.ErrorIs(t, service.RetryDeliveryFailure(ctx, deadLetter.ID), domain.ErrDeliveryFailureNotFound)
+	})
+
+	t.Run("rejects retrying a dead letter stored without its payload", func(t *testing.T) {
+		legacy := &domain.WebhookDeliveryFailure{ID: uuid.New(), WebhookID: hook.ID, EventID: eventID, BookingID: uuid.New()}
+		repo := &memoryWebhookRepo{hooks: []*domain.EventWebhook{hook}, failures: []*domain.WebhookDeliveryFailure{legacy}}
+		service := NewWebhookService(repo, webhookEventRepo{}, &blockingWebhookSender{}, zerolog.Nop())
+
+		assert.ErrorIs(t, service.RetryDeliveryFailure(ctx, legacy.ID), domain.ErrDeliveryFailureNotReplayable)
+		assert.Len(t, repo.failures, 1)
+	})
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// PublicAllocation names the default block: the event's ticket availability, which public sale draws from
+const PublicAllocation = "public"
+
+const MaxAllocationNameLen = 32
+
+var allocationNamePattern = regexp.MustCompile(`^[a-z0-9-]{1,32}$`)
+
+// Allocation is a named block of an event's tickets set aside from public sale, e.g. a press allocation
+// Creating it moves Tickets out of the event's availability, so the event's total is always split between
+// the public availability, the allocations and the booked tickets. Bookings targeting the block draw from
+// AvailableTickets only, and cancelling them returns the tickets to the block
+type Allocation struct {
+	ID               uuid.UUID
+	EventID          uuid.UUID
+	Name             string
+	Tickets          int
+	AvailableTickets int
+}
+
+// NewAllocation creates a block of tickets for the event; name is lower-cased and must not be PublicAllocation
+func NewAllocation(eventID uuid.UUID, name string, tickets int) (*Allocation, error) {
+	name, err := NormalizeAllocationName(name)
+	if err != nil {
+		return nil, err
+	}
+	if name == PublicAllocation {
+		return nil, ErrInvalidAllocationName
+	}
+	if tickets <= 0 {
+		return nil, ErrInvalidTickets
+	}
+	if tickets > MaxTickets {
+		return nil, ErrTicketsTooLarge
+	}
+
+	return &Allocation{
+		ID:               uuid.New(),
+		EventID:          eventID,
+		Name:             name,
+		Tickets:          tickets,
+		AvailableTickets: tickets,
+	}, nil
+}
+
+// NormalizeAllocationName trims and lower-cases name, which must be 1 to 32 letters, digits or '-'
+func NormalizeAllocationName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !allocationNamePattern.MatchString(name) {
+		return "", ErrInvalidAllocationName
+	}
+	return name, nil
+}
+
+// Reserve takes count tickets from the block, failing with ErrInsufficientTickets when it has too few
+func (a *Allocation) Reserve(count int) error {
+	if count <= 0 {
+		return ErrInvalidTicketCount
+	}
+	if count > MaxTickets {
+		return ErrTicketCountTooLarge
+	}
+	if count > a.Tickets {
+		return ErrExceedsCapacity
+	}
+	if a.AvailableTickets < count {
+		return ErrInsufficientTickets
+	}
+
+	a.AvailableTickets -= count
+	return nil
+}
+
+// Release returns count previously reserved tickets to the block
+func (a *Allocation) Release(count int) error {
+	if count <= 0 {
+		return ErrInvalidTicketCount
+	}
+	if a.AvailableTickets+count > a.Tickets {
+		return ErrAvailabilityOverflow
+	}
+
+	a.AvailableTickets += count
+	return nil
+}
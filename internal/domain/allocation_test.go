@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAllocation(t *testing.T) {
+	eventID := uuid.New()
+
+	tests := []struct {
+		name     string
+		block    string
+		tickets  int
+		wantName string
+		wantErr  error
+	}{
+		{name: "accepts a named block", block: "press", tickets: 20, wantName: "press"},
+		{name: "normalizes the name", block: "  Press-Row ", tickets: 20, wantName: "press-row"},
+		{name: "rejects a blank name", block: " ", tickets: 20, wantErr: ErrInvalidAllocationName},
+		{name: "rejects other characters", block: "press row", tickets: 20, wantErr: ErrInvalidAllocationName},
+		{name: "rejects overly long names", block: strings.Repeat("a", MaxAllocationNameLen+1), tickets: 20, wantErr: ErrInvalidAllocationName},
+		{name: "rejects the public block's name", block: "Public", tickets: 20, wantErr: ErrInvalidAllocationName},
+		{name: "rejects an empty block", block: "press", tickets: 0, wantErr: ErrInvalidTickets},
+		{name: "rejects an oversized block", block: "press", tickets: MaxTickets + 1, wantErr: ErrTicketsTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allocation, err := NewAllocation(eventID, tt.block, tt.tickets)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, allocation)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, allocation.Name)
+			assert.Equal(t, tt.tickets, allocation.Tickets)
+			assert.Equal(t, tt.tickets, allocation.AvailableTickets)
+		})
+	}
+}
+
+func TestAllocation_Reserve(t *testing.T) {
+	allocation, err := NewAllocation(uuid.New(), "press", 5)
+	require.NoError(t, err)
+
+	require.NoError(t, allocation.Reserve(3))
+	assert.Equal(t, 2, allocation.AvailableTickets)
+
+	assert.ErrorIs(t, allocation.Reserve(3), ErrInsufficientTickets)
+	assert.ErrorIs(t, allocation.Reserve(6), ErrExceedsCapacity, "more than the whole block can never be reserved")
+	assert.ErrorIs(t, allocation.Reserve(0), ErrInvalidTicketCount)
+	assert.Equal(t, 2, allocation.AvailableTickets, "failed reservations take nothing")
+
+	require.NoError(t, allocation.Reserve(2))
+	assert.Equal(t, 0, allocation.AvailableTickets)
+
+	require.NoError(t, allocation.Release(4))
+	assert.Equal(t, 4, allocation.AvailableTickets)
+	assert.ErrorIs(t, allocation.Release(2), ErrAvailabilityOverflow, "the block never holds more than its size")
+}
+
+func TestTicketAvailability_Allocate(t *testing.T) {
+	availability := &TicketAvailability{EventID: uuid.New(), AvailableTickets: 10}
+
+	require.NoError(t, availability.Allocate(4))
+	assert.Equal(t, 6, availability.AvailableTickets)
+
+	assert.ErrorIs(t, availability.Allocate(7), ErrAllocationExceedsAvailable)
+	assert.ErrorIs(t, availability.Allocate(0), ErrInvalidTickets)
+	assert.Equal(t, 6, availability.AvailableTickets)
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AvailabilityChangeReason says why an event's available tickets changed
+type AvailabilityChangeReason string
+
+const (
+	AvailabilityCreated          AvailabilityChangeReason = "created"
+	AvailabilityBooked           AvailabilityChangeReason = "booking"
+	AvailabilityBookingCancelled AvailabilityChangeReason = "booking_cancelled"
+	AvailabilityHeld             AvailabilityChangeReason = "hold"
+	AvailabilityHoldReleased     AvailabilityChangeReason = "hold_released"
+	AvailabilityAdjusted         AvailabilityChangeReason = "adjustment"
+	// AvailabilityAllocated records tickets moved out of public sale into an allocation block
+	AvailabilityAllocated AvailabilityChangeReason = "allocation"
+	// AvailabilityReconciled records availability reset to what the event's bookings, holds and allocations imply
+	AvailabilityReconciled AvailabilityChangeReason = "reconciliation"
+	// AvailabilityMerged records tickets taken by the bookings of a duplicate event merged into this one
+	AvailabilityMerged AvailabilityChangeReason = "merge"
+	// AvailabilityBaseline records the availability of events that existed before changes were logged
+	AvailabilityBaseline AvailabilityChangeReason = "baseline"
+)
+
+// AvailabilityChange is one entry of an event's append-only availability log
+// Resulting is the availability right after the change, so the value at any time is the latest entry before it
+type AvailabilityChange struct {
+	EventID   uuid.UUID
+	Delta     int
+	Reason    AvailabilityChangeReason
+	Resulting int
+	ChangedAt time.Time
+}
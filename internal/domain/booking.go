@@ -6,24 +6,256 @@ import (
 	"github.com/google/uuid"
 )
 
+type BookingStatus string
+
+const (
+	BookingStatusConfirmed BookingStatus = "confirmed"
+	// BookingStatusPending marks a conditional booking waiting for its event's minimum to be resolved,
+	// or a reservation waiting to be confirmed before its ExpiresAt
+	BookingStatusPending   BookingStatus = "pending"
+	BookingStatusCancelled BookingStatus = "cancelled"
+)
+
 type Booking struct {
-	ID            uuid.UUID
-	EventID       uuid.UUID
-	UserID        uuid.UUID
-	TicketsBooked int
-	BookedAt      time.Time
+	ID               uuid.UUID
+	EventID          uuid.UUID
+	UserID           uuid.UUID
+	TicketsBooked    int
+	BookedAt         time.Time
+	Status           BookingStatus
+	Conditional      bool      // Booked subject to the event reaching its minimum group size
+	SeatLabels       []string  // Reserved seats; empty for general admission bookings
+	ConfirmationCode string    // Short upper-case code customers quote to support
+	PriceCents       int64     // Total charged for the booking after any discount, in minor units
+	Currency         string    // ISO 4217 code of PriceCents; empty for free events
+	DiscountCode     string    // Code redeemed for this booking; empty when none was applied
+	CreatedBy        string    // Admin who booked on the user's behalf; empty when the user booked
+	PayPriceCents    int64     // PriceCents converted to PayCurrency when the booking was made
+	PayCurrency      string    // Currency the user pays in; empty when they pay in Currency
+	FXRate           float64   // Units of PayCurrency per unit of Currency applied to PayPriceCents
+	AllocationID     uuid.UUID // Allocation block the tickets came from; uuid.Nil for public sale
+	ExpiresAt        time.Time // Deadline to confirm a pending reservation; zero for bookings needing no confirmation
+	RefundCents      int64     // Refunded in Currency when the booking was cancelled; zero while it is not
+	// EventAvailableTickets is the event's public availability right after the booking reserved from it,
+	// read in the same transaction; nil when not known, as for allocation bookings and bookings read back
+	EventAvailableTickets *int
+}
+
+// MaxReservationTTL bounds how long a reservation may keep tickets off sale without being confirmed
+const MaxReservationTTL = 24 * time.Hour
+
+// MaxBookingActorLen bounds the admin name recorded on bookings made on a user's behalf
+const MaxBookingActorLen = 100
+
+// BookingOption configures optional booking attributes at creation
+type BookingOption func(*Booking)
+
+// WithBookingIDGenerator draws the booking ID from gen instead of a random UUIDv4
+func WithBookingIDGenerator(gen IDGenerator) BookingOption {
+	return func(b *Booking) {
+		b.ID = gen.NewID()
+	}
+}
+
+// AsConditional books subject to the event reaching its minimum; the booking starts pending
+func AsConditional() BookingOption {
+	return func(b *Booking) {
+		b.Conditional = true
+		b.Status = BookingStatusPending
+	}
+}
+
+// WithExpiry reserves the tickets until expiresAt: the booking starts pending and is released unless
+// confirmed by then
+func WithExpiry(expiresAt time.Time) BookingOption {
+	return func(b *Booking) {
+		b.ExpiresAt = expiresAt
+		b.Status = BookingStatusPending
+	}
+}
+
+// WithBookingPrice records the total charged for the booking
+func WithBookingPrice(priceCents int64, currency string) BookingOption {
+	return func(b *Booking) {
+		b.PriceCents = priceCents
+		b.Currency = currency
+	}
 }
 
-func NewBooking(eventID, userID uuid.UUID, ticketsBooked int) (*Booking, error) {
+// WithPayPrice records the booking's price converted to the currency the user pays in at rate
+func WithPayPrice(payPriceCents int64, rate FXRate) BookingOption {
+	return func(b *Booking) {
+		b.PayPriceCents = payPriceCents
+		b.PayCurrency = rate.To
+		b.FXRate = rate.Rate
+	}
+}
+
+// WithAllocation records the allocation block the booking's tickets came from
+func WithAllocation(allocationID uuid.UUID) BookingOption {
+	return func(b *Booking) {
+		b.AllocationID = allocationID
+	}
+}
+
+// WithDiscountCode records the discount code redeemed for the booking
+func WithDiscountCode(code string) BookingOption {
+	return func(b *Booking) {
+		b.DiscountCode = code
+	}
+}
+
+// WithCreatedBy records the admin booking on the user's behalf
+func WithCreatedBy(actor string) BookingOption {
+	return func(b *Booking) {
+		b.CreatedBy = actor
+	}
+}
+
+func NewBooking(eventID, userID uuid.UUID, ticketsBooked int, opts ...BookingOption) (*Booking, error) {
 	if ticketsBooked <= 0 {
 		return nil, ErrInvalidTicketCount
 	}
+	if ticketsBooked > MaxTickets {
+		return nil, ErrTicketCountTooLarge
+	}
+
+	booking := &Booking{
+		ID:               uuid.New(),
+		EventID:          eventID,
+		UserID:           userID,
+		TicketsBooked:    ticketsBooked,
+		BookedAt:         time.Now(),
+		Status:           BookingStatusConfirmed,
+		ConfirmationCode: NewConfirmationCode(),
+	}
+	for _, opt := range opts {
+		opt(booking)
+	}
+	if len(booking.CreatedBy) > MaxBookingActorLen {
+		return nil, ErrInvalidBookingActor
+	}
+	if booking.Conditional && !booking.ExpiresAt.IsZero() {
+		return nil, ErrConditionalReservation
+	}
+
+	return booking, nil
+}
+
+// ValidateReservationTTL checks a reservation's time to confirm is positive and at most MaxReservationTTL
+func ValidateReservationTTL(ttl time.Duration) error {
+	if ttl <= 0 || ttl > MaxReservationTTL {
+		return ErrInvalidReservationTTL
+	}
+	return nil
+}
+
+// IsReservation reports whether the booking must be confirmed before its ExpiresAt
+func (b *Booking) IsReservation() bool {
+	return !b.ExpiresAt.IsZero()
+}
+
+// IsExpired reports whether the booking is a pending reservation whose deadline has passed at now
+func (b *Booking) IsExpired(now time.Time) bool {
+	return b.Status == BookingStatusPending && b.IsReservation() && !now.Before(b.ExpiresAt)
+}
+
+// ConfirmReservation confirms a pending reservation before its deadline
+func (b *Booking) ConfirmReservation(now time.Time) error {
+	if b.Status != BookingStatusPending || !b.IsReservation() {
+		return ErrBookingNotPending
+	}
+	if b.IsExpired(now) {
+		return ErrBookingExpired
+	}
+	b.Status = BookingStatusConfirmed
+	return nil
+}
+
+// Expire cancels a pending reservation whose deadline has passed; the caller returns its tickets
+// An unconfirmed reservation was never paid, so nothing is refunded
+func (b *Booking) Expire(now time.Time) error {
+	if !b.IsExpired(now) {
+		return ErrBookingNotPending
+	}
+	b.Status = BookingStatusCancelled
+	return nil
+}
+
+// Split moves count of the booking's tickets to a new confirmed booking owned by userID, keeping the rest
+// The tickets only change hands, so the event's availability is unchanged and the new booking draws from the
+// same allocation. The price paid is split in proportion to the tickets, the new booking's share rounded down
+// opts configure the new booking, e.g. its ID generator
+func (b *Booking) Split(count int, userID uuid.UUID, opts ...BookingOption) (*Booking, error) {
+	if b.Status != BookingStatusConfirmed {
+		return nil, ErrBookingNotConfirmed
+	}
+	if count <= 0 {
+		return nil, ErrInvalidSplitCount
+	}
+	if count >= b.TicketsBooked {
+		return nil, ErrSplitTooLarge
+	}
+	if userID == b.UserID {
+		return nil, ErrSplitSameUser
+	}
+
+	opts = append([]BookingOption{
+		WithAllocation(b.AllocationID),
+		WithBookingPrice(b.PriceCents*int64(count)/int64(b.TicketsBooked), b.Currency),
+	}, opts...)
+	split, err := NewBooking(b.EventID, userID, count, opts...)
+	if err != nil {
+		return nil, err
+	}
+	split.Conditional = b.Conditional
+	if b.PayCurrency != "" {
+		split.PayPriceCents = b.PayPriceCents * int64(count) / int64(b.TicketsBooked)
+		split.PayCurrency = b.PayCurrency
+		split.FXRate = b.FXRate
+	}
+
+	b.TicketsBooked -= count
+	b.PriceCents -= split.PriceCents
+	b.PayPriceCents -= split.PayPriceCents
+	return split, nil
+}
+
+// ConfirmConditional confirms a pending conditional booking once its event is viable
+func (b *Booking) ConfirmConditional() error {
+	if b.Status != BookingStatusPending {
+		return ErrBookingNotPending
+	}
+	b.Status = BookingStatusConfirmed
+	return nil
+}
+
+// CancelConditional cancels a pending conditional booking; the caller returns its tickets
+// The event did not reach its minimum through no fault of the user, so the booking is refunded in full
+func (b *Booking) CancelConditional() error {
+	if b.Status != BookingStatusPending {
+		return ErrBookingNotPending
+	}
+	b.Status = BookingStatusCancelled
+	b.RefundCents = b.PriceCents
+	return nil
+}
+
+// Cancel cancels a confirmed or pending booking, recording refundCents as refunded; the caller computes
+// the refund with the event's CancellationPolicy before cancelling and returns the tickets
+func (b *Booking) Cancel(refundCents int64) error {
+	if b.Status == BookingStatusCancelled {
+		return ErrBookingAlreadyCancelled
+	}
+	b.Status = BookingStatusCancelled
+	b.RefundCents = refundCents
+	return nil
+}
 
-	return &Booking{
-		ID:            uuid.New(),
-		EventID:       eventID,
-		UserID:        userID,
-		TicketsBooked: ticketsBooked,
-		BookedAt:      time.Now(),
-	}, nil
+// BookingFilter narrows a booking listing; zero-valued fields are not applied
+type BookingFilter struct {
+	EventID    uuid.UUID
+	UserID     uuid.UUID
+	BookedFrom time.Time // Inclusive lower bound on BookedAt
+	BookedTo   time.Time // Exclusive upper bound on BookedAt
 }
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BookingAction says what happened to a booking
+type BookingAction string
+
+const (
+	BookingCreated   BookingAction = "created"
+	BookingConfirmed BookingAction = "confirmed"
+	BookingCancelled BookingAction = "cancelled"
+	// BookingReserved creates a reservation, which must be confirmed before it expires
+	BookingReserved BookingAction = "reserved"
+	// BookingExpired cancels a reservation that was not confirmed in time
+	BookingExpired BookingAction = "expired"
+	// BookingSplit moves some of a booking's tickets to a new booking, logged on both with BookingSplitCreated
+	// on the new one; neither moves availability, so they stay out of the ledger
+	BookingSplit        BookingAction = "split"
+	BookingSplitCreated BookingAction = "split_created"
+)
+
+// BookingCreatedAction is the action logged when booking is created
+func BookingCreatedAction(booking *Booking) BookingAction {
+	if booking.IsReservation() {
+		return BookingReserved
+	}
+	return BookingCreated
+}
+
+// BookingActionForStatus is the action that moves a booking into status
+func BookingActionForStatus(status BookingStatus) BookingAction {
+	switch status {
+	case BookingStatusConfirmed:
+		return BookingConfirmed
+	case BookingStatusCancelled:
+		return BookingCancelled
+	default:
+		return BookingAction(status)
+	}
+}
+
+// BookingChange is one entry of a booking's append-only change log
+// Status and TicketsBooked are the booking's values right after the change
+type BookingChange struct {
+	BookingID     uuid.UUID
+	Action        BookingAction
+	Status        BookingStatus
+	TicketsBooked int
+	Actor         string // Admin who made the change on the user's behalf; empty when the user or the system did
+	ChangedAt     time.Time
+}
+
+// Description renders the change for people reading a booking's history
+func (c *BookingChange) Description() string {
+	var description string
+	switch c.Action {
+	case BookingCreated:
+		description = fmt.Sprintf("Booked %d %s", c.TicketsBooked, pluralTickets(c.TicketsBooked))
+		if c.Status == BookingStatusPending {
+			description += ", pending the event's minimum"
+		}
+	case BookingReserved:
+		description = fmt.Sprintf("Reserved %d %s, pending confirmation", c.TicketsBooked, pluralTickets(c.TicketsBooked))
+	case BookingConfirmed:
+		description = "Confirmed"
+	case BookingCancelled:
+		description = fmt.Sprintf("Cancelled, releasing %d %s", c.TicketsBooked, pluralTickets(c.TicketsBooked))
+	case BookingExpired:
+		description = fmt.Sprintf("Expired unconfirmed, releasing %d %s", c.TicketsBooked, pluralTickets(c.TicketsBooked))
+	case BookingSplit:
+		description = fmt.Sprintf("Split, keeping %d %s", c.TicketsBooked, pluralTickets(c.TicketsBooked))
+	case BookingSplitCreated:
+		description = fmt.Sprintf("Split off another booking with %d %s", c.TicketsBooked, pluralTickets(c.TicketsBooked))
+	default:
+		description = string(c.Action)
+	}
+	if c.Actor != "" {
+		description += " by " + c.Actor
+	}
+	return description
+}
+
+func pluralTickets(n int) string {
+	if n == 1 {
+		return "ticket"
+	}
+	return "tickets"
+}
+
+// LedgerActions are the actions that move the public availability of the booking's event
+var LedgerActions = []BookingAction{BookingCreated, BookingReserved, BookingCancelled, BookingExpired}
+
+// AvailabilityDelta is how a change with this action moved its event's availability for a booking of tickets
+func (a BookingAction) AvailabilityDelta(tickets int) int {
+	switch a {
+	case BookingCreated, BookingReserved:
+		return -tickets
+	case BookingCancelled, BookingExpired:
+		return tickets
+	default:
+		return 0
+	}
+}
+
+// LedgerEntry is a booking change in an event's ledger, with the event's availability right after it
+type LedgerEntry struct {
+	*BookingChange
+	Delta          int
+	AvailableAfter int
+}
+
+// BuildLedger runs the availability from opening through changes, which must be in chronological order
+func BuildLedger(opening int, changes []*BookingChange) []*LedgerEntry {
+	entries := make([]*LedgerEntry, len(changes))
+	available := opening
+	for i, change := range changes {
+		delta := change.Action.AvailabilityDelta(change.TicketsBooked)
+		available += delta
+		entries[i] = &LedgerEntry{BookingChange: change, Delta: delta, AvailableAfter: available}
+	}
+	return entries
+}
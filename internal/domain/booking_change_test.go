@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookingChange_Description(t *testing.T) {
+	tests := []struct {
+		name   string
+		change BookingChange
+		want   string
+	}{
+		{
+			name:   "created",
+			change: BookingChange{Action: BookingCreated, Status: BookingStatusConfirmed, TicketsBooked: 1},
+			want:   "Booked 1 ticket",
+		},
+		{
+			name:   "created conditionally by an admin",
+			change: BookingChange{Action: BookingCreated, Status: BookingStatusPending, TicketsBooked: 3, Actor: "jane@support"},
+			want:   "Booked 3 tickets, pending the event's minimum by jane@support",
+		},
+		{
+			name:   "confirmed",
+			change: BookingChange{Action: BookingConfirmed, Status: BookingStatusConfirmed, TicketsBooked: 3},
+			want:   "Confirmed",
+		},
+		{
+			name:   "cancelled",
+			change: BookingChange{Action: BookingCancelled, Status: BookingStatusCancelled, TicketsBooked: 2},
+			want:   "Cancelled, releasing 2 tickets",
+		},
+		{
+			name:   "reserved",
+			change: BookingChange{Action: BookingReserved, Status: BookingStatusPending, TicketsBooked: 2},
+			want:   "Reserved 2 tickets, pending confirmation",
+		},
+		{
+			name:   "expired",
+			change: BookingChange{Action: BookingExpired, Status: BookingStatusCancelled, TicketsBooked: 1},
+			want:   "Expired unconfirmed, releasing 1 ticket",
+		},
+		{
+			name:   "split",
+			change: BookingChange{Action: BookingSplit, Status: BookingStatusConfirmed, TicketsBooked: 3},
+			want:   "Split, keeping 3 tickets",
+		},
+		{
+			name:   "split off",
+			change: BookingChange{Action: BookingSplitCreated, Status: BookingStatusConfirmed, TicketsBooked: 2},
+			want:   "Split off another booking with 2 tickets",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.change.Description())
+		})
+	}
+}
+
+func TestBookingActionForStatus(t *testing.T) {
+	assert.Equal(t, BookingConfirmed, BookingActionForStatus(BookingStatusConfirmed))
+	assert.Equal(t, BookingCancelled, BookingActionForStatus(BookingStatusCancelled))
+}
+
+func TestBuildLedger(t *testing.T) {
+	changes := []*BookingChange{
+		{Action: BookingCreated, TicketsBooked: 3},
+		{Action: BookingReserved, TicketsBooked: 2},
+		{Action: BookingConfirmed, TicketsBooked: 2},
+		{Action: BookingExpired, TicketsBooked: 2},
+		{Action: BookingCancelled, TicketsBooked: 3},
+		{Action: BookingCreated, TicketsBooked: 4},
+	}
+
+	entries := BuildLedger(10, changes)
+	deltas := make([]int, len(entries))
+	availableAfter := make([]int, len(entries))
+	for i, entry := range entries {
+		deltas[i] = entry.Delta
+		availableAfter[i] = entry.AvailableAfter
+	}
+	assert.Equal(t, []int{-3, -2, 0, 2, 3, -4}, deltas)
+	assert.Equal(t, []int{7, 5, 5, 7, 10, 6}, availableAfter)
+	assert.Empty(t, BuildLedger(10, nil))
+}
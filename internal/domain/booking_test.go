@@ -2,7 +2,9 @@ package domain
 
 import (
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -72,3 +74,188 @@ func TestNewBooking(t *testing.T) {
 		})
 	}
 }
+
+func TestNewBooking_CreatedBy(t *testing.T) {
+	booking, err := NewBooking(uuid.New(), uuid.New(), 1)
+	assert.NoError(t, err)
+	assert.Empty(t, booking.CreatedBy, "self-service bookings record no admin")
+
+	booking, err = NewBooking(uuid.New(), uuid.New(), 1, WithCreatedBy("jane@support"))
+	assert.NoError(t, err)
+	assert.Equal(t, "jane@support", booking.CreatedBy)
+
+	_, err = NewBooking(uuid.New(), uuid.New(), 1, WithCreatedBy(strings.Repeat("a", MaxBookingActorLen+1)))
+	assert.ErrorIs(t, err, ErrInvalidBookingActor)
+}
+
+func TestBooking_ConditionalTransitions(t *testing.T) {
+	t.Run("conditional booking starts pending", func(t *testing.T) {
+		booking, err := NewBooking(uuid.New(), uuid.New(), 2, AsConditional())
+		assert.NoError(t, err)
+		assert.True(t, booking.Conditional)
+		assert.Equal(t, BookingStatusPending, booking.Status)
+	})
+
+	t.Run("confirms pending booking once", func(t *testing.T) {
+		booking, err := NewBooking(uuid.New(), uuid.New(), 2, AsConditional())
+		assert.NoError(t, err)
+
+		assert.NoError(t, booking.ConfirmConditional())
+		assert.Equal(t, BookingStatusConfirmed, booking.Status)
+		assert.True(t, errors.Is(booking.CancelConditional(), ErrBookingNotPending))
+	})
+
+	t.Run("cancels pending booking", func(t *testing.T) {
+		booking, err := NewBooking(uuid.New(), uuid.New(), 2, AsConditional())
+		assert.NoError(t, err)
+
+		assert.NoError(t, booking.CancelConditional())
+		assert.Equal(t, BookingStatusCancelled, booking.Status)
+	})
+
+	t.Run("refunds a cancelled conditional booking in full", func(t *testing.T) {
+		booking, err := NewBooking(uuid.New(), uuid.New(), 2, AsConditional(), WithBookingPrice(9000, "EUR"))
+		assert.NoError(t, err)
+
+		assert.NoError(t, booking.CancelConditional())
+		assert.Equal(t, int64(9000), booking.RefundCents)
+	})
+
+	t.Run("cancels confirmed and pending bookings once", func(t *testing.T) {
+		confirmed, err := NewBooking(uuid.New(), uuid.New(), 2)
+		assert.NoError(t, err)
+		pending, err := NewBooking(uuid.New(), uuid.New(), 2, AsConditional())
+		assert.NoError(t, err)
+
+		for _, booking := range []*Booking{confirmed, pending} {
+			assert.NoError(t, booking.Cancel(500))
+			assert.Equal(t, BookingStatusCancelled, booking.Status)
+			assert.Equal(t, int64(500), booking.RefundCents)
+			assert.True(t, errors.Is(booking.Cancel(0), ErrBookingAlreadyCancelled))
+			assert.Equal(t, int64(500), booking.RefundCents)
+		}
+	})
+
+	t.Run("regular booking is not pending", func(t *testing.T) {
+		booking, err := NewBooking(uuid.New(), uuid.New(), 2)
+		assert.NoError(t, err)
+
+		assert.Equal(t, BookingStatusConfirmed, booking.Status)
+		assert.True(t, errors.Is(booking.ConfirmConditional(), ErrBookingNotPending))
+	})
+}
+
+func TestBooking_ReservationTransitions(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	deadline := now.Add(15 * time.Minute)
+
+	t.Run("reservation starts pending until its deadline", func(t *testing.T) {
+		booking, err := NewBooking(uuid.New(), uuid.New(), 2, WithExpiry(deadline))
+		assert.NoError(t, err)
+		assert.Equal(t, BookingStatusPending, booking.Status)
+		assert.True(t, booking.IsReservation())
+		assert.False(t, booking.IsExpired(now))
+		assert.True(t, booking.IsExpired(deadline))
+	})
+
+	t.Run("confirms before the deadline", func(t *testing.T) {
+		booking, err := NewBooking(uuid.New(), uuid.New(), 2, WithExpiry(deadline))
+		assert.NoError(t, err)
+
+		assert.NoError(t, booking.ConfirmReservation(deadline.Add(-time.Second)))
+		assert.Equal(t, BookingStatusConfirmed, booking.Status)
+		assert.False(t, booking.IsExpired(deadline), "a confirmed reservation never expires")
+		assert.ErrorIs(t, booking.ConfirmReservation(now), ErrBookingNotPending)
+	})
+
+	t.Run("expires at the deadline", func(t *testing.T) {
+		booking, err := NewBooking(uuid.New(), uuid.New(), 2, WithExpiry(deadline))
+		assert.NoError(t, err)
+
+		assert.ErrorIs(t, booking.ConfirmReservation(deadline), ErrBookingExpired)
+		assert.ErrorIs(t, booking.Expire(now), ErrBookingNotPending)
+		assert.NoError(t, booking.Expire(deadline))
+		assert.Equal(t, BookingStatusCancelled, booking.Status)
+	})
+
+	t.Run("only reservations are confirmed or expired", func(t *testing.T) {
+		booking, err := NewBooking(uuid.New(), uuid.New(), 2, AsConditional())
+		assert.NoError(t, err)
+
+		assert.False(t, booking.IsReservation())
+		assert.ErrorIs(t, booking.ConfirmReservation(now), ErrBookingNotPending)
+		assert.ErrorIs(t, booking.Expire(deadline), ErrBookingNotPending)
+	})
+
+	t.Run("conditional bookings cannot expire", func(t *testing.T) {
+		_, err := NewBooking(uuid.New(), uuid.New(), 2, AsConditional(), WithExpiry(deadline))
+		assert.ErrorIs(t, err, ErrConditionalReservation)
+	})
+}
+
+func TestBooking_Split(t *testing.T) {
+	friend := uuid.New()
+
+	t.Run("moves tickets and a proportional price to the new booking", func(t *testing.T) {
+		allocationID := uuid.New()
+		booking, err := NewBooking(uuid.New(), uuid.New(), 5, WithBookingPrice(1001, "EUR"), WithAllocation(allocationID),
+			WithPayPrice(1101, FXRate{To: "USD", Rate: 1.1}))
+		assert.NoError(t, err)
+
+		split, err := booking.Split(2, friend)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 3, booking.TicketsBooked)
+		assert.Equal(t, 2, split.TicketsBooked)
+		assert.Equal(t, friend, split.UserID)
+		assert.Equal(t, booking.EventID, split.EventID)
+		assert.Equal(t, allocationID, split.AllocationID)
+		assert.Equal(t, BookingStatusConfirmed, split.Status)
+		assert.NotEqual(t, booking.ID, split.ID)
+		assert.NotEqual(t, booking.ConfirmationCode, split.ConfirmationCode)
+
+		assert.Equal(t, int64(400), split.PriceCents)
+		assert.Equal(t, int64(601), booking.PriceCents, "the original keeps the rounding remainder")
+		assert.Equal(t, "EUR", split.Currency)
+		assert.Equal(t, int64(440), split.PayPriceCents)
+		assert.Equal(t, int64(661), booking.PayPriceCents)
+		assert.Equal(t, "USD", split.PayCurrency)
+	})
+
+	t.Run("rejects splits that would leave the original empty", func(t *testing.T) {
+		booking, err := NewBooking(uuid.New(), uuid.New(), 5)
+		assert.NoError(t, err)
+
+		_, err = booking.Split(5, friend)
+		assert.ErrorIs(t, err, ErrSplitTooLarge)
+		_, err = booking.Split(6, friend)
+		assert.ErrorIs(t, err, ErrSplitTooLarge)
+		_, err = booking.Split(0, friend)
+		assert.ErrorIs(t, err, ErrInvalidSplitCount)
+		assert.Equal(t, 5, booking.TicketsBooked, "a rejected split changes nothing")
+	})
+
+	t.Run("rejects a split to the booking's own user", func(t *testing.T) {
+		booking, err := NewBooking(uuid.New(), uuid.New(), 5)
+		assert.NoError(t, err)
+
+		_, err = booking.Split(2, booking.UserID)
+		assert.ErrorIs(t, err, ErrSplitSameUser)
+	})
+
+	t.Run("only confirmed bookings split", func(t *testing.T) {
+		booking, err := NewBooking(uuid.New(), uuid.New(), 5, AsConditional())
+		assert.NoError(t, err)
+
+		_, err = booking.Split(2, friend)
+		assert.ErrorIs(t, err, ErrBookingNotConfirmed)
+	})
+}
+
+func TestValidateReservationTTL(t *testing.T) {
+	assert.NoError(t, ValidateReservationTTL(time.Minute))
+	assert.NoError(t, ValidateReservationTTL(MaxReservationTTL))
+	assert.ErrorIs(t, ValidateReservationTTL(0), ErrInvalidReservationTTL)
+	assert.ErrorIs(t, ValidateReservationTTL(-time.Minute), ErrInvalidReservationTTL)
+	assert.ErrorIs(t, ValidateReservationTTL(MaxReservationTTL+time.Second), ErrInvalidReservationTTL)
+}
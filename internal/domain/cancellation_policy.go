@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RefundTier grants RefundPercent of the paid amount when a booking is cancelled
+// more than MinNotice before the event starts
+type RefundTier struct {
+	MinNotice     time.Duration
+	RefundPercent int
+}
+
+// CancellationPolicy decides how much of a booking's price is refunded on cancellation
+// Tiers are kept ordered from the longest notice to the shortest; the first matching tier wins
+// and cancelling with less notice than every tier refunds nothing
+type CancellationPolicy struct {
+	tiers []RefundTier
+}
+
+// DefaultCancellationPolicy refunds in full more than 7 days before the event,
+// half more than 24 hours before it, and nothing after that
+func DefaultCancellationPolicy() CancellationPolicy {
+	return CancellationPolicy{
+		tiers: []RefundTier{
+			{MinNotice: 7 * 24 * time.Hour, RefundPercent: 100},
+			{MinNotice: 24 * time.Hour, RefundPercent: 50},
+		},
+	}
+}
+
+// NewCancellationPolicy builds a policy from tiers given in any order; a notice must not be negative and
+// a refund must be between 0 and 100 percent. No tiers make a policy that never refunds
+func NewCancellationPolicy(tiers []RefundTier) (CancellationPolicy, error) {
+	sorted := make([]RefundTier, len(tiers))
+	copy(sorted, tiers)
+
+	for _, tier := range sorted {
+		if tier.MinNotice < 0 || tier.RefundPercent < 0 || tier.RefundPercent > 100 {
+			return CancellationPolicy{}, ErrInvalidRefundTier
+		}
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].MinNotice > sorted[j].MinNotice
+	})
+
+	return CancellationPolicy{tiers: sorted}, nil
+}
+
+// ParseCancellationPolicy builds a policy from comma-separated "<notice>=<percent>" tiers, with the notice
+// as a Go duration, e.g. "168h=100,24h=50" for DefaultCancellationPolicy
+func ParseCancellationPolicy(raw string) (CancellationPolicy, error) {
+	var tiers []RefundTier
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		notice, percent, ok := strings.Cut(entry, "=")
+		if !ok {
+			return CancellationPolicy{}, fmt.Errorf("invalid refund tier %q, want <notice>=<percent>", entry)
+		}
+		minNotice, err := time.ParseDuration(strings.TrimSpace(notice))
+		if err != nil {
+			return CancellationPolicy{}, fmt.Errorf("invalid refund tier %q: %w", entry, err)
+		}
+		refundPercent, err := strconv.Atoi(strings.TrimSpace(percent))
+		if err != nil {
+			return CancellationPolicy{}, fmt.Errorf("invalid refund tier %q: %w", entry, err)
+		}
+		tiers = append(tiers, RefundTier{MinNotice: minNotice, RefundPercent: refundPercent})
+	}
+	return NewCancellationPolicy(tiers)
+}
+
+// Tiers returns a copy of the policy tiers ordered from the longest notice to the shortest
+func (p CancellationPolicy) Tiers() []RefundTier {
+	tiers := make([]RefundTier, len(p.tiers))
+	copy(tiers, p.tiers)
+	return tiers
+}
+
+// RefundPercent returns the percentage of the paid amount refunded when cancelling at now
+func (p CancellationPolicy) RefundPercent(eventDate, now time.Time) int {
+	notice := eventDate.Sub(now)
+	for _, tier := range p.tiers {
+		if notice > tier.MinNotice {
+			return tier.RefundPercent
+		}
+	}
+	return 0
+}
+
+// refundPercent is the share of booking's price refunded when it is cancelled at now
+// The tiers only cover cancellations by the user: a cancelled event refunds in full, and a reservation
+// that was never confirmed was never paid, so it refunds nothing
+func (p CancellationPolicy) refundPercent(event *Event, booking *Booking, now time.Time) int {
+	switch {
+	case booking.Status == BookingStatusCancelled:
+		return 0
+	case event.Status == EventStatusCancelled:
+		return 100
+	case booking.Status == BookingStatusPending && booking.IsReservation():
+		return 0
+	}
+	return p.RefundPercent(event.Date, now)
+}
+
+// ComputeRefund returns the refund in cents for cancelling booking, a booking of event, at now
+// Fractions of a cent are rounded down in favour of the organizer; an already cancelled booking refunds nothing
+func (p CancellationPolicy) ComputeRefund(event *Event, booking *Booking, now time.Time) int64 {
+	if booking.PriceCents <= 0 {
+		return 0
+	}
+
+	return booking.PriceCents * int64(p.refundPercent(event, booking, now)) / 100
+}
+
+// Refund is what cancelling a booking at a given time would return to its user
+type Refund struct {
+	Eligible    bool  // Cancelling now refunds a share of the price; false once cancelled or past every tier
+	Percent     int   // Share of the price refunded
+	AmountCents int64 // Refund in the booking's currency; zero for free bookings
+}
+
+// RefundFor returns the refund for cancelling booking, a booking of event, at now
+// Cancelled bookings are not eligible, since their tickets were already returned
+func (p CancellationPolicy) RefundFor(booking *Booking, event *Event, now time.Time) Refund {
+	percent := p.refundPercent(event, booking, now)
+	if percent == 0 {
+		return Refund{}
+	}
+
+	return Refund{
+		Eligible:    true,
+		Percent:     percent,
+		AmountCents: p.ComputeRefund(event, booking, now),
+	}
+}
@@ -0,0 +1,260 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancellationPolicy_ComputeRefund(t *testing.T) {
+	eventDate := time.Date(2025, 9, 20, 19, 0, 0, 0, time.UTC)
+	event := &Event{Name: "Jazz Evening", Location: "Blue Note", Date: eventDate, Tickets: 200}
+
+	tests := []struct {
+		name           string
+		paidCents      int64
+		cancelledAt    time.Time
+		expectedRefund int64
+	}{
+		{
+			name:           "refunds in full well before the event",
+			paidCents:      12000,
+			cancelledAt:    eventDate.Add(-30 * 24 * time.Hour),
+			expectedRefund: 12000,
+		},
+		{
+			name:           "refunds in full one minute beyond seven days",
+			paidCents:      12000,
+			cancelledAt:    eventDate.Add(-7*24*time.Hour - time.Minute),
+			expectedRefund: 12000,
+		},
+		{
+			name:           "refunds half exactly seven days before the event",
+			paidCents:      12000,
+			cancelledAt:    eventDate.Add(-7 * 24 * time.Hour),
+			expectedRefund: 6000,
+		},
+		{
+			name:           "refunds half one minute beyond 24 hours",
+			paidCents:      12000,
+			cancelledAt:    eventDate.Add(-24*time.Hour - time.Minute),
+			expectedRefund: 6000,
+		},
+		{
+			name:           "refunds nothing exactly 24 hours before the event",
+			paidCents:      12000,
+			cancelledAt:    eventDate.Add(-24 * time.Hour),
+			expectedRefund: 0,
+		},
+		{
+			name:           "refunds nothing after the event started",
+			paidCents:      12000,
+			cancelledAt:    eventDate.Add(time.Hour),
+			expectedRefund: 0,
+		},
+		{
+			name:           "rounds fractional cents down",
+			paidCents:      999,
+			cancelledAt:    eventDate.Add(-3 * 24 * time.Hour),
+			expectedRefund: 499,
+		},
+		{
+			name:           "refunds nothing for a free booking",
+			paidCents:      0,
+			cancelledAt:    eventDate.Add(-30 * 24 * time.Hour),
+			expectedRefund: 0,
+		},
+	}
+
+	policy := DefaultCancellationPolicy()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			booking := &Booking{Status: BookingStatusConfirmed, PriceCents: tt.paidCents}
+			refund := policy.ComputeRefund(event, booking, tt.cancelledAt)
+			assert.Equal(t, tt.expectedRefund, refund)
+		})
+	}
+}
+
+func TestNewCancellationPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		tiers         []RefundTier
+		wantErr       bool
+		errType       error
+		expectedTiers []RefundTier
+	}{
+		{
+			name: "orders tiers from the longest notice",
+			tiers: []RefundTier{
+				{MinNotice: 48 * time.Hour, RefundPercent: 25},
+				{MinNotice: 14 * 24 * time.Hour, RefundPercent: 90},
+			},
+			wantErr: false,
+			expectedTiers: []RefundTier{
+				{MinNotice: 14 * 24 * time.Hour, RefundPercent: 90},
+				{MinNotice: 48 * time.Hour, RefundPercent: 25},
+			},
+		},
+		{
+			name:          "accepts a no-refund policy",
+			tiers:         nil,
+			wantErr:       false,
+			expectedTiers: []RefundTier{},
+		},
+		{
+			name:    "returns error for refund above 100 percent",
+			tiers:   []RefundTier{{MinNotice: 24 * time.Hour, RefundPercent: 120}},
+			wantErr: true,
+			errType: ErrInvalidRefundTier,
+		},
+		{
+			name:    "returns error for negative notice",
+			tiers:   []RefundTier{{MinNotice: -time.Hour, RefundPercent: 50}},
+			wantErr: true,
+			errType: ErrInvalidRefundTier,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := NewCancellationPolicy(tt.tiers)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.errType))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedTiers, policy.Tiers())
+			}
+		})
+	}
+}
+
+func TestCancellationPolicy_CustomTiers(t *testing.T) {
+	eventDate := time.Date(2025, 11, 2, 20, 0, 0, 0, time.UTC)
+	event := &Event{Name: "Derby Final", Location: "City Stadium", Date: eventDate, Tickets: 40000}
+
+	policy, err := NewCancellationPolicy([]RefundTier{
+		{MinNotice: 0, RefundPercent: 80},
+	})
+	assert.NoError(t, err)
+
+	booking := &Booking{Status: BookingStatusConfirmed, PriceCents: 5000}
+	assert.Equal(t, int64(4000), policy.ComputeRefund(event, booking, eventDate.Add(-time.Minute)))
+	assert.Equal(t, int64(0), policy.ComputeRefund(event, booking, eventDate))
+}
+
+func TestCancellationPolicy_ComputeRefundOutsideTiers(t *testing.T) {
+	eventDate := time.Date(2025, 9, 20, 19, 0, 0, 0, time.UTC)
+	lastMinute := eventDate.Add(-time.Hour)
+	policy := DefaultCancellationPolicy()
+
+	t.Run("refunds in full when the event is cancelled", func(t *testing.T) {
+		event := &Event{Name: "Jazz Evening", Location: "Blue Note", Date: eventDate, Tickets: 200}
+		assert.NoError(t, event.Cancel(lastMinute))
+		booking := &Booking{Status: BookingStatusConfirmed, PriceCents: 8000}
+
+		assert.Equal(t, int64(8000), policy.ComputeRefund(event, booking, lastMinute))
+	})
+
+	t.Run("refunds nothing for an unconfirmed reservation", func(t *testing.T) {
+		event := &Event{Name: "Jazz Evening", Location: "Blue Note", Date: eventDate, Tickets: 200}
+		booking := &Booking{Status: BookingStatusPending, PriceCents: 8000, ExpiresAt: eventDate.Add(-29 * 24 * time.Hour)}
+
+		assert.Equal(t, int64(0), policy.ComputeRefund(event, booking, eventDate.Add(-30*24*time.Hour)))
+	})
+
+	t.Run("refunds nothing for a booking already cancelled", func(t *testing.T) {
+		event := &Event{Name: "Jazz Evening", Location: "Blue Note", Date: eventDate, Tickets: 200}
+		booking := &Booking{Status: BookingStatusCancelled, PriceCents: 8000}
+
+		assert.Equal(t, int64(0), policy.ComputeRefund(event, booking, eventDate.Add(-30*24*time.Hour)))
+	})
+}
+
+func TestCancellationPolicy_RefundFor(t *testing.T) {
+	eventDate := time.Date(2025, 9, 20, 19, 0, 0, 0, time.UTC)
+	event := &Event{Name: "Jazz Evening", Location: "Blue Note", Date: eventDate, Tickets: 200}
+
+	tests := []struct {
+		name     string
+		status   BookingStatus
+		price    int64
+		now      time.Time
+		expected Refund
+	}{
+		{
+			name:     "eligible in full a month before the event",
+			status:   BookingStatusConfirmed,
+			price:    8000,
+			now:      eventDate.Add(-30 * 24 * time.Hour),
+			expected: Refund{Eligible: true, Percent: 100, AmountCents: 8000},
+		},
+		{
+			name:     "eligible for half three days before the event",
+			status:   BookingStatusConfirmed,
+			price:    8000,
+			now:      eventDate.Add(-3 * 24 * time.Hour),
+			expected: Refund{Eligible: true, Percent: 50, AmountCents: 4000},
+		},
+		{
+			name:     "not eligible twelve hours before the event",
+			status:   BookingStatusConfirmed,
+			price:    8000,
+			now:      eventDate.Add(-12 * time.Hour),
+			expected: Refund{},
+		},
+		{
+			name:     "not eligible after the event started",
+			status:   BookingStatusConfirmed,
+			price:    8000,
+			now:      eventDate.Add(time.Hour),
+			expected: Refund{},
+		},
+		{
+			name:     "pending conditional bookings are eligible",
+			status:   BookingStatusPending,
+			price:    8000,
+			now:      eventDate.Add(-30 * 24 * time.Hour),
+			expected: Refund{Eligible: true, Percent: 100, AmountCents: 8000},
+		},
+		{
+			name:     "cancelled bookings are not eligible",
+			status:   BookingStatusCancelled,
+			price:    8000,
+			now:      eventDate.Add(-30 * 24 * time.Hour),
+			expected: Refund{},
+		},
+		{
+			name:     "free bookings are eligible for nothing",
+			status:   BookingStatusConfirmed,
+			price:    0,
+			now:      eventDate.Add(-30 * 24 * time.Hour),
+			expected: Refund{Eligible: true, Percent: 100, AmountCents: 0},
+		},
+	}
+
+	policy := DefaultCancellationPolicy()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			booking := &Booking{Status: tt.status, PriceCents: tt.price, Currency: "EUR"}
+			assert.Equal(t, tt.expected, policy.RefundFor(booking, event, tt.now))
+		})
+	}
+}
+
+func TestParseCancellationPolicy(t *testing.T) {
+	policy, err := ParseCancellationPolicy(" 24h=50, 168h=100 ,")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultCancellationPolicy().Tiers(), policy.Tiers())
+
+	for _, raw := range []string{"24h", "1d=50", "24h=half"} {
+		_, err := ParseCancellationPolicy(raw)
+		assert.Error(t, err, raw)
+	}
+	_, err = ParseCancellationPolicy("24h=150")
+	assert.True(t, errors.Is(err, ErrInvalidRefundTier))
+}
@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// DefaultClockSkew is how far apart app servers' clocks may drift before event date rules notice
+const DefaultClockSkew = time.Minute
+
+// Clock is the time source for event date rules: past-event, booking cutoff and viability deadline checks
+// Rules give requests the benefit of the doubt and are checked at RuleTime, Skew before the clock's time,
+// so a server whose clock runs ahead never refuses what another server would accept
+type Clock struct {
+	now  func() time.Time
+	skew time.Duration
+}
+
+// NewClock reads the time from now and tolerates skew between servers; a negative skew is treated as zero
+func NewClock(now func() time.Time, skew time.Duration) Clock {
+	return Clock{now: now, skew: max(skew, 0)}
+}
+
+// SystemClock reads the system time with DefaultClockSkew
+func SystemClock() Clock {
+	return NewClock(time.Now, DefaultClockSkew)
+}
+
+// Now is the current time, for recording when something happened
+func (c Clock) Now() time.Time {
+	return c.now()
+}
+
+// RuleTime is the time event date rules are checked at
+func (c Clock) RuleTime() time.Time {
+	return c.now().Add(-c.skew)
+}
+
+// Skew is the tolerated clock difference between servers
+func (c Clock) Skew() time.Duration {
+	return c.skew
+}
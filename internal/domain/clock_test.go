@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClock(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	clock := NewClock(func() time.Time { return now }, 30*time.Second)
+	assert.Equal(t, now, clock.Now())
+	assert.Equal(t, now.Add(-30*time.Second), clock.RuleTime())
+
+	exact := NewClock(func() time.Time { return now }, -time.Second)
+	assert.Equal(t, now, exact.RuleTime(), "negative skew is treated as none")
+}
+
+// TestClock_SkewBoundaries checks each event date rule right at and just past the tolerance
+func TestClock_SkewBoundaries(t *testing.T) {
+	const skew = time.Minute
+	date := time.Date(2026, 6, 1, 20, 0, 0, 0, time.UTC)
+	clockAt := func(now time.Time) Clock {
+		return NewClock(func() time.Time { return now }, skew)
+	}
+
+	t.Run("past event", func(t *testing.T) {
+		event, err := NewEvent("Gala", "Hall", date, 10)
+		require.NoError(t, err)
+
+		assert.NoError(t, event.CheckFutureDate(clockAt(date.Add(skew)).RuleTime()))
+		assert.ErrorIs(t, event.CheckFutureDate(clockAt(date.Add(skew+time.Second)).RuleTime()), ErrEventDateInPast)
+	})
+
+	t.Run("booking cutoff", func(t *testing.T) {
+		event, err := NewEvent("Gala", "Hall", date, 10, WithMinAdvance(time.Hour))
+		require.NoError(t, err)
+		cutoff := date.Add(-time.Hour)
+
+		assert.NoError(t, event.CheckBookingWindow(clockAt(cutoff.Add(skew)).RuleTime()))
+		assert.ErrorIs(t, event.CheckBookingWindow(clockAt(cutoff.Add(skew+time.Second)).RuleTime()), ErrBookingTooLate)
+	})
+
+	t.Run("viability deadline", func(t *testing.T) {
+		deadline := date.Add(-24 * time.Hour)
+		event, err := NewEvent("Gala", "Hall", date, 10, WithMinViable(5, deadline))
+		require.NoError(t, err)
+
+		assert.NoError(t, event.CheckConditionalBooking(clockAt(deadline.Add(skew-time.Second)).RuleTime()))
+		assert.ErrorIs(t, event.CheckConditionalBooking(clockAt(deadline.Add(skew)).RuleTime()), ErrViabilityDeadlinePassed)
+
+		_, err = event.ResolveViability(0, clockAt(deadline.Add(skew-time.Second)).RuleTime())
+		assert.ErrorIs(t, err, ErrViabilityUndecided, "bookings accepted within the tolerance still count")
+		viable, err := event.ResolveViability(0, clockAt(deadline.Add(skew)).RuleTime())
+		require.NoError(t, err)
+		assert.False(t, viable)
+	})
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"crypto/rand"
+	"strings"
+)
+
+const (
+	// ConfirmationCodeLength is the length of newly generated codes
+	// Bookings that existed before codes were introduced carry a longer backfilled code
+	ConfirmationCodeLength = 8
+	// MinConfirmationCodePrefix keeps prefix searches from scanning a large share of all bookings
+	MinConfirmationCodePrefix = 3
+	// maxConfirmationCodeLength matches the stored column
+	maxConfirmationCodeLength = 16
+)
+
+// confirmationCodeAlphabet leaves out 0, O, 1 and I, which customers confuse when reading codes out
+// Its 32 characters let each random byte be reduced with a mask without bias
+const confirmationCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// NewConfirmationCode returns a random human-friendly code identifying a booking to customers and support
+func NewConfirmationCode() string {
+	buf := make([]byte, ConfirmationCodeLength)
+	// rand.Read only fails when the system random source does, which uuid.New treats as fatal too
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	for i, b := range buf {
+		buf[i] = confirmationCodeAlphabet[b&31]
+	}
+	return string(buf)
+}
+
+// NormalizeConfirmationCodePrefix upper-cases a partial code for a case-insensitive search
+// Only letters and digits are accepted, so the prefix can be used in a LIKE pattern without escaping
+func NormalizeConfirmationCodePrefix(prefix string) (string, error) {
+	prefix = strings.ToUpper(strings.TrimSpace(prefix))
+	if len(prefix) < MinConfirmationCodePrefix {
+		return "", ErrConfirmationCodePrefixTooShort
+	}
+	if len(prefix) > maxConfirmationCodeLength {
+		return "", ErrInvalidConfirmationCodePrefix
+	}
+	for _, r := range prefix {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return "", ErrInvalidConfirmationCodePrefix
+		}
+	}
+	return prefix, nil
+}
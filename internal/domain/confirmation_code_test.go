@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConfirmationCode(t *testing.T) {
+	seen := make(map[string]bool)
+	for range 100 {
+		code := NewConfirmationCode()
+
+		assert.Len(t, code, ConfirmationCodeLength)
+		for _, r := range code {
+			assert.True(t, strings.ContainsRune(confirmationCodeAlphabet, r), "unexpected character %q in %s", r, code)
+		}
+		assert.False(t, seen[code], "duplicate code %s", code)
+		seen[code] = true
+	}
+}
+
+func TestNormalizeConfirmationCodePrefix(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  string
+		want    string
+		wantErr error
+	}{
+		{name: "upper-cases prefix", prefix: "abc", want: "ABC"},
+		{name: "trims whitespace", prefix: " k7m2 ", want: "K7M2"},
+		{name: "accepts full backfilled code", prefix: strings.Repeat("A", maxConfirmationCodeLength), want: strings.Repeat("A", maxConfirmationCodeLength)},
+		{name: "rejects too short prefix", prefix: "ab", wantErr: ErrConfirmationCodePrefixTooShort},
+		{name: "rejects empty prefix", prefix: "", wantErr: ErrConfirmationCodePrefixTooShort},
+		{name: "rejects LIKE wildcards", prefix: "AB%", wantErr: ErrInvalidConfirmationCodePrefix},
+		{name: "rejects too long prefix", prefix: strings.Repeat("A", maxConfirmationCodeLength+1), wantErr: ErrInvalidConfirmationCodePrefix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeConfirmationCodePrefix(tt.prefix)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// MaxDiscountCodeLength bounds codes to the size of the stored column
+const MaxDiscountCodeLength = 32
+
+// DiscountCode reduces a booking's price either by PercentOff or by AmountOffCents, never both
+// Each redemption uses one of MaxUses; the code stops working at ExpiresAt
+type DiscountCode struct {
+	Code           string
+	PercentOff     int
+	AmountOffCents int64
+	Currency       string // Currency of AmountOffCents; empty for percentage codes
+	MaxUses        int
+	Uses           int
+	ExpiresAt      time.Time // Zero means the code never expires
+}
+
+func NewDiscountCode(code string, percentOff int, amountOffCents int64, currency string, maxUses int, expiresAt time.Time) (*DiscountCode, error) {
+	code, err := NormalizeDiscountCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if (percentOff == 0) == (amountOffCents == 0) || percentOff < 0 || percentOff > 100 ||
+		amountOffCents < 0 || amountOffCents > MaxPriceCents {
+		return nil, ErrInvalidDiscount
+	}
+
+	currency = strings.ToUpper(currency)
+	if amountOffCents > 0 && !isCurrencyCode(currency) {
+		return nil, ErrInvalidCurrency
+	}
+	if percentOff > 0 {
+		currency = ""
+	}
+	if maxUses <= 0 {
+		return nil, ErrInvalidDiscountMaxUses
+	}
+
+	return &DiscountCode{
+		Code:           code,
+		PercentOff:     percentOff,
+		AmountOffCents: amountOffCents,
+		Currency:       currency,
+		MaxUses:        maxUses,
+		ExpiresAt:      expiresAt,
+	}, nil
+}
+
+// NormalizeDiscountCode upper-cases code so customers can type it in any case
+func NormalizeDiscountCode(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" || len(code) > MaxDiscountCodeLength {
+		return "", ErrInvalidDiscountCode
+	}
+	for _, r := range code {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return "", ErrInvalidDiscountCode
+		}
+	}
+	return code, nil
+}
+
+// Redeem uses up one of the code's remaining uses at now
+func (d *DiscountCode) Redeem(now time.Time) error {
+	if !d.ExpiresAt.IsZero() && !now.Before(d.ExpiresAt) {
+		return ErrDiscountCodeExpired
+	}
+	if d.Uses >= d.MaxUses {
+		return ErrDiscountCodeExhausted
+	}
+
+	d.Uses++
+	return nil
+}
+
+// Apply returns priceCents after the discount; amount-off codes only apply in their own currency
+// Percentages round the discount down, and the price never drops below zero
+func (d *DiscountCode) Apply(priceCents int64, currency string) (int64, error) {
+	if priceCents == 0 {
+		return 0, ErrDiscountNotApplicable
+	}
+	if d.PercentOff > 0 {
+		return priceCents - priceCents*int64(d.PercentOff)/100, nil
+	}
+	if d.Currency != currency {
+		return 0, ErrDiscountNotApplicable
+	}
+	return max(priceCents-d.AmountOffCents, 0), nil
+}
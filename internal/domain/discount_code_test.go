@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDiscountCode(t *testing.T) {
+	tests := []struct {
+		name           string
+		code           string
+		percentOff     int
+		amountOffCents int64
+		currency       string
+		maxUses        int
+		wantCode       string
+		wantCurrency   string
+		wantErr        error
+	}{
+		{name: "percentage code", code: "spring-10", percentOff: 10, maxUses: 5, wantCode: "SPRING-10"},
+		{name: "percentage code drops currency", code: "HALF", percentOff: 50, currency: "EUR", maxUses: 1, wantCode: "HALF"},
+		{name: "amount code", code: "FIVER", amountOffCents: 500, currency: "eur", maxUses: 1, wantCode: "FIVER", wantCurrency: "EUR"},
+		{name: "rejects empty code", code: " ", percentOff: 10, maxUses: 1, wantErr: ErrInvalidDiscountCode},
+		{name: "rejects invalid characters", code: "10%OFF", percentOff: 10, maxUses: 1, wantErr: ErrInvalidDiscountCode},
+		{name: "rejects code above length bound", code: "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456", percentOff: 10, maxUses: 1, wantErr: ErrInvalidDiscountCode},
+		{name: "rejects no discount", code: "NOTHING", maxUses: 1, wantErr: ErrInvalidDiscount},
+		{name: "rejects both discounts", code: "BOTH", percentOff: 10, amountOffCents: 500, currency: "EUR", maxUses: 1, wantErr: ErrInvalidDiscount},
+		{name: "rejects percentage above 100", code: "TOOMUCH", percentOff: 101, maxUses: 1, wantErr: ErrInvalidDiscount},
+		{name: "rejects amount without currency", code: "FIVER", amountOffCents: 500, maxUses: 1, wantErr: ErrInvalidCurrency},
+		{name: "rejects zero max uses", code: "NEVER", percentOff: 10, maxUses: 0, wantErr: ErrInvalidDiscountMaxUses},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := NewDiscountCode(tt.code, tt.percentOff, tt.amountOffCents, tt.currency, tt.maxUses, time.Time{})
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr))
+				assert.Nil(t, code)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCode, code.Code)
+			assert.Equal(t, tt.wantCurrency, code.Currency)
+			assert.Zero(t, code.Uses)
+		})
+	}
+}
+
+func TestDiscountCode_Redeem(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("counts uses up to max", func(t *testing.T) {
+		code := &DiscountCode{Code: "TWICE", PercentOff: 10, MaxUses: 2}
+
+		require.NoError(t, code.Redeem(now))
+		require.NoError(t, code.Redeem(now))
+		assert.ErrorIs(t, code.Redeem(now), ErrDiscountCodeExhausted)
+		assert.Equal(t, 2, code.Uses)
+	})
+
+	t.Run("rejects expired code", func(t *testing.T) {
+		code := &DiscountCode{Code: "OLD", PercentOff: 10, MaxUses: 2, ExpiresAt: now}
+
+		assert.ErrorIs(t, code.Redeem(now), ErrDiscountCodeExpired)
+		assert.Zero(t, code.Uses)
+	})
+
+	t.Run("redeems before expiry", func(t *testing.T) {
+		code := &DiscountCode{Code: "SOON", PercentOff: 10, MaxUses: 1, ExpiresAt: now.Add(time.Minute)}
+
+		assert.NoError(t, code.Redeem(now))
+	})
+}
+
+func TestDiscountCode_Apply(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     DiscountCode
+		price    int64
+		currency string
+		want     int64
+		wantErr  error
+	}{
+		{name: "percentage", code: DiscountCode{PercentOff: 10}, price: 5000, currency: "EUR", want: 4500},
+		{name: "percentage rounds discount down", code: DiscountCode{PercentOff: 10}, price: 999, currency: "EUR", want: 900},
+		{name: "full percentage", code: DiscountCode{PercentOff: 100}, price: 5000, currency: "EUR", want: 0},
+		{name: "amount", code: DiscountCode{AmountOffCents: 500, Currency: "EUR"}, price: 5000, currency: "EUR", want: 4500},
+		{name: "amount never below zero", code: DiscountCode{AmountOffCents: 500, Currency: "EUR"}, price: 300, currency: "EUR", want: 0},
+		{name: "amount in other currency", code: DiscountCode{AmountOffCents: 500, Currency: "USD"}, price: 5000, currency: "EUR", wantErr: ErrDiscountNotApplicable},
+		{name: "free booking", code: DiscountCode{PercentOff: 10}, price: 0, wantErr: ErrDiscountNotApplicable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.code.Apply(tt.price, tt.currency)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -1,13 +1,117 @@
 package domain
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 var (
-	ErrEventNotFound          = &NotFoundError{Entity: "event"}
-	ErrBookingNotFound        = &NotFoundError{Entity: "booking"}
-	ErrInsufficientTickets    = &ConflictError{Message: "insufficient tickets available"}
-	ErrInvalidTicketCount     = &ValidationError{Field: "tickets_booked", Message: "must be greater than 0"}
-	ErrInvalidAvailableTickets = &ValidationError{Field: "available_tickets", Message: "cannot be negative"}
+	ErrEventNotFound                  = &NotFoundError{Entity: "event"}
+	ErrHoldNotFound                   = &NotFoundError{Entity: "hold"}
+	ErrBookingNotFound                = &NotFoundError{Entity: "booking"}
+	ErrIdempotencyKeyNotFound         = &NotFoundError{Entity: "idempotency key"}
+	ErrAvailabilityHistoryNotFound    = &NotFoundError{Entity: "availability history"}
+	ErrWebhookNotFound                = &NotFoundError{Entity: "webhook"}
+	ErrAllocationNotFound             = &NotFoundError{Entity: "allocation"}
+	ErrDeliveryFailureNotFound        = &NotFoundError{Entity: "dead letter"}
+	ErrInsufficientTickets            = &ConflictError{Message: "insufficient tickets available"}
+	ErrNothingToBuyOut                = &ConflictError{Message: "no tickets left to buy out"}
+	ErrAvailabilityExists             = &ConflictError{Message: "ticket availability already exists for event"}
+	ErrEventCancelled                 = &ConflictError{Message: "event is cancelled"}
+	ErrEventAlreadyCancelled          = &ConflictError{Message: "event is already cancelled"}
+	ErrBookingPaused                  = &ConflictError{Message: "bookings for the event are paused"}
+	ErrBookingTooLate                 = &ConflictError{Message: "bookings are closed within the event's minimum advance window"}
+	ErrAvailabilityUnderflow          = &ConflictError{Message: "adjustment would drop available tickets below zero"}
+	ErrAvailabilityOverflow           = &ConflictError{Message: "adjustment would raise available tickets above the event's total"}
+	ErrHoldAlreadyConfirmed           = &ConflictError{Message: "hold is already confirmed"}
+	ErrHoldNotActive                  = &ConflictError{Message: "hold is no longer active"}
+	ErrHoldExpiryAlreadyNotified      = &ConflictError{Message: "hold expiry was already notified"}
+	ErrDiscountCodeExhausted          = &ConflictError{Message: "discount code has no uses left"}
+	ErrDiscountCodeExists             = &ConflictError{Message: "discount code already exists"}
+	ErrAllocationExists               = &ConflictError{Message: "allocation already exists for event"}
+	ErrAllocationExceedsAvailable     = &ConflictError{Message: "allocation exceeds the event's available tickets"}
+	ErrLotteryEntryExists             = &ConflictError{Message: "user is already registered for the event's lottery"}
+	ErrLotteryAlreadyDrawn            = &ConflictError{Message: "event's lottery was already drawn"}
+	ErrLotteryOnly                    = &ConflictError{Message: "event's tickets are only sold through its lottery"}
+	ErrDeliveryFailureNotReplayable   = &ConflictError{Message: "dead letter was stored without its payload and cannot be retried"}
+	ErrNoLottery                      = &ConflictError{Message: "event does not hold a lottery"}
+	ErrMergeOverbooks                 = &ConflictError{Message: "target event has too few available tickets for the merged bookings"}
+	ErrHoldExpired                    = &ExpiredError{Entity: "hold"}
+	ErrDiscountCodeExpired            = &ExpiredError{Entity: "discount code"}
+	ErrBookingExpired                 = &ExpiredError{Entity: "booking"}
+	ErrViabilityUndecided             = &ConflictError{Message: "minimum group size not reached and viability deadline has not passed"}
+	ErrViabilityDeadlinePassed        = &ConflictError{Message: "viability deadline has passed, conditional bookings are closed"}
+	ErrBookingNotPending              = &ConflictError{Message: "booking is not pending"}
+	ErrBookingAlreadyCancelled        = &ConflictError{Message: "booking is already cancelled"}
+	ErrBookingNotConfirmed            = &ConflictError{Message: "booking is not confirmed"}
+	ErrSplitSeated                    = &ConflictError{Message: "bookings with reserved seats cannot be split"}
+	ErrSeatTaken                      = &ConflictError{Message: "one or more selected seats are already taken"}
+	ErrIdempotencyKeyReused           = &ConflictError{Message: "idempotency key was already used for a different request"}
+	ErrAvailabilityVersionMismatch    = &PreconditionFailedError{Message: "ticket availability has changed since it was read"}
+	ErrServiceUnavailable             = &UnavailableError{Message: "database is unavailable, please retry later"}
+	ErrFXRateStale                    = &UnavailableError{Message: "exchange rate is out of date, please retry later"}
+	ErrEventBusy                      = &UnavailableError{Message: "too many bookings for the event in progress, please retry shortly", RetryAfter: time.Second}
+	ErrTooManyTransactions            = &UnavailableError{Message: "too many requests in progress, please retry shortly", RetryAfter: time.Second}
+	ErrInvalidTicketCount             = &ValidationError{Field: "tickets_booked", Message: "must be greater than 0"}
+	ErrTicketCountTooLarge            = &ValidationError{Field: "tickets_booked", Message: fmt.Sprintf("must not exceed %d", MaxTickets)}
+	ErrTicketsTooLarge                = &ValidationError{Field: "tickets", Message: fmt.Sprintf("must not exceed %d", MaxTickets)}
+	ErrExceedsCapacity                = &ValidationError{Field: "tickets_booked", Message: "exceeds the event's total capacity"}
+	ErrAvailabilityDeltaTooLarge      = &ValidationError{Field: "delta", Message: fmt.Sprintf("must be between -%d and %d", MaxTickets, MaxTickets)}
+	ErrInvalidAvailableTickets        = &ValidationError{Field: "available_tickets", Message: "cannot be negative"}
+	ErrEventDateInPast                = &ValidationError{Field: "date", Message: "must be in the future"}
+	ErrInvalidEndDate                 = &ValidationError{Field: "end_date", Message: "must be after date"}
+	ErrEndDateAndDuration             = &ValidationError{Field: "end_date", Message: "set end_date or duration, not both"}
+	ErrInvalidMinAdvance              = &ValidationError{Field: "min_advance", Message: "cannot be negative"}
+	ErrInvalidAvailabilityDelta       = &ValidationError{Field: "delta", Message: "must not be 0"}
+	ErrInvalidSplitCount              = &ValidationError{Field: "split_count", Message: "must be greater than 0"}
+	ErrSplitTooLarge                  = &ValidationError{Field: "split_count", Message: "must be less than the booking's tickets"}
+	ErrSplitSameUser                  = &ValidationError{Field: "new_user_id", Message: "must differ from the booking's user"}
+	ErrInvalidIdempotencyKey          = &ValidationError{Field: "Idempotency-Key", Message: fmt.Sprintf("must be non-blank and at most %d characters", MaxIdempotencyKeyLength)}
+	ErrInvalidIdempotencyKeyTTL       = &ValidationError{Field: "idempotency_key_ttl", Message: "must be greater than 0"}
+	ErrConfirmationCodePrefixTooShort = &ValidationError{Field: "code_prefix", Message: fmt.Sprintf("must be at least %d characters", MinConfirmationCodePrefix)}
+	ErrInvalidConfirmationCodePrefix  = &ValidationError{Field: "code_prefix", Message: fmt.Sprintf("must contain only letters and digits, at most %d", maxConfirmationCodeLength)}
+	ErrInvalidHoldTTL                 = &ValidationError{Field: "hold_ttl", Message: "must be greater than 0"}
+	ErrInvalidReservationTTL          = &ValidationError{Field: "expires_in", Message: fmt.Sprintf("must be greater than 0 and at most %s", MaxReservationTTL)}
+	ErrConditionalReservation         = &ValidationError{Field: "expires_in", Message: "conditional bookings cannot expire"}
+	ErrInvalidQuantityStep            = &ValidationError{Field: "quantity_step", Message: "must be at least 1 and not exceed tickets"}
+	ErrTicketsNotMultipleOfStep       = &ValidationError{Field: "tickets_booked", Message: "must be a multiple of the event's quantity_step"}
+	ErrInvalidMinViable               = &ValidationError{Field: "min_viable", Message: "must be between 0 and tickets and requires a viability deadline"}
+	ErrConditionalNotSupported        = &ValidationError{Field: "conditional", Message: "event has no minimum group size"}
+	ErrInvalidSeatMap                 = &ValidationError{Field: "seats", Message: "seat labels must be non-empty, unique and match the ticket count"}
+	ErrInvalidSeatSelection           = &ValidationError{Field: "seats", Message: "seat labels must be non-empty, unique and match tickets_booked"}
+	ErrSeatLabelTooLong               = &ValidationError{Field: "seats", Message: fmt.Sprintf("seat labels must be at most %d characters", MaxSeatLabelLen)}
+	ErrUnknownSeat                    = &ValidationError{Field: "seats", Message: "seat does not exist for this event"}
+	ErrSeatSelectionRequired          = &ValidationError{Field: "seats", Message: "event has reserved seating, select seats to book"}
+	ErrSeatingNotSupported            = &ValidationError{Field: "seats", Message: "event has no reserved seating"}
+	ErrBuyoutSeated                   = &ValidationError{Field: "seats", Message: "events with reserved seating cannot be bought out"}
+	ErrLotterySeated                  = &ValidationError{Field: "seats", Message: "events with reserved seating cannot hold a lottery"}
+	ErrMergeSameEvent                 = &ValidationError{Field: "target_id", Message: "must differ from source_id"}
+	ErrMergeSeated                    = &ValidationError{Field: "source_id", Message: "events with reserved seating cannot be merged"}
+	ErrInvalidLotteryWeight           = &ValidationError{Field: "weights", Message: fmt.Sprintf("must be between 1 and %d", MaxLotteryWeight)}
+	ErrInvalidBookingActor            = &ValidationError{Field: "created_by", Message: fmt.Sprintf("must be 1-%d characters", MaxBookingActorLen)}
+	ErrEmptyCart                      = &ValidationError{Field: "items", Message: "must contain at least one event"}
+	ErrDuplicateCartEvent             = &ValidationError{Field: "items", Message: "each event may appear only once"}
+	ErrEmptyAvailabilityCheck         = &ValidationError{Field: "items", Message: "must contain at least one event"}
+	ErrTooManyAvailabilityChecks      = &ValidationError{Field: "items", Message: fmt.Sprintf("must not contain more than %d events", MaxAvailabilityChecks)}
+	ErrInvalidTickets                 = &ValidationError{Field: "tickets", Message: "must be greater than 0"}
+	ErrInvalidPrice                   = &ValidationError{Field: "price_cents", Message: fmt.Sprintf("must be between 0 and %d", MaxPriceCents)}
+	ErrInvalidCurrency                = &ValidationError{Field: "currency", Message: "must be a 3-letter ISO 4217 code and is required for paid events"}
+	ErrInvalidPayCurrency             = &ValidationError{Field: "pay_currency", Message: "must be a 3-letter ISO 4217 code"}
+	ErrUnsupportedCurrency            = &ValidationError{Field: "pay_currency", Message: "no exchange rate is available for this currency"}
+	ErrInvalidDiscountCode            = &ValidationError{Field: "discount_code", Message: fmt.Sprintf("must be 1 to %d letters, digits, '-' or '_'", MaxDiscountCodeLength)}
+	ErrInvalidDiscount                = &ValidationError{Field: "discount", Message: "set exactly one of percent_off (1 to 100) or amount_off_cents"}
+	ErrInvalidDiscountMaxUses         = &ValidationError{Field: "max_uses", Message: "must be greater than 0"}
+	ErrDiscountNotApplicable          = &ValidationError{Field: "discount_code", Message: "code does not apply to this event's price or currency"}
+	ErrDiscountCodeNotFound           = &ValidationError{Field: "discount_code", Message: "code does not exist"}
+	ErrInvalidImageURL                = &ValidationError{Field: "image_url", Message: fmt.Sprintf("must be an absolute http or https URL of at most %d characters", MaxImageURLLen)}
+	ErrInvalidThumbnailURL            = &ValidationError{Field: "thumbnail_url", Message: "must be an absolute http or https URL and requires image_url"}
+	ErrInvalidWebhookURL              = &ValidationError{Field: "url", Message: fmt.Sprintf("must be an absolute http or https URL of at most %d characters", MaxWebhookURLLen)}
+	ErrWebhookHostNotAllowed          = &ValidationError{Field: "url", Message: "must not point at a loopback, link-local or private address"}
+	ErrInvalidWebhookSecret           = &ValidationError{Field: "secret", Message: fmt.Sprintf("must be %d to %d characters", MinWebhookSecretLen, MaxWebhookSecretLen)}
+	ErrInvalidTags                    = &ValidationError{Field: "tags", Message: fmt.Sprintf("at most %d tags of 1 to %d letters, digits or '-'", MaxEventTags, MaxEventTagLen)}
+	ErrInvalidTagMatch                = &ValidationError{Field: "tag_mode", Message: "must be any or all"}
+	ErrInvalidAllocationName          = &ValidationError{Field: "allocation", Message: fmt.Sprintf("must be 1 to %d letters, digits or '-' and not %q", MaxAllocationNameLen, PublicAllocation)}
+	ErrInvalidRefundTier              = &ValidationError{Field: "refund_tiers", Message: "notice must not be negative and refund percent must be between 0 and 100"}
 )
 
 type NotFoundError struct {
@@ -34,3 +138,32 @@ type ConflictError struct {
 func (e *ConflictError) Error() string {
 	return fmt.Sprintf("conflict: %s", e.Message)
 }
+
+// ExpiredError marks a resource that existed but can no longer be acted on
+type ExpiredError struct {
+	Entity string
+}
+
+func (e *ExpiredError) Error() string {
+	return fmt.Sprintf("%s has expired", e.Entity)
+}
+
+// PreconditionFailedError marks a write whose caller expected a version of the resource that is no longer current
+type PreconditionFailedError struct {
+	Message string
+}
+
+func (e *PreconditionFailedError) Error() string {
+	return fmt.Sprintf("precondition failed: %s", e.Message)
+}
+
+// UnavailableError marks a dependency that is temporarily refusing work
+// RetryAfter, when set, is how long clients should wait before retrying
+type UnavailableError struct {
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *UnavailableError) Error() string {
+	return fmt.Sprintf("service unavailable: %s", e.Message)
+}
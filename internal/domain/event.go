@@ -1,31 +1,377 @@
 package domain
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type EventStatus string
+
+const (
+	EventStatusActive    EventStatus = "active"
+	EventStatusCancelled EventStatus = "cancelled"
+)
+
 // Event is a data container for event metadata
 // It does not contain booking business logic - that is handled by TicketAvailability aggregate
 type Event struct {
-	ID       uuid.UUID
-	Name     string
-	Date     time.Time
-	Location string
-	Tickets  int // Total tickets (immutable reference)
+	ID          uuid.UUID
+	Name        string
+	Date        time.Time
+	EndDate     time.Time // After Date; zero when the end is unknown
+	Location    string
+	Tickets     int           // Total tickets (immutable reference)
+	OrganizerID uuid.UUID     // uuid.Nil when the event has no organizer
+	MinAdvance  time.Duration // Bookings close this long before the event starts
+	Status      EventStatus
+	CancelledAt time.Time // Zero unless the event is cancelled
+	// MinViable is the number of tickets that must sell for the event to run; 0 means it always runs
+	MinViable int
+	// ViabilityDeadline is when conditional bookings are cancelled if MinViable has not been reached
+	ViabilityDeadline time.Time
+	// Seated events sell specific seats; their ticket count equals the number of seats
+	Seated bool
+	// PriceCents is the price of one ticket in the minor unit of Currency; 0 means the event is free
+	PriceCents int64
+	Currency   string // ISO 4217 code, empty for free events
+	// Tags categorize the event, e.g. "music" or "theater"; lower-case and never nil
+	Tags []string
+	// BookingsPaused temporarily stops new bookings and holds without cancelling the event
+	BookingsPaused bool
+	// CreatedAt is set when the event is first saved, unless already set (e.g. by an import)
+	CreatedAt time.Time
+	// ImageURL locates the event's poster and ThumbnailURL a smaller preview of it; both are optional
+	ImageURL     string
+	ThumbnailURL string
+	// QuantityStep is the multiple bookings must come in, e.g. 4 for tables of four; 1 allows any quantity
+	QuantityStep int
+	// CancellationPolicy sets the event's own refund tiers; nil leaves refunds to the deployment's default policy
+	CancellationPolicy *CancellationPolicy
+	// Lottery events sell their tickets only through a lottery draw, never first-come-first-served
+	Lottery bool
+}
+
+// EventCursor is a keyset position in the events listing, which is ordered by date and then ID
+type EventCursor struct {
+	Date time.Time
+	ID   uuid.UUID
+}
+
+// EventOption configures optional event attributes at creation
+type EventOption func(*Event)
+
+// WithOrganizer assigns the organizer who owns the event
+func WithOrganizer(organizerID uuid.UUID) EventOption {
+	return func(e *Event) {
+		e.OrganizerID = organizerID
+	}
+}
+
+// WithIDGenerator draws the event ID from gen instead of a random UUIDv4
+func WithIDGenerator(gen IDGenerator) EventOption {
+	return func(e *Event) {
+		e.ID = gen.NewID()
+	}
+}
+
+// WithMinViable makes the event run only if minViable tickets sell by deadline
+// Bookings on such events may be conditional, pending until the threshold is resolved
+func WithMinViable(minViable int, deadline time.Time) EventOption {
+	return func(e *Event) {
+		e.MinViable = minViable
+		e.ViabilityDeadline = deadline
+	}
 }
 
-func NewEvent(name, location string, date time.Time, tickets int) (*Event, error) {
+// WithSeating marks the event as reserved seating, so bookings must select specific seats
+func WithSeating() EventOption {
+	return func(e *Event) {
+		e.Seated = true
+	}
+}
+
+// WithPrice charges priceCents per ticket in currency, an ISO 4217 code
+func WithPrice(priceCents int64, currency string) EventOption {
+	return func(e *Event) {
+		e.PriceCents = priceCents
+		e.Currency = strings.ToUpper(currency)
+	}
+}
+
+// WithTags categorizes the event; tags are lower-cased and de-duplicated
+func WithTags(tags []string) EventOption {
+	return func(e *Event) {
+		e.Tags = normalizeTags(tags)
+	}
+}
+
+// WithEndDate sets when the event ends; it must be after the event's date
+func WithEndDate(endDate time.Time) EventOption {
+	return func(e *Event) {
+		e.EndDate = endDate
+	}
+}
+
+// WithMinAdvance requires bookings to be made at least minAdvance before the event starts
+func WithMinAdvance(minAdvance time.Duration) EventOption {
+	return func(e *Event) {
+		e.MinAdvance = minAdvance
+	}
+}
+
+// WithQuantityStep sells the event's tickets only in multiples of step, e.g. whole tables
+func WithQuantityStep(step int) EventOption {
+	return func(e *Event) {
+		e.QuantityStep = step
+	}
+}
+
+// WithCancellationPolicy refunds cancelled bookings of the event by policy instead of the default tiers
+func WithCancellationPolicy(policy CancellationPolicy) EventOption {
+	return func(e *Event) {
+		e.CancellationPolicy = &policy
+	}
+}
+
+// WithLottery sells the event's tickets through a lottery instead of bookings and holds
+func WithLottery() EventOption {
+	return func(e *Event) {
+		e.Lottery = true
+	}
+}
+
+func NewEvent(name, location string, date time.Time, tickets int, opts ...EventOption) (*Event, error) {
 	if tickets < 0 {
 		return nil, ErrInvalidAvailableTickets
 	}
+	if tickets > MaxTickets {
+		return nil, ErrTicketsTooLarge
+	}
+
+	event := &Event{
+		ID:           uuid.New(),
+		Name:         name,
+		Date:         date,
+		Location:     location,
+		Tickets:      tickets,
+		Status:       EventStatusActive,
+		Tags:         []string{},
+		QuantityStep: 1,
+	}
+	for _, opt := range opts {
+		opt(event)
+	}
+
+	if event.MinAdvance < 0 {
+		return nil, ErrInvalidMinAdvance
+	}
+	if !event.EndDate.IsZero() && !event.EndDate.After(event.Date) {
+		return nil, ErrInvalidEndDate
+	}
+	if event.PriceCents < 0 || event.PriceCents > MaxPriceCents {
+		return nil, ErrInvalidPrice
+	}
+	if (event.PriceCents > 0 || event.Currency != "") && !isCurrencyCode(event.Currency) {
+		return nil, ErrInvalidCurrency
+	}
+	if !validTags(event.Tags) {
+		return nil, ErrInvalidTags
+	}
+	if err := validateImages(event.ImageURL, event.ThumbnailURL); err != nil {
+		return nil, err
+	}
+	if event.QuantityStep < 1 || event.QuantityStep > max(event.Tickets, 1) {
+		return nil, ErrInvalidQuantityStep
+	}
+	if event.Lottery && event.Seated {
+		return nil, ErrLotterySeated
+	}
+	if event.MinViable < 0 || event.MinViable > event.Tickets ||
+		(event.MinViable > 0 && event.ViabilityDeadline.IsZero()) {
+		return nil, ErrInvalidMinViable
+	}
+
+	return event, nil
+}
+
+// CheckFutureDate verifies a new event is not already over at now, normally Clock.RuleTime
+// Backfills of historical events skip this check
+func (e *Event) CheckFutureDate(now time.Time) error {
+	if e.Date.Before(now) {
+		return ErrEventDateInPast
+	}
+	return nil
+}
+
+// Cursor returns the listing position just after the event
+func (e *Event) Cursor() EventCursor {
+	return EventCursor{Date: e.Date, ID: e.ID}
+}
+
+// Cancel marks the event as cancelled
+// Cancelling twice returns ErrEventAlreadyCancelled so callers can decide whether to treat it as a no-op
+func (e *Event) Cancel(now time.Time) error {
+	if e.Status == EventStatusCancelled {
+		return ErrEventAlreadyCancelled
+	}
+
+	e.Status = EventStatusCancelled
+	e.CancelledAt = now
+	return nil
+}
+
+// RefundPolicy returns the event's cancellation policy, or fallback when the event has none of its own
+func (e *Event) RefundPolicy(fallback CancellationPolicy) CancellationPolicy {
+	if e.CancellationPolicy != nil {
+		return *e.CancellationPolicy
+	}
+	return fallback
+}
+
+// CheckMergeInto verifies e can be merged into target as its duplicate
+// Seats are labelled per event, so seated bookings cannot move between events
+func (e *Event) CheckMergeInto(target *Event) error {
+	if e.ID == target.ID {
+		return ErrMergeSameEvent
+	}
+	if e.Seated || target.Seated {
+		return ErrMergeSeated
+	}
+	if target.Status == EventStatusCancelled {
+		return ErrEventCancelled
+	}
+	return nil
+}
+
+// PauseBookings stops new bookings until ResumeBookings; existing bookings are kept
+func (e *Event) PauseBookings() error {
+	if e.Status == EventStatusCancelled {
+		return ErrEventCancelled
+	}
+	e.BookingsPaused = true
+	return nil
+}
+
+// ResumeBookings lets a paused event accept bookings again
+func (e *Event) ResumeBookings() error {
+	if e.Status == EventStatusCancelled {
+		return ErrEventCancelled
+	}
+	e.BookingsPaused = false
+	return nil
+}
+
+// CheckBookable verifies the event accepts a booking made at now
+func (e *Event) CheckBookable(now time.Time) error {
+	if e.Status == EventStatusCancelled {
+		return ErrEventCancelled
+	}
+	if e.BookingsPaused {
+		return ErrBookingPaused
+	}
+	return e.CheckBookingWindow(now)
+}
+
+// CheckBookingWindow verifies a booking made at now respects the event's minimum advance window
+func (e *Event) CheckBookingWindow(now time.Time) error {
+	if e.MinAdvance > 0 && e.Date.Sub(now) < e.MinAdvance {
+		return ErrBookingTooLate
+	}
+	return nil
+}
+
+// CheckDirectSale verifies the event sells tickets first-come-first-served, through bookings and holds
+func (e *Event) CheckDirectSale() error {
+	if e.Lottery {
+		return ErrLotteryOnly
+	}
+	return nil
+}
+
+// CheckLottery verifies the event sells its tickets through a lottery
+func (e *Event) CheckLottery() error {
+	if !e.Lottery {
+		return ErrNoLottery
+	}
+	return nil
+}
+
+// CheckQuantity verifies a booking of tickets is a positive multiple of the event's QuantityStep
+func (e *Event) CheckQuantity(tickets int) error {
+	if tickets <= 0 {
+		return ErrInvalidTicketCount
+	}
+	if e.QuantityStep > 1 && tickets%e.QuantityStep != 0 {
+		return ErrTicketsNotMultipleOfStep
+	}
+	return nil
+}
+
+// CheckSeatSelection verifies a booking's seat selection fits the event's seating mode
+func (e *Event) CheckSeatSelection(seatLabels []string) error {
+	if e.Seated && len(seatLabels) == 0 {
+		return ErrSeatSelectionRequired
+	}
+	if !e.Seated && len(seatLabels) > 0 {
+		return ErrSeatingNotSupported
+	}
+	return nil
+}
+
+// RequiresMinimum reports whether the event only runs once MinViable tickets sell
+func (e *Event) RequiresMinimum() bool {
+	return e.MinViable > 0
+}
+
+// CheckConditionalBooking verifies a conditional booking made at now can still wait for the minimum
+func (e *Event) CheckConditionalBooking(now time.Time) error {
+	if !e.RequiresMinimum() {
+		return ErrConditionalNotSupported
+	}
+	if !now.Before(e.ViabilityDeadline) {
+		return ErrViabilityDeadlinePassed
+	}
+	return nil
+}
+
+// ResolveViability decides the fate of conditional bookings given the tickets sold so far
+// It returns true once the threshold is met, false once the deadline passed without it,
+// and ErrViabilityUndecided while neither has happened
+func (e *Event) ResolveViability(ticketsSold int, now time.Time) (bool, error) {
+	if !e.RequiresMinimum() {
+		return false, ErrConditionalNotSupported
+	}
+	if ticketsSold >= e.MinViable {
+		return true, nil
+	}
+	if now.Before(e.ViabilityDeadline) {
+		return false, ErrViabilityUndecided
+	}
+	return false, nil
+}
+
+// EventBookingSummary is a read model combining an event with its booking aggregates
+type EventBookingSummary struct {
+	EventID          uuid.UUID
+	Name             string
+	Date             time.Time
+	Tickets          int
+	AvailableTickets int
+	BookingsCount    int
+	TicketsSold      int
+}
+
+// UserTicketSummary totals the tickets a user holds across events, with a breakdown per event
+type UserTicketSummary struct {
+	Total    int
+	PerEvent []*UserEventTickets
+}
 
-	return &Event{
-		ID:       uuid.New(),
-		Name:     name,
-		Date:     date,
-		Location: location,
-		Tickets:  tickets,
-	}, nil
+// UserEventTickets is a read model of the tickets a user holds for one event
+type UserEventTickets struct {
+	EventID   uuid.UUID
+	EventName string
+	Tickets   int
 }
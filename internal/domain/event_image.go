@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"net/url"
+	"strings"
+)
+
+// MaxImageURLLen keeps image URLs within what browsers and CDNs reliably accept
+const MaxImageURLLen = 2048
+
+// WithImages sets the event's poster image and its optional thumbnail, both absolute http(s) URLs
+// The images are hosted elsewhere; only their URLs are stored
+func WithImages(imageURL, thumbnailURL string) EventOption {
+	return func(e *Event) {
+		e.ImageURL = strings.TrimSpace(imageURL)
+		e.ThumbnailURL = strings.TrimSpace(thumbnailURL)
+	}
+}
+
+// validateImages checks both image URLs; a thumbnail needs the image it is a preview of
+func validateImages(imageURL, thumbnailURL string) error {
+	if imageURL != "" && !validHTTPURL(imageURL, MaxImageURLLen) {
+		return ErrInvalidImageURL
+	}
+	if thumbnailURL != "" && (imageURL == "" || !validHTTPURL(thumbnailURL, MaxImageURLLen)) {
+		return ErrInvalidThumbnailURL
+	}
+	return nil
+}
+
+// validHTTPURL accepts absolute http and https URLs with a host and at most maxLen bytes, rejecting schemes
+// such as javascript: or data: that would run or embed content when a client renders an image
+func validHTTPURL(raw string, maxLen int) bool {
+	if len(raw) > maxLen {
+		return false
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	scheme := strings.ToLower(u.Scheme)
+	return (scheme == "http" || scheme == "https") && u.Host != "" && u.User == nil
+}
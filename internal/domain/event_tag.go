@@ -0,0 +1,71 @@
+package domain
+
+import "strings"
+
+const (
+	MaxEventTags   = 10
+	MaxEventTagLen = 32
+)
+
+// TagMatch decides how an events listing filtered by several tags combines them
+type TagMatch string
+
+const (
+	TagMatchAny TagMatch = "any" // events carrying at least one of the tags
+	TagMatchAll TagMatch = "all" // events carrying every tag
+)
+
+// EventFilter narrows the events listing; the zero value lists every event
+type EventFilter struct {
+	Tags     []string
+	TagMatch TagMatch
+}
+
+// NewEventFilter normalizes tags like event tags; an empty match means TagMatchAny
+func NewEventFilter(tags []string, match TagMatch) (EventFilter, error) {
+	tags = normalizeTags(tags)
+	if !validTags(tags) {
+		return EventFilter{}, ErrInvalidTags
+	}
+	switch match {
+	case "":
+		match = TagMatchAny
+	case TagMatchAny, TagMatchAll:
+	default:
+		return EventFilter{}, ErrInvalidTagMatch
+	}
+	return EventFilter{Tags: tags, TagMatch: match}, nil
+}
+
+// normalizeTags lower-cases and trims tags and drops duplicates, keeping the first occurrence's position
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// validTags reports whether there are at most MaxEventTags tags made of letters, digits and '-'
+func validTags(tags []string) bool {
+	if len(tags) > MaxEventTags {
+		return false
+	}
+	for _, tag := range tags {
+		if tag == "" || len(tag) > MaxEventTagLen {
+			return false
+		}
+		for _, r := range tag {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEvent_WithTags(t *testing.T) {
+	date := time.Date(2026, 6, 20, 19, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		tags    []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "untagged events have no tags", tags: nil, want: []string{}},
+		{name: "normalizes case and spacing", tags: []string{" Music", "JAZZ "}, want: []string{"music", "jazz"}},
+		{name: "drops duplicates", tags: []string{"music", "Music", "jazz"}, want: []string{"music", "jazz"}},
+		{name: "accepts dashes and digits", tags: []string{"open-air", "2026"}, want: []string{"open-air", "2026"}},
+		{name: "rejects blank tags", tags: []string{"music", " "}, wantErr: true},
+		{name: "rejects punctuation", tags: []string{"rock&roll"}, wantErr: true},
+		{name: "rejects long tags", tags: []string{strings.Repeat("a", MaxEventTagLen+1)}, wantErr: true},
+		{name: "rejects too many tags", tags: strings.Split("a,b,c,d,e,f,g,h,i,j,k", ","), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := NewEvent("Jazz Night", "Blue Room", date, 100, WithTags(tt.tags))
+
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrInvalidTags))
+				assert.Nil(t, event)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, event.Tags)
+		})
+	}
+}
+
+func TestNewEventFilter(t *testing.T) {
+	filter, err := NewEventFilter([]string{"Music", "music", "theater"}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, EventFilter{Tags: []string{"music", "theater"}, TagMatch: TagMatchAny}, filter)
+
+	filter, err = NewEventFilter([]string{"music"}, TagMatchAll)
+	assert.NoError(t, err)
+	assert.Equal(t, TagMatchAll, filter.TagMatch)
+
+	_, err = NewEventFilter([]string{"music"}, "xor")
+	assert.True(t, errors.Is(err, ErrInvalidTagMatch))
+
+	_, err = NewEventFilter([]string{""}, TagMatchAny)
+	assert.True(t, errors.Is(err, ErrInvalidTags))
+}
@@ -5,7 +5,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewEvent(t *testing.T) {
@@ -64,3 +66,343 @@ func TestNewEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestNewEvent_WithOrganizer(t *testing.T) {
+	organizerID := uuid.New()
+
+	event, err := NewEvent("Autumn Recital", "City Hall", time.Now().Add(72*time.Hour), 120, WithOrganizer(organizerID))
+
+	assert.NoError(t, err)
+	assert.Equal(t, organizerID, event.OrganizerID)
+}
+
+func TestEvent_CheckFutureDate(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		date    time.Time
+		wantErr error
+	}{
+		{name: "accepts future date", date: now.Add(24 * time.Hour)},
+		{name: "accepts present date", date: now},
+		{name: "rejects date just past", date: now.Add(-time.Second), wantErr: ErrEventDateInPast},
+		{name: "rejects past date", date: now.Add(-24 * time.Hour), wantErr: ErrEventDateInPast},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := NewEvent("Spring Gala", "Opera House", tt.date, 50)
+			require.NoError(t, err)
+
+			err = event.CheckFutureDate(now)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				var validationErr *ValidationError
+				assert.True(t, errors.As(err, &validationErr))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEvent_CheckBookingWindow(t *testing.T) {
+	eventDate := time.Date(2025, 10, 4, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		minAdvance time.Duration
+		bookedAt   time.Time
+		wantErr    bool
+		errType    error
+	}{
+		{
+			name:       "accepts booking just outside the window",
+			minAdvance: 24 * time.Hour,
+			bookedAt:   eventDate.Add(-24*time.Hour - time.Second),
+			wantErr:    false,
+		},
+		{
+			name:       "accepts booking exactly at the window boundary",
+			minAdvance: 24 * time.Hour,
+			bookedAt:   eventDate.Add(-24 * time.Hour),
+			wantErr:    false,
+		},
+		{
+			name:       "rejects booking just inside the window",
+			minAdvance: 24 * time.Hour,
+			bookedAt:   eventDate.Add(-24*time.Hour + time.Second),
+			wantErr:    true,
+			errType:    ErrBookingTooLate,
+		},
+		{
+			name:       "rejects booking after the event started",
+			minAdvance: time.Hour,
+			bookedAt:   eventDate.Add(time.Hour),
+			wantErr:    true,
+			errType:    ErrBookingTooLate,
+		},
+		{
+			name:       "accepts last-minute booking without a window",
+			minAdvance: 0,
+			bookedAt:   eventDate.Add(-time.Minute),
+			wantErr:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := NewEvent("Chamber Concert", "Philharmonic Hall", eventDate, 80, WithMinAdvance(tt.minAdvance))
+			assert.NoError(t, err)
+
+			err = event.CheckBookingWindow(tt.bookedAt)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.errType))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewEvent_RejectsNegativeMinAdvance(t *testing.T) {
+	event, err := NewEvent("Chamber Concert", "Philharmonic Hall", time.Now().Add(72*time.Hour), 80, WithMinAdvance(-time.Hour))
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidMinAdvance))
+	assert.Nil(t, event)
+}
+
+func TestNewEvent_ValidatesEndDate(t *testing.T) {
+	date := time.Now().Add(72 * time.Hour)
+
+	tests := []struct {
+		name    string
+		endDate time.Time
+		wantErr bool
+	}{
+		{name: "after the start", endDate: date.Add(2 * time.Hour)},
+		{name: "unknown end", endDate: time.Time{}},
+		{name: "at the start", endDate: date, wantErr: true},
+		{name: "before the start", endDate: date.Add(-time.Hour), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := NewEvent("Chamber Concert", "Philharmonic Hall", date, 80, WithEndDate(tt.endDate))
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidEndDate)
+				assert.Nil(t, event)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.endDate, event.EndDate)
+			}
+		})
+	}
+}
+
+func TestEvent_Cancel(t *testing.T) {
+	event, err := NewEvent("Open Air Cinema", "Riverside Park", time.Now().Add(96*time.Hour), 250)
+	assert.NoError(t, err)
+	assert.Equal(t, EventStatusActive, event.Status)
+
+	cancelledAt := time.Date(2025, 7, 1, 9, 30, 0, 0, time.UTC)
+	assert.NoError(t, event.Cancel(cancelledAt))
+	assert.Equal(t, EventStatusCancelled, event.Status)
+	assert.Equal(t, cancelledAt, event.CancelledAt)
+
+	err = event.Cancel(cancelledAt.Add(time.Hour))
+	assert.True(t, errors.Is(err, ErrEventAlreadyCancelled))
+	assert.Equal(t, cancelledAt, event.CancelledAt, "second cancellation must not change the event")
+
+	err = event.CheckBookable(cancelledAt)
+	assert.True(t, errors.Is(err, ErrEventCancelled))
+}
+
+func TestEvent_PauseBookings(t *testing.T) {
+	now := time.Now()
+	event, err := NewEvent("Open Air Cinema", "Riverside Park", now.Add(96*time.Hour), 250)
+	assert.NoError(t, err)
+	assert.False(t, event.BookingsPaused)
+
+	assert.NoError(t, event.PauseBookings())
+	assert.True(t, event.BookingsPaused)
+	assert.True(t, errors.Is(event.CheckBookable(now), ErrBookingPaused))
+
+	assert.NoError(t, event.ResumeBookings())
+	assert.False(t, event.BookingsPaused)
+	assert.NoError(t, event.CheckBookable(now))
+
+	assert.NoError(t, event.Cancel(now))
+	assert.True(t, errors.Is(event.PauseBookings(), ErrEventCancelled))
+	assert.True(t, errors.Is(event.ResumeBookings(), ErrEventCancelled))
+	assert.False(t, event.BookingsPaused)
+}
+
+func TestEvent_CheckMergeInto(t *testing.T) {
+	now := time.Now()
+	source, err := NewEvent("Open Air Cinema", "Riverside Park", now.Add(96*time.Hour), 250)
+	assert.NoError(t, err)
+	target, err := NewEvent("Open Air Cinema", "Riverside Park", now.Add(96*time.Hour), 300)
+	assert.NoError(t, err)
+
+	assert.NoError(t, source.CheckMergeInto(target))
+	assert.True(t, errors.Is(source.CheckMergeInto(source), ErrMergeSameEvent))
+
+	target.Seated = true
+	assert.True(t, errors.Is(source.CheckMergeInto(target), ErrMergeSeated))
+	target.Seated = false
+
+	assert.NoError(t, target.Cancel(now))
+	assert.True(t, errors.Is(source.CheckMergeInto(target), ErrEventCancelled))
+}
+
+func TestNewEvent_ValidatesMinViable(t *testing.T) {
+	date := time.Date(2026, 6, 20, 19, 0, 0, 0, time.UTC)
+	deadline := date.Add(-7 * 24 * time.Hour)
+
+	tests := []struct {
+		name      string
+		minViable int
+		deadline  time.Time
+		wantErr   bool
+	}{
+		{name: "accepts minimum with deadline", minViable: 30, deadline: deadline},
+		{name: "accepts minimum equal to tickets", minViable: 100, deadline: deadline},
+		{name: "accepts no minimum", minViable: 0},
+		{name: "rejects negative minimum", minViable: -1, deadline: deadline, wantErr: true},
+		{name: "rejects minimum above tickets", minViable: 101, deadline: deadline, wantErr: true},
+		{name: "rejects minimum without deadline", minViable: 30, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := NewEvent("Walking Tour", "Old Town", date, 100, WithMinViable(tt.minViable, tt.deadline))
+
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrInvalidMinViable))
+				assert.Nil(t, event)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.minViable, event.MinViable)
+		})
+	}
+}
+
+func TestNewEvent_ValidatesQuantityStep(t *testing.T) {
+	date := time.Date(2026, 6, 20, 19, 0, 0, 0, time.UTC)
+
+	event, err := NewEvent("Gala Dinner", "Grand Hotel", date, 40)
+	require.NoError(t, err)
+	assert.Equal(t, 1, event.QuantityStep, "any quantity by default")
+
+	for _, step := range []int{1, 4, 40} {
+		event, err := NewEvent("Gala Dinner", "Grand Hotel", date, 40, WithQuantityStep(step))
+		require.NoError(t, err, step)
+		assert.Equal(t, step, event.QuantityStep)
+	}
+	for _, step := range []int{0, -4, 41} {
+		_, err := NewEvent("Gala Dinner", "Grand Hotel", date, 40, WithQuantityStep(step))
+		assert.ErrorIs(t, err, ErrInvalidQuantityStep, step)
+	}
+}
+
+func TestEvent_CheckQuantity(t *testing.T) {
+	tables, err := NewEvent("Gala Dinner", "Grand Hotel", time.Date(2026, 6, 20, 19, 0, 0, 0, time.UTC), 40, WithQuantityStep(4))
+	require.NoError(t, err)
+
+	for _, tickets := range []int{4, 8, 40} {
+		assert.NoError(t, tables.CheckQuantity(tickets), tickets)
+	}
+	for _, tickets := range []int{1, 3, 6, 41} {
+		assert.ErrorIs(t, tables.CheckQuantity(tickets), ErrTicketsNotMultipleOfStep, tickets)
+	}
+	assert.ErrorIs(t, tables.CheckQuantity(0), ErrInvalidTicketCount)
+	assert.ErrorIs(t, tables.CheckQuantity(-4), ErrInvalidTicketCount)
+
+	// Events loaded without a step sell any quantity
+	assert.NoError(t, (&Event{}).CheckQuantity(3))
+}
+
+func TestEvent_ResolveViability(t *testing.T) {
+	deadline := time.Date(2026, 6, 13, 19, 0, 0, 0, time.UTC)
+	event, err := NewEvent("Walking Tour", "Old Town", deadline.Add(7*24*time.Hour), 100, WithMinViable(30, deadline))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		sold       int
+		now        time.Time
+		wantViable bool
+		wantErr    error
+	}{
+		{name: "viable once threshold is met before deadline", sold: 30, now: deadline.Add(-time.Hour), wantViable: true},
+		{name: "viable when threshold is met after deadline", sold: 45, now: deadline.Add(time.Hour), wantViable: true},
+		{name: "undecided below threshold before deadline", sold: 29, now: deadline.Add(-time.Hour), wantErr: ErrViabilityUndecided},
+		{name: "not viable below threshold at deadline", sold: 29, now: deadline, wantViable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viable, err := event.ResolveViability(tt.sold, tt.now)
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantViable, viable)
+		})
+	}
+}
+
+func TestEvent_CheckConditionalBooking(t *testing.T) {
+	deadline := time.Date(2026, 6, 13, 19, 0, 0, 0, time.UTC)
+	withMinimum, err := NewEvent("Walking Tour", "Old Town", deadline.Add(7*24*time.Hour), 100, WithMinViable(30, deadline))
+	require.NoError(t, err)
+	withoutMinimum, err := NewEvent("Museum Visit", "Museum", deadline.Add(7*24*time.Hour), 100)
+	require.NoError(t, err)
+
+	assert.NoError(t, withMinimum.CheckConditionalBooking(deadline.Add(-time.Minute)))
+	assert.True(t, errors.Is(withMinimum.CheckConditionalBooking(deadline), ErrViabilityDeadlinePassed))
+	assert.True(t, errors.Is(withoutMinimum.CheckConditionalBooking(deadline.Add(-time.Minute)), ErrConditionalNotSupported))
+}
+
+func TestEvent_RefundPolicy(t *testing.T) {
+	date := time.Date(2026, 6, 13, 19, 0, 0, 0, time.UTC)
+	fallback := DefaultCancellationPolicy()
+	own, err := NewCancellationPolicy([]RefundTier{{MinNotice: 48 * time.Hour, RefundPercent: 75}})
+	require.NoError(t, err)
+
+	withPolicy, err := NewEvent("Derby Final", "City Stadium", date, 100, WithCancellationPolicy(own))
+	require.NoError(t, err)
+	withoutPolicy, err := NewEvent("Museum Visit", "Museum", date, 100)
+	require.NoError(t, err)
+
+	assert.Equal(t, own.Tiers(), withPolicy.RefundPolicy(fallback).Tiers())
+	assert.Equal(t, fallback.Tiers(), withoutPolicy.RefundPolicy(fallback).Tiers())
+}
+
+func TestEvent_LotterySales(t *testing.T) {
+	date := time.Date(2026, 6, 13, 19, 0, 0, 0, time.UTC)
+	lottery, err := NewEvent("Cup Final", "City Stadium", date, 100, WithLottery())
+	require.NoError(t, err)
+	regular, err := NewEvent("Museum Visit", "Museum", date, 100)
+	require.NoError(t, err)
+
+	assert.True(t, errors.Is(lottery.CheckDirectSale(), ErrLotteryOnly))
+	assert.NoError(t, lottery.CheckLottery())
+	assert.NoError(t, regular.CheckDirectSale())
+	assert.True(t, errors.Is(regular.CheckLottery(), ErrNoLottery))
+
+	_, err = NewEvent("Opera Night", "Opera House", date, 100, WithLottery(), WithSeating())
+	assert.True(t, errors.Is(err, ErrLotterySeated))
+}
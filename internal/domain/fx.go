@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// DefaultFXRateMaxAge is how old an exchange rate may be before bookings priced with it are refused
+const DefaultFXRateMaxAge = time.Hour
+
+// FXRate converts amounts in From to To: one unit of From is worth Rate units of To, as published at AsOf
+type FXRate struct {
+	From string
+	To   string
+	Rate float64
+	AsOf time.Time
+}
+
+// FXRateProvider looks up exchange rates, e.g. from a market data feed
+type FXRateProvider interface {
+	// Rate returns the latest rate from one currency to another, or ErrUnsupportedCurrency when it has none
+	Rate(ctx context.Context, from, to string) (FXRate, error)
+}
+
+// currencyExponents lists ISO 4217 currencies whose minor unit is not a hundredth of the major unit
+var currencyExponents = map[string]int{
+	"BHD": 3, "BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "IQD": 3, "ISK": 0, "JOD": 3, "JPY": 0, "KMF": 0,
+	"KRW": 0, "KWD": 3, "LYD": 3, "OMR": 3, "PYG": 0, "RWF": 0, "TND": 3, "UGX": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0,
+}
+
+// currencyExponent is the number of decimal places of currency's minor unit
+func currencyExponent(currency string) int {
+	if exponent, ok := currencyExponents[currency]; ok {
+		return exponent
+	}
+	return 2
+}
+
+// NormalizePayCurrency upper-cases a requested payment currency and checks it is an ISO 4217 code
+func NormalizePayCurrency(currency string) (string, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if !isCurrencyCode(currency) {
+		return "", ErrInvalidPayCurrency
+	}
+	return currency, nil
+}
+
+// CheckFresh rejects a rate published more than maxAge before now; a zero maxAge accepts any rate
+func (r FXRate) CheckFresh(now time.Time, maxAge time.Duration) error {
+	if maxAge > 0 && now.Sub(r.AsOf) > maxAge {
+		return ErrFXRateStale
+	}
+	return nil
+}
+
+// Convert converts amount, in From's minor unit, to To's minor unit, rounding half away from zero
+// Amounts are scaled by the currencies' minor units, so 100 EUR cents at 160 JPY/EUR is 160 yen
+func (r FXRate) Convert(amount int64) (int64, error) {
+	if r.Rate <= 0 {
+		return 0, ErrUnsupportedCurrency
+	}
+
+	rate := new(big.Rat)
+	if rate.SetFloat64(r.Rate) == nil {
+		return 0, ErrUnsupportedCurrency
+	}
+	converted := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), rate)
+
+	shift := currencyExponent(r.To) - currencyExponent(r.From)
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(max(shift, -shift))), nil))
+	if shift >= 0 {
+		converted.Mul(converted, scale)
+	} else {
+		converted.Quo(converted, scale)
+	}
+
+	// Round half away from zero: add or subtract a half before truncating
+	half := big.NewRat(1, 2)
+	if converted.Sign() < 0 {
+		converted.Sub(converted, half)
+	} else {
+		converted.Add(converted, half)
+	}
+	rounded := new(big.Int).Quo(converted.Num(), converted.Denom())
+	if !rounded.IsInt64() || rounded.Int64() > MaxPriceCents*MaxTickets {
+		return 0, ErrInvalidPrice
+	}
+	return rounded.Int64(), nil
+}
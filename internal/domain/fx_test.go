@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFXRate_Convert(t *testing.T) {
+	tests := []struct {
+		name   string
+		rate   FXRate
+		amount int64
+		want   int64
+	}{
+		{name: "between two-decimal currencies", rate: FXRate{From: "EUR", To: "USD", Rate: 1.08}, amount: 5000, want: 5400},
+		{name: "rounds half away from zero", rate: FXRate{From: "EUR", To: "USD", Rate: 1.5}, amount: 1, want: 2},
+		{name: "rounds down below a half", rate: FXRate{From: "EUR", To: "GBP", Rate: 0.8571}, amount: 1999, want: 1713},
+		{name: "to a currency without minor units", rate: FXRate{From: "EUR", To: "JPY", Rate: 160.25}, amount: 1000, want: 1603},
+		{name: "from a currency without minor units", rate: FXRate{From: "JPY", To: "EUR", Rate: 0.0062}, amount: 1600, want: 992},
+		{name: "to a three-decimal currency", rate: FXRate{From: "USD", To: "KWD", Rate: 0.3075}, amount: 10000, want: 30750},
+		{name: "free stays free", rate: FXRate{From: "EUR", To: "USD", Rate: 1.08}, amount: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rate.Convert(tt.amount)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFXRate_ConvertRejectsUnusableRates(t *testing.T) {
+	_, err := FXRate{From: "EUR", To: "USD"}.Convert(100)
+	assert.ErrorIs(t, err, ErrUnsupportedCurrency)
+
+	_, err = FXRate{From: "EUR", To: "USD", Rate: 1e12}.Convert(MaxPriceCents)
+	assert.ErrorIs(t, err, ErrInvalidPrice)
+}
+
+func TestFXRate_CheckFresh(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	rate := FXRate{From: "EUR", To: "USD", Rate: 1.08, AsOf: now.Add(-time.Hour)}
+
+	assert.NoError(t, rate.CheckFresh(now, time.Hour))
+	assert.ErrorIs(t, rate.CheckFresh(now.Add(time.Second), time.Hour), ErrFXRateStale)
+	assert.NoError(t, rate.CheckFresh(now.Add(24*time.Hour), 0), "a zero max age accepts any rate")
+}
+
+func TestNormalizePayCurrency(t *testing.T) {
+	currency, err := NormalizePayCurrency(" usd ")
+	require.NoError(t, err)
+	assert.Equal(t, "USD", currency)
+
+	_, err = NormalizePayCurrency("dollars")
+	assert.ErrorIs(t, err, ErrInvalidPayCurrency)
+}
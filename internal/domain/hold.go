@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type HoldStatus string
+
+const (
+	HoldStatusActive    HoldStatus = "active"
+	HoldStatusConfirmed HoldStatus = "confirmed"
+	// HoldStatusReleased is stored once an expired hold's tickets are returned to availability
+	HoldStatusReleased HoldStatus = "released"
+	// HoldStatusExpired is never stored; it is reported for released holds and active holds past ExpiresAt
+	HoldStatusExpired HoldStatus = "expired"
+)
+
+// Hold reserves tickets for a user until ExpiresAt, when it must be confirmed into a booking
+// The tickets are taken from TicketAvailability when the hold is created
+type Hold struct {
+	ID        uuid.UUID
+	EventID   uuid.UUID
+	UserID    uuid.UUID
+	Tickets   int
+	Status    HoldStatus
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	BookingID uuid.UUID // uuid.Nil until the hold is confirmed
+	// ExpiryNotifiedAt is when the user was told the hold is about to expire; zero until then
+	ExpiryNotifiedAt time.Time
+	// DepositCents was authorized as PaymentID when the hold was created; 0 and empty without a deposit
+	DepositCents int64
+	PaymentID    string
+}
+
+// HoldOption configures optional hold attributes at creation
+type HoldOption func(*Hold)
+
+// WithHoldIDGenerator draws the hold ID from gen instead of a random UUIDv4
+func WithHoldIDGenerator(gen IDGenerator) HoldOption {
+	return func(h *Hold) {
+		h.ID = gen.NewID()
+	}
+}
+
+func NewHold(eventID, userID uuid.UUID, tickets int, ttl time.Duration, now time.Time, opts ...HoldOption) (*Hold, error) {
+	if tickets <= 0 {
+		return nil, ErrInvalidTicketCount
+	}
+	if tickets > MaxTickets {
+		return nil, ErrTicketCountTooLarge
+	}
+	if ttl <= 0 {
+		return nil, ErrInvalidHoldTTL
+	}
+
+	hold := &Hold{
+		ID:        uuid.New(),
+		EventID:   eventID,
+		UserID:    userID,
+		Tickets:   tickets,
+		Status:    HoldStatusActive,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+	for _, opt := range opts {
+		opt(hold)
+	}
+	return hold, nil
+}
+
+// IsExpired reports whether the hold can no longer be confirmed
+func (h *Hold) IsExpired(now time.Time) bool {
+	return h.Status == HoldStatusReleased || (h.Status == HoldStatusActive && !now.Before(h.ExpiresAt))
+}
+
+// State is the externally visible status: active, expired or confirmed
+func (h *Hold) State(now time.Time) HoldStatus {
+	if h.IsExpired(now) {
+		return HoldStatusExpired
+	}
+	return h.Status
+}
+
+// NeedsExpiryNotice reports whether an active hold expires within lead of now and its user has not been told yet
+func (h *Hold) NeedsExpiryNotice(now time.Time, lead time.Duration) bool {
+	return h.Status == HoldStatusActive &&
+		h.ExpiryNotifiedAt.IsZero() &&
+		now.Before(h.ExpiresAt) &&
+		!now.Add(lead).Before(h.ExpiresAt)
+}
+
+// MarkExpiryNotified records that the expiry notice was sent, so it is sent once per hold
+func (h *Hold) MarkExpiryNotified(now time.Time) error {
+	if !h.ExpiryNotifiedAt.IsZero() {
+		return ErrHoldExpiryAlreadyNotified
+	}
+
+	h.ExpiryNotifiedAt = now
+	return nil
+}
+
+// Confirm converts the hold into the given booking
+func (h *Hold) Confirm(bookingID uuid.UUID, now time.Time) error {
+	if h.Status == HoldStatusConfirmed {
+		return ErrHoldAlreadyConfirmed
+	}
+	if h.IsExpired(now) {
+		return ErrHoldExpired
+	}
+
+	h.Status = HoldStatusConfirmed
+	h.BookingID = bookingID
+	return nil
+}
+
+// Release marks an active hold as released; the caller returns its tickets to availability
+func (h *Hold) Release() error {
+	if h.Status != HoldStatusActive {
+		return ErrHoldNotActive
+	}
+
+	h.Status = HoldStatusReleased
+	return nil
+}
@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHold(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		tickets int
+		ttl     time.Duration
+		wantErr error
+	}{
+		{name: "creates active hold", tickets: 2, ttl: 10 * time.Minute},
+		{name: "rejects zero tickets", tickets: 0, ttl: 10 * time.Minute, wantErr: ErrInvalidTicketCount},
+		{name: "rejects non-positive ttl", tickets: 2, ttl: 0, wantErr: ErrInvalidHoldTTL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hold, err := NewHold(uuid.New(), uuid.New(), tt.tickets, tt.ttl, now)
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr))
+				assert.Nil(t, hold)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, HoldStatusActive, hold.Status)
+			assert.Equal(t, now.Add(tt.ttl), hold.ExpiresAt)
+			assert.Equal(t, uuid.Nil, hold.BookingID)
+		})
+	}
+}
+
+func TestHold_State(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		status HoldStatus
+		at     time.Time
+		want   HoldStatus
+	}{
+		{name: "active before expiry", status: HoldStatusActive, at: now.Add(9 * time.Minute), want: HoldStatusActive},
+		{name: "expired at expiry", status: HoldStatusActive, at: now.Add(10 * time.Minute), want: HoldStatusExpired},
+		{name: "released reports expired", status: HoldStatusReleased, at: now, want: HoldStatusExpired},
+		{name: "confirmed stays confirmed after expiry", status: HoldStatusConfirmed, at: now.Add(time.Hour), want: HoldStatusConfirmed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hold, err := NewHold(uuid.New(), uuid.New(), 1, 10*time.Minute, now)
+			require.NoError(t, err)
+			hold.Status = tt.status
+
+			assert.Equal(t, tt.want, hold.State(tt.at))
+		})
+	}
+}
+
+func TestHold_Confirm(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	bookingID := uuid.New()
+
+	t.Run("confirms active hold", func(t *testing.T) {
+		hold, err := NewHold(uuid.New(), uuid.New(), 1, 10*time.Minute, now)
+		require.NoError(t, err)
+
+		require.NoError(t, hold.Confirm(bookingID, now.Add(time.Minute)))
+		assert.Equal(t, HoldStatusConfirmed, hold.Status)
+		assert.Equal(t, bookingID, hold.BookingID)
+	})
+
+	t.Run("rejects expired hold", func(t *testing.T) {
+		hold, err := NewHold(uuid.New(), uuid.New(), 1, 10*time.Minute, now)
+		require.NoError(t, err)
+
+		err = hold.Confirm(bookingID, now.Add(11*time.Minute))
+		assert.True(t, errors.Is(err, ErrHoldExpired))
+		assert.Equal(t, HoldStatusActive, hold.Status)
+	})
+
+	t.Run("rejects second confirmation", func(t *testing.T) {
+		hold, err := NewHold(uuid.New(), uuid.New(), 1, 10*time.Minute, now)
+		require.NoError(t, err)
+		require.NoError(t, hold.Confirm(bookingID, now))
+
+		err = hold.Confirm(uuid.New(), now)
+		assert.True(t, errors.Is(err, ErrHoldAlreadyConfirmed))
+		assert.Equal(t, bookingID, hold.BookingID)
+	})
+}
+
+func TestHold_Release(t *testing.T) {
+	hold, err := NewHold(uuid.New(), uuid.New(), 1, 10*time.Minute, time.Now())
+	require.NoError(t, err)
+
+	require.NoError(t, hold.Release())
+	assert.Equal(t, HoldStatusReleased, hold.Status)
+	assert.True(t, errors.Is(hold.Release(), ErrHoldNotActive))
+}
+
+func TestHold_NeedsExpiryNotice(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	const lead = 2 * time.Minute
+
+	tests := []struct {
+		name  string
+		setup func(h *Hold)
+		at    time.Time
+		want  bool
+	}{
+		{name: "before the notice window", at: now.Add(7 * time.Minute), want: false},
+		{name: "at the start of the window", at: now.Add(8 * time.Minute), want: true},
+		{name: "inside the window", at: now.Add(9 * time.Minute), want: true},
+		{name: "at expiry", at: now.Add(10 * time.Minute), want: false},
+		{
+			name:  "already notified",
+			setup: func(h *Hold) { h.ExpiryNotifiedAt = now.Add(8 * time.Minute) },
+			at:    now.Add(9 * time.Minute),
+			want:  false,
+		},
+		{
+			name:  "confirmed hold",
+			setup: func(h *Hold) { h.Status = HoldStatusConfirmed },
+			at:    now.Add(9 * time.Minute),
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hold, err := NewHold(uuid.New(), uuid.New(), 1, 10*time.Minute, now)
+			require.NoError(t, err)
+			if tt.setup != nil {
+				tt.setup(hold)
+			}
+
+			assert.Equal(t, tt.want, hold.NeedsExpiryNotice(tt.at, lead))
+		})
+	}
+}
+
+func TestHold_MarkExpiryNotified(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	hold, err := NewHold(uuid.New(), uuid.New(), 1, 10*time.Minute, now)
+	require.NoError(t, err)
+
+	require.NoError(t, hold.MarkExpiryNotified(now))
+	assert.Equal(t, now, hold.ExpiryNotifiedAt)
+	assert.True(t, errors.Is(hold.MarkExpiryNotified(now.Add(time.Second)), ErrHoldExpiryAlreadyNotified))
+	assert.Equal(t, now, hold.ExpiryNotifiedAt)
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// IDGenerator produces identifiers for new entities
+// All generators return uuid.UUID so every format fits the existing UUID columns
+type IDGenerator interface {
+	NewID() uuid.UUID
+}
+
+// Supported ID formats for NewIDGenerator
+const (
+	IDFormatUUIDv4 = "uuidv4"
+	IDFormatUUIDv7 = "uuidv7"
+)
+
+// RandomIDGenerator generates random UUIDv4 identifiers; it is the default
+type RandomIDGenerator struct{}
+
+func (RandomIDGenerator) NewID() uuid.UUID {
+	return uuid.New()
+}
+
+// TimeOrderedIDGenerator generates UUIDv7 identifiers, which sort by creation time
+// This keeps B-tree inserts append-mostly and makes IDs usable as keyset pagination cursors
+type TimeOrderedIDGenerator struct{}
+
+func (TimeOrderedIDGenerator) NewID() uuid.UUID {
+	// NewV7 only fails when the random source does, which uuid.New treats as fatal too
+	return uuid.Must(uuid.NewV7())
+}
+
+// NewIDGenerator returns the generator for format, defaulting to UUIDv4 when format is empty
+func NewIDGenerator(format string) (IDGenerator, error) {
+	switch format {
+	case "", IDFormatUUIDv4:
+		return RandomIDGenerator{}, nil
+	case IDFormatUUIDv7:
+		return TimeOrderedIDGenerator{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported ID format %q, expected %s or %s", format, IDFormatUUIDv4, IDFormatUUIDv7)
+	}
+}
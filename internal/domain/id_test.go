@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIDGenerator(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      string
+		wantVersion byte
+		wantErr     bool
+	}{
+		{name: "defaults to UUIDv4", format: "", wantVersion: 4},
+		{name: "selects UUIDv4", format: IDFormatUUIDv4, wantVersion: 4},
+		{name: "selects UUIDv7", format: IDFormatUUIDv7, wantVersion: 7},
+		{name: "rejects unknown format", format: "snowflake", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen, err := NewIDGenerator(tt.format)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVersion, byte(gen.NewID().Version()))
+		})
+	}
+}
+
+func TestTimeOrderedIDGenerator_IsMonotonic(t *testing.T) {
+	gen := TimeOrderedIDGenerator{}
+
+	previous := gen.NewID()
+	for i := 0; i < 10000; i++ {
+		next := gen.NewID()
+		require.Equal(t, 1, bytes.Compare(next[:], previous[:]), "ID %d (%s) is not greater than %s", i, next, previous)
+		previous = next
+	}
+}
+
+func TestWithIDGenerator_AppliesToEventsBookingsHoldsAndLotteryEntries(t *testing.T) {
+	gen := TimeOrderedIDGenerator{}
+
+	event, err := NewEvent("Product Launch", "Expo Center", time.Date(2026, 9, 1, 10, 0, 0, 0, time.UTC), 10, WithIDGenerator(gen))
+	require.NoError(t, err)
+	assert.Equal(t, byte(7), byte(event.ID.Version()))
+
+	booking, err := NewBooking(event.ID, event.ID, 1, WithBookingIDGenerator(gen))
+	require.NoError(t, err)
+	assert.Equal(t, byte(7), byte(booking.ID.Version()))
+	assert.Equal(t, 1, bytes.Compare(booking.ID[:], event.ID[:]))
+
+	entry, err := NewLotteryEntry(event.ID, event.ID, 1, time.Date(2026, 8, 1, 10, 0, 0, 0, time.UTC), WithLotteryEntryIDGenerator(gen))
+	require.NoError(t, err)
+	assert.Equal(t, byte(7), byte(entry.ID.Version()))
+
+	hold, err := NewHold(event.ID, event.ID, 1, time.Minute, time.Date(2026, 8, 1, 10, 0, 0, 0, time.UTC), WithHoldIDGenerator(gen))
+	require.NoError(t, err)
+	assert.Equal(t, byte(7), byte(hold.ID.Version()))
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxIdempotencyKeyLength bounds client-supplied keys to the size of the stored column
+const MaxIdempotencyKeyLength = 255
+
+// IdempotencyKey records which booking a client-supplied key produced, so a retried request replays it
+// Keys are honoured until ExpiresAt and deleted by a cleanup job afterwards
+type IdempotencyKey struct {
+	Key       string
+	BookingID uuid.UUID // uuid.Nil until the request that claimed the key has created its booking
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+func NewIdempotencyKey(key string, ttl time.Duration, now time.Time) (*IdempotencyKey, error) {
+	if strings.TrimSpace(key) == "" || len(key) > MaxIdempotencyKeyLength {
+		return nil, ErrInvalidIdempotencyKey
+	}
+	if ttl <= 0 {
+		return nil, ErrInvalidIdempotencyKeyTTL
+	}
+
+	return &IdempotencyKey{
+		Key:       key,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}, nil
+}
+
+// NewRequestFingerprintKey derives a key from the booking request's content, for clients that send no
+// Idempotency-Key; identical requests within window then resolve to the first one's booking
+func NewRequestFingerprintKey(eventID, userID uuid.UUID, tickets int, conditional bool, seats []string, discountCode, payCurrency, allocation string, expiresIn, window time.Duration, now time.Time) (*IdempotencyKey, error) {
+	hash := sha256.New()
+	for _, part := range []string{eventID.String(), userID.String(), strconv.Itoa(tickets), strconv.FormatBool(conditional), discountCode, payCurrency, allocation, strconv.FormatInt(int64(expiresIn), 10)} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	for _, seat := range seats {
+		hash.Write([]byte(seat))
+		hash.Write([]byte{0})
+	}
+
+	return NewIdempotencyKey("fingerprint:"+hex.EncodeToString(hash.Sum(nil)), window, now)
+}
+
+// IsExpired reports whether the key's replay window has passed, so it may be claimed again
+func (k *IdempotencyKey) IsExpired(now time.Time) bool {
+	return !now.Before(k.ExpiresAt)
+}
+
+// Matches reports whether booking could have been produced by a request for the same event, user and tickets
+// A key reused with different parameters must not silently return an unrelated booking
+func (k *IdempotencyKey) Matches(booking *Booking, eventID, userID uuid.UUID, tickets int) bool {
+	return booking.ID == k.BookingID &&
+		booking.EventID == eventID &&
+		booking.UserID == userID &&
+		booking.TicketsBooked == tickets
+}
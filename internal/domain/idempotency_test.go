@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIdempotencyKey(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		key     string
+		ttl     time.Duration
+		wantErr error
+	}{
+		{name: "creates key", key: "checkout-42", ttl: time.Hour},
+		{name: "accepts key at max length", key: strings.Repeat("k", MaxIdempotencyKeyLength), ttl: time.Hour},
+		{name: "rejects blank key", key: "   ", ttl: time.Hour, wantErr: ErrInvalidIdempotencyKey},
+		{name: "rejects overlong key", key: strings.Repeat("k", MaxIdempotencyKeyLength+1), ttl: time.Hour, wantErr: ErrInvalidIdempotencyKey},
+		{name: "rejects non-positive ttl", key: "checkout-42", ttl: 0, wantErr: ErrInvalidIdempotencyKeyTTL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := NewIdempotencyKey(tt.key, tt.ttl, now)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, key)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.key, key.Key)
+			assert.Equal(t, now.Add(tt.ttl), key.ExpiresAt)
+			assert.Equal(t, uuid.Nil, key.BookingID)
+		})
+	}
+}
+
+func TestIdempotencyKey_Matches(t *testing.T) {
+	eventID := uuid.New()
+	userID := uuid.New()
+	booking := &Booking{ID: uuid.New(), EventID: eventID, UserID: userID, TicketsBooked: 2}
+	key := &IdempotencyKey{Key: "checkout-42", BookingID: booking.ID}
+
+	assert.True(t, key.Matches(booking, eventID, userID, 2))
+	assert.False(t, key.Matches(booking, uuid.New(), userID, 2), "different event")
+	assert.False(t, key.Matches(booking, eventID, uuid.New(), 2), "different user")
+	assert.False(t, key.Matches(booking, eventID, userID, 3), "different ticket count")
+}
+
+func TestNewRequestFingerprintKey(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	eventID := uuid.New()
+	userID := uuid.New()
+
+	key := func(tickets int, conditional bool, seats ...string) string {
+		k, err := NewRequestFingerprintKey(eventID, userID, tickets, conditional, seats, "", "", "", 0, 5*time.Second, now)
+		require.NoError(t, err)
+		return k.Key
+	}
+
+	assert.Equal(t, key(2, false), key(2, false), "identical requests share a key")
+	assert.NotEqual(t, key(2, false), key(3, false))
+	assert.NotEqual(t, key(2, false), key(2, true))
+	assert.NotEqual(t, key(2, false, "A1", "A2"), key(2, false, "A1", "A3"))
+	assert.LessOrEqual(t, len(key(2, false)), MaxIdempotencyKeyLength)
+
+	discounted, err := NewRequestFingerprintKey(eventID, userID, 2, false, nil, "SPRING10", "", "", 0, 5*time.Second, now)
+	require.NoError(t, err)
+	assert.NotEqual(t, key(2, false), discounted.Key, "a discount code changes the key")
+
+	inYen, err := NewRequestFingerprintKey(eventID, userID, 2, false, nil, "", "JPY", "", 0, 5*time.Second, now)
+	require.NoError(t, err)
+	assert.NotEqual(t, key(2, false), inYen.Key, "a payment currency changes the key")
+
+	press, err := NewRequestFingerprintKey(eventID, userID, 2, false, nil, "", "", "press", 0, 5*time.Second, now)
+	require.NoError(t, err)
+	assert.NotEqual(t, key(2, false), press.Key, "an allocation changes the key")
+
+	reserved, err := NewRequestFingerprintKey(eventID, userID, 2, false, nil, "", "", "", 15*time.Minute, 5*time.Second, now)
+	require.NoError(t, err)
+	assert.NotEqual(t, key(2, false), reserved.Key, "a reservation's expiry changes the key")
+
+	other, err := NewRequestFingerprintKey(eventID, uuid.New(), 2, false, nil, "", "", "", 0, 5*time.Second, now)
+	require.NoError(t, err)
+	assert.NotEqual(t, key(2, false), other.Key, "different users never share a key")
+	assert.Equal(t, now.Add(5*time.Second), other.ExpiresAt)
+}
+
+func TestIdempotencyKey_IsExpired(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	key := &IdempotencyKey{Key: "checkout-42", ExpiresAt: now}
+
+	assert.False(t, key.IsExpired(now.Add(-time.Second)))
+	assert.True(t, key.IsExpired(now))
+}
@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"math"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LotteryEntryStatus string
+
+const (
+	LotteryEntryRegistered LotteryEntryStatus = "registered"
+	LotteryEntryWon        LotteryEntryStatus = "won"
+	LotteryEntryLost       LotteryEntryStatus = "lost"
+)
+
+// MaxLotteryWeight bounds how much more likely an organizer can make one entrant to win than another
+const MaxLotteryWeight = 100
+
+// LotteryEntry registers a user for the lottery of an oversubscribed event, instead of booking first-come-first-served
+// Registering takes no tickets; the draw turns winning entries into bookings
+type LotteryEntry struct {
+	ID           uuid.UUID
+	EventID      uuid.UUID
+	UserID       uuid.UUID
+	Tickets      int
+	Status       LotteryEntryStatus
+	RegisteredAt time.Time
+	BookingID    uuid.UUID // Booking the entry won; uuid.Nil until it wins
+}
+
+// LotteryEntryOption configures optional entry attributes at registration
+type LotteryEntryOption func(*LotteryEntry)
+
+// WithLotteryEntryIDGenerator draws the entry ID from gen instead of a random UUIDv4
+func WithLotteryEntryIDGenerator(gen IDGenerator) LotteryEntryOption {
+	return func(e *LotteryEntry) {
+		e.ID = gen.NewID()
+	}
+}
+
+func NewLotteryEntry(eventID, userID uuid.UUID, tickets int, now time.Time, opts ...LotteryEntryOption) (*LotteryEntry, error) {
+	if tickets <= 0 {
+		return nil, ErrInvalidTickets
+	}
+	if tickets > MaxTickets {
+		return nil, ErrTicketsTooLarge
+	}
+
+	entry := &LotteryEntry{
+		ID:           uuid.New(),
+		EventID:      eventID,
+		UserID:       userID,
+		Tickets:      tickets,
+		Status:       LotteryEntryRegistered,
+		RegisteredAt: now,
+	}
+	for _, opt := range opts {
+		opt(entry)
+	}
+	return entry, nil
+}
+
+// LotteryDraw is the outcome of drawing an event's lottery; Seed reproduces it
+type LotteryDraw struct {
+	Seed    int64
+	Winners []*LotteryEntry // In the order they were drawn
+	Losers  []*LotteryEntry
+}
+
+// Tickets returns the tickets won across all winners
+func (d *LotteryDraw) Tickets() int {
+	tickets := 0
+	for _, winner := range d.Winners {
+		tickets += winner.Tickets
+	}
+	return tickets
+}
+
+// DrawLottery draws entries in a random order weighted by weights, keyed by user, where entrants without
+// a weight have 1; an entrant of weight 3 is three times as likely as one of weight 1 to be drawn next
+// Drawn entries win while their tickets fit into capacity, so winners never take more than capacity;
+// an entry asking for more than is left loses, but smaller entries drawn after it may still win
+// Entries must all be registered and given in a stable order, e.g. by registration; the same entries,
+// capacity, weights and seed always draw the same winners
+func DrawLottery(entries []*LotteryEntry, capacity int, weights map[uuid.UUID]int, seed int64) (*LotteryDraw, error) {
+	for _, weight := range weights {
+		if weight < 1 || weight > MaxLotteryWeight {
+			return nil, ErrInvalidLotteryWeight
+		}
+	}
+	for _, entry := range entries {
+		if entry.Status != LotteryEntryRegistered {
+			return nil, ErrLotteryAlreadyDrawn
+		}
+	}
+
+	// Sorting by u^(1/weight) for uniform u samples without replacement with probability proportional
+	// to weight (Efraimidis-Spirakis), in a single pass over the entries
+	rng := rand.New(rand.NewPCG(uint64(seed), 0))
+	keys := make([]float64, len(entries))
+	order := make([]int, len(entries))
+	for i, entry := range entries {
+		weight, ok := weights[entry.UserID]
+		if !ok {
+			weight = 1
+		}
+		keys[i] = math.Pow(rng.Float64(), 1/float64(weight))
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return keys[order[a]] > keys[order[b]]
+	})
+
+	draw := &LotteryDraw{Seed: seed}
+	remaining := max(capacity, 0)
+	for _, i := range order {
+		entry := entries[i]
+		if entry.Tickets <= remaining {
+			remaining -= entry.Tickets
+			entry.Status = LotteryEntryWon
+			draw.Winners = append(draw.Winners, entry)
+		} else {
+			entry.Status = LotteryEntryLost
+			draw.Losers = append(draw.Losers, entry)
+		}
+	}
+
+	return draw, nil
+}
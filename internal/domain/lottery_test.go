@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lotteryEntries(t *testing.T, tickets ...int) []*LotteryEntry {
+	t.Helper()
+	eventID := uuid.New()
+	registeredAt := time.Date(2030, 5, 1, 9, 0, 0, 0, time.UTC)
+	entries := make([]*LotteryEntry, len(tickets))
+	for i, count := range tickets {
+		entry, err := NewLotteryEntry(eventID, uuid.New(), count, registeredAt.Add(time.Duration(i)*time.Second))
+		require.NoError(t, err)
+		entries[i] = entry
+	}
+	return entries
+}
+
+func TestNewLotteryEntry(t *testing.T) {
+	now := time.Now()
+	entry, err := NewLotteryEntry(uuid.New(), uuid.New(), 2, now)
+	require.NoError(t, err)
+	assert.Equal(t, LotteryEntryRegistered, entry.Status)
+	assert.Equal(t, now, entry.RegisteredAt)
+	assert.Equal(t, uuid.Nil, entry.BookingID)
+
+	_, err = NewLotteryEntry(uuid.New(), uuid.New(), 0, now)
+	assert.ErrorIs(t, err, ErrInvalidTickets)
+	_, err = NewLotteryEntry(uuid.New(), uuid.New(), MaxTickets+1, now)
+	assert.ErrorIs(t, err, ErrTicketsTooLarge)
+}
+
+func TestDrawLottery_WinnersNeverExceedCapacity(t *testing.T) {
+	for seed := int64(0); seed < 200; seed++ {
+		entries := lotteryEntries(t, 1, 4, 2, 3, 1, 5, 2, 2, 1, 3)
+		capacity := int(seed % 12)
+
+		draw, err := DrawLottery(entries, capacity, nil, seed)
+		require.NoError(t, err)
+
+		assert.LessOrEqual(t, draw.Tickets(), capacity, "seed %d", seed)
+		assert.Len(t, append(draw.Winners, draw.Losers...), len(entries), "every entry is decided")
+		for _, winner := range draw.Winners {
+			assert.Equal(t, LotteryEntryWon, winner.Status)
+		}
+		for _, loser := range draw.Losers {
+			assert.Equal(t, LotteryEntryLost, loser.Status)
+			assert.Greater(t, loser.Tickets, capacity-draw.Tickets(), "losers did not fit in what was left")
+		}
+	}
+}
+
+func TestDrawLottery_ReproducibleWithSeed(t *testing.T) {
+	winners := func(seed int64) []uuid.UUID {
+		entries := lotteryEntries(t, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1)
+		for i, entry := range entries {
+			entry.UserID = uuid.NewSHA1(uuid.NameSpaceOID, []byte{byte(i)})
+		}
+		draw, err := DrawLottery(entries, 4, nil, seed)
+		require.NoError(t, err)
+		ids := make([]uuid.UUID, len(draw.Winners))
+		for i, winner := range draw.Winners {
+			ids[i] = winner.UserID
+		}
+		return ids
+	}
+
+	first := winners(42)
+	assert.Len(t, first, 4)
+	assert.Equal(t, first, winners(42), "the same seed draws the same winners in the same order")
+
+	differs := false
+	for seed := int64(43); seed < 53 && !differs; seed++ {
+		differs = !assert.ObjectsAreEqual(first, winners(seed))
+	}
+	assert.True(t, differs, "other seeds draw other winners")
+}
+
+func TestDrawLottery_Weights(t *testing.T) {
+	favouriteWins := 0
+	for seed := int64(0); seed < 500; seed++ {
+		entries := lotteryEntries(t, 1, 1)
+		favourite := entries[1].UserID
+
+		draw, err := DrawLottery(entries, 1, map[uuid.UUID]int{favourite: 9}, seed)
+		require.NoError(t, err)
+		require.Len(t, draw.Winners, 1)
+		if draw.Winners[0].UserID == favourite {
+			favouriteWins++
+		}
+	}
+	// Weight 9 against 1 wins 90% of draws in expectation
+	assert.InDelta(t, 450, favouriteWins, 40)
+}
+
+func TestDrawLottery_Rejects(t *testing.T) {
+	entries := lotteryEntries(t, 1, 1)
+
+	_, err := DrawLottery(entries, 1, map[uuid.UUID]int{entries[0].UserID: 0}, 1)
+	assert.ErrorIs(t, err, ErrInvalidLotteryWeight)
+	_, err = DrawLottery(entries, 1, map[uuid.UUID]int{entries[0].UserID: MaxLotteryWeight + 1}, 1)
+	assert.ErrorIs(t, err, ErrInvalidLotteryWeight)
+	assert.Equal(t, LotteryEntryRegistered, entries[0].Status, "a rejected draw decides nothing")
+
+	_, err = DrawLottery(entries, 1, nil, 1)
+	require.NoError(t, err)
+	_, err = DrawLottery(entries, 1, nil, 1)
+	assert.ErrorIs(t, err, ErrLotteryAlreadyDrawn)
+}
+
+func TestDrawLottery_SmallerEntriesFillRemainingCapacity(t *testing.T) {
+	for seed := int64(0); seed < 50; seed++ {
+		entries := lotteryEntries(t, 4, 1)
+
+		// The 4-ticket entry never fits, wherever it is drawn, and does not block the 1-ticket entry
+		draw, err := DrawLottery(entries, 3, nil, seed)
+		require.NoError(t, err)
+		assert.Equal(t, []*LotteryEntry{entries[1]}, draw.Winners)
+		assert.Equal(t, []*LotteryEntry{entries[0]}, draw.Losers)
+	}
+}
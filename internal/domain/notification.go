@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// NotificationKind identifies what a notification is about, so channels can pick a template
+type NotificationKind string
+
+const (
+	NotificationHoldExpiring NotificationKind = "hold_expiring"
+)
+
+// Notification is a message addressed to a user
+type Notification struct {
+	Kind    NotificationKind
+	UserID  uuid.UUID
+	Message string
+	// Attributes carries identifiers the message refers to, such as hold_id
+	Attributes map[string]string
+}
+
+// Notifier delivers notifications to users
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// PaymentGateway authorizes and settles payments with an external provider
+// An authorization reserves funds without moving them; it is either captured or voided
+type PaymentGateway interface {
+	// Authorize reserves amountCents from the user and returns the provider's transaction ID
+	Authorize(ctx context.Context, userID uuid.UUID, amountCents int64, currency string) (string, error)
+	// Capture collects the full amount of an authorization
+	Capture(ctx context.Context, txnID string) error
+	// Void releases an authorization that will not be captured
+	Void(ctx context.Context, txnID string) error
+}
+
+// HoldDepositCents is the share of a hold's total, in percent, authorized when the hold is created
+// It is rounded up to a whole cent, so every paid hold takes a non-zero deposit
+func HoldDepositCents(totalCents int64, percent int) int64 {
+	if totalCents <= 0 || percent <= 0 {
+		return 0
+	}
+	if percent >= 100 {
+		return totalCents
+	}
+	return (totalCents*int64(percent) + 99) / 100
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHoldDepositCents(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   int64
+		percent int
+		want    int64
+	}{
+		{name: "fraction of the total", total: 7500, percent: 20, want: 1500},
+		{name: "rounds up to the next cent", total: 999, percent: 10, want: 100},
+		{name: "no deposit configured", total: 7500, percent: 0, want: 0},
+		{name: "free event", total: 0, percent: 20, want: 0},
+		{name: "capped at the total", total: 7500, percent: 150, want: 7500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HoldDepositCents(tt.total, tt.percent))
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package domain
+
+// MaxPriceCents bounds a ticket price so that MaxTickets tickets still total well inside int64
+const MaxPriceCents = 10_000_000_000
+
+// Quote is the advisory cost of booking tickets at an event
+// Nothing is reserved, so the price and availability may change before the booking is made
+type Quote struct {
+	Tickets        int
+	UnitPriceCents int64
+	SubtotalCents  int64
+	FeesCents      int64 // No fees are charged yet; kept so clients read the total rather than the subtotal
+	TotalCents     int64
+	Currency       string
+}
+
+// Quote prices tickets at the event's ticket price
+func (e *Event) Quote(tickets int) (Quote, error) {
+	if tickets <= 0 {
+		return Quote{}, ErrInvalidTicketCount
+	}
+	if tickets > MaxTickets {
+		return Quote{}, ErrTicketCountTooLarge
+	}
+	if tickets > e.Tickets {
+		return Quote{}, ErrExceedsCapacity
+	}
+
+	subtotal := e.PriceCents * int64(tickets)
+	return Quote{
+		Tickets:        tickets,
+		UnitPriceCents: e.PriceCents,
+		SubtotalCents:  subtotal,
+		TotalCents:     subtotal,
+		Currency:       e.Currency,
+	}, nil
+}
+
+// isCurrencyCode reports whether code looks like an ISO 4217 code: three upper-case letters
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEvent_ValidatesPrice(t *testing.T) {
+	tests := []struct {
+		name         string
+		priceCents   int64
+		currency     string
+		wantCurrency string
+		wantErr      error
+	}{
+		{name: "free event without currency", priceCents: 0, currency: ""},
+		{name: "paid event", priceCents: 2500, currency: "EUR", wantCurrency: "EUR"},
+		{name: "normalizes currency case", priceCents: 2500, currency: "usd", wantCurrency: "USD"},
+		{name: "rejects negative price", priceCents: -1, currency: "EUR", wantErr: ErrInvalidPrice},
+		{name: "rejects price above bound", priceCents: MaxPriceCents + 1, currency: "EUR", wantErr: ErrInvalidPrice},
+		{name: "rejects paid event without currency", priceCents: 2500, currency: "", wantErr: ErrInvalidCurrency},
+		{name: "rejects malformed currency", priceCents: 2500, currency: "EURO", wantErr: ErrInvalidCurrency},
+		{name: "rejects non-letter currency", priceCents: 2500, currency: "E1R", wantErr: ErrInvalidCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := NewEvent("Gig", "Club", time.Now().Add(24*time.Hour), 10, WithPrice(tt.priceCents, tt.currency))
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr))
+				assert.Nil(t, event)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.priceCents, event.PriceCents)
+			assert.Equal(t, tt.wantCurrency, event.Currency)
+		})
+	}
+}
+
+func TestEvent_Quote(t *testing.T) {
+	paid, err := NewEvent("Gig", "Club", time.Now().Add(24*time.Hour), 10, WithPrice(2500, "EUR"))
+	require.NoError(t, err)
+	free, err := NewEvent("Open Day", "Park", time.Now().Add(24*time.Hour), 10)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		event   *Event
+		tickets int
+		want    Quote
+		wantErr error
+	}{
+		{
+			name:    "multiplies the ticket price",
+			event:   paid,
+			tickets: 3,
+			want:    Quote{Tickets: 3, UnitPriceCents: 2500, SubtotalCents: 7500, TotalCents: 7500, Currency: "EUR"},
+		},
+		{name: "free event costs nothing", event: free, tickets: 2, want: Quote{Tickets: 2}},
+		{name: "rejects zero tickets", event: paid, tickets: 0, wantErr: ErrInvalidTicketCount},
+		{name: "rejects more tickets than the event has", event: paid, tickets: 11, wantErr: ErrExceedsCapacity},
+		{name: "rejects counts above the global bound", event: paid, tickets: MaxTickets + 1, wantErr: ErrTicketCountTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, err := tt.event.Quote(tt.tickets)
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, quote)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -25,24 +26,193 @@ type Transaction interface {
 type EventRepository interface {
 	Create(ctx context.Context, event *Event) error
 	FindByID(ctx context.Context, id uuid.UUID) (*Event, error)
-	FindAll(ctx context.Context) ([]*Event, error)
+	// FindByIDs returns the events with the given IDs in one query; missing IDs are omitted
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*Event, error)
+	// FindAll returns every event ordered by date, and how many malformed rows it skipped instead of failing
+	FindAll(ctx context.Context) (events []*Event, skipped int, err error)
+	// FindPage returns up to limit events matching filter ordered by date and ID, starting after the cursor when it is set
+	FindPage(ctx context.Context, filter EventFilter, after *EventCursor, limit int) ([]*Event, error)
+	Count(ctx context.Context, filter EventFilter) (int, error)
+	// LastModified returns when any event was last created or updated, or zero when there are no events
+	LastModified(ctx context.Context) (time.Time, error)
+	// FindUpcoming returns active events dated at or after from, soonest first
+	// With onlyAvailable, events without available tickets are left out
+	FindUpcoming(ctx context.Context, from time.Time, onlyAvailable bool, limit, offset int) ([]*Event, error)
+	// CountUpcoming counts the events FindUpcoming pages through
+	CountUpcoming(ctx context.Context, from time.Time, onlyAvailable bool) (int, error)
+	// FindByNameAndDate returns an event with the given name (case-insensitive) on the same UTC calendar day as date
+	FindByNameAndDate(ctx context.Context, name string, date time.Time) (*Event, error)
+	// Stream calls fn for each event ordered by date, without buffering the result set
+	Stream(ctx context.Context, fn func(*Event) error) error
 	Update(ctx context.Context, event *Event) error
+	// FindSummariesByOrganizer returns the organizer's events with booking aggregates, ordered by date
+	FindSummariesByOrganizer(ctx context.Context, organizerID uuid.UUID, limit, offset int) ([]*EventBookingSummary, error)
+	CountByOrganizer(ctx context.Context, organizerID uuid.UUID) (int, error)
+	// FindBookedByUser returns the distinct events the user holds bookings for that are not cancelled, ordered by date
+	FindBookedByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*Event, error)
+	CountBookedByUser(ctx context.Context, userID uuid.UUID) (int, error)
+	// SumTicketsByUser returns the tickets the user holds per event in bookings that are not cancelled, ordered by date
+	SumTicketsByUser(ctx context.Context, userID uuid.UUID) ([]*UserEventTickets, error)
 	// Transaction-aware method for atomic event+availability creation
 	CreateWithExecutor(ctx context.Context, exec Executor, event *Event) error
+	FindByIDWithLock(ctx context.Context, exec Executor, id uuid.UUID) (*Event, error)
+	// FindByIDForShare reads the event under a shared lock, which blocks FindByIDWithLock until tx ends
+	FindByIDForShare(ctx context.Context, exec Executor, id uuid.UUID) (*Event, error)
+	// FindByIDsForShare reads the events under shared locks, taken in ID order, in one query; missing IDs are omitted
+	FindByIDsForShare(ctx context.Context, exec Executor, ids []uuid.UUID) ([]*Event, error)
+	UpdateWithExecutor(ctx context.Context, exec Executor, event *Event) error
+	// MarkMergedWithExecutor soft-deletes the event as a duplicate of mergedInto; it is then treated as not found
+	MarkMergedWithExecutor(ctx context.Context, exec Executor, id, mergedInto uuid.UUID) error
 }
 
 type BookingRepository interface {
 	Create(ctx context.Context, booking *Booking) error
 	FindByID(ctx context.Context, id uuid.UUID) (*Booking, error)
+	// FindByConfirmationCodePrefix returns bookings whose code starts with the upper-case prefix, ordered by code
+	FindByConfirmationCodePrefix(ctx context.Context, prefix string, limit, offset int) ([]*Booking, error)
+	CountByConfirmationCodePrefix(ctx context.Context, prefix string) (int, error)
+	// FindByUser returns the user's bookings, cancelled ones included, newest first
+	FindByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*Booking, error)
+	CountByUser(ctx context.Context, userID uuid.UUID) (int, error)
+	// Stream calls fn for each booking matching filter, ordered by booked_at, without buffering the result set
+	Stream(ctx context.Context, filter BookingFilter, fn func(*Booking) error) error
 	// Transaction-aware methods
 	CreateWithExecutor(ctx context.Context, exec Executor, booking *Booking) error
+	// CreateBatchWithExecutor inserts all bookings with a single multi-row statement
+	CreateBatchWithExecutor(ctx context.Context, exec Executor, bookings []*Booking) error
+	FindPendingByEventWithLock(ctx context.Context, exec Executor, eventID uuid.UUID) ([]*Booking, error)
+	FindByIDWithLock(ctx context.Context, exec Executor, id uuid.UUID) (*Booking, error)
+	// FindExpiredWithLock locks up to limit pending reservations of any tenant past their deadline, skipping rows locked elsewhere
+	FindExpiredWithLock(ctx context.Context, exec Executor, now time.Time, limit int) ([]*Booking, error)
+	// ExpireWithExecutor persists an expired reservation's cancellation, whatever its tenant
+	ExpireWithExecutor(ctx context.Context, exec Executor, booking *Booking) error
+	// FindActiveByEventWithLock locks the event's bookings that are not cancelled, in ID order (FOR UPDATE)
+	FindActiveByEventWithLock(ctx context.Context, exec Executor, eventID uuid.UUID) ([]*Booking, error)
+	// FindActiveByUserWithLock locks the user's bookings that are not cancelled, ordered by event ID (FOR UPDATE)
+	FindActiveByUserWithLock(ctx context.Context, exec Executor, userID uuid.UUID) ([]*Booking, error)
+	// SumTicketsByEventWithExecutor totals tickets of bookings that are not cancelled
+	SumTicketsByEventWithExecutor(ctx context.Context, exec Executor, eventID uuid.UUID) (int, error)
+	UpdateStatusWithExecutor(ctx context.Context, exec Executor, booking *Booking) error
+	// SplitWithExecutor persists original's remaining tickets and price and creates split from its other tickets
+	SplitWithExecutor(ctx context.Context, exec Executor, original, split *Booking) error
+	// ReassignEventWithExecutor moves every booking of fromEventID to toEventID and returns how many moved
+	// Bookings leave the source's allocations, so their tickets count against the target's public availability
+	ReassignEventWithExecutor(ctx context.Context, exec Executor, fromEventID, toEventID uuid.UUID) (int, error)
+	// FindChanges returns the booking's change log, oldest first
+	FindChanges(ctx context.Context, bookingID uuid.UUID) ([]*BookingChange, error)
+	// FindLedger returns the changes of the event's public bookings that moved its availability, oldest first
+	FindLedger(ctx context.Context, eventID uuid.UUID, limit, offset int) ([]*BookingChange, error)
+	CountLedger(ctx context.Context, eventID uuid.UUID) (int, error)
+	// SumLedgerByAction totals the tickets of the event's first n ledger changes, one change per action
+	SumLedgerByAction(ctx context.Context, eventID uuid.UUID, n int) ([]*BookingChange, error)
+}
+
+type HoldRepository interface {
+	FindByID(ctx context.Context, id uuid.UUID) (*Hold, error)
+	// Transaction-aware methods
+	CreateWithExecutor(ctx context.Context, exec Executor, hold *Hold) error
+	FindByIDWithLock(ctx context.Context, exec Executor, id uuid.UUID) (*Hold, error)
+	UpdateWithExecutor(ctx context.Context, exec Executor, hold *Hold) error
+	// FindExpiredWithLock locks up to limit active holds past their expiry, skipping rows locked elsewhere
+	FindExpiredWithLock(ctx context.Context, exec Executor, now time.Time, limit int) ([]*Hold, error)
+	// FindExpiringWithLock locks up to limit active holds expiring within lead of now that have no expiry notice yet
+	FindExpiringWithLock(ctx context.Context, exec Executor, now time.Time, lead time.Duration, limit int) ([]*Hold, error)
+}
+
+type IdempotencyKeyRepository interface {
+	// DeleteExpired removes up to limit keys that expired at or before now and returns how many were removed
+	DeleteExpired(ctx context.Context, now time.Time, limit int) (int, error)
+	// Transaction-aware methods
+	// ClaimWithExecutor stores the key unless an unexpired one exists and reports whether this call stored it
+	// An expired key is replaced; exactly one of several concurrent transactions claiming the same key wins
+	ClaimWithExecutor(ctx context.Context, exec Executor, key *IdempotencyKey) (bool, error)
+	FindByKeyWithExecutor(ctx context.Context, exec Executor, key string) (*IdempotencyKey, error)
+	SetBookingWithExecutor(ctx context.Context, exec Executor, key string, bookingID uuid.UUID) error
+}
+
+type DiscountCodeRepository interface {
+	Create(ctx context.Context, code *DiscountCode) error
+	// Transaction-aware methods
+	// FindByCodeWithLock locks the code's row so concurrent redemptions cannot exceed its max uses
+	FindByCodeWithLock(ctx context.Context, exec Executor, code string) (*DiscountCode, error)
+	UpdateUsesWithExecutor(ctx context.Context, exec Executor, code *DiscountCode) error
+}
+
+// LotteryRepository stores lottery entries per event and whether the event was drawn
+type LotteryRepository interface {
+	// Transaction-aware methods
+	CreateWithExecutor(ctx context.Context, exec Executor, entry *LotteryEntry) error
+	// IsDrawnWithExecutor reports whether the event's lottery was drawn
+	IsDrawnWithExecutor(ctx context.Context, exec Executor, eventID uuid.UUID) (bool, error)
+	// FindByEventWithLock locks the event's entries in registration order (FOR UPDATE)
+	FindByEventWithLock(ctx context.Context, exec Executor, eventID uuid.UUID) ([]*LotteryEntry, error)
+	UpdateBatchWithExecutor(ctx context.Context, exec Executor, entries []*LotteryEntry) error
+}
+
+// AllocationRepository stores the named ticket blocks set aside from public sale per event
+type AllocationRepository interface {
+	CreateWithExecutor(ctx context.Context, exec Executor, allocation *Allocation) error
+	// FindByEvent returns the event's allocations ordered by name
+	FindByEvent(ctx context.Context, eventID uuid.UUID) ([]*Allocation, error)
+	// FindByNameWithLock locks the event's allocation of that name (FOR UPDATE)
+	FindByNameWithLock(ctx context.Context, exec Executor, eventID uuid.UUID, name string) (*Allocation, error)
+	// FindByIDWithLock locks an allocation (FOR UPDATE), e.g. to return a cancelled booking's tickets
+	FindByIDWithLock(ctx context.Context, exec Executor, id uuid.UUID) (*Allocation, error)
+	UpdateWithExecutor(ctx context.Context, exec Executor, allocation *Allocation) error
+}
+
+// WebhookRepository stores the webhooks registered per event and the deliveries that failed
+type WebhookRepository interface {
+	Create(ctx context.Context, hook *EventWebhook) error
+	FindByEvent(ctx context.Context, eventID uuid.UUID) ([]*EventWebhook, error)
+	// Delete removes the event's webhook, returning ErrWebhookNotFound when the event has no such hook
+	Delete(ctx context.Context, eventID, id uuid.UUID) error
+	// CreateDeliveryFailure stores a notification that could not be delivered, as a dead letter
+	CreateDeliveryFailure(ctx context.Context, failure *WebhookDeliveryFailure) error
+	// FindDeliveryFailures pages through the dead letters, most recent first
+	FindDeliveryFailures(ctx context.Context, limit, offset int) ([]*WebhookDeliveryFailure, error)
+	CountDeliveryFailures(ctx context.Context) (int, error)
+	// FindDeliveryFailure returns ErrDeliveryFailureNotFound when there is no such dead letter
+	FindDeliveryFailure(ctx context.Context, id uuid.UUID) (*WebhookDeliveryFailure, error)
+	// DeleteDeliveryFailure returns ErrDeliveryFailureNotFound when the dead letter is already gone
+	DeleteDeliveryFailure(ctx context.Context, id uuid.UUID) error
+}
+
+type SeatRepository interface {
+	// FindByEvent returns the event's seats ordered by label
+	FindByEvent(ctx context.Context, eventID uuid.UUID) ([]*Seat, error)
+	FindByBooking(ctx context.Context, bookingID uuid.UUID) ([]*Seat, error)
+	// Transaction-aware methods
+	CreateBatchWithExecutor(ctx context.Context, exec Executor, seats []*Seat) error
+	// FindByLabelsWithLock locks the event's seats with the given labels; labels that do not exist are omitted
+	FindByLabelsWithLock(ctx context.Context, exec Executor, eventID uuid.UUID, labels []string) ([]*Seat, error)
+	UpdateWithExecutor(ctx context.Context, exec Executor, seats []*Seat) error
+	// ReleaseByBookingWithExecutor frees every seat held by the booking
+	ReleaseByBookingWithExecutor(ctx context.Context, exec Executor, bookingID uuid.UUID) error
 }
 
 type TicketAvailabilityRepository interface {
 	Create(ctx context.Context, availability *TicketAvailability) error
 	FindByEventID(ctx context.Context, eventID uuid.UUID) (*TicketAvailability, error)
+	// AdjustAvailableTickets atomically applies a signed delta, refusing results outside [0, event tickets]
+	// A non-zero expectedVersion also refuses the update with ErrAvailabilityVersionMismatch unless it is current
+	AdjustAvailableTickets(ctx context.Context, eventID uuid.UUID, delta int, expectedVersion int64) (*TicketAvailability, error)
 	// Transaction-aware methods
 	CreateWithExecutor(ctx context.Context, exec Executor, availability *TicketAvailability) error
 	FindByEventIDWithLock(ctx context.Context, exec Executor, eventID uuid.UUID) (*TicketAvailability, error)
-	UpdateWithExecutor(ctx context.Context, exec Executor, availability *TicketAvailability) error
+	// FindByEventIDs reads the availability of several events in one query, without locks
+	FindByEventIDs(ctx context.Context, eventIDs []uuid.UUID) ([]*TicketAvailability, error)
+	// FindByEventIDsWithLock locks the availability of several events in event ID order in one query
+	FindByEventIDsWithLock(ctx context.Context, exec Executor, eventIDs []uuid.UUID) ([]*TicketAvailability, error)
+	// UpdateWithExecutor writes the availability and logs the change with reason in the same statement
+	UpdateWithExecutor(ctx context.Context, exec Executor, availability *TicketAvailability, reason AvailabilityChangeReason) error
+	// UpdateBatchWithExecutor writes several availabilities with a single statement
+	UpdateBatchWithExecutor(ctx context.Context, exec Executor, availabilities []*TicketAvailability, reason AvailabilityChangeReason) error
+	// ExpectedAvailableWithExecutor derives each event's availability from its data: the total less allocated
+	// blocks, public bookings that are not cancelled and active holds. Unknown events are left out
+	ExpectedAvailableWithExecutor(ctx context.Context, exec Executor, eventIDs []uuid.UUID) (map[uuid.UUID]int, error)
+	// FindChangeAt returns the latest logged change at or before at
+	FindChangeAt(ctx context.Context, eventID uuid.UUID, at time.Time) (*AvailabilityChange, error)
+	// LastChangedAt returns when the availability of any of the events last changed, or zero when none was logged
+	LastChangedAt(ctx context.Context, eventIDs []uuid.UUID) (time.Time, error)
 }
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+type SeatStatus string
+
+const (
+	SeatStatusFree     SeatStatus = "free"
+	SeatStatusReserved SeatStatus = "reserved"
+)
+
+// MaxSeatLabelLen bounds a seat label, in characters, to what the seats table stores
+const MaxSeatLabelLen = 20
+
+// Seat is a single bookable place at a reserved-seating event
+type Seat struct {
+	EventID   uuid.UUID
+	Label     string
+	Status    SeatStatus
+	BookingID uuid.UUID // uuid.Nil while the seat is free
+}
+
+// NewSeatMap creates the free seats of an event from their labels
+// Labels must be non-empty, unique and at most MaxSeatLabelLen characters, and there must be exactly one per ticket
+func NewSeatMap(eventID uuid.UUID, labels []string, tickets int) ([]*Seat, error) {
+	if len(labels) != tickets || !uniqueLabels(labels) {
+		return nil, ErrInvalidSeatMap
+	}
+	if err := checkSeatLabelLengths(labels); err != nil {
+		return nil, err
+	}
+
+	seats := make([]*Seat, 0, len(labels))
+	for _, label := range labels {
+		seats = append(seats, &Seat{
+			EventID: eventID,
+			Label:   label,
+			Status:  SeatStatusFree,
+		})
+	}
+	return seats, nil
+}
+
+// ValidateSeatSelection checks a booking selects one distinct, non-empty seat per ticket
+func ValidateSeatSelection(labels []string, tickets int) error {
+	if len(labels) != tickets || !uniqueLabels(labels) {
+		return ErrInvalidSeatSelection
+	}
+	return checkSeatLabelLengths(labels)
+}
+
+// checkSeatLabelLengths rejects labels longer than MaxSeatLabelLen, which could never name a stored seat
+func checkSeatLabelLengths(labels []string) error {
+	for _, label := range labels {
+		if utf8.RuneCountInString(label) > MaxSeatLabelLen {
+			return ErrSeatLabelTooLong
+		}
+	}
+	return nil
+}
+
+// ReserveSeats assigns every requested seat to bookingID, or none of them
+// seats are the event's seats matching requested; a label missing from seats does not exist
+func ReserveSeats(seats []*Seat, requested []string, bookingID uuid.UUID) error {
+	byLabel := make(map[string]*Seat, len(seats))
+	for _, seat := range seats {
+		byLabel[seat.Label] = seat
+	}
+
+	for _, label := range requested {
+		seat, ok := byLabel[label]
+		if !ok {
+			return ErrUnknownSeat
+		}
+		if seat.Status != SeatStatusFree {
+			return ErrSeatTaken
+		}
+	}
+
+	for _, label := range requested {
+		seat := byLabel[label]
+		seat.Status = SeatStatusReserved
+		seat.BookingID = bookingID
+	}
+	return nil
+}
+
+func uniqueLabels(labels []string) bool {
+	seen := make(map[string]struct{}, len(labels))
+	for _, label := range labels {
+		if strings.TrimSpace(label) == "" {
+			return false
+		}
+		if _, ok := seen[label]; ok {
+			return false
+		}
+		seen[label] = struct{}{}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSeatMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		labels  []string
+		tickets int
+		wantErr error
+	}{
+		{name: "creates one free seat per label", labels: []string{"A1", "A2", "B1"}, tickets: 3},
+		{name: "rejects count mismatch", labels: []string{"A1", "A2"}, tickets: 3, wantErr: ErrInvalidSeatMap},
+		{name: "rejects duplicate labels", labels: []string{"A1", "A1"}, tickets: 2, wantErr: ErrInvalidSeatMap},
+		{name: "rejects blank labels", labels: []string{"A1", " "}, tickets: 2, wantErr: ErrInvalidSeatMap},
+		{name: "accepts labels of the maximum length", labels: []string{"Balcony-Left-Row-Z99"}, tickets: 1},
+		{name: "rejects labels over the maximum length", labels: []string{"A1", "Balcony-Left-Row-Z100"}, tickets: 2, wantErr: ErrSeatLabelTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventID := uuid.New()
+			seats, err := NewSeatMap(eventID, tt.labels, tt.tickets)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, seats, len(tt.labels))
+			for i, seat := range seats {
+				assert.Equal(t, eventID, seat.EventID)
+				assert.Equal(t, tt.labels[i], seat.Label)
+				assert.Equal(t, SeatStatusFree, seat.Status)
+			}
+		})
+	}
+}
+
+func TestReserveSeats(t *testing.T) {
+	bookingID := uuid.New()
+	otherBooking := uuid.New()
+
+	tests := []struct {
+		name      string
+		seats     []*Seat
+		requested []string
+		wantErr   error
+	}{
+		{
+			name:      "reserves all requested free seats",
+			seats:     []*Seat{{Label: "A1", Status: SeatStatusFree}, {Label: "A2", Status: SeatStatusFree}},
+			requested: []string{"A1", "A2"},
+		},
+		{
+			name:      "fails when any seat is taken",
+			seats:     []*Seat{{Label: "A1", Status: SeatStatusFree}, {Label: "A2", Status: SeatStatusReserved, BookingID: otherBooking}},
+			requested: []string{"A1", "A2"},
+			wantErr:   ErrSeatTaken,
+		},
+		{
+			name:      "fails when a seat does not exist",
+			seats:     []*Seat{{Label: "A1", Status: SeatStatusFree}},
+			requested: []string{"A1", "Z9"},
+			wantErr:   ErrUnknownSeat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := make([]Seat, len(tt.seats))
+			for i, seat := range tt.seats {
+				before[i] = *seat
+			}
+
+			err := ReserveSeats(tt.seats, tt.requested, bookingID)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				for i, seat := range tt.seats {
+					assert.Equal(t, before[i], *seat, "a failed reservation must not change any seat")
+				}
+				return
+			}
+			require.NoError(t, err)
+			for _, seat := range tt.seats {
+				assert.Equal(t, SeatStatusReserved, seat.Status)
+				assert.Equal(t, bookingID, seat.BookingID)
+			}
+		})
+	}
+}
+
+func TestEvent_CheckSeatSelection(t *testing.T) {
+	assert.NoError(t, (&Event{}).CheckSeatSelection(nil))
+	assert.ErrorIs(t, (&Event{}).CheckSeatSelection([]string{"A1"}), ErrSeatingNotSupported)
+	assert.ErrorIs(t, (&Event{Seated: true}).CheckSeatSelection(nil), ErrSeatSelectionRequired)
+	assert.NoError(t, (&Event{Seated: true}).CheckSeatSelection([]string{"A1"}))
+}
+
+func TestValidateSeatSelection(t *testing.T) {
+	assert.NoError(t, ValidateSeatSelection([]string{"A1", "Balcony-Left-Row-Z99"}, 2))
+	assert.ErrorIs(t, ValidateSeatSelection([]string{"A1"}, 2), ErrInvalidSeatSelection)
+	assert.ErrorIs(t, ValidateSeatSelection([]string{"Balcony-Left-Row-Z100"}, 1), ErrSeatLabelTooLong)
+	assert.NoError(t, ValidateSeatSelection([]string{"Łódź-Sala-Główna-Ą1"}, 1), "labels are measured in characters")
+}
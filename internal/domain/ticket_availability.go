@@ -1,33 +1,56 @@
 package domain
 
 import (
+	"math"
+
 	"github.com/google/uuid"
 )
 
+// MaxTickets bounds every ticket quantity: event totals, availability, bookings, holds and adjustments
+// Keeping quantities far below the INT column range means no sum or delta of them can overflow
+const MaxTickets = 1_000_000
+
+// MaxAvailabilityChecks caps the items of one bulk availability check, which reads them all in one query
+const MaxAvailabilityChecks = 100
+
 // TicketAvailability is an aggregate that protects ticket reservation invariants
 // It represents the consistency boundary for booking operations
 type TicketAvailability struct {
 	EventID          uuid.UUID
 	AvailableTickets int
+	// Version starts at 1 and is bumped by every write, so callers can detect changes since they read
+	Version int64
 }
 
 func NewTicketAvailability(eventID uuid.UUID, availableTickets int) (*TicketAvailability, error) {
 	if availableTickets < 0 {
 		return nil, ErrInvalidAvailableTickets
 	}
+	if availableTickets > MaxTickets {
+		return nil, ErrTicketsTooLarge
+	}
 
 	return &TicketAvailability{
 		EventID:          eventID,
 		AvailableTickets: availableTickets,
+		Version:          1,
 	}, nil
 }
 
 // ReserveTickets attempts to reserve the specified number of tickets
 // This method enforces the invariant: AvailableTickets >= 0
-func (ta *TicketAvailability) ReserveTickets(count int) error {
+// More tickets than the event's total can never be reserved, so that fails with ErrExceedsCapacity rather
+// than the ErrInsufficientTickets of a shortage that cancellations or released holds may resolve
+func (ta *TicketAvailability) ReserveTickets(count, total int) error {
 	if count <= 0 {
 		return ErrInvalidTicketCount
 	}
+	if count > MaxTickets {
+		return ErrTicketCountTooLarge
+	}
+	if count > total {
+		return ErrExceedsCapacity
+	}
 
 	if ta.AvailableTickets < count {
 		return ErrInsufficientTickets
@@ -36,3 +59,112 @@ func (ta *TicketAvailability) ReserveTickets(count int) error {
 	ta.AvailableTickets -= count
 	return nil
 }
+
+// ReserveAll reserves every available ticket that can be sold in multiples of step and returns how many
+// were taken; fewer than step tickets left is ErrNothingToBuyOut
+func (ta *TicketAvailability) ReserveAll(step int) (int, error) {
+	step = max(step, 1)
+	count := ta.AvailableTickets - ta.AvailableTickets%step
+	if count <= 0 {
+		return 0, ErrNothingToBuyOut
+	}
+
+	ta.AvailableTickets -= count
+	return count, nil
+}
+
+// ReleaseTickets returns previously reserved tickets to the pool
+func (ta *TicketAvailability) ReleaseTickets(count int) error {
+	if count <= 0 {
+		return ErrInvalidTicketCount
+	}
+	if count > MaxTickets {
+		return ErrTicketCountTooLarge
+	}
+	if ta.AvailableTickets+count > MaxTickets {
+		return ErrAvailabilityOverflow
+	}
+
+	ta.AvailableTickets += count
+	return nil
+}
+
+// Allocate moves count available tickets into an allocation block, out of public sale
+func (ta *TicketAvailability) Allocate(count int) error {
+	if count <= 0 {
+		return ErrInvalidTickets
+	}
+	if ta.AvailableTickets < count {
+		return ErrAllocationExceedsAvailable
+	}
+
+	ta.AvailableTickets -= count
+	return nil
+}
+
+// AbsorbMerged takes count tickets for bookings moved in from a merged duplicate event
+// Unlike ReserveTickets it accepts 0, a duplicate without bookings, and fails with ErrMergeOverbooks on a shortage
+func (ta *TicketAvailability) AbsorbMerged(count int) error {
+	if count < 0 {
+		return ErrInvalidTicketCount
+	}
+	if ta.AvailableTickets < count {
+		return ErrMergeOverbooks
+	}
+
+	ta.AvailableTickets -= count
+	return nil
+}
+
+// Reconcile sets the available tickets to expected, the count derived from the event's bookings, and reports
+// whether they had drifted from it. An overbooked event, expected below zero, is left with none available
+func (ta *TicketAvailability) Reconcile(expected int) bool {
+	expected = max(expected, 0)
+	if ta.AvailableTickets == expected {
+		return false
+	}
+
+	ta.AvailableTickets = expected
+	return true
+}
+
+// AdjustAvailable shifts available tickets by a signed delta without changing the event's total
+// The result must stay within [0, total]; capacity changes that also move the total are a separate operation
+func (ta *TicketAvailability) AdjustAvailable(delta, total int) error {
+	if delta == 0 {
+		return ErrInvalidAvailabilityDelta
+	}
+	if delta > MaxTickets || delta < -MaxTickets {
+		return ErrAvailabilityDeltaTooLarge
+	}
+
+	adjusted := ta.AvailableTickets + delta
+	if adjusted < 0 {
+		return ErrAvailabilityUnderflow
+	}
+	if adjusted > total {
+		return ErrAvailabilityOverflow
+	}
+
+	ta.AvailableTickets = adjusted
+	return nil
+}
+
+// Utilization is how much of an event's capacity has been taken
+type Utilization struct {
+	Sold    int
+	Total   int
+	Percent float64 // 0 to 100, rounded to two decimals
+}
+
+// Utilization reports the tickets taken out of total, where total is the event's ticket count
+// Tickets held or booked count as sold; cancelled bookings do not, since cancelling returns them to availability
+func (ta *TicketAvailability) Utilization(total int) Utilization {
+	sold := max(total-ta.AvailableTickets, 0)
+	if total <= 0 {
+		return Utilization{Sold: sold, Total: total}
+	}
+
+	percent := float64(sold) / float64(total) * 100
+	return Utilization{Sold: sold, Total: total, Percent: math.Round(percent*100) / 100}
+}
@@ -2,7 +2,9 @@ package domain
 
 import (
 	"errors"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -58,6 +60,7 @@ func TestNewTicketAvailability(t *testing.T) {
 func TestTicketAvailability_ReserveTickets(t *testing.T) {
 	tests := []struct {
 		name              string
+		totalTickets      int
 		availableTickets  int
 		requestedTickets  int
 		wantErr           bool
@@ -85,6 +88,30 @@ func TestTicketAvailability_ReserveTickets(t *testing.T) {
 			wantErr:          true,
 			errType:          ErrInsufficientTickets,
 		},
+		{
+			name:             "returns insufficient tickets for a shortage within capacity",
+			totalTickets:     100,
+			availableTickets: 0,
+			requestedTickets: 100,
+			wantErr:          true,
+			errType:          ErrInsufficientTickets,
+		},
+		{
+			name:             "returns error when requesting more than the event's capacity",
+			totalTickets:     100,
+			availableTickets: 100,
+			requestedTickets: 1000,
+			wantErr:          true,
+			errType:          ErrExceedsCapacity,
+		},
+		{
+			name:             "capacity is checked before availability",
+			totalTickets:     100,
+			availableTickets: 5,
+			requestedTickets: 101,
+			wantErr:          true,
+			errType:          ErrExceedsCapacity,
+		},
 		{
 			name:             "returns error for zero tickets",
 			availableTickets: 100,
@@ -108,7 +135,12 @@ func TestTicketAvailability_ReserveTickets(t *testing.T) {
 				AvailableTickets: tt.availableTickets,
 			}
 
-			err := availability.ReserveTickets(tt.requestedTickets)
+			total := tt.totalTickets
+			if total == 0 {
+				total = 100
+			}
+
+			err := availability.ReserveTickets(tt.requestedTickets, total)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -120,3 +152,258 @@ func TestTicketAvailability_ReserveTickets(t *testing.T) {
 		})
 	}
 }
+
+func TestTicketAvailability_ReserveAll(t *testing.T) {
+	availability := &TicketAvailability{EventID: uuid.New(), AvailableTickets: 42}
+
+	reserved, err := availability.ReserveAll(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, reserved)
+	assert.Equal(t, 0, availability.AvailableTickets)
+
+	_, err = availability.ReserveAll(1)
+	assert.ErrorIs(t, err, ErrNothingToBuyOut)
+
+	t.Run("takes the largest multiple of the step", func(t *testing.T) {
+		availability := &TicketAvailability{EventID: uuid.New(), AvailableTickets: 10}
+
+		reserved, err := availability.ReserveAll(4)
+		assert.NoError(t, err)
+		assert.Equal(t, 8, reserved)
+		assert.Equal(t, 2, availability.AvailableTickets)
+
+		_, err = availability.ReserveAll(4)
+		assert.ErrorIs(t, err, ErrNothingToBuyOut)
+		assert.Equal(t, 2, availability.AvailableTickets)
+	})
+}
+
+func TestTicketAvailability_AdjustAvailable(t *testing.T) {
+	tests := []struct {
+		name          string
+		available     int
+		total         int
+		delta         int
+		wantErr       error
+		wantAvailable int
+	}{
+		{
+			name:          "releases tickets back to the pool",
+			available:     40,
+			total:         100,
+			delta:         10,
+			wantAvailable: 50,
+		},
+		{
+			name:          "withholds tickets from the pool",
+			available:     40,
+			total:         100,
+			delta:         -40,
+			wantAvailable: 0,
+		},
+		{
+			name:          "allows raising availability up to the total",
+			available:     90,
+			total:         100,
+			delta:         10,
+			wantAvailable: 100,
+		},
+		{
+			name:          "rejects dropping below zero",
+			available:     5,
+			total:         100,
+			delta:         -6,
+			wantErr:       ErrAvailabilityUnderflow,
+			wantAvailable: 5,
+		},
+		{
+			name:          "rejects exceeding the total",
+			available:     95,
+			total:         100,
+			delta:         6,
+			wantErr:       ErrAvailabilityOverflow,
+			wantAvailable: 95,
+		},
+		{
+			name:          "rejects zero delta",
+			available:     5,
+			total:         100,
+			delta:         0,
+			wantErr:       ErrInvalidAvailabilityDelta,
+			wantAvailable: 5,
+		},
+		{
+			name:          "rejects a delta beyond the ticket bound",
+			available:     5,
+			total:         MaxTickets,
+			delta:         math.MaxInt,
+			wantErr:       ErrAvailabilityDeltaTooLarge,
+			wantAvailable: 5,
+		},
+		{
+			name:          "rejects a negative delta beyond the ticket bound",
+			available:     5,
+			total:         MaxTickets,
+			delta:         math.MinInt,
+			wantErr:       ErrAvailabilityDeltaTooLarge,
+			wantAvailable: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			availability := &TicketAvailability{EventID: uuid.New(), AvailableTickets: tt.available}
+
+			err := availability.AdjustAvailable(tt.delta, tt.total)
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr))
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantAvailable, availability.AvailableTickets)
+		})
+	}
+}
+
+func TestTicketQuantityBounds(t *testing.T) {
+	eventID := uuid.New()
+	userID := uuid.New()
+
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr error
+	}{
+		{
+			name: "event at the bound",
+			run: func() error {
+				_, err := NewEvent("Stadium Tour", "Stadium", time.Now().Add(time.Hour), MaxTickets)
+				return err
+			},
+		},
+		{
+			name: "event beyond the bound",
+			run: func() error {
+				_, err := NewEvent("Stadium Tour", "Stadium", time.Now().Add(time.Hour), MaxTickets+1)
+				return err
+			},
+			wantErr: ErrTicketsTooLarge,
+		},
+		{
+			name: "availability beyond the bound",
+			run: func() error {
+				_, err := NewTicketAvailability(eventID, math.MaxInt)
+				return err
+			},
+			wantErr: ErrTicketsTooLarge,
+		},
+		{
+			name: "booking at the bound",
+			run: func() error {
+				_, err := NewBooking(eventID, userID, MaxTickets)
+				return err
+			},
+		},
+		{
+			name: "booking beyond the bound",
+			run: func() error {
+				_, err := NewBooking(eventID, userID, MaxTickets+1)
+				return err
+			},
+			wantErr: ErrTicketCountTooLarge,
+		},
+		{
+			name: "hold beyond the bound",
+			run: func() error {
+				_, err := NewHold(eventID, userID, math.MaxInt, time.Minute, time.Now())
+				return err
+			},
+			wantErr: ErrTicketCountTooLarge,
+		},
+		{
+			name: "reservation beyond the bound",
+			run: func() error {
+				return (&TicketAvailability{EventID: eventID, AvailableTickets: MaxTickets}).ReserveTickets(MaxTickets+1, MaxTickets)
+			},
+			wantErr: ErrTicketCountTooLarge,
+		},
+		{
+			name: "release filling availability to the bound",
+			run: func() error {
+				return (&TicketAvailability{EventID: eventID, AvailableTickets: MaxTickets - 10}).ReleaseTickets(10)
+			},
+		},
+		{
+			name: "release that would push availability past the bound",
+			run: func() error {
+				return (&TicketAvailability{EventID: eventID, AvailableTickets: MaxTickets}).ReleaseTickets(1)
+			},
+			wantErr: ErrAvailabilityOverflow,
+		},
+		{
+			name: "release that would overflow int",
+			run: func() error {
+				return (&TicketAvailability{EventID: eventID, AvailableTickets: 10}).ReleaseTickets(math.MaxInt)
+			},
+			wantErr: ErrTicketCountTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTicketAvailability_Utilization(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     int
+		available int
+		want      Utilization
+	}{
+		{name: "partially sold", total: 200, available: 150, want: Utilization{Sold: 50, Total: 200, Percent: 25}},
+		{name: "rounds to two decimals", total: 3, available: 2, want: Utilization{Sold: 1, Total: 3, Percent: 33.33}},
+		{name: "fully sold", total: 80, available: 0, want: Utilization{Sold: 80, Total: 80, Percent: 100}},
+		{name: "nothing sold", total: 80, available: 80, want: Utilization{Sold: 0, Total: 80, Percent: 0}},
+		{name: "zero-capacity event", total: 0, available: 0, want: Utilization{Sold: 0, Total: 0, Percent: 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			availability := &TicketAvailability{EventID: uuid.New(), AvailableTickets: tt.available}
+
+			assert.Equal(t, tt.want, availability.Utilization(tt.total))
+		})
+	}
+}
+
+func TestTicketAvailability_Reconcile(t *testing.T) {
+	availability := &TicketAvailability{AvailableTickets: 7}
+	assert.False(t, availability.Reconcile(7))
+	assert.Equal(t, 7, availability.AvailableTickets)
+
+	assert.True(t, availability.Reconcile(4))
+	assert.Equal(t, 4, availability.AvailableTickets)
+
+	assert.True(t, availability.Reconcile(-3), "an overbooked event has drifted")
+	assert.Equal(t, 0, availability.AvailableTickets)
+	assert.False(t, availability.Reconcile(-3))
+}
+
+func TestTicketAvailability_AbsorbMerged(t *testing.T) {
+	availability := &TicketAvailability{AvailableTickets: 7}
+	assert.NoError(t, availability.AbsorbMerged(0))
+	assert.NoError(t, availability.AbsorbMerged(5))
+	assert.Equal(t, 2, availability.AvailableTickets)
+
+	assert.ErrorIs(t, availability.AbsorbMerged(3), ErrMergeOverbooks)
+	assert.Equal(t, 2, availability.AvailableTickets, "a refused merge must not change availability")
+}
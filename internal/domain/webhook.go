@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxWebhookURLLen matches MaxImageURLLen; longer URLs are rejected by many proxies
+	MaxWebhookURLLen = 2048
+	// MinWebhookSecretLen keeps signing secrets long enough that signatures cannot be guessed
+	MinWebhookSecretLen = 16
+	MaxWebhookSecretLen = 256
+)
+
+// WebhookBookingCreated is the type of the notification sent when a booking is made
+const WebhookBookingCreated = "booking.created"
+
+// EventWebhook is a URL an organizer registered to be notified of the event's bookings
+// Deliveries are signed with Secret, so the receiver can tell they came from this service
+type EventWebhook struct {
+	ID        uuid.UUID
+	EventID   uuid.UUID
+	URL       string
+	Secret    string
+	CreatedAt time.Time
+}
+
+// WebhookOption configures optional webhook attributes at registration
+type WebhookOption func(*EventWebhook)
+
+// WithWebhookIDGenerator draws the webhook ID from gen instead of a random UUIDv4
+func WithWebhookIDGenerator(gen IDGenerator) WebhookOption {
+	return func(h *EventWebhook) {
+		h.ID = gen.NewID()
+	}
+}
+
+func NewEventWebhook(eventID uuid.UUID, rawURL, secret string, now time.Time, opts ...WebhookOption) (*EventWebhook, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if !validHTTPURL(rawURL, MaxWebhookURLLen) {
+		return nil, ErrInvalidWebhookURL
+	}
+	if len(secret) < MinWebhookSecretLen || len(secret) > MaxWebhookSecretLen {
+		return nil, ErrInvalidWebhookSecret
+	}
+
+	hook := &EventWebhook{
+		ID:        uuid.New(),
+		EventID:   eventID,
+		URL:       rawURL,
+		Secret:    secret,
+		CreatedAt: now,
+	}
+	for _, opt := range opts {
+		opt(hook)
+	}
+	return hook, nil
+}
+
+// CheckPublicHost rejects a webhook URL naming this machine or an internal network, so registering one
+// cannot make the service post to internal endpoints. Only literal addresses and localhost names are
+// recognized here; hosts resolving to internal addresses are refused when a delivery dials them
+func (h *EventWebhook) CheckPublicHost() error {
+	u, err := url.Parse(h.URL)
+	if err != nil {
+		return ErrInvalidWebhookURL
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrWebhookHostNotAllowed
+	}
+	if ip := net.ParseIP(host); ip != nil && IsInternalIP(ip) {
+		return ErrWebhookHostNotAllowed
+	}
+	return nil
+}
+
+// IsInternalIP reports whether ip is a loopback, link-local, private, shared (carrier-grade NAT) or
+// unspecified address, none of which webhooks may be delivered to
+func IsInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsPrivate() || ip.IsUnspecified() || sharedAddressSpace.Contains(ip)
+}
+
+// sharedAddressSpace is 100.64.0.0/10 (RFC 6598), used inside carrier and cloud networks
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// WebhookDeliveryFailure records a notification a webhook did not accept after every retry, or that could
+// not be queued for delivery, so it can be inspected and replayed instead of being lost
+type WebhookDeliveryFailure struct {
+	ID        uuid.UUID
+	WebhookID uuid.UUID
+	EventID   uuid.UUID
+	BookingID uuid.UUID
+	Type      string
+	Error     string
+	FailedAt  time.Time
+	// Booking is the notified booking as it was sent; failures stored before it was kept have none and
+	// cannot be retried
+	Booking    *Booking
+	OccurredAt time.Time
+	// Attempts counts the deliveries of the notification that failed, one more for each failed retry
+	Attempts int
+}
+
+// Notification rebuilds the notification that failed, or reports false when the payload was not kept
+func (f *WebhookDeliveryFailure) Notification() (WebhookNotification, bool) {
+	if f.Booking == nil {
+		return WebhookNotification{}, false
+	}
+	return WebhookNotification{Type: f.Type, Booking: f.Booking, OccurredAt: f.OccurredAt}, true
+}
+
+// WebhookNotification is what a webhook is told: that something of Type happened to Booking at OccurredAt
+type WebhookNotification struct {
+	Type       string
+	Booking    *Booking
+	OccurredAt time.Time
+}
+
+// WebhookSender delivers a notification to a webhook, signed with its secret
+type WebhookSender interface {
+	Send(ctx context.Context, hook *EventWebhook, notification WebhookNotification) error
+}
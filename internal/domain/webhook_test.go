@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEventWebhook(t *testing.T) {
+	now := time.Date(2026, 6, 20, 19, 0, 0, 0, time.UTC)
+	eventID := uuid.New()
+	const secret = "0123456789abcdef"
+
+	tests := []struct {
+		name    string
+		url     string
+		secret  string
+		wantErr error
+	}{
+		{name: "accepts an https URL", url: "https://hooks.example.com/bookings", secret: secret},
+		{name: "trims the URL", url: "  https://hooks.example.com/bookings  ", secret: secret},
+		{name: "rejects a missing URL", secret: secret, wantErr: ErrInvalidWebhookURL},
+		{name: "rejects other schemes", url: "ftp://hooks.example.com/bookings", secret: secret, wantErr: ErrInvalidWebhookURL},
+		{name: "rejects relative URLs", url: "/bookings", secret: secret, wantErr: ErrInvalidWebhookURL},
+		{name: "rejects overly long URLs", url: "https://hooks.example.com/" + strings.Repeat("a", MaxWebhookURLLen), secret: secret, wantErr: ErrInvalidWebhookURL},
+		{name: "rejects a short secret", url: "https://hooks.example.com/bookings", secret: "short", wantErr: ErrInvalidWebhookSecret},
+		{name: "rejects an overly long secret", url: "https://hooks.example.com/bookings", secret: strings.Repeat("s", MaxWebhookSecretLen+1), wantErr: ErrInvalidWebhookSecret},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook, err := NewEventWebhook(eventID, tt.url, tt.secret, now)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, hook)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, hook.ID)
+			assert.Equal(t, eventID, hook.EventID)
+			assert.Equal(t, "https://hooks.example.com/bookings", hook.URL)
+			assert.Equal(t, tt.secret, hook.Secret)
+			assert.Equal(t, now, hook.CreatedAt)
+		})
+	}
+}
+
+func TestEventWebhook_CheckPublicHost(t *testing.T) {
+	now := time.Date(2026, 6, 20, 19, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		url     string
+		allowed bool
+	}{
+		{url: "https://hooks.example.com/bookings", allowed: true},
+		{url: "https://93.184.216.34/bookings", allowed: true},
+		{url: "http://localhost:8080/admin"},
+		{url: "http://LOCALHOST./admin"},
+		{url: "http://api.localhost/"},
+		{url: "http://127.0.0.1/"},
+		{url: "http://10.1.2.3/"},
+		{url: "http://172.16.0.1/"},
+		{url: "http://192.168.1.1/"},
+		{url: "http://169.254.169.254/latest/meta-data"},
+		{url: "http://100.64.0.1/"},
+		{url: "http://0.0.0.0/"},
+		{url: "http://[::1]/"},
+		{url: "http://[fe80::1]/"},
+		{url: "http://[fd00::1]/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			hook, err := NewEventWebhook(uuid.New(), tt.url, "0123456789abcdef", now)
+			require.NoError(t, err)
+
+			if tt.allowed {
+				assert.NoError(t, hook.CheckPublicHost())
+			} else {
+				assert.ErrorIs(t, hook.CheckPublicHost(), ErrWebhookHostNotAllowed)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+)
+
+const allocationColumns = "id, event_id, name, tickets, available_tickets"
+
+type PostgresAllocationRepository struct {
+	db DBClient
+}
+
+func NewPostgresAllocationRepository(db DBClient) *PostgresAllocationRepository {
+	return &PostgresAllocationRepository{db: db}
+}
+
+// CreateWithExecutor returns ErrAllocationExists when the event already has an allocation of that name
+func (r *PostgresAllocationRepository) CreateWithExecutor(ctx context.Context, exec domain.Executor, allocation *domain.Allocation) error {
+	query := `
+		INSERT INTO allocations (` + allocationColumns + `)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := exec.ExecContext(ctx, query,
+		allocation.ID,
+		allocation.EventID,
+		allocation.Name,
+		allocation.Tickets,
+		allocation.AvailableTickets,
+	)
+	if isUniqueViolation(err) {
+		return domain.ErrAllocationExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create allocation: %w", ClassifyDBError(err))
+	}
+
+	return nil
+}
+
+func (r *PostgresAllocationRepository) FindByEvent(ctx context.Context, eventID uuid.UUID) ([]*domain.Allocation, error) {
+	query := `
+		SELECT ` + allocationColumns + `
+		FROM allocations
+		WHERE event_id = $1
+		ORDER BY name ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query allocations: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	allocations := make([]*domain.Allocation, 0)
+	for rows.Next() {
+		allocation, err := scanAllocation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan allocation: %w", ClassifyDBError(err))
+		}
+		allocations = append(allocations, allocation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating allocations: %w", ClassifyDBError(err))
+	}
+
+	return allocations, nil
+}
+
+func (r *PostgresAllocationRepository) FindByNameWithLock(ctx context.Context, exec domain.Executor, eventID uuid.UUID, name string) (*domain.Allocation, error) {
+	query := `
+		SELECT ` + allocationColumns + `
+		FROM allocations
+		WHERE event_id = $1 AND name = $2
+		FOR UPDATE
+	`
+
+	return findAllocation(exec.QueryRowContext(ctx, query, eventID, name))
+}
+
+func (r *PostgresAllocationRepository) FindByIDWithLock(ctx context.Context, exec domain.Executor, id uuid.UUID) (*domain.Allocation, error) {
+	query := `
+		SELECT ` + allocationColumns + `
+		FROM allocations
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	return findAllocation(exec.QueryRowContext(ctx, query, id))
+}
+
+func (r *PostgresAllocationRepository) UpdateWithExecutor(ctx context.Context, exec domain.Executor, allocation *domain.Allocation) error {
+	result, err := exec.ExecContext(ctx,
+		"UPDATE allocations SET available_tickets = $2 WHERE id = $1",
+		allocation.ID, allocation.AvailableTickets,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update allocation: %w", ClassifyDBError(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrAllocationNotFound
+	}
+
+	return nil
+}
+
+func findAllocation(row rowScanner) (*domain.Allocation, error) {
+	allocation, err := scanAllocation(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrAllocationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find allocation: %w", ClassifyDBError(err))
+	}
+
+	return allocation, nil
+}
+
+func scanAllocation(row rowScanner) (*domain.Allocation, error) {
+	allocation := &domain.Allocation{}
+	err := row.Scan(
+		&allocation.ID,
+		&allocation.EventID,
+		&allocation.Name,
+		&allocation.Tickets,
+		&allocation.AvailableTickets,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return allocation, nil
+}
@@ -5,86 +5,866 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/lib/pq"
 )
 
+// bookingColumns lists the bookings columns in the order expected by scanBooking
+const bookingColumns = `id, event_id, user_id, tickets_booked, booked_at, status, conditional, confirmation_code,
+	price_cents, currency, discount_code, created_by, pay_price_cents, pay_currency, fx_rate, allocation_id, expires_at,
+	refund_cents`
+
+// Every booking insert and status change logs to booking_changes in the same statement, so a booking's
+// history cannot diverge from the booking; changed_at uses clock_timestamp() like availability_changes
+const createBookingQuery = `
+	WITH created AS (
+		INSERT INTO bookings (` + bookingColumns + `, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		RETURNING id, status, tickets_booked, created_by
+	)
+	INSERT INTO booking_changes (booking_id, action, status, tickets_booked, actor, changed_at)
+	SELECT id, $20, status, tickets_booked, created_by, clock_timestamp()
+	FROM created
+`
+
 type PostgresBookingRepository struct {
-	db DBClient
+	db        DBClient
+	batchSize int
+	queryLogger
 }
 
-func NewPostgresBookingRepository(db DBClient) *PostgresBookingRepository {
-	return &PostgresBookingRepository{db: db}
+func NewPostgresBookingRepository(db DBClient, opts ...RepositoryOption) *PostgresBookingRepository {
+	options := newRepositoryOptions(opts)
+	return &PostgresBookingRepository{db: db, batchSize: options.batchSize, queryLogger: queryLogger{logger: options.logger}}
 }
 
-func (r *PostgresBookingRepository) Create(ctx context.Context, booking *domain.Booking) error {
-	query := `
-		INSERT INTO bookings (id, event_id, user_id, tickets_booked, booked_at)
-		VALUES ($1, $2, $3, $4, $5)
-	`
+func (r *PostgresBookingRepository) Create(ctx context.Context, booking *domain.Booking) (err error) {
+	defer r.logFailure("booking.create", time.Now(), &err)
 
-	_, err := r.db.ExecContext(
+	_, err = r.db.ExecContext(
 		ctx,
-		query,
+		createBookingQuery,
 		booking.ID,
 		booking.EventID,
 		booking.UserID,
 		booking.TicketsBooked,
 		booking.BookedAt,
+		booking.Status,
+		booking.Conditional,
+		booking.ConfirmationCode,
+		booking.PriceCents,
+		booking.Currency,
+		booking.DiscountCode,
+		booking.CreatedBy,
+		booking.PayPriceCents,
+		booking.PayCurrency,
+		booking.FXRate,
+		nullUUID(booking.AllocationID),
+		nullTime(booking.ExpiresAt),
+		booking.RefundCents,
+		TenantFromContext(ctx),
+		domain.BookingCreatedAction(booking),
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create booking: %w", err)
+		return fmt.Errorf("failed to create booking: %w", ClassifyDBError(err))
 	}
 
 	return nil
 }
 
-func (r *PostgresBookingRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Booking, error) {
+func (r *PostgresBookingRepository) FindByID(ctx context.Context, id uuid.UUID) (_ *domain.Booking, err error) {
+	defer r.logFailure("booking.find_by_id", time.Now(), &err)
+
 	query := `
-		SELECT id, event_id, user_id, tickets_booked, booked_at
+		SELECT ` + bookingColumns + `
 		FROM bookings
-		WHERE id = $1
+		WHERE id = $1 AND tenant_id = $2
 	`
 
-	booking := &domain.Booking{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&booking.ID,
-		&booking.EventID,
-		&booking.UserID,
-		&booking.TicketsBooked,
-		&booking.BookedAt,
-	)
+	booking, err := scanBooking(r.db.QueryRowContext(ctx, query, id, TenantFromContext(ctx)))
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, domain.ErrBookingNotFound
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to find booking: %w", err)
+		return nil, fmt.Errorf("failed to find booking: %w", ClassifyDBError(err))
 	}
 
 	return booking, nil
 }
 
-// CreateWithExecutor creates a booking using the provided executor (transaction or db)
-func (r *PostgresBookingRepository) CreateWithExecutor(ctx context.Context, exec domain.Executor, booking *domain.Booking) error {
+// FindByConfirmationCodePrefix matches codes with LIKE 'prefix%', which the text_pattern_ops index serves
+// The prefix must already be upper-cased and free of LIKE wildcards
+func (r *PostgresBookingRepository) FindByConfirmationCodePrefix(ctx context.Context, prefix string, limit, offset int) (_ []*domain.Booking, err error) {
+	defer r.logFailure("booking.find_by_confirmation_code_prefix", time.Now(), &err)
+
+	query := `
+		SELECT ` + bookingColumns + `
+		FROM bookings
+		WHERE confirmation_code LIKE $1 AND tenant_id = $4
+		ORDER BY confirmation_code
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, prefix+"%", limit, offset, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bookings by confirmation code: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	var bookings []*domain.Booking
+	for rows.Next() {
+		booking, err := scanBooking(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan booking: %w", ClassifyDBError(err))
+		}
+		bookings = append(bookings, booking)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating bookings: %w", ClassifyDBError(err))
+	}
+
+	return bookings, nil
+}
+
+func (r *PostgresBookingRepository) CountByConfirmationCodePrefix(ctx context.Context, prefix string) (_ int, err error) {
+	defer r.logFailure("booking.count_by_confirmation_code_prefix", time.Now(), &err)
+
+	var count int
+	err = r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM bookings WHERE confirmation_code LIKE $1 AND tenant_id = $2", prefix+"%", TenantFromContext(ctx)).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count bookings by confirmation code: %w", ClassifyDBError(err))
+	}
+
+	return count, nil
+}
+
+func (r *PostgresBookingRepository) FindByUser(ctx context.Context, userID uuid.UUID, limit, offset int) (_ []*domain.Booking, err error) {
+	defer r.logFailure("booking.find_by_user", time.Now(), &err)
+
 	query := `
-		INSERT INTO bookings (id, event_id, user_id, tickets_booked, booked_at)
-		VALUES ($1, $2, $3, $4, $5)
+		SELECT ` + bookingColumns + `
+		FROM bookings
+		WHERE user_id = $1 AND tenant_id = $4
+		ORDER BY booked_at DESC, id
+		LIMIT $2 OFFSET $3
 	`
 
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bookings by user: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	var bookings []*domain.Booking
+	for rows.Next() {
+		booking, err := scanBooking(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan booking: %w", ClassifyDBError(err))
+		}
+		bookings = append(bookings, booking)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating bookings: %w", ClassifyDBError(err))
+	}
+
+	return bookings, nil
+}
+
+func (r *PostgresBookingRepository) CountByUser(ctx context.Context, userID uuid.UUID) (_ int, err error) {
+	defer r.logFailure("booking.count_by_user", time.Now(), &err)
+
+	var count int
+	err = r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM bookings WHERE user_id = $1 AND tenant_id = $2", userID, TenantFromContext(ctx)).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count bookings by user: %w", ClassifyDBError(err))
+	}
+
+	return count, nil
+}
+
+// CreateWithExecutor creates a booking using the provided executor (transaction or db)
+func (r *PostgresBookingRepository) CreateWithExecutor(ctx context.Context, exec domain.Executor, booking *domain.Booking) (err error) {
+	defer r.logFailure("booking.create", time.Now(), &err)
+
+	return r.create(ctx, exec, booking, domain.BookingCreatedAction(booking))
+}
+
+// create inserts booking, logging its creation as action
+func (r *PostgresBookingRepository) create(ctx context.Context, exec domain.Executor, booking *domain.Booking, action domain.BookingAction) error {
 	_, err := exec.ExecContext(
 		ctx,
-		query,
+		createBookingQuery,
 		booking.ID,
 		booking.EventID,
 		booking.UserID,
 		booking.TicketsBooked,
 		booking.BookedAt,
+		booking.Status,
+		booking.Conditional,
+		booking.ConfirmationCode,
+		booking.PriceCents,
+		booking.Currency,
+		booking.DiscountCode,
+		booking.CreatedBy,
+		booking.PayPriceCents,
+		booking.PayCurrency,
+		booking.FXRate,
+		nullUUID(booking.AllocationID),
+		nullTime(booking.ExpiresAt),
+		booking.RefundCents,
+		TenantFromContext(ctx),
+		action,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create booking: %w", ClassifyDBError(err))
+	}
+
+	return nil
+}
+
+// CreateBatchWithExecutor inserts the bookings with one statement per batch of the repository's batch size
+// Each statement unnests column arrays, so it binds the same 21 parameters however many rows it carries and
+// never nears Postgres's 65535 parameter limit; batching bounds the size of the arrays instead
+func (r *PostgresBookingRepository) CreateBatchWithExecutor(ctx context.Context, exec domain.Executor, bookings []*domain.Booking) (err error) {
+	defer r.logFailure("booking.create_batch", time.Now(), &err)
+
+	for start := 0; start < len(bookings); start += r.batchSize {
+		end := min(start+r.batchSize, len(bookings))
+		if err := r.createBatch(ctx, exec, bookings[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createBatch inserts bookings with a single statement
+func (r *PostgresBookingRepository) createBatch(ctx context.Context, exec domain.Executor, bookings []*domain.Booking) error {
+	query := `
+		WITH created AS (
+			INSERT INTO bookings (` + bookingColumns + `, tenant_id)
+			SELECT batch.*, $19 FROM unnest($1::uuid[], $2::uuid[], $3::uuid[], $4::int[], $5::timestamp[], $6::text[], $7::boolean[], $8::text[],
+				$9::bigint[], $10::text[], $11::text[], $12::text[], $13::bigint[], $14::text[], $15::float8[], $16::uuid[], $17::timestamp[],
+				$18::bigint[]) AS batch
+			RETURNING id, status, tickets_booked, created_by, expires_at
+		)
+		INSERT INTO booking_changes (booking_id, action, status, tickets_booked, actor, changed_at)
+		SELECT id, CASE WHEN expires_at IS NULL THEN $20 ELSE $21 END, status, tickets_booked, created_by, clock_timestamp()
+		FROM created
+	`
+
+	n := len(bookings)
+	ids, eventIDs, userIDs := make([]string, n), make([]string, n), make([]string, n)
+	tickets := make([]int64, n)
+	bookedAt, statuses := make([]string, n), make([]string, n)
+	conditional := make([]bool, n)
+	codes := make([]string, n)
+	prices, currencies, discountCodes := make([]int64, n), make([]string, n), make([]string, n)
+	createdBy := make([]string, n)
+	payPrices, payCurrencies, fxRates := make([]int64, n), make([]string, n), make([]float64, n)
+	allocationIDs := make([]uuid.NullUUID, n)
+	expiresAt := make([]sql.NullTime, n)
+	refunds := make([]int64, n)
+	for i, booking := range bookings {
+		ids[i] = booking.ID.String()
+		eventIDs[i] = booking.EventID.String()
+		userIDs[i] = booking.UserID.String()
+		tickets[i] = int64(booking.TicketsBooked)
+		bookedAt[i] = booking.BookedAt.Format(time.RFC3339Nano)
+		statuses[i] = string(booking.Status)
+		conditional[i] = booking.Conditional
+		codes[i] = booking.ConfirmationCode
+		prices[i] = booking.PriceCents
+		currencies[i] = booking.Currency
+		discountCodes[i] = booking.DiscountCode
+		createdBy[i] = booking.CreatedBy
+		payPrices[i] = booking.PayPriceCents
+		payCurrencies[i] = booking.PayCurrency
+		fxRates[i] = booking.FXRate
+		allocationIDs[i] = nullUUID(booking.AllocationID)
+		expiresAt[i] = nullTime(booking.ExpiresAt)
+		refunds[i] = booking.RefundCents
+	}
+
+	_, err := exec.ExecContext(ctx, query,
+		pq.Array(ids),
+		pq.Array(eventIDs),
+		pq.Array(userIDs),
+		pq.Array(tickets),
+		pq.Array(bookedAt),
+		pq.Array(statuses),
+		pq.Array(conditional),
+		pq.Array(codes),
+		pq.Array(prices),
+		pq.Array(currencies),
+		pq.Array(discountCodes),
+		pq.Array(createdBy),
+		pq.Array(payPrices),
+		pq.Array(payCurrencies),
+		pq.Array(fxRates),
+		pq.Array(allocationIDs),
+		pq.Array(expiresAt),
+		pq.Array(refunds),
+		TenantFromContext(ctx),
+		domain.BookingCreated,
+		domain.BookingReserved,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create booking: %w", err)
+		return fmt.Errorf("failed to create bookings: %w", ClassifyDBError(err))
 	}
 
 	return nil
 }
+
+// Stream iterates matching bookings row by row so large exports never hold the full result in memory
+// Iteration stops at the first error returned by fn
+func (r *PostgresBookingRepository) Stream(ctx context.Context, filter domain.BookingFilter, fn func(*domain.Booking) error) (err error) {
+	defer r.logFailure("booking.stream", time.Now(), &err)
+
+	query, args := buildBookingFilterQuery(TenantFromContext(ctx), filter)
+
+	return withStreamExecutor(ctx, r.db, func(exec domain.Executor) error {
+		rows, err := exec.QueryContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to query bookings: %w", ClassifyDBError(err))
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			booking, err := scanBooking(rows)
+			if err != nil {
+				return fmt.Errorf("failed to scan booking: %w", ClassifyDBError(err))
+			}
+			if err := fn(booking); err != nil {
+				return err
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating bookings: %w", ClassifyDBError(err))
+		}
+
+		return nil
+	})
+}
+
+// buildBookingFilterQuery renders the SELECT for the tenant's bookings matching filter, adding a placeholder per applied condition
+func buildBookingFilterQuery(tenantID string, filter domain.BookingFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	addCondition("tenant_id = $%d", tenantID)
+	if filter.EventID != uuid.Nil {
+		addCondition("event_id = $%d", filter.EventID)
+	}
+	if filter.UserID != uuid.Nil {
+		addCondition("user_id = $%d", filter.UserID)
+	}
+	if !filter.BookedFrom.IsZero() {
+		addCondition("booked_at >= $%d", filter.BookedFrom)
+	}
+	if !filter.BookedTo.IsZero() {
+		addCondition("booked_at < $%d", filter.BookedTo)
+	}
+
+	query := "SELECT " + bookingColumns + " FROM bookings WHERE " + strings.Join(conditions, " AND ") +
+		" ORDER BY booked_at ASC, id ASC"
+
+	return query, args
+}
+
+// FindPendingByEventWithLock locks the event's pending conditional bookings (FOR UPDATE); reservations are left out
+func (r *PostgresBookingRepository) FindPendingByEventWithLock(ctx context.Context, exec domain.Executor, eventID uuid.UUID) (_ []*domain.Booking, err error) {
+	defer r.logFailure("booking.find_pending_by_event_with_lock", time.Now(), &err)
+
+	query := `
+		SELECT ` + bookingColumns + `
+		FROM bookings
+		WHERE event_id = $1 AND status = $2 AND conditional AND tenant_id = $3
+		ORDER BY booked_at ASC, id ASC
+		FOR UPDATE
+	`
+
+	rows, err := exec.QueryContext(ctx, query, eventID, domain.BookingStatusPending, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending bookings: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	var bookings []*domain.Booking
+	for rows.Next() {
+		booking, err := scanBooking(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan booking: %w", ClassifyDBError(err))
+		}
+		bookings = append(bookings, booking)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pending bookings: %w", ClassifyDBError(err))
+	}
+
+	return bookings, nil
+}
+
+// FindActiveByEventWithLock locks the event's bookings that are not cancelled (FOR UPDATE)
+// They are locked in ID order, as FindActiveByUserWithLock locks each event's bookings, so the two cannot deadlock
+func (r *PostgresBookingRepository) FindActiveByEventWithLock(ctx context.Context, exec domain.Executor, eventID uuid.UUID) (_ []*domain.Booking, err error) {
+	defer r.logFailure("booking.find_active_by_event_with_lock", time.Now(), &err)
+
+	query := `
+		SELECT ` + bookingColumns + `
+		FROM bookings
+		WHERE event_id = $1 AND status <> $2 AND tenant_id = $3
+		ORDER BY id ASC
+		FOR UPDATE
+	`
+
+	rows, err := exec.QueryContext(ctx, query, eventID, domain.BookingStatusCancelled, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query event bookings: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	var bookings []*domain.Booking
+	for rows.Next() {
+		booking, err := scanBooking(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan booking: %w", ClassifyDBError(err))
+		}
+		bookings = append(bookings, booking)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event bookings: %w", ClassifyDBError(err))
+	}
+
+	return bookings, nil
+}
+
+// FindActiveByUserWithLock locks the user's bookings that are not cancelled (FOR UPDATE)
+// They come back grouped by event in event ID order, the order their availability rows are locked in
+func (r *PostgresBookingRepository) FindActiveByUserWithLock(ctx context.Context, exec domain.Executor, userID uuid.UUID) (_ []*domain.Booking, err error) {
+	defer r.logFailure("booking.find_active_by_user_with_lock", time.Now(), &err)
+
+	query := `
+		SELECT ` + bookingColumns + `
+		FROM bookings
+		WHERE user_id = $1 AND status <> $2 AND tenant_id = $3
+		ORDER BY event_id ASC, id ASC
+		FOR UPDATE
+	`
+
+	rows, err := exec.QueryContext(ctx, query, userID, domain.BookingStatusCancelled, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user bookings: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	var bookings []*domain.Booking
+	for rows.Next() {
+		booking, err := scanBooking(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan booking: %w", ClassifyDBError(err))
+		}
+		bookings = append(bookings, booking)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating user bookings: %w", ClassifyDBError(err))
+	}
+
+	return bookings, nil
+}
+
+// SumTicketsByEventWithExecutor totals tickets of the event's bookings that are not cancelled
+func (r *PostgresBookingRepository) SumTicketsByEventWithExecutor(ctx context.Context, exec domain.Executor, eventID uuid.UUID) (_ int, err error) {
+	defer r.logFailure("booking.sum_tickets_by_event", time.Now(), &err)
+
+	query := `
+		SELECT COALESCE(SUM(tickets_booked), 0)
+		FROM bookings
+		WHERE event_id = $1 AND status <> $2 AND tenant_id = $3
+	`
+
+	var total int
+	if err := exec.QueryRowContext(ctx, query, eventID, domain.BookingStatusCancelled, TenantFromContext(ctx)).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to sum booked tickets: %w", ClassifyDBError(err))
+	}
+
+	return total, nil
+}
+
+// ReassignEventWithExecutor moves the event's bookings, cancelled ones included, to another event
+func (r *PostgresBookingRepository) ReassignEventWithExecutor(ctx context.Context, exec domain.Executor, fromEventID, toEventID uuid.UUID) (_ int, err error) {
+	defer r.logFailure("booking.reassign_event", time.Now(), &err)
+
+	query := `
+		UPDATE bookings
+		SET event_id = $2, allocation_id = NULL
+		WHERE event_id = $1 AND tenant_id = $3
+	`
+
+	result, err := exec.ExecContext(ctx, query, fromEventID, toEventID, TenantFromContext(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to reassign bookings: %w", ClassifyDBError(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// UpdateStatusWithExecutor persists the booking's status using the provided executor (transaction or db)
+func (r *PostgresBookingRepository) UpdateStatusWithExecutor(ctx context.Context, exec domain.Executor, booking *domain.Booking) (err error) {
+	defer r.logFailure("booking.update_status", time.Now(), &err)
+
+	// Rows affected counts the logged changes, one per updated booking
+	query := `
+		WITH updated AS (
+			UPDATE bookings
+			SET status = $2, refund_cents = $5
+			WHERE id = $1 AND tenant_id = $3
+			RETURNING id, status, tickets_booked
+		)
+		INSERT INTO booking_changes (booking_id, action, status, tickets_booked, changed_at)
+		SELECT id, $4, status, tickets_booked, clock_timestamp()
+		FROM updated
+	`
+
+	result, err := exec.ExecContext(ctx, query, booking.ID, booking.Status, TenantFromContext(ctx),
+		domain.BookingActionForStatus(booking.Status), booking.RefundCents)
+	if err != nil {
+		return fmt.Errorf("failed to update booking: %w", ClassifyDBError(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrBookingNotFound
+	}
+
+	return nil
+}
+
+// SplitWithExecutor persists the tickets and price original kept after a split and creates split, the
+// booking holding the rest, logging the split on both
+func (r *PostgresBookingRepository) SplitWithExecutor(ctx context.Context, exec domain.Executor, original, split *domain.Booking) (err error) {
+	defer r.logFailure("booking.split", time.Now(), &err)
+
+	query := `
+		WITH updated AS (
+			UPDATE bookings
+			SET tickets_booked = $2, price_cents = $3, pay_price_cents = $4
+			WHERE id = $1 AND tenant_id = $5
+			RETURNING id, status, tickets_booked
+		)
+		INSERT INTO booking_changes (booking_id, action, status, tickets_booked, changed_at)
+		SELECT id, $6, status, tickets_booked, clock_timestamp()
+		FROM updated
+	`
+
+	result, err := exec.ExecContext(ctx, query, original.ID, original.TicketsBooked, original.PriceCents,
+		original.PayPriceCents, TenantFromContext(ctx), domain.BookingSplit)
+	if err != nil {
+		return fmt.Errorf("failed to split booking: %w", ClassifyDBError(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrBookingNotFound
+	}
+
+	return r.create(ctx, exec, split, domain.BookingSplitCreated)
+}
+
+// FindByIDWithLock locks the booking (FOR UPDATE)
+func (r *PostgresBookingRepository) FindByIDWithLock(ctx context.Context, exec domain.Executor, id uuid.UUID) (_ *domain.Booking, err error) {
+	defer r.logFailure("booking.find_by_id_with_lock", time.Now(), &err)
+
+	query := `
+		SELECT ` + bookingColumns + `
+		FROM bookings
+		WHERE id = $1 AND tenant_id = $2
+		FOR UPDATE
+	`
+
+	booking, err := scanBooking(exec.QueryRowContext(ctx, query, id, TenantFromContext(ctx)))
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrBookingNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find booking: %w", ClassifyDBError(err))
+	}
+
+	return booking, nil
+}
+
+// FindExpiredWithLock locks up to limit pending reservations whose deadline has passed, oldest deadline first
+// The sweeper serves no request, so every tenant's reservations are swept; SKIP LOCKED lets concurrent
+// sweepers and confirmations proceed without waiting on each other
+func (r *PostgresBookingRepository) FindExpiredWithLock(ctx context.Context, exec domain.Executor, now time.Time, limit int) (_ []*domain.Booking, err error) {
+	defer r.logFailure("booking.find_expired_with_lock", time.Now(), &err)
+
+	query := `
+		SELECT ` + bookingColumns + `
+		FROM bookings
+		WHERE status = $1 AND expires_at <= $2
+		ORDER BY expires_at ASC, id ASC
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED
+	`
+
+	rows, err := exec.QueryContext(ctx, query, domain.BookingStatusPending, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expired bookings: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	var bookings []*domain.Booking
+	for rows.Next() {
+		booking, err := scanBooking(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan booking: %w", ClassifyDBError(err))
+		}
+		bookings = append(bookings, booking)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating expired bookings: %w", ClassifyDBError(err))
+	}
+
+	return bookings, nil
+}
+
+// ExpireWithExecutor persists the cancellation of an expired reservation and logs it as expired
+// Like FindExpiredWithLock it is not scoped to a tenant, so the sweeper can expire any tenant's reservations
+func (r *PostgresBookingRepository) ExpireWithExecutor(ctx context.Context, exec domain.Executor, booking *domain.Booking) (err error) {
+	defer r.logFailure("booking.expire", time.Now(), &err)
+
+	query := `
+		WITH updated AS (
+			UPDATE bookings
+			SET status = $2
+			WHERE id = $1
+			RETURNING id, status, tickets_booked
+		)
+		INSERT INTO booking_changes (booking_id, action, status, tickets_booked, changed_at)
+		SELECT id, $3, status, tickets_booked, clock_timestamp()
+		FROM updated
+	`
+
+	result, err := exec.ExecContext(ctx, query, booking.ID, booking.Status, domain.BookingExpired)
+	if err != nil {
+		return fmt.Errorf("failed to expire booking: %w", ClassifyDBError(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrBookingNotFound
+	}
+
+	return nil
+}
+
+// scanBooking reads a row selected with bookingColumns into a domain booking
+func scanBooking(row rowScanner) (*domain.Booking, error) {
+	booking := &domain.Booking{}
+	var allocationID uuid.NullUUID
+	var expiresAt sql.NullTime
+	err := row.Scan(
+		&booking.ID,
+		&booking.EventID,
+		&booking.UserID,
+		&booking.TicketsBooked,
+		&booking.BookedAt,
+		&booking.Status,
+		&booking.Conditional,
+		&booking.ConfirmationCode,
+		&booking.PriceCents,
+		&booking.Currency,
+		&booking.DiscountCode,
+		&booking.CreatedBy,
+		&booking.PayPriceCents,
+		&booking.PayCurrency,
+		&booking.FXRate,
+		&allocationID,
+		&expiresAt,
+		&booking.RefundCents,
+	)
+	if err != nil {
+		return nil, err
+	}
+	booking.AllocationID = allocationID.UUID
+	booking.ExpiresAt = expiresAt.Time
+
+	return booking, nil
+}
+
+// FindChanges returns the booking's change log, oldest first
+// Callers load the booking first, which scopes the log to the tenant
+func (r *PostgresBookingRepository) FindChanges(ctx context.Context, bookingID uuid.UUID) (_ []*domain.BookingChange, err error) {
+	defer r.logFailure("booking.find_changes", time.Now(), &err)
+
+	query := `
+		SELECT booking_id, action, status, tickets_booked, actor, changed_at
+		FROM booking_changes
+		WHERE booking_id = $1
+		ORDER BY changed_at ASC, id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query booking changes: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	var changes []*domain.BookingChange
+	for rows.Next() {
+		change := &domain.BookingChange{}
+		if err := rows.Scan(
+			&change.BookingID,
+			&change.Action,
+			&change.Status,
+			&change.TicketsBooked,
+			&change.Actor,
+			&change.ChangedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan booking change: %w", ClassifyDBError(err))
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating booking changes: %w", ClassifyDBError(err))
+	}
+
+	return changes, nil
+}
+
+// ledgerChanges selects the changes of the event's public bookings that moved its availability, in ledger order
+// Bookings drawn from an allocation are left out, since their tickets were taken from the block, not public sale
+const ledgerChanges = `
+	SELECT bc.id, bc.booking_id, bc.action, bc.status, bc.tickets_booked, bc.actor, bc.changed_at
+	FROM booking_changes bc
+	JOIN bookings b ON b.id = bc.booking_id
+	WHERE b.event_id = $1 AND b.tenant_id = $2 AND b.allocation_id IS NULL AND bc.action = ANY($3::text[])
+`
+
+// FindLedger pages through the event's ledger changes, oldest first
+func (r *PostgresBookingRepository) FindLedger(ctx context.Context, eventID uuid.UUID, limit, offset int) (_ []*domain.BookingChange, err error) {
+	defer r.logFailure("booking.find_ledger", time.Now(), &err)
+
+	query := `
+		SELECT booking_id, action, status, tickets_booked, actor, changed_at
+		FROM (` + ledgerChanges + `) ledger
+		ORDER BY changed_at ASC, id ASC
+		LIMIT $4 OFFSET $5
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, eventID, TenantFromContext(ctx), pq.Array(domain.LedgerActions), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query event ledger: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	changes := make([]*domain.BookingChange, 0)
+	for rows.Next() {
+		change := &domain.BookingChange{}
+		if err := rows.Scan(
+			&change.BookingID,
+			&change.Action,
+			&change.Status,
+			&change.TicketsBooked,
+			&change.Actor,
+			&change.ChangedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan event ledger: %w", ClassifyDBError(err))
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event ledger: %w", ClassifyDBError(err))
+	}
+
+	return changes, nil
+}
+
+// CountLedger counts the changes FindLedger pages through
+func (r *PostgresBookingRepository) CountLedger(ctx context.Context, eventID uuid.UUID) (_ int, err error) {
+	defer r.logFailure("booking.count_ledger", time.Now(), &err)
+
+	var count int
+	err = r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM ("+ledgerChanges+") ledger",
+		eventID, TenantFromContext(ctx), pq.Array(domain.LedgerActions),
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count event ledger: %w", ClassifyDBError(err))
+	}
+
+	return count, nil
+}
+
+// SumLedgerByAction totals the tickets of the first n ledger changes per action, one change per action
+func (r *PostgresBookingRepository) SumLedgerByAction(ctx context.Context, eventID uuid.UUID, n int) (_ []*domain.BookingChange, err error) {
+	defer r.logFailure("booking.sum_ledger_by_action", time.Now(), &err)
+
+	query := `
+		SELECT action, SUM(tickets_booked)
+		FROM (
+			SELECT action, tickets_booked
+			FROM (` + ledgerChanges + `) ledger
+			ORDER BY changed_at ASC, id ASC
+			LIMIT $4
+		) earlier
+		GROUP BY action
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, eventID, TenantFromContext(ctx), pq.Array(domain.LedgerActions), n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum event ledger: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	var totals []*domain.BookingChange
+	for rows.Next() {
+		total := &domain.BookingChange{}
+		if err := rows.Scan(&total.Action, &total.TicketsBooked); err != nil {
+			return nil, fmt.Errorf("failed to scan event ledger sum: %w", ClassifyDBError(err))
+		}
+		totals = append(totals, total)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event ledger sums: %w", ClassifyDBError(err))
+	}
+
+	return totals, nil
+}
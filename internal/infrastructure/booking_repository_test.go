@@ -0,0 +1,117 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildBookingFilterQuery(t *testing.T) {
+	eventID := uuid.New()
+	userID := uuid.New()
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		filter    domain.BookingFilter
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "selects all of the tenant's bookings without filters",
+			filter:    domain.BookingFilter{},
+			wantQuery: "SELECT " + bookingColumns + " FROM bookings WHERE tenant_id = $1 ORDER BY booked_at ASC, id ASC",
+			wantArgs:  []interface{}{"acme"},
+		},
+		{
+			name:   "numbers placeholders in order of applied filters",
+			filter: domain.BookingFilter{UserID: userID, BookedTo: to},
+			wantQuery: "SELECT " + bookingColumns + " FROM bookings " +
+				"WHERE tenant_id = $1 AND user_id = $2 AND booked_at < $3 ORDER BY booked_at ASC, id ASC",
+			wantArgs: []interface{}{"acme", userID, to},
+		},
+		{
+			name:   "combines all filters",
+			filter: domain.BookingFilter{EventID: eventID, UserID: userID, BookedFrom: from, BookedTo: to},
+			wantQuery: "SELECT " + bookingColumns + " FROM bookings " +
+				"WHERE tenant_id = $1 AND event_id = $2 AND user_id = $3 AND booked_at >= $4 AND booked_at < $5 ORDER BY booked_at ASC, id ASC",
+			wantArgs: []interface{}{"acme", eventID, userID, from, to},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := buildBookingFilterQuery("acme", tt.filter)
+			assert.Equal(t, tt.wantQuery, query)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+// batchRecordingDB records the booking IDs of each batch insert and fails the statement numbered failAt
+type batchRecordingDB struct {
+	DBClient
+	batches [][]string
+	failAt  int
+}
+
+func (db *batchRecordingDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	db.batches = append(db.batches, *args[0].(*pq.StringArray))
+	if len(db.batches) == db.failAt {
+		return nil, errors.New("connection reset by peer")
+	}
+	return nil, nil
+}
+
+func TestCreateBatchWithExecutor_Chunks(t *testing.T) {
+	bookings := make([]*domain.Booking, 5)
+	for i := range bookings {
+		booking, err := domain.NewBooking(uuid.New(), uuid.New(), 1)
+		require.NoError(t, err)
+		bookings[i] = booking
+	}
+	ctx := context.Background()
+
+	t.Run("writes rows beyond the batch size in further statements, in order", func(t *testing.T) {
+		db := &batchRecordingDB{}
+		require.NoError(t, NewPostgresBookingRepository(db, WithBatchSize(2)).CreateBatchWithExecutor(ctx, db, bookings))
+
+		require.Len(t, db.batches, 3)
+		var written []string
+		for i, want := range []int{2, 2, 1} {
+			assert.Len(t, db.batches[i], want)
+			written = append(written, db.batches[i]...)
+		}
+		for i, booking := range bookings {
+			assert.Equal(t, booking.ID.String(), written[i])
+		}
+	})
+
+	t.Run("writes a batch within the size in one statement", func(t *testing.T) {
+		db := &batchRecordingDB{}
+		require.NoError(t, NewPostgresBookingRepository(db).CreateBatchWithExecutor(ctx, db, bookings))
+		assert.Len(t, db.batches, 1)
+	})
+
+	t.Run("stops at the first failing statement", func(t *testing.T) {
+		db := &batchRecordingDB{failAt: 2}
+		err := NewPostgresBookingRepository(db, WithBatchSize(2)).CreateBatchWithExecutor(ctx, db, bookings)
+		assert.ErrorContains(t, err, "connection reset by peer")
+		assert.Len(t, db.batches, 2)
+	})
+
+	t.Run("writes nothing for no bookings", func(t *testing.T) {
+		db := &batchRecordingDB{}
+		require.NoError(t, NewPostgresBookingRepository(db).CreateBatchWithExecutor(ctx, db, nil))
+		assert.Empty(t, db.batches)
+	})
+}
@@ -0,0 +1,89 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeConnector struct {
+	driver.Connector
+	err   error
+	calls int
+}
+
+func (c *fakeConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	c.calls++
+	return nil, c.err
+}
+
+func TestCircuitBreaker_TripsAndRecovers(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(3, 30*time.Second)
+	breaker.now = func() time.Time { return now }
+
+	inner := &fakeConnector{err: errors.New("dial tcp: connection refused")}
+	connector := &breakerConnector{Connector: inner, breaker: breaker}
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := connector.Connect(ctx)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrServiceUnavailable)
+	}
+	assert.Equal(t, BreakerOpen, breaker.State())
+
+	_, err := connector.Connect(ctx)
+	assert.ErrorIs(t, err, domain.ErrServiceUnavailable)
+	assert.Equal(t, 3, inner.calls, "open breaker must not reach the database")
+
+	// After the cool-down a single failing probe re-opens the breaker
+	now = now.Add(30 * time.Second)
+	_, err = connector.Connect(ctx)
+	assert.NotErrorIs(t, err, domain.ErrServiceUnavailable)
+	assert.Equal(t, 4, inner.calls)
+	assert.Equal(t, BreakerOpen, breaker.State())
+
+	// A successful probe closes it again
+	now = now.Add(30 * time.Second)
+	inner.err = nil
+	_, err = connector.Connect(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, BreakerClosed, breaker.State())
+	assert.Equal(t, 5, inner.calls)
+}
+
+func TestCircuitBreaker_HalfOpenAllowsSingleProbe(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(1, time.Second)
+	breaker.now = func() time.Time { return now }
+
+	require.NoError(t, breaker.Allow())
+	breaker.Record(errors.New("connection refused"))
+	assert.Equal(t, BreakerOpen, breaker.State())
+
+	now = now.Add(time.Second)
+	require.NoError(t, breaker.Allow())
+	assert.Equal(t, BreakerHalfOpen, breaker.State())
+	assert.ErrorIs(t, breaker.Allow(), domain.ErrServiceUnavailable, "only one probe may be in flight")
+
+	breaker.Record(context.Canceled)
+	assert.Equal(t, BreakerHalfOpen, breaker.State(), "a cancelled probe says nothing about the database")
+	require.NoError(t, breaker.Allow())
+}
+
+func TestCircuitBreaker_SuccessResetsFailureCount(t *testing.T) {
+	breaker := NewCircuitBreaker(2, time.Minute)
+
+	breaker.Record(errors.New("connection refused"))
+	breaker.Record(nil)
+	breaker.Record(errors.New("connection refused"))
+
+	assert.Equal(t, BreakerClosed, breaker.State())
+}
@@ -3,6 +3,7 @@ package infrastructure
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/jorzel/booking-service/internal/domain"
 )
@@ -25,10 +26,39 @@ type DBClient interface {
 	// PingContext verifies a connection to the database
 	PingContext(ctx context.Context) error
 
+	// HealthCheck round-trips to the database and reports latency, server version and pool statistics
+	HealthCheck(ctx context.Context) (HealthInfo, error)
+
+	// Stats returns connection pool statistics
+	Stats() sql.DBStats
+
 	// Close closes the database connection
 	Close() error
 }
 
+// HealthInfo describes a successful database health check
+type HealthInfo struct {
+	Latency       time.Duration
+	ServerVersion string
+	Pool          sql.DBStats
+}
+
+// checkHealth asks the server for its version through db, so the round trip goes wherever db's queries go
+// The query is kept out of the query metrics, which would otherwise count every readiness probe
+func checkHealth(ctx context.Context, db DBClient) (HealthInfo, error) {
+	ctx = WithoutInstrumentation(ctx)
+	start := time.Now()
+	var version string
+	if err := db.QueryRowContext(ctx, "SHOW server_version").Scan(&version); err != nil {
+		return HealthInfo{}, err
+	}
+	return HealthInfo{
+		Latency:       time.Since(start),
+		ServerVersion: version,
+		Pool:          db.Stats(),
+	}, nil
+}
+
 // DBClientAdapter wraps sql.DB to implement the DBClient interface
 // This allows using raw sql.DB where DBClient is expected (useful for testing or non-instrumented scenarios)
 type DBClientAdapter struct {
@@ -60,6 +90,14 @@ func (a *DBClientAdapter) PingContext(ctx context.Context) error {
 	return a.db.PingContext(ctx)
 }
 
+func (a *DBClientAdapter) HealthCheck(ctx context.Context) (HealthInfo, error) {
+	return checkHealth(ctx, a)
+}
+
+func (a *DBClientAdapter) Stats() sql.DBStats {
+	return a.db.Stats()
+}
+
 func (a *DBClientAdapter) Close() error {
 	return a.db.Close()
 }
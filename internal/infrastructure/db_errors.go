@@ -0,0 +1,101 @@
+package infrastructure
+
+import (
+	"database/sql/driver"
+	"errors"
+	"net"
+
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/lib/pq"
+)
+
+// Postgres error codes translated by ClassifyDBError
+// See https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgCheckViolation       = "23514"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+	pgQueryCanceled        = "57014"
+)
+
+// Sentinel errors for database failures that have no domain meaning
+// Serialization failures and deadlocks are transient and safe to retry
+var (
+	ErrSerializationFailure = errors.New("transaction conflicted with a concurrent update")
+	ErrDeadlockDetected     = errors.New("transaction aborted due to deadlock")
+	ErrQueryCanceled        = errors.New("query canceled")
+)
+
+// classifiedError exposes a classification while keeping the driver error reachable via errors.As
+// Its message is the classification only, so driver details never leak to API clients
+type classifiedError struct {
+	kind  error
+	cause error
+}
+
+func (e *classifiedError) Error() string {
+	return e.kind.Error()
+}
+
+func (e *classifiedError) Unwrap() []error {
+	return []error{e.kind, e.cause}
+}
+
+// ClassifyDBError translates Postgres errors into domain errors or infrastructure sentinels
+// Errors that are not *pq.Error, or carry an unmapped code, are returned unchanged
+func ClassifyDBError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	var kind error
+	switch pqErr.Code {
+	case pgUniqueViolation:
+		kind = &domain.ConflictError{Message: "resource already exists"}
+	case pgForeignKeyViolation:
+		kind = &domain.NotFoundError{Entity: "referenced resource"}
+	case pgCheckViolation:
+		kind = &domain.ValidationError{Field: pqErr.Constraint, Message: "violates check constraint"}
+	case pgSerializationFailure:
+		kind = ErrSerializationFailure
+	case pgDeadlockDetected:
+		kind = ErrDeadlockDetected
+	case pgQueryCanceled:
+		kind = ErrQueryCanceled
+	default:
+		return err
+	}
+
+	return &classifiedError{kind: kind, cause: err}
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pgUniqueViolation
+}
+
+// IsConnectionError reports whether err means the database could not be reached,
+// as opposed to the database rejecting the query
+func IsConnectionError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		// Class 08 is "connection exception"; 57P01-57P03 are server shutdown/startup states
+		return pqErr.Code.Class() == "08" ||
+			pqErr.Code == "57P01" || pqErr.Code == "57P02" || pqErr.Code == "57P03"
+	}
+
+	return false
+}
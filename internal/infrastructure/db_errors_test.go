@@ -0,0 +1,137 @@
+package infrastructure
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyDBError(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantIs      error
+		wantAs      interface{}
+		wantMessage string
+	}{
+		{
+			name:        "maps unique violation to conflict",
+			err:         &pq.Error{Code: "23505", Constraint: "bookings_pkey"},
+			wantAs:      new(*domain.ConflictError),
+			wantMessage: "conflict: resource already exists",
+		},
+		{
+			name:        "maps foreign key violation to not found",
+			err:         &pq.Error{Code: "23503", Constraint: "bookings_event_id_fkey"},
+			wantAs:      new(*domain.NotFoundError),
+			wantMessage: "referenced resource not found",
+		},
+		{
+			name:        "maps check violation to validation error on the constraint",
+			err:         &pq.Error{Code: "23514", Constraint: "available_tickets_non_negative"},
+			wantAs:      new(*domain.ValidationError),
+			wantMessage: "validation error on available_tickets_non_negative: violates check constraint",
+		},
+		{
+			name:        "maps serialization failure to sentinel",
+			err:         &pq.Error{Code: "40001"},
+			wantIs:      ErrSerializationFailure,
+			wantMessage: ErrSerializationFailure.Error(),
+		},
+		{
+			name:        "maps deadlock to sentinel",
+			err:         &pq.Error{Code: "40P01"},
+			wantIs:      ErrDeadlockDetected,
+			wantMessage: ErrDeadlockDetected.Error(),
+		},
+		{
+			name:        "maps query cancellation to sentinel",
+			err:         &pq.Error{Code: "57014"},
+			wantIs:      ErrQueryCanceled,
+			wantMessage: ErrQueryCanceled.Error(),
+		},
+		{
+			name:        "classifies wrapped driver errors",
+			err:         fmt.Errorf("failed to update ticket availability: %w", &pq.Error{Code: "40001"}),
+			wantIs:      ErrSerializationFailure,
+			wantMessage: ErrSerializationFailure.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			classified := ClassifyDBError(tt.err)
+
+			if tt.wantIs != nil {
+				assert.True(t, errors.Is(classified, tt.wantIs))
+			}
+			if tt.wantAs != nil {
+				assert.True(t, errors.As(classified, tt.wantAs))
+			}
+			assert.Equal(t, tt.wantMessage, classified.Error())
+
+			var pqErr *pq.Error
+			assert.True(t, errors.As(classified, &pqErr), "driver error must stay reachable")
+		})
+	}
+}
+
+func TestClassifyDBError_PassesThroughUnmappedErrors(t *testing.T) {
+	unmapped := &pq.Error{Code: "42P01"}
+	plain := errors.New("connection reset by peer")
+
+	assert.Nil(t, ClassifyDBError(nil))
+	assert.Same(t, unmapped, ClassifyDBError(unmapped))
+	assert.Same(t, plain, ClassifyDBError(plain))
+}
+
+func TestIsConnectionError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "detects refused TCP connection",
+			err:      &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connect: connection refused")},
+			expected: true,
+		},
+		{
+			name:     "detects bad pooled connection",
+			err:      fmt.Errorf("failed to find event: %w", driver.ErrBadConn),
+			expected: true,
+		},
+		{
+			name:     "detects server shutting down",
+			err:      &pq.Error{Code: "57P01"},
+			expected: true,
+		},
+		{
+			name:     "detects connection exception class",
+			err:      &pq.Error{Code: "08006"},
+			expected: true,
+		},
+		{
+			name:     "ignores constraint violations",
+			err:      &pq.Error{Code: "23505"},
+			expected: false,
+		},
+		{
+			name:     "ignores domain errors",
+			err:      domain.ErrEventNotFound,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsConnectionError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultDependencyProbeTimeout bounds each probe, so one hanging dependency cannot stall readiness
+const DefaultDependencyProbeTimeout = 2 * time.Second
+
+// DependencyProbe cheaply checks that an external dependency is reachable, e.g. a broker metadata request
+// or an SMTP NOOP; it must return once ctx is done
+type DependencyProbe func(ctx context.Context) error
+
+// HTTPProbe reports a service reachable when a GET of url through client gets any response below 500
+func HTTPProbe(client *http.Client, url string) DependencyProbe {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("%s responded %d", url, resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// DependencyStatus is the outcome of one dependency's probe
+// Critical dependencies are needed to serve bookings, so readiness fails without them; any other
+// unhealthy dependency only degrades it
+type DependencyStatus struct {
+	Name     string
+	Critical bool
+	Healthy  bool
+	Latency  time.Duration
+	Err      error
+}
+
+type dependency struct {
+	critical bool
+	probe    DependencyProbe
+}
+
+// DependencyChecker is where integrations with external services register a health probe for readiness
+type DependencyChecker struct {
+	mu           sync.RWMutex
+	dependencies map[string]dependency
+	timeout      time.Duration
+}
+
+// NewDependencyChecker runs each probe for at most timeout
+func NewDependencyChecker(timeout time.Duration) *DependencyChecker {
+	return &DependencyChecker{
+		dependencies: make(map[string]dependency),
+		timeout:      timeout,
+	}
+}
+
+// Register adds a dependency, replacing one registered under the same name
+func (c *DependencyChecker) Register(name string, critical bool, probe DependencyProbe) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.dependencies[name] = dependency{critical: critical, probe: probe}
+}
+
+// Check probes every dependency concurrently and returns their statuses ordered by name
+// A probe that panics reports its dependency unhealthy instead of taking the process down
+func (c *DependencyChecker) Check(ctx context.Context) []DependencyStatus {
+	c.mu.RLock()
+	statuses := make([]DependencyStatus, 0, len(c.dependencies))
+	probes := make([]DependencyProbe, 0, len(c.dependencies))
+	for name, dep := range c.dependencies {
+		statuses = append(statuses, DependencyStatus{Name: name, Critical: dep.critical})
+		probes = append(probes, dep.probe)
+	}
+	c.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for i := range statuses {
+		wg.Add(1)
+		go func(status *DependencyStatus, probe DependencyProbe) {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+
+			start := time.Now()
+			defer func() {
+				if r := recover(); r != nil {
+					status.Err = fmt.Errorf("probe panicked: %v", r)
+				}
+				status.Latency = time.Since(start)
+				status.Healthy = status.Err == nil
+			}()
+			status.Err = probe(probeCtx)
+		}(&statuses[i], probes[i])
+	}
+	wg.Wait()
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
@@ -0,0 +1,68 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependencyChecker(t *testing.T) {
+	checker := NewDependencyChecker(20 * time.Millisecond)
+	checker.Register("smtp", false, func(ctx context.Context) error { return nil })
+	checker.Register("kafka", false, func(ctx context.Context) error { return errors.New("no brokers available") })
+	checker.Register("payments", true, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	start := time.Now()
+	statuses := checker.Check(context.Background())
+	assert.Less(t, time.Since(start), time.Second, "a hanging probe is cut off by the timeout")
+
+	require.Len(t, statuses, 3)
+	assert.Equal(t, []string{"kafka", "payments", "smtp"}, []string{statuses[0].Name, statuses[1].Name, statuses[2].Name})
+
+	assert.False(t, statuses[0].Healthy)
+	assert.EqualError(t, statuses[0].Err, "no brokers available")
+	assert.False(t, statuses[1].Healthy)
+	assert.True(t, statuses[1].Critical)
+	assert.ErrorIs(t, statuses[1].Err, context.DeadlineExceeded)
+	assert.True(t, statuses[2].Healthy)
+	assert.NoError(t, statuses[2].Err)
+}
+
+func TestDependencyChecker_RecoversFromPanickingProbe(t *testing.T) {
+	checker := NewDependencyChecker(time.Second)
+	checker.Register("payments", false, func(ctx context.Context) error { panic("nil client") })
+	checker.Register("smtp", false, func(ctx context.Context) error { return nil })
+
+	statuses := checker.Check(context.Background())
+
+	require.Len(t, statuses, 2)
+	assert.False(t, statuses[0].Healthy)
+	assert.EqualError(t, statuses[0].Err, "probe panicked: nil client")
+	assert.True(t, statuses[1].Healthy)
+}
+
+func TestHTTPProbe(t *testing.T) {
+	status := http.StatusNotFound
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	probe := HTTPProbe(server.Client(), server.URL)
+
+	assert.NoError(t, probe(context.Background()), "any response below 500 means the service is reachable")
+
+	status = http.StatusServiceUnavailable
+	assert.EqualError(t, probe(context.Background()), server.URL+" responded 503")
+
+	server.Close()
+	assert.Error(t, probe(context.Background()))
+}
@@ -0,0 +1,111 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jorzel/booking-service/internal/domain"
+)
+
+const discountCodeColumns = "code, percent_off, amount_off_cents, currency, max_uses, uses, expires_at"
+
+type PostgresDiscountCodeRepository struct {
+	db DBClient
+}
+
+func NewPostgresDiscountCodeRepository(db DBClient) *PostgresDiscountCodeRepository {
+	return &PostgresDiscountCodeRepository{db: db}
+}
+
+func (r *PostgresDiscountCodeRepository) Create(ctx context.Context, code *domain.DiscountCode) error {
+	query := `
+		INSERT INTO discount_codes (` + discountCodeColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		code.Code,
+		code.PercentOff,
+		code.AmountOffCents,
+		code.Currency,
+		code.MaxUses,
+		code.Uses,
+		nullTime(code.ExpiresAt),
+	)
+	if isUniqueViolation(err) {
+		return domain.ErrDiscountCodeExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create discount code: %w", ClassifyDBError(err))
+	}
+
+	return nil
+}
+
+// FindByCodeWithLock selects the code FOR UPDATE; concurrent redemptions of one code queue on its row
+func (r *PostgresDiscountCodeRepository) FindByCodeWithLock(ctx context.Context, exec domain.Executor, code string) (*domain.DiscountCode, error) {
+	query := `
+		SELECT ` + discountCodeColumns + `
+		FROM discount_codes
+		WHERE code = $1
+		FOR UPDATE
+	`
+
+	discountCode, err := scanDiscountCode(exec.QueryRowContext(ctx, query, code))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrDiscountCodeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find discount code: %w", ClassifyDBError(err))
+	}
+
+	return discountCode, nil
+}
+
+func (r *PostgresDiscountCodeRepository) UpdateUsesWithExecutor(ctx context.Context, exec domain.Executor, code *domain.DiscountCode) error {
+	query := `
+		UPDATE discount_codes
+		SET uses = $2
+		WHERE code = $1
+	`
+
+	result, err := exec.ExecContext(ctx, query, code.Code, code.Uses)
+	if err != nil {
+		return fmt.Errorf("failed to update discount code: %w", ClassifyDBError(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrDiscountCodeNotFound
+	}
+
+	return nil
+}
+
+func scanDiscountCode(row rowScanner) (*domain.DiscountCode, error) {
+	code := &domain.DiscountCode{}
+	var expiresAt sql.NullTime
+	err := row.Scan(
+		&code.Code,
+		&code.PercentOff,
+		&code.AmountOffCents,
+		&code.Currency,
+		&code.MaxUses,
+		&code.Uses,
+		&expiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	code.ExpiresAt = expiresAt.Time
+	return code, nil
+}
@@ -5,26 +5,341 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/lib/pq"
 )
 
+// eventColumns lists the events columns in the order expected by scanEvent
+const eventColumns = `id, name, date, location, tickets, organizer_id, min_advance_seconds, status, cancelled_at,
+	min_viable, viability_deadline, seated, price_cents, currency, tags, bookings_paused, created_at, image_url, thumbnail_url, end_date,
+	quantity_step, refund_notice_seconds, refund_percents, lottery`
+
 type PostgresEventRepository struct {
 	db DBClient
+	queryLogger
+	skipMalformedRows bool
 }
 
-func NewPostgresEventRepository(db DBClient) *PostgresEventRepository {
-	return &PostgresEventRepository{db: db}
+func NewPostgresEventRepository(db DBClient, opts ...RepositoryOption) *PostgresEventRepository {
+	options := newRepositoryOptions(opts)
+	return &PostgresEventRepository{
+		db:                db,
+		queryLogger:       queryLogger{logger: options.logger},
+		skipMalformedRows: options.skipMalformedRows,
+	}
 }
 
 func (r *PostgresEventRepository) Create(ctx context.Context, event *domain.Event) error {
+	return r.CreateWithExecutor(ctx, r.db, event)
+}
+
+func (r *PostgresEventRepository) FindByID(ctx context.Context, id uuid.UUID) (_ *domain.Event, err error) {
+	defer r.logFailure("event.find_by_id", time.Now(), &err)
+
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events
+		WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+	`
+
+	event, err := scanEvent(r.db.QueryRowContext(ctx, query, id, TenantFromContext(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrEventNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find event: %w", ClassifyDBError(err))
+	}
+
+	return event, nil
+}
+
+func (r *PostgresEventRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) (_ []*domain.Event, err error) {
+	defer r.logFailure("event.find_by_ids", time.Now(), &err)
+
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events
+		WHERE id = ANY($1::uuid[]) AND tenant_id = $2 AND deleted_at IS NULL
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(uuidStrings(ids)), TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	var events []*domain.Event
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", ClassifyDBError(err))
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating events: %w", ClassifyDBError(err))
+	}
+
+	return events, nil
+}
+
+func (r *PostgresEventRepository) FindUpcoming(ctx context.Context, from time.Time, onlyAvailable bool, limit, offset int) (_ []*domain.Event, err error) {
+	defer r.logFailure("event.find_upcoming", time.Now(), &err)
+
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events
+		WHERE tenant_id = $6 AND deleted_at IS NULL
+			AND date >= $1
+			AND status = $2
+			AND (NOT $3 OR EXISTS (
+				SELECT 1 FROM ticket_availability ta
+				WHERE ta.event_id = events.id AND ta.available_tickets > 0
+			))
+		ORDER BY date ASC, id ASC
+		LIMIT $4 OFFSET $5
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, from, domain.EventStatusActive, onlyAvailable, limit, offset, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query upcoming events: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	var events []*domain.Event
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", ClassifyDBError(err))
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating upcoming events: %w", ClassifyDBError(err))
+	}
+
+	return events, nil
+}
+
+// CountUpcoming counts the events matching FindUpcoming's filter
+func (r *PostgresEventRepository) CountUpcoming(ctx context.Context, from time.Time, onlyAvailable bool) (_ int, err error) {
+	defer r.logFailure("event.count_upcoming", time.Now(), &err)
+
+	query := `
+		SELECT COUNT(*)
+		FROM events
+		WHERE tenant_id = $4 AND deleted_at IS NULL
+			AND date >= $1
+			AND status = $2
+			AND (NOT $3 OR EXISTS (
+				SELECT 1 FROM ticket_availability ta
+				WHERE ta.event_id = events.id AND ta.available_tickets > 0
+			))
+	`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, from, domain.EventStatusActive, onlyAvailable, TenantFromContext(ctx)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count upcoming events: %w", ClassifyDBError(err))
+	}
+
+	return count, nil
+}
+
+// FindPage returns the next page of events matching filter by keyset, so deep pages cost the same as the first
+func (r *PostgresEventRepository) FindPage(ctx context.Context, filter domain.EventFilter, after *domain.EventCursor, limit int) (_ []*domain.Event, err error) {
+	defer r.logFailure("event.find_page", time.Now(), &err)
+
+	args := []interface{}{limit, TenantFromContext(ctx)}
+	conditions := []string{"tenant_id = $2", "deleted_at IS NULL"}
+	if after != nil {
+		args = append(args, after.Date, after.ID)
+		conditions = append(conditions, "(date, id) > ($3, $4)")
+	}
+	if len(filter.Tags) > 0 {
+		args = append(args, pq.StringArray(filter.Tags))
+		conditions = append(conditions, tagCondition(filter.TagMatch, len(args)))
+	}
+
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events
+		` + whereClause(conditions) + `
+		ORDER BY date ASC, id ASC
+		LIMIT $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events page: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	var events []*domain.Event
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", ClassifyDBError(err))
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating events page: %w", ClassifyDBError(err))
+	}
+
+	return events, nil
+}
+
+// Count counts the events matching filter
+func (r *PostgresEventRepository) Count(ctx context.Context, filter domain.EventFilter) (_ int, err error) {
+	defer r.logFailure("event.count", time.Now(), &err)
+
+	args := []interface{}{TenantFromContext(ctx)}
+	conditions := []string{"tenant_id = $1", "deleted_at IS NULL"}
+	if len(filter.Tags) > 0 {
+		args = append(args, pq.StringArray(filter.Tags))
+		conditions = append(conditions, tagCondition(filter.TagMatch, len(args)))
+	}
+	query := "SELECT COUNT(*) FROM events " + whereClause(conditions)
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count events: %w", ClassifyDBError(err))
+	}
+
+	return count, nil
+}
+
+// LastModified reads the tenant's newest updated_at, which the idx_events_tenant_id_updated_at index answers without a scan
+func (r *PostgresEventRepository) LastModified(ctx context.Context) (_ time.Time, err error) {
+	defer r.logFailure("event.last_modified", time.Now(), &err)
+
+	var lastModified sql.NullTime
+	if err := r.db.QueryRowContext(ctx, "SELECT MAX(updated_at) FROM events WHERE tenant_id = $1", TenantFromContext(ctx)).Scan(&lastModified); err != nil {
+		return time.Time{}, fmt.Errorf("failed to read events last modified: %w", ClassifyDBError(err))
+	}
+
+	return lastModified.Time, nil
+}
+
+func (r *PostgresEventRepository) FindByNameAndDate(ctx context.Context, name string, date time.Time) (_ *domain.Event, err error) {
+	defer r.logFailure("event.find_by_name_and_date", time.Now(), &err)
+
 	query := `
-		INSERT INTO events (id, name, date, location, tickets)
-		VALUES ($1, $2, $3, $4, $5)
+		SELECT ` + eventColumns + `
+		FROM events
+		WHERE lower(name) = lower($1) AND date >= $2 AND date < $3 AND tenant_id = $4 AND deleted_at IS NULL
+		ORDER BY date ASC, id ASC
+		LIMIT 1
 	`
 
-	_, err := r.db.ExecContext(
+	dayStart := date.UTC().Truncate(24 * time.Hour)
+	event, err := scanEvent(r.db.QueryRowContext(ctx, query, name, dayStart, dayStart.Add(24*time.Hour), TenantFromContext(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrEventNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find event by name and date: %w", ClassifyDBError(err))
+	}
+
+	return event, nil
+}
+
+// FindAll returns every event ordered by date, and how many malformed rows were skipped
+// Rows that fail to scan fail the call unless the repository was built WithSkipMalformedRows
+func (r *PostgresEventRepository) FindAll(ctx context.Context) (_ []*domain.Event, skipped int, err error) {
+	defer r.logFailure("event.find_all", time.Now(), &err)
+
+	var events []*domain.Event
+	onScanError := func(scanErr error) error {
+		if !r.skipMalformedRows {
+			return scanErr
+		}
+		skipped++
+		r.logger.Warn().Err(scanErr).Msg("skipping malformed event row")
+		return nil
+	}
+	err = r.stream(ctx, func(event *domain.Event) error {
+		events = append(events, event)
+		return nil
+	}, onScanError)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return events, skipped, nil
+}
+
+// Stream calls fn for each event ordered by date, reading rows one at a time
+// Iteration stops at the first error returned by fn
+func (r *PostgresEventRepository) Stream(ctx context.Context, fn func(*domain.Event) error) (err error) {
+	defer r.logFailure("event.stream", time.Now(), &err)
+
+	return r.stream(ctx, fn, func(scanErr error) error { return scanErr })
+}
+
+// stream reads events ordered by date; a row failing to scan stops iteration when onScanError returns an error
+func (r *PostgresEventRepository) stream(ctx context.Context, fn func(*domain.Event) error, onScanError func(error) error) error {
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events
+		WHERE tenant_id = $1 AND deleted_at IS NULL
+		ORDER BY date ASC, id ASC
+	`
+
+	return withStreamExecutor(ctx, r.db, func(exec domain.Executor) error {
+		rows, err := exec.QueryContext(ctx, query, TenantFromContext(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to query events: %w", ClassifyDBError(err))
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			event, err := scanEvent(rows)
+			if err != nil {
+				if err := onScanError(fmt.Errorf("failed to scan event: %w", ClassifyDBError(err))); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating events: %w", ClassifyDBError(err))
+		}
+
+		return nil
+	})
+}
+
+func (r *PostgresEventRepository) Update(ctx context.Context, event *domain.Event) error {
+	return r.UpdateWithExecutor(ctx, r.db, event)
+}
+
+// UpdateWithExecutor updates an event using the provided executor (transaction or db)
+func (r *PostgresEventRepository) UpdateWithExecutor(ctx context.Context, exec domain.Executor, event *domain.Event) (err error) {
+	defer r.logFailure("event.update", time.Now(), &err)
+
+	query := `
+		UPDATE events
+		SET name = $2, date = $3, location = $4, tickets = $5, organizer_id = $6, min_advance_seconds = $7,
+			status = $8, cancelled_at = $9, min_viable = $10, viability_deadline = $11, seated = $12,
+			price_cents = $13, currency = $14, tags = $15, bookings_paused = $16, image_url = $17, thumbnail_url = $18,
+			end_date = $20, quantity_step = $21, refund_notice_seconds = $22, refund_percents = $23, lottery = $24, updated_at = now()
+		WHERE id = $1 AND tenant_id = $19 AND deleted_at IS NULL
+	`
+
+	noticeSeconds, percents := eventRefundTiers(event)
+	result, err := exec.ExecContext(
 		ctx,
 		query,
 		event.ID,
@@ -32,84 +347,135 @@ func (r *PostgresEventRepository) Create(ctx context.Context, event *domain.Even
 		event.Date,
 		event.Location,
 		event.Tickets,
+		nullUUID(event.OrganizerID),
+		int64(event.MinAdvance/time.Second),
+		event.Status,
+		nullTime(event.CancelledAt),
+		event.MinViable,
+		nullTime(event.ViabilityDeadline),
+		event.Seated,
+		event.PriceCents,
+		event.Currency,
+		eventTags(event),
+		event.BookingsPaused,
+		event.ImageURL,
+		event.ThumbnailURL,
+		TenantFromContext(ctx),
+		nullTime(event.EndDate),
+		eventQuantityStep(event),
+		noticeSeconds,
+		percents,
+		event.Lottery,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create event: %w", err)
+		return fmt.Errorf("failed to update event: %w", ClassifyDBError(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrEventNotFound
 	}
 
 	return nil
 }
 
-func (r *PostgresEventRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Event, error) {
+// FindByIDWithLock retrieves an event with a row-level lock (FOR UPDATE)
+// This should be used within a transaction to serialize event state transitions
+func (r *PostgresEventRepository) FindByIDWithLock(ctx context.Context, exec domain.Executor, id uuid.UUID) (_ *domain.Event, err error) {
+	defer r.logFailure("event.find_by_id_with_lock", time.Now(), &err)
+
 	query := `
-		SELECT id, name, date, location, tickets
+		SELECT ` + eventColumns + `
 		FROM events
-		WHERE id = $1
+		WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+		FOR UPDATE
 	`
 
-	event := &domain.Event{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&event.ID,
-		&event.Name,
-		&event.Date,
-		&event.Location,
-		&event.Tickets,
-	)
+	event, err := scanEvent(exec.QueryRowContext(ctx, query, id, TenantFromContext(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrEventNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find event: %w", ClassifyDBError(err))
+	}
+
+	return event, nil
+}
 
+// FindByIDForShare retrieves an event with a shared row lock (FOR SHARE)
+// Concurrent bookings can all hold it, while a state transition locking the event FOR UPDATE
+// waits for them, so a booking never commits against a state it did not see
+func (r *PostgresEventRepository) FindByIDForShare(ctx context.Context, exec domain.Executor, id uuid.UUID) (_ *domain.Event, err error) {
+	defer r.logFailure("event.find_by_id_for_share", time.Now(), &err)
+
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events
+		WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+		FOR SHARE
+	`
+
+	event, err := scanEvent(exec.QueryRowContext(ctx, query, id, TenantFromContext(ctx)))
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, domain.ErrEventNotFound
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to find event: %w", err)
+		return nil, fmt.Errorf("failed to find event: %w", ClassifyDBError(err))
 	}
 
 	return event, nil
 }
 
-func (r *PostgresEventRepository) FindAll(ctx context.Context) ([]*domain.Event, error) {
+// FindByIDsForShare retrieves events with shared row locks (FOR SHARE), taken in ID order so that
+// transactions locking overlapping sets of events cannot deadlock
+func (r *PostgresEventRepository) FindByIDsForShare(ctx context.Context, exec domain.Executor, ids []uuid.UUID) (_ []*domain.Event, err error) {
+	defer r.logFailure("event.find_by_ids_for_share", time.Now(), &err)
+
 	query := `
-		SELECT id, name, date, location, tickets
+		SELECT ` + eventColumns + `
 		FROM events
-		ORDER BY date ASC
+		WHERE id = ANY($1::uuid[]) AND tenant_id = $2 AND deleted_at IS NULL
+		ORDER BY id
+		FOR SHARE
 	`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := exec.QueryContext(ctx, query, pq.Array(uuidStrings(ids)), TenantFromContext(ctx))
 	if err != nil {
-		return nil, fmt.Errorf("failed to query events: %w", err)
+		return nil, fmt.Errorf("failed to query events: %w", ClassifyDBError(err))
 	}
 	defer rows.Close()
 
 	var events []*domain.Event
 	for rows.Next() {
-		event := &domain.Event{}
-		err := rows.Scan(
-			&event.ID,
-			&event.Name,
-			&event.Date,
-			&event.Location,
-			&event.Tickets,
-		)
+		event, err := scanEvent(rows)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan event: %w", err)
+			return nil, fmt.Errorf("failed to scan event: %w", ClassifyDBError(err))
 		}
 		events = append(events, event)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating events: %w", err)
+		return nil, fmt.Errorf("error iterating events: %w", ClassifyDBError(err))
 	}
 
 	return events, nil
 }
 
-func (r *PostgresEventRepository) Update(ctx context.Context, event *domain.Event) error {
+// CreateWithExecutor creates an event using the provided executor (transaction or db)
+func (r *PostgresEventRepository) CreateWithExecutor(ctx context.Context, exec domain.Executor, event *domain.Event) (err error) {
+	defer r.logFailure("event.create", time.Now(), &err)
+
 	query := `
-		UPDATE events
-		SET name = $2, date = $3, location = $4, tickets = $5
-		WHERE id = $1
+		INSERT INTO events (` + eventColumns + `, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, COALESCE($17, now()), $18, $19, $20, $21, $22, $23, $24, $25)
 	`
 
-	result, err := r.db.ExecContext(
+	noticeSeconds, percents := eventRefundTiers(event)
+	_, err = exec.ExecContext(
 		ctx,
 		query,
 		event.ID,
@@ -117,9 +483,47 @@ func (r *PostgresEventRepository) Update(ctx context.Context, event *domain.Even
 		event.Date,
 		event.Location,
 		event.Tickets,
+		nullUUID(event.OrganizerID),
+		int64(event.MinAdvance/time.Second),
+		event.Status,
+		nullTime(event.CancelledAt),
+		event.MinViable,
+		nullTime(event.ViabilityDeadline),
+		event.Seated,
+		event.PriceCents,
+		event.Currency,
+		eventTags(event),
+		event.BookingsPaused,
+		nullTime(event.CreatedAt),
+		event.ImageURL,
+		event.ThumbnailURL,
+		nullTime(event.EndDate),
+		eventQuantityStep(event),
+		noticeSeconds,
+		percents,
+		event.Lottery,
+		TenantFromContext(ctx),
 	)
 	if err != nil {
-		return fmt.Errorf("failed to update event: %w", err)
+		return fmt.Errorf("failed to create event: %w", ClassifyDBError(err))
+	}
+
+	return nil
+}
+
+// MarkMergedWithExecutor soft-deletes the event as a duplicate of mergedInto, hiding it from every read
+func (r *PostgresEventRepository) MarkMergedWithExecutor(ctx context.Context, exec domain.Executor, id, mergedInto uuid.UUID) (err error) {
+	defer r.logFailure("event.mark_merged", time.Now(), &err)
+
+	query := `
+		UPDATE events
+		SET deleted_at = now(), merged_into = $2, updated_at = now()
+		WHERE id = $1 AND tenant_id = $3 AND deleted_at IS NULL
+	`
+
+	result, err := exec.ExecContext(ctx, query, id, mergedInto, TenantFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to mark event merged: %w", ClassifyDBError(err))
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -134,25 +538,279 @@ func (r *PostgresEventRepository) Update(ctx context.Context, event *domain.Even
 	return nil
 }
 
-// CreateWithExecutor creates an event using the provided executor (transaction or db)
-func (r *PostgresEventRepository) CreateWithExecutor(ctx context.Context, exec domain.Executor, event *domain.Event) error {
+func (r *PostgresEventRepository) CountByOrganizer(ctx context.Context, organizerID uuid.UUID) (_ int, err error) {
+	defer r.logFailure("event.count_by_organizer", time.Now(), &err)
+
+	var count int
+	err = r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM events WHERE organizer_id = $1 AND tenant_id = $2 AND deleted_at IS NULL", organizerID, TenantFromContext(ctx)).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count organizer events: %w", ClassifyDBError(err))
+	}
+
+	return count, nil
+}
+
+// FindBookedByUser joins the user's distinct booked event IDs, so several bookings for one event yield it once
+func (r *PostgresEventRepository) FindBookedByUser(ctx context.Context, userID uuid.UUID, limit, offset int) (_ []*domain.Event, err error) {
+	defer r.logFailure("event.find_booked_by_user", time.Now(), &err)
+
 	query := `
-		INSERT INTO events (id, name, date, location, tickets)
-		VALUES ($1, $2, $3, $4, $5)
+		SELECT ` + eventColumns + `
+		FROM events
+		JOIN (
+			SELECT DISTINCT event_id FROM bookings WHERE user_id = $1 AND status <> $2 AND tenant_id = $5
+		) booked ON booked.event_id = events.id
+		ORDER BY date ASC, id ASC
+		LIMIT $3 OFFSET $4
 	`
 
-	_, err := exec.ExecContext(
-		ctx,
-		query,
-		event.ID,
-		event.Name,
-		event.Date,
-		event.Location,
-		event.Tickets,
+	rows, err := r.db.QueryContext(ctx, query, userID, domain.BookingStatusCancelled, limit, offset, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user events: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	var events []*domain.Event
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", ClassifyDBError(err))
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating user events: %w", ClassifyDBError(err))
+	}
+
+	return events, nil
+}
+
+// CountBookedByUser counts the events FindBookedByUser pages through
+func (r *PostgresEventRepository) CountBookedByUser(ctx context.Context, userID uuid.UUID) (_ int, err error) {
+	defer r.logFailure("event.count_booked_by_user", time.Now(), &err)
+
+	var count int
+	err = r.db.QueryRowContext(ctx,
+		"SELECT COUNT(DISTINCT event_id) FROM bookings WHERE user_id = $1 AND status <> $2 AND tenant_id = $3",
+		userID, domain.BookingStatusCancelled, TenantFromContext(ctx),
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count user events: %w", ClassifyDBError(err))
+	}
+
+	return count, nil
+}
+
+// SumTicketsByUser groups the user's bookings by event in a single query joined with the event names
+func (r *PostgresEventRepository) SumTicketsByUser(ctx context.Context, userID uuid.UUID) (_ []*domain.UserEventTickets, err error) {
+	defer r.logFailure("event.sum_tickets_by_user", time.Now(), &err)
+
+	query := `
+		SELECT e.id, e.name, SUM(b.tickets_booked)
+		FROM bookings b
+		JOIN events e ON e.id = b.event_id
+		WHERE b.user_id = $1 AND b.status <> $2 AND b.tenant_id = $3
+		GROUP BY e.id, e.name, e.date
+		ORDER BY e.date ASC, e.id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, domain.BookingStatusCancelled, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user tickets: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	perEvent := make([]*domain.UserEventTickets, 0)
+	for rows.Next() {
+		tickets := &domain.UserEventTickets{}
+		if err := rows.Scan(&tickets.EventID, &tickets.EventName, &tickets.Tickets); err != nil {
+			return nil, fmt.Errorf("failed to scan user tickets: %w", ClassifyDBError(err))
+		}
+		perEvent = append(perEvent, tickets)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating user tickets: %w", ClassifyDBError(err))
+	}
+
+	return perEvent, nil
+}
+
+// FindSummariesByOrganizer aggregates bookings for all organizer events in a single grouped query
+func (r *PostgresEventRepository) FindSummariesByOrganizer(ctx context.Context, organizerID uuid.UUID, limit, offset int) (_ []*domain.EventBookingSummary, err error) {
+	defer r.logFailure("event.find_summaries_by_organizer", time.Now(), &err)
+
+	query := `
+		SELECT e.id, e.name, e.date, e.tickets,
+			COALESCE(ta.available_tickets, 0),
+			COUNT(b.id),
+			COALESCE(SUM(b.tickets_booked), 0)
+		FROM events e
+		LEFT JOIN ticket_availability ta ON ta.event_id = e.id
+		LEFT JOIN bookings b ON b.event_id = e.id AND b.status <> 'cancelled'
+		WHERE e.organizer_id = $1 AND e.tenant_id = $4 AND e.deleted_at IS NULL
+		GROUP BY e.id, ta.available_tickets
+		ORDER BY e.date ASC, e.id ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, organizerID, limit, offset, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query organizer events: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	summaries := make([]*domain.EventBookingSummary, 0)
+	for rows.Next() {
+		summary := &domain.EventBookingSummary{}
+		err := rows.Scan(
+			&summary.EventID,
+			&summary.Name,
+			&summary.Date,
+			&summary.Tickets,
+			&summary.AvailableTickets,
+			&summary.BookingsCount,
+			&summary.TicketsSold,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan organizer event summary: %w", ClassifyDBError(err))
+		}
+		summaries = append(summaries, summary)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating organizer event summaries: %w", ClassifyDBError(err))
+	}
+
+	return summaries, nil
+}
+
+// nullUUID stores uuid.Nil as SQL NULL for optional references
+func nullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
+
+// uuidStrings converts IDs for binding as a uuid[] array parameter
+func uuidStrings(ids []uuid.UUID) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = id.String()
+	}
+	return out
+}
+
+// nullTime stores the zero time as SQL NULL for optional timestamps
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanEvent reads a row selected with eventColumns into a domain event
+func scanEvent(row rowScanner) (*domain.Event, error) {
+	event := &domain.Event{}
+	var minAdvanceSeconds int64
+	var cancelledAt sql.NullTime
+	var viabilityDeadline sql.NullTime
+	var endDate sql.NullTime
+	var refundNoticeSeconds pq.Int64Array
+	var refundPercents pq.Int64Array
+
+	err := row.Scan(
+		&event.ID,
+		&event.Name,
+		&event.Date,
+		&event.Location,
+		&event.Tickets,
+		&event.OrganizerID,
+		&minAdvanceSeconds,
+		&event.Status,
+		&cancelledAt,
+		&event.MinViable,
+		&viabilityDeadline,
+		&event.Seated,
+		&event.PriceCents,
+		&event.Currency,
+		(*pq.StringArray)(&event.Tags),
+		&event.BookingsPaused,
+		&event.CreatedAt,
+		&event.ImageURL,
+		&event.ThumbnailURL,
+		&endDate,
+		&event.QuantityStep,
+		&refundNoticeSeconds,
+		&refundPercents,
+		&event.Lottery,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create event: %w", err)
+		return nil, err
+	}
+	if len(refundNoticeSeconds) != len(refundPercents) {
+		return nil, fmt.Errorf("event %s has %d refund notices but %d refund percents", event.ID, len(refundNoticeSeconds), len(refundPercents))
+	}
+	if refundNoticeSeconds != nil {
+		tiers := make([]domain.RefundTier, len(refundNoticeSeconds))
+		for i := range tiers {
+			tiers[i] = domain.RefundTier{MinNotice: time.Duration(refundNoticeSeconds[i]) * time.Second, RefundPercent: int(refundPercents[i])}
+		}
+		policy, err := domain.NewCancellationPolicy(tiers)
+		if err != nil {
+			return nil, err
+		}
+		event.CancellationPolicy = &policy
 	}
 
-	return nil
+	event.MinAdvance = time.Duration(minAdvanceSeconds) * time.Second
+	event.CancelledAt = cancelledAt.Time
+	event.ViabilityDeadline = viabilityDeadline.Time
+	event.EndDate = endDate.Time
+	return event, nil
+}
+
+// eventRefundTiers splits the event's own refund tiers into the refund_notice_seconds and refund_percents
+// columns; both are NULL for an event following the default policy
+func eventRefundTiers(event *domain.Event) (noticeSeconds, percents pq.Int64Array) {
+	if event.CancellationPolicy == nil {
+		return nil, nil
+	}
+	tiers := event.CancellationPolicy.Tiers()
+	noticeSeconds, percents = make(pq.Int64Array, len(tiers)), make(pq.Int64Array, len(tiers))
+	for i, tier := range tiers {
+		noticeSeconds[i] = int64(tier.MinNotice / time.Second)
+		percents[i] = int64(tier.RefundPercent)
+	}
+	return noticeSeconds, percents
+}
+
+// eventQuantityStep writes an event built without NewEvent as selling any quantity
+func eventQuantityStep(event *domain.Event) int {
+	return max(event.QuantityStep, 1)
+}
+
+// eventTags writes an event built without NewEvent as untagged rather than violating tags NOT NULL
+func eventTags(event *domain.Event) pq.StringArray {
+	if event.Tags == nil {
+		return pq.StringArray{}
+	}
+	return event.Tags
+}
+
+// tagCondition matches the tags array bound at placeholder n: && for any of them, @> for all of them
+// Both operators are served by the GIN index on events.tags
+func tagCondition(match domain.TagMatch, n int) string {
+	if match == domain.TagMatchAll {
+		return fmt.Sprintf("tags @> $%d", n)
+	}
+	return fmt.Sprintf("tags && $%d", n)
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(conditions, " AND ")
 }
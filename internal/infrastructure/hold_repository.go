@@ -0,0 +1,216 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+)
+
+const holdColumns = "id, event_id, user_id, tickets, status, expires_at, created_at, booking_id, expiry_notified_at, deposit_cents, payment_id"
+
+// holdOfTenant limits a holds query to the holds on events of the tenant bound to $2
+const holdOfTenant = "event_id IN (SELECT id FROM events WHERE tenant_id = $2)"
+
+type PostgresHoldRepository struct {
+	db DBClient
+}
+
+func NewPostgresHoldRepository(db DBClient) *PostgresHoldRepository {
+	return &PostgresHoldRepository{db: db}
+}
+
+// FindByID reads a hold on an event of the context's tenant
+func (r *PostgresHoldRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Hold, error) {
+	query := `
+		SELECT ` + holdColumns + `
+		FROM holds
+		WHERE id = $1 AND ` + holdOfTenant + `
+	`
+
+	hold, err := scanHold(r.db.QueryRowContext(ctx, query, id, TenantFromContext(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrHoldNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find hold: %w", ClassifyDBError(err))
+	}
+
+	return hold, nil
+}
+
+// CreateWithExecutor creates a hold using the provided executor (transaction or db)
+func (r *PostgresHoldRepository) CreateWithExecutor(ctx context.Context, exec domain.Executor, hold *domain.Hold) error {
+	query := `
+		INSERT INTO holds (` + holdColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err := exec.ExecContext(
+		ctx,
+		query,
+		hold.ID,
+		hold.EventID,
+		hold.UserID,
+		hold.Tickets,
+		hold.Status,
+		hold.ExpiresAt,
+		hold.CreatedAt,
+		nullUUID(hold.BookingID),
+		nullTime(hold.ExpiryNotifiedAt),
+		hold.DepositCents,
+		sql.NullString{String: hold.PaymentID, Valid: hold.PaymentID != ""},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create hold: %w", ClassifyDBError(err))
+	}
+
+	return nil
+}
+
+// FindByIDWithLock retrieves a hold with a row-level lock (FOR UPDATE)
+// This should be used within a transaction so confirmation and expiry cannot race
+// Like FindByID, holds on other tenants' events are not found
+func (r *PostgresHoldRepository) FindByIDWithLock(ctx context.Context, exec domain.Executor, id uuid.UUID) (*domain.Hold, error) {
+	query := `
+		SELECT ` + holdColumns + `
+		FROM holds
+		WHERE id = $1 AND ` + holdOfTenant + `
+		FOR UPDATE
+	`
+
+	hold, err := scanHold(exec.QueryRowContext(ctx, query, id, TenantFromContext(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrHoldNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find hold: %w", ClassifyDBError(err))
+	}
+
+	return hold, nil
+}
+
+// UpdateWithExecutor persists the hold's status, booking and expiry notice using the provided executor (transaction or db)
+func (r *PostgresHoldRepository) UpdateWithExecutor(ctx context.Context, exec domain.Executor, hold *domain.Hold) error {
+	query := `
+		UPDATE holds
+		SET status = $2, booking_id = $3, expiry_notified_at = $4
+		WHERE id = $1
+	`
+
+	result, err := exec.ExecContext(ctx, query, hold.ID, hold.Status, nullUUID(hold.BookingID), nullTime(hold.ExpiryNotifiedAt))
+	if err != nil {
+		return fmt.Errorf("failed to update hold: %w", ClassifyDBError(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrHoldNotFound
+	}
+
+	return nil
+}
+
+// FindExpiredWithLock locks active holds whose expiry has passed, oldest first
+// SKIP LOCKED lets concurrent sweepers and confirmations proceed without waiting on each other
+func (r *PostgresHoldRepository) FindExpiredWithLock(ctx context.Context, exec domain.Executor, now time.Time, limit int) ([]*domain.Hold, error) {
+	query := `
+		SELECT ` + holdColumns + `
+		FROM holds
+		WHERE status = $1 AND expires_at <= $2
+		ORDER BY expires_at ASC
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED
+	`
+
+	rows, err := exec.QueryContext(ctx, query, domain.HoldStatusActive, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expired holds: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	holds, err := scanHolds(rows)
+	if err != nil {
+		return nil, fmt.Errorf("error iterating expired holds: %w", err)
+	}
+
+	return holds, nil
+}
+
+// FindExpiringWithLock locks active holds expiring after now but within lead, whose users were not yet notified
+func (r *PostgresHoldRepository) FindExpiringWithLock(ctx context.Context, exec domain.Executor, now time.Time, lead time.Duration, limit int) ([]*domain.Hold, error) {
+	query := `
+		SELECT ` + holdColumns + `
+		FROM holds
+		WHERE status = $1 AND expiry_notified_at IS NULL AND expires_at > $2 AND expires_at <= $3
+		ORDER BY expires_at ASC
+		LIMIT $4
+		FOR UPDATE SKIP LOCKED
+	`
+
+	rows, err := exec.QueryContext(ctx, query, domain.HoldStatusActive, now, now.Add(lead), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expiring holds: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	holds, err := scanHolds(rows)
+	if err != nil {
+		return nil, fmt.Errorf("error iterating expiring holds: %w", err)
+	}
+
+	return holds, nil
+}
+
+func scanHolds(rows *sql.Rows) ([]*domain.Hold, error) {
+	var holds []*domain.Hold
+	for rows.Next() {
+		hold, err := scanHold(rows)
+		if err != nil {
+			return nil, ClassifyDBError(err)
+		}
+		holds = append(holds, hold)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, ClassifyDBError(err)
+	}
+
+	return holds, nil
+}
+
+func scanHold(row rowScanner) (*domain.Hold, error) {
+	hold := &domain.Hold{}
+	var bookingID uuid.NullUUID
+	var expiryNotifiedAt sql.NullTime
+	var paymentID sql.NullString
+	err := row.Scan(
+		&hold.ID,
+		&hold.EventID,
+		&hold.UserID,
+		&hold.Tickets,
+		&hold.Status,
+		&hold.ExpiresAt,
+		&hold.CreatedAt,
+		&bookingID,
+		&expiryNotifiedAt,
+		&hold.DepositCents,
+		&paymentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	hold.BookingID = bookingID.UUID
+	hold.ExpiryNotifiedAt = expiryNotifiedAt.Time
+	hold.PaymentID = paymentID.String
+	return hold, nil
+}
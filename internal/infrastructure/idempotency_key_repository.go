@@ -0,0 +1,135 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+)
+
+const idempotencyKeyColumns = "key, booking_id, created_at, expires_at"
+
+type PostgresIdempotencyKeyRepository struct {
+	db DBClient
+}
+
+func NewPostgresIdempotencyKeyRepository(db DBClient) *PostgresIdempotencyKeyRepository {
+	return &PostgresIdempotencyKeyRepository{db: db}
+}
+
+// ClaimWithExecutor inserts the key, or takes over an existing row whose replay window has passed,
+// and reports whether either happened; an unexpired row is left untouched
+// A concurrent claim of the same key waits on the primary key until the first transaction finishes,
+// so only one of them ever sees a row written
+func (r *PostgresIdempotencyKeyRepository) ClaimWithExecutor(ctx context.Context, exec domain.Executor, key *domain.IdempotencyKey) (bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (` + idempotencyKeyColumns + `)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key) DO UPDATE
+		SET booking_id = EXCLUDED.booking_id, created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+	`
+
+	result, err := exec.ExecContext(ctx, query, key.Key, nullUUID(key.BookingID), key.CreatedAt, key.ExpiresAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim idempotency key: %w", ClassifyDBError(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *PostgresIdempotencyKeyRepository) FindByKeyWithExecutor(ctx context.Context, exec domain.Executor, key string) (*domain.IdempotencyKey, error) {
+	query := `
+		SELECT ` + idempotencyKeyColumns + `
+		FROM idempotency_keys
+		WHERE key = $1
+	`
+
+	idempotencyKey, err := scanIdempotencyKey(exec.QueryRowContext(ctx, query, key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrIdempotencyKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find idempotency key: %w", ClassifyDBError(err))
+	}
+
+	return idempotencyKey, nil
+}
+
+// SetBookingWithExecutor records the booking produced by the request that claimed the key
+func (r *PostgresIdempotencyKeyRepository) SetBookingWithExecutor(ctx context.Context, exec domain.Executor, key string, bookingID uuid.UUID) error {
+	query := `
+		UPDATE idempotency_keys
+		SET booking_id = $2
+		WHERE key = $1
+	`
+
+	result, err := exec.ExecContext(ctx, query, key, bookingID)
+	if err != nil {
+		return fmt.Errorf("failed to update idempotency key: %w", ClassifyDBError(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrIdempotencyKeyNotFound
+	}
+
+	return nil
+}
+
+// DeleteExpired removes a batch of expired keys; SKIP LOCKED keeps it from waiting on in-flight requests
+func (r *PostgresIdempotencyKeyRepository) DeleteExpired(ctx context.Context, now time.Time, limit int) (int, error) {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE key IN (
+			SELECT key
+			FROM idempotency_keys
+			WHERE expires_at <= $1
+			ORDER BY expires_at ASC
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, now, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", ClassifyDBError(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+func scanIdempotencyKey(row rowScanner) (*domain.IdempotencyKey, error) {
+	key := &domain.IdempotencyKey{}
+	var bookingID uuid.NullUUID
+	err := row.Scan(
+		&key.Key,
+		&bookingID,
+		&key.CreatedAt,
+		&key.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	key.BookingID = bookingID.UUID
+	return key, nil
+}
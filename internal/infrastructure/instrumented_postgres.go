@@ -9,14 +9,58 @@ import (
 	"github.com/jorzel/booking-service/internal/domain"
 )
 
+// DefaultSlowTransactionThreshold is how long a transaction may take before it is logged as slow
+const DefaultSlowTransactionThreshold = time.Second
+
 // InstrumentedPostgresClient wraps sql.DB and tracks query metrics
 type InstrumentedPostgresClient struct {
 	*sql.DB
+	slowTxThreshold time.Duration
+}
+
+type InstrumentedClientOption func(*InstrumentedPostgresClient)
+
+// WithSlowTransactionThreshold sets the duration above which transactions are logged as slow; 0 disables the log
+func WithSlowTransactionThreshold(threshold time.Duration) InstrumentedClientOption {
+	return func(c *InstrumentedPostgresClient) {
+		c.slowTxThreshold = threshold
+	}
 }
 
 // NewInstrumentedPostgresClient creates a new instrumented postgres client
-func NewInstrumentedPostgresClient(db *sql.DB) *InstrumentedPostgresClient {
-	return &InstrumentedPostgresClient{DB: db}
+func NewInstrumentedPostgresClient(db *sql.DB, opts ...InstrumentedClientOption) *InstrumentedPostgresClient {
+	c := &InstrumentedPostgresClient{DB: db, slowTxThreshold: DefaultSlowTransactionThreshold}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// SlowTransactionThreshold is the duration above which the client's transactions are logged as slow
+func (c *InstrumentedPostgresClient) SlowTransactionThreshold() time.Duration {
+	return c.slowTxThreshold
+}
+
+// SlowTransactionThreshold returns the slow transaction threshold db was configured with, looking through
+// the clients wrapping it; a client without one uses DefaultSlowTransactionThreshold
+func SlowTransactionThreshold(db DBClient) time.Duration {
+	if c, ok := db.(interface{ SlowTransactionThreshold() time.Duration }); ok {
+		return c.SlowTransactionThreshold()
+	}
+	return DefaultSlowTransactionThreshold
+}
+
+type skipInstrumentationKey struct{}
+
+// WithoutInstrumentation marks queries run with ctx as operational, e.g. health checks,
+// so they are left out of the query metrics that describe business traffic
+func WithoutInstrumentation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipInstrumentationKey{}, true)
+}
+
+func instrumentationDisabled(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipInstrumentationKey{}).(bool)
+	return skip
 }
 
 // InstrumentedTx wraps sql.Tx and tracks query metrics
@@ -26,6 +70,10 @@ type InstrumentedTx struct {
 
 // ExecContext wraps the standard ExecContext with instrumentation
 func (c *InstrumentedPostgresClient) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if instrumentationDisabled(ctx) {
+		return c.DB.ExecContext(ctx, query, args...)
+	}
+
 	operation := extractOperation(query)
 	start := time.Now()
 
@@ -39,12 +87,17 @@ func (c *InstrumentedPostgresClient) ExecContext(ctx context.Context, query stri
 		status = "error"
 	}
 	PostgresQueriesTotal.WithLabelValues(operation, status).Inc()
+	observeRowsAffected(operation, result, err)
 
 	return result, err
 }
 
 // QueryContext wraps the standard QueryContext with instrumentation
 func (c *InstrumentedPostgresClient) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if instrumentationDisabled(ctx) {
+		return c.DB.QueryContext(ctx, query, args...)
+	}
+
 	operation := extractOperation(query)
 	start := time.Now()
 
@@ -64,6 +117,10 @@ func (c *InstrumentedPostgresClient) QueryContext(ctx context.Context, query str
 
 // QueryRowContext wraps the standard QueryRowContext with instrumentation
 func (c *InstrumentedPostgresClient) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if instrumentationDisabled(ctx) {
+		return c.DB.QueryRowContext(ctx, query, args...)
+	}
+
 	operation := extractOperation(query)
 	start := time.Now()
 
@@ -90,6 +147,11 @@ func (c *InstrumentedPostgresClient) PingContext(ctx context.Context) error {
 	return c.DB.PingContext(ctx)
 }
 
+// HealthCheck reports latency, server version and pool statistics
+func (c *InstrumentedPostgresClient) HealthCheck(ctx context.Context) (HealthInfo, error) {
+	return checkHealth(ctx, c)
+}
+
 // Close wraps the standard Close
 func (c *InstrumentedPostgresClient) Close() error {
 	return c.DB.Close()
@@ -97,6 +159,10 @@ func (c *InstrumentedPostgresClient) Close() error {
 
 // ExecContext wraps the transaction's ExecContext with instrumentation
 func (tx *InstrumentedTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if instrumentationDisabled(ctx) {
+		return tx.Tx.ExecContext(ctx, query, args...)
+	}
+
 	operation := extractOperation(query)
 	start := time.Now()
 
@@ -110,12 +176,17 @@ func (tx *InstrumentedTx) ExecContext(ctx context.Context, query string, args ..
 		status = "error"
 	}
 	PostgresQueriesTotal.WithLabelValues(operation, status).Inc()
+	observeRowsAffected(operation, result, err)
 
 	return result, err
 }
 
 // QueryContext wraps the transaction's QueryContext with instrumentation
 func (tx *InstrumentedTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if instrumentationDisabled(ctx) {
+		return tx.Tx.QueryContext(ctx, query, args...)
+	}
+
 	operation := extractOperation(query)
 	start := time.Now()
 
@@ -135,6 +206,10 @@ func (tx *InstrumentedTx) QueryContext(ctx context.Context, query string, args .
 
 // QueryRowContext wraps the transaction's QueryRowContext with instrumentation
 func (tx *InstrumentedTx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if instrumentationDisabled(ctx) {
+		return tx.Tx.QueryRowContext(ctx, query, args...)
+	}
+
 	operation := extractOperation(query)
 	start := time.Now()
 
@@ -147,6 +222,19 @@ func (tx *InstrumentedTx) QueryRowContext(ctx context.Context, query string, arg
 	return row
 }
 
+// observeRowsAffected records the size of a successful write
+// Query result sizes are not observed: Executor hands out *sql.Rows, which cannot be wrapped to count scans
+func observeRowsAffected(operation string, result sql.Result, err error) {
+	if err != nil {
+		return
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+	PostgresRowsAffected.WithLabelValues(operation).Observe(float64(rows))
+}
+
 // extractOperation extracts the SQL operation type from a query string
 func extractOperation(query string) string {
 	// Trim whitespace and convert to uppercase
@@ -0,0 +1,144 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// rowsAffectedDriver is a database/sql driver whose statements report the number of affected rows
+// given as the data source name
+type rowsAffectedDriver struct{}
+
+func (rowsAffectedDriver) Open(dsn string) (driver.Conn, error) {
+	rows, err := strconv.ParseInt(dsn, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return rowsAffectedConn{rows: rows}, nil
+}
+
+type rowsAffectedConn struct{ rows int64 }
+
+func (c rowsAffectedConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+func (c rowsAffectedConn) Close() error              { return nil }
+func (c rowsAffectedConn) Begin() (driver.Tx, error) { return c, nil }
+func (c rowsAffectedConn) Commit() error             { return nil }
+func (c rowsAffectedConn) Rollback() error           { return nil }
+
+func (c rowsAffectedConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(c.rows), nil
+}
+
+var registerRowsAffectedDriver sync.Once
+
+// openRowsAffectedDB opens a database whose statements each affect rows rows
+// The driver is registered once per test binary, as database/sql panics when a name is registered twice
+func openRowsAffectedDB(t *testing.T, rows int64) *sql.DB {
+	t.Helper()
+	registerRowsAffectedDriver.Do(func() {
+		sql.Register("rows_affected", rowsAffectedDriver{})
+	})
+	db, err := sql.Open("rows_affected", strconv.FormatInt(rows, 10))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// rowsAffectedSnapshot is the rows affected histogram of one operation at a point in time
+type rowsAffectedSnapshot struct {
+	count   uint64
+	sum     float64
+	buckets map[float64]uint64
+}
+
+// snapshotRowsAffected reads the operation's rows affected histogram, so tests can assert what they added
+// to the process-wide metric regardless of other tests and -count
+func snapshotRowsAffected(t *testing.T, operation string) rowsAffectedSnapshot {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	snapshot := rowsAffectedSnapshot{buckets: map[float64]uint64{}}
+	for _, family := range families {
+		if family.GetName() != "booking_service_postgres_rows_affected" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() != "operation" || label.GetValue() != operation {
+					continue
+				}
+				histogram := metric.GetHistogram()
+				snapshot.count = histogram.GetSampleCount()
+				snapshot.sum = histogram.GetSampleSum()
+				for _, bucket := range histogram.GetBucket() {
+					snapshot.buckets[bucket.GetUpperBound()] = bucket.GetCumulativeCount()
+				}
+			}
+		}
+	}
+	return snapshot
+}
+
+func TestInstrumentedPostgresClient_ObservesRowsAffected(t *testing.T) {
+	client := NewInstrumentedPostgresClient(openRowsAffectedDB(t, 3))
+	ctx := context.Background()
+	before := snapshotRowsAffected(t, "UPDATE")
+
+	_, err := client.ExecContext(ctx, "UPDATE ticket_availability SET available_tickets = 0")
+	require.NoError(t, err)
+
+	tx, err := client.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	_, err = tx.ExecContext(ctx, "UPDATE ticket_availability SET available_tickets = 0")
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	after := snapshotRowsAffected(t, "UPDATE")
+	require.Equal(t, uint64(2), after.count-before.count)
+	require.Equal(t, 6.0, after.sum-before.sum)
+	for bound, added := range map[float64]uint64{0: 0, 1: 0, 10: 2, 100: 2, 100000: 2} {
+		require.Equal(t, added, after.buckets[bound]-before.buckets[bound], "bucket le=%v", bound)
+	}
+}
+
+func TestInstrumentedPostgresClient_WithoutInstrumentation(t *testing.T) {
+	client := NewInstrumentedPostgresClient(openRowsAffectedDB(t, 1))
+	ctx := context.Background()
+	deletes := PostgresQueriesTotal.WithLabelValues("DELETE", "success")
+	before := testutil.ToFloat64(deletes)
+
+	_, err := client.ExecContext(WithoutInstrumentation(ctx), "DELETE FROM holds")
+	require.NoError(t, err)
+	tx, err := client.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	_, err = tx.ExecContext(WithoutInstrumentation(ctx), "DELETE FROM holds")
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	require.Equal(t, before, testutil.ToFloat64(deletes), "flagged queries are not counted")
+
+	_, err = client.ExecContext(ctx, "DELETE FROM holds")
+	require.NoError(t, err)
+	require.Equal(t, before+1, testutil.ToFloat64(deletes), "regular queries are counted")
+}
+
+func TestSlowTransactionThreshold(t *testing.T) {
+	client := NewInstrumentedPostgresClient(nil, WithSlowTransactionThreshold(3*time.Second))
+	wrapped := NewRequestScopedDBClient(NewTransactionLimitedDBClient(client, 1))
+
+	require.Equal(t, 3*time.Second, SlowTransactionThreshold(wrapped), "wrapping clients report the client's threshold")
+	require.Equal(t, DefaultSlowTransactionThreshold, SlowTransactionThreshold(NewInstrumentedPostgresClient(nil)))
+	require.Equal(t, DefaultSlowTransactionThreshold, SlowTransactionThreshold(NewDBClientAdapter(nil)))
+}
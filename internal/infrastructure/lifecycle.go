@@ -0,0 +1,87 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// Component is a part of the process with a start and stop step; either may be nil
+// Start must not block: long-running work belongs in a goroutine that Stop waits for
+type Component struct {
+	Name  string
+	Start func(ctx context.Context) error
+	Stop  func(ctx context.Context) error
+}
+
+// Lifecycle starts components in registration order and stops them in reverse,
+// so anything registered later (workers, the HTTP server) is gone before what it depends on (the database)
+type Lifecycle struct {
+	mu         sync.Mutex
+	components []Component
+	started    int
+	logger     zerolog.Logger
+}
+
+func NewLifecycle(logger zerolog.Logger) *Lifecycle {
+	return &Lifecycle{logger: logger.With().Str("component", "lifecycle").Logger()}
+}
+
+// Register appends a component; it is started after and stopped before everything registered earlier
+func (l *Lifecycle) Register(component Component) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.components = append(l.components, component)
+}
+
+// Start starts every registered component that has not been started yet, in order
+// If one fails, the components already started are stopped in reverse and the start error is returned
+func (l *Lifecycle) Start(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for l.started < len(l.components) {
+		component := l.components[l.started]
+		if component.Start != nil {
+			if err := component.Start(ctx); err != nil {
+				l.logger.Error().Err(err).Str("name", component.Name).Msg("component failed to start")
+				return errors.Join(fmt.Errorf("failed to start %s: %w", component.Name, err), l.stop(ctx))
+			}
+		}
+		l.logger.Info().Str("name", component.Name).Msg("component started")
+		l.started++
+	}
+
+	return nil
+}
+
+// Stop stops the started components in reverse order
+// Every component gets its turn even when an earlier one fails; all failures are returned joined
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.stop(ctx)
+}
+
+func (l *Lifecycle) stop(ctx context.Context) error {
+	var errs []error
+	for ; l.started > 0; l.started-- {
+		component := l.components[l.started-1]
+		if component.Stop == nil {
+			continue
+		}
+		if err := component.Stop(ctx); err != nil {
+			l.logger.Error().Err(err).Str("name", component.Name).Msg("component failed to stop")
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", component.Name, err))
+			continue
+		}
+		l.logger.Info().Str("name", component.Name).Msg("component stopped")
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,85 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLifecycle_StopsInReverseOrder(t *testing.T) {
+	var calls []string
+	component := func(name string) Component {
+		return Component{
+			Name:  name,
+			Start: func(context.Context) error { calls = append(calls, "start "+name); return nil },
+			Stop:  func(context.Context) error { calls = append(calls, "stop "+name); return nil },
+		}
+	}
+
+	lifecycle := NewLifecycle(zerolog.Nop())
+	lifecycle.Register(component("database"))
+	lifecycle.Register(Component{Name: "metrics"}) // no start or stop steps
+	lifecycle.Register(component("hold_sweeper"))
+	lifecycle.Register(component("http_server"))
+
+	require.NoError(t, lifecycle.Start(context.Background()))
+	require.NoError(t, lifecycle.Stop(context.Background()))
+
+	assert.Equal(t, []string{
+		"start database", "start hold_sweeper", "start http_server",
+		"stop http_server", "stop hold_sweeper", "stop database",
+	}, calls)
+
+	require.NoError(t, lifecycle.Stop(context.Background()))
+	assert.Len(t, calls, 6, "stopping twice must not stop components again")
+}
+
+func TestLifecycle_StartFailureStopsStartedComponents(t *testing.T) {
+	var stopped []string
+	errBoom := errors.New("boom")
+
+	lifecycle := NewLifecycle(zerolog.Nop())
+	lifecycle.Register(Component{
+		Name: "database",
+		Stop: func(context.Context) error { stopped = append(stopped, "database"); return nil },
+	})
+	lifecycle.Register(Component{
+		Name: "hold_sweeper",
+		Stop: func(context.Context) error { stopped = append(stopped, "hold_sweeper"); return nil },
+	})
+	lifecycle.Register(Component{
+		Name:  "http_server",
+		Start: func(context.Context) error { return errBoom },
+		Stop:  func(context.Context) error { stopped = append(stopped, "http_server"); return nil },
+	})
+
+	err := lifecycle.Start(context.Background())
+
+	assert.ErrorIs(t, err, errBoom)
+	assert.Equal(t, []string{"hold_sweeper", "database"}, stopped, "the failed component is never stopped")
+}
+
+func TestLifecycle_StopContinuesPastFailures(t *testing.T) {
+	var stopped []string
+	errBoom := errors.New("boom")
+
+	lifecycle := NewLifecycle(zerolog.Nop())
+	lifecycle.Register(Component{
+		Name: "database",
+		Stop: func(context.Context) error { stopped = append(stopped, "database"); return nil },
+	})
+	lifecycle.Register(Component{
+		Name: "http_server",
+		Stop: func(context.Context) error { stopped = append(stopped, "http_server"); return errBoom },
+	})
+
+	require.NoError(t, lifecycle.Start(context.Background()))
+	err := lifecycle.Stop(context.Background())
+
+	assert.ErrorIs(t, err, errBoom)
+	assert.Equal(t, []string{"http_server", "database"}, stopped)
+}
@@ -0,0 +1,169 @@
+package infrastructure
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultEmailLogFields are the log fields masked as emails unless configured otherwise
+var DefaultEmailLogFields = []string{"email", "guest_email"}
+
+// PrivacyConfig chooses which log fields are masked and how
+// Email fields keep their first character and domain, e.g. "j***@example.com", so support can still tell
+// customers apart; hash fields are replaced by a keyed hash, which hides the value yet lets lines about the
+// same user be correlated. User IDs are logged as-is unless listed in HashFields
+// A field listed in both EmailFields and HashFields is hashed, as the hash reveals less of the value
+type PrivacyConfig struct {
+	EmailFields []string
+	HashFields  []string
+	HashKey     string
+}
+
+// DefaultPrivacyConfig masks emails and leaves every other field, including user IDs, unchanged
+func DefaultPrivacyConfig() PrivacyConfig {
+	return PrivacyConfig{EmailFields: DefaultEmailLogFields}
+}
+
+// ParseLogFields splits a comma-separated list of log field names, skipping blanks
+func ParseLogFields(raw string) []string {
+	var fields []string
+	for _, field := range strings.Split(raw, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// MaskingWriter masks the configured fields of the JSON log lines written through it, so every logger
+// sharing the writer, in services and handlers alike, is masked the same way without call sites opting in
+// Only top-level string fields, or arrays of strings, are masked; lines that are not JSON objects pass through
+type MaskingWriter struct {
+	w       io.Writer
+	masks   map[string]func(string) string
+	markers [][]byte
+}
+
+// NewMaskingWriter masks the fields config lists in the lines written through it to w
+func NewMaskingWriter(w io.Writer, config PrivacyConfig) *MaskingWriter {
+	mw := &MaskingWriter{w: w, masks: make(map[string]func(string) string)}
+	for _, field := range config.EmailFields {
+		mw.masks[field] = MaskEmail
+	}
+	key := []byte(config.HashKey)
+	// Set after the email masks, so a field listed in both is hashed
+	for _, field := range config.HashFields {
+		mw.masks[field] = func(value string) string { return hashLogValue(key, value) }
+	}
+	for field := range mw.masks {
+		marker, _ := json.Marshal(field)
+		mw.markers = append(mw.markers, marker)
+	}
+	return mw
+}
+
+// Write masks p, which zerolog guarantees is one whole log line, and reports len(p) as written
+func (mw *MaskingWriter) Write(p []byte) (int, error) {
+	if !mw.mayContainMaskedField(p) {
+		return mw.w.Write(p)
+	}
+
+	masked, ok := mw.mask(p)
+	if !ok {
+		return mw.w.Write(p)
+	}
+	if _, err := mw.w.Write(masked); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// mayContainMaskedField skips decoding lines that cannot hold any masked field, which is most of them
+func (mw *MaskingWriter) mayContainMaskedField(p []byte) bool {
+	for _, marker := range mw.markers {
+		if bytes.Contains(p, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// mask rewrites the line's masked fields, keeping the fields in their original order
+func (mw *MaskingWriter) mask(p []byte) ([]byte, bool) {
+	dec := json.NewDecoder(bytes.NewReader(p))
+	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
+		return nil, false
+	}
+
+	var out bytes.Buffer
+	out.WriteByte('{')
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, false
+		}
+		key, _ := token.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, false
+		}
+		if mask, ok := mw.masks[key]; ok {
+			value = maskLogValue(value, mask)
+		}
+
+		if out.Len() > 1 {
+			out.WriteByte(',')
+		}
+		encodedKey, _ := json.Marshal(key)
+		out.Write(encodedKey)
+		out.WriteByte(':')
+		out.Write(value)
+	}
+	out.WriteByte('}')
+	if bytes.HasSuffix(p, []byte("\n")) {
+		out.WriteByte('\n')
+	}
+	return out.Bytes(), true
+}
+
+// maskLogValue masks a string, or each string of an array; other values are left alone
+func maskLogValue(value json.RawMessage, mask func(string) string) json.RawMessage {
+	var single string
+	if err := json.Unmarshal(value, &single); err == nil {
+		masked, _ := json.Marshal(mask(single))
+		return masked
+	}
+	var many []string
+	if err := json.Unmarshal(value, &many); err == nil {
+		for i := range many {
+			many[i] = mask(many[i])
+		}
+		masked, _ := json.Marshal(many)
+		return masked
+	}
+	return value
+}
+
+// MaskEmail keeps an email's first character and domain, e.g. "jane@example.com" becomes "j***@example.com"
+// Values that are not emails are masked entirely
+func MaskEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" {
+		return "***"
+	}
+	first, _ := utf8.DecodeRuneInString(local)
+	return string(first) + "***@" + domain
+}
+
+// hashLogValue replaces value with the first 16 hex digits of its HMAC-SHA256 under key
+func hashLogValue(key []byte, value string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))[:16]
+}
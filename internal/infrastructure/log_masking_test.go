@@ -0,0 +1,78 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskingWriter(t *testing.T) {
+	userID := uuid.New()
+
+	logLine := func(t *testing.T, config PrivacyConfig, log func(zerolog.Logger)) (string, map[string]any) {
+		t.Helper()
+		var buf bytes.Buffer
+		log(zerolog.New(NewMaskingWriter(&buf, config)).With().Str("service", "booking").Logger())
+
+		var fields map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &fields), buf.String())
+		return buf.String(), fields
+	}
+
+	t.Run("masks emails and keeps user IDs by default", func(t *testing.T) {
+		line, fields := logLine(t, DefaultPrivacyConfig(), func(logger zerolog.Logger) {
+			logger.Info().Str("user_id", userID.String()).Str("email", "jane.doe@example.com").Msg("booking created")
+		})
+
+		assert.Equal(t, "j***@example.com", fields["email"])
+		assert.Equal(t, userID.String(), fields["user_id"])
+		assert.NotContains(t, line, "jane.doe")
+		assert.Regexp(t, `^\{"level":"info","service":"booking","user_id":"[^"]+","email":"[^"]+","message":"booking created"\}\n$`, line,
+			"fields keep their order")
+	})
+
+	t.Run("hashes configured fields consistently", func(t *testing.T) {
+		config := PrivacyConfig{HashFields: []string{"user_id"}, HashKey: "secret"}
+		log := func(logger zerolog.Logger) { logger.Info().Str("user_id", userID.String()).Msg("booking created") }
+		_, first := logLine(t, config, log)
+		_, second := logLine(t, config, log)
+
+		assert.NotEqual(t, userID.String(), first["user_id"])
+		assert.Len(t, first["user_id"], 16)
+		assert.Equal(t, first["user_id"], second["user_id"], "lines about one user can still be correlated")
+	})
+
+	t.Run("hashes a field that is also an email field", func(t *testing.T) {
+		config := PrivacyConfig{EmailFields: []string{"email"}, HashFields: []string{"email"}, HashKey: "secret"}
+		line, fields := logLine(t, config, func(logger zerolog.Logger) {
+			logger.Info().Str("email", "jane.doe@example.com").Msg("booking created")
+		})
+
+		assert.Len(t, fields["email"], 16)
+		assert.NotContains(t, line, "example.com", "the email mask would keep the domain")
+	})
+
+	t.Run("masks each email of a list", func(t *testing.T) {
+		_, fields := logLine(t, DefaultPrivacyConfig(), func(logger zerolog.Logger) {
+			logger.Warn().Strs("guest_email", []string{"ann@example.com", "not-an-email"}).Msg("guests invited")
+		})
+		assert.Equal(t, []any{"a***@example.com", "***"}, fields["guest_email"])
+	})
+
+	t.Run("passes lines without masked fields through unchanged", func(t *testing.T) {
+		var buf bytes.Buffer
+		writer := NewMaskingWriter(&buf, DefaultPrivacyConfig())
+		for _, line := range []string{`{"level":"info","emails_sent":3}` + "\n", "not json email\n"} {
+			buf.Reset()
+			n, err := writer.Write([]byte(line))
+			require.NoError(t, err)
+			assert.Equal(t, len(line), n)
+			assert.Equal(t, line, buf.String())
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package infrastructure
+
+import (
+	"context"
+
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/rs/zerolog"
+)
+
+// LogNotifier writes notifications to the log instead of delivering them
+// It stands in for a real channel (email, push) until one is wired up
+type LogNotifier struct {
+	logger zerolog.Logger
+}
+
+func NewLogNotifier(logger zerolog.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger.With().Str("component", "notifier").Logger()}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	event := n.logger.Info().
+		Str("kind", string(notification.Kind)).
+		Str("user_id", notification.UserID.String())
+	for key, value := range notification.Attributes {
+		event = event.Str(key, value)
+	}
+	event.Msg(notification.Message)
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/lib/pq"
+)
+
+const lotteryEntryColumns = "id, event_id, user_id, tickets, status, registered_at, booking_id"
+
+type PostgresLotteryRepository struct {
+	db DBClient
+}
+
+func NewPostgresLotteryRepository(db DBClient) *PostgresLotteryRepository {
+	return &PostgresLotteryRepository{db: db}
+}
+
+// CreateWithExecutor returns ErrLotteryEntryExists when the user is already registered for the event
+func (r *PostgresLotteryRepository) CreateWithExecutor(ctx context.Context, exec domain.Executor, entry *domain.LotteryEntry) error {
+	query := `
+		INSERT INTO lottery_entries (` + lotteryEntryColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := exec.ExecContext(ctx, query,
+		entry.ID,
+		entry.EventID,
+		entry.UserID,
+		entry.Tickets,
+		entry.Status,
+		entry.RegisteredAt,
+		nullUUID(entry.BookingID),
+	)
+	if isUniqueViolation(err) {
+		return domain.ErrLotteryEntryExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create lottery entry: %w", ClassifyDBError(err))
+	}
+
+	return nil
+}
+
+// IsDrawnWithExecutor reports whether any of the event's entries was decided by a draw
+func (r *PostgresLotteryRepository) IsDrawnWithExecutor(ctx context.Context, exec domain.Executor, eventID uuid.UUID) (bool, error) {
+	var drawn bool
+	err := exec.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM lottery_entries WHERE event_id = $1 AND status <> $2)",
+		eventID, domain.LotteryEntryRegistered,
+	).Scan(&drawn)
+	if err != nil {
+		return false, fmt.Errorf("failed to check lottery draw: %w", ClassifyDBError(err))
+	}
+
+	return drawn, nil
+}
+
+// FindByEventWithLock locks the event's entries in registration order (FOR UPDATE)
+func (r *PostgresLotteryRepository) FindByEventWithLock(ctx context.Context, exec domain.Executor, eventID uuid.UUID) ([]*domain.LotteryEntry, error) {
+	query := `
+		SELECT ` + lotteryEntryColumns + `
+		FROM lottery_entries
+		WHERE event_id = $1
+		ORDER BY registered_at ASC, id ASC
+		FOR UPDATE
+	`
+
+	rows, err := exec.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lottery entries: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	var entries []*domain.LotteryEntry
+	for rows.Next() {
+		entry, err := scanLotteryEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan lottery entry: %w", ClassifyDBError(err))
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating lottery entries: %w", ClassifyDBError(err))
+	}
+
+	return entries, nil
+}
+
+// UpdateBatchWithExecutor writes the status and booking of every entry with a single statement
+func (r *PostgresLotteryRepository) UpdateBatchWithExecutor(ctx context.Context, exec domain.Executor, entries []*domain.LotteryEntry) error {
+	query := `
+		UPDATE lottery_entries le
+		SET status = v.status, booking_id = v.booking_id
+		FROM unnest($1::uuid[], $2::text[], $3::uuid[]) AS v(id, status, booking_id)
+		WHERE le.id = v.id
+	`
+
+	ids := make([]string, len(entries))
+	statuses := make([]string, len(entries))
+	bookingIDs := make([]uuid.NullUUID, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.ID.String()
+		statuses[i] = string(entry.Status)
+		bookingIDs[i] = nullUUID(entry.BookingID)
+	}
+
+	result, err := exec.ExecContext(ctx, query, pq.Array(ids), pq.Array(statuses), pq.Array(bookingIDs))
+	if err != nil {
+		return fmt.Errorf("failed to update lottery entries: %w", ClassifyDBError(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected != int64(len(entries)) {
+		return fmt.Errorf("updated %d of %d lottery entries", rowsAffected, len(entries))
+	}
+
+	return nil
+}
+
+func scanLotteryEntry(row rowScanner) (*domain.LotteryEntry, error) {
+	entry := &domain.LotteryEntry{}
+	var bookingID uuid.NullUUID
+	err := row.Scan(
+		&entry.ID,
+		&entry.EventID,
+		&entry.UserID,
+		&entry.Tickets,
+		&entry.Status,
+		&entry.RegisteredAt,
+		&bookingID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	entry.BookingID = bookingID.UUID
+
+	return entry, nil
+}
@@ -1,6 +1,8 @@
 package infrastructure
 
 import (
+	"strings"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -31,6 +33,36 @@ var (
 		[]string{"method", "path", "status"},
 	)
 
+	// BookingHTTPRequestDuration uses buckets clustered around the booking SLO thresholds (100ms, 250ms, 1s)
+	// Booking requests are observed here in addition to HTTPRequestDuration, adding one series
+	// per bucket for every method/path/status combination of the booking routes
+	BookingHTTPRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "booking_service_http_booking_request_duration_seconds",
+			Help:    "HTTP request duration in seconds for booking endpoints, with SLO-aligned buckets",
+			Buckets: []float64{.025, .05, .075, .1, .15, .2, .25, .35, .5, .75, 1, 2.5},
+		},
+		[]string{"method", "path", "status"},
+	)
+
+	// BookingsThrottled counts bookings turned away because their event was at its concurrency cap
+	BookingsThrottled = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "booking_service_bookings_throttled_total",
+			Help: "Total number of bookings rejected because too many bookings of the event were in progress",
+		},
+	)
+
+	// SoldOutRejections counts bookings and holds turned away because their event had too few tickets left,
+	// labelled with EventPopularityBucket so the label keeps a handful of values however many events exist
+	SoldOutRejections = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "booking_service_sold_out_rejections_total",
+			Help: "Total number of bookings and holds rejected for insufficient tickets, by event popularity bucket",
+		},
+		[]string{"event_popularity_bucket"},
+	)
+
 	TicketsBooked = promauto.NewCounter(
 		prometheus.CounterOpts{
 			Name: "booking_service_tickets_booked_total",
@@ -38,6 +70,23 @@ var (
 		},
 	)
 
+	// TimeToSellout is observed once each time a reservation takes an event's last tickets
+	TimeToSellout = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "booking_service_time_to_sellout_seconds",
+			Help:    "Time from event creation until its last tickets were reserved, in seconds",
+			Buckets: []float64{60, 600, 3600, 6 * 3600, 24 * 3600, 3 * 24 * 3600, 7 * 24 * 3600, 30 * 24 * 3600, 90 * 24 * 3600},
+		},
+	)
+
+	TransactionsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "booking_service_transactions_total",
+			Help: "Total number of database transactions by outcome (committed or rolledback)",
+		},
+		[]string{"operation", "outcome"},
+	)
+
 	PostgresQueriesTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "booking_service_postgres_queries_total",
@@ -46,6 +95,39 @@ var (
 		[]string{"operation", "status"},
 	)
 
+	// DBCircuitBreakerState is 0 when closed, 1 when half-open and 2 when open
+	DBCircuitBreakerState = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "booking_service_db_circuit_breaker_state",
+			Help: "Database circuit breaker state (0 closed, 1 half-open, 2 open)",
+		},
+	)
+
+	// DBTransactionsRejected counts transactions refused because the concurrent transaction limit was reached
+	DBTransactionsRejected = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "booking_service_db_transactions_rejected_total",
+			Help: "Total number of database transactions rejected because too many were open",
+		},
+	)
+
+	// SchemaDrift is 1 while the schema no longer matches what the migrations create, e.g. after manual DDL
+	SchemaDrift = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "booking_service_schema_drift",
+			Help: "Whether the database schema drifted from the migrations (0 matches, 1 drifted)",
+		},
+	)
+
+	// AvailabilityReconciled counts events checked by availability reconciliation, by whether they were corrected
+	AvailabilityReconciled = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "booking_service_availability_reconciled_total",
+			Help: "Events checked by availability reconciliation, by result (corrected or consistent)",
+		},
+		[]string{"result"},
+	)
+
 	PostgresQueryDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    "booking_service_postgres_query_duration_seconds",
@@ -54,4 +136,58 @@ var (
 		},
 		[]string{"operation"},
 	)
+
+	// PostgresRowsAffected makes unexpectedly large writes, such as an UPDATE missing its WHERE, stand out
+	PostgresRowsAffected = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "booking_service_postgres_rows_affected",
+			Help:    "Rows affected by successful Postgres statements",
+			Buckets: []float64{0, 1, 10, 100, 1000, 10000, 100000},
+		},
+		[]string{"operation"},
+	)
+
+	WebhookDeliveries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "booking_service_webhook_deliveries_total",
+			Help: "Total number of webhook deliveries by outcome (delivered or failed, after retries)",
+		},
+		[]string{"status"},
+	)
 )
+
+// EventPopularityBucket buckets an event by its ticket count, the measure of demand known when booking:
+// "small" up to 100 tickets, "medium" up to 1000, "large" up to 10000 and "stadium" beyond
+func EventPopularityBucket(tickets int) string {
+	switch {
+	case tickets <= 100:
+		return "small"
+	case tickets <= 1000:
+		return "medium"
+	case tickets <= 10000:
+		return "large"
+	default:
+		return "stadium"
+	}
+}
+
+// httpDurationGroups maps route prefixes to histograms with group-specific buckets
+// Each group is a separate metric family because a histogram's buckets are fixed per family;
+// add a group only for endpoints that have their own latency SLO
+var httpDurationGroups = []struct {
+	prefix    string
+	histogram *prometheus.HistogramVec
+}{
+	{prefix: "/bookings", histogram: BookingHTTPRequestDuration},
+}
+
+// HTTPRequestDurationGroup returns the group-specific histogram for a route path, or nil when
+// the route is only tracked by HTTPRequestDuration
+func HTTPRequestDurationGroup(path string) *prometheus.HistogramVec {
+	for _, group := range httpDurationGroups {
+		if path == group.prefix || strings.HasPrefix(path, group.prefix+"/") {
+			return group.histogram
+		}
+	}
+	return nil
+}
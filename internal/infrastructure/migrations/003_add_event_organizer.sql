@@ -0,0 +1,6 @@
+-- Track the organizer owning each event
+-- Nullable because events created before organizers were introduced have no owner
+ALTER TABLE events ADD COLUMN IF NOT EXISTS organizer_id UUID;
+
+-- Create index for organizer dashboards
+CREATE INDEX IF NOT EXISTS idx_events_organizer_id ON events(organizer_id);
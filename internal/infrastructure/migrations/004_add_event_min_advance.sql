@@ -0,0 +1,6 @@
+-- Minimum time before the event start after which bookings are no longer accepted
+-- Stored in seconds; 0 keeps bookings open until the event starts
+ALTER TABLE events ADD COLUMN IF NOT EXISTS min_advance_seconds BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE events DROP CONSTRAINT IF EXISTS min_advance_seconds_non_negative;
+ALTER TABLE events ADD CONSTRAINT min_advance_seconds_non_negative CHECK (min_advance_seconds >= 0);
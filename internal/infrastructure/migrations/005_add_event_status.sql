@@ -0,0 +1,6 @@
+-- Track event lifecycle so cancelled events stop accepting bookings
+ALTER TABLE events ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMP;
+
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_status_valid;
+ALTER TABLE events ADD CONSTRAINT events_status_valid CHECK (status IN ('active', 'cancelled'));
@@ -0,0 +1,16 @@
+-- Holds reserve tickets for a limited time until they are confirmed into a booking
+CREATE TABLE IF NOT EXISTS holds (
+    id UUID PRIMARY KEY,
+    event_id UUID NOT NULL REFERENCES events(id),
+    user_id UUID NOT NULL,
+    tickets INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    booking_id UUID REFERENCES bookings(id),
+    CONSTRAINT holds_tickets_positive CHECK (tickets > 0),
+    CONSTRAINT holds_status_valid CHECK (status IN ('active', 'confirmed', 'released'))
+);
+
+-- Supports the sweeper that releases expired holds
+CREATE INDEX IF NOT EXISTS idx_holds_active_expires_at ON holds(expires_at) WHERE status = 'active';
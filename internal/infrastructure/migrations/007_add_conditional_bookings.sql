@@ -0,0 +1,16 @@
+-- Events may require a minimum number of tickets sold by a deadline to run
+ALTER TABLE events ADD COLUMN IF NOT EXISTS min_viable INT NOT NULL DEFAULT 0;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS viability_deadline TIMESTAMP;
+
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_min_viable_valid;
+ALTER TABLE events ADD CONSTRAINT events_min_viable_valid
+    CHECK (min_viable >= 0 AND min_viable <= tickets AND (min_viable = 0 OR viability_deadline IS NOT NULL));
+
+-- Conditional bookings stay pending until the event's minimum is resolved
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'confirmed';
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS conditional BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS bookings_status_valid;
+ALTER TABLE bookings ADD CONSTRAINT bookings_status_valid CHECK (status IN ('confirmed', 'pending', 'cancelled'));
+
+CREATE INDEX IF NOT EXISTS idx_bookings_event_id_pending ON bookings(event_id) WHERE status = 'pending';
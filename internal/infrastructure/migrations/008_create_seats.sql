@@ -0,0 +1,14 @@
+-- Reserved-seating events sell specific seats instead of general admission tickets
+ALTER TABLE events ADD COLUMN IF NOT EXISTS seated BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS seats (
+    event_id UUID NOT NULL REFERENCES events(id),
+    seat_label VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'free',
+    booking_id UUID REFERENCES bookings(id),
+    PRIMARY KEY (event_id, seat_label),
+    CONSTRAINT seats_status_valid CHECK (status IN ('free', 'reserved')),
+    CONSTRAINT seats_reserved_has_booking CHECK ((status = 'reserved') = (booking_id IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_seats_booking_id ON seats(booking_id) WHERE booking_id IS NOT NULL;
@@ -0,0 +1,11 @@
+-- Idempotency keys let clients retry booking requests without creating duplicates, also across restarts
+-- The primary key elects a single winner among concurrent requests carrying the same new key
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key VARCHAR(255) PRIMARY KEY,
+    booking_id UUID REFERENCES bookings(id),
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+
+-- Supports the cleanup job that deletes expired keys
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
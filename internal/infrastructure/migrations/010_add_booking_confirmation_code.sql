@@ -0,0 +1,10 @@
+-- Confirmation codes are short upper-case codes customers quote to support
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS confirmation_code VARCHAR(16);
+
+-- Existing bookings get a 16-character hex code derived from their ID; new codes are 8 characters
+UPDATE bookings SET confirmation_code = upper(substr(md5(id::text), 1, 16)) WHERE confirmation_code IS NULL;
+
+ALTER TABLE bookings ALTER COLUMN confirmation_code SET NOT NULL;
+
+-- text_pattern_ops lets LIKE 'ABC%' prefix searches use the index regardless of the database collation
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookings_confirmation_code ON bookings(confirmation_code text_pattern_ops);
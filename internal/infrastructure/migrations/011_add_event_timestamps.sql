@@ -0,0 +1,6 @@
+-- Track when events were created and last changed, so listings can answer conditional GETs
+ALTER TABLE events ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE events ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- Lets MAX(updated_at) read the last index entry instead of scanning the table
+CREATE INDEX IF NOT EXISTS idx_events_updated_at ON events(updated_at);
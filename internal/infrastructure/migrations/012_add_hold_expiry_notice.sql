@@ -0,0 +1,5 @@
+-- Set once the user has been told the hold is about to expire, so the notice is sent once
+ALTER TABLE holds ADD COLUMN IF NOT EXISTS expiry_notified_at TIMESTAMP;
+
+-- Supports the sweeper that looks for active holds nearing expiry without a notice
+CREATE INDEX IF NOT EXISTS idx_holds_expiry_notice ON holds(expires_at) WHERE status = 'active' AND expiry_notified_at IS NULL;
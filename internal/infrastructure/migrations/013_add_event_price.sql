@@ -0,0 +1,6 @@
+-- Ticket price in the currency's minor unit; existing events are free
+ALTER TABLE events ADD COLUMN IF NOT EXISTS price_cents BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT '';
+
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_price_non_negative;
+ALTER TABLE events ADD CONSTRAINT events_price_non_negative CHECK (price_cents >= 0);
@@ -0,0 +1,20 @@
+-- Discount codes redeemable on bookings; exactly one of percent_off and amount_off_cents is set
+CREATE TABLE IF NOT EXISTS discount_codes (
+    code VARCHAR(32) PRIMARY KEY,
+    percent_off INTEGER NOT NULL DEFAULT 0,
+    amount_off_cents BIGINT NOT NULL DEFAULT 0,
+    currency VARCHAR(3) NOT NULL DEFAULT '',
+    max_uses INTEGER NOT NULL,
+    uses INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT discount_codes_percent_range CHECK (percent_off BETWEEN 0 AND 100),
+    CONSTRAINT discount_codes_amount_non_negative CHECK (amount_off_cents >= 0),
+    CONSTRAINT discount_codes_one_discount CHECK ((percent_off > 0) <> (amount_off_cents > 0)),
+    CONSTRAINT discount_codes_uses_within_max CHECK (uses >= 0 AND uses <= max_uses)
+);
+
+-- Price charged per booking after any discount; existing bookings are recorded as free
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS price_cents BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT '';
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS discount_code VARCHAR(32) NOT NULL DEFAULT '';
@@ -0,0 +1,17 @@
+-- Append-only log of availability changes, written in the same statement as each availability write
+CREATE TABLE IF NOT EXISTS availability_changes (
+    id BIGSERIAL PRIMARY KEY,
+    event_id UUID NOT NULL REFERENCES events(id),
+    delta INTEGER NOT NULL,
+    reason VARCHAR(32) NOT NULL,
+    resulting_available INTEGER NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX IF NOT EXISTS idx_availability_changes_event_changed_at ON availability_changes(event_id, changed_at, id);
+
+-- Events created before the log existed start from their availability at migration time
+INSERT INTO availability_changes (event_id, delta, reason, resulting_available)
+SELECT ta.event_id, 0, 'baseline', ta.available_tickets
+FROM ticket_availability ta
+WHERE NOT EXISTS (SELECT 1 FROM availability_changes ac WHERE ac.event_id = ta.event_id);
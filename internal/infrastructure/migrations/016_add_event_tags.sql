@@ -0,0 +1,4 @@
+-- Categories such as music or theater; a GIN index serves both any-of (&&) and all-of (@>) filters
+ALTER TABLE events ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_events_tags ON events USING GIN (tags);
@@ -0,0 +1,2 @@
+-- Bumped by every availability write and exposed as an ETag, so admin corrections can use If-Match
+ALTER TABLE ticket_availability ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+-- Organizers pause sales of one event (e.g. after a pricing error) without cancelling it
+ALTER TABLE events ADD COLUMN IF NOT EXISTS bookings_paused BOOLEAN NOT NULL DEFAULT FALSE;
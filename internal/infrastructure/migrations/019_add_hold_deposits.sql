@@ -0,0 +1,3 @@
+-- Paid holds authorize a deposit when created; payment_id is the provider's authorization, NULL without a deposit
+ALTER TABLE holds ADD COLUMN IF NOT EXISTS deposit_cents BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE holds ADD COLUMN IF NOT EXISTS payment_id TEXT;
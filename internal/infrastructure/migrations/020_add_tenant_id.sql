@@ -0,0 +1,8 @@
+-- Row-level tenancy: every event and booking belongs to a tenant, '' being the default one
+-- Rows written before tenancy existed belong to the default tenant
+ALTER TABLE events ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT '';
+
+-- Listings page through one tenant's events by date, and Last-Modified reads one tenant's newest update
+CREATE INDEX IF NOT EXISTS idx_events_tenant_id_date ON events(tenant_id, date, id);
+CREATE INDEX IF NOT EXISTS idx_events_tenant_id_updated_at ON events(tenant_id, updated_at);
@@ -0,0 +1,3 @@
+-- URLs of an externally hosted poster image and its thumbnail; '' when the event has none
+ALTER TABLE events ADD COLUMN IF NOT EXISTS image_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS thumbnail_url TEXT NOT NULL DEFAULT '';
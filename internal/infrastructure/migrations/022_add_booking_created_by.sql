@@ -0,0 +1,2 @@
+-- Admin who created the booking on the user's behalf; '' for bookings users made themselves
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS created_by TEXT NOT NULL DEFAULT '';
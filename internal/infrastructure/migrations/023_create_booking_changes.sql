@@ -0,0 +1,18 @@
+-- Append-only log of booking changes, written in the same statement as each booking write
+CREATE TABLE IF NOT EXISTS booking_changes (
+    id BIGSERIAL PRIMARY KEY,
+    booking_id UUID NOT NULL REFERENCES bookings(id),
+    action VARCHAR(32) NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    tickets_booked INTEGER NOT NULL,
+    actor TEXT NOT NULL DEFAULT '',
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_changes_booking_changed_at ON booking_changes(booking_id, changed_at, id);
+
+-- Bookings made before the log existed start with their creation; later changes to them are unknown
+INSERT INTO booking_changes (booking_id, action, status, tickets_booked, actor, changed_at)
+SELECT b.id, 'created', CASE WHEN b.conditional THEN 'pending' ELSE 'confirmed' END, b.tickets_booked, b.created_by, b.booked_at
+FROM bookings b
+WHERE NOT EXISTS (SELECT 1 FROM booking_changes bc WHERE bc.booking_id = b.id);
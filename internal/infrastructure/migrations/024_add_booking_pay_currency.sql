@@ -0,0 +1,5 @@
+-- Price in the currency the user chose to pay in, converted from price_cents at fx_rate when booking;
+-- pay_currency is '' for bookings paid in the event's currency
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS pay_price_cents BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS pay_currency VARCHAR(3) NOT NULL DEFAULT '';
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS fx_rate DOUBLE PRECISION NOT NULL DEFAULT 0;
@@ -0,0 +1,10 @@
+-- Webhooks organizers register per event; every booking of the event is posted to each of them
+CREATE TABLE IF NOT EXISTS event_webhooks (
+    id UUID PRIMARY KEY,
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_webhooks_event_id ON event_webhooks(event_id);
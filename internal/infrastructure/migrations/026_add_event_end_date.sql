@@ -0,0 +1,2 @@
+-- When the event ends; NULL when only its start is known
+ALTER TABLE events ADD COLUMN IF NOT EXISTS end_date TIMESTAMP;
@@ -0,0 +1,14 @@
+-- Named blocks of an event's tickets set aside from public sale, e.g. a press allocation
+-- Tickets moved into a block leave ticket_availability, which stays the public block
+CREATE TABLE IF NOT EXISTS allocations (
+    id UUID PRIMARY KEY,
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name VARCHAR(32) NOT NULL,
+    tickets INTEGER NOT NULL CHECK (tickets > 0),
+    available_tickets INTEGER NOT NULL CHECK (available_tickets >= 0 AND available_tickets <= tickets),
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    UNIQUE (event_id, name)
+);
+
+-- Block the booking's tickets came from; NULL for bookings from public sale
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS allocation_id UUID REFERENCES allocations(id);
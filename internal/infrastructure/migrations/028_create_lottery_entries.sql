@@ -0,0 +1,12 @@
+-- Registrations for the lottery of an oversubscribed event; the draw turns winning entries into bookings
+CREATE TABLE IF NOT EXISTS lottery_entries (
+    id UUID PRIMARY KEY,
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    tickets INTEGER NOT NULL CHECK (tickets > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'registered',
+    registered_at TIMESTAMP NOT NULL,
+    booking_id UUID REFERENCES bookings(id),
+    CONSTRAINT lottery_entries_status_valid CHECK (status IN ('registered', 'won', 'lost')),
+    UNIQUE (event_id, user_id)
+);
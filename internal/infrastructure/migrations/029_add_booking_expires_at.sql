@@ -0,0 +1,4 @@
+-- Deadline to confirm a pending reservation; NULL for bookings that need no confirmation
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_pending_expires_at ON bookings(expires_at) WHERE status = 'pending' AND expires_at IS NOT NULL;
@@ -0,0 +1,3 @@
+-- Soft delete of events merged into a duplicate; merged events are hidden from every read
+ALTER TABLE events ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS merged_into UUID REFERENCES events(id);
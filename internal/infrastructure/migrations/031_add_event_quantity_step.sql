@@ -0,0 +1,5 @@
+-- Bookings of the event must be a multiple of quantity_step tickets, e.g. 4 for tables of four
+ALTER TABLE events ADD COLUMN IF NOT EXISTS quantity_step INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE events DROP CONSTRAINT IF EXISTS quantity_step_positive;
+ALTER TABLE events ADD CONSTRAINT quantity_step_positive CHECK (quantity_step >= 1);
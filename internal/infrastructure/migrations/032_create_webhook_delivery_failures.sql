@@ -0,0 +1,13 @@
+-- Dead letters: notifications a webhook did not accept after every retry, kept for inspection and replay
+-- Not tied to event_webhooks, so the record of a failure outlives the webhook being deleted
+CREATE TABLE IF NOT EXISTS webhook_delivery_failures (
+    id UUID PRIMARY KEY,
+    webhook_id UUID NOT NULL,
+    event_id UUID NOT NULL,
+    booking_id UUID NOT NULL,
+    type TEXT NOT NULL,
+    error TEXT NOT NULL,
+    failed_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_failures_webhook_id ON webhook_delivery_failures(webhook_id, failed_at);
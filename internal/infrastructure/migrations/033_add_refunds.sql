@@ -0,0 +1,7 @@
+-- An event's own refund tiers: refund_percents[i] of the price is refunded when a booking is cancelled more
+-- than refund_notice_seconds[i] before the event. NULL leaves refunds to the default cancellation policy
+ALTER TABLE events ADD COLUMN IF NOT EXISTS refund_notice_seconds BIGINT[];
+ALTER TABLE events ADD COLUMN IF NOT EXISTS refund_percents INTEGER[];
+
+-- What a cancelled booking refunded, in the booking's currency; 0 for bookings that are not cancelled
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS refund_cents BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- Lottery events sell their tickets only through their lottery draw, never through bookings or holds
+ALTER TABLE events ADD COLUMN IF NOT EXISTS lottery BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,7 @@
+-- Keeps what a dead letter notified, so operators can retry it, and how many deliveries of it failed
+-- Dead letters stored before have no payload and are listed but cannot be retried
+ALTER TABLE webhook_delivery_failures ADD COLUMN IF NOT EXISTS payload JSONB;
+ALTER TABLE webhook_delivery_failures ADD COLUMN IF NOT EXISTS occurred_at TIMESTAMP;
+ALTER TABLE webhook_delivery_failures ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 1;
+
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_failures_failed_at ON webhook_delivery_failures(failed_at DESC, id);
@@ -0,0 +1,121 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultPaymentGatewayTimeout bounds each call to the payment gateway
+const DefaultPaymentGatewayTimeout = 10 * time.Second
+
+// HTTPPaymentGateway authorizes and settles payments through a provider's REST API, authenticated with
+// the API key as a bearer token:
+// POST <base>/authorizations, POST <base>/authorizations/<id>/capture and POST <base>/authorizations/<id>/void
+// Calls are not retried; a failed capture or void is logged by the hold service for follow-up
+type HTTPPaymentGateway struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+}
+
+func NewHTTPPaymentGateway(baseURL, apiKey string) *HTTPPaymentGateway {
+	return &HTTPPaymentGateway{
+		client:  &http.Client{Timeout: DefaultPaymentGatewayTimeout},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+	}
+}
+
+type authorizationRequest struct {
+	UserID      string `json:"user_id"`
+	AmountCents int64  `json:"amount_cents"`
+	Currency    string `json:"currency"`
+}
+
+type authorizationResponse struct {
+	ID string `json:"id"`
+}
+
+func (g *HTTPPaymentGateway) Authorize(ctx context.Context, userID uuid.UUID, amountCents int64, currency string) (string, error) {
+	var authorization authorizationResponse
+	err := g.post(ctx, "/authorizations", authorizationRequest{
+		UserID:      userID.String(),
+		AmountCents: amountCents,
+		Currency:    currency,
+	}, &authorization)
+	if err != nil {
+		return "", fmt.Errorf("failed to authorize payment: %w", err)
+	}
+	if authorization.ID == "" {
+		return "", errors.New("failed to authorize payment: gateway returned no authorization id")
+	}
+	return authorization.ID, nil
+}
+
+func (g *HTTPPaymentGateway) Capture(ctx context.Context, txnID string) error {
+	if err := g.post(ctx, "/authorizations/"+url.PathEscape(txnID)+"/capture", nil, nil); err != nil {
+		return fmt.Errorf("failed to capture payment %s: %w", txnID, err)
+	}
+	return nil
+}
+
+func (g *HTTPPaymentGateway) Void(ctx context.Context, txnID string) error {
+	if err := g.post(ctx, "/authorizations/"+url.PathEscape(txnID)+"/void", nil, nil); err != nil {
+		return fmt.Errorf("failed to void payment %s: %w", txnID, err)
+	}
+	return nil
+}
+
+// Probe checks that the gateway answers, for readiness
+func (g *HTTPPaymentGateway) Probe() DependencyProbe {
+	return HTTPProbe(g.client, g.baseURL)
+}
+
+// post sends body, when not nil, as JSON and decodes a 2xx response into out, when not nil
+func (g *HTTPPaymentGateway) post(ctx context.Context, path string, body, out interface{}) error {
+	var payload io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.baseURL+path, payload)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if g.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+g.apiKey)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Drain the body so the connection can be reused
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return fmt.Errorf("gateway responded with status %d", resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(out)
+}
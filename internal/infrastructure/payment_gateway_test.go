@@ -0,0 +1,55 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPPaymentGateway(t *testing.T) {
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		assert.Equal(t, "Bearer sk_test", r.Header.Get("Authorization"))
+
+		switch r.URL.Path {
+		case "/v1/authorizations":
+			var req authorizationRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			if req.AmountCents > 10000 {
+				w.WriteHeader(http.StatusPaymentRequired)
+				return
+			}
+			json.NewEncoder(w).Encode(authorizationResponse{ID: "auth_1"})
+		case "/v1/authorizations/auth_1/capture", "/v1/authorizations/auth_1/void":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	gateway := NewHTTPPaymentGateway(server.URL+"/v1/", "sk_test")
+	ctx := context.Background()
+
+	txnID, err := gateway.Authorize(ctx, uuid.New(), 2500, "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, "auth_1", txnID)
+	require.NoError(t, gateway.Capture(ctx, txnID))
+	require.NoError(t, gateway.Void(ctx, txnID))
+	assert.Equal(t, []string{
+		"POST /v1/authorizations",
+		"POST /v1/authorizations/auth_1/capture",
+		"POST /v1/authorizations/auth_1/void",
+	}, calls)
+
+	_, err = gateway.Authorize(ctx, uuid.New(), 20000, "EUR")
+	assert.EqualError(t, err, "failed to authorize payment: gateway responded with status 402")
+	assert.EqualError(t, gateway.Capture(ctx, "auth_2"), "failed to capture payment auth_2: gateway responded with status 404")
+}
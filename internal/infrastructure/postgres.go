@@ -4,9 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"regexp"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type Config struct {
@@ -16,19 +17,76 @@ type Config struct {
 	Password string
 	Database string
 	SSLMode  string
+	// Schema, when set, is the connection's search_path, so unqualified table names resolve in it
+	// Multi-tenant deployments point each instance at a tenant's schema
+	Schema string
+	// StatementTimeout makes the server cancel any statement running longer, failing it with ErrQueryCanceled
+	// Zero leaves the server default, usually no timeout; see WithStatementTimeout for per-operation overrides
+	StatementTimeout time.Duration
 }
 
-func NewPostgresDB(cfg Config) (*sql.DB, error) {
+// schemaPattern accepts unquoted Postgres identifiers, which is all a search_path in the DSN may carry
+var schemaPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+// dsn builds the lib/pq connection string; lib/pq sends search_path and statement_timeout to the server
+// as startup parameters, so they apply to every pooled connection
+func (cfg Config) dsn() (string, error) {
 	dsn := fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Database, cfg.SSLMode,
 	)
+	if cfg.Schema != "" {
+		if !schemaPattern.MatchString(cfg.Schema) {
+			return "", fmt.Errorf("invalid schema %q: must be a lowercase identifier", cfg.Schema)
+		}
+		dsn += " search_path=" + cfg.Schema
+	}
+	if cfg.StatementTimeout < 0 {
+		return "", fmt.Errorf("invalid statement timeout %s: must not be negative", cfg.StatementTimeout)
+	}
+	if cfg.StatementTimeout > 0 {
+		dsn += fmt.Sprintf(" statement_timeout=%d", cfg.StatementTimeout.Milliseconds())
+	}
+	return dsn, nil
+}
+
+// PostgresOption configures optional connection behaviour
+type PostgresOption func(*postgresOptions)
+
+type postgresOptions struct {
+	breaker *CircuitBreaker
+}
+
+// WithCircuitBreaker fails new connection attempts fast while the breaker is open
+func WithCircuitBreaker(breaker *CircuitBreaker) PostgresOption {
+	return func(o *postgresOptions) {
+		o.breaker = breaker
+	}
+}
 
-	db, err := sql.Open("postgres", dsn)
+func NewPostgresDB(cfg Config, opts ...PostgresOption) (*sql.DB, error) {
+	options := &postgresOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	dsn, err := cfg.dsn()
+	if err != nil {
+		return nil, err
+	}
+
+	connector, err := pq.NewConnector(dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
+	var db *sql.DB
+	if options.breaker != nil {
+		db = sql.OpenDB(&breakerConnector{Connector: connector, breaker: options.breaker})
+	} else {
+		db = sql.OpenDB(connector)
+	}
+
 	db.SetMaxOpenConns(25)
 	db.SetMaxIdleConns(5)
 	db.SetConnMaxLifetime(5 * time.Minute)
@@ -0,0 +1,45 @@
+package infrastructure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigDSN_Schema(t *testing.T) {
+	cfg := Config{Host: "db", Port: 5432, User: "app", Password: "secret", Database: "booking", SSLMode: "disable"}
+
+	dsn, err := cfg.dsn()
+	require.NoError(t, err)
+	assert.NotContains(t, dsn, "search_path", "without a schema the server default applies")
+
+	cfg.Schema = "tenant_42"
+	dsn, err = cfg.dsn()
+	require.NoError(t, err)
+	assert.Contains(t, dsn, " search_path=tenant_42")
+
+	for _, schema := range []string{"Tenant", "tenant a", "tenant;drop", "1tenant", "tenant,public"} {
+		cfg.Schema = schema
+		_, err := cfg.dsn()
+		assert.Error(t, err, schema)
+	}
+}
+
+func TestConfigDSN_StatementTimeout(t *testing.T) {
+	cfg := Config{Host: "db", Port: 5432, User: "app", Password: "secret", Database: "booking", SSLMode: "disable"}
+
+	dsn, err := cfg.dsn()
+	require.NoError(t, err)
+	assert.NotContains(t, dsn, "statement_timeout", "without a timeout the server default applies")
+
+	cfg.StatementTimeout = 5 * time.Second
+	dsn, err = cfg.dsn()
+	require.NoError(t, err)
+	assert.Contains(t, dsn, " statement_timeout=5000")
+
+	cfg.StatementTimeout = -time.Second
+	_, err = cfg.dsn()
+	assert.Error(t, err)
+}
@@ -0,0 +1,44 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultReplicaMaxLag is how far a read replica may trail the primary before readiness is degraded
+const DefaultReplicaMaxLag = 30 * time.Second
+
+// ReplicationLagChecker reports how far a read replica trails the primary
+type ReplicationLagChecker interface {
+	ReplicationLag(ctx context.Context) (time.Duration, error)
+}
+
+// PostgresReplicationLag measures the lag of the Postgres replica behind db
+type PostgresReplicationLag struct {
+	db DBClient
+}
+
+func NewPostgresReplicationLag(db DBClient) *PostgresReplicationLag {
+	return &PostgresReplicationLag{db: db}
+}
+
+// replicationLagQuery reports zero once everything received has been replayed, so a replica of an idle
+// primary does not look like it lags just because no transaction committed lately. A server that is
+// not a replica has neither position and also reports zero
+const replicationLagQuery = `
+	SELECT CASE
+		WHEN pg_last_wal_receive_lsn() IS NOT DISTINCT FROM pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM (now() - pg_last_xact_replay_timestamp())), 0)
+	END
+`
+
+// ReplicationLag returns how long ago the last transaction the replica replayed committed on the primary
+// The query is kept out of the query metrics, like the health check
+func (r *PostgresReplicationLag) ReplicationLag(ctx context.Context) (time.Duration, error) {
+	var seconds float64
+	if err := r.db.QueryRowContext(WithoutInstrumentation(ctx), replicationLagQuery).Scan(&seconds); err != nil {
+		return 0, fmt.Errorf("failed to query replication lag: %w", err)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
@@ -0,0 +1,79 @@
+package infrastructure
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/rs/zerolog"
+)
+
+// RepositoryOption configures optional repository behaviour
+type RepositoryOption func(*repositoryOptions)
+
+// DefaultBatchSize is how many rows a batch insert writes per statement unless WithBatchSize says otherwise
+const DefaultBatchSize = 1000
+
+type repositoryOptions struct {
+	logger            zerolog.Logger
+	skipMalformedRows bool
+	batchSize         int
+}
+
+// WithRepositoryLogger logs failed repository calls at debug level; without it failures are only returned
+func WithRepositoryLogger(logger zerolog.Logger) RepositoryOption {
+	return func(o *repositoryOptions) {
+		o.logger = logger
+	}
+}
+
+// WithSkipMalformedRows makes full listings skip rows that fail to scan, such as unexpected NULLs left
+// by a bad migration, logging and counting them instead of failing the whole listing; by default they fail
+func WithSkipMalformedRows(skip bool) RepositoryOption {
+	return func(o *repositoryOptions) {
+		o.skipMalformedRows = skip
+	}
+}
+
+// WithBatchSize caps the rows one batch insert statement writes; larger batches are written in chunks of
+// size within the caller's transaction, which keeps each statement's arrays and memory bounded
+func WithBatchSize(size int) RepositoryOption {
+	return func(o *repositoryOptions) {
+		if size > 0 {
+			o.batchSize = size
+		}
+	}
+}
+
+func newRepositoryOptions(opts []RepositoryOption) *repositoryOptions {
+	options := &repositoryOptions{logger: zerolog.Nop(), batchSize: DefaultBatchSize}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// queryLogger records which repository operation failed, since wrapped errors name the failure but not the call
+// Query arguments are never logged as they may carry user data
+type queryLogger struct {
+	logger zerolog.Logger
+}
+
+// logFailure is deferred with the call's start time and a pointer to its named error result
+// Not-found results are expected outcomes rather than failures and are not logged
+func (l queryLogger) logFailure(operation string, start time.Time, errp *error) {
+	err := *errp
+	if err == nil {
+		return
+	}
+	var notFound *domain.NotFoundError
+	if errors.As(err, &notFound) {
+		return
+	}
+
+	l.logger.Debug().
+		Err(err).
+		Str("operation", operation).
+		Dur("duration", time.Since(start)).
+		Msg("repository call failed")
+}
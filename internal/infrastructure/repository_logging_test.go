@@ -0,0 +1,79 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingDB fails every statement with err
+type failingDB struct {
+	DBClient
+	err error
+}
+
+func (db failingDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, db.err
+}
+
+func (db failingDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, db.err
+}
+
+func TestRepositoryLogger_LogsFailedCalls(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf).Level(zerolog.DebugLevel)
+	db := failingDB{err: errors.New("connection reset by peer")}
+
+	event, err := domain.NewEvent("Concert", "Arena", time.Now().Add(24*time.Hour), 100)
+	require.NoError(t, err)
+	err = NewPostgresEventRepository(db, WithRepositoryLogger(logger)).Update(context.Background(), event)
+	require.Error(t, err)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "debug", entry["level"])
+	assert.Equal(t, "repository call failed", entry["message"])
+	assert.Equal(t, "event.update", entry["operation"])
+	assert.Contains(t, entry["error"], "connection reset by peer")
+	assert.Contains(t, entry, "duration")
+	assert.NotContains(t, buf.String(), "Concert", "query arguments are not logged")
+
+	buf.Reset()
+	_, err = NewPostgresBookingRepository(db, WithRepositoryLogger(logger)).FindPendingByEventWithLock(context.Background(), db, event.ID)
+	require.Error(t, err)
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "booking.find_pending_by_event_with_lock", entry["operation"])
+}
+
+func TestRepositoryLogger_SkipsSuccessAndNotFound(t *testing.T) {
+	var buf bytes.Buffer
+	l := queryLogger{logger: zerolog.New(&buf)}
+
+	var err error
+	l.logFailure("event.find_by_id", time.Now(), &err)
+	err = domain.ErrEventNotFound
+	l.logFailure("event.find_by_id", time.Now(), &err)
+
+	assert.Empty(t, buf.String())
+}
+
+func TestRepositoryLogger_DebugOnly(t *testing.T) {
+	var buf bytes.Buffer
+	db := failingDB{err: errors.New("boom")}
+	repo := NewPostgresBookingRepository(db, WithRepositoryLogger(zerolog.New(&buf).Level(zerolog.InfoLevel)))
+
+	_, err := repo.FindActiveByUserWithLock(context.Background(), db, uuid.New())
+	require.Error(t, err)
+	assert.Empty(t, buf.String())
+}
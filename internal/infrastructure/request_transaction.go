@@ -0,0 +1,158 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jorzel/booking-service/internal/domain"
+)
+
+type requestTransactionKey struct{}
+
+// requestTransaction is a request's transaction and the work waiting for it to commit
+type requestTransaction struct {
+	tx          domain.Transaction
+	mu          sync.Mutex
+	afterCommit []func()
+}
+
+// WithRequestTransaction makes queries run with ctx through a RequestScopedDBClient go to tx
+func WithRequestTransaction(ctx context.Context, tx domain.Transaction) context.Context {
+	return context.WithValue(ctx, requestTransactionKey{}, &requestTransaction{tx: tx})
+}
+
+// WithoutRequestTransaction detaches ctx from its request transaction, keeping its other values, for work
+// that outlives the request such as webhook deliveries: their queries go to the database directly instead
+// of into a transaction the request commits or rolls back under them
+func WithoutRequestTransaction(ctx context.Context) context.Context {
+	if _, ok := RequestTransactionFromContext(ctx); !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, requestTransactionKey{}, (*requestTransaction)(nil))
+}
+
+// RequestTransactionFromContext returns the transaction set by WithRequestTransaction, if any
+func RequestTransactionFromContext(ctx context.Context) (domain.Transaction, bool) {
+	rt, ok := ctx.Value(requestTransactionKey{}).(*requestTransaction)
+	if !ok || rt == nil {
+		return nil, false
+	}
+	return rt.tx, true
+}
+
+// AfterRequestCommit runs fn once the request transaction of ctx commits, and never when it rolls back,
+// so side effects such as notifications only follow writes that happened; without a request transaction
+// fn runs right away
+func AfterRequestCommit(ctx context.Context, fn func()) {
+	rt, ok := ctx.Value(requestTransactionKey{}).(*requestTransaction)
+	if !ok || rt == nil {
+		fn()
+		return
+	}
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.afterCommit = append(rt.afterCommit, fn)
+}
+
+// RequestCommitted runs what AfterRequestCommit deferred for the request transaction of ctx; whoever
+// commits the request transaction calls it once the commit succeeded
+func RequestCommitted(ctx context.Context) {
+	rt, ok := ctx.Value(requestTransactionKey{}).(*requestTransaction)
+	if !ok || rt == nil {
+		return
+	}
+	rt.mu.Lock()
+	afterCommit := rt.afterCommit
+	rt.afterCommit = nil
+	rt.mu.Unlock()
+	for _, fn := range afterCommit {
+		fn()
+	}
+}
+
+// RequestScopedDBClient routes the queries of a context carrying a request transaction into it, so the
+// repositories built on it write through the request's transaction without being passed an executor
+// Without one, every call goes to the wrapped client unchanged
+type RequestScopedDBClient struct {
+	DBClient
+}
+
+func NewRequestScopedDBClient(db DBClient) *RequestScopedDBClient {
+	return &RequestScopedDBClient{DBClient: db}
+}
+
+func (c *RequestScopedDBClient) SlowTransactionThreshold() time.Duration {
+	return SlowTransactionThreshold(c.DBClient)
+}
+
+func (c *RequestScopedDBClient) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if tx, ok := RequestTransactionFromContext(ctx); ok {
+		return tx.ExecContext(ctx, query, args...)
+	}
+	return c.DBClient.ExecContext(ctx, query, args...)
+}
+
+func (c *RequestScopedDBClient) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if tx, ok := RequestTransactionFromContext(ctx); ok {
+		return tx.QueryContext(ctx, query, args...)
+	}
+	return c.DBClient.QueryContext(ctx, query, args...)
+}
+
+func (c *RequestScopedDBClient) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if tx, ok := RequestTransactionFromContext(ctx); ok {
+		return tx.QueryRowContext(ctx, query, args...)
+	}
+	return c.DBClient.QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx nests a transaction begun inside a request transaction as a savepoint of it
+// Committing the nested one releases the savepoint and rolling it back undoes only its own writes, while
+// the request transaction decides what is finally committed. opts cannot change a transaction already
+// running, so the nested one inherits the request transaction's isolation level
+func (c *RequestScopedDBClient) BeginTx(ctx context.Context, opts *sql.TxOptions) (domain.Transaction, error) {
+	tx, ok := RequestTransactionFromContext(ctx)
+	if !ok {
+		return c.DBClient.BeginTx(ctx, opts)
+	}
+
+	name := fmt.Sprintf("nested_%d", savepointSeq.Add(1))
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return nil, fmt.Errorf("failed to create savepoint: %w", ClassifyDBError(err))
+	}
+	return &savepointTx{Transaction: tx, ctx: ctx, name: name}, nil
+}
+
+// savepointSeq numbers savepoints; names only need to be unique within one transaction
+var savepointSeq atomic.Uint64
+
+// savepointTx is a transaction nested in a request transaction as a savepoint
+type savepointTx struct {
+	domain.Transaction
+	ctx  context.Context
+	name string
+	done bool
+}
+
+func (tx *savepointTx) Commit() error {
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	tx.done = true
+	_, err := tx.Transaction.ExecContext(tx.ctx, "RELEASE SAVEPOINT "+tx.name)
+	return err
+}
+
+// Rollback after Commit is a no-op, as for sql.Tx, so it can be deferred
+func (tx *savepointTx) Rollback() error {
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	tx.done = true
+	_, err := tx.Transaction.ExecContext(tx.ctx, "ROLLBACK TO SAVEPOINT "+tx.name)
+	return err
+}
@@ -0,0 +1,55 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// SchemaDriftDetector re-runs the startup schema check while the service is up, so a table or column
+// removed by manual DDL after startup is reported at once instead of surfacing as failing bookings
+// It only reports drift and never alters the schema: SchemaDrift is 1 while it lasts and every check
+// that finds it logs an error naming the outdated tables
+type SchemaDriftDetector struct {
+	database SelfCheck
+	schema   SelfCheck
+	logger   zerolog.Logger
+
+	mu      sync.Mutex
+	drifted bool
+}
+
+func NewSchemaDriftDetector(db DBClient, logger zerolog.Logger) *SchemaDriftDetector {
+	return &SchemaDriftDetector{
+		database: DatabaseCheck(db),
+		schema:   SchemaCheck(db),
+		logger:   logger.With().Str("component", "schema_drift").Logger(),
+	}
+}
+
+// Check compares the schema with the one the repositories expect and reports whether it drifted
+// An unreachable database is returned as an error rather than reported as drift
+func (d *SchemaDriftDetector) Check(ctx context.Context) (bool, error) {
+	if err := d.database.Run(ctx); err != nil {
+		return false, fmt.Errorf("failed to reach database: %w", err)
+	}
+
+	drift := d.schema.Run(ctx)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if drift != nil {
+		SchemaDrift.Set(1)
+		d.logger.Error().Err(drift).Msg("schema drift detected, the schema no longer matches the migrations")
+	} else {
+		SchemaDrift.Set(0)
+		if d.drifted {
+			d.logger.Info().Msg("schema drift resolved")
+		}
+	}
+	d.drifted = drift != nil
+	return d.drifted, nil
+}
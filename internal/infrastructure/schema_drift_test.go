@@ -0,0 +1,52 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaDriftDetector_FlagsDrift(t *testing.T) {
+	var logs bytes.Buffer
+	var schemaErr, databaseErr error
+	detector := &SchemaDriftDetector{
+		database: SelfCheck{Name: "database", Run: func(context.Context) error { return databaseErr }},
+		schema:   SelfCheck{Name: "schema", Run: func(context.Context) error { return schemaErr }},
+		logger:   zerolog.New(&logs),
+	}
+	ctx := context.Background()
+
+	drifted, err := detector.Check(ctx)
+	require.NoError(t, err)
+	assert.False(t, drifted)
+	assert.Equal(t, 0.0, testutil.ToFloat64(SchemaDrift))
+	assert.Empty(t, logs.String())
+
+	schemaErr = errors.New("table bookings is missing or outdated, run the migrations")
+	drifted, err = detector.Check(ctx)
+	require.NoError(t, err)
+	assert.True(t, drifted)
+	assert.Equal(t, 1.0, testutil.ToFloat64(SchemaDrift))
+	assert.Contains(t, logs.String(), `"level":"error"`)
+	assert.Contains(t, logs.String(), "table bookings is missing")
+
+	databaseErr = errors.New("connection refused")
+	logs.Reset()
+	_, err = detector.Check(ctx)
+	require.Error(t, err, "an unreachable database is not reported as drift")
+	assert.Equal(t, 1.0, testutil.ToFloat64(SchemaDrift), "the last known state is kept")
+	assert.Empty(t, logs.String())
+
+	databaseErr, schemaErr = nil, nil
+	drifted, err = detector.Check(ctx)
+	require.NoError(t, err)
+	assert.False(t, drifted)
+	assert.Equal(t, 0.0, testutil.ToFloat64(SchemaDrift))
+	assert.Contains(t, logs.String(), "schema drift resolved")
+}
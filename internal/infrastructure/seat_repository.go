@@ -0,0 +1,138 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/lib/pq"
+)
+
+const seatColumns = "event_id, seat_label, status, booking_id"
+
+type PostgresSeatRepository struct {
+	db DBClient
+}
+
+func NewPostgresSeatRepository(db DBClient) *PostgresSeatRepository {
+	return &PostgresSeatRepository{db: db}
+}
+
+func (r *PostgresSeatRepository) FindByEvent(ctx context.Context, eventID uuid.UUID) ([]*domain.Seat, error) {
+	query := `
+		SELECT ` + seatColumns + `
+		FROM seats
+		WHERE event_id = $1
+		ORDER BY seat_label ASC
+	`
+
+	return r.querySeats(ctx, r.db, query, eventID)
+}
+
+func (r *PostgresSeatRepository) FindByBooking(ctx context.Context, bookingID uuid.UUID) ([]*domain.Seat, error) {
+	query := `
+		SELECT ` + seatColumns + `
+		FROM seats
+		WHERE booking_id = $1
+		ORDER BY seat_label ASC
+	`
+
+	return r.querySeats(ctx, r.db, query, bookingID)
+}
+
+// CreateBatchWithExecutor inserts all seats of an event in a single statement
+func (r *PostgresSeatRepository) CreateBatchWithExecutor(ctx context.Context, exec domain.Executor, seats []*domain.Seat) error {
+	if len(seats) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO seats (event_id, seat_label, status)
+		SELECT $1, label, $3
+		FROM unnest($2::text[]) AS label
+	`
+
+	labels := make([]string, 0, len(seats))
+	for _, seat := range seats {
+		labels = append(labels, seat.Label)
+	}
+
+	_, err := exec.ExecContext(ctx, query, seats[0].EventID, pq.Array(labels), domain.SeatStatusFree)
+	if err != nil {
+		return fmt.Errorf("failed to create seats: %w", ClassifyDBError(err))
+	}
+
+	return nil
+}
+
+// FindByLabelsWithLock locks the selected seats in label order (FOR UPDATE)
+// A consistent lock order keeps concurrent bookings of overlapping seats from deadlocking
+func (r *PostgresSeatRepository) FindByLabelsWithLock(ctx context.Context, exec domain.Executor, eventID uuid.UUID, labels []string) ([]*domain.Seat, error) {
+	query := `
+		SELECT ` + seatColumns + `
+		FROM seats
+		WHERE event_id = $1 AND seat_label = ANY($2)
+		ORDER BY seat_label ASC
+		FOR UPDATE
+	`
+
+	return r.querySeats(ctx, exec, query, eventID, pq.Array(labels))
+}
+
+// UpdateWithExecutor persists the status and booking of each seat using the provided executor (transaction or db)
+func (r *PostgresSeatRepository) UpdateWithExecutor(ctx context.Context, exec domain.Executor, seats []*domain.Seat) error {
+	query := `
+		UPDATE seats
+		SET status = $3, booking_id = $4
+		WHERE event_id = $1 AND seat_label = $2
+	`
+
+	for _, seat := range seats {
+		_, err := exec.ExecContext(ctx, query, seat.EventID, seat.Label, seat.Status, nullUUID(seat.BookingID))
+		if err != nil {
+			return fmt.Errorf("failed to update seat %s: %w", seat.Label, ClassifyDBError(err))
+		}
+	}
+
+	return nil
+}
+
+func (r *PostgresSeatRepository) ReleaseByBookingWithExecutor(ctx context.Context, exec domain.Executor, bookingID uuid.UUID) error {
+	query := `
+		UPDATE seats
+		SET status = $2, booking_id = NULL
+		WHERE booking_id = $1
+	`
+
+	if _, err := exec.ExecContext(ctx, query, bookingID, domain.SeatStatusFree); err != nil {
+		return fmt.Errorf("failed to release seats: %w", ClassifyDBError(err))
+	}
+
+	return nil
+}
+
+func (r *PostgresSeatRepository) querySeats(ctx context.Context, exec domain.Executor, query string, args ...interface{}) ([]*domain.Seat, error) {
+	rows, err := exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query seats: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	var seats []*domain.Seat
+	for rows.Next() {
+		seat := &domain.Seat{}
+		var bookingID uuid.NullUUID
+		if err := rows.Scan(&seat.EventID, &seat.Label, &seat.Status, &bookingID); err != nil {
+			return nil, fmt.Errorf("failed to scan seat: %w", ClassifyDBError(err))
+		}
+		seat.BookingID = bookingID.UUID
+		seats = append(seats, seat)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating seats: %w", ClassifyDBError(err))
+	}
+
+	return seats, nil
+}
@@ -0,0 +1,138 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SelfCheck is a precondition for serving traffic, verified once at startup
+type SelfCheck struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// RunSelfChecks runs every check, not just up to the first failure, so a misconfigured deployment
+// reports all of its problems at once
+func RunSelfChecks(ctx context.Context, checks ...SelfCheck) error {
+	var failures []error
+	for _, check := range checks {
+		if err := check.Run(ctx); err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", check.Name, err))
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("startup self-check failed: %w", errors.Join(failures...))
+	}
+	return nil
+}
+
+// DatabaseCheck verifies the database answers queries
+func DatabaseCheck(db DBClient) SelfCheck {
+	return SelfCheck{
+		Name: "database",
+		Run: func(ctx context.Context) error {
+			_, err := db.HealthCheck(ctx)
+			return err
+		},
+	}
+}
+
+// expectedSchema lists, per table, the columns the repositories read and write
+// Migrations are plain SQL files without a version table, so the schema is at the expected version
+// when every one of them exists; keep it in step with new migrations
+var expectedSchema = []struct {
+	table   string
+	columns string
+}{
+	{table: "events", columns: eventColumns + ", updated_at, tenant_id, deleted_at, merged_into"},
+	{table: "ticket_availability", columns: "event_id, available_tickets, version"},
+	{table: "availability_changes", columns: "event_id, delta, reason, resulting_available, changed_at"},
+	{table: "booking_changes", columns: "booking_id, action, status, tickets_booked, actor, changed_at"},
+	{table: "bookings", columns: bookingColumns + ", tenant_id"},
+	{table: "holds", columns: holdColumns},
+	{table: "seats", columns: seatColumns},
+	{table: "idempotency_keys", columns: idempotencyKeyColumns},
+	{table: "discount_codes", columns: discountCodeColumns},
+	{table: "event_webhooks", columns: webhookColumns},
+	{table: "webhook_delivery_failures", columns: webhookDeliveryFailureColumns},
+	{table: "allocations", columns: allocationColumns},
+	{table: "lottery_entries", columns: lotteryEntryColumns},
+}
+
+// SchemaCheck verifies every table and column in expectedSchema exists, by selecting them without reading rows
+func SchemaCheck(db DBClient) SelfCheck {
+	return SelfCheck{
+		Name: "schema",
+		Run: func(ctx context.Context) error {
+			ctx = WithoutInstrumentation(ctx)
+			var failures []error
+			for _, expected := range expectedSchema {
+				rows, err := db.QueryContext(ctx, "SELECT "+expected.columns+" FROM "+expected.table+" LIMIT 0")
+				if err != nil {
+					failures = append(failures, fmt.Errorf("table %s is missing or outdated, run the migrations: %w", expected.table, err))
+					continue
+				}
+				rows.Close()
+			}
+			return errors.Join(failures...)
+		},
+	}
+}
+
+// RequiredEnvCheck verifies each named environment variable is set, for settings whose defaults
+// must not be relied on in a deployment (e.g. ADMIN_TOKEN or DB_PASSWORD in production)
+func RequiredEnvCheck(names []string) SelfCheck {
+	return SelfCheck{
+		Name: "environment",
+		Run: func(context.Context) error {
+			var missing []string
+			for _, name := range names {
+				if name = strings.TrimSpace(name); name != "" && os.Getenv(name) == "" {
+					missing = append(missing, name)
+				}
+			}
+			if len(missing) > 0 {
+				return fmt.Errorf("required variables not set: %s", strings.Join(missing, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+// MetricsCheck verifies the service's collectors are registered and the registry can be gathered,
+// so /metrics does not fail or silently drop series on the first scrape
+func MetricsCheck(registerer prometheus.Registerer, gatherer prometheus.Gatherer) SelfCheck {
+	return SelfCheck{
+		Name: "metrics",
+		Run: func(context.Context) error {
+			collectors := map[string]prometheus.Collector{
+				"events_created":   EventsCreated,
+				"bookings_created": BookingsCreated,
+				"http_duration":    HTTPRequestDuration,
+				"postgres_queries": PostgresQueriesTotal,
+			}
+			for name, collector := range collectors {
+				// Registering an already registered collector is how the client reports it is present
+				err := registerer.Register(collector)
+				var alreadyRegistered prometheus.AlreadyRegisteredError
+				if errors.As(err, &alreadyRegistered) {
+					continue
+				}
+				if err == nil {
+					registerer.Unregister(collector)
+				}
+				return fmt.Errorf("collector %s is not registered", name)
+			}
+
+			if _, err := gatherer.Gather(); err != nil {
+				return fmt.Errorf("failed to gather metrics: %w", err)
+			}
+			return nil
+		},
+	}
+}
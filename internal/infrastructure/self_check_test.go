@@ -0,0 +1,51 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSelfChecks_ReportsEveryFailure(t *testing.T) {
+	passing := SelfCheck{Name: "passing", Run: func(context.Context) error { return nil }}
+	failing := func(name string) SelfCheck {
+		return SelfCheck{Name: name, Run: func(context.Context) error { return errors.New("broken") }}
+	}
+
+	require.NoError(t, RunSelfChecks(context.Background(), passing))
+
+	err := RunSelfChecks(context.Background(), failing("first"), passing, failing("second"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "first: broken")
+	assert.Contains(t, err.Error(), "second: broken")
+	assert.NotContains(t, err.Error(), "passing")
+}
+
+func TestRequiredEnvCheck(t *testing.T) {
+	t.Setenv("SELF_CHECK_TEST_SET", "value")
+
+	check := RequiredEnvCheck([]string{"SELF_CHECK_TEST_SET", " SELF_CHECK_TEST_MISSING ", ""})
+	err := check.Run(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, "required variables not set: SELF_CHECK_TEST_MISSING", err.Error())
+
+	require.NoError(t, RequiredEnvCheck([]string{"SELF_CHECK_TEST_SET"}).Run(context.Background()))
+	require.NoError(t, RequiredEnvCheck(nil).Run(context.Background()))
+}
+
+func TestMetricsCheck(t *testing.T) {
+	require.NoError(t, MetricsCheck(prometheus.DefaultRegisterer, prometheus.DefaultGatherer).Run(context.Background()))
+
+	empty := prometheus.NewRegistry()
+	err := MetricsCheck(empty, empty).Run(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not registered")
+
+	families, err := empty.Gather()
+	require.NoError(t, err)
+	assert.Empty(t, families, "the check does not leave collectors behind")
+}
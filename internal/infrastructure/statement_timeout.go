@@ -0,0 +1,56 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jorzel/booking-service/internal/domain"
+)
+
+type statementTimeoutKey struct{}
+
+// WithStatementTimeout overrides the connection's statement_timeout for transactions and streams run with ctx,
+// e.g. to let an export run longer than regular requests; zero disables the timeout
+func WithStatementTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, statementTimeoutKey{}, timeout)
+}
+
+// StatementTimeoutFromContext returns the override set by WithStatementTimeout, if any
+func StatementTimeoutFromContext(ctx context.Context) (time.Duration, bool) {
+	timeout, ok := ctx.Value(statementTimeoutKey{}).(time.Duration)
+	return timeout, ok
+}
+
+// SetLocalStatementTimeout applies timeout to the rest of the transaction exec belongs to
+// SET cannot take placeholders, so the milliseconds are formatted into the statement
+func SetLocalStatementTimeout(ctx context.Context, exec domain.Executor, timeout time.Duration) error {
+	if _, err := exec.ExecContext(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout.Milliseconds())); err != nil {
+		return fmt.Errorf("failed to set statement timeout: %w", ClassifyDBError(err))
+	}
+	return nil
+}
+
+// withStreamExecutor runs fn against db, or against a read-only transaction carrying the context's statement
+// timeout override, since a session-level SET would outlive the request on a pooled connection
+func withStreamExecutor(ctx context.Context, db DBClient, fn func(exec domain.Executor) error) error {
+	timeout, ok := StatementTimeoutFromContext(ctx)
+	if !ok {
+		return fn(db)
+	}
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", ClassifyDBError(err))
+	}
+	defer tx.Rollback()
+
+	if err := SetLocalStatementTimeout(ctx, tx, timeout); err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,63 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jorzel/booking-service/internal/domain"
+)
+
+// StaticFXRates serves a fixed set of exchange rates, e.g. in tests, until a market data feed is wired up
+// A pair missing from the set is served as the inverse of its reverse pair when that one is present
+type StaticFXRates struct {
+	rates map[[2]string]domain.FXRate
+}
+
+// ParseFXRates parses comma-separated "<FROM>/<TO>=<rate>" pairs, e.g. "EUR/USD=1.08", published at asOf
+func ParseFXRates(raw string, asOf time.Time) ([]domain.FXRate, error) {
+	var rates []domain.FXRate
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		pair, value, ok := strings.Cut(entry, "=")
+		from, to, okPair := strings.Cut(strings.TrimSpace(pair), "/")
+		if !ok || !okPair {
+			return nil, fmt.Errorf("invalid exchange rate %q, want <FROM>/<TO>=<rate>", entry)
+		}
+		from, errFrom := domain.NormalizePayCurrency(from)
+		to, errTo := domain.NormalizePayCurrency(to)
+		if errFrom != nil || errTo != nil {
+			return nil, fmt.Errorf("invalid exchange rate %q: currencies must be ISO 4217 codes", entry)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("invalid exchange rate %q: rate must be a positive number", entry)
+		}
+		rates = append(rates, domain.FXRate{From: from, To: to, Rate: rate, AsOf: asOf})
+	}
+	return rates, nil
+}
+
+func NewStaticFXRates(rates ...domain.FXRate) *StaticFXRates {
+	byPair := make(map[[2]string]domain.FXRate, len(rates))
+	for _, rate := range rates {
+		byPair[[2]string{rate.From, rate.To}] = rate
+	}
+	return &StaticFXRates{rates: byPair}
+}
+
+func (p *StaticFXRates) Rate(ctx context.Context, from, to string) (domain.FXRate, error) {
+	if rate, ok := p.rates[[2]string{from, to}]; ok {
+		return rate, nil
+	}
+	if reverse, ok := p.rates[[2]string{to, from}]; ok && reverse.Rate > 0 {
+		return domain.FXRate{From: from, To: to, Rate: 1 / reverse.Rate, AsOf: reverse.AsOf}, nil
+	}
+	return domain.FXRate{}, domain.ErrUnsupportedCurrency
+}
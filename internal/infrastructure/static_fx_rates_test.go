@@ -0,0 +1,30 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFXRates(t *testing.T) {
+	asOf := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	rates, err := ParseFXRates(" eur/USD=1.08, EUR/JPY = 160 ,", asOf)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.FXRate{
+		{From: "EUR", To: "USD", Rate: 1.08, AsOf: asOf},
+		{From: "EUR", To: "JPY", Rate: 160, AsOf: asOf},
+	}, rates)
+
+	rate, err := NewStaticFXRates(rates...).Rate(context.Background(), "USD", "EUR")
+	require.NoError(t, err)
+	assert.InDelta(t, 1/1.08, rate.Rate, 1e-9)
+
+	for _, raw := range []string{"EUR/USD", "EURUSD=1.08", "EUR/DOLLAR=1.08", "EUR/USD=0", "EUR/USD=abc"} {
+		_, err := ParseFXRates(raw, asOf)
+		assert.Error(t, err, raw)
+	}
+}
@@ -0,0 +1,31 @@
+package infrastructure
+
+import (
+	"context"
+	"regexp"
+)
+
+// DefaultTenant owns rows written without a tenant, so single-tenant deployments need no tenant at all
+const DefaultTenant = ""
+
+// tenantPattern bounds tenant IDs to short, printable identifiers
+var tenantPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// ValidTenantID reports whether id may be used as a tenant ID
+func ValidTenantID(id string) bool {
+	return tenantPattern.MatchString(id)
+}
+
+type tenantKey struct{}
+
+// WithTenant scopes the event and booking repositories to tenantID for queries run with ctx
+// Rows of other tenants are neither read nor written, so they look like they do not exist
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant set by WithTenant, or DefaultTenant
+func TenantFromContext(ctx context.Context) string {
+	tenantID, _ := ctx.Value(tenantKey{}).(string)
+	return tenantID
+}
@@ -5,11 +5,28 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/lib/pq"
 )
 
+// Every availability write logs its change to availability_changes in the same statement, so the log
+// cannot diverge from ticket_availability. changed_at uses clock_timestamp() because writes happen under
+// the availability row lock, which orders them correctly even when their transactions started out of order
+
+const createAvailabilityQuery = `
+	WITH created AS (
+		INSERT INTO ticket_availability (event_id, available_tickets)
+		VALUES ($1, $2)
+		RETURNING event_id, available_tickets
+	)
+	INSERT INTO availability_changes (event_id, delta, reason, resulting_available, changed_at)
+	SELECT event_id, available_tickets, $3, available_tickets, clock_timestamp()
+	FROM created
+`
+
 type PostgresTicketAvailabilityRepository struct {
 	db DBClient
 }
@@ -19,62 +36,127 @@ func NewPostgresTicketAvailabilityRepository(db DBClient) *PostgresTicketAvailab
 }
 
 func (r *PostgresTicketAvailabilityRepository) Create(ctx context.Context, availability *domain.TicketAvailability) error {
-	query := `
-		INSERT INTO ticket_availability (event_id, available_tickets)
-		VALUES ($1, $2)
-	`
-
 	_, err := r.db.ExecContext(
 		ctx,
-		query,
+		createAvailabilityQuery,
 		availability.EventID,
 		availability.AvailableTickets,
+		domain.AvailabilityCreated,
 	)
+	if isUniqueViolation(err) {
+		return domain.ErrAvailabilityExists
+	}
 	if err != nil {
-		return fmt.Errorf("failed to create ticket availability: %w", err)
+		return fmt.Errorf("failed to create ticket availability: %w", ClassifyDBError(err))
 	}
 
 	return nil
 }
 
+// FindByEventID reads the availability of an event of the context's tenant
 func (r *PostgresTicketAvailabilityRepository) FindByEventID(ctx context.Context, eventID uuid.UUID) (*domain.TicketAvailability, error) {
 	query := `
-		SELECT event_id, available_tickets
-		FROM ticket_availability
-		WHERE event_id = $1
+		SELECT ta.event_id, ta.available_tickets, ta.version
+		FROM ticket_availability ta
+		JOIN events e ON e.id = ta.event_id
+		WHERE ta.event_id = $1 AND e.tenant_id = $2
 	`
 
 	availability := &domain.TicketAvailability{}
-	err := r.db.QueryRowContext(ctx, query, eventID).Scan(
+	err := r.db.QueryRowContext(ctx, query, eventID, TenantFromContext(ctx)).Scan(
 		&availability.EventID,
 		&availability.AvailableTickets,
+		&availability.Version,
 	)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, domain.ErrEventNotFound
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to find ticket availability: %w", err)
+		return nil, fmt.Errorf("failed to find ticket availability: %w", ClassifyDBError(err))
 	}
 
 	return availability, nil
 }
 
-// CreateWithExecutor creates ticket availability using the provided executor (transaction or db)
-func (r *PostgresTicketAvailabilityRepository) CreateWithExecutor(ctx context.Context, exec domain.Executor, availability *domain.TicketAvailability) error {
+// AdjustAvailableTickets applies delta with a single conditional UPDATE so concurrent adjustments
+// and bookings cannot push availability outside [0, event tickets]
+// A non-zero expectedVersion is checked in the same UPDATE, so a write between the caller's read and
+// this one is never overwritten
+// A refused update is explained by re-reading the row and applying the version check and the domain rule
+// Only events of the context's tenant are adjusted; others are reported as not found
+func (r *PostgresTicketAvailabilityRepository) AdjustAvailableTickets(ctx context.Context, eventID uuid.UUID, delta int, expectedVersion int64) (*domain.TicketAvailability, error) {
 	query := `
-		INSERT INTO ticket_availability (event_id, available_tickets)
-		VALUES ($1, $2)
+		WITH adjusted AS (
+			UPDATE ticket_availability ta
+			SET available_tickets = ta.available_tickets + $2, version = ta.version + 1
+			FROM events e
+			WHERE ta.event_id = $1
+				AND e.id = ta.event_id
+				AND e.deleted_at IS NULL
+				AND e.tenant_id = $5
+				AND ta.available_tickets + $2 BETWEEN 0 AND e.tickets
+				AND ($4::bigint = 0 OR ta.version = $4)
+			RETURNING ta.event_id, ta.available_tickets, ta.version
+		), logged AS (
+			INSERT INTO availability_changes (event_id, delta, reason, resulting_available, changed_at)
+			SELECT event_id, $2, $3, available_tickets, clock_timestamp()
+			FROM adjusted
+		)
+		SELECT event_id, available_tickets, version FROM adjusted
 	`
 
+	availability := &domain.TicketAvailability{}
+	err := r.db.QueryRowContext(ctx, query, eventID, delta, domain.AvailabilityAdjusted, expectedVersion, TenantFromContext(ctx)).Scan(
+		&availability.EventID,
+		&availability.AvailableTickets,
+		&availability.Version,
+	)
+	if err == nil {
+		return availability, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to adjust ticket availability: %w", ClassifyDBError(err))
+	}
+
+	var total int
+	err = r.db.QueryRowContext(ctx, `
+		SELECT ta.event_id, ta.available_tickets, ta.version, e.tickets
+		FROM ticket_availability ta
+		JOIN events e ON e.id = ta.event_id
+		WHERE ta.event_id = $1 AND e.deleted_at IS NULL AND e.tenant_id = $2
+	`, eventID, TenantFromContext(ctx)).Scan(&availability.EventID, &availability.AvailableTickets, &availability.Version, &total)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrEventNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find ticket availability: %w", ClassifyDBError(err))
+	}
+
+	if expectedVersion != 0 && availability.Version != expectedVersion {
+		return nil, domain.ErrAvailabilityVersionMismatch
+	}
+	if err := availability.AdjustAvailable(delta, total); err != nil {
+		return nil, err
+	}
+	// The row changed between the refused update and the re-read; the caller may retry
+	return nil, ErrSerializationFailure
+}
+
+// CreateWithExecutor creates ticket availability using the provided executor (transaction or db)
+func (r *PostgresTicketAvailabilityRepository) CreateWithExecutor(ctx context.Context, exec domain.Executor, availability *domain.TicketAvailability) error {
 	_, err := exec.ExecContext(
 		ctx,
-		query,
+		createAvailabilityQuery,
 		availability.EventID,
 		availability.AvailableTickets,
+		domain.AvailabilityCreated,
 	)
+	if isUniqueViolation(err) {
+		return domain.ErrAvailabilityExists
+	}
 	if err != nil {
-		return fmt.Errorf("failed to create ticket availability: %w", err)
+		return fmt.Errorf("failed to create ticket availability: %w", ClassifyDBError(err))
 	}
 
 	return nil
@@ -84,7 +166,7 @@ func (r *PostgresTicketAvailabilityRepository) CreateWithExecutor(ctx context.Co
 // This should be used within a transaction to prevent concurrent modifications
 func (r *PostgresTicketAvailabilityRepository) FindByEventIDWithLock(ctx context.Context, exec domain.Executor, eventID uuid.UUID) (*domain.TicketAvailability, error) {
 	query := `
-		SELECT event_id, available_tickets
+		SELECT event_id, available_tickets, version
 		FROM ticket_availability
 		WHERE event_id = $1
 		FOR UPDATE
@@ -94,24 +176,179 @@ func (r *PostgresTicketAvailabilityRepository) FindByEventIDWithLock(ctx context
 	err := exec.QueryRowContext(ctx, query, eventID).Scan(
 		&availability.EventID,
 		&availability.AvailableTickets,
+		&availability.Version,
 	)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, domain.ErrEventNotFound
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to find ticket availability: %w", err)
+		return nil, fmt.Errorf("failed to find ticket availability: %w", ClassifyDBError(err))
 	}
 
 	return availability, nil
 }
 
+// FindByEventIDsWithLock locks availability rows in event ID order (FOR UPDATE)
+// Every multi-event reservation locks in the same order, so overlapping carts cannot deadlock
+func (r *PostgresTicketAvailabilityRepository) FindByEventIDsWithLock(ctx context.Context, exec domain.Executor, eventIDs []uuid.UUID) ([]*domain.TicketAvailability, error) {
+	query := `
+		SELECT event_id, available_tickets, version
+		FROM ticket_availability
+		WHERE event_id = ANY($1::uuid[])
+		ORDER BY event_id
+		FOR UPDATE
+	`
+
+	rows, err := exec.QueryContext(ctx, query, pq.Array(uuidStrings(eventIDs)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ticket availability: %w", ClassifyDBError(err))
+	}
+	return scanAvailabilities(rows)
+}
+
+// FindByEventIDs reads the availability of several events in one query without locking
+// Events without availability, or of another tenant than the context's, are left out of the result
+func (r *PostgresTicketAvailabilityRepository) FindByEventIDs(ctx context.Context, eventIDs []uuid.UUID) ([]*domain.TicketAvailability, error) {
+	query := `
+		SELECT ta.event_id, ta.available_tickets, ta.version
+		FROM ticket_availability ta
+		JOIN events e ON e.id = ta.event_id
+		WHERE ta.event_id = ANY($1::uuid[]) AND e.tenant_id = $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(uuidStrings(eventIDs)), TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ticket availability: %w", ClassifyDBError(err))
+	}
+	return scanAvailabilities(rows)
+}
+
+// ExpectedAvailableWithExecutor derives the events' availability from their allocations, bookings and holds
+// Bookings drawn from an allocation are left out, since their tickets were taken from the block, not public sale
+func (r *PostgresTicketAvailabilityRepository) ExpectedAvailableWithExecutor(ctx context.Context, exec domain.Executor, eventIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	query := `
+		SELECT e.id,
+			e.tickets
+			- COALESCE((SELECT SUM(a.tickets) FROM allocations a WHERE a.event_id = e.id), 0)
+			- COALESCE((SELECT SUM(b.tickets_booked) FROM bookings b
+				WHERE b.event_id = e.id AND b.status <> $2 AND b.allocation_id IS NULL), 0)
+			- COALESCE((SELECT SUM(h.tickets) FROM holds h WHERE h.event_id = e.id AND h.status = $3), 0)
+		FROM events e
+		WHERE e.id = ANY($1::uuid[]) AND e.tenant_id = $4
+	`
+
+	rows, err := exec.QueryContext(ctx, query, pq.Array(uuidStrings(eventIDs)),
+		domain.BookingStatusCancelled, domain.HoldStatusActive, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute expected availability: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	expected := make(map[uuid.UUID]int, len(eventIDs))
+	for rows.Next() {
+		var eventID uuid.UUID
+		var available int
+		if err := rows.Scan(&eventID, &available); err != nil {
+			return nil, fmt.Errorf("failed to scan expected availability: %w", ClassifyDBError(err))
+		}
+		expected[eventID] = available
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating expected availability: %w", ClassifyDBError(err))
+	}
+
+	return expected, nil
+}
+
+func scanAvailabilities(rows *sql.Rows) ([]*domain.TicketAvailability, error) {
+	defer rows.Close()
+
+	var availabilities []*domain.TicketAvailability
+	for rows.Next() {
+		availability := &domain.TicketAvailability{}
+		if err := rows.Scan(&availability.EventID, &availability.AvailableTickets, &availability.Version); err != nil {
+			return nil, fmt.Errorf("failed to scan ticket availability: %w", ClassifyDBError(err))
+		}
+		availabilities = append(availabilities, availability)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating ticket availability: %w", ClassifyDBError(err))
+	}
+
+	return availabilities, nil
+}
+
+// UpdateBatchWithExecutor updates several availabilities in one statement using the provided executor
+// The previous values are read from the statement's snapshot, which the caller's row locks keep current
+func (r *PostgresTicketAvailabilityRepository) UpdateBatchWithExecutor(ctx context.Context, exec domain.Executor, availabilities []*domain.TicketAvailability, reason domain.AvailabilityChangeReason) error {
+	query := `
+		WITH v AS (
+			SELECT * FROM unnest($1::uuid[], $2::int[]) AS v(event_id, available_tickets)
+		), previous AS (
+			SELECT ta.event_id, ta.available_tickets
+			FROM ticket_availability ta
+			JOIN v ON v.event_id = ta.event_id
+		), updated AS (
+			UPDATE ticket_availability ta
+			SET available_tickets = v.available_tickets, version = ta.version + 1
+			FROM v
+			WHERE ta.event_id = v.event_id
+			RETURNING ta.event_id, ta.available_tickets
+		)
+		INSERT INTO availability_changes (event_id, delta, reason, resulting_available, changed_at)
+		SELECT u.event_id, u.available_tickets - p.available_tickets, $3, u.available_tickets, clock_timestamp()
+		FROM updated u
+		JOIN previous p ON p.event_id = u.event_id
+	`
+
+	eventIDs := make([]string, len(availabilities))
+	available := make([]int64, len(availabilities))
+	for i, availability := range availabilities {
+		eventIDs[i] = availability.EventID.String()
+		available[i] = int64(availability.AvailableTickets)
+	}
+
+	result, err := exec.ExecContext(ctx, query, pq.Array(eventIDs), pq.Array(available), reason)
+	if err != nil {
+		return fmt.Errorf("failed to update ticket availability: %w", ClassifyDBError(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected != int64(len(availabilities)) {
+		return domain.ErrEventNotFound
+	}
+	for _, availability := range availabilities {
+		availability.Version++
+	}
+
+	return nil
+}
+
 // UpdateWithExecutor updates ticket availability using the provided executor (transaction or db)
-func (r *PostgresTicketAvailabilityRepository) UpdateWithExecutor(ctx context.Context, exec domain.Executor, availability *domain.TicketAvailability) error {
+// and logs the change with reason; the delta is taken against the value the statement replaces
+func (r *PostgresTicketAvailabilityRepository) UpdateWithExecutor(ctx context.Context, exec domain.Executor, availability *domain.TicketAvailability, reason domain.AvailabilityChangeReason) error {
 	query := `
-		UPDATE ticket_availability
-		SET available_tickets = $2
-		WHERE event_id = $1
+		WITH previous AS (
+			SELECT event_id, available_tickets
+			FROM ticket_availability
+			WHERE event_id = $1
+		), updated AS (
+			UPDATE ticket_availability
+			SET available_tickets = $2, version = version + 1
+			WHERE event_id = $1
+			RETURNING event_id, available_tickets
+		)
+		INSERT INTO availability_changes (event_id, delta, reason, resulting_available, changed_at)
+		SELECT u.event_id, u.available_tickets - p.available_tickets, $3, u.available_tickets, clock_timestamp()
+		FROM updated u
+		JOIN previous p ON p.event_id = u.event_id
 	`
 
 	result, err := exec.ExecContext(
@@ -119,9 +356,10 @@ func (r *PostgresTicketAvailabilityRepository) UpdateWithExecutor(ctx context.Co
 		query,
 		availability.EventID,
 		availability.AvailableTickets,
+		reason,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to update ticket availability: %w", err)
+		return fmt.Errorf("failed to update ticket availability: %w", ClassifyDBError(err))
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -132,6 +370,85 @@ func (r *PostgresTicketAvailabilityRepository) UpdateWithExecutor(ctx context.Co
 	if rowsAffected == 0 {
 		return domain.ErrEventNotFound
 	}
+	availability.Version++
 
 	return nil
 }
+
+// FindChangeAt returns the latest availability change at or before at, whose Resulting value is the
+// availability at that time
+func (r *PostgresTicketAvailabilityRepository) FindChangeAt(ctx context.Context, eventID uuid.UUID, at time.Time) (*domain.AvailabilityChange, error) {
+	query := `
+		SELECT event_id, delta, reason, resulting_available, changed_at
+		FROM availability_changes
+		WHERE event_id = $1 AND changed_at <= $2
+		ORDER BY changed_at DESC, id DESC
+		LIMIT 1
+	`
+
+	change := &domain.AvailabilityChange{}
+	err := r.db.QueryRowContext(ctx, query, eventID, at).Scan(
+		&change.EventID,
+		&change.Delta,
+		&change.Reason,
+		&change.Resulting,
+		&change.ChangedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrAvailabilityHistoryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find availability change: %w", ClassifyDBError(err))
+	}
+
+	return change, nil
+}
+
+// LastChangedAt returns the newest logged change of the events' availability, or zero when none was logged
+// Each event's newest change is read from the idx_availability_changes_event_changed_at index
+func (r *PostgresTicketAvailabilityRepository) LastChangedAt(ctx context.Context, eventIDs []uuid.UUID) (time.Time, error) {
+	query := `
+		SELECT MAX(changed_at)
+		FROM availability_changes
+		WHERE event_id = ANY($1::uuid[])
+	`
+
+	var lastChanged sql.NullTime
+	if err := r.db.QueryRowContext(ctx, query, pq.Array(uuidStrings(eventIDs))).Scan(&lastChanged); err != nil {
+		return time.Time{}, fmt.Errorf("failed to read availability last changed: %w", ClassifyDBError(err))
+	}
+
+	return lastChanged.Time, nil
+}
+
+// FindDuplicateEventIDs returns events that have more than one availability row
+// The aggregate must be a single row per event; duplicates make FindByEventID pick an arbitrary row
+func (r *PostgresTicketAvailabilityRepository) FindDuplicateEventIDs(ctx context.Context) ([]uuid.UUID, error) {
+	query := `
+		SELECT event_id
+		FROM ticket_availability
+		GROUP BY event_id
+		HAVING COUNT(*) > 1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query duplicate ticket availability: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	var eventIDs []uuid.UUID
+	for rows.Next() {
+		var eventID uuid.UUID
+		if err := rows.Scan(&eventID); err != nil {
+			return nil, fmt.Errorf("failed to scan duplicate ticket availability: %w", ClassifyDBError(err))
+		}
+		eventIDs = append(eventIDs, eventID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating duplicate ticket availability: %w", ClassifyDBError(err))
+	}
+
+	return eventIDs, nil
+}
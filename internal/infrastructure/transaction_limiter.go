@@ -0,0 +1,74 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/jorzel/booking-service/internal/domain"
+)
+
+// TransactionLimitedDBClient caps how many transactions are open at once, so a burst of requests fails fast
+// with domain.ErrTooManyTransactions instead of queueing for the connection pool until it times out
+// A transaction holds its slot from BeginTx until its first Commit or Rollback; callers already defer
+// Rollback, which also returns the slot when the work panics
+type TransactionLimitedDBClient struct {
+	DBClient
+	slots chan struct{}
+}
+
+// NewTransactionLimitedDBClient allows up to limit concurrent transactions on db; limit must be positive
+func NewTransactionLimitedDBClient(db DBClient, limit int) *TransactionLimitedDBClient {
+	return &TransactionLimitedDBClient{DBClient: db, slots: make(chan struct{}, limit)}
+}
+
+// BeginTx takes a slot without waiting for one, failing with domain.ErrTooManyTransactions when all are taken
+func (c *TransactionLimitedDBClient) BeginTx(ctx context.Context, opts *sql.TxOptions) (_ domain.Transaction, err error) {
+	select {
+	case c.slots <- struct{}{}:
+	default:
+		DBTransactionsRejected.Inc()
+		return nil, domain.ErrTooManyTransactions
+	}
+
+	// The slot goes back if the transaction never starts, including when BeginTx panics
+	started := false
+	defer func() {
+		if !started {
+			<-c.slots
+		}
+	}()
+
+	tx, err := c.DBClient.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	started = true
+	return &limitedTx{Transaction: tx, release: sync.OnceFunc(func() { <-c.slots })}, nil
+}
+
+func (c *TransactionLimitedDBClient) SlowTransactionThreshold() time.Duration {
+	return SlowTransactionThreshold(c.DBClient)
+}
+
+// InFlight returns how many transactions hold a slot
+func (c *TransactionLimitedDBClient) InFlight() int {
+	return len(c.slots)
+}
+
+// limitedTx returns its slot once it ends, however many times Commit and Rollback are called
+type limitedTx struct {
+	domain.Transaction
+	release func()
+}
+
+func (tx *limitedTx) Commit() error {
+	defer tx.release()
+	return tx.Transaction.Commit()
+}
+
+func (tx *limitedTx) Rollback() error {
+	defer tx.release()
+	return tx.Transaction.Rollback()
+}
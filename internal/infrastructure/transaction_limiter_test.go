@@ -0,0 +1,118 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubTx struct{ domain.Executor }
+
+func (stubTx) Commit() error   { return nil }
+func (stubTx) Rollback() error { return nil }
+
+// beginTxDB begins stub transactions, or fails or panics when told to
+type beginTxDB struct {
+	DBClient
+	err   error
+	panic bool
+}
+
+func (db *beginTxDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (domain.Transaction, error) {
+	if db.panic {
+		panic("driver blew up")
+	}
+	if db.err != nil {
+		return nil, db.err
+	}
+	return stubTx{}, nil
+}
+
+func TestTransactionLimitedDBClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rejects transactions beyond the limit until one ends", func(t *testing.T) {
+		db := NewTransactionLimitedDBClient(&beginTxDB{}, 2)
+
+		first, err := db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+		second, err := db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+
+		_, err = db.BeginTx(ctx, nil)
+		assert.ErrorIs(t, err, domain.ErrTooManyTransactions)
+		var unavailableErr *domain.UnavailableError
+		assert.ErrorAs(t, err, &unavailableErr, "excess transactions answer 503")
+
+		require.NoError(t, first.Commit())
+		require.NoError(t, first.Rollback(), "a deferred rollback after commit must not free a second slot")
+		assert.Equal(t, 1, db.InFlight())
+
+		third, err := db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+		require.NoError(t, second.Rollback())
+		require.NoError(t, third.Rollback())
+		assert.Equal(t, 0, db.InFlight())
+	})
+
+	t.Run("caps concurrent transactions", func(t *testing.T) {
+		db := NewTransactionLimitedDBClient(&beginTxDB{}, 3)
+		var open, peak, rejected atomic.Int32
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				tx, err := db.BeginTx(ctx, nil)
+				if err != nil {
+					rejected.Add(1)
+					return
+				}
+				defer tx.Rollback()
+				n := open.Add(1)
+				for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+				}
+				time.Sleep(10 * time.Millisecond)
+				open.Add(-1)
+			}()
+		}
+		wg.Wait()
+
+		assert.LessOrEqual(t, peak.Load(), int32(3))
+		assert.Positive(t, rejected.Load())
+		assert.Equal(t, 0, db.InFlight())
+	})
+
+	t.Run("frees the slot when the transaction fails to begin", func(t *testing.T) {
+		db := NewTransactionLimitedDBClient(&beginTxDB{err: errors.New("connection refused")}, 1)
+		_, err := db.BeginTx(ctx, nil)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrTooManyTransactions)
+		assert.Equal(t, 0, db.InFlight())
+	})
+
+	t.Run("frees the slot when beginning panics", func(t *testing.T) {
+		db := NewTransactionLimitedDBClient(&beginTxDB{panic: true}, 1)
+		assert.Panics(t, func() { db.BeginTx(ctx, nil) })
+		assert.Equal(t, 0, db.InFlight())
+	})
+
+	t.Run("frees the slot when the work panics", func(t *testing.T) {
+		db := NewTransactionLimitedDBClient(&beginTxDB{}, 1)
+		assert.Panics(t, func() {
+			tx, err := db.BeginTx(ctx, nil)
+			require.NoError(t, err)
+			defer tx.Rollback()
+			panic("handler blew up")
+		})
+		assert.Equal(t, 0, db.InFlight())
+	})
+}
@@ -0,0 +1,237 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+)
+
+const webhookColumns = "id, event_id, url, secret, created_at"
+
+const webhookDeliveryFailureColumns = "id, webhook_id, event_id, booking_id, type, error, failed_at, payload, occurred_at, attempts"
+
+// deliveryFailurePayload is the notified booking kept with a dead letter, holding what deliveries send
+type deliveryFailurePayload struct {
+	UserID        uuid.UUID            `json:"user_id"`
+	TicketsBooked int                  `json:"tickets_booked"`
+	Status        domain.BookingStatus `json:"status"`
+	BookedAt      time.Time            `json:"booked_at"`
+	PriceCents    int64                `json:"price_cents"`
+	Currency      string               `json:"currency,omitempty"`
+}
+
+type PostgresWebhookRepository struct {
+	db DBClient
+}
+
+func NewPostgresWebhookRepository(db DBClient) *PostgresWebhookRepository {
+	return &PostgresWebhookRepository{db: db}
+}
+
+func (r *PostgresWebhookRepository) Create(ctx context.Context, hook *domain.EventWebhook) error {
+	query := `
+		INSERT INTO event_webhooks (` + webhookColumns + `)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, hook.ID, hook.EventID, hook.URL, hook.Secret, hook.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", ClassifyDBError(err))
+	}
+
+	return nil
+}
+
+// FindByEvent returns the event's webhooks, oldest first
+func (r *PostgresWebhookRepository) FindByEvent(ctx context.Context, eventID uuid.UUID) ([]*domain.EventWebhook, error) {
+	query := `
+		SELECT ` + webhookColumns + `
+		FROM event_webhooks
+		WHERE event_id = $1
+		ORDER BY created_at ASC, id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhooks: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	var hooks []*domain.EventWebhook
+	for rows.Next() {
+		hook := &domain.EventWebhook{}
+		if err := rows.Scan(&hook.ID, &hook.EventID, &hook.URL, &hook.Secret, &hook.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", ClassifyDBError(err))
+		}
+		hooks = append(hooks, hook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhooks: %w", ClassifyDBError(err))
+	}
+
+	return hooks, nil
+}
+
+func (r *PostgresWebhookRepository) Delete(ctx context.Context, eventID, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM event_webhooks WHERE id = $1 AND event_id = $2", id, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", ClassifyDBError(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresWebhookRepository) CreateDeliveryFailure(ctx context.Context, failure *domain.WebhookDeliveryFailure) error {
+	query := `
+		INSERT INTO webhook_delivery_failures (` + webhookDeliveryFailureColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	var payload []byte
+	var occurredAt sql.NullTime
+	if booking := failure.Booking; booking != nil {
+		encoded, err := json.Marshal(deliveryFailurePayload{
+			UserID:        booking.UserID,
+			TicketsBooked: booking.TicketsBooked,
+			Status:        booking.Status,
+			BookedAt:      booking.BookedAt,
+			PriceCents:    booking.PriceCents,
+			Currency:      booking.Currency,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to encode webhook delivery failure payload: %w", err)
+		}
+		payload = encoded
+		occurredAt = sql.NullTime{Time: failure.OccurredAt, Valid: true}
+	}
+
+	_, err := r.db.ExecContext(ctx, query, failure.ID, failure.WebhookID, failure.EventID, failure.BookingID,
+		failure.Type, failure.Error, failure.FailedAt, payload, occurredAt, max(failure.Attempts, 1))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery failure: %w", ClassifyDBError(err))
+	}
+
+	return nil
+}
+
+// FindDeliveryFailures pages through the dead letters, most recent first
+func (r *PostgresWebhookRepository) FindDeliveryFailures(ctx context.Context, limit, offset int) ([]*domain.WebhookDeliveryFailure, error) {
+	query := `
+		SELECT ` + webhookDeliveryFailureColumns + `
+		FROM webhook_delivery_failures
+		ORDER BY failed_at DESC, id
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook delivery failures: %w", ClassifyDBError(err))
+	}
+	defer rows.Close()
+
+	var failures []*domain.WebhookDeliveryFailure
+	for rows.Next() {
+		failure, err := scanDeliveryFailure(rows)
+		if err != nil {
+			return nil, err
+		}
+		failures = append(failures, failure)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook delivery failures: %w", ClassifyDBError(err))
+	}
+
+	return failures, nil
+}
+
+func (r *PostgresWebhookRepository) CountDeliveryFailures(ctx context.Context) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM webhook_delivery_failures").Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count webhook delivery failures: %w", ClassifyDBError(err))
+	}
+	return count, nil
+}
+
+func (r *PostgresWebhookRepository) FindDeliveryFailure(ctx context.Context, id uuid.UUID) (*domain.WebhookDeliveryFailure, error) {
+	query := `
+		SELECT ` + webhookDeliveryFailureColumns + `
+		FROM webhook_delivery_failures
+		WHERE id = $1
+	`
+
+	failure, err := scanDeliveryFailure(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrDeliveryFailureNotFound
+	}
+	return failure, err
+}
+
+func (r *PostgresWebhookRepository) DeleteDeliveryFailure(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM webhook_delivery_failures WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook delivery failure: %w", ClassifyDBError(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrDeliveryFailureNotFound
+	}
+
+	return nil
+}
+
+// scanDeliveryFailure reads a row of webhookDeliveryFailureColumns, rebuilding the booking from its payload
+// sql.ErrNoRows is returned unwrapped, so callers can tell a missing dead letter apart
+func scanDeliveryFailure(row rowScanner) (*domain.WebhookDeliveryFailure, error) {
+	failure := &domain.WebhookDeliveryFailure{}
+	var payload []byte
+	var occurredAt sql.NullTime
+	err := row.Scan(&failure.ID, &failure.WebhookID, &failure.EventID, &failure.BookingID, &failure.Type,
+		&failure.Error, &failure.FailedAt, &payload, &occurredAt, &failure.Attempts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan webhook delivery failure: %w", ClassifyDBError(err))
+	}
+
+	if payload != nil {
+		var booking deliveryFailurePayload
+		if err := json.Unmarshal(payload, &booking); err != nil {
+			return nil, fmt.Errorf("failed to decode webhook delivery failure payload: %w", err)
+		}
+		failure.Booking = &domain.Booking{
+			ID:            failure.BookingID,
+			EventID:       failure.EventID,
+			UserID:        booking.UserID,
+			TicketsBooked: booking.TicketsBooked,
+			Status:        booking.Status,
+			BookedAt:      booking.BookedAt,
+			PriceCents:    booking.PriceCents,
+			Currency:      booking.Currency,
+		}
+		failure.OccurredAt = occurredAt.Time
+	}
+	return failure, nil
+}
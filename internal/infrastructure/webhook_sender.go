@@ -0,0 +1,216 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+)
+
+const (
+	// WebhookSignatureHeader carries "sha256=" and the hex HMAC-SHA256 of "<timestamp>.<body>" keyed by the secret
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	// WebhookTimestampHeader carries the Unix time the delivery was signed, so receivers can reject replays
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	// WebhookDeliveryHeader identifies a delivery; retries of one delivery share it, so receivers can dedupe
+	WebhookDeliveryHeader = "X-Webhook-Delivery"
+)
+
+const (
+	DefaultWebhookAttempts = 3
+	DefaultWebhookBackoff  = time.Second
+	DefaultWebhookTimeout  = 5 * time.Second
+)
+
+// errInternalWebhookAddress refuses a delivery whose host resolved to an internal address
+var errInternalWebhookAddress = errors.New("webhook host resolves to an internal address")
+
+// HTTPWebhookSender posts signed payloads to webhooks, retrying network errors, 429 and 5xx responses
+// with exponential backoff; other responses are final
+// Deliveries are refused, without retrying, when the webhook's host resolves to a loopback, link-local or
+// private address, checked on every connection so re-pointing a registered host's DNS does not get around it
+type HTTPWebhookSender struct {
+	client        *http.Client
+	attempts      int
+	backoff       time.Duration
+	allowInternal bool
+}
+
+// WebhookSenderOption configures optional sender behaviour
+type WebhookSenderOption func(*HTTPWebhookSender)
+
+// WithWebhookRetries makes up to attempts deliveries, waiting backoff, then twice as long, between them
+func WithWebhookRetries(attempts int, backoff time.Duration) WebhookSenderOption {
+	return func(s *HTTPWebhookSender) {
+		s.attempts = max(attempts, 1)
+		s.backoff = backoff
+	}
+}
+
+// WithWebhookClient replaces the HTTP client, e.g. to route deliveries through an egress proxy
+// The client's transport then decides which addresses may be dialed
+func WithWebhookClient(client *http.Client) WebhookSenderOption {
+	return func(s *HTTPWebhookSender) {
+		s.client = client
+	}
+}
+
+// WithWebhookInternalHosts lets deliveries reach internal addresses, for development and tests where
+// receivers listen on localhost
+func WithWebhookInternalHosts() WebhookSenderOption {
+	return func(s *HTTPWebhookSender) {
+		s.allowInternal = true
+	}
+}
+
+func NewHTTPWebhookSender(opts ...WebhookSenderOption) *HTTPWebhookSender {
+	s := &HTTPWebhookSender{
+		attempts: DefaultWebhookAttempts,
+		backoff:  DefaultWebhookBackoff,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.client == nil {
+		s.client = &http.Client{Timeout: DefaultWebhookTimeout, Transport: newWebhookTransport(s.allowInternal)}
+	}
+	return s
+}
+
+// Probe checks that deliveries can leave the service by reaching url through the sender's own client, so
+// an unreachable egress proxy or a broken DNS shows up in readiness
+func (s *HTTPWebhookSender) Probe(url string) DependencyProbe {
+	return HTTPProbe(s.client, url)
+}
+
+// newWebhookTransport dials webhooks directly; unless allowInternal, a connection to an internal address
+// is refused after DNS resolution, right before it would be made
+func newWebhookTransport(allowInternal bool) *http.Transport {
+	dialer := &net.Dialer{Timeout: DefaultWebhookTimeout}
+	if !allowInternal {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || domain.IsInternalIP(ip) {
+				return errInternalWebhookAddress
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialed instead of the webhook, so the address check would no longer apply
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
+
+// SignWebhookPayload returns the signature receivers recompute to verify a delivery
+func SignWebhookPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookPayload is the JSON body of a delivery
+type webhookPayload struct {
+	Type          string    `json:"type"`
+	OccurredAt    time.Time `json:"occurred_at"`
+	BookingID     string    `json:"booking_id"`
+	EventID       string    `json:"event_id"`
+	UserID        string    `json:"user_id"`
+	TicketsBooked int       `json:"tickets_booked"`
+	Status        string    `json:"status"`
+	BookedAt      time.Time `json:"booked_at"`
+	PriceCents    int64     `json:"price_cents"`
+	Currency      string    `json:"currency,omitempty"`
+}
+
+func (s *HTTPWebhookSender) Send(ctx context.Context, hook *domain.EventWebhook, notification domain.WebhookNotification) error {
+	booking := notification.Booking
+	payload, err := json.Marshal(webhookPayload{
+		Type:          notification.Type,
+		OccurredAt:    notification.OccurredAt,
+		BookingID:     booking.ID.String(),
+		EventID:       booking.EventID.String(),
+		UserID:        booking.UserID.String(),
+		TicketsBooked: booking.TicketsBooked,
+		Status:        string(booking.Status),
+		BookedAt:      booking.BookedAt,
+		PriceCents:    booking.PriceCents,
+		Currency:      booking.Currency,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	if err := s.send(ctx, hook, payload); err != nil {
+		WebhookDeliveries.WithLabelValues("failed").Inc()
+		return err
+	}
+	WebhookDeliveries.WithLabelValues("delivered").Inc()
+	return nil
+}
+
+func (s *HTTPWebhookSender) send(ctx context.Context, hook *domain.EventWebhook, payload []byte) error {
+	deliveryID := uuid.NewString()
+	backoff := s.backoff
+	for attempt := 1; ; attempt++ {
+		retryable, err := s.deliver(ctx, hook, deliveryID, payload)
+		if err == nil || !retryable || attempt >= s.attempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w, retry abandoned: %w", err, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// deliver makes one delivery attempt and reports whether a failure is worth retrying
+func (s *HTTPWebhookSender) deliver(ctx context.Context, hook *domain.EventWebhook, deliveryID string, payload []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return false, fmt.Errorf("failed to build webhook request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookDeliveryHeader, deliveryID)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(hook.Secret, timestamp, payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return !errors.Is(err, errInternalWebhookAddress), fmt.Errorf("failed to deliver webhook: %w", err)
+	}
+	// Drain the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+}
@@ -0,0 +1,124 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedDelivery struct {
+	header http.Header
+	body   []byte
+}
+
+// webhookReceiver answers deliveries with the given statuses in turn, then with 200, and records them
+type webhookReceiver struct {
+	mu         sync.Mutex
+	statuses   []int
+	deliveries []recordedDelivery
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries = append(r.deliveries, recordedDelivery{header: req.Header.Clone(), body: body})
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func TestHTTPWebhookSender_Send(t *testing.T) {
+	booking := &domain.Booking{
+		ID:            uuid.New(),
+		EventID:       uuid.New(),
+		UserID:        uuid.New(),
+		TicketsBooked: 2,
+		Status:        domain.BookingStatusConfirmed,
+		BookedAt:      time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC),
+		PriceCents:    5000,
+		Currency:      "EUR",
+	}
+	notification := domain.WebhookNotification{Type: domain.WebhookBookingCreated, Booking: booking, OccurredAt: booking.BookedAt}
+	ctx := context.Background()
+
+	send := func(t *testing.T, statuses ...int) (*webhookReceiver, error) {
+		t.Helper()
+		receiver := &webhookReceiver{statuses: statuses}
+		server := httptest.NewServer(receiver)
+		t.Cleanup(server.Close)
+
+		hook := &domain.EventWebhook{ID: uuid.New(), EventID: booking.EventID, URL: server.URL, Secret: "0123456789abcdef"}
+		sender := NewHTTPWebhookSender(WithWebhookRetries(3, time.Millisecond), WithWebhookInternalHosts())
+		return receiver, sender.Send(ctx, hook, notification)
+	}
+
+	t.Run("signs the payload", func(t *testing.T) {
+		receiver, err := send(t)
+		require.NoError(t, err)
+		require.Len(t, receiver.deliveries, 1)
+
+		delivery := receiver.deliveries[0]
+		timestamp, err := strconv.ParseInt(delivery.header.Get(WebhookTimestampHeader), 10, 64)
+		require.NoError(t, err)
+		assert.Equal(t, SignWebhookPayload("0123456789abcdef", timestamp, delivery.body), delivery.header.Get(WebhookSignatureHeader))
+		assert.NotEqual(t, SignWebhookPayload("another-secret-value", timestamp, delivery.body), delivery.header.Get(WebhookSignatureHeader))
+
+		var payload map[string]any
+		require.NoError(t, json.Unmarshal(delivery.body, &payload))
+		assert.Equal(t, "booking.created", payload["type"])
+		assert.Equal(t, booking.ID.String(), payload["booking_id"])
+		assert.Equal(t, float64(2), payload["tickets_booked"])
+	})
+
+	t.Run("retries server errors with one delivery ID", func(t *testing.T) {
+		receiver, err := send(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+		require.NoError(t, err)
+		require.Len(t, receiver.deliveries, 3)
+
+		deliveryID := receiver.deliveries[0].header.Get(WebhookDeliveryHeader)
+		assert.NotEmpty(t, deliveryID)
+		for _, delivery := range receiver.deliveries {
+			assert.Equal(t, deliveryID, delivery.header.Get(WebhookDeliveryHeader))
+		}
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		receiver, err := send(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+		assert.ErrorContains(t, err, "status 500")
+		assert.Len(t, receiver.deliveries, 3)
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		receiver, err := send(t, http.StatusGone)
+		assert.ErrorContains(t, err, "status 410")
+		assert.Len(t, receiver.deliveries, 1)
+	})
+
+	t.Run("refuses internal addresses without retrying", func(t *testing.T) {
+		receiver := &webhookReceiver{}
+		server := httptest.NewServer(receiver)
+		t.Cleanup(server.Close)
+
+		hook := &domain.EventWebhook{ID: uuid.New(), EventID: booking.EventID, URL: server.URL, Secret: "0123456789abcdef"}
+		sender := NewHTTPWebhookSender(WithWebhookRetries(3, time.Second))
+		err := sender.Send(ctx, hook, notification)
+
+		assert.ErrorIs(t, err, errInternalWebhookAddress)
+		assert.Empty(t, receiver.deliveries)
+	})
+}
@@ -0,0 +1,73 @@
+package infrastructure
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// WorkerStatus is a snapshot of a background worker's liveness
+type WorkerStatus struct {
+	Name          string
+	LastHeartbeat time.Time
+	MaxSilence    time.Duration
+	Healthy       bool
+}
+
+type workerState struct {
+	lastHeartbeat time.Time
+	maxSilence    time.Duration
+}
+
+// WorkerRegistry tracks heartbeats of background workers so a worker that silently stops is visible
+// A worker is healthy while its last heartbeat is no older than the maxSilence it registered with
+type WorkerRegistry struct {
+	mu      sync.RWMutex
+	workers map[string]*workerState
+	now     func() time.Time
+}
+
+func NewWorkerRegistry() *WorkerRegistry {
+	return &WorkerRegistry{
+		workers: make(map[string]*workerState),
+		now:     time.Now,
+	}
+}
+
+// Register adds a worker; registration counts as a heartbeat so the worker has maxSilence to start
+func (r *WorkerRegistry) Register(name string, maxSilence time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.workers[name] = &workerState{lastHeartbeat: r.now(), maxSilence: maxSilence}
+}
+
+// Heartbeat records that the worker completed a run successfully; unknown workers are ignored
+func (r *WorkerRegistry) Heartbeat(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if worker, ok := r.workers[name]; ok {
+		worker.lastHeartbeat = r.now()
+	}
+}
+
+// Statuses returns every registered worker ordered by name
+func (r *WorkerRegistry) Statuses() []WorkerStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := r.now()
+	statuses := make([]WorkerStatus, 0, len(r.workers))
+	for name, worker := range r.workers {
+		statuses = append(statuses, WorkerStatus{
+			Name:          name,
+			LastHeartbeat: worker.lastHeartbeat,
+			MaxSilence:    worker.maxSilence,
+			Healthy:       now.Sub(worker.lastHeartbeat) <= worker.maxSilence,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
@@ -0,0 +1,31 @@
+package infrastructure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerRegistry_Statuses(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	registry := NewWorkerRegistry()
+	registry.now = func() time.Time { return now }
+
+	registry.Register("outbox_poller", time.Minute)
+	registry.Register("hold_sweeper", time.Minute)
+
+	now = now.Add(50 * time.Second)
+	registry.Heartbeat("hold_sweeper")
+	registry.Heartbeat("unknown_worker")
+
+	now = now.Add(20 * time.Second)
+	statuses := registry.Statuses()
+
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "hold_sweeper", statuses[0].Name)
+	assert.True(t, statuses[0].Healthy)
+	assert.Equal(t, "outbox_poller", statuses[1].Name)
+	assert.False(t, statuses[1].Healthy, "worker silent for longer than max silence must be degraded")
+}
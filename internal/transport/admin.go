@@ -0,0 +1,166 @@
+package transport
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/labstack/echo/v4"
+)
+
+// DefaultAdminName is the acting admin of requests authenticated by the shared ADMIN_TOKEN, and of every
+// admin request while admin routes are open
+const DefaultAdminName = "admin"
+
+// adminContextKey holds the name of the admin the request was authenticated as
+const adminContextKey = "admin"
+
+// AdminAuthMiddleware requires "Authorization: Bearer <token>" on admin routes
+// An empty token leaves the routes open, which is only suitable for local development
+func AdminAuthMiddleware(token string) echo.MiddlewareFunc {
+	tokens := make(map[string]string)
+	if token != "" {
+		tokens[DefaultAdminName] = token
+	}
+	return NamedAdminAuthMiddleware(tokens)
+}
+
+// NamedAdminAuthMiddleware requires the bearer token of one of the admins in tokens, keyed by admin name,
+// and records that admin as the request's actor, e.g. the created_by of bookings made on a user's behalf
+// No tokens leave the routes open, which is only suitable for local development
+func NamedAdminAuthMiddleware(tokens map[string]string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if len(tokens) == 0 {
+				c.Set(adminContextKey, DefaultAdminName)
+				return next(c)
+			}
+
+			provided, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			admin := ""
+			for name, token := range tokens {
+				if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1 {
+					admin = name
+				}
+			}
+			if !ok || admin == "" {
+				return writeError(c, http.StatusUnauthorized, problemUnauthorized, "admin token required")
+			}
+
+			c.Set(adminContextKey, admin)
+			return next(c)
+		}
+	}
+}
+
+// authenticatedAdmin returns the admin the request was authenticated as by NamedAdminAuthMiddleware
+func authenticatedAdmin(c echo.Context) string {
+	admin, _ := c.Get(adminContextKey).(string)
+	return admin
+}
+
+// ParseAdminTokens parses comma-separated "<admin>=<token>" pairs, naming the admin each token authenticates
+func ParseAdminTokens(raw string) (map[string]string, error) {
+	tokens := make(map[string]string)
+	admins := make(map[string]string)
+	for i, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, token, ok := strings.Cut(entry, "=")
+		name, token = strings.TrimSpace(name), strings.TrimSpace(token)
+		if !ok || name == "" || token == "" {
+			// The entry is not echoed, as it may be a bare token
+			return nil, fmt.Errorf("invalid admin token entry %d, want <admin>=<token>", i+1)
+		}
+		if _, ok := tokens[name]; ok {
+			return nil, fmt.Errorf("admin %q has more than one token", name)
+		}
+		if other, ok := admins[token]; ok {
+			return nil, fmt.Errorf("admins %q and %q share a token", other, name)
+		}
+		tokens[name] = token
+		admins[token] = name
+	}
+	return tokens, nil
+}
+
+// StatementTimeoutMiddleware raises the statement timeout for the route's transactions and exports
+// Admin operations scan whole tables and would otherwise be cancelled by the connection's timeout
+func StatementTimeoutMiddleware(timeout time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			c.SetRequest(req.WithContext(infrastructure.WithStatementTimeout(req.Context(), timeout)))
+			return next(c)
+		}
+	}
+}
+
+type MemoryStatsResponse struct {
+	AllocBytes      uint64 `json:"alloc_bytes"`
+	TotalAllocBytes uint64 `json:"total_alloc_bytes"`
+	SysBytes        uint64 `json:"sys_bytes"`
+	HeapObjects     uint64 `json:"heap_objects"`
+	NumGC           uint32 `json:"num_gc"`
+	PauseTotalNs    uint64 `json:"pause_total_ns"`
+}
+
+type DBPoolStatsResponse struct {
+	MaxOpenConnections int    `json:"max_open_connections"`
+	OpenConnections    int    `json:"open_connections"`
+	InUse              int    `json:"in_use"`
+	Idle               int    `json:"idle"`
+	WaitCount          int64  `json:"wait_count"`
+	WaitDuration       string `json:"wait_duration"`
+	MaxIdleClosed      int64  `json:"max_idle_closed"`
+	MaxLifetimeClosed  int64  `json:"max_lifetime_closed"`
+}
+
+type RuntimeStatsResponse struct {
+	Goroutines int                 `json:"goroutines"`
+	Memory     MemoryStatsResponse `json:"memory"`
+	DBPool     DBPoolStatsResponse `json:"db_pool"`
+}
+
+// runtimeStatsHandler returns goroutine, memory and connection pool statistics for a quick on-call glance
+// It is read-only; ReadMemStats briefly stops the world, so it is not meant for high-frequency polling
+func runtimeStatsHandler(db infrastructure.DBClient) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var mem runtime.MemStats
+		runtime.ReadMemStats(&mem)
+
+		return c.JSON(http.StatusOK, RuntimeStatsResponse{
+			Goroutines: runtime.NumGoroutine(),
+			Memory: MemoryStatsResponse{
+				AllocBytes:      mem.Alloc,
+				TotalAllocBytes: mem.TotalAlloc,
+				SysBytes:        mem.Sys,
+				HeapObjects:     mem.HeapObjects,
+				NumGC:           mem.NumGC,
+				PauseTotalNs:    mem.PauseTotalNs,
+			},
+			DBPool: toDBPoolStatsResponse(db.Stats()),
+		})
+	}
+}
+
+func toDBPoolStatsResponse(pool sql.DBStats) DBPoolStatsResponse {
+	return DBPoolStatsResponse{
+		MaxOpenConnections: pool.MaxOpenConnections,
+		OpenConnections:    pool.OpenConnections,
+		InUse:              pool.InUse,
+		Idle:               pool.Idle,
+		WaitCount:          pool.WaitCount,
+		WaitDuration:       pool.WaitDuration.String(),
+		MaxIdleClosed:      pool.MaxIdleClosed,
+		MaxLifetimeClosed:  pool.MaxLifetimeClosed,
+	}
+}
@@ -0,0 +1,162 @@
+package transport
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePoolStats struct {
+	infrastructure.DBClient
+	stats sql.DBStats
+}
+
+func (db *fakePoolStats) Stats() sql.DBStats {
+	return db.stats
+}
+
+func TestRuntimeStatsHandler(t *testing.T) {
+	db := &fakePoolStats{stats: sql.DBStats{
+		MaxOpenConnections: 25,
+		OpenConnections:    3,
+		InUse:              1,
+		Idle:               2,
+		WaitCount:          4,
+		WaitDuration:       150 * time.Millisecond,
+	}}
+
+	tests := []struct {
+		name     string
+		token    string
+		header   string
+		wantCode int
+	}{
+		{name: "open when no token is configured", wantCode: http.StatusOK},
+		{name: "accepts matching bearer token", token: "s3cret", header: "Bearer s3cret", wantCode: http.StatusOK},
+		{name: "rejects missing token", token: "s3cret", wantCode: http.StatusUnauthorized},
+		{name: "rejects wrong token", token: "s3cret", header: "Bearer nope", wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Group("/admin", AdminAuthMiddleware(tt.token)).GET("/debug/runtime", runtimeStatsHandler(db))
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/debug/runtime", nil)
+			if tt.header != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var resp RuntimeStatsResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Positive(t, resp.Goroutines)
+			assert.Positive(t, resp.Memory.SysBytes)
+			assert.Equal(t, DBPoolStatsResponse{
+				MaxOpenConnections: 25,
+				OpenConnections:    3,
+				InUse:              1,
+				Idle:               2,
+				WaitCount:          4,
+				WaitDuration:       "150ms",
+			}, resp.DBPool)
+		})
+	}
+}
+
+func TestRouter_UserCancellationRequiresAdmin(t *testing.T) {
+	router := NewRouter(nil, nil, nil, nil, infrastructure.NewWorkerRegistry(), zerolog.Nop(), WithAdminToken("s3cret"))
+	userID := uuid.New().String()
+
+	for path, wantCode := range map[string]int{
+		"/admin/users/" + userID + "/cancel-bookings": http.StatusUnauthorized,
+		"/users/" + userID + "/cancel-bookings":       http.StatusNotFound,
+	} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+		assert.Equal(t, wantCode, rec.Code, path)
+	}
+}
+
+func TestRouter_LotteryDrawRequiresAdmin(t *testing.T) {
+	lottery := app.NewLotteryService(nil, nil, nil, nil, nil, zerolog.Nop())
+	router := NewRouter(nil, nil, nil, nil, infrastructure.NewWorkerRegistry(), zerolog.Nop(), WithAdminToken("s3cret"), WithLottery(lottery))
+	eventID := uuid.New().String()
+
+	for path, wantCode := range map[string]int{
+		"/admin/events/" + eventID + "/lottery/draw": http.StatusUnauthorized,
+		"/events/" + eventID + "/lottery/draw":       http.StatusNotFound,
+	} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+		assert.Equal(t, wantCode, rec.Code, path)
+	}
+}
+
+func TestRouter_SplitBookingRequiresAdmin(t *testing.T) {
+	router := NewRouter(nil, nil, nil, nil, infrastructure.NewWorkerRegistry(), zerolog.Nop(), WithAdminToken("s3cret"))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/bookings/"+uuid.New().String()+"/split", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestNamedAdminAuthMiddleware(t *testing.T) {
+	serve := func(tokens map[string]string, header string) *httptest.ResponseRecorder {
+		e := echo.New()
+		e.GET("/admin/whoami", func(c echo.Context) error {
+			return c.String(http.StatusOK, authenticatedAdmin(c))
+		}, NamedAdminAuthMiddleware(tokens))
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/whoami", nil)
+		if header != "" {
+			req.Header.Set(echo.HeaderAuthorization, header)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	tokens := map[string]string{"jane@support": "t0ken", "joe@support": "s3cret"}
+
+	rec := serve(tokens, "Bearer s3cret")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "joe@support", rec.Body.String(), "the token names the acting admin")
+
+	assert.Equal(t, http.StatusUnauthorized, serve(tokens, "Bearer nope").Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(tokens, "s3cret").Code)
+
+	rec = serve(nil, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, DefaultAdminName, rec.Body.String())
+}
+
+func TestParseAdminTokens(t *testing.T) {
+	tokens, err := ParseAdminTokens(" jane@support=t0ken, joe@support = s3cret ,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"jane@support": "t0ken", "joe@support": "s3cret"}, tokens)
+
+	for _, raw := range []string{"s3cret", "=s3cret", "jane=", "jane=a,jane=b", "jane=a,joe=a"} {
+		_, err := ParseAdminTokens(raw)
+		assert.Error(t, err, raw)
+	}
+	_, err = ParseAdminTokens("jane=t0ken,s3cret")
+	assert.NotContains(t, err.Error(), "s3cret", "a bare token is not echoed")
+}
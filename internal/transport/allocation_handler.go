@@ -0,0 +1,90 @@
+package transport
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+type AllocationHandler struct {
+	service *app.AllocationService
+	logger  zerolog.Logger
+}
+
+func NewAllocationHandler(service *app.AllocationService, logger zerolog.Logger) *AllocationHandler {
+	return &AllocationHandler{
+		service: service,
+		logger:  logger.With().Str("handler", "allocation").Logger(),
+	}
+}
+
+type CreateAllocationRequest struct {
+	Name    string `json:"name"`
+	Tickets int    `json:"tickets"`
+}
+
+type AllocationResponse struct {
+	ID               string `json:"id"`
+	EventID          string `json:"event_id"`
+	Name             string `json:"name"`
+	Tickets          int    `json:"tickets"`
+	AvailableTickets int    `json:"available_tickets"`
+}
+
+// CreateAllocation sets tickets of the event aside from public sale in a named block, e.g. for the press
+func (h *AllocationHandler) CreateAllocation(c echo.Context) error {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid event id")
+	}
+
+	var req CreateAllocationRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error().Err(err).Msg("failed to bind request")
+		return badRequest(c, "invalid request body")
+	}
+
+	allocation, err := h.service.CreateAllocation(c.Request().Context(), app.CreateAllocationRequest{
+		EventID: eventID,
+		Name:    req.Name,
+		Tickets: req.Tickets,
+	})
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, toAllocationResponse(allocation))
+}
+
+// ListAllocations returns the event's allocations with the tickets left in each
+func (h *AllocationHandler) ListAllocations(c echo.Context) error {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid event id")
+	}
+
+	allocations, err := h.service.ListAllocations(c.Request().Context(), eventID)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	response := make([]AllocationResponse, len(allocations))
+	for i, allocation := range allocations {
+		response[i] = toAllocationResponse(allocation)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+func toAllocationResponse(allocation *domain.Allocation) AllocationResponse {
+	return AllocationResponse{
+		ID:               allocation.ID.String(),
+		EventID:          allocation.EventID.String(),
+		Name:             allocation.Name,
+		Tickets:          allocation.Tickets,
+		AvailableTickets: allocation.AvailableTickets,
+	}
+}
@@ -1,11 +1,17 @@
 package transport
 
 import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
 	"github.com/jorzel/booking-service/internal/infrastructure"
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog"
@@ -13,28 +19,173 @@ import (
 
 type BookingHandler struct {
 	service *app.BookingService
+	holds   *app.HoldService
 	logger  zerolog.Logger
 }
 
-func NewBookingHandler(service *app.BookingService, logger zerolog.Logger) *BookingHandler {
+func NewBookingHandler(service *app.BookingService, holds *app.HoldService, logger zerolog.Logger) *BookingHandler {
 	return &BookingHandler{
 		service: service,
+		holds:   holds,
 		logger:  logger.With().Str("handler", "booking").Logger(),
 	}
 }
 
+// defaultCodeSearchLimit keeps confirmation code searches to a page support agents can scan by eye
+const defaultCodeSearchLimit = 10
+
+// idempotencyKeyHeader carries a client-chosen key that makes retried booking requests safe
+const idempotencyKeyHeader = "Idempotency-Key"
+
 type CreateBookingRequest struct {
 	EventID       string `json:"event_id" validate:"required"`
 	UserID        string `json:"user_id" validate:"required"`
 	TicketsBooked int    `json:"tickets_booked" validate:"required,min=1"`
+	Conditional   bool   `json:"conditional,omitempty"`
+	// Seats selects specific seats at reserved-seating events; tickets_booked defaults to their count
+	Seats        []string `json:"seats,omitempty"`
+	DiscountCode string   `json:"discount_code,omitempty"`
+	// PayCurrency pays in a currency other than the event's, converted at the current exchange rate
+	PayCurrency string `json:"pay_currency,omitempty"`
+	// Allocation books from a named block of the event's tickets, e.g. "press", instead of public sale
+	Allocation string `json:"allocation,omitempty"`
+	// HoldID confirms the user's hold instead; the event and tickets are taken from the hold
+	HoldID string `json:"hold_id,omitempty"`
+	// ExpiresIn, a Go duration such as "15m", reserves the tickets as a pending booking to confirm within it
+	ExpiresIn string `json:"expires_in,omitempty"`
 }
 
 type BookingResponse struct {
-	ID            string    `json:"id"`
-	EventID       string    `json:"event_id"`
-	UserID        string    `json:"user_id"`
+	ID               string    `json:"id"`
+	EventID          string    `json:"event_id"`
+	UserID           string    `json:"user_id"`
+	TicketsBooked    int       `json:"tickets_booked"`
+	BookedAt         time.Time `json:"booked_at"`
+	Status           string    `json:"status"`
+	Conditional      bool      `json:"conditional"`
+	Seats            []string  `json:"seats,omitempty"`
+	ConfirmationCode string    `json:"confirmation_code"`
+	PriceCents       int64     `json:"price_cents"`
+	Currency         string    `json:"currency,omitempty"`
+	DiscountCode     string    `json:"discount_code,omitempty"`
+	CreatedBy        string    `json:"created_by,omitempty"`
+	PayPriceCents    int64     `json:"pay_price_cents,omitempty"`
+	PayCurrency      string    `json:"pay_currency,omitempty"`
+	FXRate           float64   `json:"fx_rate,omitempty"`
+	AllocationID     string    `json:"allocation_id,omitempty"`
+	// RefundCents is what cancelling the booking refunded, in Currency
+	RefundCents int64 `json:"refund_cents,omitempty"`
+	// ExpiresAt is when a pending reservation is released unless confirmed
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// EventAvailableTickets is how many tickets public sale had left once the booking was made, returned
+	// when the booking is created so clients need no follow-up read of the event
+	EventAvailableTickets *int `json:"event_available_tickets,omitempty"`
+}
+
+// UserBookingResponse is a booking in GET /users/{id}/bookings, with what cancelling it now would refund
+type UserBookingResponse struct {
+	BookingResponse
+	RefundEligible bool  `json:"refund_eligible"`
+	RefundAmount   int64 `json:"refund_amount"` // In minor units of the booking's currency
+}
+
+type BookingChangeResponse struct {
+	Action        string    `json:"action"`
+	Description   string    `json:"description"`
+	Status        string    `json:"status"`
 	TicketsBooked int       `json:"tickets_booked"`
-	BookedAt      time.Time `json:"booked_at"`
+	Actor         string    `json:"actor,omitempty"`
+	ChangedAt     time.Time `json:"changed_at"`
+}
+
+type BookingHistoryResponse struct {
+	BookingID string                  `json:"booking_id"`
+	History   []BookingChangeResponse `json:"history"`
+}
+
+// LedgerEntryResponse is one booking change in an event's ledger with the availability it left
+type LedgerEntryResponse struct {
+	BookingID      string    `json:"booking_id"`
+	Action         string    `json:"action"`
+	Description    string    `json:"description"`
+	TicketsBooked  int       `json:"tickets_booked"`
+	Delta          int       `json:"delta"`
+	AvailableAfter int       `json:"available_after"`
+	ChangedAt      time.Time `json:"changed_at"`
+}
+
+type CartItemRequest struct {
+	EventID       string `json:"event_id"`
+	TicketsBooked int    `json:"tickets_booked"`
+}
+
+type CreateBookingsRequest struct {
+	UserID string            `json:"user_id"`
+	Items  []CartItemRequest `json:"items"`
+}
+
+type BuyoutRequest struct {
+	UserID string `json:"user_id"`
+}
+
+type SplitBookingRequest struct {
+	SplitCount int    `json:"split_count"`
+	NewUserID  string `json:"new_user_id"`
+}
+
+// SplitBookingResponse holds the original booking, reduced by the split, and the new booking split off it
+type SplitBookingResponse struct {
+	Original BookingResponse `json:"original"`
+	Split    BookingResponse `json:"split"`
+}
+
+type BookingsResponse struct {
+	Bookings []BookingResponse `json:"bookings"`
+}
+
+// CreateDiscountCodeRequest sets exactly one of PercentOff and AmountOffCents; Currency goes with AmountOffCents
+type CreateDiscountCodeRequest struct {
+	Code           string     `json:"code"`
+	PercentOff     int        `json:"percent_off,omitempty"`
+	AmountOffCents int64      `json:"amount_off_cents,omitempty"`
+	Currency       string     `json:"currency,omitempty"`
+	MaxUses        int        `json:"max_uses"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+}
+
+type DiscountCodeResponse struct {
+	Code           string     `json:"code"`
+	PercentOff     int        `json:"percent_off,omitempty"`
+	AmountOffCents int64      `json:"amount_off_cents,omitempty"`
+	Currency       string     `json:"currency,omitempty"`
+	MaxUses        int        `json:"max_uses"`
+	Uses           int        `json:"uses"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+}
+
+type ConditionalResolutionResponse struct {
+	EventID     string `json:"event_id"`
+	Viable      bool   `json:"viable"`
+	Confirmed   int    `json:"confirmed"`
+	Cancelled   int    `json:"cancelled"`
+	RefundCents int64  `json:"refund_cents"` // Total refunded for the cancelled bookings, in the event's currency
+}
+
+// BookingRefundResponse is what cancelling one booking refunded
+type BookingRefundResponse struct {
+	BookingID   string `json:"booking_id"`
+	RefundCents int64  `json:"refund_cents"`
+	Currency    string `json:"currency,omitempty"`
+}
+
+// UserCancellationResponse summarizes POST /admin/users/{id}/cancel-bookings: how many bookings, tickets
+// and events the call cancelled and what each booking refunded; a repeated call reports zeros
+type UserCancellationResponse struct {
+	UserID          string                  `json:"user_id"`
+	Cancelled       int                     `json:"cancelled"`
+	TicketsReleased int                     `json:"tickets_released"`
+	Events          int                     `json:"events"`
+	Refunds         []BookingRefundResponse `json:"refunds"`
 }
 
 func (h *BookingHandler) CreateBooking(c echo.Context) error {
@@ -42,25 +193,109 @@ func (h *BookingHandler) CreateBooking(c echo.Context) error {
 	if err := c.Bind(&req); err != nil {
 		h.logger.Error().Err(err).Msg("failed to bind request")
 		infrastructure.BookingsCreated.WithLabelValues("error").Inc()
-		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+		return badRequest(c, "invalid request body")
+	}
+	if req.HoldID != "" {
+		return h.confirmHold(c, req)
+	}
+
+	return h.createBooking(c, req, h.service.CreateBooking)
+}
+
+// confirmHold books the caller's hold in place of a booking request; an expired hold yields 410 Gone
+func (h *BookingHandler) confirmHold(c echo.Context, req CreateBookingRequest) error {
+	holdID, err := uuid.Parse(req.HoldID)
+	if err != nil {
+		infrastructure.BookingsCreated.WithLabelValues("error").Inc()
+		return badRequest(c, "invalid hold_id")
+	}
+
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		infrastructure.BookingsCreated.WithLabelValues("error").Inc()
+		return badRequest(c, "invalid user_id")
+	}
+
+	if req.EventID != "" || req.TicketsBooked != 0 || len(req.Seats) > 0 || req.Conditional || req.DiscountCode != "" || req.PayCurrency != "" || req.Allocation != "" || req.ExpiresIn != "" {
+		infrastructure.BookingsCreated.WithLabelValues("error").Inc()
+		return badRequest(c, "hold_id cannot be combined with other booking fields")
+	}
+
+	booking, err := h.holds.ConfirmHoldForUser(c.Request().Context(), holdID, userID)
+	if err != nil {
+		infrastructure.BookingsCreated.WithLabelValues("error").Inc()
+		return handleError(c, err)
+	}
+
+	infrastructure.BookingsCreated.WithLabelValues("success").Inc()
+	infrastructure.TicketsBooked.Add(float64(booking.TicketsBooked))
+
+	return c.JSON(http.StatusCreated, toBookingResponse(booking))
+}
+
+// AdminCreateBooking books for any user on behalf of support staff, recording the admin the request was
+// authenticated as in created_by
+func (h *BookingHandler) AdminCreateBooking(c echo.Context) error {
+	var req CreateBookingRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error().Err(err).Msg("failed to bind request")
+		infrastructure.BookingsCreated.WithLabelValues("error").Inc()
+		return badRequest(c, "invalid request body")
+	}
+
+	if req.HoldID != "" {
+		infrastructure.BookingsCreated.WithLabelValues("error").Inc()
+		return badRequest(c, "hold_id is not supported for admin bookings")
 	}
 
+	admin := authenticatedAdmin(c)
+	return h.createBooking(c, req, func(ctx context.Context, booking app.CreateBookingRequest) (*domain.Booking, error) {
+		return h.service.CreateBookingOnBehalf(ctx, admin, booking)
+	})
+}
+
+// createBooking validates a booking request body and books it with create
+func (h *BookingHandler) createBooking(
+	c echo.Context,
+	req CreateBookingRequest,
+	create func(context.Context, app.CreateBookingRequest) (*domain.Booking, error),
+) error {
 	eventID, err := uuid.Parse(req.EventID)
 	if err != nil {
 		infrastructure.BookingsCreated.WithLabelValues("error").Inc()
-		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid event_id"})
+		return badRequest(c, "invalid event_id")
 	}
 
 	userID, err := uuid.Parse(req.UserID)
 	if err != nil {
 		infrastructure.BookingsCreated.WithLabelValues("error").Inc()
-		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid user_id"})
+		return badRequest(c, "invalid user_id")
+	}
+
+	if req.TicketsBooked == 0 {
+		req.TicketsBooked = len(req.Seats)
 	}
 
-	booking, err := h.service.CreateBooking(c.Request().Context(), app.CreateBookingRequest{
-		EventID:       eventID,
-		UserID:        userID,
-		TicketsBooked: req.TicketsBooked,
+	var expiresIn time.Duration
+	if req.ExpiresIn != "" {
+		expiresIn, err = time.ParseDuration(req.ExpiresIn)
+		if err != nil {
+			infrastructure.BookingsCreated.WithLabelValues("error").Inc()
+			return badRequest(c, "invalid expires_in")
+		}
+	}
+
+	booking, err := create(c.Request().Context(), app.CreateBookingRequest{
+		EventID:        eventID,
+		UserID:         userID,
+		TicketsBooked:  req.TicketsBooked,
+		Conditional:    req.Conditional,
+		Seats:          req.Seats,
+		IdempotencyKey: c.Request().Header.Get(idempotencyKeyHeader),
+		DiscountCode:   req.DiscountCode,
+		PayCurrency:    req.PayCurrency,
+		Allocation:     req.Allocation,
+		ExpiresIn:      expiresIn,
 	})
 	if err != nil {
 		infrastructure.BookingsCreated.WithLabelValues("error").Inc()
@@ -70,19 +305,86 @@ func (h *BookingHandler) CreateBooking(c echo.Context) error {
 	infrastructure.BookingsCreated.WithLabelValues("success").Inc()
 	infrastructure.TicketsBooked.Add(float64(booking.TicketsBooked))
 
-	return c.JSON(http.StatusCreated, BookingResponse{
-		ID:            booking.ID.String(),
-		EventID:       booking.EventID.String(),
-		UserID:        booking.UserID.String(),
-		TicketsBooked: booking.TicketsBooked,
-		BookedAt:      booking.BookedAt,
-	})
+	return c.JSON(http.StatusCreated, toBookingResponse(booking))
+}
+
+// CreateBookings books several events for one user atomically
+func (h *BookingHandler) CreateBookings(c echo.Context) error {
+	var req CreateBookingsRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error().Err(err).Msg("failed to bind request")
+		infrastructure.BookingsCreated.WithLabelValues("error").Inc()
+		return badRequest(c, "invalid request body")
+	}
+
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		infrastructure.BookingsCreated.WithLabelValues("error").Inc()
+		return badRequest(c, "invalid user_id")
+	}
+
+	items := make([]app.CartItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		eventID, err := uuid.Parse(item.EventID)
+		if err != nil {
+			infrastructure.BookingsCreated.WithLabelValues("error").Inc()
+			return badRequest(c, "invalid event_id")
+		}
+		items = append(items, app.CartItem{EventID: eventID, TicketsBooked: item.TicketsBooked})
+	}
+
+	bookings, err := h.service.CreateBookings(c.Request().Context(), app.CreateBookingsRequest{UserID: userID, Items: items})
+	if err != nil {
+		infrastructure.BookingsCreated.WithLabelValues("error").Inc()
+		return handleError(c, err)
+	}
+
+	response := BookingsResponse{Bookings: make([]BookingResponse, 0, len(bookings))}
+	for _, booking := range bookings {
+		infrastructure.BookingsCreated.WithLabelValues("success").Inc()
+		infrastructure.TicketsBooked.Add(float64(booking.TicketsBooked))
+		response.Bookings = append(response.Bookings, toBookingResponse(booking))
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// Buyout books every ticket still available for the event to one user
+func (h *BookingHandler) Buyout(c echo.Context) error {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid event id")
+	}
+
+	var req BuyoutRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error().Err(err).Msg("failed to bind request")
+		infrastructure.BookingsCreated.WithLabelValues("error").Inc()
+		return badRequest(c, "invalid request body")
+	}
+
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		infrastructure.BookingsCreated.WithLabelValues("error").Inc()
+		return badRequest(c, "invalid user_id")
+	}
+
+	booking, err := h.service.Buyout(c.Request().Context(), eventID, userID)
+	if err != nil {
+		infrastructure.BookingsCreated.WithLabelValues("error").Inc()
+		return handleError(c, err)
+	}
+
+	infrastructure.BookingsCreated.WithLabelValues("success").Inc()
+	infrastructure.TicketsBooked.Add(float64(booking.TicketsBooked))
+
+	return c.JSON(http.StatusCreated, toBookingResponse(booking))
 }
 
 func (h *BookingHandler) GetBooking(c echo.Context) error {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid booking id"})
+		return badRequest(c, "invalid booking id")
 	}
 
 	booking, err := h.service.GetBooking(c.Request().Context(), id)
@@ -90,11 +392,363 @@ func (h *BookingHandler) GetBooking(c echo.Context) error {
 		return handleError(c, err)
 	}
 
-	return c.JSON(http.StatusOK, BookingResponse{
-		ID:            booking.ID.String(),
-		EventID:       booking.EventID.String(),
-		UserID:        booking.UserID.String(),
-		TicketsBooked: booking.TicketsBooked,
-		BookedAt:      booking.BookedAt,
+	return c.JSON(http.StatusOK, toBookingResponse(booking))
+}
+
+// ConfirmBooking confirms a pending reservation made with expires_in; an expired one yields 410 Gone
+func (h *BookingHandler) ConfirmBooking(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid booking id")
+	}
+
+	booking, err := h.service.ConfirmBooking(c.Request().Context(), id)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toBookingResponse(booking))
+}
+
+// SplitBooking moves some of the booking's tickets to a new booking for another user
+// It requires the admin token, as requests carry no user identity to check the booking's owner against
+func (h *BookingHandler) SplitBooking(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid booking id")
+	}
+
+	var req SplitBookingRequest
+	if err := c.Bind(&req); err != nil {
+		return badRequest(c, "invalid request body")
+	}
+	newUserID, err := uuid.Parse(req.NewUserID)
+	if err != nil {
+		return badRequest(c, "invalid new_user_id")
+	}
+
+	original, split, err := h.service.SplitBooking(c.Request().Context(), id, req.SplitCount, newUserID)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, SplitBookingResponse{
+		Original: toBookingResponse(original),
+		Split:    toBookingResponse(split),
+	})
+}
+
+// GetBookingHistory lists the booking's changes, oldest first
+func (h *BookingHandler) GetBookingHistory(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid booking id")
+	}
+
+	changes, err := h.service.GetBookingHistory(c.Request().Context(), id)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	history := make([]BookingChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		history = append(history, BookingChangeResponse{
+			Action:        string(change.Action),
+			Description:   change.Description(),
+			Status:        string(change.Status),
+			TicketsBooked: change.TicketsBooked,
+			Actor:         change.Actor,
+			ChangedAt:     change.ChangedAt,
+		})
+	}
+
+	return c.JSON(http.StatusOK, BookingHistoryResponse{BookingID: id.String(), History: history})
+}
+
+// SearchBookings finds bookings by a partial confirmation code, matched as a case-insensitive prefix
+func (h *BookingHandler) SearchBookings(c echo.Context) error {
+	page, err := parsePaginationWithDefault(c, defaultCodeSearchLimit)
+	if err != nil {
+		return badRequest(c, err.Error())
+	}
+
+	bookings, total, err := h.service.SearchByConfirmationCode(c.Request().Context(), c.QueryParam("code_prefix"), page.Limit, page.Offset)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newPagedResponse(bookings, total, page, toBookingResponse))
+}
+
+// CreateDiscountCode creates a discount code customers can redeem when booking
+func (h *BookingHandler) CreateDiscountCode(c echo.Context) error {
+	var req CreateDiscountCodeRequest
+	if err := c.Bind(&req); err != nil {
+		return badRequest(c, "invalid request body")
+	}
+
+	var expiresAt time.Time
+	if req.ExpiresAt != nil {
+		expiresAt = *req.ExpiresAt
+	}
+
+	code, err := h.service.CreateDiscountCode(c.Request().Context(), app.CreateDiscountCodeRequest{
+		Code:           req.Code,
+		PercentOff:     req.PercentOff,
+		AmountOffCents: req.AmountOffCents,
+		Currency:       req.Currency,
+		MaxUses:        req.MaxUses,
+		ExpiresAt:      expiresAt,
 	})
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	response := DiscountCodeResponse{
+		Code:           code.Code,
+		PercentOff:     code.PercentOff,
+		AmountOffCents: code.AmountOffCents,
+		Currency:       code.Currency,
+		MaxUses:        code.MaxUses,
+		Uses:           code.Uses,
+	}
+	if !code.ExpiresAt.IsZero() {
+		response.ExpiresAt = &code.ExpiresAt
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// ResolveConditionalBookings confirms or cancels the event's pending conditional bookings
+func (h *BookingHandler) ResolveConditionalBookings(c echo.Context) error {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid event id")
+	}
+
+	resolution, err := h.service.ResolveConditionalBookings(c.Request().Context(), eventID)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, ConditionalResolutionResponse{
+		EventID:     eventID.String(),
+		Viable:      resolution.Viable,
+		Confirmed:   resolution.Confirmed,
+		Cancelled:   resolution.Cancelled,
+		RefundCents: resolution.RefundCents,
+	})
+}
+
+// ListUserBookings pages through the user's bookings, newest first, with their refund eligibility
+func (h *BookingHandler) ListUserBookings(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid user id")
+	}
+
+	page, err := parsePagination(c)
+	if err != nil {
+		return badRequest(c, err.Error())
+	}
+
+	bookings, total, err := h.service.ListUserBookings(c.Request().Context(), userID, page.Limit, page.Offset)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newPagedResponse(bookings, total, page, toUserBookingResponse))
+}
+
+// GetEventLedger pages through the event's booking changes, oldest first, with a running available_after
+// starting from the event's total tickets
+func (h *BookingHandler) GetEventLedger(c echo.Context) error {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid event id")
+	}
+
+	page, err := parsePagination(c)
+	if err != nil {
+		return badRequest(c, err.Error())
+	}
+
+	entries, total, err := h.service.GetEventLedger(c.Request().Context(), eventID, page.Limit, page.Offset)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newPagedResponse(entries, total, page, toLedgerEntryResponse))
+}
+
+func toLedgerEntryResponse(entry *domain.LedgerEntry) LedgerEntryResponse {
+	return LedgerEntryResponse{
+		BookingID:      entry.BookingID.String(),
+		Action:         string(entry.Action),
+		Description:    entry.Description(),
+		TicketsBooked:  entry.TicketsBooked,
+		Delta:          entry.Delta,
+		AvailableAfter: entry.AvailableAfter,
+		ChangedAt:      entry.ChangedAt,
+	}
+}
+
+// CancelUserBookings cancels all of a user's bookings and returns their tickets; repeating it is a no-op
+// It is an admin route, as there is no user authentication to tell the user apart from anyone else
+func (h *BookingHandler) CancelUserBookings(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid user id")
+	}
+
+	summary, err := h.service.CancelAllForUser(c.Request().Context(), userID)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, UserCancellationResponse{
+		UserID:          userID.String(),
+		Cancelled:       summary.Cancelled,
+		TicketsReleased: summary.TicketsReleased,
+		Events:          summary.Events,
+		Refunds:         toBookingRefundResponses(summary.Refunds),
+	})
+}
+
+func toBookingRefundResponses(refunds []app.BookingRefund) []BookingRefundResponse {
+	responses := make([]BookingRefundResponse, len(refunds))
+	for i, refund := range refunds {
+		responses[i] = BookingRefundResponse{
+			BookingID:   refund.BookingID.String(),
+			RefundCents: refund.RefundCents,
+			Currency:    refund.Currency,
+		}
+	}
+	return responses
+}
+
+func toUserBookingResponse(booking *app.UserBooking) UserBookingResponse {
+	return UserBookingResponse{
+		BookingResponse: toBookingResponse(booking.Booking),
+		RefundEligible:  booking.Refund.Eligible,
+		RefundAmount:    booking.Refund.AmountCents,
+	}
+}
+
+func toBookingResponse(booking *domain.Booking) BookingResponse {
+	response := BookingResponse{
+		ID:                    booking.ID.String(),
+		EventID:               booking.EventID.String(),
+		UserID:                booking.UserID.String(),
+		TicketsBooked:         booking.TicketsBooked,
+		BookedAt:              booking.BookedAt,
+		Status:                string(booking.Status),
+		Conditional:           booking.Conditional,
+		Seats:                 booking.SeatLabels,
+		ConfirmationCode:      booking.ConfirmationCode,
+		PriceCents:            booking.PriceCents,
+		Currency:              booking.Currency,
+		DiscountCode:          booking.DiscountCode,
+		CreatedBy:             booking.CreatedBy,
+		PayPriceCents:         booking.PayPriceCents,
+		PayCurrency:           booking.PayCurrency,
+		FXRate:                booking.FXRate,
+		RefundCents:           booking.RefundCents,
+		EventAvailableTickets: booking.EventAvailableTickets,
+	}
+	if booking.AllocationID != uuid.Nil {
+		response.AllocationID = booking.AllocationID.String()
+	}
+	if booking.IsReservation() {
+		response.ExpiresAt = &booking.ExpiresAt
+	}
+	return response
+}
+
+// bookingCSVHeader lists the columns written by ExportBookings
+var bookingCSVHeader = []string{"id", "event_id", "user_id", "tickets_booked", "booked_at", "status"}
+
+// ExportBookings streams bookings matching the query filters as CSV
+// Rows are written as they are read from the database, so the export size is not bounded by memory
+func (h *BookingHandler) ExportBookings(c echo.Context) error {
+	filter, err := parseBookingFilter(c)
+	if err != nil {
+		return badRequest(c, err.Error())
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/csv")
+	res.Header().Set(echo.HeaderContentDisposition,
+		fmt.Sprintf(`attachment; filename="bookings-%s.csv"`, time.Now().UTC().Format("20060102T150405Z")))
+
+	w := csv.NewWriter(res)
+	if err := w.Write(bookingCSVHeader); err != nil {
+		return err
+	}
+
+	rows := 0
+	err = h.service.ExportBookings(c.Request().Context(), filter, func(booking *domain.Booking) error {
+		rows++
+		return w.Write([]string{
+			booking.ID.String(),
+			booking.EventID.String(),
+			booking.UserID.String(),
+			strconv.Itoa(booking.TicketsBooked),
+			booking.BookedAt.UTC().Format(time.RFC3339),
+			string(booking.Status),
+		})
+	})
+	w.Flush()
+	if err == nil {
+		err = w.Error()
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Int("rows", rows).Msg("booking export aborted")
+		if !res.Committed {
+			return handleError(c, err)
+		}
+		// Headers are already sent; dropping the connection is the only way to signal a truncated export
+		return err
+	}
+
+	return nil
+}
+
+// parseBookingFilter reads ?event_id=, ?user_id=, ?from= and ?to= (RFC 3339) into a BookingFilter
+func parseBookingFilter(c echo.Context) (domain.BookingFilter, error) {
+	var filter domain.BookingFilter
+
+	if raw := c.QueryParam("event_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return domain.BookingFilter{}, errors.New("invalid event_id")
+		}
+		filter.EventID = id
+	}
+
+	if raw := c.QueryParam("user_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return domain.BookingFilter{}, errors.New("invalid user_id")
+		}
+		filter.UserID = id
+	}
+
+	if raw := c.QueryParam("from"); raw != "" {
+		from, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return domain.BookingFilter{}, errors.New("invalid from, expected RFC 3339 timestamp")
+		}
+		filter.BookedFrom = from
+	}
+
+	if raw := c.QueryParam("to"); raw != "" {
+		to, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return domain.BookingFilter{}, errors.New("invalid to, expected RFC 3339 timestamp")
+		}
+		filter.BookedTo = to
+	}
+
+	return filter, nil
 }
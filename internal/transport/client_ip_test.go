@@ -0,0 +1,119 @@
+package transport
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggingMiddleware_LogsClientIPAndUserAgent(t *testing.T) {
+	proxies, err := ParseTrustedProxies("10.0.0.0/8")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		trustedProxies []*net.IPNet
+		remoteAddr     string
+		forwardedFor   string
+		wantIP         string
+	}{
+		{name: "uses remote address without forwarding", remoteAddr: "198.51.100.7:4000", wantIP: "198.51.100.7"},
+		{
+			name:         "ignores X-Forwarded-For when no proxy is trusted",
+			remoteAddr:   "198.51.100.7:4000",
+			forwardedFor: "203.0.113.9",
+			wantIP:       "198.51.100.7",
+		},
+		{
+			name:           "ignores X-Forwarded-For from an untrusted proxy",
+			trustedProxies: proxies,
+			remoteAddr:     "192.168.1.5:4000",
+			forwardedFor:   "203.0.113.9",
+			wantIP:         "192.168.1.5",
+		},
+		{
+			name:           "honors X-Forwarded-For from a trusted proxy",
+			trustedProxies: proxies,
+			remoteAddr:     "10.1.2.3:4000",
+			forwardedFor:   "203.0.113.9",
+			wantIP:         "203.0.113.9",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs strings.Builder
+			var contextIP string
+
+			e := echo.New()
+			e.IPExtractor = newIPExtractor(tt.trustedProxies)
+			e.Use(ClientIPMiddleware())
+			e.Use(LoggingMiddleware(zerolog.New(&logs)))
+			e.GET("/events", func(c echo.Context) error {
+				contextIP = ClientIPFromContext(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/events", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("User-Agent", "curl/8.5.0")
+			if tt.forwardedFor != "" {
+				req.Header.Set(echo.HeaderXForwardedFor, tt.forwardedFor)
+			}
+			e.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.wantIP, contextIP)
+
+			lines := 0
+			scanner := bufio.NewScanner(strings.NewReader(logs.String()))
+			for scanner.Scan() {
+				var entry map[string]interface{}
+				require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+				assert.Equal(t, tt.wantIP, entry["client_ip"], entry["message"])
+				assert.Equal(t, "curl/8.5.0", entry["user_agent"], entry["message"])
+				lines++
+			}
+			assert.Equal(t, 2, lines, "incoming and completed lines")
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty", raw: "", want: nil},
+		{name: "cidrs and bare ips", raw: "10.0.0.0/8, 192.0.2.10 ,2001:db8::1", want: []string{"10.0.0.0/8", "192.0.2.10/32", "2001:db8::1/128"}},
+		{name: "invalid ip", raw: "10.0.0.300", wantErr: true},
+		{name: "invalid cidr", raw: "10.0.0.0/33", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranges, err := ParseTrustedProxies(tt.raw)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			var got []string
+			for _, ipRange := range ranges {
+				got = append(got, ipRange.String())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package transport
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// OriginAllowlist decides which browser origins may call the API cross-origin
+// Entries are exact origins ("https://app.example.com") or wildcard-subdomain patterns ("https://*.example.com");
+// an entry without a scheme matches both http and https, and one without a port matches only the default port
+type OriginAllowlist struct {
+	patterns []originPattern
+}
+
+type originPattern struct {
+	scheme   string // "" matches http and https
+	host     string // Exact host, or for wildcards the suffix including its leading dot
+	port     string
+	wildcard bool
+}
+
+// NewOriginAllowlist parses patterns; an invalid pattern is an error rather than silently never matching
+func NewOriginAllowlist(patterns []string) (*OriginAllowlist, error) {
+	allowlist := &OriginAllowlist{}
+	for _, raw := range patterns {
+		pattern, err := parseOriginPattern(raw)
+		if err != nil {
+			return nil, err
+		}
+		allowlist.patterns = append(allowlist.patterns, pattern)
+	}
+	return allowlist, nil
+}
+
+// ParseAllowedOrigins splits a comma- or newline-separated list, as read from env or a file,
+// dropping blank entries and lines starting with #
+func ParseAllowedOrigins(raw string) []string {
+	var patterns []string
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, entry := range strings.Split(line, ",") {
+			if entry = strings.TrimSpace(entry); entry != "" {
+				patterns = append(patterns, entry)
+			}
+		}
+	}
+	return patterns
+}
+
+// Empty reports whether no origin is allowed, in which case CORS stays disabled
+func (a *OriginAllowlist) Empty() bool {
+	return a == nil || len(a.patterns) == 0
+}
+
+// Allowed reports whether the Origin header value matches an entry
+// The origin is parsed rather than compared as a string, so look-alikes such as
+// "https://evil.com?.example.com" or "https://example.com.evil.com" never match
+func (a *OriginAllowlist) Allowed(origin string) bool {
+	if a == nil {
+		return false
+	}
+	scheme, host, port, ok := splitOrigin(origin)
+	if !ok {
+		return false
+	}
+
+	for _, p := range a.patterns {
+		if p.scheme != "" && p.scheme != scheme {
+			continue
+		}
+		if p.port != port {
+			continue
+		}
+		if p.wildcard {
+			// The suffix starts with a dot, so "*.example.com" matches subdomains but not example.com itself
+			if len(host) > len(p.host) && strings.HasSuffix(host, p.host) {
+				return true
+			}
+			continue
+		}
+		if host == p.host {
+			return true
+		}
+	}
+	return false
+}
+
+func parseOriginPattern(raw string) (originPattern, error) {
+	entry := strings.ToLower(strings.TrimSpace(raw))
+
+	var pattern originPattern
+	if scheme, rest, found := strings.Cut(entry, "://"); found {
+		pattern.scheme = scheme
+		entry = rest
+	}
+	if pattern.scheme != "" && pattern.scheme != "http" && pattern.scheme != "https" {
+		return originPattern{}, fmt.Errorf("invalid CORS origin %q: scheme must be http or https", raw)
+	}
+
+	if strings.HasPrefix(entry, "*.") {
+		pattern.wildcard = true
+		entry = entry[1:]
+	}
+
+	host, port, ok := splitHostPort(entry)
+	if !ok || !validHost(strings.TrimPrefix(host, ".")) {
+		return originPattern{}, fmt.Errorf("invalid CORS origin %q", raw)
+	}
+	pattern.host, pattern.port = host, port
+
+	return pattern, nil
+}
+
+// splitOrigin breaks an Origin header into lower-case scheme, host and port
+// Anything beyond scheme://host[:port] (a path, query, fragment or credentials) makes it invalid
+func splitOrigin(origin string) (scheme, host, port string, ok bool) {
+	u, err := url.Parse(origin)
+	if err != nil || u.User != nil || u.Opaque != "" || u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.ForceQuery {
+		return "", "", "", false
+	}
+
+	scheme = strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return "", "", "", false
+	}
+
+	host, port, ok = splitHostPort(strings.ToLower(u.Host))
+	if !ok || !validHost(host) {
+		return "", "", "", false
+	}
+	return scheme, host, port, true
+}
+
+func splitHostPort(hostport string) (host, port string, ok bool) {
+	host, port, found := strings.Cut(hostport, ":")
+	if !found {
+		return host, "", host != ""
+	}
+	if port == "" || len(port) > 5 {
+		return "", "", false
+	}
+	for _, r := range port {
+		if r < '0' || r > '9' {
+			return "", "", false
+		}
+	}
+	return host, port, host != ""
+}
+
+// validHost accepts dot-separated labels of lower-case letters, digits and inner hyphens
+func validHost(host string) bool {
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return false
+			}
+		}
+	}
+	return true
+}
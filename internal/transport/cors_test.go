@@ -0,0 +1,127 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOriginAllowlist_Allowed(t *testing.T) {
+	allowlist, err := NewOriginAllowlist([]string{
+		"https://app.example.com",
+		"https://*.example.org",
+		"*.example.net",
+		"http://localhost:3000",
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{origin: "https://app.example.com", want: true},
+		{origin: "HTTPS://APP.EXAMPLE.COM", want: true},
+		{origin: "http://app.example.com", want: false},
+		{origin: "https://app.example.com:8443", want: false},
+		{origin: "https://other.example.com", want: false},
+		{origin: "https://example.com", want: false},
+
+		{origin: "https://shop.example.org", want: true},
+		{origin: "https://a.b.example.org", want: true},
+		{origin: "https://example.org", want: false},
+		{origin: "http://shop.example.org", want: false},
+		{origin: "https://.example.org", want: false},
+		{origin: "https://evilexample.org", want: false},
+		{origin: "https://example.org.evil.com", want: false},
+		{origin: "https://shop.example.org.", want: false},
+		{origin: "https://evil.com?.example.org", want: false},
+		{origin: "https://evil.com#.example.org", want: false},
+		{origin: "https://evil.com/.example.org", want: false},
+		{origin: "https://shop.example.org@evil.com", want: false},
+		{origin: "https://evil.com\\.example.org", want: false},
+		{origin: "https://evil.com%2e.example.org", want: false},
+
+		{origin: "https://shop.example.net", want: true},
+		{origin: "http://shop.example.net", want: true},
+		{origin: "ftp://shop.example.net", want: false},
+
+		{origin: "http://localhost:3000", want: true},
+		{origin: "http://localhost", want: false},
+		{origin: "http://localhost:30000", want: false},
+
+		{origin: "", want: false},
+		{origin: "null", want: false},
+		{origin: "app.example.com", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			assert.Equal(t, tt.want, allowlist.Allowed(tt.origin))
+		})
+	}
+}
+
+func TestNewOriginAllowlist_RejectsInvalidPatterns(t *testing.T) {
+	for _, pattern := range []string{
+		"*",
+		"https://*",
+		"https://app.*.example.com",
+		"https://*example.com",
+		"ftp://example.com",
+		"https://example.com/path",
+		"https://example.com:http",
+		"https://exa mple.com",
+	} {
+		t.Run(pattern, func(t *testing.T) {
+			_, err := NewOriginAllowlist([]string{pattern})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseAllowedOrigins(t *testing.T) {
+	raw := "# staging\nhttps://staging.example.com, https://*.preview.example.com\n\n  http://localhost:3000  \n"
+
+	assert.Equal(t, []string{
+		"https://staging.example.com",
+		"https://*.preview.example.com",
+		"http://localhost:3000",
+	}, ParseAllowedOrigins(raw))
+	assert.Empty(t, ParseAllowedOrigins(""))
+}
+
+func TestRouter_CORS(t *testing.T) {
+	allowlist, err := NewOriginAllowlist([]string{"https://*.example.com"})
+	require.NoError(t, err)
+	router := NewRouter(nil, nil, nil, nil, infrastructure.NewWorkerRegistry(), zerolog.Nop(), WithAllowedOrigins(allowlist))
+
+	preflight := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, "/events", nil)
+		req.Header.Set(echo.HeaderOrigin, origin)
+		req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodPost)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("allows a matching origin", func(t *testing.T) {
+		rec := preflight("https://app.example.com")
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "https://app.example.com", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+		assert.Contains(t, rec.Header().Get(echo.HeaderAccessControlAllowHeaders), idempotencyKeyHeader)
+	})
+
+	t.Run("sends no CORS headers to other origins", func(t *testing.T) {
+		rec := preflight("https://app.example.com.evil.com")
+
+		assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+		assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowMethods))
+	})
+}
@@ -0,0 +1,307 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// HeaderDeprecation marks a response of a deprecated route or field, as "@<unix time>" of when it was
+	// deprecated (RFC 9745), or "true" when no date is known
+	HeaderDeprecation = "Deprecation"
+	// HeaderSunset is the HTTP date after which the deprecated route or field may stop working (RFC 8594)
+	HeaderSunset = "Sunset"
+)
+
+// deprecationWarningsKey is the top-level key listing the deprecation warnings of an object response
+const deprecationWarningsKey = "warnings"
+
+// deprecationsContextKey holds the warnings of the deprecations the request ran into
+const deprecationsContextKey = "deprecations"
+
+// responseFieldsContextKey holds the deprecated response fields of the request's route
+const responseFieldsContextKey = "deprecated_response_fields"
+
+// Deprecation announces that a route, or a field of its requests or responses, is going away
+type Deprecation struct {
+	// Since is when it was deprecated; zero sends "Deprecation: true"
+	Since time.Time
+	// Sunset is when it may be removed; zero sends no Sunset header
+	Sunset time.Time
+	// Successor is what to use instead, e.g. "/bookings/batch" or "tickets_booked", linked as rel="successor-version"
+	// when it is a path
+	Successor string
+	// Message replaces the generated warning
+	Message string
+}
+
+// warning describes the deprecation of subject, e.g. "POST /bookings" or "field user_id"
+func (d Deprecation) warning(subject string) string {
+	if d.Message != "" {
+		return d.Message
+	}
+	warning := subject + " is deprecated"
+	if !d.Sunset.IsZero() {
+		warning += " and will be removed after " + d.Sunset.UTC().Format(time.DateOnly)
+	}
+	if d.Successor != "" {
+		warning += "; use " + d.Successor + " instead"
+	}
+	return warning
+}
+
+// Deprecations lists what the API has deprecated, keyed by route as "METHOD /path" where the path is the
+// route's pattern, e.g. "GET /users/:id/events"
+type Deprecations struct {
+	Routes map[string]Deprecation
+	// Fields holds each route's deprecated request fields, matched against the top-level keys of a JSON
+	// body and the query parameters
+	Fields map[string]map[string]Deprecation
+	// ResponseFields holds each route's deprecated response fields, flagged when a JSON response includes
+	// the key at any depth, e.g. in the items of a page
+	ResponseFields map[string]map[string]Deprecation
+}
+
+// apiDeprecations is where routes and fields are deprecated; every response of an entry here carries the
+// Deprecation and Sunset headers and, when it is an object, a warning under "warnings"
+var apiDeprecations = Deprecations{
+	ResponseFields: map[string]map[string]Deprecation{
+		// Listed availability is a snapshot; the availability aggregate is the source of truth
+		"GET /events": {
+			"available_tickets": {Successor: "GET /events/{id}/availability"},
+		},
+	},
+}
+
+func (d Deprecations) empty() bool {
+	return len(d.Routes) == 0 && len(d.Fields) == 0 && len(d.ResponseFields) == 0
+}
+
+// DeprecationMiddleware marks the responses of deprecated routes, and of requests using deprecated
+// fields, with the Deprecation, Sunset and Link headers and a warning in the body
+// Deprecated response fields are only known once the response is serialized, so they are left to the
+// JSON serializer through markDeprecatedResponseFields
+func DeprecationMiddleware(deprecations Deprecations) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			route := c.Request().Method + " " + c.Path()
+			if deprecation, ok := deprecations.Routes[route]; ok {
+				markDeprecated(c, route, deprecation)
+			}
+			if fields := deprecations.Fields[route]; len(fields) > 0 {
+				used, err := requestFields(c)
+				if err != nil {
+					return err
+				}
+				names := make([]string, 0, len(fields))
+				for name := range fields {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					if used[name] {
+						markDeprecated(c, "field "+name, fields[name])
+					}
+				}
+			}
+			if fields := deprecations.ResponseFields[route]; len(fields) > 0 {
+				c.Set(responseFieldsContextKey, fields)
+			}
+			return next(c)
+		}
+	}
+}
+
+// markDeprecated records the deprecation's warning and sets the headers of every deprecation seen so far:
+// the earliest deprecation and sunset dates win, and each successor path is linked
+func markDeprecated(c echo.Context, subject string, deprecation Deprecation) {
+	header := c.Response().Header()
+	warnings, _ := c.Get(deprecationsContextKey).([]string)
+	c.Set(deprecationsContextKey, append(warnings, deprecation.warning(subject)))
+
+	if !deprecation.Since.IsZero() {
+		since := deprecation.Since.Unix()
+		current, err := strconv.ParseInt(strings.TrimPrefix(header.Get(HeaderDeprecation), "@"), 10, 64)
+		if err != nil || since < current {
+			header.Set(HeaderDeprecation, "@"+strconv.FormatInt(since, 10))
+		}
+	} else if header.Get(HeaderDeprecation) == "" {
+		header.Set(HeaderDeprecation, "true")
+	}
+
+	if !deprecation.Sunset.IsZero() {
+		current, err := http.ParseTime(header.Get(HeaderSunset))
+		if err != nil || deprecation.Sunset.Before(current) {
+			header.Set(HeaderSunset, deprecation.Sunset.UTC().Format(http.TimeFormat))
+		}
+	}
+
+	if strings.HasPrefix(deprecation.Successor, "/") {
+		header.Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, deprecation.Successor))
+	}
+}
+
+// markDeprecatedResponseFields marks the deprecated response fields of the request's route that data, a
+// JSON response, includes at any depth
+func markDeprecatedResponseFields(c echo.Context, data []byte) error {
+	fields, _ := c.Get(responseFieldsContextKey).(map[string]Deprecation)
+	if len(fields) == 0 {
+		return nil
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	present := make(map[string]bool)
+	collectJSONKeys(decoded, present)
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		if present[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		markDeprecated(c, "field "+name, fields[name])
+	}
+	return nil
+}
+
+// hasDeprecatedResponseFields reports whether the request's route deprecates any response field
+func hasDeprecatedResponseFields(c echo.Context) bool {
+	fields, _ := c.Get(responseFieldsContextKey).(map[string]Deprecation)
+	return len(fields) > 0
+}
+
+// collectJSONKeys adds the object keys of a decoded JSON value, at any depth, to keys
+func collectJSONKeys(value interface{}, keys map[string]bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			keys[key] = true
+			collectJSONKeys(item, keys)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectJSONKeys(item, keys)
+		}
+	}
+}
+
+// deprecationWarnings returns the warnings recorded for the request by DeprecationMiddleware
+func deprecationWarnings(c echo.Context) []string {
+	warnings, _ := c.Get(deprecationsContextKey).([]string)
+	return warnings
+}
+
+// requestFields returns the names of the query parameters and top-level JSON body keys of the request,
+// leaving the body in place for the handler
+func requestFields(c echo.Context) (map[string]bool, error) {
+	fields := make(map[string]bool)
+	for name := range c.QueryParams() {
+		fields[name] = true
+	}
+
+	req := c.Request()
+	if req.Body == nil || !strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		return fields, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	// A body that is not an object is left for the handler to reject
+	var object map[string]json.RawMessage
+	if json.Unmarshal(body, &object) == nil {
+		for name := range object {
+			fields[name] = true
+		}
+	}
+	return fields, nil
+}
+
+// withDeprecationWarnings adds the warnings to a JSON object under "warnings", after the ones a response
+// such as a partially failed read already lists there; anything else is returned as is
+func withDeprecationWarnings(data []byte, warnings []string) ([]byte, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) < 2 || trimmed[0] != '{' {
+		return data, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	out.WriteByte('{')
+	merged := false
+	for first := true; dec.More(); first = false {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected JSON object key %v", token)
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		if key == deprecationWarningsKey {
+			var existing []json.RawMessage
+			if err := json.Unmarshal(value, &existing); err != nil {
+				return nil, fmt.Errorf("response %q is not an array: %w", deprecationWarningsKey, err)
+			}
+			for _, warning := range warnings {
+				encoded, err := json.Marshal(warning)
+				if err != nil {
+					return nil, err
+				}
+				existing = append(existing, encoded)
+			}
+			if value, err = json.Marshal(existing); err != nil {
+				return nil, err
+			}
+			merged = true
+		}
+
+		if !first {
+			out.WriteByte(',')
+		}
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		out.Write(encodedKey)
+		out.WriteByte(':')
+		out.Write(value)
+	}
+
+	if !merged {
+		encoded, err := json.Marshal(warnings)
+		if err != nil {
+			return nil, err
+		}
+		if out.Len() > 1 {
+			out.WriteByte(',')
+		}
+		out.WriteString(`"` + deprecationWarningsKey + `":`)
+		out.Write(encoded)
+	}
+	out.WriteByte('}')
+	return out.Bytes(), nil
+}
@@ -0,0 +1,179 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_DeprecatedRoute(t *testing.T) {
+	since := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC)
+	router := NewRouter(nil, nil, nil, nil, infrastructure.NewWorkerRegistry(), zerolog.Nop(), WithDeprecations(Deprecations{
+		Routes: map[string]Deprecation{
+			"POST /admin/maintenance": {Since: since, Sunset: sunset, Successor: "/admin/maintenance-window"},
+		},
+	}))
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("marks the deprecated route's response", func(t *testing.T) {
+		rec := serve(http.MethodPost, "/admin/maintenance", `{"enabled": false}`)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "@1788220800", rec.Header().Get(HeaderDeprecation))
+		assert.Equal(t, "Mon, 01 Mar 2027 00:00:00 GMT", rec.Header().Get(HeaderSunset))
+		assert.Equal(t, `</admin/maintenance-window>; rel="successor-version"`, rec.Header().Get("Link"))
+		assert.JSONEq(t, `{
+			"enabled": false,
+			"retry_after_seconds": 300,
+			"warnings": ["POST /admin/maintenance is deprecated and will be removed after 2027-03-01; use /admin/maintenance-window instead"]
+		}`, rec.Body.String())
+	})
+
+	t.Run("leaves other routes unmarked", func(t *testing.T) {
+		rec := serve(http.MethodGet, "/metrics", "")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get(HeaderDeprecation))
+		assert.Empty(t, rec.Header().Get(HeaderSunset))
+	})
+}
+
+func TestDeprecationMiddleware_Fields(t *testing.T) {
+	e := echo.New()
+	e.JSONSerializer = jsonSerializer{}
+	e.Use(DeprecationMiddleware(Deprecations{
+		Fields: map[string]map[string]Deprecation{
+			"POST /orders": {
+				"qty":    {Successor: "quantity"},
+				"legacy": {Message: "legacy is ignored"},
+			},
+		},
+	}))
+	e.POST("/orders", func(c echo.Context) error {
+		var req struct {
+			Qty      int `json:"qty"`
+			Quantity int `json:"quantity"`
+		}
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+		return c.JSON(http.StatusCreated, map[string]int{"quantity": max(req.Qty, req.Quantity)})
+	})
+
+	serve := func(target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("warns about deprecated body and query fields", func(t *testing.T) {
+		rec := serve("/orders?legacy=1", `{"qty": 3}`)
+
+		require.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "true", rec.Header().Get(HeaderDeprecation))
+		assert.Empty(t, rec.Header().Get(HeaderSunset))
+		assert.Empty(t, rec.Header().Get("Link"), "only successor paths are linked")
+		assert.JSONEq(t, `{
+			"quantity": 3,
+			"warnings": ["legacy is ignored", "field qty is deprecated; use quantity instead"]
+		}`, rec.Body.String())
+	})
+
+	t.Run("leaves requests without deprecated fields unmarked", func(t *testing.T) {
+		rec := serve("/orders", `{"quantity": 3}`)
+
+		require.Equal(t, http.StatusCreated, rec.Code)
+		assert.Empty(t, rec.Header().Get(HeaderDeprecation))
+		assert.JSONEq(t, `{"quantity": 3}`, rec.Body.String())
+	})
+}
+
+func TestDeprecationMiddleware_ResponseFields(t *testing.T) {
+	e := echo.New()
+	e.JSONSerializer = jsonSerializer{}
+	e.Use(DeprecationMiddleware(Deprecations{
+		ResponseFields: map[string]map[string]Deprecation{
+			"GET /orders": {"qty": {Successor: "/orders/{id}/quantity"}},
+		},
+	}))
+	e.GET("/orders", func(c echo.Context) error {
+		if c.QueryParam("full") == "true" {
+			return c.JSON(http.StatusOK, map[string]interface{}{"data": []map[string]int{{"quantity": 3}}})
+		}
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"data":     []map[string]int{{"qty": 3}},
+			"warnings": []string{"page 2 failed"},
+		})
+	})
+
+	serve := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	t.Run("warns about deprecated fields nested in the response", func(t *testing.T) {
+		rec := serve("/orders")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "true", rec.Header().Get(HeaderDeprecation))
+		assert.Equal(t, `</orders/{id}/quantity>; rel="successor-version"`, rec.Header().Get("Link"))
+		assert.JSONEq(t, `{
+			"data": [{"qty": 3}],
+			"warnings": ["page 2 failed", "field qty is deprecated; use /orders/{id}/quantity instead"]
+		}`, rec.Body.String())
+	})
+
+	t.Run("leaves responses without deprecated fields unmarked", func(t *testing.T) {
+		rec := serve("/orders?full=true")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get(HeaderDeprecation))
+		assert.JSONEq(t, `{"data": [{"quantity": 3}]}`, rec.Body.String())
+	})
+}
+
+func TestMarkDeprecated_KeepsEarliestDates(t *testing.T) {
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	markDeprecated(c, "a", Deprecation{Since: time.Unix(2000, 0), Sunset: time.Unix(90000, 0)})
+	markDeprecated(c, "b", Deprecation{Since: time.Unix(100, 0), Sunset: time.Unix(200000, 0)})
+	markDeprecated(c, "c", Deprecation{})
+
+	assert.Equal(t, "@100", c.Response().Header().Get(HeaderDeprecation))
+	assert.Equal(t, time.Unix(90000, 0).UTC().Format(http.TimeFormat), c.Response().Header().Get(HeaderSunset))
+	assert.Equal(t, []string{"a is deprecated and will be removed after 1970-01-02", "b is deprecated and will be removed after 1970-01-03", "c is deprecated"}, deprecationWarnings(c))
+}
+
+func TestWithDeprecationWarnings(t *testing.T) {
+	for _, tc := range []struct{ in, want string }{
+		{`{}`, `{"warnings":["w"]}`},
+		{`{"a":1}` + "\n", `{"a":1,"warnings":["w"]}`},
+		{`{"warnings":["partial"],"a":1}`, `{"warnings":["partial","w"],"a":1}`},
+		{`[1]`, `[1]`},
+		{`"text"`, `"text"`},
+	} {
+		got, err := withDeprecationWarnings([]byte(tc.in), []string{"w"})
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, strings.TrimSpace(string(got)), tc.in)
+	}
+}
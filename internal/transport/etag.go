@@ -0,0 +1,38 @@
+package transport
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	headerETag    = "ETag"
+	headerIfMatch = "If-Match"
+)
+
+// versionETag renders an aggregate version as a strong entity tag
+func versionETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// ifMatchVersion reads If-Match as a version written by versionETag
+// It returns 0 when the request has no precondition (no header or "*"), and ok=false when the header
+// cannot match any version: weak tags never match under the strong comparison If-Match requires
+func ifMatchVersion(c echo.Context) (version int64, ok bool) {
+	raw := strings.TrimSpace(c.Request().Header.Get(headerIfMatch))
+	if raw == "" || raw == "*" {
+		return 0, true
+	}
+
+	unquoted, err := strconv.Unquote(raw)
+	if err != nil || !strings.HasPrefix(raw, `"`) {
+		return 0, false
+	}
+	version, err = strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+	return version, true
+}
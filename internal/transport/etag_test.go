@@ -0,0 +1,42 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		wantVersion int64
+		wantOK      bool
+	}{
+		{name: "no precondition without the header", header: "", wantVersion: 0, wantOK: true},
+		{name: "no precondition for any representation", header: "*", wantVersion: 0, wantOK: true},
+		{name: "reads a version tag", header: versionETag(42), wantVersion: 42, wantOK: true},
+		{name: "weak tags never match", header: `W/"42"`, wantOK: false},
+		{name: "unquoted tags never match", header: "42", wantOK: false},
+		{name: "non-numeric tags never match", header: `"abc"`, wantOK: false},
+		{name: "non-positive versions never match", header: `"0"`, wantOK: false},
+	}
+
+	e := echo.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(headerIfMatch, tt.header)
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			version, ok := ifMatchVersion(c)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantVersion, version)
+		})
+	}
+}
@@ -1,16 +1,42 @@
 package transport
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
 	"github.com/jorzel/booking-service/internal/infrastructure"
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog"
 )
 
+const (
+	// HeaderAllowStaleRead opts a read into replica fallback when the primary is unreachable
+	HeaderAllowStaleRead = "X-Allow-Stale-Read"
+	// HeaderStaleRead marks a response served from a replica that may lag behind the primary
+	HeaderStaleRead = "X-Stale-Read"
+)
+
+const (
+	// eventImportChunkSize is the number of events created per import transaction
+	eventImportChunkSize = 100
+	// maxEventImportLineBytes bounds a single JSON Lines record
+	maxEventImportLineBytes = 1 << 20
+
+	importModeSkip  = "skip"
+	importModeAbort = "abort"
+)
+
 type EventHandler struct {
 	service *app.EventService
 	logger  zerolog.Logger
@@ -24,85 +50,1109 @@ func NewEventHandler(service *app.EventService, logger zerolog.Logger) *EventHan
 }
 
 type CreateEventRequest struct {
-	Name     string    `json:"name" validate:"required"`
-	Date     time.Time `json:"date" validate:"required"`
-	Location string    `json:"location" validate:"required"`
-	Tickets  int       `json:"tickets" validate:"required,min=0"`
+	Name        string    `json:"name" validate:"required"`
+	Date        time.Time `json:"date" validate:"required"`
+	Location    string    `json:"location" validate:"required"`
+	Tickets     int       `json:"tickets" validate:"required,min=0"`
+	OrganizerID string    `json:"organizer_id,omitempty"`
+	MinAdvance  string    `json:"min_advance,omitempty"` // Go duration, e.g. "24h"
+	// EndDate or Duration, a Go duration such as "2h", sets when the event ends; omit both for the default
+	EndDate  *time.Time `json:"end_date,omitempty"`
+	Duration string     `json:"duration,omitempty"`
+	// MinViable tickets must sell by ViabilityDeadline for the event to run
+	MinViable         int        `json:"min_viable,omitempty"`
+	ViabilityDeadline *time.Time `json:"viability_deadline,omitempty"`
+	// Seats makes the event reserved seating with one labelled seat per ticket
+	Seats []string `json:"seats,omitempty"`
+	// PriceCents per ticket in Currency, an ISO 4217 code; omit both for a free event
+	PriceCents int64  `json:"price_cents,omitempty"`
+	Currency   string `json:"currency,omitempty"`
+	// Tags categorize the event, e.g. ["music", "jazz"]
+	Tags []string `json:"tags,omitempty"`
+	// ImageURL and ThumbnailURL are absolute http(s) URLs of a poster hosted elsewhere
+	ImageURL     string `json:"image_url,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	// QuantityStep sells tickets only in multiples of it, e.g. 4 for tables of four; omitted means 1
+	QuantityStep int `json:"quantity_step,omitempty"`
+	// RefundTiers replace the default cancellation policy for the event's bookings
+	RefundTiers []RefundTierRequest `json:"refund_tiers,omitempty"`
+	// Lottery sells the tickets only through the event's lottery draw; bookings and holds are rejected
+	Lottery bool `json:"lottery,omitempty"`
+}
+
+// RefundTierRequest refunds RefundPercent of a booking's price when it is cancelled more than MinNotice,
+// a Go duration such as "168h", before the event
+type RefundTierRequest struct {
+	MinNotice     string `json:"min_notice"`
+	RefundPercent int    `json:"refund_percent"`
 }
 
 type EventResponse struct {
-	ID       string    `json:"id"`
-	Name     string    `json:"name"`
-	Date     time.Time `json:"date"`
-	Location string    `json:"location"`
-	Tickets  int       `json:"tickets"`
+	ID                string     `json:"id"`
+	Name              string     `json:"name"`
+	Date              time.Time  `json:"date"`
+	EndDate           *time.Time `json:"end_date,omitempty"`
+	Location          string     `json:"location"`
+	Tickets           int        `json:"tickets"`
+	OrganizerID       string     `json:"organizer_id,omitempty"`
+	MinAdvance        string     `json:"min_advance,omitempty"`
+	Status            string     `json:"status"`
+	CancelledAt       *time.Time `json:"cancelled_at,omitempty"`
+	MinViable         int        `json:"min_viable,omitempty"`
+	ViabilityDeadline *time.Time `json:"viability_deadline,omitempty"`
+	Seated            bool       `json:"seated,omitempty"`
+	PriceCents        int64      `json:"price_cents,omitempty"`
+	Currency          string     `json:"currency,omitempty"`
+	Tags              []string   `json:"tags,omitempty"`
+	BookingsPaused    bool       `json:"bookings_paused,omitempty"`
+	ImageURL          string     `json:"image_url,omitempty"`
+	ThumbnailURL      string     `json:"thumbnail_url,omitempty"`
+	QuantityStep      int        `json:"quantity_step"`
+	// RefundTiers are the event's own cancellation policy; omitted when it uses the default
+	RefundTiers []RefundTierRequest `json:"refund_tiers,omitempty"`
+	Lottery     bool                `json:"lottery,omitempty"`
+}
+
+// EventCancellationResponse is the cancelled event with the refunds of the bookings cancelled along with it
+type EventCancellationResponse struct {
+	EventResponse
+	Refunds []BookingRefundResponse `json:"refunds"`
+}
+
+// EventSummary is the light representation of an event used by the events listing
+type EventSummary struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	Date             time.Time `json:"date"`
+	Location         string    `json:"location"`
+	AvailableTickets int       `json:"available_tickets"`
+	SoldOut          bool      `json:"sold_out"`
+}
+
+type SeatResponse struct {
+	Label  string `json:"label"`
+	Status string `json:"status"`
+}
+
+type SeatMapResponse struct {
+	EventID   string         `json:"event_id"`
+	Available int            `json:"available"`
+	Seats     []SeatResponse `json:"seats"`
+}
+
+// CreateEventResponse is the created event, plus DuplicateOf when an event with the same name
+// already exists on the same day; the event is created regardless so the organizer can confirm
+type CreateEventResponse struct {
+	EventResponse
+	DuplicateOf string `json:"duplicate_of,omitempty"`
+}
+
+type AdjustAvailabilityRequest struct {
+	Delta *int `json:"delta"`
+}
+
+type AvailabilityResponse struct {
+	EventID          string `json:"event_id"`
+	AvailableTickets int    `json:"available_tickets"`
+}
+
+// ReconciliationResponse summarizes POST /admin/reconcile-all
+type ReconciliationResponse struct {
+	Checked   int `json:"checked"`
+	Corrected int `json:"corrected"`
+	Batches   int `json:"batches"`
+}
+
+type QuoteRequest struct {
+	Tickets int `json:"tickets"`
+}
+
+// AvailabilityCheckRequest is one item of the POST /availability/check body, which is a JSON array of them
+type AvailabilityCheckRequest struct {
+	EventID string `json:"event_id"`
+	Tickets int    `json:"tickets"`
+}
+
+// AvailabilityCheckResponse answers each item of the request, in order; the answers are advisory
+type AvailabilityCheckResponse struct {
+	Items []AvailabilityCheckResultResponse `json:"items"`
+}
+
+type AvailabilityCheckResultResponse struct {
+	EventID   string `json:"event_id"`
+	Available bool   `json:"available"`
+	Remaining int    `json:"remaining"`
+}
+
+// QuoteResponse is advisory; nothing is reserved and the price may change before booking
+type QuoteResponse struct {
+	EventID        string `json:"event_id"`
+	Tickets        int    `json:"tickets"`
+	UnitPriceCents int64  `json:"unit_price_cents"`
+	SubtotalCents  int64  `json:"subtotal_cents"`
+	FeesCents      int64  `json:"fees_cents"`
+	TotalCents     int64  `json:"total_cents"`
+	Currency       string `json:"currency,omitempty"`
+}
+
+// EventWithStatsResponse is GET /events/{id}?include=stats; stats is null and warnings explains why
+// when the stats could not be read
+type EventWithStatsResponse struct {
+	EventResponse
+	Stats    *EventStatsResponse `json:"stats"`
+	Warnings []string            `json:"warnings,omitempty"`
+}
+
+type EventStatsResponse struct {
+	Available      int     `json:"available"`
+	Sold           int     `json:"sold"`
+	UtilizationPct float64 `json:"utilization_pct"`
+}
+
+// AvailabilityAtResponse is the event's availability at At, as set by the last change logged before it
+type AvailabilityAtResponse struct {
+	EventID          string                 `json:"event_id"`
+	At               time.Time              `json:"at"`
+	AvailableTickets int                    `json:"available_tickets"`
+	LastChange       AvailabilityChangeInfo `json:"last_change"`
+}
+
+type AvailabilityChangeInfo struct {
+	Delta     int       `json:"delta"`
+	Reason    string    `json:"reason"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+type UtilizationResponse struct {
+	EventID        string  `json:"event_id"`
+	Sold           int     `json:"sold"`
+	Total          int     `json:"total"`
+	UtilizationPct float64 `json:"utilization_pct"`
+}
+
+// EventImportLineError reports a malformed import record by its 1-based line number
+type EventImportLineError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+type EventImportResponse struct {
+	Created int                    `json:"created"`
+	Skipped int                    `json:"skipped"` // Events whose ID already exists
+	Failed  int                    `json:"failed"`  // Malformed lines
+	Aborted bool                   `json:"aborted"`
+	Errors  []EventImportLineError `json:"errors"`
+}
+
+type EventCountResponse struct {
+	Count int `json:"count"`
+}
+
+type OrganizerEventSummaryResponse struct {
+	EventID          string    `json:"event_id"`
+	Name             string    `json:"name"`
+	Date             time.Time `json:"date"`
+	Tickets          int       `json:"tickets"`
+	AvailableTickets int       `json:"available_tickets"`
+	BookingsCount    int       `json:"bookings_count"`
+	TicketsSold      int       `json:"tickets_sold"`
+}
+
+type UserTicketSummaryResponse struct {
+	Total    int                        `json:"total"`
+	PerEvent []UserEventTicketsResponse `json:"per_event"`
+}
+
+type UserEventTicketsResponse struct {
+	EventID   string `json:"event_id"`
+	EventName string `json:"event_name"`
+	Tickets   int    `json:"tickets"`
 }
 
 func (h *EventHandler) CreateEvent(c echo.Context) error {
 	var req CreateEventRequest
-	if err := c.Bind(&req); err != nil {
+	err := c.Bind(&req)
+	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to bind request")
 		infrastructure.EventsCreated.WithLabelValues("error").Inc()
-		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+		return badRequest(c, "invalid request body")
 	}
 
-	event, err := h.service.CreateEvent(c.Request().Context(), app.CreateEventRequest{
-		Name:     req.Name,
-		Date:     req.Date,
-		Location: req.Location,
-		Tickets:  req.Tickets,
-	})
+	var organizerID uuid.UUID
+	if req.OrganizerID != "" {
+		organizerID, err = uuid.Parse(req.OrganizerID)
+		if err != nil {
+			infrastructure.EventsCreated.WithLabelValues("error").Inc()
+			return badRequest(c, "invalid organizer_id")
+		}
+	}
+
+	var minAdvance time.Duration
+	if req.MinAdvance != "" {
+		minAdvance, err = time.ParseDuration(req.MinAdvance)
+		if err != nil {
+			infrastructure.EventsCreated.WithLabelValues("error").Inc()
+			return badRequest(c, "invalid min_advance")
+		}
+	}
+
+	var duration time.Duration
+	if req.Duration != "" {
+		duration, err = time.ParseDuration(req.Duration)
+		if err != nil {
+			infrastructure.EventsCreated.WithLabelValues("error").Inc()
+			return badRequest(c, "invalid duration")
+		}
+	}
+
+	refundTiers, err := parseRefundTiers(req.RefundTiers)
+	if err != nil {
+		infrastructure.EventsCreated.WithLabelValues("error").Inc()
+		return badRequest(c, err.Error())
+	}
+
+	createReq := app.CreateEventRequest{
+		Name:         req.Name,
+		Date:         req.Date,
+		Location:     req.Location,
+		Tickets:      req.Tickets,
+		OrganizerID:  organizerID,
+		MinAdvance:   minAdvance,
+		Duration:     duration,
+		MinViable:    req.MinViable,
+		Seats:        req.Seats,
+		PriceCents:   req.PriceCents,
+		Currency:     req.Currency,
+		Tags:         req.Tags,
+		ImageURL:     req.ImageURL,
+		ThumbnailURL: req.ThumbnailURL,
+		QuantityStep: req.QuantityStep,
+		RefundTiers:  refundTiers,
+		Lottery:      req.Lottery,
+	}
+	if req.EndDate != nil {
+		createReq.EndDate = *req.EndDate
+	}
+	if req.ViabilityDeadline != nil {
+		createReq.ViabilityDeadline = *req.ViabilityDeadline
+	}
+
+	ctx := c.Request().Context()
+	duplicate := h.service.FindSameDayDuplicate(ctx, createReq.Name, createReq.Date)
+
+	event, err := h.service.CreateEvent(ctx, createReq)
 	if err != nil {
 		infrastructure.EventsCreated.WithLabelValues("error").Inc()
 		return handleError(c, err)
 	}
 
+	resp := CreateEventResponse{EventResponse: toEventResponse(event)}
+	if duplicate != nil {
+		resp.DuplicateOf = duplicate.ID.String()
+	}
+
 	infrastructure.EventsCreated.WithLabelValues("success").Inc()
-	return c.JSON(http.StatusCreated, EventResponse{
-		ID:       event.ID.String(),
-		Name:     event.Name,
-		Date:     event.Date,
-		Location: event.Location,
-		Tickets:  event.Tickets,
-	})
+	return c.JSON(http.StatusCreated, resp)
 }
 
 func (h *EventHandler) GetEvent(c echo.Context) error {
-	id, err := uuid.Parse(c.Param("id"))
+	rawID, icsPath := strings.CutSuffix(c.Param("id"), icsSuffix)
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return badRequest(c, "invalid event id")
+	}
+
+	if icsPath || acceptsCalendar(c.Request()) {
+		return h.getEventICalendar(c, id)
+	}
+	if c.QueryParam("include") == "stats" {
+		return h.getEventWithStats(c, id)
+	}
+
+	if c.Request().Header.Get(HeaderAllowStaleRead) != "true" {
+		event, err := h.service.GetEvent(c.Request().Context(), id)
+		if err != nil {
+			return handleError(c, err)
+		}
+		return c.JSON(http.StatusOK, toEventResponse(event))
+	}
+
+	event, stale, err := h.service.GetEventAllowStale(c.Request().Context(), id)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid event id"})
+		return handleError(c, err)
 	}
+	if stale {
+		c.Response().Header().Set(HeaderStaleRead, "true")
+	}
+
+	return c.JSON(http.StatusOK, toEventResponse(event))
+}
 
+// getEventICalendar serves the event as an iCalendar file that calendar apps can import
+func (h *EventHandler) getEventICalendar(c echo.Context, id uuid.UUID) error {
 	event, err := h.service.GetEvent(c.Request().Context(), id)
 	if err != nil {
 		return handleError(c, err)
 	}
 
-	return c.JSON(http.StatusOK, EventResponse{
-		ID:       event.ID.String(),
-		Name:     event.Name,
-		Date:     event.Date,
-		Location: event.Location,
-		Tickets:  event.Tickets,
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`inline; filename="event-%s.ics"`, event.ID))
+	return c.Blob(http.StatusOK, mimeCalendar+"; charset=utf-8", []byte(eventICalendar(event, time.Now())))
+}
+
+func (h *EventHandler) getEventWithStats(c echo.Context, id uuid.UUID) error {
+	result, err := h.service.GetEventWithStats(c.Request().Context(), id)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	response := EventWithStatsResponse{EventResponse: toEventResponse(result.Event), Warnings: result.Warnings}
+	if result.Stats != nil {
+		response.Stats = &EventStatsResponse{
+			Available:      result.Stats.Available,
+			Sold:           result.Stats.Utilization.Sold,
+			UtilizationPct: result.Stats.Utilization.Percent,
+		}
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// QuoteBooking previews what booking the requested tickets would cost, without reserving them
+func (h *EventHandler) QuoteBooking(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid event id")
+	}
+
+	var req QuoteRequest
+	if err := c.Bind(&req); err != nil {
+		return badRequest(c, "invalid request body, expected {\"tickets\": N}")
+	}
+
+	quote, err := h.service.QuoteBooking(c.Request().Context(), id, req.Tickets)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, QuoteResponse{
+		EventID:        id.String(),
+		Tickets:        quote.Tickets,
+		UnitPriceCents: quote.UnitPriceCents,
+		SubtotalCents:  quote.SubtotalCents,
+		FeesCents:      quote.FeesCents,
+		TotalCents:     quote.TotalCents,
+		Currency:       quote.Currency,
 	})
 }
 
-func (h *EventHandler) ListEvents(c echo.Context) error {
-	events, err := h.service.ListEvents(c.Request().Context())
+// CheckAvailability reports, for several events at once, whether the requested tickets are still available
+func (h *EventHandler) CheckAvailability(c echo.Context) error {
+	var req []AvailabilityCheckRequest
+	if err := c.Bind(&req); err != nil {
+		return badRequest(c, "invalid request body, expected [{\"event_id\": \"...\", \"tickets\": N}]")
+	}
+	// Checked before parsing so an oversized body is rejected without further work
+	if len(req) > domain.MaxAvailabilityChecks {
+		return handleError(c, domain.ErrTooManyAvailabilityChecks)
+	}
+
+	checks := make([]app.AvailabilityCheck, 0, len(req))
+	for _, item := range req {
+		eventID, err := uuid.Parse(item.EventID)
+		if err != nil {
+			return badRequest(c, "invalid event_id")
+		}
+		checks = append(checks, app.AvailabilityCheck{EventID: eventID, Tickets: item.Tickets})
+	}
+
+	results, err := h.service.CheckAvailability(c.Request().Context(), checks)
 	if err != nil {
 		return handleError(c, err)
 	}
 
-	response := make([]EventResponse, 0, len(events))
-	for _, event := range events {
-		response = append(response, EventResponse{
-			ID:       event.ID.String(),
-			Name:     event.Name,
-			Date:     event.Date,
-			Location: event.Location,
-			Tickets:  event.Tickets,
+	response := AvailabilityCheckResponse{Items: make([]AvailabilityCheckResultResponse, 0, len(results))}
+	for _, result := range results {
+		response.Items = append(response.Items, AvailabilityCheckResultResponse{
+			EventID:   result.EventID.String(),
+			Available: result.Available,
+			Remaining: result.Remaining,
 		})
 	}
 
 	return c.JSON(http.StatusOK, response)
 }
+
+// GetUtilization returns the share of the event's tickets that are sold or held
+func (h *EventHandler) GetUtilization(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid event id")
+	}
+
+	utilization, err := h.service.GetUtilization(c.Request().Context(), id)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, UtilizationResponse{
+		EventID:        id.String(),
+		Sold:           utilization.Sold,
+		Total:          utilization.Total,
+		UtilizationPct: utilization.Percent,
+	})
+}
+
+// GetAvailabilityAt answers how many tickets were available at ?at=<RFC 3339>, defaulting to now
+func (h *EventHandler) GetAvailabilityAt(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid event id")
+	}
+
+	at := time.Now()
+	if raw := c.QueryParam("at"); raw != "" {
+		at, err = time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return badRequest(c, "invalid at, expected an RFC 3339 timestamp")
+		}
+	}
+
+	change, err := h.service.GetAvailabilityAt(c.Request().Context(), id, at)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, AvailabilityAtResponse{
+		EventID:          id.String(),
+		At:               at,
+		AvailableTickets: change.Resulting,
+		LastChange: AvailabilityChangeInfo{
+			Delta:     change.Delta,
+			Reason:    string(change.Reason),
+			ChangedAt: change.ChangedAt,
+		},
+	})
+}
+
+// GetSeatMap lists a reserved-seating event's seats and how many are still free
+func (h *EventHandler) GetSeatMap(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid event id")
+	}
+
+	seats, err := h.service.GetSeatMap(c.Request().Context(), id)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	response := SeatMapResponse{EventID: id.String(), Seats: make([]SeatResponse, 0, len(seats))}
+	for _, seat := range seats {
+		if seat.Status == domain.SeatStatusFree {
+			response.Available++
+		}
+		response.Seats = append(response.Seats, SeatResponse{Label: seat.Label, Status: string(seat.Status)})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *EventHandler) CancelEvent(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid event id")
+	}
+
+	cancellation, err := h.service.CancelEvent(c.Request().Context(), id)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, EventCancellationResponse{
+		EventResponse: toEventResponse(cancellation.Event),
+		Refunds:       toBookingRefundResponses(cancellation.Refunds),
+	})
+}
+
+// PauseBookings stops new bookings for the event without cancelling it; reads are unaffected
+func (h *EventHandler) PauseBookings(c echo.Context) error {
+	return h.setBookingsPaused(c, h.service.PauseBookings)
+}
+
+// ResumeBookings lets a paused event accept bookings again
+func (h *EventHandler) ResumeBookings(c echo.Context) error {
+	return h.setBookingsPaused(c, h.service.ResumeBookings)
+}
+
+func (h *EventHandler) setBookingsPaused(c echo.Context, set func(context.Context, uuid.UUID) (*domain.Event, error)) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid event id")
+	}
+
+	event, err := set(c.Request().Context(), id)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toEventResponse(event))
+}
+
+// ListEvents pages through events by date with an opaque ?cursor= taken from the previous next_cursor
+// Repeated or comma-separated ?tag= narrows the listing to events with any of the tags, or all of them with ?tag_mode=all
+// Events are listed as an EventSummary, or as the full EventResponse with ?full=true
+func (h *EventHandler) ListEvents(c echo.Context) error {
+	page, err := parsePagination(c)
+	if err != nil {
+		return badRequest(c, err.Error())
+	}
+	if page.Offset != 0 {
+		return badRequest(c, errCursorOffset.Error())
+	}
+
+	after, err := parseEventCursor(c)
+	if err != nil {
+		return badRequest(c, err.Error())
+	}
+
+	filter, err := parseEventFilter(c)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	var full bool
+	if raw := c.QueryParam("full"); raw != "" {
+		full, err = strconv.ParseBool(raw)
+		if err != nil {
+			return badRequest(c, "invalid full, expected true or false")
+		}
+	}
+
+	ctx := c.Request().Context()
+	lastModified, err := h.service.EventsLastModified(ctx)
+	if err != nil {
+		return handleError(c, err)
+	}
+	// Full events only change with the catalog, so an unchanged catalog is answered before reading the page
+	if full && checkNotModified(c, lastModified) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	events, err := h.service.ListEventsPage(ctx, filter, after, page.Limit)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	if full {
+		response := newPagedResponse(events.Events, events.TotalCount, page, toEventResponse)
+		response.NextCursor = encodeEventCursor(events.Next)
+		return c.JSON(http.StatusOK, response)
+	}
+
+	eventIDs := make([]uuid.UUID, 0, len(events.Events))
+	for _, event := range events.Events {
+		eventIDs = append(eventIDs, event.ID)
+	}
+	availability, err := h.service.GetEventsAvailability(ctx, eventIDs)
+	if err != nil {
+		return handleError(c, err)
+	}
+	// Summaries also change with every booking, which does not touch the catalog
+	if availability.LastChanged.After(lastModified) {
+		lastModified = availability.LastChanged
+	}
+	if checkNotModified(c, lastModified) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	response := newPagedResponse(events.Events, events.TotalCount, page, func(event *domain.Event) EventSummary {
+		return toEventSummary(event, availability.Available[event.ID])
+	})
+	response.NextCursor = encodeEventCursor(events.Next)
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// checkNotModified sets Last-Modified, unless lastModified is zero, and reports whether the client's copy is current
+func checkNotModified(c echo.Context, lastModified time.Time) bool {
+	if lastModified.IsZero() {
+		return false
+	}
+	c.Response().Header().Set(echo.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	return notModifiedSince(c.Request(), lastModified)
+}
+
+// CountEvents returns how many events the listing would return for the same filter, without fetching them
+func (h *EventHandler) CountEvents(c echo.Context) error {
+	filter, err := parseEventFilter(c)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	count, err := h.service.CountEvents(c.Request().Context(), filter)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, EventCountResponse{Count: count})
+}
+
+// parseEventFilter reads ?tag= (repeated or comma-separated) and ?tag_mode=, shared by the listing and its count
+func parseEventFilter(c echo.Context) (domain.EventFilter, error) {
+	var tags []string
+	for _, tag := range c.QueryParams()["tag"] {
+		tags = append(tags, strings.Split(tag, ",")...)
+	}
+	return domain.NewEventFilter(tags, domain.TagMatch(c.QueryParam("tag_mode")))
+}
+
+// notModifiedSince reports whether If-Modified-Since is at or after lastModified
+// HTTP dates have second precision, so lastModified is truncated before comparing
+func notModifiedSince(r *http.Request, lastModified time.Time) bool {
+	since, err := http.ParseTime(r.Header.Get(echo.HeaderIfModifiedSince))
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// defaultUpcomingLimit is smaller than the general page size since upcoming events feed a homepage widget
+const defaultUpcomingLimit = 10
+
+// ListUpcomingEvents returns the soonest future events; ?available=true leaves out sold-out events
+func (h *EventHandler) ListUpcomingEvents(c echo.Context) error {
+	page, err := parsePaginationWithDefault(c, defaultUpcomingLimit)
+	if err != nil {
+		return badRequest(c, err.Error())
+	}
+
+	var onlyAvailable bool
+	if raw := c.QueryParam("available"); raw != "" {
+		onlyAvailable, err = strconv.ParseBool(raw)
+		if err != nil {
+			return badRequest(c, "invalid available, expected true or false")
+		}
+	}
+
+	events, total, err := h.service.ListUpcomingEvents(c.Request().Context(), onlyAvailable, page.Limit, page.Offset)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newPagedResponse(events, total, page, toEventResponse))
+}
+
+// ListUserEvents lists the events the user holds tickets for, e.g. for a "my events" view
+func (h *EventHandler) ListUserEvents(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid user id")
+	}
+
+	page, err := parsePagination(c)
+	if err != nil {
+		return badRequest(c, err.Error())
+	}
+
+	events, total, err := h.service.ListEventsForUser(c.Request().Context(), userID, page.Limit, page.Offset)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newPagedResponse(events, total, page, toEventResponse))
+}
+
+// GetUserTicketSummary returns the tickets the user holds in total and per event, in one call
+func (h *EventHandler) GetUserTicketSummary(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid user id")
+	}
+
+	summary, err := h.service.GetUserTicketSummary(c.Request().Context(), userID)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	response := UserTicketSummaryResponse{
+		Total:    summary.Total,
+		PerEvent: make([]UserEventTicketsResponse, len(summary.PerEvent)),
+	}
+	for i, tickets := range summary.PerEvent {
+		response.PerEvent[i] = UserEventTicketsResponse{
+			EventID:   tickets.EventID.String(),
+			EventName: tickets.EventName,
+			Tickets:   tickets.Tickets,
+		}
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *EventHandler) GetOrganizerDashboard(c echo.Context) error {
+	organizerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid organizer id")
+	}
+
+	page, err := parsePagination(c)
+	if err != nil {
+		return badRequest(c, err.Error())
+	}
+
+	summaries, total, err := h.service.GetOrganizerDashboard(c.Request().Context(), organizerID, page.Limit, page.Offset)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newPagedResponse(summaries, total, page, toOrganizerEventSummaryResponse))
+}
+
+func toOrganizerEventSummaryResponse(summary *domain.EventBookingSummary) OrganizerEventSummaryResponse {
+	return OrganizerEventSummaryResponse{
+		EventID:          summary.EventID.String(),
+		Name:             summary.Name,
+		Date:             summary.Date,
+		Tickets:          summary.Tickets,
+		AvailableTickets: summary.AvailableTickets,
+		BookingsCount:    summary.BookingsCount,
+		TicketsSold:      summary.TicketsSold,
+	}
+}
+
+// GetAvailability returns the event's current available tickets with their version as ETag
+func (h *EventHandler) GetAvailability(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid event id")
+	}
+
+	availability, err := h.service.GetAvailability(c.Request().Context(), id)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	c.Response().Header().Set(headerETag, versionETag(availability.Version))
+	return c.JSON(http.StatusOK, AvailabilityResponse{
+		EventID:          availability.EventID.String(),
+		AvailableTickets: availability.AvailableTickets,
+	})
+}
+
+// AdjustAvailability applies a signed delta to the event's available tickets
+// With If-Match set to the ETag of a previous read it only applies while nothing else has changed the
+// availability since, so concurrent corrections are refused with 412 instead of stacking up
+func (h *EventHandler) AdjustAvailability(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid event id")
+	}
+
+	var req AdjustAvailabilityRequest
+	if err := c.Bind(&req); err != nil || req.Delta == nil {
+		return badRequest(c, "invalid request body, expected {\"delta\": N}")
+	}
+
+	expectedVersion, ok := ifMatchVersion(c)
+	if !ok {
+		return handleError(c, domain.ErrAvailabilityVersionMismatch)
+	}
+
+	availability, err := h.service.AdjustAvailability(c.Request().Context(), id, *req.Delta, expectedVersion)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	c.Response().Header().Set(headerETag, versionETag(availability.Version))
+	return c.JSON(http.StatusOK, AvailabilityResponse{
+		EventID:          availability.EventID.String(),
+		AvailableTickets: availability.AvailableTickets,
+	})
+}
+
+// ReconcileAllAvailability resets the availability of every event of the request's tenant to what its bookings
+// imply, in batched transactions
+func (h *EventHandler) ReconcileAllAvailability(c echo.Context) error {
+	summary, err := h.service.ReconcileAllAvailability(c.Request().Context(), app.DefaultReconcileBatchSize)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, ReconciliationResponse{
+		Checked:   summary.Checked,
+		Corrected: summary.Corrected,
+		Batches:   summary.Batches,
+	})
+}
+
+// ExportEvents streams all events as JSON Lines, one EventResponse per line
+func (h *EventHandler) ExportEvents(c echo.Context) error {
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+	res.Header().Set(echo.HeaderContentDisposition,
+		fmt.Sprintf(`attachment; filename="events-%s.jsonl"`, time.Now().UTC().Format("20060102T150405Z")))
+
+	encoder := json.NewEncoder(res)
+	rows := 0
+	err := h.service.ExportEvents(c.Request().Context(), func(event *domain.Event) error {
+		rows++
+		return encoder.Encode(toEventResponse(event))
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Int("rows", rows).Msg("event export aborted")
+		if !res.Committed {
+			return handleError(c, err)
+		}
+		// Headers are already sent; dropping the connection is the only way to signal a truncated export
+		return err
+	}
+
+	return nil
+}
+
+// ImportEvents reads JSON Lines in the ExportEvents format and creates events in chunked transactions
+// ?mode=skip (default) reports malformed lines and continues; ?mode=abort stops at the first one,
+// keeping chunks that were already committed
+func (h *EventHandler) ImportEvents(c echo.Context) error {
+	mode := c.QueryParam("mode")
+	if mode == "" {
+		mode = importModeSkip
+	}
+	if mode != importModeSkip && mode != importModeAbort {
+		return badRequest(c, "invalid mode, expected skip or abort")
+	}
+
+	ctx := c.Request().Context()
+	response := EventImportResponse{Errors: []EventImportLineError{}}
+	chunk := make([]*domain.Event, 0, eventImportChunkSize)
+
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		created, skipped, err := h.service.ImportEvents(ctx, chunk)
+		if err != nil {
+			return err
+		}
+		response.Created += created
+		response.Skipped += skipped
+		chunk = chunk[:0]
+		return nil
+	}
+
+	scanner := bufio.NewScanner(c.Request().Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventImportLineBytes)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		event, err := parseEventRecord(raw)
+		if err != nil {
+			response.Failed++
+			response.Errors = append(response.Errors, EventImportLineError{Line: line, Error: err.Error()})
+			if mode == importModeAbort {
+				response.Aborted = true
+				break
+			}
+			continue
+		}
+
+		chunk = append(chunk, event)
+		if len(chunk) == eventImportChunkSize {
+			if err := flush(); err != nil {
+				return handleError(c, err)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return badRequest(c, fmt.Sprintf("line %d: %v", line+1, err))
+	}
+
+	if response.Aborted {
+		h.logger.Warn().Int("line", line).Int("created", response.Created).Msg("event import aborted")
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
+	if err := flush(); err != nil {
+		return handleError(c, err)
+	}
+
+	h.logger.Info().
+		Int("created", response.Created).
+		Int("skipped", response.Skipped).
+		Int("failed", response.Failed).
+		Msg("event import completed")
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// parseEventRecord decodes one exported event, keeping its ID so re-imports are detected
+func parseEventRecord(raw []byte) (*domain.Event, error) {
+	var record EventResponse
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&record); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	id, err := uuid.Parse(record.ID)
+	if err != nil {
+		return nil, errors.New("invalid id")
+	}
+	// Seat maps are not part of the export, so a seated event could not be booked after import
+	if record.Seated {
+		return nil, errors.New("reserved seating events cannot be imported")
+	}
+	if record.Name == "" || record.Location == "" || record.Date.IsZero() {
+		return nil, errors.New("name, location and date are required")
+	}
+
+	var opts []domain.EventOption
+	if record.EndDate != nil {
+		opts = append(opts, domain.WithEndDate(*record.EndDate))
+	}
+	if record.OrganizerID != "" {
+		organizerID, err := uuid.Parse(record.OrganizerID)
+		if err != nil {
+			return nil, errors.New("invalid organizer_id")
+		}
+		opts = append(opts, domain.WithOrganizer(organizerID))
+	}
+	if record.MinAdvance != "" {
+		minAdvance, err := time.ParseDuration(record.MinAdvance)
+		if err != nil {
+			return nil, errors.New("invalid min_advance")
+		}
+		opts = append(opts, domain.WithMinAdvance(minAdvance))
+	}
+	if record.MinViable > 0 {
+		var deadline time.Time
+		if record.ViabilityDeadline != nil {
+			deadline = *record.ViabilityDeadline
+		}
+		opts = append(opts, domain.WithMinViable(record.MinViable, deadline))
+	}
+	if record.PriceCents != 0 || record.Currency != "" {
+		opts = append(opts, domain.WithPrice(record.PriceCents, record.Currency))
+	}
+	if len(record.Tags) > 0 {
+		opts = append(opts, domain.WithTags(record.Tags))
+	}
+	if record.ImageURL != "" || record.ThumbnailURL != "" {
+		opts = append(opts, domain.WithImages(record.ImageURL, record.ThumbnailURL))
+	}
+	// Exports made before quantity steps existed omit it, selling any quantity
+	if record.QuantityStep != 0 {
+		opts = append(opts, domain.WithQuantityStep(record.QuantityStep))
+	}
+	if len(record.RefundTiers) > 0 {
+		tiers, err := parseRefundTiers(record.RefundTiers)
+		if err != nil {
+			return nil, err
+		}
+		policy, err := domain.NewCancellationPolicy(tiers)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, domain.WithCancellationPolicy(policy))
+	}
+	if record.Lottery {
+		opts = append(opts, domain.WithLottery())
+	}
+
+	event, err := domain.NewEvent(record.Name, record.Location, record.Date, record.Tickets, opts...)
+	if err != nil {
+		return nil, err
+	}
+	event.ID = id
+
+	switch domain.EventStatus(record.Status) {
+	case "", domain.EventStatusActive:
+	case domain.EventStatusCancelled:
+		if record.CancelledAt == nil {
+			return nil, errors.New("cancelled_at is required for cancelled events")
+		}
+		if err := event.Cancel(*record.CancelledAt); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid status %q", record.Status)
+	}
+	event.BookingsPaused = record.BookingsPaused
+
+	return event, nil
+}
+
+func toEventResponse(event *domain.Event) EventResponse {
+	response := EventResponse{
+		ID:             event.ID.String(),
+		Name:           event.Name,
+		Date:           event.Date,
+		Location:       event.Location,
+		Tickets:        event.Tickets,
+		Status:         string(event.Status),
+		Seated:         event.Seated,
+		PriceCents:     event.PriceCents,
+		Currency:       event.Currency,
+		Tags:           event.Tags,
+		BookingsPaused: event.BookingsPaused,
+		ImageURL:       event.ImageURL,
+		ThumbnailURL:   event.ThumbnailURL,
+		QuantityStep:   event.QuantityStep,
+		Lottery:        event.Lottery,
+	}
+	if event.OrganizerID != uuid.Nil {
+		response.OrganizerID = event.OrganizerID.String()
+	}
+	if !event.EndDate.IsZero() {
+		response.EndDate = &event.EndDate
+	}
+	if event.MinAdvance > 0 {
+		response.MinAdvance = event.MinAdvance.String()
+	}
+	if !event.CancelledAt.IsZero() {
+		response.CancelledAt = &event.CancelledAt
+	}
+	if event.RequiresMinimum() {
+		response.MinViable = event.MinViable
+		response.ViabilityDeadline = &event.ViabilityDeadline
+	}
+	if event.CancellationPolicy != nil {
+		for _, tier := range event.CancellationPolicy.Tiers() {
+			response.RefundTiers = append(response.RefundTiers, RefundTierRequest{
+				MinNotice:     tier.MinNotice.String(),
+				RefundPercent: tier.RefundPercent,
+			})
+		}
+	}
+	return response
+}
+
+// parseRefundTiers converts the refund tiers of a request; their values are validated by the domain
+func parseRefundTiers(tiers []RefundTierRequest) ([]domain.RefundTier, error) {
+	parsed := make([]domain.RefundTier, len(tiers))
+	for i, tier := range tiers {
+		notice, err := time.ParseDuration(tier.MinNotice)
+		if err != nil {
+			return nil, errors.New("invalid refund_tiers min_notice")
+		}
+		parsed[i] = domain.RefundTier{MinNotice: notice, RefundPercent: tier.RefundPercent}
+	}
+	return parsed, nil
+}
+
+func toEventSummary(event *domain.Event, available int) EventSummary {
+	return EventSummary{
+		ID:               event.ID.String(),
+		Name:             event.Name,
+		Date:             event.Date,
+		Location:         event.Location,
+		AvailableTickets: available,
+		SoldOut:          available == 0,
+	}
+}
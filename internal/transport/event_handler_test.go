@@ -0,0 +1,73 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotModifiedSince(t *testing.T) {
+	lastModified := time.Date(2030, 5, 1, 12, 0, 0, 250_000_000, time.UTC)
+
+	tests := []struct {
+		name            string
+		ifModifiedSince string
+		want            bool
+	}{
+		{name: "no header", ifModifiedSince: "", want: false},
+		{name: "malformed header", ifModifiedSince: "yesterday", want: false},
+		{name: "same second ignores sub-second part", ifModifiedSince: "Wed, 01 May 2030 12:00:00 GMT", want: true},
+		{name: "later than last modified", ifModifiedSince: "Wed, 01 May 2030 13:00:00 GMT", want: true},
+		{name: "earlier than last modified", ifModifiedSince: "Wed, 01 May 2030 11:59:59 GMT", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/events", nil)
+			if tt.ifModifiedSince != "" {
+				req.Header.Set("If-Modified-Since", tt.ifModifiedSince)
+			}
+
+			assert.Equal(t, tt.want, notModifiedSince(req, lastModified))
+		})
+	}
+}
+
+func TestCheckAvailability_RejectsInvalidBodies(t *testing.T) {
+	e := echo.New()
+	handler := NewEventHandler(nil, zerolog.Nop())
+	e.POST("/availability/check", handler.CheckAvailability)
+
+	item := fmt.Sprintf(`{"event_id": %q, "tickets": 1}`, uuid.New())
+	tooMany := "[" + strings.TrimSuffix(strings.Repeat(item+",", domain.MaxAvailabilityChecks+1), ",") + "]"
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{name: "not an array", body: item, want: http.StatusBadRequest},
+		{name: "invalid event id", body: `[{"event_id": "nope", "tickets": 1}]`, want: http.StatusBadRequest},
+		{name: "too many items", body: tooMany, want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/availability/check", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.want, rec.Code, rec.Body.String())
+		})
+	}
+}
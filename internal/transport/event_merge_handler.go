@@ -0,0 +1,68 @@
+package transport
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+type EventMergeHandler struct {
+	service *app.EventMergeService
+	logger  zerolog.Logger
+}
+
+func NewEventMergeHandler(service *app.EventMergeService, logger zerolog.Logger) *EventMergeHandler {
+	return &EventMergeHandler{
+		service: service,
+		logger:  logger.With().Str("handler", "event_merge").Logger(),
+	}
+}
+
+type MergeEventsRequest struct {
+	SourceID string `json:"source_id"`
+	TargetID string `json:"target_id"`
+}
+
+// MergeEventsResponse summarizes POST /admin/events/merge with the target as it is after the merge
+type MergeEventsResponse struct {
+	SourceID         string        `json:"source_id"`
+	Target           EventResponse `json:"target"`
+	AvailableTickets int           `json:"available_tickets"`
+	BookingsMoved    int           `json:"bookings_moved"`
+	TicketsMoved     int           `json:"tickets_moved"`
+}
+
+// MergeEvents folds a duplicate event into another: the source's bookings move to the target, which
+// gives up available tickets for them, and the source is soft-deleted
+func (h *EventMergeHandler) MergeEvents(c echo.Context) error {
+	var req MergeEventsRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error().Err(err).Msg("failed to bind request")
+		return badRequest(c, "invalid request body")
+	}
+
+	sourceID, err := uuid.Parse(req.SourceID)
+	if err != nil {
+		return badRequest(c, "invalid source_id")
+	}
+	targetID, err := uuid.Parse(req.TargetID)
+	if err != nil {
+		return badRequest(c, "invalid target_id")
+	}
+
+	merge, err := h.service.MergeEvents(c.Request().Context(), sourceID, targetID)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, MergeEventsResponse{
+		SourceID:         merge.SourceID.String(),
+		Target:           toEventResponse(merge.Target),
+		AvailableTickets: merge.Availability.AvailableTickets,
+		BookingsMoved:    merge.BookingsMoved,
+		TicketsMoved:     merge.TicketsMoved,
+	})
+}
@@ -0,0 +1,73 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderFieldCase selects the response key convention; ?case= does the same for clients that cannot set headers
+const HeaderFieldCase = "X-Field-Case"
+
+const fieldCaseCamel = "camel"
+
+func requestedFieldCase(c echo.Context) string {
+	if fieldCase := c.QueryParam("case"); fieldCase != "" {
+		return strings.ToLower(fieldCase)
+	}
+	return strings.ToLower(c.Request().Header.Get(HeaderFieldCase))
+}
+
+// camelCaseTopLevelKeys renames the keys of a JSON object, keeping their order and leaving values untouched
+// Anything other than an object is returned as is
+func camelCaseTopLevelKeys(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
+		return data, nil
+	}
+
+	var out bytes.Buffer
+	out.WriteByte('{')
+	for first := true; dec.More(); first = false {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected JSON object key %v", token)
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		if !first {
+			out.WriteByte(',')
+		}
+		encodedKey, err := json.Marshal(snakeToCamel(key))
+		if err != nil {
+			return nil, err
+		}
+		out.Write(encodedKey)
+		out.WriteByte(':')
+		out.Write(value)
+	}
+	out.WriteByte('}')
+
+	return out.Bytes(), nil
+}
+
+// snakeToCamel turns "utilization_pct" into "utilizationPct"
+func snakeToCamel(key string) string {
+	parts := strings.Split(key, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
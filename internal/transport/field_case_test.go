@@ -0,0 +1,80 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONSerializer_FieldCase(t *testing.T) {
+	event, err := domain.NewEvent("Jazz Night", "Blue Room", time.Date(2030, 5, 1, 19, 0, 0, 0, time.UTC), 80,
+		domain.WithPrice(2500, "EUR"), domain.WithMinViable(20, time.Date(2030, 4, 1, 0, 0, 0, 0, time.UTC)))
+	require.NoError(t, err)
+
+	e := echo.New()
+	e.JSONSerializer = jsonSerializer{}
+	e.GET("/events/:id", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, toEventResponse(event))
+	})
+	e.GET("/paged", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, newPagedResponse([]*domain.Event{event}, 1, Pagination{Limit: 10}, toEventResponse))
+	})
+
+	get := func(path string, header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if header != "" {
+			req.Header.Set(HeaderFieldCase, header)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		return rec
+	}
+
+	snake := get("/events/"+event.ID.String(), "").Body.String()
+	assert.Contains(t, snake, `"price_cents":2500`)
+	assert.Contains(t, snake, `"min_viable":20`)
+	assert.NotContains(t, snake, `"priceCents"`)
+
+	for name, rec := range map[string]*httptest.ResponseRecorder{
+		"query parameter": get("/events/"+event.ID.String()+"?case=camel", ""),
+		"header":          get("/events/"+event.ID.String(), "camel"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			camel := rec.Body.String()
+			assert.Contains(t, camel, `"priceCents":2500`)
+			assert.Contains(t, camel, `"minViable":20`)
+			assert.NotContains(t, camel, `"price_cents"`)
+			assert.Contains(t, rec.Header().Values(echo.HeaderVary), HeaderFieldCase)
+		})
+	}
+
+	t.Run("keeps the values", func(t *testing.T) {
+		var snakeFields, camelFields map[string]any
+		require.NoError(t, json.Unmarshal([]byte(snake), &snakeFields))
+		require.NoError(t, json.Unmarshal(get("/events/"+event.ID.String()+"?case=camel", "").Body.Bytes(), &camelFields))
+
+		want := make(map[string]any, len(snakeFields))
+		for key, value := range snakeFields {
+			want[snakeToCamel(key)] = value
+		}
+		assert.Equal(t, want, camelFields)
+	})
+
+	t.Run("renames only top-level keys", func(t *testing.T) {
+		camel := get("/paged?case=camel", "").Body.String()
+		assert.Contains(t, camel, `"totalCount":1`)
+		assert.Contains(t, camel, `"price_cents":2500`, "items inside data keep snake_case")
+	})
+
+	t.Run("unknown cases fall back to snake_case", func(t *testing.T) {
+		assert.Equal(t, snake, get("/events/"+event.ID.String()+"?case=kebab", "").Body.String())
+	})
+}
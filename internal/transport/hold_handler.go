@@ -0,0 +1,114 @@
+package transport
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+type HoldHandler struct {
+	service *app.HoldService
+	logger  zerolog.Logger
+}
+
+func NewHoldHandler(service *app.HoldService, logger zerolog.Logger) *HoldHandler {
+	return &HoldHandler{
+		service: service,
+		logger:  logger.With().Str("handler", "hold").Logger(),
+	}
+}
+
+type CreateHoldRequest struct {
+	UserID  string `json:"user_id" validate:"required"`
+	Tickets int    `json:"tickets" validate:"required,min=1"`
+}
+
+type HoldResponse struct {
+	ID           string    `json:"id"`
+	EventID      string    `json:"event_id"`
+	UserID       string    `json:"user_id"`
+	Tickets      int       `json:"tickets"`
+	Status       string    `json:"status"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	BookingID    string    `json:"booking_id,omitempty"`
+	DepositCents int64     `json:"deposit_cents,omitempty"`
+}
+
+func (h *HoldHandler) CreateHold(c echo.Context) error {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid event id")
+	}
+
+	var req CreateHoldRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error().Err(err).Msg("failed to bind request")
+		return badRequest(c, "invalid request body")
+	}
+
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return badRequest(c, "invalid user_id")
+	}
+
+	hold, err := h.service.CreateHold(c.Request().Context(), app.CreateHoldRequest{
+		EventID: eventID,
+		UserID:  userID,
+		Tickets: req.Tickets,
+	})
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, toHoldResponse(hold, time.Now()))
+}
+
+func (h *HoldHandler) GetHold(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid hold id")
+	}
+
+	hold, err := h.service.GetHold(c.Request().Context(), id)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toHoldResponse(hold, time.Now()))
+}
+
+// ConfirmHold creates the booking for an active hold; an expired hold yields 410 Gone
+func (h *HoldHandler) ConfirmHold(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid hold id")
+	}
+
+	booking, err := h.service.ConfirmHold(c.Request().Context(), id)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, toBookingResponse(booking))
+}
+
+func toHoldResponse(hold *domain.Hold, now time.Time) HoldResponse {
+	response := HoldResponse{
+		ID:           hold.ID.String(),
+		EventID:      hold.EventID.String(),
+		UserID:       hold.UserID.String(),
+		Tickets:      hold.Tickets,
+		Status:       string(hold.State(now)),
+		ExpiresAt:    hold.ExpiresAt,
+		DepositCents: hold.DepositCents,
+	}
+	if hold.BookingID != uuid.Nil {
+		response.BookingID = hold.BookingID.String()
+	}
+	return response
+}
@@ -3,8 +3,11 @@ package transport
 import (
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
 	"github.com/labstack/echo/v4"
 )
 
@@ -12,19 +15,122 @@ type ErrorResponse struct {
 	Error string `json:"error"`
 }
 
+// ProblemDetails is an RFC 7807 error body, returned when the client accepts application/problem+json
+type ProblemDetails struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail"`
+	Instance string `json:"instance,omitempty"`
+}
+
+const mimeProblemJSON = "application/problem+json"
+
+// problemType identifies a class of error; its URI is stable and safe for clients to match on
+type problemType struct {
+	slug  string
+	title string
+}
+
+func (p problemType) uri() string {
+	return "/problems/" + p.slug
+}
+
+var (
+	problemBadRequest      = problemType{slug: "bad-request", title: "Bad request"}
+	problemValidation      = problemType{slug: "validation-error", title: "Validation failed"}
+	problemUnauthorized    = problemType{slug: "unauthorized", title: "Unauthorized"}
+	problemNotFound        = problemType{slug: "not-found", title: "Resource not found"}
+	problemConflict        = problemType{slug: "conflict", title: "Conflict with current state"}
+	problemConcurrent      = problemType{slug: "concurrent-update", title: "Concurrent update conflict"}
+	problemExpired         = problemType{slug: "expired", title: "Resource expired"}
+	problemPrecondition    = problemType{slug: "precondition-failed", title: "Precondition failed"}
+	problemTimeout         = problemType{slug: "timeout", title: "Request timed out"}
+	problemUnavailable     = problemType{slug: "service-unavailable", title: "Service unavailable"}
+	problemInternalFailure = problemType{slug: "internal-error", title: "Internal server error"}
+)
+
 func handleError(c echo.Context, err error) error {
 	var notFoundErr *domain.NotFoundError
 	var validationErr *domain.ValidationError
 	var conflictErr *domain.ConflictError
+	var expiredErr *domain.ExpiredError
+	var preconditionErr *domain.PreconditionFailedError
+	var unavailableErr *domain.UnavailableError
 
 	switch {
 	case errors.As(err, &notFoundErr):
-		return c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		return writeError(c, http.StatusNotFound, problemNotFound, err.Error())
 	case errors.As(err, &validationErr):
-		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return writeError(c, validationStatus(c), problemValidation, err.Error())
 	case errors.As(err, &conflictErr):
-		return c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+		return writeError(c, http.StatusConflict, problemConflict, err.Error())
+	case errors.As(err, &expiredErr):
+		return writeError(c, http.StatusGone, problemExpired, err.Error())
+	case errors.As(err, &preconditionErr):
+		return writeError(c, http.StatusPreconditionFailed, problemPrecondition, err.Error())
+	case errors.As(err, &unavailableErr):
+		if unavailableErr.RetryAfter > 0 {
+			c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(retryAfterSeconds(unavailableErr.RetryAfter)))
+		}
+		return writeError(c, http.StatusServiceUnavailable, problemUnavailable, err.Error())
+	case errors.Is(err, infrastructure.ErrSerializationFailure), errors.Is(err, infrastructure.ErrDeadlockDetected):
+		return writeError(c, http.StatusConflict, problemConcurrent, "concurrent update conflict, please retry")
+	case errors.Is(err, infrastructure.ErrQueryCanceled):
+		return writeError(c, http.StatusServiceUnavailable, problemTimeout, "request took too long, please retry")
 	default:
-		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+		return writeError(c, http.StatusInternalServerError, problemInternalFailure, "internal server error")
+	}
+}
+
+// validationStatusKey holds the status set by ValidationStatusMiddleware for domain validation failures
+const validationStatusKey = "validation_status"
+
+// ValidationStatusMiddleware answers domain validation failures with status instead of 400, e.g. 422
+// Malformed input rejected before reaching the domain, such as unparsable JSON, still answers 400
+func ValidationStatusMiddleware(status int) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(validationStatusKey, status)
+			return next(c)
+		}
+	}
+}
+
+func validationStatus(c echo.Context) int {
+	if status, ok := c.Get(validationStatusKey).(int); ok {
+		return status
+	}
+	return http.StatusBadRequest
+}
+
+// badRequest rejects malformed input that never reached the domain (unparsable IDs, bodies, query params)
+func badRequest(c echo.Context, detail string) error {
+	return writeError(c, http.StatusBadRequest, problemBadRequest, detail)
+}
+
+// writeError renders an error as problem details when the client asks for them, and as ErrorResponse otherwise
+func writeError(c echo.Context, status int, problem problemType, detail string) error {
+	if !acceptsProblemJSON(c.Request()) {
+		return c.JSON(status, ErrorResponse{Error: detail})
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, mimeProblemJSON)
+	return c.JSON(status, ProblemDetails{
+		Type:     problem.uri(),
+		Title:    problem.title,
+		Status:   status,
+		Detail:   detail,
+		Instance: c.Request().URL.Path,
+	})
+}
+
+func acceptsProblemJSON(r *http.Request) bool {
+	for _, accepted := range strings.Split(r.Header.Get(echo.HeaderAccept), ",") {
+		mediaType, _, _ := strings.Cut(accepted, ";")
+		if strings.EqualFold(strings.TrimSpace(mediaType), mimeProblemJSON) {
+			return true
+		}
 	}
+	return false
 }
@@ -0,0 +1,146 @@
+package transport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantType   string
+	}{
+		{name: "not found", err: fmt.Errorf("failed to get event: %w", domain.ErrEventNotFound), wantStatus: http.StatusNotFound, wantType: "/problems/not-found"},
+		{name: "validation", err: domain.ErrInvalidTicketCount, wantStatus: http.StatusBadRequest, wantType: "/problems/validation-error"},
+		{name: "conflict", err: domain.ErrInsufficientTickets, wantStatus: http.StatusConflict, wantType: "/problems/conflict"},
+		{name: "expired", err: domain.ErrHoldExpired, wantStatus: http.StatusGone, wantType: "/problems/expired"},
+		{name: "unavailable", err: domain.ErrServiceUnavailable, wantStatus: http.StatusServiceUnavailable, wantType: "/problems/service-unavailable"},
+		{name: "serialization failure", err: infrastructure.ErrSerializationFailure, wantStatus: http.StatusConflict, wantType: "/problems/concurrent-update"},
+		{name: "query canceled", err: infrastructure.ErrQueryCanceled, wantStatus: http.StatusServiceUnavailable, wantType: "/problems/timeout"},
+		{name: "unknown", err: errors.New("boom"), wantStatus: http.StatusInternalServerError, wantType: "/problems/internal-error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" as problem+json", func(t *testing.T) {
+			rec := serveError(t, tt.err, "application/problem+json")
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, "application/problem+json", rec.Header().Get(echo.HeaderContentType))
+
+			var problem ProblemDetails
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+			assert.Equal(t, tt.wantType, problem.Type)
+			assert.Equal(t, rec.Code, problem.Status)
+			assert.NotEmpty(t, problem.Title)
+			assert.NotEmpty(t, problem.Detail)
+			assert.Equal(t, "/things/42", problem.Instance)
+		})
+
+		t.Run(tt.name+" as plain json", func(t *testing.T) {
+			rec := serveError(t, tt.err, "application/json")
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Contains(t, rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
+
+			var body map[string]interface{}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Len(t, body, 1)
+			assert.NotEmpty(t, body["error"])
+		})
+	}
+}
+
+func TestHandleError_RetryAfter(t *testing.T) {
+	rec := serveError(t, fmt.Errorf("booking rejected: %w", domain.ErrEventBusy), "application/json")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get(echo.HeaderRetryAfter))
+
+	rec = serveError(t, domain.ErrServiceUnavailable, "application/json")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Empty(t, rec.Header().Get(echo.HeaderRetryAfter), "errors without a retry hint send no Retry-After")
+}
+
+func TestHandleError_UnprocessableValidation(t *testing.T) {
+	e := echo.New()
+	e.Use(ValidationStatusMiddleware(http.StatusUnprocessableEntity))
+	e.GET("/validation", func(c echo.Context) error {
+		return handleError(c, domain.ErrInvalidTicketCount)
+	})
+	e.GET("/malformed", func(c echo.Context) error {
+		return badRequest(c, "invalid request body")
+	})
+	e.GET("/conflict", func(c echo.Context) error {
+		return handleError(c, domain.ErrInsufficientTickets)
+	})
+
+	tests := []struct {
+		path       string
+		wantStatus int
+	}{
+		{path: "/validation", wantStatus: http.StatusUnprocessableEntity},
+		{path: "/malformed", wantStatus: http.StatusBadRequest},
+		{path: "/conflict", wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set(echo.HeaderAccept, mimeProblemJSON)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			var problem ProblemDetails
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+			assert.Equal(t, tt.wantStatus, problem.Status)
+		})
+	}
+}
+
+func TestAcceptsProblemJSON(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{accept: "", want: false},
+		{accept: "application/json", want: false},
+		{accept: "application/problem+json", want: true},
+		{accept: "application/json, application/problem+json;q=0.9", want: true},
+		{accept: "Application/Problem+JSON", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAccept, tt.accept)
+			assert.Equal(t, tt.want, acceptsProblemJSON(req))
+		})
+	}
+}
+
+func serveError(t *testing.T, err error, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	e := echo.New()
+	e.GET("/things/:id", func(c echo.Context) error {
+		return handleError(c, err)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/things/42", nil)
+	req.Header.Set(echo.HeaderAccept, accept)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
@@ -0,0 +1,122 @@
+package transport
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	mimeCalendar = "text/calendar"
+	// icsSuffix selects the iCalendar representation from the path, e.g. /events/{id}.ics
+	icsSuffix = ".ics"
+	// icsLineLimit is the longest content line RFC 5545 allows, in octets, before it must be folded
+	icsLineLimit = 75
+)
+
+// acceptsCalendar reports whether the client asked for the iCalendar representation of a resource
+func acceptsCalendar(r *http.Request) bool {
+	for _, accepted := range strings.Split(r.Header.Get(echo.HeaderAccept), ",") {
+		mediaType, _, _ := strings.Cut(accepted, ";")
+		if strings.EqualFold(strings.TrimSpace(mediaType), mimeCalendar) {
+			return true
+		}
+	}
+	return false
+}
+
+// eventICalendar renders the event as an iCalendar object holding a single VEVENT (RFC 5545)
+// DTEND is left out for events whose end is unknown. Both times are written in UTC unless they carry
+// a named time zone, in which case they are local time with a TZID parameter
+func eventICalendar(event *domain.Event, stamp time.Time) string {
+	var b strings.Builder
+	line := func(content string) {
+		writeICSLine(&b, content)
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//booking-service//events//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("BEGIN:VEVENT")
+	line("UID:" + event.ID.String() + "@booking-service")
+	line("DTSTAMP:" + icsUTC(stamp))
+	line(icsDateTime("DTSTART", event.Date))
+	if !event.EndDate.IsZero() {
+		line(icsDateTime("DTEND", event.EndDate))
+	}
+	line("SUMMARY:" + escapeICSText(event.Name))
+	if event.Location != "" {
+		line("LOCATION:" + escapeICSText(event.Location))
+	}
+	if len(event.Tags) > 0 {
+		tags := make([]string, len(event.Tags))
+		for i, tag := range event.Tags {
+			tags[i] = escapeICSText(tag)
+		}
+		line("CATEGORIES:" + strings.Join(tags, ","))
+	}
+	if event.Status == domain.EventStatusCancelled {
+		line("STATUS:CANCELLED")
+	} else {
+		line("STATUS:CONFIRMED")
+	}
+	line("END:VEVENT")
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+// icsDateTime renders a date-time property, as local time with a TZID parameter when t has a named zone
+func icsDateTime(property string, t time.Time) string {
+	if tzid := icsTZID(t); tzid != "" {
+		return property + ";TZID=" + tzid + ":" + t.Format("20060102T150405")
+	}
+	return property + ":" + icsUTC(t)
+}
+
+func icsUTC(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// icsTZID returns the IANA name of t's time zone, or "" when t is in UTC or the process-local zone,
+// which has no portable name
+func icsTZID(t time.Time) string {
+	switch name := t.Location().String(); name {
+	case "UTC", "Local", "":
+		return ""
+	default:
+		return name
+	}
+}
+
+// escapeICSText escapes a TEXT property value
+func escapeICSText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// writeICSLine writes one content line terminated by CRLF, folding it into continuation lines
+// (CRLF followed by a space) so that no line exceeds icsLineLimit octets, without splitting a UTF-8 sequence
+func writeICSLine(b *strings.Builder, content string) {
+	limit := icsLineLimit
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !isUTF8Start(content[cut]) {
+			cut--
+		}
+		b.WriteString(content[:cut])
+		b.WriteString("\r\n ")
+		content = content[cut:]
+		// Continuation lines start with a space, which counts towards the limit
+		limit = icsLineLimit - 1
+	}
+	b.WriteString(content)
+	b.WriteString("\r\n")
+}
+
+func isUTF8Start(c byte) bool {
+	return c&0xC0 != 0x80
+}
@@ -0,0 +1,142 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseICS unfolds an iCalendar object and returns its content lines, checking the framing RFC 5545 requires
+func parseICS(t *testing.T, ics string) []string {
+	t.Helper()
+	require.True(t, strings.HasSuffix(ics, "\r\n"), "content lines end with CRLF")
+
+	var lines []string
+	for _, physical := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(physical), icsLineLimit, "line %q is not folded", physical)
+		assert.NotContains(t, physical, "\n", "bare LF inside a line")
+		if strings.HasPrefix(physical, " ") {
+			require.NotEmpty(t, lines, "continuation without a line to continue")
+			lines[len(lines)-1] += physical[1:]
+			continue
+		}
+		lines = append(lines, physical)
+	}
+
+	var open []string
+	for _, line := range lines {
+		name, value, found := strings.Cut(line, ":")
+		require.True(t, found, "line %q has no value", line)
+		switch name {
+		case "BEGIN":
+			open = append(open, value)
+		case "END":
+			require.NotEmpty(t, open, "END:%s without BEGIN", value)
+			require.Equal(t, open[len(open)-1], value)
+			open = open[:len(open)-1]
+		}
+	}
+	require.Empty(t, open, "unterminated components")
+	return lines
+}
+
+func TestEventICalendar(t *testing.T) {
+	event := &domain.Event{
+		ID:       uuid.MustParse("7d6f0a6e-6a55-4c0e-9f59-5b8c9d3f1a21"),
+		Name:     "Jazz, Blues; and \\ More",
+		Date:     time.Date(2030, 6, 1, 19, 30, 0, 0, time.UTC),
+		Location: "Main Hall",
+		Status:   domain.EventStatusActive,
+		Tags:     []string{"music", "jazz"},
+	}
+	stamp := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	lines := parseICS(t, eventICalendar(event, stamp))
+	assert.Equal(t, []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//booking-service//events//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"BEGIN:VEVENT",
+		"UID:7d6f0a6e-6a55-4c0e-9f59-5b8c9d3f1a21@booking-service",
+		"DTSTAMP:20300102T030405Z",
+		"DTSTART:20300601T193000Z",
+		`SUMMARY:Jazz\, Blues\; and \\ More`,
+		"LOCATION:Main Hall",
+		"CATEGORIES:music,jazz",
+		"STATUS:CONFIRMED",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, lines)
+}
+
+func TestEventICalendar_TimeZone(t *testing.T) {
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	event := &domain.Event{
+		ID:      uuid.New(),
+		Name:    "Opera",
+		Date:    time.Date(2030, 6, 1, 19, 30, 0, 0, warsaw),
+		EndDate: time.Date(2030, 6, 1, 22, 0, 0, 0, warsaw),
+		Status:  domain.EventStatusCancelled,
+	}
+
+	lines := parseICS(t, eventICalendar(event, time.Now()))
+	assert.Contains(t, lines, "DTSTART;TZID=Europe/Warsaw:20300601T193000")
+	assert.Contains(t, lines, "DTEND;TZID=Europe/Warsaw:20300601T220000")
+	assert.Contains(t, lines, "STATUS:CANCELLED")
+	for _, line := range lines {
+		assert.False(t, strings.HasPrefix(line, "LOCATION"), "an event without a location has no LOCATION")
+	}
+}
+
+func TestEventICalendar_EndDate(t *testing.T) {
+	date := time.Date(2030, 6, 1, 19, 30, 0, 0, time.UTC)
+	event := &domain.Event{ID: uuid.New(), Name: "Gala", Date: date, EndDate: date.Add(3 * time.Hour), Status: domain.EventStatusActive}
+
+	lines := parseICS(t, eventICalendar(event, time.Now()))
+	assert.Contains(t, lines, "DTSTART:20300601T193000Z")
+	assert.Contains(t, lines, "DTEND:20300601T223000Z")
+}
+
+func TestEventICalendar_FoldsLongLines(t *testing.T) {
+	name := strings.Repeat("Koncert symfoniczny w Łodzi ", 8)
+	event := &domain.Event{ID: uuid.New(), Name: name, Date: time.Now(), Status: domain.EventStatusActive}
+
+	ics := eventICalendar(event, time.Now())
+	for _, physical := range strings.Split(ics, "\r\n") {
+		assert.True(t, utf8.ValidString(physical), "folding split a UTF-8 sequence in %q", physical)
+	}
+	assert.Contains(t, parseICS(t, ics), "SUMMARY:"+name)
+}
+
+func TestAcceptsCalendar(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{accept: "", want: false},
+		{accept: "application/json", want: false},
+		{accept: "text/calendar", want: true},
+		{accept: "application/json;q=0.5, Text/Calendar; charset=utf-8", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/events/1", nil)
+			req.Header.Set("Accept", tt.accept)
+			assert.Equal(t, tt.want, acceptsCalendar(req))
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package transport
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+type LotteryHandler struct {
+	service *app.LotteryService
+	logger  zerolog.Logger
+}
+
+func NewLotteryHandler(service *app.LotteryService, logger zerolog.Logger) *LotteryHandler {
+	return &LotteryHandler{
+		service: service,
+		logger:  logger.With().Str("handler", "lottery").Logger(),
+	}
+}
+
+type RegisterLotteryRequest struct {
+	UserID  string `json:"user_id"`
+	Tickets int    `json:"tickets"`
+}
+
+type DrawLotteryRequest struct {
+	Seed    *int64         `json:"seed"`
+	Weights map[string]int `json:"weights"`
+}
+
+type LotteryEntryResponse struct {
+	ID           string    `json:"id"`
+	EventID      string    `json:"event_id"`
+	UserID       string    `json:"user_id"`
+	Tickets      int       `json:"tickets"`
+	Status       string    `json:"status"`
+	RegisteredAt time.Time `json:"registered_at"`
+	BookingID    string    `json:"booking_id,omitempty"`
+}
+
+type LotteryDrawResponse struct {
+	Seed          int64                  `json:"seed"`
+	TicketsBooked int                    `json:"tickets_booked"`
+	Winners       []LotteryEntryResponse `json:"winners"`
+	Losers        []LotteryEntryResponse `json:"losers"`
+}
+
+// RegisterLottery enters a user into the event's lottery
+func (h *LotteryHandler) RegisterLottery(c echo.Context) error {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid event id")
+	}
+
+	var req RegisterLotteryRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error().Err(err).Msg("failed to bind request")
+		return badRequest(c, "invalid request body")
+	}
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return badRequest(c, "invalid user id")
+	}
+
+	entry, err := h.service.Register(c.Request().Context(), app.RegisterLotteryRequest{
+		EventID: eventID,
+		UserID:  userID,
+		Tickets: req.Tickets,
+	})
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, toLotteryEntryResponse(entry))
+}
+
+// DrawLottery picks the event's lottery winners and books their tickets; the body is optional
+func (h *LotteryHandler) DrawLottery(c echo.Context) error {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid event id")
+	}
+
+	var req DrawLotteryRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error().Err(err).Msg("failed to bind request")
+		return badRequest(c, "invalid request body")
+	}
+	weights := make(map[uuid.UUID]int, len(req.Weights))
+	for user, weight := range req.Weights {
+		userID, err := uuid.Parse(user)
+		if err != nil {
+			return badRequest(c, "invalid user id in weights")
+		}
+		weights[userID] = weight
+	}
+
+	draw, err := h.service.Draw(c.Request().Context(), app.DrawLotteryRequest{
+		EventID: eventID,
+		Weights: weights,
+		Seed:    req.Seed,
+	})
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, LotteryDrawResponse{
+		Seed:          draw.Seed,
+		TicketsBooked: draw.Tickets(),
+		Winners:       toLotteryEntryResponses(draw.Winners),
+		Losers:        toLotteryEntryResponses(draw.Losers),
+	})
+}
+
+func toLotteryEntryResponses(entries []*domain.LotteryEntry) []LotteryEntryResponse {
+	response := make([]LotteryEntryResponse, len(entries))
+	for i, entry := range entries {
+		response[i] = toLotteryEntryResponse(entry)
+	}
+	return response
+}
+
+func toLotteryEntryResponse(entry *domain.LotteryEntry) LotteryEntryResponse {
+	response := LotteryEntryResponse{
+		ID:           entry.ID.String(),
+		EventID:      entry.EventID.String(),
+		UserID:       entry.UserID.String(),
+		Tickets:      entry.Tickets,
+		Status:       string(entry.Status),
+		RegisteredAt: entry.RegisteredAt,
+	}
+	if entry.BookingID != uuid.Nil {
+		response.BookingID = entry.BookingID.String()
+	}
+	return response
+}
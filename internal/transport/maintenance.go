@@ -0,0 +1,91 @@
+package transport
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// DefaultMaintenanceRetryAfter is how long clients are told to wait before retrying a paused write
+const DefaultMaintenanceRetryAfter = 5 * time.Minute
+
+var problemMaintenance = problemType{slug: "maintenance", title: "Down for maintenance"}
+
+// Maintenance pauses writes while reads keep working, e.g. during migrations or incidents
+// It is toggled at runtime through POST /admin/maintenance and safe for concurrent use
+type Maintenance struct {
+	enabled    atomic.Bool
+	retryAfter time.Duration
+}
+
+func NewMaintenance(enabled bool, retryAfter time.Duration) *Maintenance {
+	m := &Maintenance{retryAfter: retryAfter}
+	m.enabled.Store(enabled)
+	return m
+}
+
+func (m *Maintenance) Enabled() bool {
+	return m.enabled.Load()
+}
+
+func (m *Maintenance) SetEnabled(enabled bool) {
+	m.enabled.Store(enabled)
+}
+
+func (m *Maintenance) retryAfterSeconds() int {
+	return retryAfterSeconds(m.retryAfter)
+}
+
+// retryAfterSeconds renders d for the Retry-After header, which counts whole seconds
+func retryAfterSeconds(d time.Duration) int {
+	return int(d.Round(time.Second) / time.Second)
+}
+
+// maintenanceTogglePath is the one write allowed during maintenance, so it can be turned off again
+const maintenanceTogglePath = "/admin/maintenance"
+
+// MaintenanceMiddleware rejects writes with 503 and Retry-After while maintenance is on
+// Reads pass through, and so does the maintenance toggle; every other write, admin ones included, is paused
+func MaintenanceMiddleware(m *Maintenance) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if !m.Enabled() || isReadMethod(req.Method) || req.URL.Path == maintenanceTogglePath {
+				return next(c)
+			}
+
+			c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(m.retryAfterSeconds()))
+			return writeError(c, http.StatusServiceUnavailable, problemMaintenance, "down for maintenance, writes are paused")
+		}
+	}
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+type MaintenanceRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+type MaintenanceResponse struct {
+	Enabled           bool `json:"enabled"`
+	RetryAfterSeconds int  `json:"retry_after_seconds"`
+}
+
+// maintenanceHandler turns maintenance on or off and reports the resulting state
+func maintenanceHandler(m *Maintenance) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req MaintenanceRequest
+		if err := c.Bind(&req); err != nil || req.Enabled == nil {
+			return badRequest(c, "invalid request body, expected {\"enabled\": true|false}")
+		}
+
+		m.SetEnabled(*req.Enabled)
+
+		return c.JSON(http.StatusOK, MaintenanceResponse{Enabled: m.Enabled(), RetryAfterSeconds: m.retryAfterSeconds()})
+	}
+}
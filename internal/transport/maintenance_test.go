@@ -0,0 +1,59 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceMiddleware(t *testing.T) {
+	maintenance := NewMaintenance(false, 2*time.Minute)
+
+	e := echo.New()
+	e.Use(MaintenanceMiddleware(maintenance))
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	e.GET("/events", ok)
+	e.POST("/bookings", ok)
+	e.PATCH("/events/:id", ok)
+	e.DELETE("/events/:id", ok)
+	e.PUT("/events/:id", ok)
+	e.POST("/admin/bookings", ok)
+	e.POST("/admin/maintenance", maintenanceHandler(maintenance))
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/bookings", "").Code, "writes pass while maintenance is off")
+
+	rec := serve(http.MethodPost, "/admin/maintenance", `{"enabled": true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"enabled": true, "retry_after_seconds": 120}`, rec.Body.String())
+
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		path := "/events/42"
+		if method == http.MethodPost {
+			path = "/bookings"
+		}
+		rec := serve(method, path, "")
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code, method)
+		assert.Equal(t, "120", rec.Header().Get(echo.HeaderRetryAfter), method)
+	}
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/events", "").Code, "reads keep working")
+	assert.Equal(t, http.StatusServiceUnavailable, serve(http.MethodPost, "/admin/bookings", "").Code, "admin writes are paused too")
+
+	rec = serve(http.MethodPost, "/admin/maintenance", `{"enabled": false}`)
+	assert.Equal(t, http.StatusOK, rec.Code, "the toggle stays writable")
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/bookings", "").Code)
+
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPost, "/admin/maintenance", `{}`).Code)
+}
@@ -0,0 +1,116 @@
+package transport
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+var (
+	errInvalidLimit  = errors.New("invalid limit")
+	errInvalidOffset = errors.New("invalid offset")
+	errInvalidCursor = errors.New("invalid cursor")
+	errCursorOffset  = errors.New("offset is not supported by cursor-paginated endpoints, use cursor")
+)
+
+// PagedResponse is the envelope every list endpoint returns
+// Offset-paginated endpoints echo Offset; cursor-paginated ones set NextCursor until the last page
+type PagedResponse[T any] struct {
+	Data       []T    `json:"data"`
+	TotalCount int    `json:"total_count"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// newPagedResponse maps items into an envelope; Data is an empty array rather than null when there are none
+func newPagedResponse[S any, T any](items []S, totalCount int, page Pagination, toResponse func(S) T) PagedResponse[T] {
+	data := make([]T, 0, len(items))
+	for _, item := range items {
+		data = append(data, toResponse(item))
+	}
+
+	return PagedResponse[T]{Data: data, TotalCount: totalCount, Limit: page.Limit, Offset: page.Offset}
+}
+
+// Pagination holds offset-based paging parameters parsed from the query string
+type Pagination struct {
+	Limit  int
+	Offset int
+}
+
+// parsePagination reads ?limit= and ?offset=, applying the default limit and capping it at maxPageLimit
+func parsePagination(c echo.Context) (Pagination, error) {
+	return parsePaginationWithDefault(c, defaultPageLimit)
+}
+
+// parsePaginationWithDefault is parsePagination for endpoints with their own default limit
+func parsePaginationWithDefault(c echo.Context, defaultLimit int) (Pagination, error) {
+	page := Pagination{Limit: defaultLimit}
+
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return Pagination{}, errInvalidLimit
+		}
+		page.Limit = min(limit, maxPageLimit)
+	}
+
+	if raw := c.QueryParam("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return Pagination{}, errInvalidOffset
+		}
+		page.Offset = offset
+	}
+
+	return page, nil
+}
+
+// encodeEventCursor makes an opaque next_cursor token from a listing position
+func encodeEventCursor(cursor *domain.EventCursor) string {
+	if cursor == nil {
+		return ""
+	}
+	raw := cursor.Date.UTC().Format(time.RFC3339Nano) + "|" + cursor.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// parseEventCursor reads ?cursor=, returning nil for the first page
+func parseEventCursor(c echo.Context) (*domain.EventCursor, error) {
+	token := c.QueryParam("cursor")
+	if token == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	rawDate, rawID, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, errInvalidCursor
+	}
+	date, err := time.Parse(time.RFC3339Nano, rawDate)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	return &domain.EventCursor{Date: date, ID: id}, nil
+}
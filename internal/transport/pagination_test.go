@@ -0,0 +1,67 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPagedResponse_OffsetEnvelope(t *testing.T) {
+	response := newPagedResponse([]int{1, 2}, 7, Pagination{Limit: 2, Offset: 4}, strconv.Itoa)
+
+	body, err := json.Marshal(response)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"data":["1","2"],"total_count":7,"limit":2,"offset":4}`, string(body))
+}
+
+func TestPagedResponse_EmptyDataIsNotNull(t *testing.T) {
+	response := newPagedResponse[int](nil, 0, Pagination{Limit: 20}, strconv.Itoa)
+
+	body, err := json.Marshal(response)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"data":[],"total_count":0,"limit":20,"offset":0}`, string(body))
+}
+
+func TestPagedResponse_CursorEnvelope(t *testing.T) {
+	cursor := &domain.EventCursor{Date: time.Date(2026, 7, 4, 20, 30, 0, 123000, time.UTC), ID: uuid.New()}
+	response := newPagedResponse([]int{1}, 3, Pagination{Limit: 1}, strconv.Itoa)
+	response.NextCursor = encodeEventCursor(cursor)
+
+	body, err := json.Marshal(response)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"data":["1"],"total_count":3,"limit":1,"offset":0,"next_cursor":"`+response.NextCursor+`"}`, string(body))
+
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/events?cursor="+response.NextCursor, nil), httptest.NewRecorder())
+	decoded, err := parseEventCursor(c)
+	require.NoError(t, err)
+	assert.True(t, cursor.Date.Equal(decoded.Date))
+	assert.Equal(t, cursor.ID, decoded.ID)
+
+	assert.Empty(t, encodeEventCursor(nil), "the last page has no next cursor")
+}
+
+func TestListEvents_RejectsInvalidPaging(t *testing.T) {
+	e := echo.New()
+	handler := NewEventHandler(nil, zerolog.Nop())
+	e.GET("/events", handler.ListEvents)
+
+	for _, query := range []string{"?cursor=not-a-cursor", "?cursor=" + "bm8tc2VwYXJhdG9y", "?offset=20", "?limit=0"} {
+		t.Run(query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events"+query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	}
+}
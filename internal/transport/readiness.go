@@ -0,0 +1,170 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	readinessReady    = "ready"
+	readinessDegraded = "degraded"
+	readinessNotReady = "not_ready"
+)
+
+type WorkerStatusResponse struct {
+	Name          string    `json:"name"`
+	Status        string    `json:"status"` // "ok" or "degraded"
+	LastHeartbeat time.Time `json:"last_heartbeat"`
+	MaxSilence    string    `json:"max_silence"`
+}
+
+type DatabaseHealthResponse struct {
+	Latency       string              `json:"latency"`
+	ServerVersion string              `json:"server_version"`
+	Pool          DBPoolStatsResponse `json:"pool"`
+}
+
+// ReplicaStatusResponse reports one read replica; Lag is empty when the replica could not be reached
+type ReplicaStatusResponse struct {
+	Name       string  `json:"name"`
+	Status     string  `json:"status"` // "ok", "degraded" or "unreachable"
+	Lag        string  `json:"lag,omitempty"`
+	LagSeconds float64 `json:"lag_seconds"`
+	MaxLag     string  `json:"max_lag"`
+}
+
+// DependencyStatusResponse reports one external dependency; Latency is how long its probe took
+type DependencyStatusResponse struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"` // "ok" or "unreachable"
+	Critical bool   `json:"critical"`
+	Latency  string `json:"latency"`
+}
+
+type ReadinessResponse struct {
+	Status         string                     `json:"status"`
+	Database       string                     `json:"database"`
+	DatabaseHealth *DatabaseHealthResponse    `json:"database_health,omitempty"`
+	Workers        []WorkerStatusResponse     `json:"workers"`
+	Replicas       []ReplicaStatusResponse    `json:"replicas,omitempty"`
+	Dependencies   []DependencyStatusResponse `json:"dependencies,omitempty"`
+}
+
+// Replica is a named read replica whose replication lag readiness reports
+type Replica struct {
+	Name string
+	Lag  infrastructure.ReplicationLagChecker
+}
+
+// ReplicaLagCheck lists the read replicas to check and how far they may trail the primary
+type ReplicaLagCheck struct {
+	Replicas []Replica
+	MaxLag   time.Duration
+}
+
+// readinessHandler reports database reachability and background worker liveness
+// A reachable database also reports its round-trip latency, server version and pool statistics
+// An unreachable database fails readiness with 503; a stalled worker only marks the response degraded,
+// since taking the instance out of rotation would not restart the worker
+// A read replica lagging more than its check allows, or unreachable, also only marks the response degraded:
+// the primary still serves every request, and the replica is only read from when the primary is down
+// An unreachable external dependency fails readiness when it is critical and otherwise marks it degraded,
+// e.g. bookings are still taken while the broker their events are published to is down
+func readinessHandler(
+	db infrastructure.DBClient,
+	workers *infrastructure.WorkerRegistry,
+	replicaLag *ReplicaLagCheck,
+	dependencies *infrastructure.DependencyChecker,
+) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		response := ReadinessResponse{
+			Status:   readinessReady,
+			Database: "ok",
+			Workers:  []WorkerStatusResponse{},
+		}
+
+		for _, worker := range workers.Statuses() {
+			status := "ok"
+			if !worker.Healthy {
+				status = readinessDegraded
+				response.Status = readinessDegraded
+			}
+			response.Workers = append(response.Workers, WorkerStatusResponse{
+				Name:          worker.Name,
+				Status:        status,
+				LastHeartbeat: worker.LastHeartbeat,
+				MaxSilence:    worker.MaxSilence.String(),
+			})
+		}
+
+		if replicaLag != nil {
+			for _, replica := range replicaLag.Replicas {
+				status := replicaLag.status(c.Request().Context(), replica)
+				if status.Status != "ok" {
+					response.Status = readinessDegraded
+				}
+				response.Replicas = append(response.Replicas, status)
+			}
+		}
+
+		criticalDown := false
+		if dependencies != nil {
+			for _, dependency := range dependencies.Check(c.Request().Context()) {
+				status := "ok"
+				if !dependency.Healthy {
+					status = "unreachable"
+					criticalDown = criticalDown || dependency.Critical
+					response.Status = readinessDegraded
+				}
+				response.Dependencies = append(response.Dependencies, DependencyStatusResponse{
+					Name:     dependency.Name,
+					Status:   status,
+					Critical: dependency.Critical,
+					Latency:  dependency.Latency.String(),
+				})
+			}
+		}
+
+		health, err := db.HealthCheck(c.Request().Context())
+		if err != nil {
+			response.Status = readinessNotReady
+			response.Database = "unreachable"
+			return c.JSON(http.StatusServiceUnavailable, response)
+		}
+		response.DatabaseHealth = &DatabaseHealthResponse{
+			Latency:       health.Latency.String(),
+			ServerVersion: health.ServerVersion,
+			Pool:          toDBPoolStatsResponse(health.Pool),
+		}
+		if criticalDown {
+			response.Status = readinessNotReady
+			return c.JSON(http.StatusServiceUnavailable, response)
+		}
+
+		return c.JSON(http.StatusOK, response)
+	}
+}
+
+func (check *ReplicaLagCheck) status(ctx context.Context, replica Replica) ReplicaStatusResponse {
+	response := ReplicaStatusResponse{
+		Name:   replica.Name,
+		Status: "ok",
+		MaxLag: check.MaxLag.String(),
+	}
+
+	lag, err := replica.Lag.ReplicationLag(ctx)
+	if err != nil {
+		response.Status = "unreachable"
+		return response
+	}
+	response.Lag = lag.String()
+	response.LagSeconds = lag.Seconds()
+	if lag > check.MaxLag {
+		response.Status = readinessDegraded
+	}
+	return response
+}
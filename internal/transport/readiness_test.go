@@ -0,0 +1,257 @@
+package transport
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePinger struct {
+	infrastructure.DBClient
+	err error
+}
+
+func (db *fakePinger) HealthCheck(ctx context.Context) (infrastructure.HealthInfo, error) {
+	if db.err != nil {
+		return infrastructure.HealthInfo{}, db.err
+	}
+	return infrastructure.HealthInfo{
+		Latency:       2 * time.Millisecond,
+		ServerVersion: "16.4",
+		Pool:          sql.DBStats{OpenConnections: 3, InUse: 1, Idle: 2},
+	}, nil
+}
+
+type stubReplicationLag struct {
+	lag time.Duration
+	err error
+}
+
+func (s stubReplicationLag) ReplicationLag(ctx context.Context) (time.Duration, error) {
+	return s.lag, s.err
+}
+
+func TestReadinessHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		pingErr        error
+		stalled        bool
+		wantCode       int
+		wantStatus     string
+		wantWorkerStat string
+	}{
+		{
+			name:           "ready when database and workers are healthy",
+			wantCode:       http.StatusOK,
+			wantStatus:     readinessReady,
+			wantWorkerStat: "ok",
+		},
+		{
+			name:           "degraded when a worker stalls",
+			stalled:        true,
+			wantCode:       http.StatusOK,
+			wantStatus:     readinessDegraded,
+			wantWorkerStat: readinessDegraded,
+		},
+		{
+			name:           "not ready when database is unreachable",
+			pingErr:        errors.New("connection refused"),
+			wantCode:       http.StatusServiceUnavailable,
+			wantStatus:     readinessNotReady,
+			wantWorkerStat: "ok",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maxSilence := time.Hour
+			if tt.stalled {
+				maxSilence = time.Millisecond
+			}
+			workers := infrastructure.NewWorkerRegistry()
+			workers.Register("hold_sweeper", maxSilence)
+			if tt.stalled {
+				// No heartbeat follows registration, so the worker outlives its window
+				time.Sleep(5 * time.Millisecond)
+			}
+
+			e := echo.New()
+			e.GET("/readyz", readinessHandler(&fakePinger{err: tt.pingErr}, workers, nil, nil))
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+
+			var response ReadinessResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantStatus, response.Status)
+			require.Len(t, response.Workers, 1)
+			assert.Equal(t, "hold_sweeper", response.Workers[0].Name)
+			assert.Equal(t, tt.wantWorkerStat, response.Workers[0].Status)
+
+			if tt.pingErr != nil {
+				assert.Nil(t, response.DatabaseHealth)
+				return
+			}
+			require.NotNil(t, response.DatabaseHealth)
+			assert.Equal(t, "16.4", response.DatabaseHealth.ServerVersion)
+			assert.Equal(t, "2ms", response.DatabaseHealth.Latency)
+			assert.Equal(t, 3, response.DatabaseHealth.Pool.OpenConnections)
+		})
+	}
+}
+
+func TestReadinessHandler_ReplicaLag(t *testing.T) {
+	tests := []struct {
+		name       string
+		replicas   []Replica
+		wantStatus string
+		wantLags   map[string]string
+	}{
+		{
+			name: "ready when replicas are within the allowed lag",
+			replicas: []Replica{
+				{Name: "replica-a", Lag: stubReplicationLag{lag: 2 * time.Second}},
+				{Name: "replica-b", Lag: stubReplicationLag{}},
+			},
+			wantStatus: readinessReady,
+			wantLags:   map[string]string{"replica-a": "ok", "replica-b": "ok"},
+		},
+		{
+			name: "degraded when a replica lags too far",
+			replicas: []Replica{
+				{Name: "replica-a", Lag: stubReplicationLag{lag: 2 * time.Second}},
+				{Name: "replica-b", Lag: stubReplicationLag{lag: 45 * time.Second}},
+			},
+			wantStatus: readinessDegraded,
+			wantLags:   map[string]string{"replica-a": "ok", "replica-b": readinessDegraded},
+		},
+		{
+			name:       "degraded when a replica is unreachable",
+			replicas:   []Replica{{Name: "replica-a", Lag: stubReplicationLag{err: errors.New("connection refused")}}},
+			wantStatus: readinessDegraded,
+			wantLags:   map[string]string{"replica-a": "unreachable"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := &ReplicaLagCheck{Replicas: tt.replicas, MaxLag: 30 * time.Second}
+			e := echo.New()
+			e.GET("/readyz", readinessHandler(&fakePinger{}, infrastructure.NewWorkerRegistry(), check, nil))
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			assert.Equal(t, http.StatusOK, rec.Code, "lagging replicas do not take the instance out of rotation")
+
+			var response ReadinessResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantStatus, response.Status)
+			require.Len(t, response.Replicas, len(tt.replicas))
+			for i, replica := range response.Replicas {
+				assert.Equal(t, tt.replicas[i].Name, replica.Name, "replicas are reported in order")
+				assert.Equal(t, tt.wantLags[replica.Name], replica.Status)
+				assert.Equal(t, "30s", replica.MaxLag)
+			}
+		})
+	}
+
+	t.Run("reports each replica's lag", func(t *testing.T) {
+		check := &ReplicaLagCheck{
+			Replicas: []Replica{{Name: "replica-a", Lag: stubReplicationLag{lag: 1500 * time.Millisecond}}},
+			MaxLag:   time.Second,
+		}
+		e := echo.New()
+		e.GET("/readyz", readinessHandler(&fakePinger{}, infrastructure.NewWorkerRegistry(), check, nil))
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		var response ReadinessResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.Len(t, response.Replicas, 1)
+		assert.Equal(t, "1.5s", response.Replicas[0].Lag)
+		assert.InDelta(t, 1.5, response.Replicas[0].LagSeconds, 0.001)
+		assert.Equal(t, readinessDegraded, response.Replicas[0].Status)
+	})
+}
+
+func TestReadinessHandler_Dependencies(t *testing.T) {
+	healthy := func(ctx context.Context) error { return nil }
+	failing := func(ctx context.Context) error { return errors.New("dial tcp: connection refused") }
+
+	tests := []struct {
+		name       string
+		register   func(checker *infrastructure.DependencyChecker)
+		wantCode   int
+		wantStatus string
+		wantDeps   map[string]string
+	}{
+		{
+			name: "ready when every dependency is reachable",
+			register: func(checker *infrastructure.DependencyChecker) {
+				checker.Register("kafka", false, healthy)
+				checker.Register("payments", true, healthy)
+			},
+			wantCode:   http.StatusOK,
+			wantStatus: readinessReady,
+			wantDeps:   map[string]string{"kafka": "ok", "payments": "ok"},
+		},
+		{
+			name: "degraded but serving when a non-critical dependency is down",
+			register: func(checker *infrastructure.DependencyChecker) {
+				checker.Register("kafka", false, failing)
+				checker.Register("payments", true, healthy)
+			},
+			wantCode:   http.StatusOK,
+			wantStatus: readinessDegraded,
+			wantDeps:   map[string]string{"kafka": "unreachable", "payments": "ok"},
+		},
+		{
+			name: "not ready when a critical dependency is down",
+			register: func(checker *infrastructure.DependencyChecker) {
+				checker.Register("kafka", false, healthy)
+				checker.Register("payments", true, failing)
+			},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: readinessNotReady,
+			wantDeps:   map[string]string{"kafka": "ok", "payments": "unreachable"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := infrastructure.NewDependencyChecker(time.Second)
+			tt.register(checker)
+			e := echo.New()
+			e.GET("/readyz", readinessHandler(&fakePinger{}, infrastructure.NewWorkerRegistry(), nil, checker))
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+
+			var response ReadinessResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantStatus, response.Status)
+			assert.Equal(t, "ok", response.Database)
+			require.Len(t, response.Dependencies, len(tt.wantDeps))
+			for _, dependency := range response.Dependencies {
+				assert.Equal(t, tt.wantDeps[dependency.Name], dependency.Status, dependency.Name)
+				assert.Equal(t, dependency.Name == "payments", dependency.Critical)
+			}
+		})
+	}
+}
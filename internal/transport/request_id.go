@@ -0,0 +1,100 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// traceparentHeader is the W3C Trace Context header; only its trace-id identifies the request across hops
+const traceparentHeader = "Traceparent"
+
+// maxRequestIDLen bounds incoming request IDs, which end up in every log line of the request
+const maxRequestIDLen = 128
+
+// DefaultRequestIDHeaders are consulted when no request ID headers are configured
+var DefaultRequestIDHeaders = []string{echo.HeaderXRequestID}
+
+var (
+	headerNamePattern  = regexp.MustCompile(`^[A-Za-z0-9!#$%&'*+.^_|~-]+$`)
+	traceparentPattern = regexp.MustCompile(`^[0-9a-f]{2}-([0-9a-f]{32})-[0-9a-f]{16}-[0-9a-f]{2}$`)
+)
+
+// ParseRequestIDHeaders splits a comma-separated list of header names, in order of preference
+func ParseRequestIDHeaders(raw string) ([]string, error) {
+	var headers []string
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !headerNamePattern.MatchString(entry) {
+			return nil, fmt.Errorf("invalid request ID header %q", entry)
+		}
+		headers = append(headers, http.CanonicalHeaderKey(entry))
+	}
+	return headers, nil
+}
+
+// RequestIDMiddleware takes the request ID from the first of headers the request carries, generating one otherwise
+// The ID is stored as X-Request-ID on the request, where the logging middleware reads it, and returned in the
+// response as X-Request-ID and under the header it came from; traceparent is not echoed as it names a span
+func RequestIDMiddleware(headers []string) echo.MiddlewareFunc {
+	if len(headers) == 0 {
+		headers = DefaultRequestIDHeaders
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			res := c.Response()
+
+			id, source := incomingRequestID(req.Header, headers)
+			if id == "" {
+				id = uuid.NewString()
+			}
+
+			req.Header.Set(echo.HeaderXRequestID, id)
+			res.Header().Set(echo.HeaderXRequestID, id)
+			if source != "" && source != traceparentHeader {
+				res.Header().Set(source, id)
+			}
+			return next(c)
+		}
+	}
+}
+
+// incomingRequestID returns the first usable ID among headers and the header it was read from
+// Overlong values or ones with control characters are skipped rather than logged
+func incomingRequestID(header http.Header, headers []string) (string, string) {
+	for _, name := range headers {
+		value := strings.TrimSpace(header.Get(name))
+		if name == traceparentHeader {
+			match := traceparentPattern.FindStringSubmatch(value)
+			if match == nil {
+				continue
+			}
+			value = match[1]
+		}
+		if validRequestID(value) {
+			return value, name
+		}
+	}
+	return "", ""
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x20 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
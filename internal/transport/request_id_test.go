@@ -0,0 +1,114 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	headers := []string{"X-Correlation-Id", traceparentHeader, echo.HeaderXRequestID}
+
+	tests := []struct {
+		name          string
+		incoming      map[string]string
+		wantID        string
+		wantEchoedAs  string
+		wantGenerated bool
+	}{
+		{
+			name:         "honors a correlation header",
+			incoming:     map[string]string{"X-Correlation-ID": "corr-123"},
+			wantID:       "corr-123",
+			wantEchoedAs: "X-Correlation-Id",
+		},
+		{
+			name:     "prefers headers earlier in the list",
+			incoming: map[string]string{"X-Correlation-ID": "corr-123", echo.HeaderXRequestID: "req-456"},
+			wantID:   "corr-123",
+		},
+		{
+			name:     "uses the trace-id of traceparent",
+			incoming: map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			wantID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:     "skips a malformed traceparent",
+			incoming: map[string]string{"traceparent": "garbage", echo.HeaderXRequestID: "req-456"},
+			wantID:   "req-456",
+		},
+		{
+			name:          "skips values with control characters",
+			incoming:      map[string]string{"X-Correlation-ID": "bad\x01id"},
+			wantGenerated: true,
+		},
+		{
+			name:          "generates one when absent",
+			wantGenerated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(RequestIDMiddleware(headers))
+			var seen string
+			e.GET("/", func(c echo.Context) error {
+				seen = c.Request().Header.Get(echo.HeaderXRequestID)
+				return c.NoContent(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for name, value := range tt.incoming {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			got := rec.Header().Get(echo.HeaderXRequestID)
+			assert.Equal(t, got, seen, "handlers and logs see the returned ID")
+			if tt.wantGenerated {
+				_, err := uuid.Parse(got)
+				require.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.wantID, got)
+			}
+			if tt.wantEchoedAs != "" {
+				assert.Equal(t, tt.wantID, rec.Header().Get(tt.wantEchoedAs))
+			}
+			assert.Empty(t, rec.Header().Get(traceparentHeader))
+		})
+	}
+}
+
+func TestRequestIDMiddleware_DefaultsToXRequestID(t *testing.T) {
+	e := echo.New()
+	e.Use(RequestIDMiddleware(nil))
+	e.GET("/", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderXRequestID, "req-456")
+	req.Header.Set("X-Correlation-ID", "corr-123")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, "req-456", rec.Header().Get(echo.HeaderXRequestID))
+}
+
+func TestParseRequestIDHeaders(t *testing.T) {
+	headers, err := ParseRequestIDHeaders(" x-correlation-id, traceparent ,,")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"X-Correlation-Id", traceparentHeader}, headers)
+
+	headers, err = ParseRequestIDHeaders("")
+	require.NoError(t, err)
+	assert.Empty(t, headers)
+
+	_, err = ParseRequestIDHeaders("X Correlation")
+	assert.Error(t, err)
+}
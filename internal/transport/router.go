@@ -1,6 +1,7 @@
 package transport
 
 import (
+	"net"
 	"net/http"
 	"strconv"
 	"time"
@@ -13,45 +14,347 @@ import (
 	"github.com/rs/zerolog"
 )
 
+type routerConfig struct {
+	adminTokens    map[string]string
+	breaker        *infrastructure.CircuitBreaker
+	trustedProxies []*net.IPNet
+	allowedOrigins *OriginAllowlist
+	maintenance    *Maintenance
+	requestIDs     []string
+	unprocessable  bool
+	adminTimeout   time.Duration
+	webhooks       *app.WebhookService
+	allocations    *app.AllocationService
+	lottery        *app.LotteryService
+	eventMerge     *app.EventMergeService
+	txRoutes       []string
+	replicaLag     *ReplicaLagCheck
+	dependencies   *infrastructure.DependencyChecker
+	slowResponse   time.Duration
+	deprecations   Deprecations
+
+	ticketCountsAsStrings bool
+}
+
+// RouterOption configures optional router behaviour
+type RouterOption func(*routerConfig)
+
+// WithAdminToken protects /admin routes with a static bearer token, authenticating DefaultAdminName
+func WithAdminToken(token string) RouterOption {
+	if token == "" {
+		return func(c *routerConfig) {}
+	}
+	return WithAdminTokens(map[string]string{DefaultAdminName: token})
+}
+
+// WithAdminTokens protects /admin routes with a bearer token per admin, keyed by admin name, so the acting
+// admin is known from the token
+func WithAdminTokens(tokens map[string]string) RouterOption {
+	return func(c *routerConfig) {
+		if c.adminTokens == nil {
+			c.adminTokens = make(map[string]string, len(tokens))
+		}
+		for name, token := range tokens {
+			c.adminTokens[name] = token
+		}
+	}
+}
+
+// WithCircuitBreaker reports the database circuit breaker state from /health
+func WithCircuitBreaker(breaker *infrastructure.CircuitBreaker) RouterOption {
+	return func(c *routerConfig) {
+		c.breaker = breaker
+	}
+}
+
+// WithTrustedProxies honors X-Forwarded-For for requests arriving from these ranges
+// Without it the client IP is always the connection's remote address
+func WithTrustedProxies(ranges []*net.IPNet) RouterOption {
+	return func(c *routerConfig) {
+		c.trustedProxies = ranges
+	}
+}
+
+// WithAllowedOrigins enables CORS for origins on the allowlist; other origins get no CORS headers
+func WithAllowedOrigins(allowlist *OriginAllowlist) RouterOption {
+	return func(c *routerConfig) {
+		c.allowedOrigins = allowlist
+	}
+}
+
+// WithMaintenance shares the maintenance toggle with the caller, e.g. to start with writes paused
+// Without it the router starts with maintenance off
+func WithMaintenance(m *Maintenance) RouterOption {
+	return func(c *routerConfig) {
+		c.maintenance = m
+	}
+}
+
+// WithRequestIDHeaders reads the request ID from the first of these headers present, e.g. X-Correlation-ID
+// Without it only X-Request-ID is honored
+func WithRequestIDHeaders(headers []string) RouterOption {
+	return func(c *routerConfig) {
+		c.requestIDs = headers
+	}
+}
+
+// WithUnprocessableValidation answers domain validation failures with 422 instead of 400
+// Without it every invalid request answers 400, as existing clients expect
+func WithUnprocessableValidation(enabled bool) RouterOption {
+	return func(c *routerConfig) {
+		c.unprocessable = enabled
+	}
+}
+
+// WithAdminStatementTimeout lets statements of /admin routes, such as exports, run up to timeout
+// Without it, or with zero, they are bound by the connection's statement timeout like any other route
+func WithAdminStatementTimeout(timeout time.Duration) RouterOption {
+	return func(c *routerConfig) {
+		c.adminTimeout = timeout
+	}
+}
+
+// WithWebhooks serves the admin routes managing per-event webhooks; without it they are not registered
+func WithWebhooks(service *app.WebhookService) RouterOption {
+	return func(c *routerConfig) {
+		c.webhooks = service
+	}
+}
+
+// WithAllocations serves the admin routes managing events' allocation blocks; without it they are not registered
+func WithAllocations(service *app.AllocationService) RouterOption {
+	return func(c *routerConfig) {
+		c.allocations = service
+	}
+}
+
+// WithLottery serves the routes registering for and drawing events' lotteries; without it they are not registered
+func WithLottery(service *app.LotteryService) RouterOption {
+	return func(c *routerConfig) {
+		c.lottery = service
+	}
+}
+
+// WithEventMerge serves the admin route merging duplicate events; without it the route is not registered
+func WithEventMerge(service *app.EventMergeService) RouterOption {
+	return func(c *routerConfig) {
+		c.eventMerge = service
+	}
+}
+
+// WithTransactionalRoutes runs each of the routes, given as "METHOD /path", in one request transaction
+// The router's db must be an infrastructure.RequestScopedDBClient shared with the repositories
+func WithTransactionalRoutes(routes []string) RouterOption {
+	return func(c *routerConfig) {
+		c.txRoutes = routes
+	}
+}
+
+// WithReplicaLagCheck reports the replication lag of each replica from /readyz, degrading readiness
+// when one trails the primary by more than maxLag
+func WithReplicaLagCheck(maxLag time.Duration, replicas ...Replica) RouterOption {
+	return func(c *routerConfig) {
+		c.replicaLag = &ReplicaLagCheck{Replicas: replicas, MaxLag: maxLag}
+	}
+}
+
+// WithDependencyChecker reports the external dependencies registered with checker from /readyz
+func WithDependencyChecker(checker *infrastructure.DependencyChecker) RouterOption {
+	return func(c *routerConfig) {
+		c.dependencies = checker
+	}
+}
+
+// WithTicketCountsAsStrings writes ticket counts as JSON strings unless the client sends X-Ticket-Count-Format: number
+// Without it counts are numbers unless the client sends X-Ticket-Count-Format: string
+func WithTicketCountsAsStrings(enabled bool) RouterOption {
+	return func(c *routerConfig) {
+		c.ticketCountsAsStrings = enabled
+	}
+}
+
+// WithSlowResponseThreshold logs a warning for requests taking longer than threshold; zero disables it
+// Without it the threshold is DefaultSlowResponseThreshold
+func WithSlowResponseThreshold(threshold time.Duration) RouterOption {
+	return func(c *routerConfig) {
+		c.slowResponse = threshold
+	}
+}
+
+// WithDeprecations marks the routes and request fields in deprecations as deprecated
+// Without it the router uses the API's own list of deprecations
+func WithDeprecations(deprecations Deprecations) RouterOption {
+	return func(c *routerConfig) {
+		c.deprecations = deprecations
+	}
+}
+
 func NewRouter(
 	eventService *app.EventService,
 	bookingService *app.BookingService,
+	holdService *app.HoldService,
 	db infrastructure.DBClient,
+	workers *infrastructure.WorkerRegistry,
 	logger zerolog.Logger,
+	opts ...RouterOption,
 ) *echo.Echo {
+	cfg := &routerConfig{
+		maintenance:  NewMaintenance(false, DefaultMaintenanceRetryAfter),
+		slowResponse: DefaultSlowResponseThreshold,
+		deprecations: apiDeprecations,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
 	e := echo.New()
 	e.HideBanner = true
+	e.IPExtractor = newIPExtractor(cfg.trustedProxies)
+	e.JSONSerializer = jsonSerializer{ticketCountsAsStrings: cfg.ticketCountsAsStrings}
 
-	e.Use(middleware.RequestID())
+	e.Use(RequestIDMiddleware(cfg.requestIDs))
+	e.Use(ClientIPMiddleware())
 	e.Use(LoggingMiddleware(logger))
 	e.Use(MetricsMiddleware())
+	e.Use(ServerTimingMiddleware(logger, cfg.slowResponse))
 	e.Use(middleware.Recover())
+	if !cfg.allowedOrigins.Empty() {
+		e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+			AllowOriginFunc: func(origin string) (bool, error) {
+				return cfg.allowedOrigins.Allowed(origin), nil
+			},
+			AllowHeaders:  []string{echo.HeaderContentType, echo.HeaderAuthorization, idempotencyKeyHeader, tenantHeader, HeaderTicketCountFormat},
+			ExposeHeaders: append([]string{echo.HeaderXRequestID, echo.HeaderLastModified, echo.HeaderRetryAfter, HeaderServerTiming, HeaderDeprecation, HeaderSunset, "Link"}, cfg.requestIDs...),
+		}))
+	}
+	if cfg.unprocessable {
+		e.Use(ValidationStatusMiddleware(http.StatusUnprocessableEntity))
+	}
+	e.Use(MaintenanceMiddleware(cfg.maintenance))
+	e.Use(TenantMiddleware())
+	if !cfg.deprecations.empty() {
+		e.Use(DeprecationMiddleware(cfg.deprecations))
+	}
+	publicTxRoutes, adminTxRoutes := splitAdminRoutes(cfg.txRoutes)
+	if len(publicTxRoutes) > 0 {
+		e.Use(transactionalRoutes(publicTxRoutes, db, logger))
+	}
 
 	eventHandler := NewEventHandler(eventService, logger)
-	bookingHandler := NewBookingHandler(bookingService, logger)
+	bookingHandler := NewBookingHandler(bookingService, holdService, logger)
+	holdHandler := NewHoldHandler(holdService, logger)
 
 	e.POST("/events", eventHandler.CreateEvent)
 	e.GET("/events", eventHandler.ListEvents)
+	e.GET("/events/count", eventHandler.CountEvents)
+	e.GET("/events/upcoming", eventHandler.ListUpcomingEvents)
 	e.GET("/events/:id", eventHandler.GetEvent)
+	e.POST("/events/:id/cancel", eventHandler.CancelEvent)
+	e.POST("/events/:id/pause", eventHandler.PauseBookings)
+	e.POST("/events/:id/resume", eventHandler.ResumeBookings)
+	e.GET("/events/:id/seats", eventHandler.GetSeatMap)
+	e.GET("/events/:id/utilization", eventHandler.GetUtilization)
+	e.GET("/events/:id/availability", eventHandler.GetAvailabilityAt)
+	e.GET("/events/:id/ledger", bookingHandler.GetEventLedger)
+	e.POST("/events/:id/quote", eventHandler.QuoteBooking)
+	e.POST("/events/:id/holds", holdHandler.CreateHold)
+	e.POST("/events/:id/buyout", bookingHandler.Buyout)
+	var lotteryHandler *LotteryHandler
+	if cfg.lottery != nil {
+		lotteryHandler = NewLotteryHandler(cfg.lottery, logger)
+		e.POST("/events/:id/lottery/register", lotteryHandler.RegisterLottery)
+	}
+
+	e.POST("/availability/check", eventHandler.CheckAvailability)
+
+	e.GET("/organizers/:id/dashboard", eventHandler.GetOrganizerDashboard)
 
 	e.POST("/bookings", bookingHandler.CreateBooking)
+	e.POST("/bookings/batch", bookingHandler.CreateBookings)
 	e.GET("/bookings/:id", bookingHandler.GetBooking)
+	e.POST("/bookings/:id/confirm", bookingHandler.ConfirmBooking)
+	// There is no user authentication to prove a booking's owner, so only support staff split bookings
+	e.POST("/bookings/:id/split", bookingHandler.SplitBooking, NamedAdminAuthMiddleware(cfg.adminTokens))
+	e.GET("/bookings/:id/history", bookingHandler.GetBookingHistory)
 
-	e.GET("/health", func(c echo.Context) error {
-		if err := db.PingContext(c.Request().Context()); err != nil {
-			return c.JSON(http.StatusServiceUnavailable, map[string]string{
-				"status":   "unhealthy",
-				"database": "unreachable",
-			})
-		}
-		return c.JSON(http.StatusOK, map[string]string{"status": "healthy"})
-	})
+	e.GET("/users/:id/events", eventHandler.ListUserEvents)
+	e.GET("/users/:id/ticket-summary", eventHandler.GetUserTicketSummary)
+	e.GET("/users/:id/bookings", bookingHandler.ListUserBookings)
+
+	e.GET("/holds/:id", holdHandler.GetHold)
+	e.POST("/holds/:id/confirm", holdHandler.ConfirmHold)
+
+	admin := e.Group("/admin", NamedAdminAuthMiddleware(cfg.adminTokens))
+	if cfg.adminTimeout > 0 {
+		admin.Use(StatementTimeoutMiddleware(cfg.adminTimeout))
+	}
+	if len(adminTxRoutes) > 0 {
+		admin.Use(transactionalRoutes(adminTxRoutes, db, logger))
+	}
+	admin.POST("/bookings", bookingHandler.AdminCreateBooking)
+	admin.GET("/bookings/export", bookingHandler.ExportBookings)
+	admin.GET("/bookings/search", bookingHandler.SearchBookings)
+	admin.GET("/events/export", eventHandler.ExportEvents)
+	admin.GET("/events/:id/availability", eventHandler.GetAvailability)
+	admin.PATCH("/events/:id/availability", eventHandler.AdjustAvailability)
+	admin.POST("/reconcile-all", eventHandler.ReconcileAllAvailability)
+	admin.POST("/events/:id/conditional-bookings/resolve", bookingHandler.ResolveConditionalBookings)
+	admin.POST("/users/:id/cancel-bookings", bookingHandler.CancelUserBookings)
+	admin.POST("/discount-codes", bookingHandler.CreateDiscountCode)
+	admin.POST("/events/import", eventHandler.ImportEvents)
+	if cfg.allocations != nil {
+		allocationHandler := NewAllocationHandler(cfg.allocations, logger)
+		admin.POST("/events/:id/allocations", allocationHandler.CreateAllocation)
+		admin.GET("/events/:id/allocations", allocationHandler.ListAllocations)
+	}
+	if lotteryHandler != nil {
+		admin.POST("/events/:id/lottery/draw", lotteryHandler.DrawLottery)
+	}
+	if cfg.webhooks != nil {
+		webhookHandler := NewWebhookHandler(cfg.webhooks, logger)
+		admin.POST("/events/:id/webhooks", webhookHandler.RegisterWebhook)
+		admin.DELETE("/events/:id/webhooks/:hookId", webhookHandler.DeleteWebhook)
+		admin.GET("/dead-letters", webhookHandler.ListDeadLetters)
+		admin.POST("/dead-letters/:id/retry", webhookHandler.RetryDeadLetter)
+	}
+	if cfg.eventMerge != nil {
+		admin.POST("/events/merge", NewEventMergeHandler(cfg.eventMerge, logger).MergeEvents)
+	}
+	admin.GET("/debug/runtime", runtimeStatsHandler(db))
+	admin.POST("/maintenance", maintenanceHandler(cfg.maintenance))
+
+	e.GET("/health", healthHandler(db, cfg.breaker))
+
+	e.GET("/readyz", readinessHandler(db, workers, cfg.replicaLag, cfg.dependencies))
 
 	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
 
 	return e
 }
 
+// healthHandler pings the database; with a breaker it also reports its state and is unhealthy while open
+func healthHandler(db infrastructure.DBClient, breaker *infrastructure.CircuitBreaker) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		response := map[string]string{"status": "healthy"}
+		if breaker != nil {
+			state := breaker.State()
+			response["circuit_breaker"] = state.String()
+			if state == infrastructure.BreakerOpen {
+				response["status"] = "unhealthy"
+				response["database"] = "circuit open"
+				return c.JSON(http.StatusServiceUnavailable, response)
+			}
+		}
+
+		if err := db.PingContext(c.Request().Context()); err != nil {
+			response["status"] = "unhealthy"
+			response["database"] = "unreachable"
+			return c.JSON(http.StatusServiceUnavailable, response)
+		}
+		return c.JSON(http.StatusOK, response)
+	}
+}
+
 func LoggingMiddleware(logger zerolog.Logger) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -62,6 +365,8 @@ func LoggingMiddleware(logger zerolog.Logger) echo.MiddlewareFunc {
 				Str("method", req.Method).
 				Str("path", req.URL.Path).
 				Str("request_id", req.Header.Get(echo.HeaderXRequestID)).
+				Str("client_ip", c.RealIP()).
+				Str("user_agent", req.UserAgent()).
 				Msg("incoming request")
 
 			err := next(c)
@@ -71,6 +376,8 @@ func LoggingMiddleware(logger zerolog.Logger) echo.MiddlewareFunc {
 				Str("path", req.URL.Path).
 				Int("status", res.Status).
 				Str("request_id", req.Header.Get(echo.HeaderXRequestID)).
+				Str("client_ip", c.RealIP()).
+				Str("user_agent", req.UserAgent()).
 				Msg("request completed")
 
 			return err
@@ -101,6 +408,11 @@ func MetricsMiddleware() echo.MiddlewareFunc {
 				strconv.Itoa(status),
 			).Observe(duration)
 
+			// Routes with their own SLO are also recorded with finer, group-specific buckets
+			if histogram := infrastructure.HTTPRequestDurationGroup(path); histogram != nil {
+				histogram.WithLabelValues(method, path, strconv.Itoa(status)).Observe(duration)
+			}
+
 			return err
 		}
 	}
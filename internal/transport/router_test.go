@@ -0,0 +1,65 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bookingRequestCount returns how many requests the booking histogram observed for the method, path and
+// status, so tests assert what they added to the process-wide metric regardless of other tests and -count
+func bookingRequestCount(t *testing.T, method, path, status string) uint64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	want := map[string]string{"method": method, "path": path, "status": status}
+	for _, family := range families {
+		if family.GetName() != "booking_service_http_booking_request_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			matched := 0
+			for _, label := range metric.GetLabel() {
+				if want[label.GetName()] == label.GetValue() {
+					matched++
+				}
+			}
+			if matched == len(want) {
+				return metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return 0
+}
+
+func TestMetricsMiddleware_ObservesBookingRoutesInBookingHistogram(t *testing.T) {
+	e := echo.New()
+	e.Use(MetricsMiddleware())
+	e.GET("/events", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+	e.GET("/bookings/:id", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	eventsBefore := bookingRequestCount(t, http.MethodGet, "/events", "200")
+	bookingsBefore := bookingRequestCount(t, http.MethodGet, "/bookings/:id", "200")
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/events", nil))
+	assert.Equal(t, eventsBefore, bookingRequestCount(t, http.MethodGet, "/events", "200"),
+		"non-booking routes must not be observed in the booking histogram")
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/bookings/123", nil))
+	assert.Equal(t, bookingsBefore+1, bookingRequestCount(t, http.MethodGet, "/bookings/:id", "200"))
+}
+
+func TestHTTPRequestDurationGroup(t *testing.T) {
+	assert.Equal(t, infrastructure.BookingHTTPRequestDuration, infrastructure.HTTPRequestDurationGroup("/bookings"))
+	assert.Equal(t, infrastructure.BookingHTTPRequestDuration, infrastructure.HTTPRequestDurationGroup("/bookings/:id"))
+	assert.Nil(t, infrastructure.HTTPRequestDurationGroup("/bookingsummary"))
+	assert.Nil(t, infrastructure.HTTPRequestDurationGroup("/admin/bookings/export"))
+	assert.Nil(t, infrastructure.HTTPRequestDurationGroup("/events"))
+}
@@ -0,0 +1,64 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/labstack/echo/v4"
+)
+
+// jsonSerializer writes snake_case JSON, as tagged on the response types, with ticket counts as numbers
+// Clients may ask for camelCase keys (see HeaderFieldCase) or ticket counts as strings (see HeaderTicketCountFormat)
+// Only the top-level keys of an object response are renamed; nested objects, array items and map keys
+// (e.g. the items of a paged response's data) keep their snake_case names
+// Responses of deprecated routes or fields list their deprecation warnings under a top-level "warnings" key,
+// and those of routes deprecating response fields are checked for the fields before they are written
+type jsonSerializer struct {
+	echo.DefaultJSONSerializer
+	// ticketCountsAsStrings is the ticket count format of clients not sending HeaderTicketCountFormat
+	ticketCountsAsStrings bool
+}
+
+func (s jsonSerializer) Serialize(c echo.Context, i interface{}, indent string) error {
+	c.Response().Header().Add(echo.HeaderVary, HeaderFieldCase)
+	c.Response().Header().Add(echo.HeaderVary, HeaderTicketCountFormat)
+	camel := requestedFieldCase(c) == fieldCaseCamel
+	asStrings := ticketCountsAsStrings(c, s.ticketCountsAsStrings)
+	if !camel && !asStrings && len(deprecationWarnings(c)) == 0 && !hasDeprecatedResponseFields(c) {
+		return s.DefaultJSONSerializer.Serialize(c, i, indent)
+	}
+
+	data, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+	if err := markDeprecatedResponseFields(c, data); err != nil {
+		return err
+	}
+	if warnings := deprecationWarnings(c); len(warnings) > 0 {
+		if data, err = withDeprecationWarnings(data, warnings); err != nil {
+			return err
+		}
+	}
+	// Ticket count keys are matched by their snake_case names, so counts are rewritten before renaming
+	if asStrings {
+		if data, err = stringifyTicketCounts(data); err != nil {
+			return err
+		}
+	}
+	if camel {
+		if data, err = camelCaseTopLevelKeys(data); err != nil {
+			return err
+		}
+	}
+
+	if indent != "" {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, data, "", indent); err != nil {
+			return err
+		}
+		data = indented.Bytes()
+	}
+	_, err = c.Response().Write(append(data, '\n'))
+	return err
+}
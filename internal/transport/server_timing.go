@@ -0,0 +1,70 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+// HeaderServerTiming reports how long the handler took, so clients can tell a slow server from a slow network
+const HeaderServerTiming = "Server-Timing"
+
+// DefaultSlowResponseThreshold is how long a request may take before it is logged as a slow response
+const DefaultSlowResponseThreshold = time.Second
+
+// ServerTimingMiddleware sets Server-Timing to the time spent until the response is written, e.g. "app;dur=12.5"
+// The header is added right before the headers go out, so it is present on responses written by handlers,
+// by the error handler and through middlewares that wrap the writer, such as gzip
+// Requests taking longer than slowThreshold are logged as a warning; zero disables the warning
+func ServerTimingMiddleware(logger zerolog.Logger, slowThreshold time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			res := c.Response()
+			res.Before(func() {
+				res.Header().Set(HeaderServerTiming, serverTiming(time.Since(start)))
+			})
+
+			err := next(c)
+
+			if elapsed := time.Since(start); slowThreshold > 0 && elapsed > slowThreshold {
+				req := c.Request()
+				logger.Warn().
+					Str("method", req.Method).
+					Str("path", req.URL.Path).
+					Str("route", c.Path()).
+					Int("status", responseStatus(res, err)).
+					Str("request_id", req.Header.Get(echo.HeaderXRequestID)).
+					Dur("duration", elapsed).
+					Dur("threshold", slowThreshold).
+					Msg("slow response")
+			}
+			return err
+		}
+	}
+}
+
+// responseStatus is the status the response was, or is about to be, written with
+// An error returned up the middleware chain is only answered later by echo's error handler, with the
+// HTTPError's code, or 500 for any other error, so the response's own status is still the default then
+func responseStatus(res *echo.Response, err error) int {
+	if err == nil || res.Committed {
+		return res.Status
+	}
+	he, ok := err.(*echo.HTTPError)
+	if !ok {
+		return http.StatusInternalServerError
+	}
+	if internal, ok := he.Internal.(*echo.HTTPError); ok {
+		return internal.Code
+	}
+	return he.Code
+}
+
+// serverTiming formats d as a Server-Timing metric in milliseconds
+func serverTiming(d time.Duration) string {
+	return fmt.Sprintf("app;dur=%.1f", float64(d)/float64(time.Millisecond))
+}
@@ -0,0 +1,81 @@
+package transport
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerTimingMiddleware(t *testing.T) {
+	serve := func(t *testing.T, path string, mw ...echo.MiddlewareFunc) (*httptest.ResponseRecorder, string) {
+		t.Helper()
+		var logs bytes.Buffer
+		e := echo.New()
+		e.Use(ServerTimingMiddleware(zerolog.New(&logs), 20*time.Millisecond))
+		e.Use(mw...)
+		e.GET("/fast", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+		})
+		e.GET("/slow", func(c echo.Context) error {
+			time.Sleep(30 * time.Millisecond)
+			return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+		})
+		e.GET("/missing", func(c echo.Context) error {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		})
+		e.GET("/slow-missing", func(c echo.Context) error {
+			time.Sleep(30 * time.Millisecond)
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		})
+
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec, logs.String()
+	}
+
+	t.Run("reports the handler duration", func(t *testing.T) {
+		rec, logs := serve(t, "/fast")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Regexp(t, `^app;dur=\d+\.\d$`, rec.Header().Get(HeaderServerTiming))
+		assert.Empty(t, logs, "fast responses are not logged")
+	})
+
+	t.Run("warns about slow responses", func(t *testing.T) {
+		rec, logs := serve(t, "/slow")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Regexp(t, `^app;dur=\d+\.\d$`, rec.Header().Get(HeaderServerTiming))
+		assert.Contains(t, logs, `"level":"warn"`)
+		assert.Contains(t, logs, `"message":"slow response"`)
+		assert.Contains(t, logs, `"route":"/slow"`)
+	})
+
+	t.Run("logs the status of slow error responses", func(t *testing.T) {
+		rec, logs := serve(t, "/slow-missing")
+		require.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, logs, `"message":"slow response"`)
+		assert.Contains(t, logs, `"status":404`, "the status the error is answered with, not the default 200")
+	})
+
+	t.Run("is set on error responses", func(t *testing.T) {
+		rec, _ := serve(t, "/missing")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.NotEmpty(t, rec.Header().Get(HeaderServerTiming))
+	})
+
+	t.Run("is set on compressed responses", func(t *testing.T) {
+		rec, _ := serve(t, "/fast", middleware.Gzip(), MetricsMiddleware())
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "gzip", rec.Header().Get(echo.HeaderContentEncoding))
+		assert.NotEmpty(t, rec.Header().Get(HeaderServerTiming))
+	})
+}
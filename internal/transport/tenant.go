@@ -0,0 +1,29 @@
+package transport
+
+import (
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/labstack/echo/v4"
+)
+
+// tenantHeader names the tenant a request acts for; requests without it act for the default tenant
+const tenantHeader = "X-Tenant-ID"
+
+// TenantMiddleware scopes the request context to the tenant in X-Tenant-ID
+// Events and bookings of other tenants then answer 404, as if they did not exist
+func TenantMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			tenantID := c.Request().Header.Get(tenantHeader)
+			if tenantID == "" {
+				return next(c)
+			}
+			if !infrastructure.ValidTenantID(tenantID) {
+				return badRequest(c, "invalid "+tenantHeader+": expected 1-64 letters, digits, '-' or '_'")
+			}
+
+			req := c.Request()
+			c.SetRequest(req.WithContext(infrastructure.WithTenant(req.Context(), tenantID)))
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+		wantTenant string
+	}{
+		{name: "defaults without a header", wantStatus: http.StatusOK, wantTenant: infrastructure.DefaultTenant},
+		{name: "scopes to the header's tenant", header: "acme-eu_1", wantStatus: http.StatusOK, wantTenant: "acme-eu_1"},
+		{name: "rejects malformed tenants", header: "acme/../other", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(TenantMiddleware())
+			var gotTenant string
+			e.GET("/", func(c echo.Context) error {
+				gotTenant = infrastructure.TenantFromContext(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(tenantHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantTenant, gotTenant)
+		})
+	}
+}
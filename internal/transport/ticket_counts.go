@@ -0,0 +1,137 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderTicketCountFormat selects how ticket counts are written: "number" or "string"
+// JavaScript clients lose precision on integers above 2^53, so they can ask for counts as strings
+const HeaderTicketCountFormat = "X-Ticket-Count-Format"
+
+const (
+	ticketCountFormatNumber = "number"
+	ticketCountFormatString = "string"
+)
+
+// ticketCountFields are the response keys holding ticket counts, wherever they appear in the response
+var ticketCountFields = map[string]bool{
+	"tickets":           true,
+	"available_tickets": true,
+	"tickets_booked":    true,
+	"tickets_sold":      true,
+	"tickets_released":  true,
+}
+
+// ticketCountsAsStrings reports whether the client asked for ticket counts as strings, falling back to
+// the server default when it did not say or sent an unknown format
+func ticketCountsAsStrings(c echo.Context, fallback bool) bool {
+	switch strings.ToLower(c.Request().Header.Get(HeaderTicketCountFormat)) {
+	case ticketCountFormatString:
+		return true
+	case ticketCountFormatNumber:
+		return false
+	default:
+		return fallback
+	}
+}
+
+// stringifyTicketCounts quotes the numeric values of ticket count keys at any depth, keeping key order
+// and the exact digits of every number; other values, and null counts, are left untouched
+func stringifyTicketCounts(data []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return data, nil
+	}
+	switch trimmed[0] {
+	case '{':
+		return stringifyObjectTicketCounts(trimmed)
+	case '[':
+		return stringifyArrayTicketCounts(trimmed)
+	default:
+		return data, nil
+	}
+}
+
+func stringifyObjectTicketCounts(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	out.WriteByte('{')
+	for first := true; dec.More(); first = false {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected JSON object key %v", token)
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		if ticketCountFields[key] && isJSONNumber(value) {
+			value, err = json.Marshal(string(value))
+		} else {
+			value, err = stringifyTicketCounts(value)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if !first {
+			out.WriteByte(',')
+		}
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		out.Write(encodedKey)
+		out.WriteByte(':')
+		out.Write(value)
+	}
+	out.WriteByte('}')
+
+	return out.Bytes(), nil
+}
+
+func stringifyArrayTicketCounts(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	out.WriteByte('[')
+	for first := true; dec.More(); first = false {
+		var item json.RawMessage
+		if err := dec.Decode(&item); err != nil {
+			return nil, err
+		}
+		item, err := stringifyTicketCounts(item)
+		if err != nil {
+			return nil, err
+		}
+
+		if !first {
+			out.WriteByte(',')
+		}
+		out.Write(item)
+	}
+	out.WriteByte(']')
+
+	return out.Bytes(), nil
+}
+
+func isJSONNumber(value json.RawMessage) bool {
+	return len(value) > 0 && (value[0] == '-' || (value[0] >= '0' && value[0] <= '9'))
+}
@@ -0,0 +1,106 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONSerializer_TicketCounts(t *testing.T) {
+	// 2^53 + 1 cannot be represented as a float64, so JavaScript would read it as 9007199254740992
+	const bigCount = 9007199254740993
+
+	newEcho := func(stringsByDefault bool) *echo.Echo {
+		e := echo.New()
+		e.JSONSerializer = jsonSerializer{ticketCountsAsStrings: stringsByDefault}
+		e.GET("/event", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, EventResponse{ID: "e1", Name: "Stadium", Tickets: bigCount, MinViable: bigCount - 1, PriceCents: 2500})
+		})
+		e.GET("/paged", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, PagedResponse[EventSummary]{
+				Data:       []EventSummary{{ID: "e1", AvailableTickets: bigCount}, {ID: "e2"}},
+				TotalCount: 2,
+			})
+		})
+		e.GET("/check", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, AvailabilityCheckResponse{Items: []AvailabilityCheckResultResponse{{EventID: "e1", Available: true, Remaining: 3}}})
+		})
+		return e
+	}
+	get := func(e *echo.Echo, path, format string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if format != "" {
+			req.Header.Set(HeaderTicketCountFormat, format)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		return rec
+	}
+
+	numbers := newEcho(false)
+	stringDefault := newEcho(true)
+
+	t.Run("writes numbers by default", func(t *testing.T) {
+		body := get(numbers, "/event", "").Body.String()
+		assert.Contains(t, body, `"tickets":9007199254740993`)
+		assert.Contains(t, body, `"min_viable":9007199254740992`)
+	})
+
+	t.Run("writes strings on request", func(t *testing.T) {
+		rec := get(numbers, "/event", "string")
+		body := rec.Body.String()
+		assert.Contains(t, body, `"tickets":"9007199254740993"`)
+		assert.Contains(t, body, `"min_viable":9007199254740992`, "only ticket count fields become strings")
+		assert.Contains(t, body, `"price_cents":2500`)
+		assert.Contains(t, rec.Header().Values(echo.HeaderVary), HeaderTicketCountFormat)
+	})
+
+	t.Run("keeps key order and every other value", func(t *testing.T) {
+		var asNumbers, asStrings map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(get(numbers, "/event", "").Body.Bytes(), &asNumbers))
+		require.NoError(t, json.Unmarshal(get(numbers, "/event", "string").Body.Bytes(), &asStrings))
+		require.Len(t, asStrings, len(asNumbers))
+		for key, value := range asNumbers {
+			if ticketCountFields[key] {
+				assert.Equal(t, `"`+string(value)+`"`, string(asStrings[key]), key)
+				continue
+			}
+			assert.Equal(t, string(value), string(asStrings[key]), key)
+		}
+
+		numbersBody := get(numbers, "/event", "").Body.String()
+		stringsBody := get(numbers, "/event", "string").Body.String()
+		assert.Less(t, strings.Index(numbersBody, `"name"`), strings.Index(numbersBody, `"tickets"`))
+		assert.Less(t, strings.Index(stringsBody, `"name"`), strings.Index(stringsBody, `"tickets"`))
+	})
+
+	t.Run("rewrites counts nested in arrays", func(t *testing.T) {
+		body := get(numbers, "/paged", "string").Body.String()
+		assert.Contains(t, body, `"available_tickets":"9007199254740993"`)
+		assert.Contains(t, body, `"available_tickets":"0"`)
+		assert.Contains(t, body, `"total_count":2`)
+	})
+
+	t.Run("leaves non-numeric values alone", func(t *testing.T) {
+		assert.Equal(t, get(numbers, "/check", "").Body.String(), get(numbers, "/check", "string").Body.String())
+	})
+
+	t.Run("server default can be strings", func(t *testing.T) {
+		assert.Contains(t, get(stringDefault, "/event", "").Body.String(), `"tickets":"9007199254740993"`)
+		assert.Contains(t, get(stringDefault, "/event", "number").Body.String(), `"tickets":9007199254740993`)
+		assert.Contains(t, get(stringDefault, "/event", "bogus").Body.String(), `"tickets":"9007199254740993"`)
+	})
+
+	t.Run("combines with camelCase", func(t *testing.T) {
+		body := get(numbers, "/event?case=camel", "string").Body.String()
+		assert.Contains(t, body, `"tickets":"9007199254740993"`)
+		assert.Contains(t, body, `"minViable":9007199254740992`)
+	})
+}
@@ -0,0 +1,159 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+// errRequestNotSuccessful rolls back the request transaction of a handler that answered outside 2xx
+var errRequestNotSuccessful = errors.New("request did not succeed")
+
+// TransactionMiddleware runs the route's handler in one transaction, committed when it answers 2xx and
+// rolled back when it answers anything else or returns an error, so a handler making several writes
+// either applies them all or none
+// Work deferred with infrastructure.AfterRequestCommit runs after the commit
+// Repositories take part through infrastructure.RequestScopedDBClient, which db should be as well, and
+// transactions the services begin become savepoints of this one
+// The response is held back until the commit succeeds, so a failed begin or commit is answered like any
+// other error rather than with the handler's success; routes that stream their response must not use it
+func TransactionMiddleware(db infrastructure.DBClient, logger zerolog.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			res := c.Response()
+			writer := res.Writer
+			buffer := newBufferedResponse()
+			res.Writer = buffer
+			defer func() {
+				c.SetRequest(req)
+				res.Writer = writer
+			}()
+
+			var handlerErr error
+			var txCtx context.Context
+			err := app.WithTransaction(req.Context(), db, logger, nil, "request "+c.Path(), func(tx domain.Transaction) error {
+				txCtx = infrastructure.WithRequestTransaction(req.Context(), tx)
+				c.SetRequest(req.WithContext(txCtx))
+				if handlerErr = next(c); handlerErr != nil {
+					return handlerErr
+				}
+				if res.Status < http.StatusOK || res.Status >= http.StatusMultipleChoices {
+					return errRequestNotSuccessful
+				}
+				return nil
+			})
+			if err != nil && handlerErr == nil && !errors.Is(err, errRequestNotSuccessful) {
+				// Begin or commit failed: whatever the handler answered did not happen
+				res.Writer = writer
+				res.Committed, res.Status, res.Size = false, 0, 0
+				return handleError(c, err)
+			}
+			if err == nil {
+				infrastructure.RequestCommitted(txCtx)
+			}
+			buffer.flush(writer)
+			return handlerErr
+		}
+	}
+}
+
+// ParseTransactionalRoutes splits a comma-separated list of routes given as "METHOD /path", where the path is
+// the route's pattern, e.g. "POST /admin/events/import"
+func ParseTransactionalRoutes(raw string) ([]string, error) {
+	var routes []string
+	for _, entry := range strings.Split(raw, ",") {
+		method, path, ok := strings.Cut(strings.TrimSpace(entry), " ")
+		if method == "" && !ok {
+			continue
+		}
+		path = strings.TrimSpace(path)
+		if !ok || !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("invalid transactional route %q, expected METHOD /path", entry)
+		}
+		routes = append(routes, strings.ToUpper(method)+" "+path)
+	}
+	return routes, nil
+}
+
+// splitAdminRoutes separates the routes under /admin from the rest, so their transaction is begun by the
+// admin group, after the admin token was checked, rather than for every caller
+func splitAdminRoutes(routes []string) (public, admin []string) {
+	for _, route := range routes {
+		_, path, _ := strings.Cut(route, " ")
+		if path == "/admin" || strings.HasPrefix(path, "/admin/") {
+			admin = append(admin, route)
+		} else {
+			public = append(public, route)
+		}
+	}
+	return public, admin
+}
+
+// transactionalRoutes runs the listed routes through TransactionMiddleware and every other route unchanged
+func transactionalRoutes(routes []string, db infrastructure.DBClient, logger zerolog.Logger) echo.MiddlewareFunc {
+	listed := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		listed[route] = true
+	}
+	inTransaction := TransactionMiddleware(db, logger)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		transactional := inTransaction(next)
+		return func(c echo.Context) error {
+			if listed[c.Request().Method+" "+c.Path()] {
+				return transactional(c)
+			}
+			return next(c)
+		}
+	}
+}
+
+// bufferedResponse holds a response back from the client until it is flushed
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBufferedResponse() *bufferedResponse {
+	return &bufferedResponse{header: make(http.Header)}
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+// flush writes the held response to w; nothing is written when the handler wrote nothing
+func (b *bufferedResponse) flush(w http.ResponseWriter) {
+	if b.status == 0 {
+		return
+	}
+	for key, values := range b.header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(b.status)
+	w.Write(b.body.Bytes())
+}
@@ -0,0 +1,236 @@
+package transport
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTx records the statements run through it and how it ended
+type recordingTx struct {
+	domain.Executor
+	statements []string
+	committed  bool
+	rolledBack bool
+	commitErr  error
+}
+
+func (tx *recordingTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	tx.statements = append(tx.statements, query)
+	return nil, nil
+}
+
+func (tx *recordingTx) Commit() error {
+	if tx.commitErr != nil {
+		return tx.commitErr
+	}
+	tx.committed = true
+	return nil
+}
+
+func (tx *recordingTx) Rollback() error {
+	if !tx.committed {
+		tx.rolledBack = true
+	}
+	return nil
+}
+
+type fakeTxDB struct {
+	infrastructure.DBClient
+	tx *recordingTx
+}
+
+func (db *fakeTxDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (domain.Transaction, error) {
+	return db.tx, nil
+}
+
+func TestTransactionMiddleware(t *testing.T) {
+	serve := func(t *testing.T, tx *recordingTx, handler echo.HandlerFunc) *httptest.ResponseRecorder {
+		t.Helper()
+		db := infrastructure.NewRequestScopedDBClient(&fakeTxDB{tx: tx})
+		e := echo.New()
+		e.POST("/orders", func(c echo.Context) error {
+			// Writes through the scoped client land in the request transaction
+			if _, err := db.ExecContext(c.Request().Context(), "INSERT INTO orders DEFAULT VALUES"); err != nil {
+				return err
+			}
+			return handler(c)
+		}, TransactionMiddleware(db, zerolog.Nop()))
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders", nil))
+		return rec
+	}
+
+	t.Run("commits a successful handler's writes", func(t *testing.T) {
+		tx := &recordingTx{}
+		rec := serve(t, tx, func(c echo.Context) error {
+			c.Response().Header().Set(headerETag, `"1"`)
+			return c.JSON(http.StatusCreated, map[string]string{"status": "created"})
+		})
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.JSONEq(t, `{"status":"created"}`, rec.Body.String())
+		assert.Equal(t, `"1"`, rec.Header().Get(headerETag))
+		assert.Equal(t, []string{"INSERT INTO orders DEFAULT VALUES"}, tx.statements)
+		assert.True(t, tx.committed)
+	})
+
+	t.Run("rolls back when the handler returns an error", func(t *testing.T) {
+		tx := &recordingTx{}
+		rec := serve(t, tx, func(c echo.Context) error {
+			return echo.NewHTTPError(http.StatusServiceUnavailable, "unavailable")
+		})
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Len(t, tx.statements, 1)
+		assert.True(t, tx.rolledBack)
+		assert.False(t, tx.committed)
+	})
+
+	t.Run("rolls back when the handler answers an error", func(t *testing.T) {
+		tx := &recordingTx{}
+		rec := serve(t, tx, func(c echo.Context) error {
+			return handleError(c, domain.ErrInsufficientTickets)
+		})
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Contains(t, rec.Body.String(), "insufficient tickets available")
+		assert.True(t, tx.rolledBack)
+	})
+
+	t.Run("answers 500 instead of the handler's success when the commit fails", func(t *testing.T) {
+		tx := &recordingTx{commitErr: errors.New("connection reset")}
+		rec := serve(t, tx, func(c echo.Context) error {
+			c.Response().Header().Set(headerETag, `"1"`)
+			return c.JSON(http.StatusCreated, map[string]string{"status": "created"})
+		})
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "created")
+		assert.Contains(t, rec.Body.String(), "internal server error", "answered like handleError, not echo's default")
+		assert.Empty(t, rec.Header().Get(headerETag))
+	})
+
+	t.Run("answers a commit lost to a concurrent update like the handlers do", func(t *testing.T) {
+		tx := &recordingTx{commitErr: infrastructure.ErrSerializationFailure}
+		rec := serve(t, tx, func(c echo.Context) error {
+			return c.JSON(http.StatusCreated, map[string]string{"status": "created"})
+		})
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Contains(t, rec.Body.String(), "concurrent update conflict")
+	})
+
+	t.Run("runs deferred work only after the commit", func(t *testing.T) {
+		for _, tc := range []struct {
+			name   string
+			status int
+			want   []string
+		}{
+			{name: "committed", status: http.StatusCreated, want: []string{"handler", "after commit"}},
+			{name: "rolled back", status: http.StatusConflict, want: []string{"handler"}},
+		} {
+			tx := &recordingTx{}
+			var ran []string
+			serve(t, tx, func(c echo.Context) error {
+				ctx := c.Request().Context()
+				infrastructure.AfterRequestCommit(ctx, func() {
+					assert.True(t, tx.committed, "deferred work runs once the transaction committed")
+					ran = append(ran, "after commit")
+				})
+				ran = append(ran, "handler")
+
+				_, attached := infrastructure.RequestTransactionFromContext(ctx)
+				_, detached := infrastructure.RequestTransactionFromContext(infrastructure.WithoutRequestTransaction(ctx))
+				assert.True(t, attached)
+				assert.False(t, detached, "detached contexts no longer query through the request transaction")
+				return c.NoContent(tc.status)
+			})
+			assert.Equal(t, tc.want, ran, tc.name)
+		}
+	})
+
+	t.Run("nests transactions begun by the handler as savepoints", func(t *testing.T) {
+		tx := &recordingTx{}
+		db := infrastructure.NewRequestScopedDBClient(&fakeTxDB{tx: tx})
+		rec := serve(t, tx, func(c echo.Context) error {
+			nested, err := db.BeginTx(c.Request().Context(), nil)
+			require.NoError(t, err)
+			require.NoError(t, nested.Rollback())
+			assert.ErrorIs(t, nested.Commit(), sql.ErrTxDone)
+			return c.NoContent(http.StatusNoContent)
+		})
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		require.Len(t, tx.statements, 3)
+		assert.Regexp(t, `^SAVEPOINT nested_\d+$`, tx.statements[1])
+		assert.Regexp(t, `^ROLLBACK TO SAVEPOINT nested_\d+$`, tx.statements[2])
+		assert.True(t, tx.committed, "rolling back a savepoint leaves the request transaction to commit")
+	})
+}
+
+func TestTransactionalRoutes(t *testing.T) {
+	routes, err := ParseTransactionalRoutes(" post /orders , ,")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"POST /orders"}, routes)
+
+	_, err = ParseTransactionalRoutes("POST")
+	assert.Error(t, err)
+	_, err = ParseTransactionalRoutes("POST orders")
+	assert.Error(t, err)
+
+	tx := &recordingTx{}
+	e := echo.New()
+	e.Use(transactionalRoutes(routes, &fakeTxDB{tx: tx}, zerolog.Nop()))
+	e.POST("/orders", func(c echo.Context) error {
+		_, ok := infrastructure.RequestTransactionFromContext(c.Request().Context())
+		return c.JSON(http.StatusOK, ok)
+	})
+	e.GET("/orders", func(c echo.Context) error {
+		_, ok := infrastructure.RequestTransactionFromContext(c.Request().Context())
+		return c.JSON(http.StatusOK, ok)
+	})
+
+	for method, want := range map[string]string{http.MethodPost: "true", http.MethodGet: "false"} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(method, "/orders", nil))
+		assert.JSONEq(t, want, rec.Body.String(), method)
+	}
+	assert.True(t, tx.committed)
+}
+
+func TestRouter_AdminTransactionBeginsAfterAuth(t *testing.T) {
+	tx := &recordingTx{}
+	db := infrastructure.NewRequestScopedDBClient(&fakeTxDB{tx: tx})
+	router := NewRouter(nil, nil, nil, db, infrastructure.NewWorkerRegistry(), zerolog.Nop(),
+		WithAdminToken("secret"), WithTransactionalRoutes([]string{"POST /admin/maintenance"}))
+
+	serve := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/admin/maintenance", strings.NewReader(`{"enabled": false}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if token != "" {
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, serve("").Code)
+	assert.False(t, tx.committed || tx.rolledBack, "no transaction is begun for unauthenticated requests")
+
+	assert.Equal(t, http.StatusOK, serve("secret").Code)
+	assert.True(t, tx.committed)
+}
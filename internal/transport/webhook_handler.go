@@ -0,0 +1,144 @@
+package transport
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+type WebhookHandler struct {
+	service *app.WebhookService
+	logger  zerolog.Logger
+}
+
+func NewWebhookHandler(service *app.WebhookService, logger zerolog.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+		logger:  logger.With().Str("handler", "webhook").Logger(),
+	}
+}
+
+type RegisterWebhookRequest struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
+}
+
+// WebhookResponse leaves out the secret: the organizer chose it, and responses may end up in logs
+type WebhookResponse struct {
+	ID        string    `json:"id"`
+	EventID   string    `json:"event_id"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RegisterWebhook registers a URL to be posted every booking of the event, signed with the given secret
+func (h *WebhookHandler) RegisterWebhook(c echo.Context) error {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid event id")
+	}
+
+	var req RegisterWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error().Err(err).Msg("failed to bind request")
+		return badRequest(c, "invalid request body")
+	}
+
+	hook, err := h.service.RegisterWebhook(c.Request().Context(), app.RegisterWebhookRequest{
+		EventID: eventID,
+		URL:     req.URL,
+		Secret:  req.Secret,
+	})
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, toWebhookResponse(hook))
+}
+
+func (h *WebhookHandler) DeleteWebhook(c echo.Context) error {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid event id")
+	}
+	hookID, err := uuid.Parse(c.Param("hookId"))
+	if err != nil {
+		return badRequest(c, "invalid webhook id")
+	}
+
+	if err := h.service.DeleteWebhook(c.Request().Context(), eventID, hookID); err != nil {
+		return handleError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DeadLetterResponse is a stored failed delivery; Retryable is false for those kept without their payload
+type DeadLetterResponse struct {
+	ID        string    `json:"id"`
+	WebhookID string    `json:"webhook_id"`
+	EventID   string    `json:"event_id"`
+	BookingID string    `json:"booking_id"`
+	Type      string    `json:"type"`
+	Error     string    `json:"error"`
+	Attempts  int       `json:"attempts"`
+	FailedAt  time.Time `json:"failed_at"`
+	Retryable bool      `json:"retryable"`
+}
+
+// ListDeadLetters pages through the webhook deliveries that failed, most recent first
+func (h *WebhookHandler) ListDeadLetters(c echo.Context) error {
+	page, err := parsePagination(c)
+	if err != nil {
+		return badRequest(c, err.Error())
+	}
+
+	failures, total, err := h.service.ListDeliveryFailures(c.Request().Context(), page.Limit, page.Offset)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newPagedResponse(failures, total, page, toDeadLetterResponse))
+}
+
+// RetryDeadLetter removes the dead letter and queues its delivery again; it is accepted, not yet delivered
+func (h *WebhookHandler) RetryDeadLetter(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "invalid dead letter id")
+	}
+
+	if err := h.service.RetryDeliveryFailure(c.Request().Context(), id); err != nil {
+		return handleError(c, err)
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+func toWebhookResponse(hook *domain.EventWebhook) WebhookResponse {
+	return WebhookResponse{
+		ID:        hook.ID.String(),
+		EventID:   hook.EventID.String(),
+		URL:       hook.URL,
+		CreatedAt: hook.CreatedAt,
+	}
+}
+
+func toDeadLetterResponse(failure *domain.WebhookDeliveryFailure) DeadLetterResponse {
+	return DeadLetterResponse{
+		ID:        failure.ID.String(),
+		WebhookID: failure.WebhookID.String(),
+		EventID:   failure.EventID.String(),
+		BookingID: failure.BookingID.String(),
+		Type:      failure.Type,
+		Error:     failure.Error,
+		Attempts:  failure.Attempts,
+		FailedAt:  failure.FailedAt,
+		Retryable: failure.Booking != nil,
+	}
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminCreateBooking_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger,
+		transport.WithAdminTokens(map[string]string{"jane@support": "test-admin-token"}))
+
+	ctx := context.Background()
+	event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name:     "Phone Order Gala",
+		Date:     time.Now().Add(7 * 24 * time.Hour),
+		Location: "Hall",
+		Tickets:  10,
+	})
+	require.NoError(t, err)
+	customerID := uuid.New()
+
+	post := func(path, body string, admin bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if admin {
+			req.Header.Set("Authorization", "Bearer test-admin-token")
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	bookingBody := func(createdBy string) string {
+		return `{"event_id":"` + event.ID.String() + `","user_id":"` + customerID.String() + `","tickets_booked":2,"created_by":"` + createdBy + `"}`
+	}
+
+	t.Run("requires admin token", func(t *testing.T) {
+		rec := post("/admin/bookings", bookingBody("jane@support"), false)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("books for the user and records the admin the token belongs to", func(t *testing.T) {
+		rec := post("/admin/bookings", bookingBody("mallory@support"), true)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		var booking transport.BookingResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &booking))
+		assert.Equal(t, customerID.String(), booking.UserID)
+		assert.Equal(t, "jane@support", booking.CreatedBy, "created_by in the body is ignored")
+
+		var createdBy string
+		require.NoError(t, db.QueryRowContext(ctx, "SELECT created_by FROM bookings WHERE id = $1", booking.ID).Scan(&createdBy))
+		assert.Equal(t, "jane@support", createdBy)
+
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 8, availability.AvailableTickets)
+	})
+
+	t.Run("self-service bookings cannot claim an admin", func(t *testing.T) {
+		rec := post("/bookings", bookingBody("jane@support"), false)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		var booking transport.BookingResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &booking))
+		assert.Empty(t, booking.CreatedBy)
+	})
+}
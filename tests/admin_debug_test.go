@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminRuntimeDebug_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger, transport.WithAdminToken("test-admin-token"))
+
+	// Hold a connection checked out so the pool reports it as in use
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	t.Run("requires admin token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/debug/runtime", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("reports runtime and pool stats", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/debug/runtime", nil)
+		req.Header.Set("Authorization", "Bearer test-admin-token")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var body map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Contains(t, body, "goroutines")
+		assert.Contains(t, body, "memory")
+		assert.Contains(t, body, "db_pool")
+
+		var resp transport.RuntimeStatsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Positive(t, resp.Goroutines)
+		assert.GreaterOrEqual(t, resp.DBPool.InUse, 1)
+		assert.GreaterOrEqual(t, resp.DBPool.OpenConnections, resp.DBPool.InUse)
+	})
+}
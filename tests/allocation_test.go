@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocations_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	allocationRepo := infrastructure.NewPostgresAllocationRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger,
+		app.WithAllocations(allocationRepo))
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger,
+		transport.WithAllocations(app.NewAllocationService(allocationRepo, eventRepo, ticketAvailabilityRepo, dbClient, logger)))
+
+	ctx := context.Background()
+	event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name:     "Premiere",
+		Date:     time.Now().Add(30 * 24 * time.Hour),
+		Location: "Cinema",
+		Tickets:  20,
+	})
+	require.NoError(t, err)
+
+	post := func(t *testing.T, path string, body map[string]any) *httptest.ResponseRecorder {
+		t.Helper()
+		raw, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(raw))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	allocate := func(t *testing.T, name string, tickets int) *httptest.ResponseRecorder {
+		t.Helper()
+		return post(t, "/admin/events/"+event.ID.String()+"/allocations", map[string]any{"name": name, "tickets": tickets})
+	}
+	book := func(t *testing.T, userID uuid.UUID, tickets int, allocation string) *httptest.ResponseRecorder {
+		t.Helper()
+		return post(t, "/bookings", map[string]any{
+			"event_id":       event.ID.String(),
+			"user_id":        userID.String(),
+			"tickets_booked": tickets,
+			"allocation":     allocation,
+		})
+	}
+	publicAvailable := func(t *testing.T) int {
+		t.Helper()
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, event.ID)
+		require.NoError(t, err)
+		return availability.AvailableTickets
+	}
+	allocations := func(t *testing.T) map[string]transport.AllocationResponse {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/events/"+event.ID.String()+"/allocations", nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var list []transport.AllocationResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+		byName := make(map[string]transport.AllocationResponse, len(list))
+		for _, allocation := range list {
+			byName[allocation.Name] = allocation
+		}
+		return byName
+	}
+
+	t.Run("carves blocks out of public sale", func(t *testing.T) {
+		rec := allocate(t, "Press", 5)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		var press transport.AllocationResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &press))
+		assert.Equal(t, "press", press.Name)
+		assert.Equal(t, 5, press.AvailableTickets)
+
+		require.Equal(t, http.StatusCreated, allocate(t, "sponsors", 3).Code)
+		assert.Equal(t, 12, publicAvailable(t), "20 tickets minus the 8 allocated")
+	})
+
+	t.Run("rejects invalid blocks", func(t *testing.T) {
+		assert.Equal(t, http.StatusConflict, allocate(t, "press", 1).Code, "names are unique per event")
+		assert.Equal(t, http.StatusConflict, allocate(t, "crew", 13).Code, "more than public sale has left")
+		assert.Equal(t, http.StatusBadRequest, allocate(t, "public", 1).Code)
+		assert.Equal(t, http.StatusBadRequest, allocate(t, "crew", 0).Code)
+		assert.Equal(t, 12, publicAvailable(t))
+	})
+
+	journalist := uuid.New()
+	t.Run("books from a specific allocation", func(t *testing.T) {
+		rec := book(t, journalist, 3, "press")
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		var booking transport.BookingResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &booking))
+		assert.NotEmpty(t, booking.AllocationID)
+
+		blocks := allocations(t)
+		assert.Equal(t, 2, blocks["press"].AvailableTickets)
+		assert.Equal(t, 3, blocks["sponsors"].AvailableTickets, "other blocks are untouched")
+		assert.Equal(t, 12, publicAvailable(t), "public sale is untouched")
+	})
+
+	t.Run("exhausting an allocation does not affect others", func(t *testing.T) {
+		require.Equal(t, http.StatusCreated, book(t, uuid.New(), 2, "press").Code)
+
+		rec := book(t, uuid.New(), 1, "press")
+		assert.Equal(t, http.StatusConflict, rec.Code, rec.Body.String())
+
+		require.Equal(t, http.StatusCreated, book(t, uuid.New(), 3, "sponsors").Code)
+		require.Equal(t, http.StatusCreated, book(t, uuid.New(), 12, "").Code, "public bookings draw from the default block")
+		assert.Equal(t, http.StatusConflict, book(t, uuid.New(), 1, "public").Code, "public sale is sold out")
+
+		blocks := allocations(t)
+		assert.Equal(t, 0, blocks["press"].AvailableTickets)
+		assert.Equal(t, 0, blocks["sponsors"].AvailableTickets)
+	})
+
+	t.Run("cancelled bookings return tickets to their allocation", func(t *testing.T) {
+		_, err := bookingService.CancelAllForUser(ctx, journalist)
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, allocations(t)["press"].AvailableTickets)
+		assert.Equal(t, 0, publicAvailable(t))
+	})
+
+	t.Run("rejects unknown allocations", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, book(t, uuid.New(), 1, "crew").Code)
+		assert.Equal(t, http.StatusBadRequest, book(t, uuid.New(), 1, "no such block").Code)
+	})
+
+	t.Run("books from an allocation through the service", func(t *testing.T) {
+		booking, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{
+			EventID:       event.ID,
+			UserID:        uuid.New(),
+			TicketsBooked: 1,
+			Allocation:    "PRESS",
+		})
+		require.NoError(t, err)
+
+		stored, err := bookingRepo.FindByID(ctx, booking.ID)
+		require.NoError(t, err)
+		assert.Equal(t, booking.AllocationID, stored.AllocationID)
+		assert.NotEqual(t, uuid.Nil, stored.AllocationID)
+
+		_, err = bookingService.CreateBooking(ctx, app.CreateBookingRequest{
+			EventID:       event.ID,
+			UserID:        uuid.New(),
+			TicketsBooked: 3,
+			Allocation:    "press",
+		})
+		assert.ErrorIs(t, err, domain.ErrInsufficientTickets)
+	})
+}
@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvailabilityCheck_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	createEvent := func(name string, tickets int) uuid.UUID {
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:     name,
+			Date:     time.Now().Add(7 * 24 * time.Hour),
+			Location: "Hall",
+			Tickets:  tickets,
+		})
+		require.NoError(t, err)
+		return event.ID
+	}
+	large := createEvent("Large Hall", 100)
+	small := createEvent("Small Club", 5)
+	_, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: small, UserID: uuid.New(), TicketsBooked: 3})
+	require.NoError(t, err)
+	unknown := uuid.New()
+
+	body := fmt.Sprintf(`[
+		{"event_id": %q, "tickets": 10},
+		{"event_id": %q, "tickets": 3},
+		{"event_id": %q, "tickets": 2},
+		{"event_id": %q, "tickets": 1}
+	]`, large, small, small, unknown)
+	req := httptest.NewRequest(http.MethodPost, "/availability/check", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.JSONEq(t, fmt.Sprintf(`{"items": [
+		{"event_id": %q, "available": true, "remaining": 100},
+		{"event_id": %q, "available": false, "remaining": 2},
+		{"event_id": %q, "available": true, "remaining": 2},
+		{"event_id": %q, "available": false, "remaining": 0}
+	]}`, large, small, small, unknown), rec.Body.String())
+}
@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvailabilityIfMatch_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name:     "Corrected Night",
+		Date:     time.Now().Add(7 * 24 * time.Hour),
+		Location: "Hall",
+		Tickets:  50,
+	})
+	require.NoError(t, err)
+	path := "/admin/events/" + event.ID.String() + "/availability"
+
+	read := func() string {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		etag := rec.Header().Get("ETag")
+		require.NotEmpty(t, etag)
+		return etag
+	}
+	adjust := func(delta, ifMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, path, strings.NewReader(`{"delta": `+delta+`}`))
+		req.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("applies the adjustment when If-Match is current", func(t *testing.T) {
+		etag := read()
+
+		rec := adjust("-5", etag)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.JSONEq(t, `{"event_id": "`+event.ID.String()+`", "available_tickets": 45}`, rec.Body.String())
+		assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+		assert.Equal(t, rec.Header().Get("ETag"), read())
+	})
+
+	t.Run("refuses a second correction based on the same read", func(t *testing.T) {
+		etag := read()
+		require.Equal(t, http.StatusOK, adjust("-1", etag).Code)
+
+		rec := adjust("-1", etag)
+		assert.Equal(t, http.StatusPreconditionFailed, rec.Code, rec.Body.String())
+
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 44, availability.AvailableTickets, "the stale correction is not applied")
+	})
+
+	t.Run("refuses a correction after a booking changed availability", func(t *testing.T) {
+		etag := read()
+		_, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 2})
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusPreconditionFailed, adjust("3", etag).Code)
+	})
+
+	t.Run("refuses weak or malformed tags", func(t *testing.T) {
+		etag := read()
+		assert.Equal(t, http.StatusPreconditionFailed, adjust("1", "W/"+etag).Code)
+		assert.Equal(t, http.StatusPreconditionFailed, adjust("1", "not-a-tag").Code)
+	})
+
+	t.Run("adjusts unconditionally without If-Match", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, adjust("1", "").Code)
+		assert.Equal(t, http.StatusOK, adjust("1", "*").Code)
+	})
+}
@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvailabilityHistory_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	// checkpoint separates consecutive changes so each one falls strictly between two checkpoints
+	checkpoint := func() time.Time {
+		time.Sleep(10 * time.Millisecond)
+		now := time.Now()
+		time.Sleep(10 * time.Millisecond)
+		return now
+	}
+
+	beforeCreate := checkpoint()
+	event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name:     "Disputed Night",
+		Date:     time.Now().Add(7 * 24 * time.Hour),
+		Location: "Hall",
+		Tickets:  10,
+	})
+	require.NoError(t, err)
+	afterCreate := checkpoint()
+
+	_, err = bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 3})
+	require.NoError(t, err)
+	afterBooking := checkpoint()
+
+	_, err = holdService.CreateHold(ctx, app.CreateHoldRequest{EventID: event.ID, UserID: uuid.New(), Tickets: 2})
+	require.NoError(t, err)
+	afterHold := checkpoint()
+
+	_, err = eventService.AdjustAvailability(ctx, event.ID, -1, 0)
+	require.NoError(t, err)
+	afterAdjustment := checkpoint()
+
+	_, err = bookingService.CreateBookings(ctx, app.CreateBookingsRequest{UserID: uuid.New(), Items: []app.CartItem{{EventID: event.ID, TicketsBooked: 1}}})
+	require.NoError(t, err)
+	afterCart := checkpoint()
+
+	t.Run("reconstructs availability after each change", func(t *testing.T) {
+		tests := []struct {
+			at         time.Time
+			want       int
+			wantDelta  int
+			wantReason domain.AvailabilityChangeReason
+		}{
+			{at: afterCreate, want: 10, wantDelta: 10, wantReason: domain.AvailabilityCreated},
+			{at: afterBooking, want: 7, wantDelta: -3, wantReason: domain.AvailabilityBooked},
+			{at: afterHold, want: 5, wantDelta: -2, wantReason: domain.AvailabilityHeld},
+			{at: afterAdjustment, want: 4, wantDelta: -1, wantReason: domain.AvailabilityAdjusted},
+			{at: afterCart, want: 3, wantDelta: -1, wantReason: domain.AvailabilityBooked},
+		}
+
+		for _, tt := range tests {
+			change, err := eventService.GetAvailabilityAt(ctx, event.ID, tt.at)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, change.Resulting)
+			assert.Equal(t, tt.wantDelta, change.Delta)
+			assert.Equal(t, tt.wantReason, change.Reason)
+		}
+
+		current, err := ticketAvailabilityRepo.FindByEventID(ctx, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 3, current.AvailableTickets, "the log ends at the current availability")
+	})
+
+	t.Run("serves availability at a timestamp over HTTP", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		path := "/events/" + event.ID.String() + "/availability?at=" + afterHold.UTC().Format(time.RFC3339Nano)
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var got transport.AvailabilityAtResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		assert.Equal(t, 5, got.AvailableTickets)
+		assert.Equal(t, "hold", got.LastChange.Reason)
+	})
+
+	t.Run("has no availability before the event existed", func(t *testing.T) {
+		_, err := eventService.GetAvailabilityAt(ctx, event.ID, beforeCreate)
+		assert.ErrorIs(t, err, domain.ErrAvailabilityHistoryNotFound)
+	})
+
+	t.Run("rejects malformed timestamps", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events/"+event.ID.String()+"/availability?at=yesterday", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
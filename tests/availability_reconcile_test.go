@@ -0,0 +1,150 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcileAllAvailability_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	allocationRepo := infrastructure.NewPostgresAllocationRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger,
+		app.WithAllocations(allocationRepo))
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	allocationService := app.NewAllocationService(allocationRepo, eventRepo, ticketAvailabilityRepo, dbClient, logger)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	user := uuid.New()
+
+	// Seven events, each with 20 tickets, 3 booked and 1 more booked then cancelled; every other one
+	// also has a hold of 2 and an allocation of 5, one ticket of which is booked
+	expected := make(map[uuid.UUID]int)
+	var events []*domain.Event
+	for i := 0; i < 7; i++ {
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:     fmt.Sprintf("Reconciled %d", i),
+			Date:     time.Now().Add(time.Duration(i+1) * 24 * time.Hour),
+			Location: "Hall",
+			Tickets:  20,
+		})
+		require.NoError(t, err)
+		events = append(events, event)
+
+		_, err = bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: user, TicketsBooked: 3})
+		require.NoError(t, err)
+		expected[event.ID] = 17
+
+		if i%2 == 0 {
+			_, err = holdService.CreateHold(ctx, app.CreateHoldRequest{EventID: event.ID, UserID: uuid.New(), Tickets: 2})
+			require.NoError(t, err)
+			_, err = allocationService.CreateAllocation(ctx, app.CreateAllocationRequest{EventID: event.ID, Name: "press", Tickets: 5})
+			require.NoError(t, err)
+			_, err = bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 1, Allocation: "press"})
+			require.NoError(t, err)
+			expected[event.ID] = 10
+		}
+	}
+	canceller := uuid.New()
+	for _, event := range events {
+		_, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: canceller, TicketsBooked: 1})
+		require.NoError(t, err)
+	}
+	_, err := bookingService.CancelAllForUser(ctx, canceller)
+	require.NoError(t, err)
+
+	available := func(t *testing.T, eventID uuid.UUID) int {
+		t.Helper()
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, eventID)
+		require.NoError(t, err)
+		return availability.AvailableTickets
+	}
+	for _, event := range events {
+		require.Equal(t, expected[event.ID], available(t, event.ID), "bookings keep availability consistent")
+	}
+
+	corrupt := func(t *testing.T, eventID uuid.UUID, value int) {
+		t.Helper()
+		_, err := db.ExecContext(ctx, "UPDATE ticket_availability SET available_tickets = $2 WHERE event_id = $1", eventID, value)
+		require.NoError(t, err)
+	}
+
+	t.Run("corrects drifted events in batches", func(t *testing.T) {
+		corrupt(t, events[0].ID, 20)
+		corrupt(t, events[3].ID, 0)
+		corrupt(t, events[6].ID, 13)
+
+		summary, err := eventService.ReconcileAllAvailability(ctx, 3)
+		require.NoError(t, err)
+		assert.Equal(t, 7, summary.Checked)
+		assert.Equal(t, 3, summary.Corrected)
+		assert.Equal(t, 3, summary.Batches, "seven events in batches of three")
+
+		for _, event := range events {
+			assert.Equal(t, expected[event.ID], available(t, event.ID), event.Name)
+		}
+
+		var reconciled int
+		require.NoError(t, db.QueryRowContext(ctx, "SELECT COUNT(*) FROM availability_changes WHERE reason = $1", domain.AvailabilityReconciled).Scan(&reconciled))
+		assert.Equal(t, 3, reconciled, "only corrected events are logged")
+	})
+
+	t.Run("repeating it corrects nothing", func(t *testing.T) {
+		summary, err := eventService.ReconcileAllAvailability(ctx, 100)
+		require.NoError(t, err)
+		assert.Equal(t, 7, summary.Checked)
+		assert.Zero(t, summary.Corrected)
+		assert.Equal(t, 1, summary.Batches)
+	})
+
+	t.Run("reconciles over HTTP", func(t *testing.T) {
+		corrupt(t, events[1].ID, 2)
+		corrupt(t, events[2].ID, 19)
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/reconcile-all", nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var response transport.ReconciliationResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, transport.ReconciliationResponse{Checked: 7, Corrected: 2, Batches: 1}, response)
+		assert.Equal(t, expected[events[1].ID], available(t, events[1].ID))
+		assert.Equal(t, expected[events[2].ID], available(t, events[2].ID))
+	})
+
+	t.Run("an overbooked event is left with none available", func(t *testing.T) {
+		_, err := db.ExecContext(ctx, "UPDATE events SET tickets = 2 WHERE id = $1", events[5].ID)
+		require.NoError(t, err)
+
+		summary, err := eventService.ReconcileAllAvailability(ctx, 100)
+		require.NoError(t, err)
+		assert.Equal(t, 1, summary.Corrected)
+		assert.Zero(t, available(t, events[5].ID))
+	})
+}
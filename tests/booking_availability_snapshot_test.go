@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookingAvailabilitySnapshot_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name:     "Snapshot Show",
+		Date:     time.Now().Add(30 * 24 * time.Hour),
+		Location: "Arena",
+		Tickets:  10,
+	})
+	require.NoError(t, err)
+
+	book := func(t *testing.T, tickets int) transport.BookingResponse {
+		t.Helper()
+		body := fmt.Sprintf(`{"event_id":%q,"user_id":%q,"tickets_booked":%d}`, event.ID, uuid.New(), tickets)
+		req := httptest.NewRequest(http.MethodPost, "/bookings", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		var booking transport.BookingResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &booking))
+		return booking
+	}
+
+	var bookingIDs []string
+	t.Run("returns the tickets left after each of consecutive bookings", func(t *testing.T) {
+		for _, want := range []struct{ tickets, left int }{{3, 7}, {2, 5}, {5, 0}} {
+			booking := book(t, want.tickets)
+			bookingIDs = append(bookingIDs, booking.ID)
+			require.NotNil(t, booking.EventAvailableTickets)
+			assert.Equal(t, want.left, *booking.EventAvailableTickets)
+
+			availability, err := ticketAvailabilityRepo.FindByEventID(ctx, event.ID)
+			require.NoError(t, err)
+			assert.Equal(t, availability.AvailableTickets, *booking.EventAvailableTickets, "matches the stored availability")
+		}
+	})
+
+	t.Run("omits the count when the booking is read back", func(t *testing.T) {
+		require.NotEmpty(t, bookingIDs)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/bookings/"+bookingIDs[0], nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "event_available_tickets", "a stale count would mislead clients")
+	})
+}
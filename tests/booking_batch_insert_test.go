@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createBatchEvent inserts an event for bookings to reference
+func createBatchEvent(t testing.TB, dbClient infrastructure.DBClient) uuid.UUID {
+	t.Helper()
+	event, err := domain.NewEvent("Batch Festival", "Fields", time.Now().Add(30*24*time.Hour), domain.MaxTickets)
+	require.NoError(t, err)
+	require.NoError(t, infrastructure.NewPostgresEventRepository(dbClient).Create(context.Background(), event))
+	return event.ID
+}
+
+func newBatchBookings(t testing.TB, eventID uuid.UUID, n int) []*domain.Booking {
+	t.Helper()
+	bookings := make([]*domain.Booking, n)
+	for i := range bookings {
+		booking, err := domain.NewBooking(eventID, uuid.New(), 1)
+		require.NoError(t, err)
+		bookings[i] = booking
+	}
+	return bookings
+}
+
+func TestBookingBatchInsert_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient, infrastructure.WithBatchSize(1000))
+	eventID := createBatchEvent(t, dbClient)
+	ctx := context.Background()
+
+	count := func(t *testing.T, query string) int {
+		var n int
+		require.NoError(t, db.QueryRowContext(ctx, query, eventID).Scan(&n))
+		return n
+	}
+
+	t.Run("inserts more rows than one batch holds", func(t *testing.T) {
+		bookings := newBatchBookings(t, eventID, 2500)
+
+		tx, err := dbClient.BeginTx(ctx, nil)
+		require.NoError(t, err)
+		require.NoError(t, bookingRepo.CreateBatchWithExecutor(ctx, tx, bookings))
+		require.NoError(t, tx.Commit())
+
+		assert.Equal(t, 2500, count(t, `SELECT COUNT(*) FROM bookings WHERE event_id = $1`))
+		assert.Equal(t, 2500, count(t, `SELECT COUNT(*) FROM booking_changes bc JOIN bookings b ON b.id = bc.booking_id WHERE b.event_id = $1`))
+
+		last, err := bookingRepo.FindByID(ctx, bookings[len(bookings)-1].ID)
+		require.NoError(t, err)
+		assert.Equal(t, bookings[len(bookings)-1].UserID, last.UserID)
+	})
+
+	t.Run("a failing batch rolls back the batches written before it", func(t *testing.T) {
+		before := count(t, `SELECT COUNT(*) FROM bookings WHERE event_id = $1`)
+		bookings := newBatchBookings(t, eventID, 1500)
+		bookings[1200].ID = bookings[0].ID
+
+		tx, err := dbClient.BeginTx(ctx, nil)
+		require.NoError(t, err)
+		err = bookingRepo.CreateBatchWithExecutor(ctx, tx, bookings)
+		require.Error(t, err)
+		require.NoError(t, tx.Rollback())
+
+		assert.Equal(t, before, count(t, `SELECT COUNT(*) FROM bookings WHERE event_id = $1`))
+	})
+}
+
+// BenchmarkBookingInsert compares writing bookings with batch inserts against one insert per booking
+// Run with: go test ./tests -run '^$' -bench BookingInsert -benchtime 10x
+func BenchmarkBookingInsert(b *testing.B) {
+	db, cleanup := setupTestDB(b)
+	defer cleanup()
+
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	eventID := createBatchEvent(b, dbClient)
+	ctx := context.Background()
+
+	for _, rows := range []int{100, 2000} {
+		insert := func(b *testing.B, write func(tx domain.Transaction, bookings []*domain.Booking) error) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				bookings := newBatchBookings(b, eventID, rows)
+				b.StartTimer()
+
+				tx, err := dbClient.BeginTx(ctx, nil)
+				require.NoError(b, err)
+				require.NoError(b, write(tx, bookings))
+				require.NoError(b, tx.Commit())
+			}
+		}
+
+		b.Run(fmt.Sprintf("batched/%d", rows), func(b *testing.B) {
+			insert(b, func(tx domain.Transaction, bookings []*domain.Booking) error {
+				return bookingRepo.CreateBatchWithExecutor(ctx, tx, bookings)
+			})
+		})
+
+		b.Run(fmt.Sprintf("per_row/%d", rows), func(b *testing.B) {
+			insert(b, func(tx domain.Transaction, bookings []*domain.Booking) error {
+				for _, booking := range bookings {
+					if err := bookingRepo.CreateWithExecutor(ctx, tx, booking); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		})
+	}
+}
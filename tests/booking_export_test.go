@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"context"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookingExport_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+
+	concert, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name:     "Autumn Concert",
+		Date:     time.Now().Add(14 * 24 * time.Hour),
+		Location: "Concert Hall",
+		Tickets:  100,
+	})
+	require.NoError(t, err)
+
+	lecture, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name:     "Evening Lecture",
+		Date:     time.Now().Add(21 * 24 * time.Hour),
+		Location: "Library",
+		Tickets:  40,
+	})
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	first, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: concert.ID, UserID: userID, TicketsBooked: 2})
+	require.NoError(t, err)
+	second, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: concert.ID, UserID: uuid.New(), TicketsBooked: 5})
+	require.NoError(t, err)
+	_, err = bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: lecture.ID, UserID: userID, TicketsBooked: 1})
+	require.NoError(t, err)
+
+	export := func(t *testing.T, query string) (*httptest.ResponseRecorder, [][]string) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/admin/bookings/export"+query, nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			return rec, nil
+		}
+		records, err := csv.NewReader(rec.Body).ReadAll()
+		require.NoError(t, err)
+		return rec, records
+	}
+
+	t.Run("exports bookings of an event as CSV", func(t *testing.T) {
+		rec, records := export(t, "?event_id="+concert.ID.String())
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+		assert.Contains(t, rec.Header().Get("Content-Disposition"), `filename="bookings-`)
+
+		require.Len(t, records, 3)
+		assert.Equal(t, []string{"id", "event_id", "user_id", "tickets_booked", "booked_at", "status"}, records[0])
+
+		ids := []string{records[1][0], records[2][0]}
+		assert.ElementsMatch(t, []string{first.ID.String(), second.ID.String()}, ids)
+		for _, record := range records[1:] {
+			assert.Equal(t, concert.ID.String(), record[1])
+			_, err := strconv.Atoi(record[3])
+			assert.NoError(t, err)
+			_, err = time.Parse(time.RFC3339, record[4])
+			assert.NoError(t, err)
+			assert.Equal(t, "confirmed", record[5])
+		}
+	})
+
+	t.Run("filters by user", func(t *testing.T) {
+		_, records := export(t, "?user_id="+userID.String())
+		require.Len(t, records, 3)
+		for _, record := range records[1:] {
+			assert.Equal(t, userID.String(), record[2])
+		}
+	})
+
+	t.Run("writes only the header when nothing matches", func(t *testing.T) {
+		_, records := export(t, "?event_id="+uuid.New().String())
+		require.Len(t, records, 1)
+	})
+
+	t.Run("rejects malformed filters", func(t *testing.T) {
+		rec, _ := export(t, "?from=yesterday")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
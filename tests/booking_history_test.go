@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookingHistory_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+
+	getHistory := func(t *testing.T, id string) (*httptest.ResponseRecorder, transport.BookingHistoryResponse) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/bookings/"+id+"/history", nil))
+		var history transport.BookingHistoryResponse
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &history))
+		}
+		return rec, history
+	}
+
+	t.Run("lists changes in the order they happened", func(t *testing.T) {
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:              "Kayak Trip",
+			Date:              time.Now().Add(30 * 24 * time.Hour),
+			Location:          "River Base",
+			Tickets:           20,
+			MinViable:         3,
+			ViabilityDeadline: time.Now().Add(7 * 24 * time.Hour),
+		})
+		require.NoError(t, err)
+
+		userID := uuid.New()
+		booking, err := bookingService.CreateBookingOnBehalf(ctx, "jane@support", app.CreateBookingRequest{
+			EventID:       event.ID,
+			UserID:        userID,
+			TicketsBooked: 3,
+			Conditional:   true,
+		})
+		require.NoError(t, err)
+
+		_, err = bookingService.ResolveConditionalBookings(ctx, event.ID)
+		require.NoError(t, err)
+		_, err = bookingService.CancelAllForUser(ctx, userID)
+		require.NoError(t, err)
+
+		rec, history := getHistory(t, booking.ID.String())
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, booking.ID.String(), history.BookingID)
+		require.Len(t, history.History, 3)
+
+		created, confirmed, cancelled := history.History[0], history.History[1], history.History[2]
+		assert.Equal(t, "created", created.Action)
+		assert.Equal(t, "pending", created.Status)
+		assert.Equal(t, "jane@support", created.Actor)
+		assert.Equal(t, "Booked 3 tickets, pending the event's minimum by jane@support", created.Description)
+
+		assert.Equal(t, "confirmed", confirmed.Action)
+		assert.Equal(t, "confirmed", confirmed.Status)
+		assert.Empty(t, confirmed.Actor)
+
+		assert.Equal(t, "cancelled", cancelled.Action)
+		assert.Equal(t, "Cancelled, releasing 3 tickets", cancelled.Description)
+
+		assert.False(t, confirmed.ChangedAt.Before(created.ChangedAt))
+		assert.False(t, cancelled.ChangedAt.Before(confirmed.ChangedAt))
+	})
+
+	t.Run("unknown booking", func(t *testing.T) {
+		rec, _ := getHistory(t, uuid.NewString())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("invalid booking id", func(t *testing.T) {
+		rec, _ := getHistory(t, "not-a-uuid")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
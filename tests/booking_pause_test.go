@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookingPause_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name:     "Mispriced Gala",
+		Date:     time.Now().Add(7 * 24 * time.Hour),
+		Location: "Hall",
+		Tickets:  50,
+	})
+	require.NoError(t, err)
+
+	book := func() error {
+		_, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 2})
+		return err
+	}
+	post := func(action string) transport.EventResponse {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/events/"+event.ID.String()+"/"+action, nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var response transport.EventResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response
+	}
+
+	require.NoError(t, book())
+
+	assert.True(t, post("pause").BookingsPaused)
+	assert.True(t, post("pause").BookingsPaused, "pausing twice is a no-op")
+	assert.ErrorIs(t, book(), domain.ErrBookingPaused)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events/"+event.ID.String(), nil))
+	require.Equal(t, http.StatusOK, rec.Code, "reads are not affected by the pause")
+	assert.Contains(t, rec.Body.String(), `"bookings_paused":true`)
+
+	availability, err := eventService.GetAvailability(ctx, event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 48, availability.AvailableTickets, "the rejected booking reserved nothing")
+
+	assert.False(t, post("resume").BookingsPaused)
+	require.NoError(t, book())
+
+	t.Run("a booking that read the event before the pause is rejected in its transaction", func(t *testing.T) {
+		stale, err := eventRepo.FindByID(ctx, event.ID)
+		require.NoError(t, err)
+		require.NoError(t, stale.CheckBookable(time.Now()))
+
+		post("pause")
+		defer post("resume")
+
+		tx, err := dbClient.BeginTx(ctx, nil)
+		require.NoError(t, err)
+		defer tx.Rollback()
+		current, err := eventRepo.FindByIDForShare(ctx, tx, event.ID)
+		require.NoError(t, err)
+		assert.ErrorIs(t, current.CheckBookable(time.Now()), domain.ErrBookingPaused)
+	})
+
+	t.Run("cancelled events cannot be paused", func(t *testing.T) {
+		_, err := eventService.CancelEvent(ctx, event.ID)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/events/"+event.ID.String()+"/pause", nil))
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+}
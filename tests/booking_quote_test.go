@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookingQuote_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name:       "Paid Gig",
+		Date:       time.Now().Add(7 * 24 * time.Hour),
+		Location:   "Club",
+		Tickets:    10,
+		PriceCents: 2500,
+		Currency:   "EUR",
+	})
+	require.NoError(t, err)
+
+	quote := func(eventID uuid.UUID, tickets int) *httptest.ResponseRecorder {
+		body, err := json.Marshal(map[string]int{"tickets": tickets})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/events/"+eventID.String()+"/quote", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("prices tickets without reserving them", func(t *testing.T) {
+		rec := quote(event.ID, 3)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var got transport.QuoteResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		assert.Equal(t, int64(2500), got.UnitPriceCents)
+		assert.Equal(t, int64(7500), got.SubtotalCents)
+		assert.Equal(t, int64(0), got.FeesCents)
+		assert.Equal(t, int64(7500), got.TotalCents)
+		assert.Equal(t, "EUR", got.Currency)
+
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 10, availability.AvailableTickets)
+	})
+
+	t.Run("rejects non-positive ticket counts", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, quote(event.ID, 0).Code)
+		assert.Equal(t, http.StatusBadRequest, quote(event.ID, -2).Code)
+	})
+
+	t.Run("rejects more tickets than the event has", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, quote(event.ID, 11).Code)
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, quote(uuid.New(), 1).Code)
+	})
+}
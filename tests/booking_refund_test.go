@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookingRefunds_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+
+	clock := time.Now().UTC()
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger,
+		app.WithBookingClock(domain.NewClock(func() time.Time { return clock }, 0)))
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger,
+		app.WithCancelledEventBookings(bookingService))
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	// Two days out, the default policy would refund only half and the event's own tiers nothing
+	createEvent := func(t *testing.T, req app.CreateEventRequest) *domain.Event {
+		t.Helper()
+		req.Name, req.Location, req.Tickets = "Harbour Festival", "Pier 4", 20
+		req.Date = time.Now().Add(48 * time.Hour)
+		req.PriceCents, req.Currency = 2500, "EUR"
+		event, err := eventService.CreateEvent(ctx, req)
+		require.NoError(t, err)
+		return event
+	}
+	book := func(t *testing.T, req app.CreateBookingRequest) *domain.Booking {
+		t.Helper()
+		req.UserID = uuid.New()
+		booking, err := bookingService.CreateBooking(ctx, req)
+		require.NoError(t, err)
+		return booking
+	}
+	stored := func(t *testing.T, id uuid.UUID) *domain.Booking {
+		t.Helper()
+		booking, err := bookingService.GetBooking(ctx, id)
+		require.NoError(t, err)
+		return booking
+	}
+	available := func(t *testing.T, eventID uuid.UUID) int {
+		t.Helper()
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, eventID)
+		require.NoError(t, err)
+		return availability.AvailableTickets
+	}
+
+	t.Run("stores the event's own refund tiers", func(t *testing.T) {
+		tiers := []domain.RefundTier{{MinNotice: 72 * time.Hour, RefundPercent: 90}}
+		event := createEvent(t, app.CreateEventRequest{RefundTiers: tiers})
+
+		loaded, err := eventService.GetEvent(ctx, event.ID)
+		require.NoError(t, err)
+		require.NotNil(t, loaded.CancellationPolicy)
+		assert.Equal(t, tiers, loaded.CancellationPolicy.Tiers())
+
+		booking := book(t, app.CreateBookingRequest{EventID: event.ID, TicketsBooked: 2})
+		summary, err := bookingService.CancelAllForUser(ctx, booking.UserID)
+		require.NoError(t, err)
+		require.Len(t, summary.Refunds, 1)
+		assert.Zero(t, summary.Refunds[0].RefundCents, "the event's tiers refund nothing within 72 hours")
+		assert.Zero(t, stored(t, booking.ID).RefundCents)
+	})
+
+	t.Run("cancelling the event cancels its bookings and refunds them in full", func(t *testing.T) {
+		event := createEvent(t, app.CreateEventRequest{RefundTiers: []domain.RefundTier{{MinNotice: 72 * time.Hour, RefundPercent: 90}}})
+		confirmed := book(t, app.CreateBookingRequest{EventID: event.ID, TicketsBooked: 2})
+		reserved := book(t, app.CreateBookingRequest{EventID: event.ID, TicketsBooked: 3, ExpiresIn: time.Hour})
+		require.Equal(t, 15, available(t, event.ID))
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/events/"+event.ID.String()+"/cancel", nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var got transport.EventCancellationResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		assert.Equal(t, string(domain.EventStatusCancelled), got.Status)
+		assert.ElementsMatch(t, []transport.BookingRefundResponse{
+			{BookingID: confirmed.ID.String(), RefundCents: 5000, Currency: "EUR"},
+			{BookingID: reserved.ID.String(), RefundCents: 0, Currency: "EUR"},
+		}, got.Refunds, "an unconfirmed reservation was never paid")
+
+		assert.Equal(t, domain.BookingStatusCancelled, stored(t, confirmed.ID).Status)
+		assert.Equal(t, int64(5000), stored(t, confirmed.ID).RefundCents)
+		assert.Equal(t, domain.BookingStatusCancelled, stored(t, reserved.ID).Status)
+		assert.Equal(t, 20, available(t, event.ID))
+
+		again, err := eventService.CancelEvent(ctx, event.ID)
+		require.NoError(t, err)
+		assert.Empty(t, again.Refunds, "repeating the cancellation refunds nothing twice")
+	})
+
+	t.Run("resolving an unviable event refunds its conditional bookings in full", func(t *testing.T) {
+		event := createEvent(t, app.CreateEventRequest{MinViable: 10, ViabilityDeadline: time.Now().Add(24 * time.Hour)})
+		first := book(t, app.CreateBookingRequest{EventID: event.ID, TicketsBooked: 2, Conditional: true})
+		second := book(t, app.CreateBookingRequest{EventID: event.ID, TicketsBooked: 1, Conditional: true})
+		_, err := db.ExecContext(ctx, "UPDATE events SET viability_deadline = $2 WHERE id = $1", event.ID, time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		resolution, err := bookingService.ResolveConditionalBookings(ctx, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, resolution.Cancelled)
+		assert.Equal(t, int64(7500), resolution.RefundCents)
+		assert.Equal(t, int64(5000), stored(t, first.ID).RefundCents)
+		assert.Equal(t, int64(2500), stored(t, second.ID).RefundCents)
+	})
+
+	t.Run("an expired reservation refunds nothing", func(t *testing.T) {
+		event := createEvent(t, app.CreateEventRequest{})
+		reserved := book(t, app.CreateBookingRequest{EventID: event.ID, TicketsBooked: 2, ExpiresIn: 5 * time.Minute})
+
+		clock = clock.Add(5 * time.Minute)
+		released, err := bookingService.ReleaseExpiredBookings(ctx, 100)
+		require.NoError(t, err)
+		assert.Equal(t, 1, released)
+
+		expired := stored(t, reserved.ID)
+		assert.Equal(t, domain.BookingStatusCancelled, expired.Status)
+		assert.Zero(t, expired.RefundCents)
+	})
+}
@@ -0,0 +1,169 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookingReservation_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+
+	clock := time.Now().UTC()
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger,
+		app.WithBookingClock(domain.NewClock(func() time.Time { return clock }, 0)))
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name:     "Opera Gala",
+		Date:     time.Now().Add(30 * 24 * time.Hour),
+		Location: "Opera House",
+		Tickets:  20,
+	})
+	require.NoError(t, err)
+
+	post := func(t *testing.T, path string, body map[string]any) *httptest.ResponseRecorder {
+		t.Helper()
+		raw, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(raw))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	book := func(t *testing.T, body map[string]any) transport.BookingResponse {
+		t.Helper()
+		body["event_id"] = event.ID.String()
+		body["user_id"] = uuid.New().String()
+		rec := post(t, "/bookings", body)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		var response transport.BookingResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response
+	}
+	confirm := func(t *testing.T, id string) *httptest.ResponseRecorder {
+		t.Helper()
+		return post(t, "/bookings/"+id+"/confirm", map[string]any{})
+	}
+	available := func(t *testing.T) int {
+		t.Helper()
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, event.ID)
+		require.NoError(t, err)
+		return availability.AvailableTickets
+	}
+
+	t.Run("without expires_in the booking is confirmed immediately", func(t *testing.T) {
+		before := available(t)
+
+		booking := book(t, map[string]any{"tickets_booked": 2})
+
+		assert.Equal(t, string(domain.BookingStatusConfirmed), booking.Status)
+		assert.Nil(t, booking.ExpiresAt)
+		assert.Equal(t, before-2, available(t))
+		assert.Equal(t, http.StatusConflict, confirm(t, booking.ID).Code, "only reservations are confirmed")
+	})
+
+	t.Run("with expires_in the booking reserves the tickets until confirmed", func(t *testing.T) {
+		before := available(t)
+
+		booking := book(t, map[string]any{"tickets_booked": 3, "expires_in": "15m"})
+
+		assert.Equal(t, string(domain.BookingStatusPending), booking.Status)
+		require.NotNil(t, booking.ExpiresAt)
+		assert.WithinDuration(t, clock.Add(15*time.Minute), *booking.ExpiresAt, time.Second)
+		assert.Equal(t, before-3, available(t), "tickets are reserved immediately")
+
+		clock = clock.Add(10 * time.Minute)
+		rec := confirm(t, booking.ID)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var confirmed transport.BookingResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &confirmed))
+		assert.Equal(t, string(domain.BookingStatusConfirmed), confirmed.Status)
+
+		clock = clock.Add(time.Hour)
+		released, err := bookingService.ReleaseExpiredBookings(ctx, 100)
+		require.NoError(t, err)
+		assert.Zero(t, released, "a confirmed reservation is kept past its deadline")
+		assert.Equal(t, before-3, available(t))
+	})
+
+	t.Run("the sweeper releases reservations not confirmed in time", func(t *testing.T) {
+		before := available(t)
+		booking := book(t, map[string]any{"tickets_booked": 4, "expires_in": "5m"})
+		assert.Equal(t, before-4, available(t))
+
+		released, err := bookingService.ReleaseExpiredBookings(ctx, 100)
+		require.NoError(t, err)
+		assert.Zero(t, released, "the reservation has not expired yet")
+
+		clock = clock.Add(5 * time.Minute)
+		released, err = bookingService.ReleaseExpiredBookings(ctx, 100)
+		require.NoError(t, err)
+		assert.Equal(t, 1, released)
+		assert.Equal(t, before, available(t))
+
+		id, err := uuid.Parse(booking.ID)
+		require.NoError(t, err)
+		stored, err := bookingService.GetBooking(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, domain.BookingStatusCancelled, stored.Status)
+
+		history, err := bookingService.GetBookingHistory(ctx, id)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, domain.BookingReserved, history[0].Action)
+		assert.Equal(t, domain.BookingExpired, history[1].Action)
+
+		assert.Equal(t, http.StatusConflict, confirm(t, booking.ID).Code)
+	})
+
+	t.Run("confirming an expired reservation releases it", func(t *testing.T) {
+		before := available(t)
+		booking := book(t, map[string]any{"tickets_booked": 1, "expires_in": "5m"})
+
+		clock = clock.Add(6 * time.Minute)
+		assert.Equal(t, http.StatusGone, confirm(t, booking.ID).Code)
+		assert.Equal(t, before, available(t))
+	})
+
+	t.Run("rejects invalid expiries", func(t *testing.T) {
+		for _, body := range []map[string]any{
+			{"tickets_booked": 1, "expires_in": "soon"},
+			{"tickets_booked": 1, "expires_in": "-5m"},
+			{"tickets_booked": 1, "expires_in": "48h"},
+		} {
+			body["event_id"] = event.ID.String()
+			body["user_id"] = uuid.New().String()
+			assert.Equal(t, http.StatusBadRequest, post(t, "/bookings", body).Code, body["expires_in"])
+		}
+	})
+}
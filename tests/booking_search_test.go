@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookingCodeSearch_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+
+	event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name:     "Support Desk Gig",
+		Date:     time.Now().Add(30 * 24 * time.Hour),
+		Location: "Club",
+		Tickets:  10,
+	})
+	require.NoError(t, err)
+
+	booking, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 1})
+	require.NoError(t, err)
+	other, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 1})
+	require.NoError(t, err)
+
+	search := func(prefix string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/admin/bookings/search?code_prefix="+prefix, nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("booking response carries its confirmation code", func(t *testing.T) {
+		stored, err := bookingService.GetBooking(ctx, booking.ID)
+		require.NoError(t, err)
+		assert.Equal(t, booking.ConfirmationCode, stored.ConfirmationCode)
+		assert.Len(t, stored.ConfirmationCode, 8)
+	})
+
+	t.Run("matches prefix case-insensitively", func(t *testing.T) {
+		rec := search(strings.ToLower(booking.ConfirmationCode[:4]))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var body transport.PagedResponse[transport.BookingResponse]
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		require.NotEmpty(t, body.Data)
+		ids := make([]string, 0, len(body.Data))
+		for _, found := range body.Data {
+			assert.True(t, strings.HasPrefix(found.ConfirmationCode, booking.ConfirmationCode[:4]))
+			ids = append(ids, found.ID)
+		}
+		assert.Contains(t, ids, booking.ID.String())
+	})
+
+	t.Run("full code matches exactly one booking", func(t *testing.T) {
+		rec := search(other.ConfirmationCode)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var body transport.PagedResponse[transport.BookingResponse]
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		require.Len(t, body.Data, 1)
+		assert.Equal(t, other.ID.String(), body.Data[0].ID)
+		assert.Equal(t, 1, body.TotalCount)
+	})
+
+	t.Run("rejects too short prefix", func(t *testing.T) {
+		rec := search(booking.ConfirmationCode[:2])
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookingSplit_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name:     "Split Concert",
+		Date:     time.Now().Add(30 * 24 * time.Hour),
+		Location: "Hall",
+		Tickets:  10,
+	})
+	require.NoError(t, err)
+
+	availableTickets := func(t *testing.T) int {
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, event.ID)
+		require.NoError(t, err)
+		return availability.AvailableTickets
+	}
+
+	split := func(t *testing.T, bookingID uuid.UUID, count int, newUserID uuid.UUID) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"split_count":%d,"new_user_id":%q}`, count, newUserID)
+		req := httptest.NewRequest(http.MethodPost, "/bookings/"+bookingID.String()+"/split", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	original, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 5})
+	require.NoError(t, err)
+	require.Equal(t, 5, availableTickets(t))
+
+	t.Run("splits tickets to a new booking without touching availability", func(t *testing.T) {
+		friend := uuid.New()
+		rec := split(t, original.ID, 2, friend)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		var response transport.SplitBookingResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, 3, response.Original.TicketsBooked)
+		assert.Equal(t, 2, response.Split.TicketsBooked)
+		assert.Equal(t, friend.String(), response.Split.UserID)
+		assert.Equal(t, 5, availableTickets(t))
+
+		stored, err := bookingService.GetBooking(ctx, original.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 3, stored.TicketsBooked)
+
+		history, err := bookingService.GetBookingHistory(ctx, original.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.BookingSplit, history[len(history)-1].Action)
+
+		ledger, _, err := bookingService.GetEventLedger(ctx, event.ID, 100, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 5, ledger[len(ledger)-1].AvailableAfter, "a split is not an availability change")
+	})
+
+	t.Run("cancelling both bookings releases every ticket", func(t *testing.T) {
+		friend := uuid.New()
+		rec := split(t, original.ID, 1, friend)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		_, err := bookingService.CancelAllForUser(ctx, friend)
+		require.NoError(t, err)
+		assert.Equal(t, 6, availableTickets(t))
+		_, err = bookingService.CancelAllForUser(ctx, original.UserID)
+		require.NoError(t, err)
+		assert.Equal(t, 8, availableTickets(t), "the first split's 2 tickets are still booked")
+	})
+
+	t.Run("rejects splitting every ticket", func(t *testing.T) {
+		booking, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 3})
+		require.NoError(t, err)
+
+		rec := split(t, booking.ID, 3, uuid.New())
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "split_count")
+
+		stored, err := bookingService.GetBooking(ctx, booking.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 3, stored.TicketsBooked)
+	})
+
+	t.Run("unknown booking", func(t *testing.T) {
+		rec := split(t, uuid.New(), 1, uuid.New())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
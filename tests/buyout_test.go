@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuyout_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	createEvent := func(t *testing.T, tickets int) *domain.Event {
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:     "Corporate Offsite " + uuid.NewString(),
+			Date:     time.Now().Add(7 * 24 * time.Hour),
+			Location: "Hall",
+			Tickets:  tickets,
+		})
+		require.NoError(t, err)
+		return event
+	}
+	buyout := func(eventID uuid.UUID) *httptest.ResponseRecorder {
+		body := `{"user_id": "` + uuid.NewString() + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/events/"+eventID.String()+"/buyout", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("books every remaining ticket in one booking", func(t *testing.T) {
+		event := createEvent(t, 40)
+		_, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 4})
+		require.NoError(t, err)
+
+		rec := buyout(event.ID)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		var booking transport.BookingResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &booking))
+		assert.Equal(t, 36, booking.TicketsBooked)
+
+		availability, err := eventService.GetAvailability(ctx, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, availability.AvailableTickets)
+
+		rec = buyout(event.ID)
+		assert.Equal(t, http.StatusConflict, rec.Code, "nothing is left to buy out")
+	})
+
+	t.Run("a buyout racing a booking never oversells", func(t *testing.T) {
+		const rounds, tickets, booked = 10, 20, 3
+
+		for range rounds {
+			event := createEvent(t, tickets)
+
+			type result struct {
+				tickets int
+				err     error
+			}
+			results := make(chan result, 2)
+			start := make(chan struct{})
+			retry := func(fn func() (*domain.Booking, error)) {
+				<-start
+				for {
+					booking, err := fn()
+					if errors.Is(err, infrastructure.ErrSerializationFailure) {
+						continue
+					}
+					if err != nil {
+						results <- result{err: err}
+						return
+					}
+					results <- result{tickets: booking.TicketsBooked}
+					return
+				}
+			}
+			go retry(func() (*domain.Booking, error) {
+				return bookingService.Buyout(ctx, event.ID, uuid.New())
+			})
+			go retry(func() (*domain.Booking, error) {
+				return bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: booked})
+			})
+			close(start)
+
+			total := 0
+			for range 2 {
+				r := <-results
+				if r.err != nil {
+					// Only the booking can lose: the buyout always finds tickets left, whichever commits first
+					assert.ErrorIs(t, r.err, domain.ErrInsufficientTickets)
+					continue
+				}
+				total += r.tickets
+			}
+			assert.Equal(t, tickets, total, "every ticket is sold exactly once")
+
+			sold, err := bookingRepo.SumTicketsByEventWithExecutor(ctx, dbClient, event.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tickets, sold)
+
+			availability, err := eventService.GetAvailability(ctx, event.ID)
+			require.NoError(t, err)
+			assert.Equal(t, 0, availability.AvailableTickets)
+		}
+	})
+}
@@ -0,0 +1,248 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCartServices(db infrastructure.DBClient) (*app.EventService, *app.BookingService, *infrastructure.PostgresTicketAvailabilityRepository) {
+	logger := zerolog.Nop()
+	eventRepo := infrastructure.NewPostgresEventRepository(db)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(db)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(db)
+	seatRepo := infrastructure.NewPostgresSeatRepository(db)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(db)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(db)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, db, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, db, logger)
+	return eventService, bookingService, ticketAvailabilityRepo
+}
+
+func createCartEvents(tb testing.TB, eventService *app.EventService, n, tickets int) []uuid.UUID {
+	tb.Helper()
+
+	ids := make([]uuid.UUID, 0, n)
+	for i := 0; i < n; i++ {
+		event, err := eventService.CreateEvent(context.Background(), app.CreateEventRequest{
+			Name:     "Festival Day",
+			Date:     time.Now().Add(time.Duration(10+i) * 24 * time.Hour),
+			Location: "Fairground",
+			Tickets:  tickets,
+		})
+		require.NoError(tb, err)
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func TestCartBooking_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	eventService, bookingService, ticketAvailabilityRepo := newCartServices(infrastructure.NewDBClientAdapter(db))
+	ctx := context.Background()
+
+	t.Run("books every event in the cart", func(t *testing.T) {
+		eventIDs := createCartEvents(t, eventService, 3, 10)
+		userID := uuid.New()
+
+		bookings, err := bookingService.CreateBookings(ctx, app.CreateBookingsRequest{
+			UserID: userID,
+			Items: []app.CartItem{
+				{EventID: eventIDs[0], TicketsBooked: 1},
+				{EventID: eventIDs[1], TicketsBooked: 2},
+				{EventID: eventIDs[2], TicketsBooked: 3},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, bookings, 3)
+
+		for i, booking := range bookings {
+			assert.Equal(t, eventIDs[i], booking.EventID)
+
+			stored, err := bookingService.GetBooking(ctx, booking.ID)
+			require.NoError(t, err)
+			assert.Equal(t, userID, stored.UserID)
+			assert.Equal(t, i+1, stored.TicketsBooked)
+
+			availability, err := ticketAvailabilityRepo.FindByEventID(ctx, eventIDs[i])
+			require.NoError(t, err)
+			assert.Equal(t, 10-(i+1), availability.AvailableTickets)
+		}
+	})
+
+	t.Run("books nothing when any event lacks tickets", func(t *testing.T) {
+		eventIDs := createCartEvents(t, eventService, 2, 5)
+		_, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: eventIDs[1], UserID: uuid.New(), TicketsBooked: 3})
+		require.NoError(t, err)
+
+		_, err = bookingService.CreateBookings(ctx, app.CreateBookingsRequest{
+			UserID: uuid.New(),
+			Items: []app.CartItem{
+				{EventID: eventIDs[0], TicketsBooked: 2},
+				{EventID: eventIDs[1], TicketsBooked: 3},
+			},
+		})
+		assert.ErrorIs(t, err, domain.ErrInsufficientTickets)
+
+		for i, want := range []int{5, 2} {
+			availability, err := ticketAvailabilityRepo.FindByEventID(ctx, eventIDs[i])
+			require.NoError(t, err)
+			assert.Equal(t, want, availability.AvailableTickets, "a failed cart must not reserve anything")
+		}
+	})
+
+	t.Run("rejects unknown and repeated events", func(t *testing.T) {
+		eventIDs := createCartEvents(t, eventService, 1, 5)
+
+		_, err := bookingService.CreateBookings(ctx, app.CreateBookingsRequest{
+			UserID: uuid.New(),
+			Items:  []app.CartItem{{EventID: eventIDs[0], TicketsBooked: 1}, {EventID: uuid.New(), TicketsBooked: 1}},
+		})
+		assert.ErrorIs(t, err, domain.ErrEventNotFound)
+
+		_, err = bookingService.CreateBookings(ctx, app.CreateBookingsRequest{
+			UserID: uuid.New(),
+			Items:  []app.CartItem{{EventID: eventIDs[0], TicketsBooked: 1}, {EventID: eventIDs[0], TicketsBooked: 1}},
+		})
+		assert.ErrorIs(t, err, domain.ErrDuplicateCartEvent)
+	})
+}
+
+// countingDBClient counts the round trips made through it to the database, BEGIN and COMMIT included
+type countingDBClient struct {
+	infrastructure.DBClient
+	roundTrips int
+}
+
+func (c *countingDBClient) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	c.roundTrips++
+	return c.DBClient.ExecContext(ctx, query, args...)
+}
+
+func (c *countingDBClient) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	c.roundTrips++
+	return c.DBClient.QueryContext(ctx, query, args...)
+}
+
+func (c *countingDBClient) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	c.roundTrips++
+	return c.DBClient.QueryRowContext(ctx, query, args...)
+}
+
+func (c *countingDBClient) BeginTx(ctx context.Context, opts *sql.TxOptions) (domain.Transaction, error) {
+	c.roundTrips++
+	tx, err := c.DBClient.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &countingTx{Transaction: tx, client: c}, nil
+}
+
+type countingTx struct {
+	domain.Transaction
+	client *countingDBClient
+}
+
+func (tx *countingTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	tx.client.roundTrips++
+	return tx.Transaction.ExecContext(ctx, query, args...)
+}
+
+func (tx *countingTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	tx.client.roundTrips++
+	return tx.Transaction.QueryContext(ctx, query, args...)
+}
+
+func (tx *countingTx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	tx.client.roundTrips++
+	return tx.Transaction.QueryRowContext(ctx, query, args...)
+}
+
+func (tx *countingTx) Commit() error {
+	tx.client.roundTrips++
+	return tx.Transaction.Commit()
+}
+
+func (tx *countingTx) Rollback() error {
+	tx.client.roundTrips++
+	return tx.Transaction.Rollback()
+}
+
+// TestCartBooking_RoundTrips measures the database round trips of both booking paths; the README quotes them
+func TestCartBooking_RoundTrips(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	client := &countingDBClient{DBClient: infrastructure.NewDBClientAdapter(db)}
+	eventService, bookingService, _ := newCartServices(client)
+	ctx := context.Background()
+	eventIDs := createCartEvents(t, eventService, 20, 100)
+
+	cart := func(eventIDs []uuid.UUID) int {
+		items := make([]app.CartItem, 0, len(eventIDs))
+		for _, eventID := range eventIDs {
+			items = append(items, app.CartItem{EventID: eventID, TicketsBooked: 1})
+		}
+		client.roundTrips = 0
+		_, err := bookingService.CreateBookings(ctx, app.CreateBookingsRequest{UserID: uuid.New(), Items: items})
+		require.NoError(t, err)
+		return client.roundTrips
+	}
+	assert.Equal(t, 7, cart(eventIDs[:2]), "read, BEGIN, share-lock events, lock availability, update it, insert bookings, COMMIT")
+	assert.Equal(t, 7, cart(eventIDs), "a larger cart costs no more round trips")
+
+	client.roundTrips = 0
+	userID := uuid.New()
+	for _, eventID := range eventIDs {
+		_, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: eventID, UserID: userID, TicketsBooked: 1})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 20*7, client.roundTrips, "each booking costs its own read, transaction and statements")
+}
+
+// BenchmarkCartBooking compares booking a 20-event cart in one transaction with booking the same
+// events one CreateBooking at a time
+// Run with: go test ./tests -run '^$' -bench CartBooking -benchtime 200x
+func BenchmarkCartBooking(b *testing.B) {
+	db, cleanup := setupTestDB(b)
+	defer cleanup()
+
+	const cartSize = 20
+	eventService, bookingService, _ := newCartServices(infrastructure.NewDBClientAdapter(db))
+	eventIDs := createCartEvents(b, eventService, cartSize, 1_000_000)
+	ctx := context.Background()
+
+	b.Run("batched", func(b *testing.B) {
+		items := make([]app.CartItem, 0, cartSize)
+		for _, eventID := range eventIDs {
+			items = append(items, app.CartItem{EventID: eventID, TicketsBooked: 1})
+		}
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, err := bookingService.CreateBookings(ctx, app.CreateBookingsRequest{UserID: uuid.New(), Items: items})
+			require.NoError(b, err)
+		}
+	})
+
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			userID := uuid.New()
+			for _, eventID := range eventIDs {
+				_, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: eventID, UserID: userID, TicketsBooked: 1})
+				require.NoError(b, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditionalBookings_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+
+	ctx := context.Background()
+
+	createEvent := func(t *testing.T, minViable int) *domain.Event {
+		t.Helper()
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:              "Kayak Trip",
+			Date:              time.Now().Add(30 * 24 * time.Hour),
+			Location:          "River Base",
+			Tickets:           20,
+			MinViable:         minViable,
+			ViabilityDeadline: time.Now().Add(7 * 24 * time.Hour),
+		})
+		require.NoError(t, err)
+		return event
+	}
+
+	book := func(t *testing.T, eventID uuid.UUID, tickets int, conditional bool) *domain.Booking {
+		t.Helper()
+		booking, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{
+			EventID:       eventID,
+			UserID:        uuid.New(),
+			TicketsBooked: tickets,
+			Conditional:   conditional,
+		})
+		require.NoError(t, err)
+		return booking
+	}
+
+	passDeadline := func(t *testing.T, eventID uuid.UUID) {
+		t.Helper()
+		_, err := db.ExecContext(ctx, "UPDATE events SET viability_deadline = $2 WHERE id = $1", eventID, time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+	}
+
+	t.Run("confirms conditional bookings when the threshold is met", func(t *testing.T) {
+		event := createEvent(t, 8)
+		first := book(t, event.ID, 3, true)
+		second := book(t, event.ID, 3, true)
+		book(t, event.ID, 2, false)
+		assert.Equal(t, domain.BookingStatusPending, first.Status)
+
+		resolution, err := bookingService.ResolveConditionalBookings(ctx, event.ID)
+		require.NoError(t, err)
+		assert.True(t, resolution.Viable)
+		assert.Equal(t, 2, resolution.Confirmed)
+		assert.Equal(t, 0, resolution.Cancelled)
+
+		for _, id := range []uuid.UUID{first.ID, second.ID} {
+			stored, err := bookingService.GetBooking(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, domain.BookingStatusConfirmed, stored.Status)
+		}
+
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 12, availability.AvailableTickets)
+	})
+
+	t.Run("leaves bookings pending before the deadline when the threshold is not met", func(t *testing.T) {
+		event := createEvent(t, 10)
+		booking := book(t, event.ID, 4, true)
+
+		_, err := bookingService.ResolveConditionalBookings(ctx, event.ID)
+		assert.ErrorIs(t, err, domain.ErrViabilityUndecided)
+
+		stored, err := bookingService.GetBooking(ctx, booking.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.BookingStatusPending, stored.Status)
+	})
+
+	t.Run("cancels conditional bookings and releases tickets when the deadline passes unmet", func(t *testing.T) {
+		event := createEvent(t, 10)
+		conditional := book(t, event.ID, 4, true)
+		regular := book(t, event.ID, 2, false)
+		passDeadline(t, event.ID)
+
+		resolution, err := bookingService.ResolveConditionalBookings(ctx, event.ID)
+		require.NoError(t, err)
+		assert.False(t, resolution.Viable)
+		assert.Equal(t, 0, resolution.Confirmed)
+		assert.Equal(t, 1, resolution.Cancelled)
+
+		stored, err := bookingService.GetBooking(ctx, conditional.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.BookingStatusCancelled, stored.Status)
+
+		stored, err = bookingService.GetBooking(ctx, regular.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.BookingStatusConfirmed, stored.Status, "unconditional bookings are not affected")
+
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 18, availability.AvailableTickets)
+
+		_, err = bookingService.CreateBooking(ctx, app.CreateBookingRequest{
+			EventID:       event.ID,
+			UserID:        uuid.New(),
+			TicketsBooked: 1,
+			Conditional:   true,
+		})
+		assert.ErrorIs(t, err, domain.ErrViabilityDeadlinePassed)
+	})
+
+	t.Run("rejects conditional bookings for events without a minimum", func(t *testing.T) {
+		event := createEvent(t, 0)
+		_, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{
+			EventID:       event.ID,
+			UserID:        uuid.New(),
+			TicketsBooked: 1,
+			Conditional:   true,
+		})
+		assert.ErrorIs(t, err, domain.ErrConditionalNotSupported)
+	})
+}
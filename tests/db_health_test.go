@@ -0,0 +1,30 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBHealthCheck_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clients := map[string]infrastructure.DBClient{
+		"adapter":      infrastructure.NewDBClientAdapter(db),
+		"instrumented": infrastructure.NewInstrumentedPostgresClient(db),
+	}
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			info, err := client.HealthCheck(context.Background())
+			require.NoError(t, err)
+
+			assert.Positive(t, info.Latency)
+			assert.Regexp(t, `^16\.`, info.ServerVersion, "the test container runs postgres:16")
+			assert.Positive(t, info.Pool.OpenConnections)
+		})
+	}
+}
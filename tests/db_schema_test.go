@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBSchema_Integration(t *testing.T) {
+	config, terminate := startPostgres(t)
+	defer terminate()
+
+	config.Schema = "tenant_a"
+	db, err := infrastructure.NewPostgresDB(config)
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	_, err = db.ExecContext(ctx, "CREATE SCHEMA tenant_a")
+	require.NoError(t, err)
+	runMigrations(t, db)
+
+	tablesIn := func(schema string) int {
+		var count int
+		err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = $1", schema).Scan(&count)
+		require.NoError(t, err)
+		return count
+	}
+	assert.Positive(t, tablesIn("tenant_a"), "migrations create their tables in the configured schema")
+	assert.Zero(t, tablesIn("public"))
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	require.NoError(t, infrastructure.SchemaCheck(dbClient).Run(ctx))
+
+	eventService := app.NewEventService(
+		infrastructure.NewPostgresEventRepository(dbClient),
+		infrastructure.NewPostgresTicketAvailabilityRepository(dbClient),
+		infrastructure.NewPostgresSeatRepository(dbClient),
+		dbClient, logger,
+	)
+	event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name:     "Tenant Gala",
+		Date:     time.Now().Add(7 * 24 * time.Hour),
+		Location: "Hall",
+		Tickets:  10,
+	})
+	require.NoError(t, err)
+
+	var count int
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tenant_a.events WHERE id = $1", event.ID).Scan(&count))
+	assert.Equal(t, 1, count, "unqualified queries resolve in the configured schema")
+}
@@ -0,0 +1,200 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscountCodes_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	createEvent := func(t *testing.T) *domain.Event {
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:       "Paid Gig",
+			Date:       time.Now().Add(7 * 24 * time.Hour),
+			Location:   "Club",
+			Tickets:    10,
+			PriceCents: 2500,
+			Currency:   "EUR",
+		})
+		require.NoError(t, err)
+		return event
+	}
+	createCode := func(t *testing.T, req app.CreateDiscountCodeRequest) *domain.DiscountCode {
+		code, err := bookingService.CreateDiscountCode(ctx, req)
+		require.NoError(t, err)
+		return code
+	}
+	uses := func(t *testing.T, code string) int {
+		var uses int
+		require.NoError(t, db.QueryRowContext(ctx, "SELECT uses FROM discount_codes WHERE code = $1", code).Scan(&uses))
+		return uses
+	}
+
+	t.Run("creates codes through the admin API", func(t *testing.T) {
+		body, err := json.Marshal(map[string]any{"code": "launch-5", "amount_off_cents": 500, "currency": "eur", "max_uses": 10})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/admin/discount-codes", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		var got transport.DiscountCodeResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		assert.Equal(t, "LAUNCH-5", got.Code)
+		assert.Equal(t, "EUR", got.Currency)
+		assert.Equal(t, 0, got.Uses)
+
+		rec = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodPost, "/admin/discount-codes", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusConflict, rec.Code, "codes are unique")
+	})
+
+	t.Run("applies a valid code to the booking price", func(t *testing.T) {
+		event := createEvent(t)
+		createCode(t, app.CreateDiscountCodeRequest{Code: "TENOFF", PercentOff: 10, MaxUses: 5})
+
+		body, err := json.Marshal(map[string]any{
+			"event_id":       event.ID.String(),
+			"user_id":        uuid.NewString(),
+			"tickets_booked": 2,
+			"discount_code":  "tenoff",
+		})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/bookings", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		var got transport.BookingResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		assert.Equal(t, int64(4500), got.PriceCents)
+		assert.Equal(t, "EUR", got.Currency)
+		assert.Equal(t, "TENOFF", got.DiscountCode)
+		assert.Equal(t, 1, uses(t, "TENOFF"))
+
+		stored, err := bookingRepo.FindByID(ctx, uuid.MustParse(got.ID))
+		require.NoError(t, err)
+		assert.Equal(t, int64(4500), stored.PriceCents)
+		assert.Equal(t, "TENOFF", stored.DiscountCode)
+	})
+
+	t.Run("bookings without a code pay the full price", func(t *testing.T) {
+		event := createEvent(t)
+
+		booking, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 3})
+		require.NoError(t, err)
+		assert.Equal(t, int64(7500), booking.PriceCents)
+		assert.Empty(t, booking.DiscountCode)
+	})
+
+	t.Run("rejects an expired code", func(t *testing.T) {
+		event := createEvent(t)
+		createCode(t, app.CreateDiscountCodeRequest{Code: "BYGONE", PercentOff: 10, MaxUses: 5, ExpiresAt: time.Now().Add(-time.Hour)})
+
+		_, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 1, DiscountCode: "BYGONE"})
+		assert.ErrorIs(t, err, domain.ErrDiscountCodeExpired)
+		assert.Equal(t, 0, uses(t, "BYGONE"))
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 10, availability.AvailableTickets, "a rejected code books nothing")
+	})
+
+	t.Run("rejects an exhausted code", func(t *testing.T) {
+		event := createEvent(t)
+		createCode(t, app.CreateDiscountCodeRequest{Code: "ONCE", PercentOff: 10, MaxUses: 1})
+
+		_, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 1, DiscountCode: "ONCE"})
+		require.NoError(t, err)
+		_, err = bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 1, DiscountCode: "ONCE"})
+		assert.ErrorIs(t, err, domain.ErrDiscountCodeExhausted)
+		assert.Equal(t, 1, uses(t, "ONCE"))
+	})
+
+	t.Run("rejects unknown and malformed codes", func(t *testing.T) {
+		event := createEvent(t)
+
+		_, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 1, DiscountCode: "NOSUCHCODE"})
+		assert.ErrorIs(t, err, domain.ErrDiscountCodeNotFound)
+
+		_, err = bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 1, DiscountCode: "50% off"})
+		assert.ErrorIs(t, err, domain.ErrInvalidDiscountCode)
+	})
+
+	t.Run("concurrent redemptions never exceed max uses", func(t *testing.T) {
+		const maxUses, bookers = 3, 10
+		createCode(t, app.CreateDiscountCodeRequest{Code: "RUSH", PercentOff: 20, MaxUses: maxUses})
+
+		// Each booker books a different event, so the code's row is the only contended lock
+		events := make([]*domain.Event, bookers)
+		for i := range events {
+			events[i] = createEvent(t)
+		}
+
+		results := make(chan error, bookers)
+		start := make(chan struct{})
+		for _, event := range events {
+			go func() {
+				<-start
+				req := app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 1, DiscountCode: "RUSH"}
+				for {
+					_, err := bookingService.CreateBooking(ctx, req)
+					if !errors.Is(err, infrastructure.ErrSerializationFailure) {
+						results <- err
+						return
+					}
+				}
+			}()
+		}
+		close(start)
+
+		succeeded := 0
+		for range bookers {
+			err := <-results
+			if err == nil {
+				succeeded++
+				continue
+			}
+			assert.ErrorIs(t, err, domain.ErrDiscountCodeExhausted)
+		}
+
+		assert.Equal(t, maxUses, succeeded)
+		assert.Equal(t, maxUses, uses(t, "RUSH"))
+	})
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventICalendar_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	date := time.Now().Add(7 * 24 * time.Hour).UTC().Truncate(time.Second)
+	event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name:     "Calendar Night",
+		Date:     date,
+		Location: "Hall",
+		Tickets:  50,
+	})
+	require.NoError(t, err)
+
+	requests := map[string]*http.Request{
+		"ics suffix": httptest.NewRequest(http.MethodGet, "/events/"+event.ID.String()+".ics", nil),
+		"accept header": func() *http.Request {
+			req := httptest.NewRequest(http.MethodGet, "/events/"+event.ID.String(), nil)
+			req.Header.Set("Accept", "text/calendar")
+			return req
+		}(),
+	}
+	for name, req := range requests {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+			assert.Equal(t, "text/calendar; charset=utf-8", rec.Header().Get("Content-Type"))
+			body := rec.Body.String()
+			assert.Contains(t, body, "BEGIN:VEVENT\r\n")
+			assert.Contains(t, body, "UID:"+event.ID.String()+"@booking-service\r\n")
+			assert.Contains(t, body, "SUMMARY:Calendar Night\r\n")
+			assert.Contains(t, body, "LOCATION:Hall\r\n")
+			assert.Contains(t, body, "DTSTART:"+date.Format("20060102T150405Z")+"\r\n")
+		})
+	}
+
+	t.Run("unknown event", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events/00000000-0000-0000-0000-000000000001.ics", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	createEvent := func(t *testing.T, body map[string]any) *httptest.ResponseRecorder {
+		t.Helper()
+		raw, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(raw))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("derives the end from a duration", func(t *testing.T) {
+		rec := createEvent(t, map[string]any{"name": "Long Night", "date": date, "location": "Hall", "tickets": 10, "duration": "3h"})
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		var created transport.EventResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+		require.NotNil(t, created.EndDate)
+		assert.True(t, date.Add(3*time.Hour).Equal(*created.EndDate))
+
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events/"+created.ID+".ics", nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Contains(t, rec.Body.String(), "DTEND:"+date.Add(3*time.Hour).Format("20060102T150405Z")+"\r\n")
+	})
+
+	t.Run("events without an end have no DTEND", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events/"+event.ID.String()+".ics", nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.NotContains(t, rec.Body.String(), "DTEND")
+	})
+
+	t.Run("rejects invalid ends", func(t *testing.T) {
+		for name, extra := range map[string]map[string]any{
+			"end before start":      {"end_date": date.Add(-time.Hour)},
+			"end at start":          {"end_date": date},
+			"end and duration":      {"end_date": date.Add(time.Hour), "duration": "1h"},
+			"malformed duration":    {"duration": "three hours"},
+			"non-positive duration": {"duration": "-1h"},
+		} {
+			body := map[string]any{"name": "Bad End", "date": date, "location": "Hall", "tickets": 10}
+			for key, value := range extra {
+				body[key] = value
+			}
+			rec := createEvent(t, body)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, "%s: %s", name, rec.Body.String())
+		}
+	})
+}
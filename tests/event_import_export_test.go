@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventImportExport_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+
+	exportEvents := func(t *testing.T) string {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/events/export", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+		return rec.Body.String()
+	}
+
+	importEvents := func(t *testing.T, body, query string) (int, transport.EventImportResponse) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/admin/events/import"+query, strings.NewReader(body))
+		router.ServeHTTP(rec, req)
+		var response transport.EventImportResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return rec.Code, response
+	}
+
+	organizerID := uuid.New()
+	for i, name := range []string{"Opening Night", "Matinee", "Closing Gala"} {
+		_, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:        name,
+			Date:        time.Now().Add(time.Duration(i+1) * 24 * time.Hour).Truncate(time.Second),
+			Location:    "Grand Theatre",
+			Tickets:     50 * (i + 1),
+			OrganizerID: organizerID,
+			MinAdvance:  time.Hour,
+		})
+		require.NoError(t, err)
+	}
+	cancelled, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name:     "Rained Out",
+		Date:     time.Now().Add(48 * time.Hour).Truncate(time.Second),
+		Location: "Open Air Stage",
+		Tickets:  10,
+	})
+	require.NoError(t, err)
+	_, err = eventService.CancelEvent(ctx, cancelled.ID)
+	require.NoError(t, err)
+
+	t.Run("round trips events through export and import", func(t *testing.T) {
+		exported := exportEvents(t)
+
+		lines := 0
+		scanner := bufio.NewScanner(strings.NewReader(exported))
+		for scanner.Scan() {
+			var record transport.EventResponse
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+			lines++
+		}
+		require.Equal(t, 4, lines)
+
+		_, err := db.ExecContext(ctx, "DELETE FROM ticket_availability; DELETE FROM events")
+		require.NoError(t, err)
+
+		code, response := importEvents(t, exported, "")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 4, response.Created)
+		assert.Equal(t, 0, response.Skipped)
+		assert.Equal(t, 0, response.Failed)
+
+		assert.Equal(t, exported, exportEvents(t))
+
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, cancelled.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 10, availability.AvailableTickets)
+	})
+
+	t.Run("skips events that already exist", func(t *testing.T) {
+		code, response := importEvents(t, exportEvents(t), "")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 0, response.Created)
+		assert.Equal(t, 4, response.Skipped)
+	})
+
+	valid := `{"id":"` + uuid.New().String() + `","name":"Late Addition","date":"2030-05-01T19:00:00Z","location":"Annex","tickets":5,"status":"active"}`
+	body := "not json\n" + valid + "\n"
+
+	t.Run("reports malformed lines and continues in skip mode", func(t *testing.T) {
+		code, response := importEvents(t, body, "?mode=skip")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 1, response.Created)
+		assert.Equal(t, 1, response.Failed)
+		require.Len(t, response.Errors, 1)
+		assert.Equal(t, 1, response.Errors[0].Line)
+	})
+
+	t.Run("stops at the first malformed line in abort mode", func(t *testing.T) {
+		code, response := importEvents(t, body, "?mode=abort")
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.True(t, response.Aborted)
+		assert.Equal(t, 0, response.Created)
+		require.Len(t, response.Errors, 1)
+		assert.Equal(t, 1, response.Errors[0].Line)
+	})
+}
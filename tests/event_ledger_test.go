@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventLedger_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name:     "Ledger Gala",
+		Date:     time.Now().Add(30 * 24 * time.Hour),
+		Location: "Opera House",
+		Tickets:  20,
+	})
+	require.NoError(t, err)
+
+	for _, tickets := range []int{3, 5} {
+		_, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: tickets})
+		require.NoError(t, err)
+	}
+	canceller := uuid.New()
+	_, err = bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: canceller, TicketsBooked: 2})
+	require.NoError(t, err)
+	_, err = bookingService.CancelAllForUser(ctx, canceller)
+	require.NoError(t, err)
+	_, err = bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 4})
+	require.NoError(t, err)
+
+	getPage := func(t *testing.T, limit, offset int) transport.PagedResponse[transport.LedgerEntryResponse] {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		path := fmt.Sprintf("/events/%s/ledger?limit=%d&offset=%d", event.ID, limit, offset)
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var page transport.PagedResponse[transport.LedgerEntryResponse]
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		return page
+	}
+
+	t.Run("the running total ends at the current availability", func(t *testing.T) {
+		var deltas, availableAfter []int
+		for offset := 0; ; offset += 2 {
+			page := getPage(t, 2, offset)
+			assert.Equal(t, 5, page.TotalCount)
+			if len(page.Data) == 0 {
+				break
+			}
+			for _, entry := range page.Data {
+				deltas = append(deltas, entry.Delta)
+				availableAfter = append(availableAfter, entry.AvailableAfter)
+			}
+		}
+
+		assert.Equal(t, []int{-3, -5, -2, 2, -4}, deltas)
+		assert.Equal(t, []int{17, 12, 10, 12, 8}, availableAfter, "later pages continue the running total")
+
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, availability.AvailableTickets, availableAfter[len(availableAfter)-1])
+	})
+
+	t.Run("lists entries oldest first", func(t *testing.T) {
+		page := getPage(t, 100, 0)
+		require.Len(t, page.Data, 5)
+		assert.Equal(t, "cancelled", page.Data[3].Action)
+		for i := 1; i < len(page.Data); i++ {
+			assert.False(t, page.Data[i].ChangedAt.Before(page.Data[i-1].ChangedAt))
+		}
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events/"+uuid.NewString()+"/ledger", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
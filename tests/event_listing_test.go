@@ -0,0 +1,412 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventListingPagination_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	var created []string
+	for i := range 5 {
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:     "Series Night",
+			Date:     time.Now().Add(time.Duration(i+1) * 24 * time.Hour),
+			Location: "Hall",
+			Tickets:  10,
+		})
+		require.NoError(t, err)
+		created = append(created, event.ID.String())
+	}
+
+	get := func(path string) (*httptest.ResponseRecorder, map[string]json.RawMessage) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		var raw map[string]json.RawMessage
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &raw))
+		}
+		return rec, raw
+	}
+
+	t.Run("walks all events with the cursor", func(t *testing.T) {
+		var seen []string
+		path := "/events?limit=2"
+		for pages := 0; pages < 10; pages++ {
+			rec, _ := get(path)
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+			var page transport.PagedResponse[transport.EventSummary]
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+			assert.Equal(t, 5, page.TotalCount)
+			assert.Equal(t, 2, page.Limit)
+			for _, event := range page.Data {
+				seen = append(seen, event.ID)
+			}
+
+			if page.NextCursor == "" {
+				break
+			}
+			path = "/events?limit=2&cursor=" + page.NextCursor
+		}
+
+		assert.Equal(t, created, seen)
+	})
+
+	t.Run("cursor and offset endpoints share the envelope", func(t *testing.T) {
+		for _, path := range []string{"/events?limit=2", "/events/upcoming?limit=2"} {
+			rec, body := get(path)
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			for _, key := range []string{"data", "total_count", "limit", "offset"} {
+				assert.Contains(t, body, key, path)
+			}
+		}
+
+		_, cursorPage := get("/events?limit=2")
+		assert.Contains(t, cursorPage, "next_cursor")
+		_, offsetPage := get("/events/upcoming?limit=2")
+		assert.NotContains(t, offsetPage, "next_cursor")
+	})
+
+	t.Run("empty page has an empty data array", func(t *testing.T) {
+		rec, body := get("/events/upcoming?offset=100")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `[]`, string(body["data"]))
+	})
+}
+
+func TestEventListingConditionalGet_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	createEvent := func() uuid.UUID {
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:     "Polling Night",
+			Date:     time.Now().Add(48 * time.Hour),
+			Location: "Hall",
+			Tickets:  10,
+		})
+		require.NoError(t, err)
+		return event.ID
+	}
+	get := func(ifModifiedSince string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/events", nil)
+		if ifModifiedSince != "" {
+			req.Header.Set("If-Modified-Since", ifModifiedSince)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	// Last-Modified has second precision, so changes must land in a later second to be visible
+	nextSecond := func() { time.Sleep(1100 * time.Millisecond) }
+
+	t.Run("empty catalog has no Last-Modified", func(t *testing.T) {
+		rec := get("")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("Last-Modified"))
+	})
+
+	eventID := createEvent()
+
+	rec := get("")
+	require.Equal(t, http.StatusOK, rec.Code)
+	lastModified := rec.Header().Get("Last-Modified")
+	require.NotEmpty(t, lastModified)
+
+	t.Run("unchanged catalog returns 304", func(t *testing.T) {
+		rec := get(lastModified)
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Body.String())
+		assert.Equal(t, lastModified, rec.Header().Get("Last-Modified"))
+	})
+
+	t.Run("malformed If-Modified-Since is ignored", func(t *testing.T) {
+		rec := get("yesterday")
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("new event busts the cache", func(t *testing.T) {
+		nextSecond()
+		createEvent()
+
+		rec := get(lastModified)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.NotEqual(t, lastModified, rec.Header().Get("Last-Modified"))
+		lastModified = rec.Header().Get("Last-Modified")
+	})
+
+	t.Run("booking busts the cache of summaries", func(t *testing.T) {
+		nextSecond()
+		_, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: eventID, UserID: uuid.New(), TicketsBooked: 1})
+		require.NoError(t, err)
+
+		rec := get(lastModified)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.NotEqual(t, lastModified, rec.Header().Get("Last-Modified"))
+		lastModified = rec.Header().Get("Last-Modified")
+	})
+
+	t.Run("updated event busts the cache", func(t *testing.T) {
+		nextSecond()
+		_, err := eventService.CancelEvent(ctx, eventID)
+		require.NoError(t, err)
+
+		rec := get(lastModified)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.NotEqual(t, lastModified, rec.Header().Get("Last-Modified"))
+	})
+}
+
+func TestEventListingRepresentation_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	createEvent := func(name string, days, tickets int) uuid.UUID {
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:     name,
+			Date:     time.Now().Add(time.Duration(days) * 24 * time.Hour),
+			Location: "Arena",
+			Tickets:  tickets,
+			Tags:     []string{"music"},
+		})
+		require.NoError(t, err)
+		return event.ID
+	}
+	openID := createEvent("Open Gig", 1, 10)
+	soldOutID := createEvent("Sold Out Gig", 2, 2)
+	_, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: openID, UserID: uuid.New(), TicketsBooked: 3})
+	require.NoError(t, err)
+	_, err = bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: soldOutID, UserID: uuid.New(), TicketsBooked: 2})
+	require.NoError(t, err)
+
+	list := func(query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events"+query, nil))
+		return rec
+	}
+
+	t.Run("lists summaries by default", func(t *testing.T) {
+		rec := list("")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var raw struct {
+			Data []map[string]json.RawMessage `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &raw))
+		require.Len(t, raw.Data, 2)
+		for _, event := range raw.Data {
+			keys := make([]string, 0, len(event))
+			for key := range event {
+				keys = append(keys, key)
+			}
+			assert.ElementsMatch(t, []string{"id", "name", "date", "location", "available_tickets", "sold_out"}, keys)
+		}
+
+		var page transport.PagedResponse[transport.EventSummary]
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		assert.Equal(t, openID.String(), page.Data[0].ID)
+		assert.Equal(t, 7, page.Data[0].AvailableTickets)
+		assert.False(t, page.Data[0].SoldOut)
+		assert.Equal(t, soldOutID.String(), page.Data[1].ID)
+		assert.Zero(t, page.Data[1].AvailableTickets)
+		assert.True(t, page.Data[1].SoldOut)
+	})
+
+	t.Run("lists full events on request", func(t *testing.T) {
+		rec := list("?full=true")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var page transport.PagedResponse[transport.EventResponse]
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		require.Len(t, page.Data, 2)
+		assert.Equal(t, openID.String(), page.Data[0].ID)
+		assert.Equal(t, []string{"music"}, page.Data[0].Tags)
+		assert.Equal(t, 10, page.Data[0].Tickets)
+		assert.Contains(t, rec.Body.String(), `"status"`)
+	})
+
+	t.Run("detail stays full", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events/"+openID.String(), nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"tags"`)
+	})
+
+	t.Run("rejects an invalid full flag", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, list("?full=maybe").Code)
+	})
+}
+
+func TestEventListingByTag_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	createEvent := func(name string, day int, tags ...string) {
+		_, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:     name,
+			Date:     time.Now().Add(time.Duration(day) * 24 * time.Hour),
+			Location: "Hall",
+			Tickets:  10,
+			Tags:     tags,
+		})
+		require.NoError(t, err)
+	}
+	createEvent("Jazz Night", 1, "music", "jazz")
+	createEvent("Rock Festival", 2, "Music", "outdoor")
+	createEvent("Hamlet", 3, "theater")
+	createEvent("Derby", 4, "sports", "outdoor")
+	createEvent("Book Fair", 5)
+
+	list := func(query string) (int, []string) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events?"+query, nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var page transport.PagedResponse[transport.EventSummary]
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		var names []string
+		for _, event := range page.Data {
+			names = append(names, event.Name)
+		}
+		return page.TotalCount, names
+	}
+
+	t.Run("filters by a single tag", func(t *testing.T) {
+		total, names := list("tag=music")
+		assert.Equal(t, 2, total)
+		assert.Equal(t, []string{"Jazz Night", "Rock Festival"}, names)
+	})
+
+	t.Run("matches any of several tags by default", func(t *testing.T) {
+		total, names := list("tag=theater&tag=outdoor")
+		assert.Equal(t, 3, total)
+		assert.Equal(t, []string{"Rock Festival", "Hamlet", "Derby"}, names)
+
+		_, commaSeparated := list("tag=theater,outdoor&tag_mode=any")
+		assert.Equal(t, names, commaSeparated)
+	})
+
+	t.Run("matches all of several tags", func(t *testing.T) {
+		total, names := list("tag=music&tag=outdoor&tag_mode=all")
+		assert.Equal(t, 1, total)
+		assert.Equal(t, []string{"Rock Festival"}, names)
+	})
+
+	t.Run("pages through a filtered listing", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events?tag=outdoor&limit=1", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var page transport.PagedResponse[transport.EventSummary]
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		require.NotEmpty(t, page.NextCursor)
+
+		_, names := list("tag=outdoor&limit=1&cursor=" + page.NextCursor)
+		assert.Equal(t, []string{"Derby"}, names)
+	})
+
+	t.Run("returns tags on full events", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events?tag=jazz&full=true", nil))
+		var page transport.PagedResponse[transport.EventResponse]
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		require.Len(t, page.Data, 1)
+		assert.Equal(t, []string{"music", "jazz"}, page.Data[0].Tags)
+	})
+
+	t.Run("counts match the filtered listing", func(t *testing.T) {
+		for _, query := range []string{"", "tag=music", "tag=theater&tag=outdoor", "tag=music&tag=outdoor&tag_mode=all", "tag=opera"} {
+			total, names := list(query)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events/count?"+query, nil))
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			var count transport.EventCountResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &count))
+			assert.Equal(t, total, count.Count, query)
+			assert.Len(t, names, count.Count, query)
+		}
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events/count?tag=music&tag_mode=xor", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("rejects an unknown tag mode", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events?tag=music&tag_mode=xor", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
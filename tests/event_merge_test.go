@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeEvents_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	allocationRepo := infrastructure.NewPostgresAllocationRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger,
+		app.WithAllocations(allocationRepo))
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	allocationService := app.NewAllocationService(allocationRepo, eventRepo, ticketAvailabilityRepo, dbClient, logger)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger,
+		transport.WithEventMerge(app.NewEventMergeService(eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger)))
+
+	ctx := context.Background()
+	date := time.Now().Add(14 * 24 * time.Hour)
+	createEvent := func(t *testing.T, tickets int) *domain.Event {
+		t.Helper()
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{Name: "Jazz Night", Date: date, Location: "Blue Note", Tickets: tickets})
+		require.NoError(t, err)
+		return event
+	}
+	book := func(t *testing.T, eventID uuid.UUID, userID uuid.UUID, tickets int, allocation string) *domain.Booking {
+		t.Helper()
+		booking, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: eventID, UserID: userID, TicketsBooked: tickets, Allocation: allocation})
+		require.NoError(t, err)
+		return booking
+	}
+	merge := func(t *testing.T, sourceID, targetID uuid.UUID) *httptest.ResponseRecorder {
+		t.Helper()
+		raw, err := json.Marshal(map[string]string{"source_id": sourceID.String(), "target_id": targetID.String()})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/admin/events/merge", bytes.NewReader(raw))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	available := func(t *testing.T, eventID uuid.UUID) int {
+		t.Helper()
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, eventID)
+		require.NoError(t, err)
+		return availability.AvailableTickets
+	}
+
+	t.Run("moves the source's bookings and deletes it", func(t *testing.T) {
+		source := createEvent(t, 20)
+		target := createEvent(t, 10)
+
+		_, err := allocationService.CreateAllocation(ctx, app.CreateAllocationRequest{EventID: source.ID, Name: "press", Tickets: 4})
+		require.NoError(t, err)
+		sourceBookings := []*domain.Booking{
+			book(t, source.ID, uuid.New(), 3, ""),
+			book(t, source.ID, uuid.New(), 1, "press"),
+		}
+		canceller := uuid.New()
+		sourceBookings = append(sourceBookings, book(t, source.ID, canceller, 2, ""))
+		_, err = bookingService.CancelAllForUser(ctx, canceller)
+		require.NoError(t, err)
+		book(t, target.ID, uuid.New(), 4, "")
+		require.Equal(t, 6, available(t, target.ID))
+
+		rec := merge(t, source.ID, target.ID)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var response transport.MergeEventsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, source.ID.String(), response.SourceID)
+		assert.Equal(t, target.ID.String(), response.Target.ID)
+		assert.Equal(t, 3, response.BookingsMoved, "cancelled bookings move too")
+		assert.Equal(t, 4, response.TicketsMoved)
+		assert.Equal(t, 2, response.AvailableTickets)
+		assert.Equal(t, 2, available(t, target.ID))
+
+		for _, booking := range sourceBookings {
+			moved, err := bookingRepo.FindByID(ctx, booking.ID)
+			require.NoError(t, err)
+			assert.Equal(t, target.ID, moved.EventID)
+			assert.Equal(t, booking.Status, moved.Status)
+		}
+
+		expected, err := ticketAvailabilityRepo.ExpectedAvailableWithExecutor(ctx, dbClient, []uuid.UUID{target.ID})
+		require.NoError(t, err)
+		assert.Equal(t, 2, expected[target.ID], "the target's availability matches its bookings")
+
+		_, err = eventService.GetEvent(ctx, source.ID)
+		assert.ErrorIs(t, err, domain.ErrEventNotFound)
+		var mergedInto uuid.UUID
+		require.NoError(t, db.QueryRowContext(ctx, "SELECT merged_into FROM events WHERE id = $1 AND deleted_at IS NOT NULL", source.ID).Scan(&mergedInto))
+		assert.Equal(t, target.ID, mergedInto)
+
+		t.Run("a merged event cannot be merged again", func(t *testing.T) {
+			rec := merge(t, source.ID, target.ID)
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		})
+	})
+
+	t.Run("refuses to merge an event into itself", func(t *testing.T) {
+		event := createEvent(t, 10)
+
+		rec := merge(t, event.ID, event.ID)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("refuses to overbook the target", func(t *testing.T) {
+		source := createEvent(t, 10)
+		target := createEvent(t, 10)
+		booking := book(t, source.ID, uuid.New(), 5, "")
+		book(t, target.ID, uuid.New(), 7, "")
+
+		rec := merge(t, source.ID, target.ID)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+
+		unchanged, err := bookingRepo.FindByID(ctx, booking.ID)
+		require.NoError(t, err)
+		assert.Equal(t, source.ID, unchanged.EventID)
+		assert.Equal(t, 3, available(t, target.ID))
+		_, err = eventService.GetEvent(ctx, source.ID)
+		assert.NoError(t, err)
+	})
+}
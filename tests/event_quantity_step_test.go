@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventQuantityStep_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	lotteryService := app.NewLotteryService(infrastructure.NewPostgresLotteryRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	post := func(t *testing.T, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	date := time.Now().Add(30 * 24 * time.Hour).UTC().Format(time.RFC3339)
+
+	rec := post(t, "/events", fmt.Sprintf(`{"name":"Gala Dinner","date":%q,"location":"Grand Hotel","tickets":40,"quantity_step":4}`, date))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created transport.EventResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, 4, created.QuantityStep)
+	eventID := uuid.MustParse(created.ID)
+
+	stored, err := eventService.GetEvent(ctx, eventID)
+	require.NoError(t, err)
+	assert.Equal(t, 4, stored.QuantityStep)
+
+	book := func(t *testing.T, tickets int) *httptest.ResponseRecorder {
+		return post(t, "/bookings", fmt.Sprintf(`{"event_id":%q,"user_id":%q,"tickets_booked":%d}`, eventID, uuid.New(), tickets))
+	}
+
+	t.Run("books multiples of the step", func(t *testing.T) {
+		for _, tickets := range []int{4, 8} {
+			rec := book(t, tickets)
+			assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("rejects quantities that are not a multiple of the step", func(t *testing.T) {
+		for _, tickets := range []int{1, 6} {
+			rec := book(t, tickets)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, tickets)
+			assert.Contains(t, rec.Body.String(), "quantity_step")
+		}
+
+		_, err := bookingService.CreateBookings(ctx, app.CreateBookingsRequest{
+			UserID: uuid.New(),
+			Items:  []app.CartItem{{EventID: eventID, TicketsBooked: 2}},
+		})
+		assert.ErrorIs(t, err, domain.ErrTicketsNotMultipleOfStep)
+
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, eventID)
+		require.NoError(t, err)
+		assert.Equal(t, 28, availability.AvailableTickets, "only the 4 and 8 ticket bookings took tickets")
+	})
+
+	t.Run("holds, lottery entries and splits keep to the step", func(t *testing.T) {
+		_, err := holdService.CreateHold(ctx, app.CreateHoldRequest{EventID: eventID, UserID: uuid.New(), Tickets: 3})
+		assert.ErrorIs(t, err, domain.ErrTicketsNotMultipleOfStep)
+		_, err = holdService.CreateHold(ctx, app.CreateHoldRequest{EventID: eventID, UserID: uuid.New(), Tickets: 4})
+		require.NoError(t, err)
+
+		rec := post(t, "/events", fmt.Sprintf(`{"name":"Gala Raffle","date":%q,"location":"Grand Hotel","tickets":40,"quantity_step":4,"lottery":true}`, date))
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		var raffle transport.EventResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &raffle))
+		_, err = lotteryService.Register(ctx, app.RegisterLotteryRequest{EventID: uuid.MustParse(raffle.ID), UserID: uuid.New(), Tickets: 6})
+		assert.ErrorIs(t, err, domain.ErrTicketsNotMultipleOfStep)
+
+		booking, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: eventID, UserID: uuid.New(), TicketsBooked: 8})
+		require.NoError(t, err)
+		for _, count := range []int{2, 6} {
+			_, _, err := bookingService.SplitBooking(ctx, booking.ID, count, uuid.New())
+			assert.ErrorIs(t, err, domain.ErrTicketsNotMultipleOfStep, "splitting %d of 8", count)
+		}
+		original, split, err := bookingService.SplitBooking(ctx, booking.ID, 4, uuid.New())
+		require.NoError(t, err)
+		assert.Equal(t, 4, original.TicketsBooked)
+		assert.Equal(t, 4, split.TicketsBooked)
+	})
+
+	t.Run("a buyout takes the largest multiple of the step", func(t *testing.T) {
+		rec := post(t, "/events", fmt.Sprintf(`{"name":"Banquet","date":%q,"location":"Grand Hotel","tickets":10,"quantity_step":4}`, date))
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		var event transport.EventResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &event))
+		banquetID := uuid.MustParse(event.ID)
+
+		booking, err := bookingService.Buyout(ctx, banquetID, uuid.New())
+		require.NoError(t, err)
+		assert.Equal(t, 8, booking.TicketsBooked)
+
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, banquetID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, availability.AvailableTickets, "the tickets short of a step stay available")
+
+		_, err = bookingService.Buyout(ctx, banquetID, uuid.New())
+		assert.ErrorIs(t, err, domain.ErrNothingToBuyOut)
+	})
+
+	t.Run("a lottery entry the event no longer sells loses the draw", func(t *testing.T) {
+		rec := post(t, "/events", fmt.Sprintf(`{"name":"Cup Final","date":%q,"location":"Stadium","tickets":12,"lottery":true}`, date))
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		var event transport.EventResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &event))
+		lotteryEventID := uuid.MustParse(event.ID)
+
+		odd, err := lotteryService.Register(ctx, app.RegisterLotteryRequest{EventID: lotteryEventID, UserID: uuid.New(), Tickets: 3})
+		require.NoError(t, err)
+		even, err := lotteryService.Register(ctx, app.RegisterLotteryRequest{EventID: lotteryEventID, UserID: uuid.New(), Tickets: 4})
+		require.NoError(t, err)
+		// Entries registered before the event was sold in pairs
+		_, err = db.ExecContext(ctx, "UPDATE events SET quantity_step = 2 WHERE id = $1", lotteryEventID)
+		require.NoError(t, err)
+
+		draw, err := lotteryService.Draw(ctx, app.DrawLotteryRequest{EventID: lotteryEventID})
+		require.NoError(t, err)
+		require.Len(t, draw.Winners, 1)
+		assert.Equal(t, even.ID, draw.Winners[0].ID)
+		require.Len(t, draw.Losers, 1)
+		assert.Equal(t, odd.ID, draw.Losers[0].ID)
+
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, lotteryEventID)
+		require.NoError(t, err)
+		assert.Equal(t, 8, availability.AvailableTickets)
+	})
+
+	t.Run("events without a step sell any quantity", func(t *testing.T) {
+		rec := post(t, "/events", fmt.Sprintf(`{"name":"Open Mic","date":%q,"location":"Cellar Bar","tickets":10}`, date))
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		var event transport.EventResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &event))
+		assert.Equal(t, 1, event.QuantityStep)
+
+		rec = post(t, "/bookings", fmt.Sprintf(`{"event_id":%q,"user_id":%q,"tickets_booked":3}`, event.ID, uuid.New()))
+		assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	})
+
+	t.Run("rejects a step larger than the event", func(t *testing.T) {
+		rec := post(t, "/events", fmt.Sprintf(`{"name":"Private Room","date":%q,"location":"Grand Hotel","tickets":6,"quantity_step":8}`, date))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "quantity_step")
+	})
+}
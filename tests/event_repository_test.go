@@ -99,3 +99,87 @@ func TestEventRepository_AfterProperMigrations(t *testing.T) {
 	assert.Equal(t, event.ID, retrieved.ID)
 	assert.Equal(t, event.Tickets, retrieved.Tickets)
 }
+
+func TestEventRepository_FindByNameAndDate(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	eventRepo := infrastructure.NewPostgresEventRepository(infrastructure.NewDBClientAdapter(db))
+
+	day := time.Now().Add(30 * 24 * time.Hour).UTC().Truncate(24 * time.Hour)
+	event, err := domain.NewEvent("Jazz Night", "Blue Room", day.Add(20*time.Hour), 80)
+	require.NoError(t, err)
+	require.NoError(t, eventRepo.Create(ctx, event))
+
+	found, err := eventRepo.FindByNameAndDate(ctx, "JAZZ NIGHT", day.Add(9*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, event.ID, found.ID)
+
+	_, err = eventRepo.FindByNameAndDate(ctx, "Jazz Night", day.Add(24*time.Hour))
+	assert.ErrorIs(t, err, domain.ErrEventNotFound)
+}
+
+func TestEventRepository_Images(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	eventRepo := infrastructure.NewPostgresEventRepository(infrastructure.NewDBClientAdapter(db))
+
+	event, err := domain.NewEvent("Poster Night", "Gallery", time.Now().Add(24*time.Hour), 50,
+		domain.WithImages("https://cdn.example.com/poster.jpg", "https://cdn.example.com/poster-thumb.jpg"))
+	require.NoError(t, err)
+	require.NoError(t, eventRepo.Create(ctx, event))
+
+	retrieved, err := eventRepo.FindByID(ctx, event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/poster.jpg", retrieved.ImageURL)
+	assert.Equal(t, "https://cdn.example.com/poster-thumb.jpg", retrieved.ThumbnailURL)
+
+	plain, err := domain.NewEvent("No Poster", "Gallery", time.Now().Add(24*time.Hour), 50)
+	require.NoError(t, err)
+	require.NoError(t, eventRepo.Create(ctx, plain))
+
+	retrieved, err = eventRepo.FindByID(ctx, plain.ID)
+	require.NoError(t, err)
+	assert.Empty(t, retrieved.ImageURL)
+	assert.Empty(t, retrieved.ThumbnailURL)
+}
+
+func TestEventRepository_FindAll_MalformedRow(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	failHard := infrastructure.NewPostgresEventRepository(dbClient)
+	skipping := infrastructure.NewPostgresEventRepository(dbClient, infrastructure.WithSkipMalformedRows(true))
+
+	var valid []*domain.Event
+	for i, name := range []string{"First", "Corrupt", "Last"} {
+		event, err := domain.NewEvent(name, "Hall", time.Now().Add(time.Duration(i+1)*24*time.Hour), 100)
+		require.NoError(t, err)
+		require.NoError(t, failHard.Create(ctx, event))
+		if name != "Corrupt" {
+			valid = append(valid, event)
+		}
+	}
+
+	// A bad migration leaves an unexpected NULL the repository cannot scan
+	_, err := db.ExecContext(ctx, "ALTER TABLE events ALTER COLUMN location DROP NOT NULL")
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, "UPDATE events SET location = NULL WHERE name = 'Corrupt'")
+	require.NoError(t, err)
+
+	_, _, err = failHard.FindAll(ctx)
+	require.Error(t, err, "by default one malformed row fails the whole listing")
+	assert.Contains(t, err.Error(), "failed to scan event")
+
+	events, skipped, err := skipping.FindAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, skipped)
+	require.Len(t, events, 2)
+	assert.Equal(t, valid[0].ID, events[0].ID)
+	assert.Equal(t, valid[1].ID, events[1].ID)
+}
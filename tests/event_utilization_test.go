@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventUtilization_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	createEvent := func(t *testing.T, tickets int) uuid.UUID {
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:     "Capacity Night",
+			Date:     time.Now().Add(7 * 24 * time.Hour),
+			Location: "Hall",
+			Tickets:  tickets,
+		})
+		require.NoError(t, err)
+		return event.ID
+	}
+	book := func(t *testing.T, eventID uuid.UUID, tickets int) {
+		_, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: eventID, UserID: uuid.New(), TicketsBooked: tickets})
+		require.NoError(t, err)
+	}
+	utilization := func(t *testing.T, eventID uuid.UUID) transport.UtilizationResponse {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events/"+eventID.String()+"/utilization", nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var response transport.UtilizationResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response
+	}
+
+	t.Run("partially sold", func(t *testing.T) {
+		eventID := createEvent(t, 8)
+		book(t, eventID, 2)
+
+		got := utilization(t, eventID)
+		assert.Equal(t, 2, got.Sold)
+		assert.Equal(t, 8, got.Total)
+		assert.Equal(t, 25.0, got.UtilizationPct)
+	})
+
+	t.Run("fully sold", func(t *testing.T) {
+		eventID := createEvent(t, 3)
+		book(t, eventID, 3)
+
+		got := utilization(t, eventID)
+		assert.Equal(t, 3, got.Sold)
+		assert.Equal(t, 100.0, got.UtilizationPct)
+	})
+
+	t.Run("zero-capacity event", func(t *testing.T) {
+		eventID := createEvent(t, 0)
+
+		got := utilization(t, eventID)
+		assert.Equal(t, 0, got.Total)
+		assert.Equal(t, 0.0, got.UtilizationPct)
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events/"+uuid.New().String()+"/utilization", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
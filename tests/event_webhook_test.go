@@ -0,0 +1,302 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventWebhooks_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	// The receiver is an httptest server on loopback, which production refuses
+	webhookService := app.NewWebhookService(infrastructure.NewPostgresWebhookRepository(dbClient), eventRepo,
+		infrastructure.NewHTTPWebhookSender(infrastructure.WithWebhookRetries(3, 10*time.Millisecond), infrastructure.WithWebhookInternalHosts()), logger,
+		app.WithInternalWebhookHosts())
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger,
+		app.WithBookingWebhooks(webhookService))
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	const adminToken = "admin-secret"
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger,
+		transport.WithWebhooks(webhookService), transport.WithAdminToken(adminToken))
+
+	ctx := context.Background()
+	const secret = "organizer-secret-123"
+
+	// The receiver fails the first delivery, so the booking only arrives through a retry
+	deliveries := make(chan *http.Request, 10)
+	bodies := make(chan []byte, 10)
+	attempts := 0
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		deliveries <- r
+		bodies <- body
+	}))
+	defer receiver.Close()
+
+	event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name:     "Webhook Gig",
+		Date:     time.Now().Add(30 * 24 * time.Hour),
+		Location: "Club",
+		Tickets:  50,
+	})
+	require.NoError(t, err)
+
+	register := func(t *testing.T, eventID string, body map[string]string) *httptest.ResponseRecorder {
+		t.Helper()
+		raw, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/admin/events/"+eventID+"/webhooks", bytes.NewReader(raw))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	deleteHook := func(eventID, hookID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, "/admin/events/"+eventID+"/webhooks/"+hookID, nil)
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	var hook transport.WebhookResponse
+	t.Run("requires the admin token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/events/"+event.ID.String()+"/webhooks",
+			bytes.NewReader([]byte(`{"url":"https://hooks.example.com","secret":"organizer-secret-123"}`)))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("registers a webhook", func(t *testing.T) {
+		rec := register(t, event.ID.String(), map[string]string{"url": receiver.URL, "secret": secret})
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &hook))
+		assert.Equal(t, event.ID.String(), hook.EventID)
+		assert.Equal(t, receiver.URL, hook.URL)
+		assert.NotContains(t, rec.Body.String(), secret)
+	})
+
+	t.Run("delivers a signed notification of a new booking", func(t *testing.T) {
+		booking, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{
+			EventID:       event.ID,
+			UserID:        uuid.New(),
+			TicketsBooked: 2,
+		})
+		require.NoError(t, err)
+
+		var delivery *http.Request
+		var body []byte
+		select {
+		case delivery = <-deliveries:
+			body = <-bodies
+		case <-time.After(5 * time.Second):
+			t.Fatal("webhook was not delivered")
+		}
+
+		timestamp, err := strconv.ParseInt(delivery.Header.Get(infrastructure.WebhookTimestampHeader), 10, 64)
+		require.NoError(t, err)
+		assert.Equal(t, infrastructure.SignWebhookPayload(secret, timestamp, body), delivery.Header.Get(infrastructure.WebhookSignatureHeader))
+
+		var payload map[string]any
+		require.NoError(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, "booking.created", payload["type"])
+		assert.Equal(t, booking.ID.String(), payload["booking_id"])
+		assert.Equal(t, event.ID.String(), payload["event_id"])
+		assert.Equal(t, float64(2), payload["tickets_booked"])
+		assert.Equal(t, 2, attempts, "the failed first attempt is retried")
+	})
+
+	t.Run("rejects invalid webhooks", func(t *testing.T) {
+		rec := register(t, event.ID.String(), map[string]string{"url": "javascript:alert(1)", "secret": secret})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = register(t, event.ID.String(), map[string]string{"url": receiver.URL, "secret": "short"})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = register(t, uuid.NewString(), map[string]string{"url": receiver.URL, "secret": secret})
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("deletes a webhook", func(t *testing.T) {
+		rec := deleteHook(uuid.NewString(), hook.ID)
+		assert.Equal(t, http.StatusNotFound, rec.Code, "a webhook is only deleted through its own event")
+
+		rec = deleteHook(event.ID.String(), hook.ID)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+
+		rec = deleteHook(event.ID.String(), hook.ID)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		_, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{
+			EventID:       event.ID,
+			UserID:        uuid.New(),
+			TicketsBooked: 1,
+		})
+		require.NoError(t, err)
+
+		waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		require.NoError(t, webhookService.Wait(waitCtx))
+		assert.Empty(t, deliveries, "deleted webhooks are not notified")
+	})
+
+	t.Run("stores deliveries that failed every retry", func(t *testing.T) {
+		down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer down.Close()
+
+		rec := register(t, event.ID.String(), map[string]string{"url": down.URL, "secret": secret})
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		var failing transport.WebhookResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &failing))
+
+		booking, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{
+			EventID:       event.ID,
+			UserID:        uuid.New(),
+			TicketsBooked: 1,
+		})
+		require.NoError(t, err)
+
+		waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		require.NoError(t, webhookService.Wait(waitCtx))
+
+		var bookingID, failureType, failureErr string
+		require.NoError(t, db.QueryRowContext(ctx,
+			`SELECT booking_id, type, error FROM webhook_delivery_failures WHERE webhook_id = $1`, failing.ID,
+		).Scan(&bookingID, &failureType, &failureErr))
+		assert.Equal(t, booking.ID.String(), bookingID)
+		assert.Equal(t, "booking.created", failureType)
+		assert.Contains(t, failureErr, "502")
+	})
+
+	t.Run("lists dead letters and retries them from the admin endpoints", func(t *testing.T) {
+		// The receiver fails until it recovers, so the first retry is dead-lettered again
+		var recovered atomic.Bool
+		received := make(chan []byte, 10)
+		flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !recovered.Load() {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			received <- body
+		}))
+		defer flaky.Close()
+
+		rec := register(t, event.ID.String(), map[string]string{"url": flaky.URL, "secret": secret})
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		var failing transport.WebhookResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &failing))
+
+		admin := func(method, path string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, path, nil)
+			req.Header.Set("Authorization", "Bearer "+adminToken)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			return rec
+		}
+		wait := func(t *testing.T) {
+			t.Helper()
+			waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			require.NoError(t, webhookService.Wait(waitCtx))
+		}
+		deadLetter := func(t *testing.T) transport.DeadLetterResponse {
+			t.Helper()
+			rec := admin(http.MethodGet, "/admin/dead-letters?limit=100")
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			var page transport.PagedResponse[transport.DeadLetterResponse]
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+			var found []transport.DeadLetterResponse
+			for _, letter := range page.Data {
+				if letter.WebhookID == failing.ID {
+					found = append(found, letter)
+				}
+			}
+			require.Len(t, found, 1)
+			return found[0]
+		}
+
+		booking, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{
+			EventID:       event.ID,
+			UserID:        uuid.New(),
+			TicketsBooked: 1,
+		})
+		require.NoError(t, err)
+		wait(t)
+
+		first := deadLetter(t)
+		assert.Equal(t, booking.ID.String(), first.BookingID)
+		assert.Equal(t, 1, first.Attempts)
+		assert.True(t, first.Retryable)
+
+		rec = admin(http.MethodPost, "/admin/dead-letters/"+first.ID+"/retry")
+		require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+		wait(t)
+
+		second := deadLetter(t)
+		assert.NotEqual(t, first.ID, second.ID, "the retried dead letter is removed")
+		assert.Equal(t, booking.ID.String(), second.BookingID)
+		assert.Equal(t, 2, second.Attempts)
+		assert.Equal(t, http.StatusNotFound, admin(http.MethodPost, "/admin/dead-letters/"+first.ID+"/retry").Code)
+
+		recovered.Store(true)
+		rec = admin(http.MethodPost, "/admin/dead-letters/"+second.ID+"/retry")
+		require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+		wait(t)
+
+		select {
+		case body := <-received:
+			var payload map[string]interface{}
+			require.NoError(t, json.Unmarshal(body, &payload))
+			assert.Equal(t, "booking.created", payload["type"])
+			assert.Equal(t, booking.ID.String(), payload["booking_id"])
+			assert.Equal(t, float64(1), payload["tickets_booked"])
+		default:
+			t.Fatal("the retried delivery did not arrive")
+		}
+
+		var remaining int
+		require.NoError(t, db.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM webhook_delivery_failures WHERE webhook_id = $1`, failing.ID,
+		).Scan(&remaining))
+		assert.Zero(t, remaining)
+	})
+}
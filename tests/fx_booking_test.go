@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayCurrencyBookings_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	rates := infrastructure.NewStaticFXRates(
+		domain.FXRate{From: "EUR", To: "USD", Rate: 1.08, AsOf: time.Now()},
+		domain.FXRate{From: "EUR", To: "GBP", Rate: 0.86, AsOf: time.Now().Add(-2 * time.Hour)},
+	)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger,
+		app.WithFXConversion(rates, time.Hour))
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name:       "Touring Concert",
+		Date:       time.Now().Add(7 * 24 * time.Hour),
+		Location:   "Arena",
+		Tickets:    10,
+		PriceCents: 2500,
+		Currency:   "EUR",
+	})
+	require.NoError(t, err)
+
+	book := func(payCurrency string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(transport.CreateBookingRequest{
+			EventID:       event.ID.String(),
+			UserID:        uuid.NewString(),
+			TicketsBooked: 2,
+			PayCurrency:   payCurrency,
+		})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/bookings", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("stores the base and converted prices", func(t *testing.T) {
+		rec := book("usd")
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		var response transport.BookingResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, int64(5000), response.PriceCents)
+		assert.Equal(t, "EUR", response.Currency)
+		assert.Equal(t, int64(5400), response.PayPriceCents)
+		assert.Equal(t, "USD", response.PayCurrency)
+		assert.Equal(t, 1.08, response.FXRate)
+
+		stored, err := bookingService.GetBooking(ctx, uuid.MustParse(response.ID))
+		require.NoError(t, err)
+		assert.Equal(t, int64(5000), stored.PriceCents)
+		assert.Equal(t, int64(5400), stored.PayPriceCents)
+		assert.Equal(t, "USD", stored.PayCurrency)
+	})
+
+	t.Run("paying in the event's currency converts nothing", func(t *testing.T) {
+		rec := book("EUR")
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		var response transport.BookingResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Zero(t, response.PayPriceCents)
+		assert.Empty(t, response.PayCurrency)
+	})
+
+	t.Run("rejects currencies without a rate", func(t *testing.T) {
+		rec := book("CHF")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "pay_currency")
+	})
+
+	t.Run("rejects stale rates", func(t *testing.T) {
+		rec := book("GBP")
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+
+	t.Run("no tickets are reserved by rejected bookings", func(t *testing.T) {
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 6, availability.AvailableTickets)
+	})
+}
@@ -0,0 +1,293 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHoldFlow_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	const holdTTL = 500 * time.Millisecond
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	holdRepo := infrastructure.NewPostgresHoldRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(holdRepo, eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, holdTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+
+	event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name:     "Jazz Night",
+		Date:     time.Now().Add(7 * 24 * time.Hour),
+		Location: "Blue Room",
+		Tickets:  20,
+	})
+	require.NoError(t, err)
+
+	do := func(t *testing.T, method, path string, body interface{}) *httptest.ResponseRecorder {
+		t.Helper()
+		var payload bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&payload).Encode(body))
+		}
+		req := httptest.NewRequest(method, path, &payload)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	createHold := func(t *testing.T, tickets int) transport.HoldResponse {
+		t.Helper()
+		rec := do(t, http.MethodPost, "/events/"+event.ID.String()+"/holds", map[string]interface{}{
+			"user_id": uuid.New().String(),
+			"tickets": tickets,
+		})
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		var hold transport.HoldResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &hold))
+		return hold
+	}
+
+	getHold := func(t *testing.T, id string) transport.HoldResponse {
+		t.Helper()
+		rec := do(t, http.MethodGet, "/holds/"+id, nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var hold transport.HoldResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &hold))
+		return hold
+	}
+
+	availableTickets := func(t *testing.T) int {
+		t.Helper()
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, event.ID)
+		require.NoError(t, err)
+		return availability.AvailableTickets
+	}
+
+	t.Run("hold then confirm creates a booking", func(t *testing.T) {
+		hold := createHold(t, 3)
+		assert.Equal(t, "active", hold.Status)
+		assert.True(t, hold.ExpiresAt.After(time.Now()))
+		assert.Equal(t, 17, availableTickets(t))
+
+		rec := do(t, http.MethodPost, "/holds/"+hold.ID+"/confirm", nil)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		var booking transport.BookingResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &booking))
+		assert.Equal(t, 3, booking.TicketsBooked)
+		assert.Equal(t, 17, availableTickets(t), "confirmation must not take tickets twice")
+
+		confirmed := getHold(t, hold.ID)
+		assert.Equal(t, "confirmed", confirmed.Status)
+		assert.Equal(t, booking.ID, confirmed.BookingID)
+
+		rec = do(t, http.MethodPost, "/holds/"+hold.ID+"/confirm", nil)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("confirming an expired hold returns 410 and releases its tickets", func(t *testing.T) {
+		before := availableTickets(t)
+		hold := createHold(t, 4)
+		assert.Equal(t, before-4, availableTickets(t))
+
+		time.Sleep(holdTTL + 100*time.Millisecond)
+		assert.Equal(t, "expired", getHold(t, hold.ID).Status)
+
+		rec := do(t, http.MethodPost, "/holds/"+hold.ID+"/confirm", nil)
+		require.Equal(t, http.StatusGone, rec.Code)
+		assert.Contains(t, rec.Body.String(), "hold has expired")
+		assert.Equal(t, before, availableTickets(t))
+
+		rec = do(t, http.MethodPost, "/holds/"+hold.ID+"/confirm", nil)
+		assert.Equal(t, http.StatusGone, rec.Code)
+		assert.Equal(t, before, availableTickets(t), "tickets must be released only once")
+	})
+
+	t.Run("confirms a hold through POST /bookings", func(t *testing.T) {
+		owner := uuid.New()
+		rec := do(t, http.MethodPost, "/events/"+event.ID.String()+"/holds", map[string]interface{}{
+			"user_id": owner.String(),
+			"tickets": 2,
+		})
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		var hold transport.HoldResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &hold))
+		before := availableTickets(t)
+
+		rec = do(t, http.MethodPost, "/bookings", map[string]interface{}{"hold_id": hold.ID, "user_id": uuid.New().String()})
+		assert.Equal(t, http.StatusNotFound, rec.Code, "only the user who placed the hold can confirm it")
+
+		rec = do(t, http.MethodPost, "/bookings", map[string]interface{}{"hold_id": hold.ID, "user_id": owner.String(), "tickets_booked": 5})
+		assert.Equal(t, http.StatusBadRequest, rec.Code, "the tickets come from the hold")
+
+		rec = do(t, http.MethodPost, "/bookings", map[string]interface{}{"hold_id": hold.ID, "user_id": owner.String()})
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		var booking transport.BookingResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &booking))
+		assert.Equal(t, event.ID.String(), booking.EventID)
+		assert.Equal(t, owner.String(), booking.UserID)
+		assert.Equal(t, 2, booking.TicketsBooked)
+		assert.Equal(t, before, availableTickets(t), "confirmation must not take tickets twice")
+		assert.Equal(t, booking.ID, getHold(t, hold.ID).BookingID)
+
+		rec = do(t, http.MethodPost, "/bookings", map[string]interface{}{"hold_id": hold.ID, "user_id": owner.String()})
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("confirming an expired hold through POST /bookings returns 410", func(t *testing.T) {
+		owner := uuid.New()
+		before := availableTickets(t)
+		rec := do(t, http.MethodPost, "/events/"+event.ID.String()+"/holds", map[string]interface{}{
+			"user_id": owner.String(),
+			"tickets": 3,
+		})
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		var hold transport.HoldResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &hold))
+
+		time.Sleep(holdTTL + 100*time.Millisecond)
+		rec = do(t, http.MethodPost, "/bookings", map[string]interface{}{"hold_id": hold.ID, "user_id": owner.String()})
+		assert.Equal(t, http.StatusGone, rec.Code)
+		assert.Equal(t, before, availableTickets(t))
+	})
+
+	t.Run("rejects an invalid hold_id", func(t *testing.T) {
+		rec := do(t, http.MethodPost, "/bookings", map[string]interface{}{"hold_id": "not-a-uuid", "user_id": uuid.New().String()})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = do(t, http.MethodPost, "/bookings", map[string]interface{}{"hold_id": uuid.New().String(), "user_id": uuid.New().String()})
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("sweeper releases expired holds", func(t *testing.T) {
+		before := availableTickets(t)
+		hold := createHold(t, 2)
+
+		time.Sleep(holdTTL + 100*time.Millisecond)
+		released, err := holdService.ReleaseExpiredHolds(ctx, 100)
+		require.NoError(t, err)
+		assert.Equal(t, 1, released)
+		assert.Equal(t, before, availableTickets(t))
+		assert.Equal(t, "expired", getHold(t, hold.ID).Status)
+	})
+
+	t.Run("rejects holds beyond availability", func(t *testing.T) {
+		rec := do(t, http.MethodPost, "/events/"+event.ID.String()+"/holds", map[string]interface{}{
+			"user_id": uuid.New().String(),
+			"tickets": availableTickets(t) + 1,
+		})
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("rejects holds beyond the event's capacity", func(t *testing.T) {
+		rec := do(t, http.MethodPost, "/events/"+event.ID.String()+"/holds", map[string]interface{}{
+			"user_id": uuid.New().String(),
+			"tickets": 1000,
+		})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("returns 404 for unknown hold", func(t *testing.T) {
+		rec := do(t, http.MethodGet, "/holds/"+uuid.New().String(), nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+type recordingNotifier struct {
+	notifications []domain.Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	n.notifications = append(n.notifications, notification)
+	return nil
+}
+
+func TestHoldExpiryNotice_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	const holdTTL = 10 * time.Minute
+	const lead = 2 * time.Minute
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+
+	clock := time.Now().UTC()
+	notifier := &recordingNotifier{}
+	holdService := app.NewHoldService(
+		infrastructure.NewPostgresHoldRepository(dbClient),
+		eventRepo,
+		ticketAvailabilityRepo,
+		infrastructure.NewPostgresBookingRepository(dbClient),
+		dbClient,
+		logger,
+		holdTTL,
+		app.WithHoldExpiryNotifier(notifier, lead),
+		app.WithHoldClock(domain.NewClock(func() time.Time { return clock }, 0)),
+	)
+
+	ctx := context.Background()
+	event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name:     "Jazz Night",
+		Date:     time.Now().Add(7 * 24 * time.Hour),
+		Location: "Blue Room",
+		Tickets:  20,
+	})
+	require.NoError(t, err)
+
+	hold, err := holdService.CreateHold(ctx, app.CreateHoldRequest{EventID: event.ID, UserID: uuid.New(), Tickets: 2})
+	require.NoError(t, err)
+
+	notified, err := holdService.NotifyExpiringHolds(ctx, 100)
+	require.NoError(t, err)
+	assert.Zero(t, notified, "hold is not yet within the notice window")
+
+	clock = clock.Add(holdTTL - lead + time.Second)
+	for range 3 {
+		_, err := holdService.NotifyExpiringHolds(ctx, 100)
+		require.NoError(t, err)
+	}
+
+	require.Len(t, notifier.notifications, 1)
+	assert.Equal(t, hold.UserID, notifier.notifications[0].UserID)
+	assert.Equal(t, hold.ID.String(), notifier.notifications[0].Attributes["hold_id"])
+
+	stored, err := holdService.GetHold(ctx, hold.ID)
+	require.NoError(t, err)
+	assert.False(t, stored.ExpiryNotifiedAt.IsZero())
+}
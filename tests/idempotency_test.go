@@ -0,0 +1,213 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookingIdempotency_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	newBookingService := func(opts ...app.BookingServiceOption) *app.BookingService {
+		return app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger, opts...)
+	}
+	bookingService := newBookingService()
+
+	ctx := context.Background()
+
+	createEvent := func(t *testing.T) *domain.Event {
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:     "Idempotent Gig",
+			Date:     time.Now().Add(30 * 24 * time.Hour),
+			Location: "Club",
+			Tickets:  10,
+		})
+		require.NoError(t, err)
+		return event
+	}
+
+	availableTickets := func(t *testing.T, eventID uuid.UUID) int {
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, eventID)
+		require.NoError(t, err)
+		return availability.AvailableTickets
+	}
+
+	t.Run("replays the original booking, also from a restarted service", func(t *testing.T) {
+		event := createEvent(t)
+		req := app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 2, IdempotencyKey: uuid.NewString()}
+
+		first, err := bookingService.CreateBooking(ctx, req)
+		require.NoError(t, err)
+
+		second, err := bookingService.CreateBooking(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, second.ID)
+
+		// A fresh service holds no in-memory state, so the replay must come from the database
+		restarted, err := newBookingService().CreateBooking(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, restarted.ID)
+
+		assert.Equal(t, 8, availableTickets(t, event.ID))
+	})
+
+	t.Run("rejects a key reused for a different request", func(t *testing.T) {
+		event := createEvent(t)
+		req := app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 2, IdempotencyKey: uuid.NewString()}
+
+		_, err := bookingService.CreateBooking(ctx, req)
+		require.NoError(t, err)
+
+		req.TicketsBooked = 3
+		_, err = bookingService.CreateBooking(ctx, req)
+		assert.ErrorIs(t, err, domain.ErrIdempotencyKeyReused)
+		assert.Equal(t, 8, availableTickets(t, event.ID))
+	})
+
+	t.Run("does not store the key of a failed request", func(t *testing.T) {
+		event := createEvent(t)
+		req := app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 11, IdempotencyKey: uuid.NewString()}
+
+		_, err := bookingService.CreateBooking(ctx, req)
+		require.ErrorIs(t, err, domain.ErrExceedsCapacity)
+
+		req.TicketsBooked = 1
+		_, err = bookingService.CreateBooking(ctx, req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("concurrent requests with the same new key book once", func(t *testing.T) {
+		event := createEvent(t)
+		req := app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 3, IdempotencyKey: uuid.NewString()}
+
+		type result struct {
+			booking *domain.Booking
+			err     error
+		}
+		results := make(chan result, 2)
+		start := make(chan struct{})
+		for range 2 {
+			go func() {
+				<-start
+				booking, err := bookingService.CreateBooking(ctx, req)
+				results <- result{booking, err}
+			}()
+		}
+		close(start)
+
+		var bookingIDs []uuid.UUID
+		for range 2 {
+			r := <-results
+			if r.err != nil {
+				// The loser either replays the winner or aborts on the key conflict and may retry
+				assert.True(t, errors.Is(r.err, infrastructure.ErrSerializationFailure), "unexpected error: %v", r.err)
+				continue
+			}
+			bookingIDs = append(bookingIDs, r.booking.ID)
+		}
+		require.NotEmpty(t, bookingIDs)
+		for _, id := range bookingIDs {
+			assert.Equal(t, bookingIDs[0], id)
+		}
+
+		assert.Equal(t, 7, availableTickets(t, event.ID), "tickets are reserved only once")
+
+		retried, err := bookingService.CreateBooking(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, bookingIDs[0], retried.ID)
+	})
+
+	t.Run("expired key is claimed again before cleanup runs", func(t *testing.T) {
+		event := createEvent(t)
+		shortLived := newBookingService(app.WithIdempotencyKeyTTL(time.Millisecond))
+		req := app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 1, IdempotencyKey: uuid.NewString()}
+
+		first, err := shortLived.CreateBooking(ctx, req)
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+
+		second, err := shortLived.CreateBooking(ctx, req)
+		require.NoError(t, err)
+		assert.NotEqual(t, first.ID, second.ID)
+	})
+
+	t.Run("cleanup deletes expired keys so they can be reused", func(t *testing.T) {
+		event := createEvent(t)
+		shortLived := newBookingService(app.WithIdempotencyKeyTTL(time.Millisecond))
+		req := app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 1, IdempotencyKey: uuid.NewString()}
+
+		first, err := shortLived.CreateBooking(ctx, req)
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+
+		deleted, err := shortLived.DeleteExpiredIdempotencyKeys(ctx, 100)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, deleted, 1)
+
+		_, err = idempotencyRepo.FindByKeyWithExecutor(ctx, dbClient, req.IdempotencyKey)
+		assert.ErrorIs(t, err, domain.ErrIdempotencyKeyNotFound)
+
+		second, err := shortLived.CreateBooking(ctx, req)
+		require.NoError(t, err)
+		assert.NotEqual(t, first.ID, second.ID)
+	})
+
+	t.Run("request dedup collapses identical rapid requests without a key", func(t *testing.T) {
+		event := createEvent(t)
+		deduplicating := newBookingService(app.WithRequestDedup(5 * time.Second))
+		req := app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 2}
+
+		first, err := deduplicating.CreateBooking(ctx, req)
+		require.NoError(t, err)
+		second, err := deduplicating.CreateBooking(ctx, req)
+		require.NoError(t, err)
+
+		assert.Equal(t, first.ID, second.ID)
+		assert.Equal(t, 8, availableTickets(t, event.ID), "only one booking took tickets")
+
+		req.TicketsBooked = 1
+		different, err := deduplicating.CreateBooking(ctx, req)
+		require.NoError(t, err)
+		assert.NotEqual(t, first.ID, different.ID, "a different request is not deduplicated")
+	})
+
+	t.Run("request dedup is off by default and ends with its window", func(t *testing.T) {
+		event := createEvent(t)
+		req := app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 1}
+
+		first, err := bookingService.CreateBooking(ctx, req)
+		require.NoError(t, err)
+		second, err := bookingService.CreateBooking(ctx, req)
+		require.NoError(t, err)
+		assert.NotEqual(t, first.ID, second.ID)
+
+		deduplicating := newBookingService(app.WithRequestDedup(50 * time.Millisecond))
+		third, err := deduplicating.CreateBooking(ctx, req)
+		require.NoError(t, err)
+		time.Sleep(100 * time.Millisecond)
+		fourth, err := deduplicating.CreateBooking(ctx, req)
+		require.NoError(t, err)
+		assert.NotEqual(t, third.ID, fourth.ID)
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"testing"
 	"time"
 
@@ -19,8 +21,27 @@ import (
 	"github.com/testcontainers/testcontainers-go/wait"
 )
 
-func setupTestDB(t *testing.T) (*sql.DB, func()) {
-	t.Helper()
+func setupTestDB(tb testing.TB) (*sql.DB, func()) {
+	tb.Helper()
+
+	config, terminate := startPostgres(tb)
+
+	db, err := infrastructure.NewPostgresDB(config)
+	require.NoError(tb, err)
+
+	runMigrations(tb, db)
+
+	cleanup := func() {
+		db.Close()
+		terminate()
+	}
+
+	return db, cleanup
+}
+
+// startPostgres starts an empty Postgres container and returns its connection config
+func startPostgres(tb testing.TB) (infrastructure.Config, func()) {
+	tb.Helper()
 
 	ctx := context.Background()
 
@@ -41,13 +62,13 @@ func setupTestDB(t *testing.T) (*sql.DB, func()) {
 		ContainerRequest: req,
 		Started:          true,
 	})
-	require.NoError(t, err)
+	require.NoError(tb, err)
 
 	host, err := postgres.Host(ctx)
-	require.NoError(t, err)
+	require.NoError(tb, err)
 
 	port, err := postgres.MappedPort(ctx, "5432")
-	require.NoError(t, err)
+	require.NoError(tb, err)
 
 	config := infrastructure.Config{
 		Host:     host,
@@ -58,28 +79,24 @@ func setupTestDB(t *testing.T) (*sql.DB, func()) {
 		SSLMode:  "disable",
 	}
 
-	db, err := infrastructure.NewPostgresDB(config)
-	require.NoError(t, err)
-
-	// Run migrations
-	migrationSQL1, err := os.ReadFile("../internal/infrastructure/migrations/001_create_tables.sql")
-	require.NoError(t, err)
+	return config, func() { postgres.Terminate(ctx) }
+}
 
-	_, err = db.ExecContext(ctx, string(migrationSQL1))
-	require.NoError(t, err)
+// runMigrations applies every migration file in lexical order, mirroring `make migrate`
+func runMigrations(tb testing.TB, db *sql.DB) {
+	tb.Helper()
 
-	migrationSQL2, err := os.ReadFile("../internal/infrastructure/migrations/002_separate_ticket_availability.sql")
-	require.NoError(t, err)
+	files, err := filepath.Glob("../internal/infrastructure/migrations/*.sql")
+	require.NoError(tb, err)
+	sort.Strings(files)
 
-	_, err = db.ExecContext(ctx, string(migrationSQL2))
-	require.NoError(t, err)
+	for _, file := range files {
+		migrationSQL, err := os.ReadFile(file)
+		require.NoError(tb, err)
 
-	cleanup := func() {
-		db.Close()
-		postgres.Terminate(ctx)
+		_, err = db.ExecContext(context.Background(), string(migrationSQL))
+		require.NoError(tb, err, "migration %s failed", filepath.Base(file))
 	}
-
-	return db, cleanup
 }
 
 func TestEventService_Integration(t *testing.T) {
@@ -90,7 +107,8 @@ func TestEventService_Integration(t *testing.T) {
 	dbClient := infrastructure.NewDBClientAdapter(db)
 	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
 	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
-	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, dbClient, logger)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
 
 	ctx := context.Background()
 
@@ -121,6 +139,66 @@ func TestEventService_Integration(t *testing.T) {
 		assert.NotEmpty(t, events)
 	})
 
+	t.Run("cancels event idempotently", func(t *testing.T) {
+		created, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:     "Lakeside Marathon",
+			Date:     time.Now().Add(40 * 24 * time.Hour),
+			Location: "North Shore",
+			Tickets:  500,
+		})
+		require.NoError(t, err)
+
+		cancellation, err := eventService.CancelEvent(ctx, created.ID)
+		require.NoError(t, err)
+		first := cancellation.Event
+		assert.Equal(t, domain.EventStatusCancelled, first.Status)
+		assert.False(t, first.CancelledAt.IsZero())
+
+		cancellation, err = eventService.CancelEvent(ctx, created.ID)
+		require.NoError(t, err)
+		second := cancellation.Event
+		assert.Equal(t, domain.EventStatusCancelled, second.Status)
+		assert.WithinDuration(t, first.CancelledAt, second.CancelledAt, time.Millisecond, "repeated cancellation must not touch the event")
+	})
+
+	t.Run("serializes concurrent cancellations", func(t *testing.T) {
+		created, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:     "Food Truck Fair",
+			Date:     time.Now().Add(12 * 24 * time.Hour),
+			Location: "Market Square",
+			Tickets:  150,
+		})
+		require.NoError(t, err)
+
+		results := make(chan *domain.Event, 5)
+		errs := make(chan error, 5)
+		for i := 0; i < 5; i++ {
+			go func() {
+				cancellation, err := eventService.CancelEvent(ctx, created.ID)
+				errs <- err
+				if err != nil {
+					results <- nil
+					return
+				}
+				results <- cancellation.Event
+			}()
+		}
+
+		var cancelledAt time.Time
+		for i := 0; i < 5; i++ {
+			require.NoError(t, <-errs)
+			event := <-results
+			if cancelledAt.IsZero() {
+				cancelledAt = event.CancelledAt
+			}
+			assert.WithinDuration(t, cancelledAt, event.CancelledAt, time.Millisecond)
+		}
+
+		stored, err := eventService.GetEvent(ctx, created.ID)
+		require.NoError(t, err)
+		assert.WithinDuration(t, cancelledAt, stored.CancelledAt, time.Millisecond)
+	})
+
 	t.Run("returns error for non-existent event", func(t *testing.T) {
 		nonExistentID := uuid.New()
 		_, err := eventService.GetEvent(ctx, nonExistentID)
@@ -138,8 +216,11 @@ func TestBookingService_Integration(t *testing.T) {
 	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
 	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
 	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
-	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, dbClient, logger)
-	bookingService := app.NewBookingService(bookingRepo, ticketAvailabilityRepo, dbClient, logger)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
 
 	ctx := context.Background()
 
@@ -182,15 +263,90 @@ func TestBookingService_Integration(t *testing.T) {
 		})
 		require.NoError(t, err)
 
+		_, err = bookingService.CreateBooking(ctx, app.CreateBookingRequest{
+			EventID:       event.ID,
+			UserID:        uuid.New(),
+			TicketsBooked: 3,
+		})
+		require.NoError(t, err)
+
 		bookingReq := app.CreateBookingRequest{
 			EventID:       event.ID,
 			UserID:        uuid.New(),
-			TicketsBooked: 10,
+			TicketsBooked: 3,
 		}
 
 		_, err = bookingService.CreateBooking(ctx, bookingReq)
 		require.Error(t, err)
-		assert.ErrorIs(t, err, domain.ErrInsufficientTickets)
+		assert.ErrorIs(t, err, domain.ErrInsufficientTickets, "a shortage may clear once bookings are cancelled")
+	})
+
+	t.Run("returns error when booking more tickets than the event has", func(t *testing.T) {
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:     "Small Event",
+			Date:     time.Now().Add(7 * 24 * time.Hour),
+			Location: "Small Venue",
+			Tickets:  100,
+		})
+		require.NoError(t, err)
+
+		_, err = bookingService.CreateBooking(ctx, app.CreateBookingRequest{
+			EventID:       event.ID,
+			UserID:        uuid.New(),
+			TicketsBooked: 1000,
+		})
+		assert.ErrorIs(t, err, domain.ErrExceedsCapacity, "no amount of retrying can book more than the event's capacity")
+
+		var validationErr *domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 100, availability.AvailableTickets)
+	})
+
+	t.Run("returns error when booking within the minimum advance window", func(t *testing.T) {
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:       "Members Preview",
+			Date:       time.Now().Add(12 * time.Hour),
+			Location:   "Gallery",
+			Tickets:    30,
+			MinAdvance: 24 * time.Hour,
+		})
+		require.NoError(t, err)
+
+		_, err = bookingService.CreateBooking(ctx, app.CreateBookingRequest{
+			EventID:       event.ID,
+			UserID:        uuid.New(),
+			TicketsBooked: 2,
+		})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, domain.ErrBookingTooLate)
+
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 30, availability.AvailableTickets)
+	})
+
+	t.Run("returns error when booking a cancelled event", func(t *testing.T) {
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:     "Rooftop Party",
+			Date:     time.Now().Add(6 * 24 * time.Hour),
+			Location: "Skyline Hotel",
+			Tickets:  60,
+		})
+		require.NoError(t, err)
+
+		_, err = eventService.CancelEvent(ctx, event.ID)
+		require.NoError(t, err)
+
+		_, err = bookingService.CreateBooking(ctx, app.CreateBookingRequest{
+			EventID:       event.ID,
+			UserID:        uuid.New(),
+			TicketsBooked: 2,
+		})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, domain.ErrEventCancelled)
 	})
 
 	t.Run("retrieves booking by id", func(t *testing.T) {
@@ -258,6 +414,94 @@ func TestBookingService_Integration(t *testing.T) {
 	})
 }
 
+func TestOrganizerDashboard_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+
+	ctx := context.Background()
+	organizerID := uuid.New()
+
+	createEvent := func(name string, daysAhead, tickets int, organizer uuid.UUID) *domain.Event {
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:        name,
+			Date:        time.Now().Add(time.Duration(daysAhead) * 24 * time.Hour),
+			Location:    "Opera House",
+			Tickets:     tickets,
+			OrganizerID: organizer,
+		})
+		require.NoError(t, err)
+		return event
+	}
+
+	book := func(eventID uuid.UUID, tickets int) {
+		_, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{
+			EventID:       eventID,
+			UserID:        uuid.New(),
+			TicketsBooked: tickets,
+		})
+		require.NoError(t, err)
+	}
+
+	opening := createEvent("Season Opening", 3, 100, organizerID)
+	matinee := createEvent("Sunday Matinee", 5, 40, organizerID)
+	premiere := createEvent("Winter Premiere", 9, 60, organizerID)
+	otherOrganizers := createEvent("Charity Gala", 4, 80, uuid.New())
+
+	book(opening.ID, 5)
+	book(opening.ID, 3)
+	book(matinee.ID, 40)
+	book(otherOrganizers.ID, 10)
+
+	t.Run("aggregates bookings per organizer event", func(t *testing.T) {
+		summaries, total, err := eventService.GetOrganizerDashboard(ctx, organizerID, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, summaries, 3)
+		assert.Equal(t, 3, total)
+
+		assert.Equal(t, opening.ID, summaries[0].EventID)
+		assert.Equal(t, 2, summaries[0].BookingsCount)
+		assert.Equal(t, 8, summaries[0].TicketsSold)
+		assert.Equal(t, 92, summaries[0].AvailableTickets)
+
+		assert.Equal(t, matinee.ID, summaries[1].EventID)
+		assert.Equal(t, 1, summaries[1].BookingsCount)
+		assert.Equal(t, 40, summaries[1].TicketsSold)
+		assert.Equal(t, 0, summaries[1].AvailableTickets)
+
+		assert.Equal(t, premiere.ID, summaries[2].EventID)
+		assert.Equal(t, 0, summaries[2].BookingsCount)
+		assert.Equal(t, 0, summaries[2].TicketsSold)
+		assert.Equal(t, 60, summaries[2].AvailableTickets)
+	})
+
+	t.Run("paginates organizer events", func(t *testing.T) {
+		summaries, total, err := eventService.GetOrganizerDashboard(ctx, organizerID, 2, 1)
+		require.NoError(t, err)
+		require.Len(t, summaries, 2)
+		assert.Equal(t, 3, total, "the total covers all pages")
+		assert.Equal(t, matinee.ID, summaries[0].EventID)
+		assert.Equal(t, premiere.ID, summaries[1].EventID)
+	})
+
+	t.Run("returns empty dashboard for organizer without events", func(t *testing.T) {
+		summaries, total, err := eventService.GetOrganizerDashboard(ctx, uuid.New(), 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, summaries)
+		assert.Zero(t, total)
+	})
+}
+
 func TestHTTPEndpoints_Integration(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -267,8 +511,11 @@ func TestHTTPEndpoints_Integration(t *testing.T) {
 	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
 	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
 	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
-	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, dbClient, logger)
-	bookingService := app.NewBookingService(bookingRepo, ticketAvailabilityRepo, dbClient, logger)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
 
 	ctx := context.Background()
 
@@ -321,8 +568,11 @@ func BenchmarkCreateBooking(b *testing.B) {
 	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
 	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
 	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
-	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, dbClient, logger)
-	bookingService := app.NewBookingService(bookingRepo, ticketAvailabilityRepo, dbClient, logger)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
 
 	ctx := context.Background()
 
@@ -382,18 +632,7 @@ func setupBenchDB(b *testing.B) (*sql.DB, func()) {
 	db, err := sql.Open("postgres", dsn)
 	require.NoError(b, err)
 
-	// Run migrations
-	migrationSQL1, err := os.ReadFile("../internal/infrastructure/migrations/001_create_tables.sql")
-	require.NoError(b, err)
-
-	_, err = db.ExecContext(ctx, string(migrationSQL1))
-	require.NoError(b, err)
-
-	migrationSQL2, err := os.ReadFile("../internal/infrastructure/migrations/002_separate_ticket_availability.sql")
-	require.NoError(b, err)
-
-	_, err = db.ExecContext(ctx, string(migrationSQL2))
-	require.NoError(b, err)
+	runMigrations(b, db)
 
 	cleanup := func() {
 		db.Close()
@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLottery_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	lotteryRepo := infrastructure.NewPostgresLotteryRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger,
+		transport.WithLottery(app.NewLotteryService(lotteryRepo, eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger)))
+
+	ctx := context.Background()
+	post := func(t *testing.T, path string, body map[string]any) *httptest.ResponseRecorder {
+		t.Helper()
+		raw, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(raw))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	// oversubscribed creates an event with 10 tickets and 8 entrants asking for 3 tickets each
+	oversubscribed := func(t *testing.T) (*domain.Event, []uuid.UUID) {
+		t.Helper()
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:     "Final",
+			Date:     time.Now().Add(30 * 24 * time.Hour),
+			Location: "Stadium",
+			Tickets:  10,
+			Lottery:  true,
+		})
+		require.NoError(t, err)
+
+		users := make([]uuid.UUID, 8)
+		for i := range users {
+			users[i] = uuid.New()
+			rec := post(t, "/events/"+event.ID.String()+"/lottery/register", map[string]any{"user_id": users[i].String(), "tickets": 3})
+			require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		}
+		return event, users
+	}
+	draw := func(t *testing.T, event *domain.Event, seed int64) transport.LotteryDrawResponse {
+		t.Helper()
+		rec := post(t, "/admin/events/"+event.ID.String()+"/lottery/draw", map[string]any{"seed": seed})
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var response transport.LotteryDrawResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response
+	}
+	winningUsers := func(response transport.LotteryDrawResponse) []string {
+		users := make([]string, len(response.Winners))
+		for i, winner := range response.Winners {
+			users[i] = winner.UserID
+		}
+		return users
+	}
+
+	t.Run("winners never exceed capacity and are booked", func(t *testing.T) {
+		event, users := oversubscribed(t)
+
+		response := draw(t, event, 42)
+		assert.Equal(t, int64(42), response.Seed)
+		assert.Len(t, response.Winners, 3)
+		assert.Len(t, response.Losers, 5)
+		assert.Equal(t, 9, response.TicketsBooked)
+
+		for _, winner := range response.Winners {
+			assert.Equal(t, string(domain.LotteryEntryWon), winner.Status)
+			bookingID, err := uuid.Parse(winner.BookingID)
+			require.NoError(t, err)
+			booking, err := bookingRepo.FindByID(ctx, bookingID)
+			require.NoError(t, err)
+			assert.Equal(t, winner.UserID, booking.UserID.String())
+			assert.Equal(t, 3, booking.TicketsBooked)
+		}
+		for _, loser := range response.Losers {
+			assert.Equal(t, string(domain.LotteryEntryLost), loser.Status)
+			assert.Empty(t, loser.BookingID)
+		}
+
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, availability.AvailableTickets)
+
+		t.Run("an event is drawn once", func(t *testing.T) {
+			rec := post(t, "/admin/events/"+event.ID.String()+"/lottery/draw", map[string]any{"seed": 42})
+			assert.Equal(t, http.StatusConflict, rec.Code)
+		})
+
+		t.Run("registration closes after the draw", func(t *testing.T) {
+			rec := post(t, "/events/"+event.ID.String()+"/lottery/register", map[string]any{"user_id": uuid.New().String(), "tickets": 1})
+			assert.Equal(t, http.StatusConflict, rec.Code)
+		})
+
+		t.Run("a user registers once", func(t *testing.T) {
+			rec := post(t, "/events/"+event.ID.String()+"/lottery/register", map[string]any{"user_id": users[0].String(), "tickets": 1})
+			assert.Equal(t, http.StatusConflict, rec.Code)
+		})
+
+		t.Run("tickets are not sold outside the draw", func(t *testing.T) {
+			rec := post(t, "/bookings", map[string]any{"event_id": event.ID.String(), "user_id": uuid.New().String(), "tickets_booked": 1})
+			assert.Equal(t, http.StatusConflict, rec.Code, rec.Body.String())
+			assert.Contains(t, rec.Body.String(), domain.ErrLotteryOnly.Message)
+
+			rec = post(t, "/events/"+event.ID.String()+"/holds", map[string]any{"user_id": uuid.New().String(), "tickets": 1})
+			assert.Equal(t, http.StatusConflict, rec.Code, rec.Body.String())
+		})
+	})
+
+	t.Run("events without a lottery take no entries", func(t *testing.T) {
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:     "Friendly",
+			Date:     time.Now().Add(30 * 24 * time.Hour),
+			Location: "Stadium",
+			Tickets:  10,
+		})
+		require.NoError(t, err)
+
+		rec := post(t, "/events/"+event.ID.String()+"/lottery/register", map[string]any{"user_id": uuid.New().String(), "tickets": 1})
+		assert.Equal(t, http.StatusConflict, rec.Code, rec.Body.String())
+		rec = post(t, "/admin/events/"+event.ID.String()+"/lottery/draw", map[string]any{})
+		assert.Equal(t, http.StatusConflict, rec.Code, rec.Body.String())
+	})
+
+	t.Run("the same seed draws the same winners", func(t *testing.T) {
+		first, firstUsers := oversubscribed(t)
+		second, secondUsers := oversubscribed(t)
+		// Entries are drawn in registration order, so map each event's users by their position
+		position := make(map[string]int, len(firstUsers))
+		for i, user := range firstUsers {
+			position[user.String()] = i
+		}
+		for i, user := range secondUsers {
+			position[user.String()] = i
+		}
+
+		positions := func(users []string) []int {
+			result := make([]int, len(users))
+			for i, user := range users {
+				result[i] = position[user]
+			}
+			return result
+		}
+		assert.Equal(t, positions(winningUsers(draw(t, first, 7))), positions(winningUsers(draw(t, second, 7))))
+	})
+}
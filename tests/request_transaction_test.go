@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionMiddleware_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewRequestScopedDBClient(infrastructure.NewDBClientAdapter(db))
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+
+	ctx := context.Background()
+	// The handler creates two events, each in its own service transaction, then answers with status
+	e := echo.New()
+	e.POST("/events/pair/:status", func(c echo.Context) error {
+		for _, name := range []string{"Pair One", "Pair Two"} {
+			_, err := eventService.CreateEvent(c.Request().Context(), app.CreateEventRequest{
+				Name:     name + " " + c.Param("status"),
+				Date:     time.Now().Add(7 * 24 * time.Hour),
+				Location: "Hall",
+				Tickets:  10,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if c.Param("status") == "error" {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed after writing")
+		}
+		if c.Param("status") == "conflict" {
+			return c.JSON(http.StatusConflict, map[string]string{"error": "conflict"})
+		}
+		return c.NoContent(http.StatusCreated)
+	}, transport.TransactionMiddleware(dbClient, logger))
+
+	post := func(t *testing.T, status string) *httptest.ResponseRecorder {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/events/pair/"+status, nil))
+		return rec
+	}
+	count := func(t *testing.T, status string) int {
+		t.Helper()
+		var n int
+		require.NoError(t, db.QueryRowContext(ctx, "SELECT COUNT(*) FROM events WHERE name LIKE '% ' || $1", status).Scan(&n))
+		return n
+	}
+
+	t.Run("a handler error rolls back its writes", func(t *testing.T) {
+		rec := post(t, "error")
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Zero(t, count(t, "error"), "neither event is kept")
+
+		var availability int
+		require.NoError(t, db.QueryRowContext(ctx, "SELECT COUNT(*) FROM ticket_availability").Scan(&availability))
+		assert.Zero(t, availability)
+	})
+
+	t.Run("an error response rolls back its writes", func(t *testing.T) {
+		rec := post(t, "conflict")
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.JSONEq(t, `{"error":"conflict"}`, rec.Body.String())
+		assert.Zero(t, count(t, "conflict"))
+	})
+
+	t.Run("a successful handler commits every write", func(t *testing.T) {
+		rec := post(t, "created")
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, 2, count(t, "created"))
+	})
+
+	t.Run("queries outside a transactional route are not affected", func(t *testing.T) {
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{Name: "Solo", Date: time.Now().Add(24 * time.Hour), Location: "Hall", Tickets: 5})
+		require.NoError(t, err)
+		found, err := eventRepo.FindByID(ctx, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.EventStatusActive, found.Status)
+	})
+}
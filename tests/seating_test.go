@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservedSeating_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+
+	ctx := context.Background()
+
+	createSeatedEvent := func(t *testing.T, seats []string) *domain.Event {
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:     "Chamber Recital",
+			Date:     time.Now().Add(20 * 24 * time.Hour),
+			Location: "Small Hall",
+			Tickets:  len(seats),
+			Seats:    seats,
+		})
+		require.NoError(t, err)
+		require.True(t, event.Seated)
+		return event
+	}
+
+	t.Run("books selected seats and derives availability from free seats", func(t *testing.T) {
+		event := createSeatedEvent(t, []string{"A1", "A2", "A3"})
+
+		booking, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{
+			EventID: event.ID, UserID: uuid.New(), TicketsBooked: 2, Seats: []string{"A1", "A3"},
+		})
+		require.NoError(t, err)
+
+		stored, err := bookingService.GetBooking(ctx, booking.ID)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"A1", "A3"}, stored.SeatLabels)
+
+		seats, err := eventService.GetSeatMap(ctx, event.ID)
+		require.NoError(t, err)
+		free := 0
+		for _, seat := range seats {
+			if seat.Status == domain.SeatStatusFree {
+				free++
+			}
+		}
+		assert.Equal(t, 1, free)
+
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, free, availability.AvailableTickets)
+	})
+
+	t.Run("rejects taken and unknown seats", func(t *testing.T) {
+		event := createSeatedEvent(t, []string{"B1", "B2"})
+
+		_, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{
+			EventID: event.ID, UserID: uuid.New(), TicketsBooked: 1, Seats: []string{"B1"},
+		})
+		require.NoError(t, err)
+
+		_, err = bookingService.CreateBooking(ctx, app.CreateBookingRequest{
+			EventID: event.ID, UserID: uuid.New(), TicketsBooked: 2, Seats: []string{"B1", "B2"},
+		})
+		assert.ErrorIs(t, err, domain.ErrSeatTaken)
+
+		_, err = bookingService.CreateBooking(ctx, app.CreateBookingRequest{
+			EventID: event.ID, UserID: uuid.New(), TicketsBooked: 1, Seats: []string{"Z9"},
+		})
+		assert.ErrorIs(t, err, domain.ErrUnknownSeat)
+
+		_, err = bookingService.CreateBooking(ctx, app.CreateBookingRequest{
+			EventID: event.ID, UserID: uuid.New(), TicketsBooked: 1,
+		})
+		assert.ErrorIs(t, err, domain.ErrSeatSelectionRequired)
+	})
+
+	t.Run("general admission events reject seat selection", func(t *testing.T) {
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:     "Open Air Cinema",
+			Date:     time.Now().Add(20 * 24 * time.Hour),
+			Location: "Park",
+			Tickets:  50,
+		})
+		require.NoError(t, err)
+
+		_, err = bookingService.CreateBooking(ctx, app.CreateBookingRequest{
+			EventID: event.ID, UserID: uuid.New(), TicketsBooked: 1, Seats: []string{"A1"},
+		})
+		assert.ErrorIs(t, err, domain.ErrSeatingNotSupported)
+
+		_, err = bookingService.CreateBooking(ctx, app.CreateBookingRequest{
+			EventID: event.ID, UserID: uuid.New(), TicketsBooked: 2,
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("concurrent bookings of overlapping seats never double-book", func(t *testing.T) {
+		event := createSeatedEvent(t, []string{"C1", "C2", "C3", "C4"})
+
+		// Every selection shares C2 with every other, so at most one can succeed
+		selections := [][]string{{"C1", "C2"}, {"C2", "C3"}, {"C2", "C4"}, {"C2"}, {"C4", "C2"}}
+		errs := make(chan error, len(selections))
+		for _, seats := range selections {
+			go func() {
+				_, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{
+					EventID: event.ID, UserID: uuid.New(), TicketsBooked: len(seats), Seats: seats,
+				})
+				errs <- err
+			}()
+		}
+
+		succeeded := 0
+		for range selections {
+			if err := <-errs; err == nil {
+				succeeded++
+			}
+		}
+		assert.Equal(t, 1, succeeded)
+
+		seats, err := eventService.GetSeatMap(ctx, event.ID)
+		require.NoError(t, err)
+		var reservedBy uuid.UUID
+		for _, seat := range seats {
+			if seat.Label == "C2" {
+				reservedBy = seat.BookingID
+			}
+		}
+		assert.NotEqual(t, uuid.Nil, reservedBy)
+		for _, seat := range seats {
+			if seat.Status == domain.SeatStatusReserved {
+				assert.Equal(t, reservedBy, seat.BookingID, "all reserved seats belong to the single winning booking")
+			}
+		}
+	})
+}
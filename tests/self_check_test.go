@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfCheck_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	client := infrastructure.NewInstrumentedPostgresClient(db)
+	checks := []infrastructure.SelfCheck{
+		infrastructure.DatabaseCheck(client),
+		infrastructure.SchemaCheck(client),
+	}
+
+	require.NoError(t, infrastructure.RunSelfChecks(ctx, checks...), "a fully migrated database passes")
+
+	_, err := db.ExecContext(ctx, "DROP TABLE discount_codes")
+	require.NoError(t, err)
+
+	err = infrastructure.RunSelfChecks(ctx, checks...)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "schema: table discount_codes is missing")
+	assert.NotContains(t, err.Error(), "database:", "the database itself is still reachable")
+}
+
+func TestSchemaDriftDetector_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	detector := infrastructure.NewSchemaDriftDetector(infrastructure.NewInstrumentedPostgresClient(db), logger)
+
+	drifted, err := detector.Check(ctx)
+	require.NoError(t, err)
+	assert.False(t, drifted, "a fully migrated database has not drifted")
+	assert.Equal(t, 0.0, testutil.ToFloat64(infrastructure.SchemaDrift))
+
+	// Manual DDL while the service runs, like a migration rolled back by hand
+	_, err = db.ExecContext(ctx, "ALTER TABLE bookings DROP COLUMN allocation_id")
+	require.NoError(t, err)
+
+	drifted, err = detector.Check(ctx)
+	require.NoError(t, err)
+	assert.True(t, drifted)
+	assert.Equal(t, 1.0, testutil.ToFloat64(infrastructure.SchemaDrift))
+
+	_, err = db.ExecContext(ctx, "ALTER TABLE bookings ADD COLUMN allocation_id UUID REFERENCES allocations(id)")
+	require.NoError(t, err)
+
+	drifted, err = detector.Check(ctx)
+	require.NoError(t, err)
+	assert.False(t, drifted, "drift clears once the schema is repaired")
+	assert.Equal(t, 0.0, testutil.ToFloat64(infrastructure.SchemaDrift))
+}
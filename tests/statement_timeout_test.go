@@ -0,0 +1,52 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatementTimeout_Integration(t *testing.T) {
+	config, terminate := startPostgres(t)
+	defer terminate()
+
+	config.StatementTimeout = 200 * time.Millisecond
+	db, err := infrastructure.NewPostgresDB(config)
+	require.NoError(t, err)
+	defer db.Close()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+
+	ctx := context.Background()
+	slowQuery := func(tx domain.Transaction) error {
+		_, err := tx.ExecContext(ctx, "SELECT pg_sleep(0.5)")
+		return infrastructure.ClassifyDBError(err)
+	}
+
+	t.Run("cancels statements running past the connection timeout", func(t *testing.T) {
+		_, err := dbClient.ExecContext(ctx, "SELECT pg_sleep(0.5)")
+		assert.ErrorIs(t, infrastructure.ClassifyDBError(err), infrastructure.ErrQueryCanceled)
+
+		err = app.WithTransaction(ctx, dbClient, zerolog.Nop(), nil, "slow", slowQuery)
+		assert.ErrorIs(t, err, infrastructure.ErrQueryCanceled)
+	})
+
+	t.Run("lets an overridden transaction run longer", func(t *testing.T) {
+		longCtx := infrastructure.WithStatementTimeout(ctx, 5*time.Second)
+		err := app.WithTransaction(longCtx, dbClient, zerolog.Nop(), nil, "slow_admin", func(tx domain.Transaction) error {
+			_, err := tx.ExecContext(longCtx, "SELECT pg_sleep(0.5)")
+			return err
+		})
+		require.NoError(t, err)
+
+		_, err = dbClient.ExecContext(ctx, "SELECT pg_sleep(0.5)")
+		assert.ErrorIs(t, infrastructure.ClassifyDBError(err), infrastructure.ErrQueryCanceled,
+			"the override does not leak onto the pooled connection")
+	})
+}
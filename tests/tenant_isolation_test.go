@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantIsolation_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	acme := infrastructure.WithTenant(context.Background(), "acme")
+	globex := infrastructure.WithTenant(context.Background(), "globex")
+
+	event, err := eventService.CreateEvent(acme, app.CreateEventRequest{
+		Name:     "Acme Offsite",
+		Date:     time.Now().Add(7 * 24 * time.Hour),
+		Location: "Hall",
+		Tickets:  20,
+	})
+	require.NoError(t, err)
+	booking, err := bookingService.CreateBooking(acme, app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 2})
+	require.NoError(t, err)
+	hold, err := holdService.CreateHold(acme, app.CreateHoldRequest{EventID: event.ID, UserID: uuid.New(), Tickets: 1})
+	require.NoError(t, err)
+
+	request := func(method, path, tenant string, body ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(strings.Join(body, "")))
+		req.Header.Set("Content-Type", "application/json")
+		if tenant != "" {
+			req.Header.Set("X-Tenant-ID", tenant)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("reads are scoped to the tenant", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, request(http.MethodGet, "/events/"+event.ID.String(), "acme").Code)
+		assert.Equal(t, http.StatusOK, request(http.MethodGet, "/bookings/"+booking.ID.String(), "acme").Code)
+
+		for _, tenant := range []string{"globex", ""} {
+			assert.Equal(t, http.StatusNotFound, request(http.MethodGet, "/events/"+event.ID.String(), tenant).Code, tenant)
+			assert.Equal(t, http.StatusNotFound, request(http.MethodGet, "/bookings/"+booking.ID.String(), tenant).Code, tenant)
+		}
+
+		rec := request(http.MethodGet, "/events", "globex")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var page transport.PagedResponse[transport.EventResponse]
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		assert.Zero(t, page.TotalCount, "listings only show the tenant's events")
+	})
+
+	t.Run("writes cannot reach another tenant's rows", func(t *testing.T) {
+		rec := request(http.MethodPost, "/events/"+event.ID.String()+"/cancel", "globex")
+		assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+
+		_, err := bookingService.CreateBooking(globex, app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: 1})
+		assert.ErrorIs(t, err, domain.ErrEventNotFound)
+
+		current, err := eventService.GetEvent(acme, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.EventStatusActive, current.Status)
+
+		availability, err := eventService.GetAvailability(acme, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 17, availability.AvailableTickets, "the rejected booking reserved nothing")
+	})
+
+	t.Run("availability and holds are scoped to the tenant", func(t *testing.T) {
+		availabilityPath := "/admin/events/" + event.ID.String() + "/availability"
+		check := fmt.Sprintf(`[{"event_id": %q, "tickets": 1}]`, event.ID)
+
+		assert.Equal(t, http.StatusOK, request(http.MethodGet, availabilityPath, "acme").Code)
+		assert.Equal(t, http.StatusOK, request(http.MethodGet, "/holds/"+hold.ID.String(), "acme").Code)
+		rec := request(http.MethodPost, "/availability/check", "acme", check)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.JSONEq(t, fmt.Sprintf(`{"items": [{"event_id": %q, "available": true, "remaining": 17}]}`, event.ID), rec.Body.String())
+
+		for _, tenant := range []string{"globex", ""} {
+			assert.Equal(t, http.StatusNotFound, request(http.MethodGet, availabilityPath, tenant).Code, tenant)
+			assert.Equal(t, http.StatusNotFound, request(http.MethodPatch, availabilityPath, tenant, `{"delta": -5}`).Code, tenant)
+			assert.Equal(t, http.StatusNotFound, request(http.MethodGet, "/holds/"+hold.ID.String(), tenant).Code, tenant)
+			assert.Equal(t, http.StatusNotFound, request(http.MethodPost, "/holds/"+hold.ID.String()+"/confirm", tenant).Code, tenant)
+
+			rec := request(http.MethodPost, "/availability/check", tenant, check)
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			assert.JSONEq(t, fmt.Sprintf(`{"items": [{"event_id": %q, "available": false, "remaining": 0}]}`, event.ID), rec.Body.String(),
+				"another tenant's event reads as unknown")
+		}
+
+		availability, err := eventService.GetAvailability(acme, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 17, availability.AvailableTickets, "no other tenant adjusted the availability")
+		current, err := holdService.GetHold(acme, hold.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.HoldStatusActive, current.Status, "no other tenant confirmed the hold")
+	})
+
+	t.Run("rejects malformed tenant IDs", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, request(http.MethodGet, "/events", "acme corp").Code)
+	})
+}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTicketAvailabilityRepository_RejectsSecondRowForEvent verifies the one-row-per-event
+// invariant of the TicketAvailability aggregate is enforced by the database
+func TestTicketAvailabilityRepository_RejectsSecondRowForEvent(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+
+	event, err := domain.NewEvent("Harbour Fireworks", "Old Port", time.Now().Add(48*time.Hour), 300)
+	require.NoError(t, err)
+	require.NoError(t, eventRepo.Create(ctx, event))
+
+	availability, err := domain.NewTicketAvailability(event.ID, 300)
+	require.NoError(t, err)
+	require.NoError(t, ticketAvailabilityRepo.Create(ctx, availability))
+
+	duplicate, err := domain.NewTicketAvailability(event.ID, 150)
+	require.NoError(t, err)
+
+	err = ticketAvailabilityRepo.Create(ctx, duplicate)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, domain.ErrAvailabilityExists)
+
+	stored, err := ticketAvailabilityRepo.FindByEventID(ctx, event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 300, stored.AvailableTickets)
+
+	duplicates, err := ticketAvailabilityRepo.FindDuplicateEventIDs(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, duplicates)
+}
+
+func TestTicketAvailabilityRepository_AdjustAvailableTickets(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+
+	event, err := domain.NewEvent("Chamber Recital", "Music School", time.Now().Add(72*time.Hour), 100)
+	require.NoError(t, err)
+	require.NoError(t, eventRepo.Create(ctx, event))
+
+	availability, err := domain.NewTicketAvailability(event.ID, 60)
+	require.NoError(t, err)
+	require.NoError(t, ticketAvailabilityRepo.Create(ctx, availability))
+
+	t.Run("applies a positive delta", func(t *testing.T) {
+		adjusted, err := ticketAvailabilityRepo.AdjustAvailableTickets(ctx, event.ID, 15, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 75, adjusted.AvailableTickets)
+	})
+
+	t.Run("refuses a negative delta that would underflow", func(t *testing.T) {
+		_, err := ticketAvailabilityRepo.AdjustAvailableTickets(ctx, event.ID, -76, 0)
+		assert.ErrorIs(t, err, domain.ErrAvailabilityUnderflow)
+
+		stored, err := ticketAvailabilityRepo.FindByEventID(ctx, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 75, stored.AvailableTickets)
+	})
+
+	t.Run("refuses a delta that would exceed the total", func(t *testing.T) {
+		_, err := ticketAvailabilityRepo.AdjustAvailableTickets(ctx, event.ID, 26, 0)
+		assert.ErrorIs(t, err, domain.ErrAvailabilityOverflow)
+
+		stored, err := ticketAvailabilityRepo.FindByEventID(ctx, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 75, stored.AvailableTickets)
+	})
+
+	t.Run("applies a negative delta down to zero", func(t *testing.T) {
+		adjusted, err := ticketAvailabilityRepo.AdjustAvailableTickets(ctx, event.ID, -75, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 0, adjusted.AvailableTickets)
+	})
+
+	t.Run("reports unknown events as not found", func(t *testing.T) {
+		_, err := ticketAvailabilityRepo.AdjustAvailableTickets(ctx, uuid.New(), 1, 0)
+		assert.ErrorIs(t, err, domain.ErrEventNotFound)
+	})
+}
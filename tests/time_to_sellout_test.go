@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func selloutCount(t *testing.T) uint64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == "booking_service_time_to_sellout_seconds" {
+			return family.GetMetric()[0].GetHistogram().GetSampleCount()
+		}
+	}
+	return 0
+}
+
+func TestTimeToSellout_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+
+	ctx := context.Background()
+	event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name:     "Small Gig",
+		Date:     time.Now().Add(7 * 24 * time.Hour),
+		Location: "Basement",
+		Tickets:  5,
+	})
+	require.NoError(t, err)
+
+	stored, err := eventRepo.FindByID(ctx, event.ID)
+	require.NoError(t, err)
+	assert.False(t, stored.CreatedAt.IsZero(), "the database sets the creation time")
+
+	book := func(tickets int) error {
+		_, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: uuid.New(), TicketsBooked: tickets})
+		return err
+	}
+	before := selloutCount(t)
+
+	require.NoError(t, book(3))
+	assert.Equal(t, before, selloutCount(t), "tickets are left")
+
+	require.NoError(t, book(2))
+	assert.Equal(t, before+1, selloutCount(t), "the booking taking the last tickets is observed")
+
+	assert.ErrorIs(t, book(1), domain.ErrInsufficientTickets)
+	assert.Equal(t, before+1, selloutCount(t), "bookings rejected after the sellout are not observed")
+}
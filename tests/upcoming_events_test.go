@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpcomingEvents_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	create := func(name string, offset time.Duration, tickets int) uuid.UUID {
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:     name,
+			Date:     time.Now().Add(offset),
+			Location: "Riverside",
+			Tickets:  tickets,
+			// Past events are backfilled so the listing can be checked to leave them out
+			AllowPastDate: true,
+		})
+		require.NoError(t, err)
+		return event.ID
+	}
+
+	create("Last Month", -30*24*time.Hour, 10)
+	create("Yesterday", -24*time.Hour, 10)
+	nextWeek := create("Next Week", 7*24*time.Hour, 10)
+	tomorrow := create("Tomorrow", 24*time.Hour, 10)
+	soldOut := create("Sold Out", 3*24*time.Hour, 2)
+	nextMonth := create("Next Month", 30*24*time.Hour, 10)
+
+	_, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: soldOut, UserID: uuid.New(), TicketsBooked: 2})
+	require.NoError(t, err)
+
+	get := func(query string) transport.PagedResponse[transport.EventResponse] {
+		req := httptest.NewRequest(http.MethodGet, "/events/upcoming"+query, nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var resp transport.PagedResponse[transport.EventResponse]
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp
+	}
+	ids := func(resp transport.PagedResponse[transport.EventResponse]) []string {
+		out := make([]string, 0, len(resp.Data))
+		for _, event := range resp.Data {
+			out = append(out, event.ID)
+		}
+		return out
+	}
+
+	t.Run("returns only future events ordered by date", func(t *testing.T) {
+		resp := get("")
+		assert.Equal(t, 10, resp.Limit)
+		assert.Equal(t, 4, resp.TotalCount)
+		assert.Equal(t, []string{tomorrow.String(), soldOut.String(), nextWeek.String(), nextMonth.String()}, ids(resp))
+	})
+
+	t.Run("limits the number of events", func(t *testing.T) {
+		resp := get("?limit=2")
+		assert.Equal(t, []string{tomorrow.String(), soldOut.String()}, ids(resp))
+		assert.Equal(t, 4, resp.TotalCount, "the total covers all pages")
+	})
+
+	t.Run("leaves out sold-out events when asked", func(t *testing.T) {
+		resp := get("?available=true")
+		assert.Equal(t, []string{tomorrow.String(), nextWeek.String(), nextMonth.String()}, ids(resp))
+		assert.Equal(t, 3, resp.TotalCount)
+	})
+
+	t.Run("caps the limit", func(t *testing.T) {
+		resp := get("?limit=1000")
+		assert.Equal(t, 100, resp.Limit)
+	})
+
+	t.Run("rejects an invalid available flag", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/events/upcoming?available=maybe", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserBookings_RefundEligibility_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	user := uuid.New()
+	book := func(userID uuid.UUID, name string, in time.Duration, priceCents int64) *domain.Booking {
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:       name,
+			Date:       time.Now().Add(in),
+			Location:   "Hall",
+			Tickets:    50,
+			PriceCents: priceCents,
+			Currency:   "EUR",
+		})
+		require.NoError(t, err)
+		booking, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: event.ID, UserID: userID, TicketsBooked: 2})
+		require.NoError(t, err)
+		return booking
+	}
+
+	// The default policy refunds in full beyond 7 days, half beyond 24 hours and nothing after
+	monthAway := book(user, "Month Away", 30*24*time.Hour, 2500)
+	daysAway := book(user, "Days Away", 3*24*time.Hour, 2500)
+	hoursAway := book(user, "Hours Away", 12*time.Hour, 2500)
+	free := book(user, "Free Talk", 30*24*time.Hour, 0)
+	book(uuid.New(), "Someone Else's Show", 30*24*time.Hour, 2500)
+
+	list := func(t *testing.T, query string) transport.PagedResponse[transport.UserBookingResponse] {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/"+user.String()+"/bookings"+query, nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var page transport.PagedResponse[transport.UserBookingResponse]
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		return page
+	}
+	byID := func(page transport.PagedResponse[transport.UserBookingResponse]) map[string]transport.UserBookingResponse {
+		bookings := make(map[string]transport.UserBookingResponse, len(page.Data))
+		for _, booking := range page.Data {
+			bookings[booking.ID] = booking
+		}
+		return bookings
+	}
+
+	t.Run("computes eligibility from the distance to each event", func(t *testing.T) {
+		page := list(t, "")
+		assert.Equal(t, 4, page.TotalCount, "other users' bookings are left out")
+		bookings := byID(page)
+
+		assert.True(t, bookings[monthAway.ID.String()].RefundEligible)
+		assert.Equal(t, int64(5000), bookings[monthAway.ID.String()].RefundAmount)
+
+		assert.True(t, bookings[daysAway.ID.String()].RefundEligible)
+		assert.Equal(t, int64(2500), bookings[daysAway.ID.String()].RefundAmount)
+
+		assert.False(t, bookings[hoursAway.ID.String()].RefundEligible)
+		assert.Zero(t, bookings[hoursAway.ID.String()].RefundAmount)
+
+		assert.True(t, bookings[free.ID.String()].RefundEligible)
+		assert.Zero(t, bookings[free.ID.String()].RefundAmount)
+	})
+
+	t.Run("cancelled bookings are listed but not eligible", func(t *testing.T) {
+		_, err := bookingService.CancelAllForUser(ctx, user)
+		require.NoError(t, err)
+
+		bookings := byID(list(t, ""))
+		require.Contains(t, bookings, monthAway.ID.String())
+		assert.Equal(t, string(domain.BookingStatusCancelled), bookings[monthAway.ID.String()].Status)
+		assert.False(t, bookings[monthAway.ID.String()].RefundEligible)
+		assert.Zero(t, bookings[monthAway.ID.String()].RefundAmount)
+	})
+
+	t.Run("pages newest first", func(t *testing.T) {
+		page := list(t, "?limit=2&offset=0")
+		assert.Equal(t, 4, page.TotalCount)
+		require.Len(t, page.Data, 2)
+		assert.False(t, page.Data[0].BookedAt.Before(page.Data[1].BookedAt))
+	})
+
+	t.Run("rejects an invalid user id", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/not-a-uuid/bookings", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
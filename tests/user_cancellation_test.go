@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancelAllForUser_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	date := time.Now().Add(30 * 24 * time.Hour)
+	concert, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name: "Concert", Date: date, Location: "Arena", Tickets: 20, PriceCents: 5000, Currency: "EUR",
+		RefundTiers: []domain.RefundTier{{MinNotice: 14 * 24 * time.Hour, RefundPercent: 80}},
+	})
+	require.NoError(t, err)
+	play, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name: "Play", Date: date, Location: "Theater", Tickets: 10,
+		MinViable: 5, ViabilityDeadline: date.Add(-24 * time.Hour),
+	})
+	require.NoError(t, err)
+	recital, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+		Name: "Recital", Date: date, Location: "Hall", Tickets: 3, Seats: []string{"A1", "A2", "A3"},
+	})
+	require.NoError(t, err)
+
+	user, otherUser := uuid.New(), uuid.New()
+	var userBookings []*domain.Booking
+	book := func(req app.CreateBookingRequest) *domain.Booking {
+		booking, err := bookingService.CreateBooking(ctx, req)
+		require.NoError(t, err)
+		return booking
+	}
+	userBookings = append(userBookings,
+		book(app.CreateBookingRequest{EventID: concert.ID, UserID: user, TicketsBooked: 2}),
+		book(app.CreateBookingRequest{EventID: concert.ID, UserID: user, TicketsBooked: 3}),
+		book(app.CreateBookingRequest{EventID: play.ID, UserID: user, TicketsBooked: 2, Conditional: true}),
+		book(app.CreateBookingRequest{EventID: recital.ID, UserID: user, TicketsBooked: 2, Seats: []string{"A1", "A2"}}),
+	)
+	otherBooking := book(app.CreateBookingRequest{EventID: concert.ID, UserID: otherUser, TicketsBooked: 4})
+
+	available := func(eventID uuid.UUID) int {
+		availability, err := ticketAvailabilityRepo.FindByEventID(ctx, eventID)
+		require.NoError(t, err)
+		return availability.AvailableTickets
+	}
+	require.Equal(t, 11, available(concert.ID))
+	require.Equal(t, 8, available(play.ID))
+	require.Equal(t, 1, available(recital.ID))
+
+	t.Run("cancels the user's bookings across events", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/users/"+user.String()+"/cancel-bookings", nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var got transport.UserCancellationResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		assert.Equal(t, user.String(), got.UserID)
+		assert.Equal(t, 4, got.Cancelled)
+		assert.Equal(t, 9, got.TicketsReleased)
+		assert.Equal(t, 3, got.Events)
+
+		// The concert's own policy refunds 80% a month ahead; the other events are free
+		refunds := make(map[string]transport.BookingRefundResponse, len(got.Refunds))
+		for _, refund := range got.Refunds {
+			refunds[refund.BookingID] = refund
+		}
+		require.Len(t, refunds, 4)
+		assert.Equal(t, transport.BookingRefundResponse{BookingID: userBookings[0].ID.String(), RefundCents: 8000, Currency: "EUR"}, refunds[userBookings[0].ID.String()])
+		assert.Equal(t, transport.BookingRefundResponse{BookingID: userBookings[1].ID.String(), RefundCents: 12000, Currency: "EUR"}, refunds[userBookings[1].ID.String()])
+		assert.Zero(t, refunds[userBookings[2].ID.String()].RefundCents)
+		assert.Zero(t, refunds[userBookings[3].ID.String()].RefundCents)
+
+		for _, booking := range userBookings {
+			stored, err := bookingService.GetBooking(ctx, booking.ID)
+			require.NoError(t, err)
+			assert.Equal(t, domain.BookingStatusCancelled, stored.Status)
+			assert.Equal(t, refunds[booking.ID.String()].RefundCents, stored.RefundCents, "the refund is stored with the booking")
+		}
+		stored, err := bookingService.GetBooking(ctx, otherBooking.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.BookingStatusConfirmed, stored.Status, "other users keep their bookings")
+
+		assert.Equal(t, 16, available(concert.ID))
+		assert.Equal(t, 10, available(play.ID))
+		assert.Equal(t, 3, available(recital.ID))
+
+		seats, err := eventService.GetSeatMap(ctx, recital.ID)
+		require.NoError(t, err)
+		for _, seat := range seats {
+			assert.Equal(t, domain.SeatStatusFree, seat.Status, seat.Label)
+		}
+	})
+
+	t.Run("repeating the cancellation is a no-op", func(t *testing.T) {
+		summary, err := bookingService.CancelAllForUser(ctx, user)
+		require.NoError(t, err)
+		assert.Equal(t, app.UserCancellation{}, *summary)
+		assert.Equal(t, 16, available(concert.ID))
+	})
+
+	t.Run("rejects an invalid user id", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/users/not-a-uuid/cancel-bookings", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jorzel/booking-service/internal/app"
+	"github.com/jorzel/booking-service/internal/domain"
+	"github.com/jorzel/booking-service/internal/infrastructure"
+	"github.com/jorzel/booking-service/internal/transport"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserEvents_Integration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	dbClient := infrastructure.NewDBClientAdapter(db)
+	eventRepo := infrastructure.NewPostgresEventRepository(dbClient)
+	bookingRepo := infrastructure.NewPostgresBookingRepository(dbClient)
+	ticketAvailabilityRepo := infrastructure.NewPostgresTicketAvailabilityRepository(dbClient)
+	seatRepo := infrastructure.NewPostgresSeatRepository(dbClient)
+	idempotencyRepo := infrastructure.NewPostgresIdempotencyKeyRepository(dbClient)
+	discountCodeRepo := infrastructure.NewPostgresDiscountCodeRepository(dbClient)
+	eventService := app.NewEventService(eventRepo, ticketAvailabilityRepo, seatRepo, dbClient, logger)
+	bookingService := app.NewBookingService(bookingRepo, eventRepo, ticketAvailabilityRepo, seatRepo, idempotencyRepo, discountCodeRepo, dbClient, logger)
+	holdService := app.NewHoldService(infrastructure.NewPostgresHoldRepository(dbClient), eventRepo, ticketAvailabilityRepo, bookingRepo, dbClient, logger, app.DefaultHoldTTL)
+	router := transport.NewRouter(eventService, bookingService, holdService, dbClient, infrastructure.NewWorkerRegistry(), logger)
+
+	ctx := context.Background()
+	createEvent := func(name string, in time.Duration) *domain.Event {
+		event, err := eventService.CreateEvent(ctx, app.CreateEventRequest{
+			Name:     name,
+			Date:     time.Now().Add(in),
+			Location: "Hall",
+			Tickets:  50,
+		})
+		require.NoError(t, err)
+		return event
+	}
+	book := func(eventID, userID uuid.UUID) *domain.Booking {
+		booking, err := bookingService.CreateBooking(ctx, app.CreateBookingRequest{EventID: eventID, UserID: userID, TicketsBooked: 2})
+		require.NoError(t, err)
+		return booking
+	}
+
+	user := uuid.New()
+	later := createEvent("Later Gig", 30*24*time.Hour)
+	sooner := createEvent("Sooner Gig", 7*24*time.Hour)
+	cancelled := createEvent("Cancelled Gig", 14*24*time.Hour)
+	notBooked := createEvent("Someone Else's Gig", 10*24*time.Hour)
+
+	book(later.ID, user)
+	book(later.ID, user)
+	book(sooner.ID, user)
+	book(notBooked.ID, uuid.New())
+
+	// The user keeps a booking for sooner but only has a cancelled one for cancelled
+	dropped := book(cancelled.ID, user)
+	require.NoError(t, dropped.Cancel(0))
+	require.NoError(t, bookingRepo.UpdateStatusWithExecutor(ctx, dbClient, dropped))
+
+	t.Run("lists each booked event once by date", func(t *testing.T) {
+		events, total, err := eventService.ListEventsForUser(ctx, user, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		require.Len(t, events, 2)
+		assert.Equal(t, sooner.ID, events[0].ID)
+		assert.Equal(t, later.ID, events[1].ID, "two bookings for one event list it once")
+	})
+
+	t.Run("pages through the events", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/"+user.String()+"/events?limit=1&offset=1", nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var response transport.PagedResponse[transport.EventResponse]
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, 2, response.TotalCount)
+		require.Len(t, response.Data, 1)
+		assert.Equal(t, later.ID.String(), response.Data[0].ID)
+	})
+
+	t.Run("a user without bookings has no events", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/"+uuid.NewString()+"/events", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"data": [], "total_count": 0, "limit": 20, "offset": 0}`, rec.Body.String())
+	})
+
+	t.Run("sums the user's tickets per event", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/"+user.String()+"/ticket-summary", nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var summary transport.UserTicketSummaryResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &summary))
+		assert.Equal(t, 6, summary.Total, "the cancelled booking is excluded")
+		assert.Equal(t, []transport.UserEventTicketsResponse{
+			{EventID: sooner.ID.String(), EventName: "Sooner Gig", Tickets: 2},
+			{EventID: later.ID.String(), EventName: "Later Gig", Tickets: 4},
+		}, summary.PerEvent)
+	})
+
+	t.Run("a user without bookings holds no tickets", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/"+uuid.NewString()+"/ticket-summary", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"total": 0, "per_event": []}`, rec.Body.String())
+	})
+
+	t.Run("rejects an invalid user ID", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/not-a-uuid/ticket-summary", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}